@@ -1,9 +1,9 @@
 package regal.capabilities
 
-import data.regal.config
-
 import rego.v1
 
+import data.regal.config
+
 default provided := {}
 
 # METADATA
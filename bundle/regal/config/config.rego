@@ -19,8 +19,13 @@ default for_rule(_, _) := {"level": "error"}
 # description: |
 #   Returns the configuration applied (i.e. the provided configuration
 #   merged with any user configuration and possibly command line overrides)
-#   to the rule matching the category and title.
-for_rule(category, title) := _with_level(category, title, "ignore") if {
+#   to the rule matching the category and title. When linting, the
+#   configuration of each rule is resolved once, before any file is
+#   evaluated, and provided in data.internal.rules_config.
+for_rule(category, title) := c if {
+	# regal ignore:external-reference
+	c := data.internal.rules_config[category][title]
+} else := _with_level(category, title, "ignore") if {
 	force_disabled(category, title)
 } else := _with_level(category, title, "error") if {
 	force_enabled(category, title)
@@ -168,7 +168,14 @@ test_all_rules_are_in_provided_configuration if {
 }
 
 test_all_configured_rules_exist if {
-	go_rules := {"opa-fmt"}
+	go_rules := {
+		"field-not-in-schema",
+		"indentation",
+		"missing-final-newline",
+		"opa-fmt",
+		"strict-mode",
+		"trailing-whitespace",
+	}
 
 	missing_rules := {title |
 		some category, title
@@ -13,14 +13,27 @@ lint_aggregate.violations := aggregate_report
 
 lint.violations := report
 
+lint.suppressed := suppressed
+
 rules_to_run[category][title] if {
 	some category, title
 	config.merged_config.rules[category][title]
 
 	config.for_rule(category, title).level != "ignore"
 	not config.excluded_file(category, title, input.regal.file.name)
+	selected(category, title)
 }
 
+# When input.regal.rules is provided, only the rules listed there (as
+# "category/title") are run. This is used when linting files identical
+# to ones already linted, where only rules depending on the name of the
+# file need to be evaluated.
+# regal ignore:external-reference
+selected(_, _) if not input.regal.rules
+
+# regal ignore:external-reference
+selected(category, title) if concat("/", [category, title]) in input.regal.rules
+
 notices contains notice if {
 	some category, title
 	some notice in grouped_notices[category][title]
@@ -76,6 +89,7 @@ report contains violation if {
 
 	config.for_rule(category, title).level != "ignore"
 	not config.excluded_file(category, title, input.regal.file.name)
+	selected(category, title)
 
 	not ignored(violation, ignore_directives)
 }
@@ -96,6 +110,7 @@ aggregate[category_title] contains entry if {
 
 	config.for_rule(category, title).level != "ignore"
 	not config.excluded_file(category, title, input.regal.file.name)
+	selected(category, title)
 
 	some entry in data.custom.regal.rules[category][title].aggregate
 
@@ -144,6 +159,62 @@ aggregate_report contains violation if {
 	not ignored(violation, ignore_directives)
 }
 
+# METADATA
+# description: |
+#   Violations suppressed by ignore directives, or by the rule being configured to
+#   ignore the file, reported only when requested so that suppressions may be audited
+suppressed contains object.union(violation, {"suppression": "ignore-directive"}) if {
+	data.internal.show_suppressed
+
+	some category, title
+	rules_to_run[category][title]
+
+	count(object.get(grouped_notices, [category, title], [])) == 0
+
+	some violation in data.regal.rules[category][title].report
+
+	ignored(violation, ignore_directives)
+}
+
+suppressed contains object.union(violation, {"suppression": "ignore-directive"}) if {
+	data.internal.show_suppressed
+
+	some category, title
+
+	violation := data.custom.regal.rules[category][title].report[_]
+
+	config.for_rule(category, title).level != "ignore"
+	not config.excluded_file(category, title, input.regal.file.name)
+	selected(category, title)
+
+	ignored(violation, ignore_directives)
+}
+
+suppressed contains object.union(violation, {"suppression": "config"}) if {
+	data.internal.show_suppressed
+
+	some category, title
+	config.merged_config.rules[category][title]
+
+	config.for_rule(category, title).level != "ignore"
+	config.excluded_file(category, title, input.regal.file.name)
+	selected(category, title)
+
+	some violation in data.regal.rules[category][title].report
+}
+
+suppressed contains object.union(violation, {"suppression": "config"}) if {
+	data.internal.show_suppressed
+
+	some category, title
+
+	violation := data.custom.regal.rules[category][title].report[_]
+
+	config.for_rule(category, title).level != "ignore"
+	config.excluded_file(category, title, input.regal.file.name)
+	selected(category, title)
+}
+
 ignored(violation, directives) if {
 	ignored_rules := directives[violation.location.row]
 	violation.title in ignored_rules
@@ -145,6 +145,44 @@ test_main_exclude_files_rule_config if {
 	count(report) == 0
 }
 
+test_main_suppressed_ignore_directive if {
+	policy := `package p
+
+	# regal ignore:prefer-snake-case
+	camelCase := "yes"
+	`
+	suppressed := main.suppressed with input as regal.parse_module("p.rego", policy)
+		with config.merged_config as {"rules": {"style": {"prefer-snake-case": {"level": "error"}}}}
+		with data.internal.show_suppressed as true
+
+	{[v.title, v.suppression] | some v in suppressed} == {["prefer-snake-case", "ignore-directive"]}
+}
+
+test_main_suppressed_rule_config if {
+	policy := `package p
+
+	camelCase := "yes"
+	`
+	cfg := {"rules": {"style": {"prefer-snake-case": {"level": "error", "ignore": {"files": ["p.rego"]}}}}}
+	suppressed := main.suppressed with input as regal.parse_module("p.rego", policy)
+		with config.merged_config as cfg
+		with data.internal.show_suppressed as true
+
+	{[v.title, v.suppression] | some v in suppressed} == {["prefer-snake-case", "config"]}
+}
+
+test_main_suppressed_not_requested if {
+	policy := `package p
+
+	# regal ignore:prefer-snake-case
+	camelCase := "yes"
+	`
+	suppressed := main.suppressed with input as regal.parse_module("p.rego", policy)
+		with config.merged_config as {"rules": {"style": {"prefer-snake-case": {"level": "error"}}}}
+
+	suppressed == set()
+}
+
 test_main_force_exclude_file_eval_param if {
 	policy := `package p
 
@@ -9,14 +9,9 @@ import data.regal.config
 import data.regal.result
 
 report contains violation if {
-	deprecated_builtins := {
-		"any", "all", "re_match", "net.cidr_overlap", "set_diff", "cast_array",
-		"cast_set", "cast_string", "cast_boolean", "cast_null", "cast_object",
-	}
-
-	# if none of the deprecated built-ins are in the
-	# capabilities for the target, bail out early
-	any_deprecated_builtin(object.keys(config.capabilities.builtins), deprecated_builtins)
+	# if none of the built-ins in the capabilities
+	# for the target are deprecated, bail out early
+	count(deprecated_builtins) > 0
 
 	some ref in ast.all_refs
 
@@ -33,7 +28,7 @@ report contains violation if {
 	violation := result.fail(rego.metadata.chain(), result.location(ref))
 }
 
-any_deprecated_builtin(caps_builtins, deprecated_builtins) if {
-	some builtin in caps_builtins
-	builtin in deprecated_builtins
+deprecated_builtins contains name if {
+	some name, builtin in config.capabilities.builtins
+	builtin.deprecated
 }
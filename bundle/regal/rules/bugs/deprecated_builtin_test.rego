@@ -14,7 +14,7 @@ test_fail_call_to_deprecated_builtin_function if {
 	}
 	`)
 
-	r := rule.report with input as module with config.capabilities as {"builtins": {"any": {}}}
+	r := rule.report with input as module with config.capabilities as {"builtins": {"any": {"deprecated": true}}}
 	r == {{
 		"category": "bugs",
 		"description": "Avoid using deprecated built-in functions",
@@ -38,3 +38,14 @@ test_success_deprecated_builtin_not_in_capabilities if {
 	r := rule.report with input as module with config.capabilities as {"builtins": {"http.send": {}}}
 	r == set()
 }
+
+test_success_builtin_not_deprecated_in_capabilities if {
+	module := ast.with_rego_v1(`
+	allow if {
+		any([true, false])
+	}
+	`)
+
+	r := rule.report with input as module with config.capabilities as {"builtins": {"any": {}}}
+	r == set()
+}
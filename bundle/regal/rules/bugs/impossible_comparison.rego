@@ -0,0 +1,65 @@
+# METADATA
+# description: Impossible comparison of values of different types
+package regal.rules.bugs["impossible-comparison"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.result
+
+report contains violation if {
+	some rule in input.rules
+
+	walk(rule, [_, value])
+
+	terms := _comparison(value)
+
+	count(terms) == 3
+	terms[0].type == "ref"
+	terms[0].value[0].value in {"eq", "equal"}
+
+	lhs_type := _type(terms[1], rule, config.capabilities.builtins)
+	rhs_type := _type(terms[2], rule, config.capabilities.builtins)
+
+	lhs_type != rhs_type
+
+	violation := result.fail(rego.metadata.chain(), result.location(value))
+}
+
+_comparison(value) := value.terms if is_array(value.terms)
+
+_comparison(value) := value.value if value.type == "call"
+
+_type(term, _, builtins) := _term_type(term, builtins) if term.type != "var"
+
+# local variables assigned a value of known type, like `n := count(users)`
+_type(term, rule, builtins) := _term_type(value, builtins) if {
+	term.type == "var"
+
+	some expr in rule.body
+
+	expr.terms[0].type == "ref"
+	expr.terms[0].value[0].value == "assign"
+	expr.terms[1].type == "var"
+	expr.terms[1].value == term.value
+
+	value := expr.terms[2]
+}
+
+_term_type(term, _) := term.type if term.type in {"array", "boolean", "null", "number", "object", "set", "string"}
+
+_term_type(term, _) := {
+	"arraycomprehension": "array",
+	"objectcomprehension": "object",
+	"setcomprehension": "set",
+}[term.type]
+
+# built-in functions declaring the type of their result, like `count` returning a number,
+# where results of any type, or of a choice of types, like `any<number, string>`, are unknown
+_term_type(term, builtins) := type if {
+	term.type == "call"
+
+	type := regex.find_n(`^[a-z]+`, builtins[ast.ref_to_string(term.value[0].value)].decl.result, 1)[0]
+	type != "any"
+}
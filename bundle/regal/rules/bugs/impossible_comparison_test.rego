@@ -0,0 +1,99 @@
+package regal.rules.bugs["impossible-comparison_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.capabilities
+import data.regal.config
+
+import data.regal.rules.bugs["impossible-comparison"] as rule
+
+test_fail_count_compared_to_string if {
+	r := rule.report with input as ast.with_rego_v1(`allow if count(input.users) == "0"`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == with_location({"col": 10, "file": "policy.rego", "row": 5, "text": "allow if count(input.users) == \"0\""})
+}
+
+test_fail_boolean_result_compared_to_string if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		startswith(input.path, "/admin") = "true"
+	}`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == with_location({
+		"col": 3,
+		"file": "policy.rego",
+		"row": 6,
+		"text": "\t\tstartswith(input.path, \"/admin\") = \"true\"",
+	})
+}
+
+test_fail_local_variable_compared_to_number if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		name := lower(input.name)
+		name == 1
+	}`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == with_location({"col": 3, "file": "policy.rego", "row": 7, "text": "\t\tname == 1"})
+}
+
+test_fail_comprehension_compared_to_set if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		[x | some x in input.roles] == {"admin"}
+	}`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == with_location({
+		"col": 3,
+		"file": "policy.rego",
+		"row": 6,
+		"text": "\t\t[x | some x in input.roles] == {\"admin\"}",
+	})
+}
+
+test_fail_comparison_in_assignment if {
+	r := rule.report with input as ast.with_rego_v1(`empty := count(input.users) == null`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == with_location({"col": 10, "file": "policy.rego", "row": 5, "text": "empty := count(input.users) == null"})
+}
+
+test_success_same_types if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		count(input.users) == 0
+		lower(input.name) == "admin"
+		split(input.path, "/") == ["", "admin"]
+		n := count(input.roles)
+		n == 1
+	}`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == set()
+}
+
+test_success_unknown_types if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		input.count == "0"
+		object.get(input, "count", 0) == "0"
+		some x in input.xs
+		x == 1
+		data.policy.count == "0"
+	}`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "bugs",
+	"description": "Impossible comparison of values of different types",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/impossible-comparison", "bugs"),
+	}],
+	"title": "impossible-comparison",
+}}
@@ -0,0 +1,49 @@
+# METADATA
+# description: Unreachable or inconsistent else branch
+package regal.rules.bugs["inconsistent-else"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.result
+
+# a branch without a condition (or one only saying `true`) always matches,
+# so any `else` following it can never be reached
+report contains violation if {
+	some rule in input.rules
+
+	rule["else"]
+
+	walk(rule, [_, branch])
+
+	branch["else"]
+
+	count(branch.body) == 1
+	branch.body[0].terms.type == "boolean"
+	branch.body[0].terms.value == true
+
+	violation := result.fail(rego.metadata.chain(), result.location(branch["else"].head))
+}
+
+# `else if` implicitly assigns `true`, which is likely a mistake when
+# other branches of the chain assign values other than booleans
+report contains violation if {
+	some rule in input.rules
+
+	rule["else"]
+
+	_non_boolean_value(rule)
+
+	walk(rule, [_, branch])
+
+	ast.implicit_boolean_assignment(branch["else"])
+
+	violation := result.fail(rego.metadata.chain(), result.location(branch["else"].head))
+}
+
+# values of unknown type, like variables and references, may well be booleans
+_non_boolean_value(rule) if {
+	walk(rule, [_, branch])
+
+	not branch.head.value.type in {"boolean", "call", "ref", "var"}
+}
@@ -0,0 +1,103 @@
+package regal.rules.bugs["inconsistent-else_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+
+import data.regal.rules.bugs["inconsistent-else"] as rule
+
+test_fail_else_after_unconditional_else if {
+	r := rule.report with input as ast.with_rego_v1(`
+	x := 1 if {
+		input.a
+	} else := 2 if true else := 3`)
+
+	r == with_location({"col": 22, "file": "policy.rego", "row": 8, "text": "\t} else := 2 if true else := 3"})
+}
+
+test_fail_else_after_unconditional_rule if {
+	r := rule.report with input as ast.with_rego_v1(`
+	x := 1 if true
+	else := 2 if input.a`)
+
+	r == with_location({"col": 2, "file": "policy.rego", "row": 7, "text": "\telse := 2 if input.a"})
+}
+
+test_fail_implicit_true_else_after_assignment if {
+	r := rule.report with input as ast.with_rego_v1(`
+	role := "admin" if {
+		input.user.admin
+	} else if {
+		input.user.member
+	}`)
+
+	r == with_location({"col": 4, "file": "policy.rego", "row": 8, "text": "\t} else if {"})
+}
+
+test_fail_implicit_true_else_after_else_assignment if {
+	r := rule.report with input as ast.with_rego_v1(`
+	limit := input.limit if {
+		input.limit
+	} else := 10 if {
+		input.user.member
+	} else if {
+		input.user.admin
+	}`)
+
+	r == with_location({"col": 4, "file": "policy.rego", "row": 10, "text": "\t} else if {"})
+}
+
+test_success_implicit_true_else_with_boolean_values if {
+	r := rule.report with input as ast.with_rego_v1(`
+	allow if {
+		input.user.admin
+	} else := false if {
+		input.user.banned
+	} else if {
+		input.user.member
+	}`)
+
+	r == set()
+}
+
+test_success_conditionless_else_last if {
+	r := rule.report with input as ast.with_rego_v1(`
+	x := 1 if {
+		input.a
+	} else := 2 if {
+		input.b
+	} else := 3`)
+
+	r == set()
+}
+
+test_success_implicit_true_with_explicit_false_else if {
+	r := rule.report with input as ast.with_rego_v1(`
+	allow if {
+		input.user.admin
+	} else := false`)
+
+	r == set()
+}
+
+test_success_function_else if {
+	r := rule.report with input as ast.with_rego_v1(`
+	f(x) := "a" if {
+		x == 1
+	} else := "b"`)
+
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "bugs",
+	"description": "Unreachable or inconsistent else branch",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/inconsistent-else", "bugs"),
+	}],
+	"title": "inconsistent-else",
+}}
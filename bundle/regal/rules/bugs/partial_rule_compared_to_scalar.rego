@@ -0,0 +1,83 @@
+# METADATA
+# description: Partial rule compared to scalar value
+package regal.rules.bugs["partial-rule-compared-to-scalar"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.result
+
+comparison_operators := {"eq", "equal", "neq", "lt", "gt", "lte", "gte"}
+
+# partial set rules, like `deny contains msg if`
+partial_rules contains ast.name(rule) if {
+	some rule in ast.rules
+
+	count(rule.head.ref) == 1
+
+	rule.head.key
+	not rule.head.value
+}
+
+# partial object rules, like `roles[name] := role if`
+partial_rules contains ast.name(rule) if {
+	some rule in ast.rules
+
+	count(rule.head.ref) == 2
+
+	rule.head.ref[1].type == "var"
+}
+
+report contains violation if {
+	# skip expensive walk if there are no partial rules to compare
+	count(partial_rules) > 0
+
+	some rule in input.rules
+
+	walk(rule, [_, value])
+
+	terms := _comparison(value)
+
+	count(terms) == 3
+	terms[0].type == "ref"
+	terms[0].value[0].value in comparison_operators
+
+	some operand, other in {terms[1]: terms[2], terms[2]: terms[1]}
+
+	other.type in ast.scalar_types
+
+	_rule_name(operand, concat(".", ["data", ast.package_name, ""])) in partial_rules
+
+	not _local_var(rule, operand)
+
+	violation := result.fail(rego.metadata.chain(), result.location(operand))
+}
+
+_comparison(value) := value.terms if is_array(value.terms)
+
+_comparison(value) := value.value if value.type == "call"
+
+_rule_name(term, _) := term.value if term.type == "var"
+
+# refs to rules of the package, like `data.policy.deny`, given a prefix like "data.policy."
+_rule_name(term, prefix) := name if {
+	term.type == "ref"
+
+	path := ast.ref_to_string(term.value)
+
+	startswith(path, prefix)
+
+	name := substring(path, count(prefix), -1)
+
+	not contains(name, ".")
+}
+
+_local_var(rule, term) if {
+	term.type == "var"
+	term.value in ast.function_arg_names(rule)
+}
+
+_local_var(rule, term) if {
+	term.type == "var"
+	term.value in {var.value | some var in ast.find_vars_in_local_scope(rule, term.location)}
+}
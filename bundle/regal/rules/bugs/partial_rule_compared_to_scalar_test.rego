@@ -0,0 +1,112 @@
+package regal.rules.bugs["partial-rule-compared-to-scalar_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+
+import data.regal.rules.bugs["partial-rule-compared-to-scalar"] as rule
+
+test_fail_partial_set_compared_to_boolean if {
+	r := rule.report with input as ast.with_rego_v1(`
+	deny contains msg if {
+		input.x
+		msg := "x"
+	}
+
+	allow if deny == false`)
+
+	r == {{
+		"category": "bugs",
+		"description": "Partial rule compared to scalar value",
+		"level": "error",
+		"location": {"col": 11, "file": "policy.rego", "row": 11, "text": "\tallow if deny == false"},
+		"related_resources": [{
+			"description": "documentation",
+			"ref": config.docs.resolve_url("$baseUrl/$category/partial-rule-compared-to-scalar", "bugs"),
+		}],
+		"title": "partial-rule-compared-to-scalar",
+	}}
+}
+
+test_fail_partial_object_compared_to_string if {
+	r := rule.report with input as ast.with_rego_v1(`
+	roles[name] := role if {
+		some name, role in input.roles
+	}
+
+	admin if {
+		"admin" == roles
+	}`)
+
+	count(r) == 1
+}
+
+test_fail_partial_set_referenced_by_path if {
+	r := rule.report with input as ast.policy(`
+	deny[msg] {
+		msg := "x"
+	}
+
+	allow {
+		data.policy.deny != true
+	}`)
+
+	count(r) == 1
+}
+
+test_fail_partial_set_compared_in_rule_head if {
+	r := rule.report with input as ast.with_rego_v1(`
+	deny contains "x" if input.x
+
+	denied := deny == true`)
+
+	count(r) == 1
+}
+
+test_success_complete_rule_compared_to_scalar if {
+	r := rule.report with input as ast.with_rego_v1(`
+	deny := "x" if input.x
+
+	allow if deny == "x"`)
+
+	r == set()
+}
+
+test_success_partial_set_compared_to_set if {
+	r := rule.report with input as ast.with_rego_v1(`
+	deny contains msg if {
+		msg := "x"
+	}
+
+	allow if deny == set()`)
+
+	r == set()
+}
+
+test_success_partial_set_referenced_by_key if {
+	r := rule.report with input as ast.with_rego_v1(`
+	roles[name] := role if {
+		some name, role in input.roles
+	}
+
+	admin if roles.admin == true`)
+
+	r == set()
+}
+
+test_success_local_var_shadowing_partial_rule if {
+	r := rule.report with input as ast.with_rego_v1(`
+	deny contains msg if {
+		msg := "x"
+	}
+
+	f(deny) if deny == true
+
+	allow if {
+		some deny in input.denied
+		deny == true
+	}`)
+
+	r == set()
+}
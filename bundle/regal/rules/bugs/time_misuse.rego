@@ -0,0 +1,81 @@
+# METADATA
+# description: Time handling pitfall
+package regal.rules.bugs["time-misuse"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.result
+
+# nanosecond timestamps compared to numbers far too small to be nanoseconds, like `time.now_ns() > 1700000000`
+report contains violation if {
+	some rule in input.rules
+
+	walk(rule, [_, value])
+
+	terms := _call(value)
+
+	count(terms) == 3
+	terms[0].type == "ref"
+	terms[0].value[0].value in {"eq", "equal", "neq", "gt", "gte", "lt", "lte"}
+
+	some [timestamp, number] in [[terms[1], terms[2]], [terms[2], terms[1]]]
+
+	_nanoseconds(timestamp, rule, _timestamp_functions)
+
+	number.type == "number"
+	number.value > 0
+	number.value < 1e15
+
+	violation := result.fail(rego.metadata.chain(), result.location(value))
+}
+
+# the current time included in a request cached by http.send, making each request, and cache entry, unique
+report contains violation if {
+	some rule in input.rules
+
+	walk(rule, [_, value])
+
+	terms := _call(value)
+
+	terms[0].type == "ref"
+	ast.ref_to_string(terms[0].value) == "http.send"
+	terms[1].type == "object"
+
+	some [key, enabled] in terms[1].value
+	key.value in {"cache", "force_cache"}
+	enabled.value == true
+
+	walk(terms[1], [_, term])
+
+	_nanoseconds(term, rule, {"time.now_ns"})
+
+	violation := result.fail(rego.metadata.chain(), result.location(value))
+}
+
+_timestamp_functions := {"time.add_date", "time.now_ns", "time.parse_ns", "time.parse_rfc3339_ns"}
+
+_call(value) := value.terms if is_array(value.terms)
+
+_call(value) := value.value if value.type == "call"
+
+_nanoseconds(term, _, functions) if _call_of(term, functions)
+
+# local variables assigned a timestamp, like `now := time.now_ns()`
+_nanoseconds(term, rule, functions) if {
+	term.type == "var"
+
+	some expr in rule.body
+
+	expr.terms[0].type == "ref"
+	expr.terms[0].value[0].value == "assign"
+	expr.terms[1].type == "var"
+	expr.terms[1].value == term.value
+
+	_call_of(expr.terms[2], functions)
+}
+
+_call_of(term, functions) if {
+	term.type == "call"
+	ast.ref_to_string(term.value[0].value) in functions
+}
@@ -0,0 +1,95 @@
+package regal.rules.bugs["time-misuse_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+
+import data.regal.rules.bugs["time-misuse"] as rule
+
+test_fail_now_compared_to_seconds if {
+	r := rule.report with input as ast.with_rego_v1(`expired if time.now_ns() > 1700000000`)
+
+	r == with_location({"col": 12, "file": "policy.rego", "row": 5, "text": "expired if time.now_ns() > 1700000000"})
+}
+
+test_fail_parsed_timestamp_compared_to_milliseconds if {
+	r := rule.report with input as ast.with_rego_v1(`expired if {
+		1700000000000 >= time.parse_rfc3339_ns(input.expires)
+	}`)
+
+	r == with_location({
+		"col": 3,
+		"file": "policy.rego",
+		"row": 6,
+		"text": "\t\t1700000000000 >= time.parse_rfc3339_ns(input.expires)",
+	})
+}
+
+test_fail_local_variable_compared_to_seconds if {
+	r := rule.report with input as ast.with_rego_v1(`expired if {
+		now := time.now_ns()
+		now > 1700000000
+	}`)
+
+	r == with_location({"col": 3, "file": "policy.rego", "row": 7, "text": "\t\tnow > 1700000000"})
+}
+
+test_fail_now_in_cached_request if {
+	r := rule.report with input as ast.with_rego_v1(`response := http.send({
+		"method": "GET",
+		"url": sprintf("https://example.com/users?at=%d", [time.now_ns()]),
+		"cache": true,
+	})`)
+
+	r == with_location({"col": 13, "file": "policy.rego", "row": 5, "text": "response := http.send({"})
+}
+
+test_fail_local_variable_in_force_cached_request if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		now := time.now_ns()
+		http.send({"url": "https://example.com", "headers": {"x-time": now}, "force_cache": true})
+	}`)
+
+	r == with_location({
+		"col": 3,
+		"file": "policy.rego",
+		"row": 7,
+		"text": "\t\thttp.send({\"url\": \"https://example.com\", \"headers\": {\"x-time\": now}, \"force_cache\": true})",
+	})
+}
+
+test_success_nanoseconds if {
+	r := rule.report with input as ast.with_rego_v1(`expired if {
+		time.now_ns() > 1700000000000000000
+		time.now_ns() > input.expires_ns
+		time.now_ns() - time.parse_rfc3339_ns(input.issued) > 3600
+		time.now_ns() > 0
+		count(input.users) > 1700000000
+	}`)
+
+	r == set()
+}
+
+test_success_now_in_uncached_request if {
+	r := rule.report with input as ast.with_rego_v1(`response := http.send({
+		"method": "GET",
+		"url": "https://example.com",
+		"headers": {"x-time": sprintf("%d", [time.now_ns()])},
+		"cache": false,
+	})`)
+
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "bugs",
+	"description": "Time handling pitfall",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/time-misuse", "bugs"),
+	}],
+	"title": "time-misuse",
+}}
@@ -0,0 +1,98 @@
+# METADATA
+# description: Unresolved `with` target
+package regal.rules.bugs["unresolved-with-target"]
+
+import rego.v1
+
+import data.regal.result
+
+aggregate contains entry if {
+	package_path := [part.value | some i, part in input["package"].path; i > 0]
+
+	rule_refs := {ref |
+		some rule in input.rules
+
+		ref := array.concat(package_path, [_to_string(i, part) | some i, part in rule.head.ref])
+	}
+
+	entry := result.aggregate(rego.metadata.chain(), {
+		"package": package_path,
+		"rule_refs": rule_refs,
+		"targets": _targets,
+	})
+}
+
+_targets contains target if {
+	some rule in input.rules
+	some expr in rule.body
+	some w in expr["with"]
+
+	w.target.value[0].type == "var"
+	w.target.value[0].value == "data"
+
+	# dynamic refs, like data.users[name], can't be resolved
+	every i, part in w.target.value {
+		_static_part(i, part)
+	}
+
+	path := [part.value |
+		some i, part in w.target.value
+		i > 0
+	]
+
+	target := object.union(result.location(w), {"path": path})
+}
+
+# METADATA
+# schemas:
+#   - input: schema.regal.aggregate
+aggregate_report contains violation if {
+	all_packages := {entry.aggregate_data["package"] | some entry in input.aggregate}
+
+	all_rule_refs := {ref |
+		some entry in input.aggregate
+		some ref in entry.aggregate_data.rule_refs
+	}
+
+	some entry in input.aggregate
+	some target in entry.aggregate_data.targets
+
+	# only targets inside of the packages linted are considered, as others
+	# likely refer to data documents, like those loaded from JSON files
+	some pkg in all_packages
+	count(target.path) > count(pkg)
+	array.slice(target.path, 0, count(pkg)) == pkg
+
+	not _resolved(target.path, all_rule_refs)
+
+	violation := result.fail(rego.metadata.chain(), result.location(target))
+}
+
+_static_part(0, _)
+
+_static_part(i, part) if {
+	i > 0
+	part.type == "string"
+}
+
+_to_string(0, part) := part.value
+
+_to_string(i, part) := part.value if {
+	i > 0
+	part.type == "string"
+}
+
+_to_string(i, part) := "**" if {
+	i > 0
+	part.type != "string"
+}
+
+# a target resolves if it's a rule, a part of its value, or
+# a path to several rules, like a package within the package
+_resolved(path, rule_refs) if {
+	some ref in rule_refs
+
+	every i in numbers.range(0, min([count(path), count(ref)]) - 1) {
+		ref[i] in {"**", path[i]}
+	}
+}
@@ -0,0 +1,116 @@
+package regal.rules.bugs["unresolved-with-target_test"]
+
+import rego.v1
+
+import data.regal.config
+
+import data.regal.rules.bugs["unresolved-with-target"] as rule
+
+test_fail_with_target_not_in_package if {
+	agg1 := rule.aggregate with input as regal.parse_module("policy.rego", `package policy
+
+	import rego.v1
+
+	allow if "admin" in user.roles
+
+	user := data.users[input.user_id]
+	`)
+	agg2 := rule.aggregate with input as regal.parse_module("policy_test.rego", `package policy_test
+
+	import rego.v1
+
+	test_allow if {
+		data.policy.allow with data.policy.usr as {"roles": ["admin"]}
+	}
+	`)
+
+	r := rule.aggregate_report with input as {"aggregate": (agg1 | agg2)}
+	r == with_location({
+		"col": 21,
+		"file": "policy_test.rego",
+		"row": 6,
+		"text": "\t\tdata.policy.allow with data.policy.usr as {\"roles\": [\"admin\"]}",
+	})
+}
+
+test_success_with_target_resolved if {
+	agg1 := rule.aggregate with input as regal.parse_module("policy.rego", `package policy
+
+	import rego.v1
+
+	allow if "admin" in user.roles
+
+	user := data.users[input.user_id]
+
+	roles[name] := role if some name, role in data.roles
+
+	deny.reasons.admin := "admin" if input.admin
+	`)
+	agg2 := rule.aggregate with input as regal.parse_module("policy_test.rego", `package policy_test
+
+	import rego.v1
+
+	test_allow if {
+		data.policy.allow with data.policy.user as {"roles": ["admin"]}
+		data.policy.allow with data.policy.user.roles as ["admin"]
+		data.policy.allow with data.policy.roles.admin as {}
+		data.policy.allow with data.policy.deny as {}
+	}
+	`)
+
+	r := rule.aggregate_report with input as {"aggregate": (agg1 | agg2)}
+	r == set()
+}
+
+test_success_with_target_outside_packages_linted if {
+	agg := rule.aggregate with input as regal.parse_module("policy_test.rego", `package policy_test
+
+	import rego.v1
+
+	test_allow if {
+		data.policy.allow with data.users as {"alice": {"roles": ["admin"]}}
+	}
+	`)
+
+	r := rule.aggregate_report with input as {"aggregate": agg}
+	r == set()
+}
+
+test_success_with_target_nested_package if {
+	agg1 := rule.aggregate with input as regal.parse_module("policy.rego", `package policy
+
+	import rego.v1
+
+	allow if data.policy.users.admin
+	`)
+	agg2 := rule.aggregate with input as regal.parse_module("users.rego", `package policy.users
+
+	import rego.v1
+
+	admin if "admin" in input.roles
+	`)
+	agg3 := rule.aggregate with input as regal.parse_module("policy_test.rego", `package policy_test
+
+	import rego.v1
+
+	test_allow if {
+		data.policy.allow with data.policy.users.admin as true
+		data.policy.allow with data.policy.users as {"admin": true}
+	}
+	`)
+
+	r := rule.aggregate_report with input as {"aggregate": agg1 | agg2 | agg3}
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "bugs",
+	"description": "Unresolved `with` target",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/unresolved-with-target", "bugs"),
+	}],
+	"title": "unresolved-with-target",
+}}
@@ -5,9 +5,14 @@ package regal.rules.performance["with-outside-test-context"]
 import rego.v1
 
 import data.regal.ast
+import data.regal.config
 import data.regal.result
 
+cfg := config.for_rule("performance", "with-outside-test-context")
+
 report contains violation if {
+	not _allowed_test_file
+
 	some rule in input.rules
 	some expr in rule.body
 
@@ -16,3 +21,14 @@ report contains violation if {
 
 	violation := result.fail(rego.metadata.chain(), result.location(expr["with"][0]))
 }
+
+# rules other than tests in test files, like mocks and fixtures
+# shared by tests, commonly use `with` too
+_allowed_test_file if {
+	cfg["allow-in-test-files"] == true
+	_test_file
+}
+
+_test_file if endswith(input.regal.file.name, "_test.rego")
+
+_test_file if endswith(ast.package_name, "_test")
@@ -49,3 +49,33 @@ test_success_with_used_in_todo_test if {
 	r := rule.report with input as module
 	r == set()
 }
+
+test_success_with_used_in_test_file if {
+	module := regal.parse_module("policy_test.rego", `package policy_test
+
+	import rego.v1
+
+	admin_allowed if {
+		data.policy.allow with input as {"user": {"roles": ["admin"]}}
+	}
+	`)
+
+	r := rule.report with input as module
+		with config.for_rule as {"level": "error", "allow-in-test-files": true}
+	r == set()
+}
+
+test_fail_with_used_in_test_file_not_allowed if {
+	module := regal.parse_module("policy_test.rego", `package policy_test
+
+	import rego.v1
+
+	admin_allowed if {
+		data.policy.allow with input as {"user": {"roles": ["admin"]}}
+	}
+	`)
+
+	r := rule.report with input as module
+		with config.for_rule as {"level": "error", "allow-in-test-files": false}
+	count(r) == 1
+}
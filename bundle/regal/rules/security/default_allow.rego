@@ -0,0 +1,23 @@
+# METADATA
+# description: Access allowed by default
+package regal.rules.security["default-allow"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.result
+
+cfg := config.for_rule("security", "default-allow")
+
+report contains violation if {
+	some rule in input.rules
+
+	rule["default"] == true
+	rule.head.value.type == "boolean"
+	rule.head.value.value == true
+
+	ast.name(rule) in cfg["allow-rules"]
+
+	violation := result.fail(rego.metadata.chain(), result.location(rule))
+}
@@ -0,0 +1,50 @@
+package regal.rules.security["default-allow_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+
+import data.regal.rules.security["default-allow"] as rule
+
+cfg := {"level": "error", "allow-rules": ["allow", "allowed"]}
+
+test_fail_default_allow_true if {
+	r := rule.report with input as ast.with_rego_v1(`default allow := true`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 1, "file": "policy.rego", "row": 5, "text": "default allow := true"})
+}
+
+test_fail_configured_rule_name if {
+	r := rule.report with input as ast.with_rego_v1(`default can_access := true`)
+		with config.for_rule as {"level": "error", "allow-rules": ["can_access"]}
+
+	r == with_location({"col": 1, "file": "policy.rego", "row": 5, "text": "default can_access := true"})
+}
+
+test_success_default_allow_false if {
+	r := rule.report with input as ast.with_rego_v1(`default allow := false`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+test_success_default_deny_true if {
+	r := rule.report with input as ast.with_rego_v1(`default deny := true`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "security",
+	"description": "Access allowed by default",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/default-allow", "security"),
+	}],
+	"title": "default-allow",
+}}
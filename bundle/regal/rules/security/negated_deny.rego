@@ -0,0 +1,37 @@
+# METADATA
+# description: Access allowed by negated deny
+package regal.rules.security["negated-deny"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.result
+
+cfg := config.for_rule("security", "negated-deny")
+
+report contains violation if {
+	some rule in input.rules
+
+	# tests commonly assert that access is not denied
+	not startswith(ast.name(rule), "test_")
+
+	walk(rule, [_, value])
+
+	value.negated == true
+
+	_name(value.terms) in cfg["deny-rules"]
+
+	violation := result.fail(rego.metadata.chain(), result.location(value))
+}
+
+# not deny
+_name(term) := term.value if term.type == "var"
+
+# not data.authz.deny, or not input.user.denied
+_name(term) := last.value if {
+	term.type == "ref"
+
+	last := regal.last(term.value)
+	last.type == "string"
+}
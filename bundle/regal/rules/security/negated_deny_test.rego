@@ -0,0 +1,63 @@
+package regal.rules.security["negated-deny_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+
+import data.regal.rules.security["negated-deny"] as rule
+
+cfg := {"level": "error", "deny-rules": ["deny", "denied"]}
+
+test_fail_allow_if_not_deny if {
+	r := rule.report with input as ast.with_rego_v1(`allow if not deny`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 10, "file": "policy.rego", "row": 5, "text": "allow if not deny"})
+}
+
+test_fail_not_denied_in_input if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		input.user.admin
+		not input.user.denied
+	}`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 3, "file": "policy.rego", "row": 7, "text": "\t\tnot input.user.denied"})
+}
+
+test_fail_not_deny_of_other_package if {
+	r := rule.report with input as ast.with_rego_v1(`allow if not data.authz.deny`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 10, "file": "policy.rego", "row": 5, "text": "allow if not data.authz.deny"})
+}
+
+test_success_deny_checked_explicitly if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		count(deny) == 0
+		not input.user.locked
+	}`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+test_success_not_deny_in_test if {
+	r := rule.report with input as ast.with_rego_v1(`test_deny if not deny with input as {}`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "security",
+	"description": "Access allowed by negated deny",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/negated-deny", "security"),
+	}],
+	"title": "negated-deny",
+}}
@@ -0,0 +1,38 @@
+# METADATA
+# description: Access allowed unconditionally
+package regal.rules.security["unconditional-allow"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.result
+
+cfg := config.for_rule("security", "unconditional-allow")
+
+report contains violation if {
+	some rule in input.rules
+
+	not rule["default"]
+	not rule.head.args
+
+	rule.head.value.type == "boolean"
+	rule.head.value.value == true
+
+	ast.name(rule) in cfg["allow-rules"]
+
+	_unconditional(rule)
+
+	violation := result.fail(rego.metadata.chain(), result.location(rule))
+}
+
+# allow := true, which has a generated body
+_unconditional(rule) if ast.generated_body(rule)
+
+# allow if true, or allow if { true }
+_unconditional(rule) if {
+	count(rule.body) == 1
+
+	rule.body[0].terms.type == "boolean"
+	rule.body[0].terms.value == true
+}
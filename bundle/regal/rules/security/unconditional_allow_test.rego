@@ -0,0 +1,66 @@
+package regal.rules.security["unconditional-allow_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+
+import data.regal.rules.security["unconditional-allow"] as rule
+
+cfg := {"level": "error", "allow-rules": ["allow", "allowed"]}
+
+test_fail_allow_assigned_true if {
+	r := rule.report with input as ast.with_rego_v1(`allow := true`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 1, "file": "policy.rego", "row": 5, "text": "allow := true"})
+}
+
+test_fail_allow_if_true if {
+	r := rule.report with input as ast.with_rego_v1(`allow if true`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 1, "file": "policy.rego", "row": 5, "text": "allow if true"})
+}
+
+test_fail_allow_with_body_true if {
+	r := rule.report with input as ast.with_rego_v1(`allowed if {
+		true
+	}`)
+		with config.for_rule as cfg
+
+	r == with_location({"col": 1, "file": "policy.rego", "row": 5, "text": "allowed if {"})
+}
+
+test_success_allow_with_condition if {
+	r := rule.report with input as ast.with_rego_v1(`allow if input.user.admin`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+test_success_default_allow if {
+	r := rule.report with input as ast.with_rego_v1(`default allow := true`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+test_success_other_rule_name if {
+	r := rule.report with input as ast.with_rego_v1(`enabled := true`)
+		with config.for_rule as cfg
+
+	r == set()
+}
+
+with_location(location) := {{
+	"category": "security",
+	"description": "Access allowed unconditionally",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/unconditional-allow", "security"),
+	}],
+	"title": "unconditional-allow",
+}}
@@ -0,0 +1,51 @@
+# METADATA
+# description: Commented-out code
+package regal.rules.style["commented-out-code"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.result
+
+# METADATA
+# description: |
+#   patterns matching comments which look like Rego rather than prose, like package and
+#   import declarations, assignments, rule heads opening a body, and iteration with some
+code_patterns := [
+	`^package\s+[\w.\[\]"-]+$`,
+	`^import\s+(data|input|future|rego)\b[\w.\[\]"-]*(\s+as\s+\w+)?$`,
+	`^(default\s+)?[a-z_][\w.]*(\[[^\]]*\])*(\([^)]*\))?\s*:=\s*(\S+|[\[{(].*[\]})])$`,
+	`^[a-z_][\w.]*(\[[^\]]*\])*(\([^)]*\))?(\s+contains\s+\S+)?(\s+if)?\s*\{$`,
+	`^some\s+\w+(\s*,\s*\w+)?\s+in\s+\w+[.\[][\w.\[\]"]*$`,
+]
+
+report contains violation if {
+	some block in ast.comments.blocks
+
+	not startswith(trim_space(block[0].Text), "METADATA")
+
+	code := [comment |
+		some i, comment in block
+		some pattern in code_patterns
+		regex.match(pattern, trim_space(trim_left(comment.Text, "#")))
+
+		not trailing(comment, input.regal.file.lines)
+		not example(block, i)
+	]
+
+	# report each block of comments only once, at its first line of code
+	count(code) > 0
+
+	violation := result.fail(rego.metadata.chain(), result.location(code[0]))
+}
+
+# a comment following code on the same line, like x := 1 # x := 2
+trailing(comment, lines) if trim_space(substring(lines[comment.Location.row - 1], 0, comment.Location.col - 1)) != ""
+
+# a comment introduced as an example by a previous line of the block, like "# for example:"
+example(block, i) if {
+	some j, comment in block
+
+	j < i
+	endswith(trim_space(comment.Text), ":")
+}
@@ -0,0 +1,68 @@
+package regal.rules.style["commented-out-code_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.rules.style["commented-out-code"] as rule
+
+test_fail_commented_out_rule if {
+	r := rule.report with input as ast.with_rego_v1(`# Rules
+
+# allow if {
+#     input.user.admin
+# }
+allow := false`)
+	r == with_location({"col": 1, "file": "policy.rego", "row": 7, "text": "# allow if {"})
+}
+
+test_fail_commented_out_assignment_and_import if {
+	r := rule.report with input as ast.with_rego_v1(`# the users, from data
+# users := data.users
+
+#import data.roles
+allow := false`)
+	r == {
+		violation({"col": 1, "file": "policy.rego", "row": 6, "text": "# users := data.users"}),
+		violation({"col": 1, "file": "policy.rego", "row": 8, "text": "#import data.roles"}),
+	}
+}
+
+test_fail_commented_out_iteration if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+	# some user in input.users
+	input.user.admin
+}`)
+	r == with_location({"col": 2, "file": "policy.rego", "row": 6, "text": "\t# some user in input.users"})
+}
+
+test_success_prose if {
+	r := rule.report with input as ast.with_rego_v1(`# allow if the user is an admin, or if some users in the
+# group are, as per the policy { see docs }
+allow if input.user.admin
+
+x := 1 # x := 2 would break things`)
+	r == set()
+}
+
+test_success_metadata if {
+	r := rule.report with input as ast.with_rego_v1(`# METADATA
+# description: |
+#   allow := true, if the user is an admin
+allow if input.user.admin`)
+	r == set()
+}
+
+with_location(location) := {violation(location)}
+
+violation(location) := {
+	"category": "style",
+	"description": "Commented-out code",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/commented-out-code", "style"),
+	}],
+	"title": "commented-out-code",
+}
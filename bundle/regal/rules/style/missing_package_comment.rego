@@ -0,0 +1,20 @@
+# METADATA
+# description: Package without comment
+package regal.rules.style["missing-package-comment"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.result
+
+report contains violation if {
+	not endswith(ast.package_name, "_test")
+
+	package_row := input["package"].location.row
+
+	every comment in ast.comments_decoded {
+		comment.Location.row != package_row - 1
+	}
+
+	violation := result.fail(rego.metadata.chain(), result.location(input["package"]))
+}
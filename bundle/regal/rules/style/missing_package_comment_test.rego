@@ -0,0 +1,45 @@
+package regal.rules.style["missing-package-comment_test"]
+
+import rego.v1
+
+import data.regal.config
+import data.regal.rules.style["missing-package-comment"] as rule
+
+test_fail_package_without_comment if {
+	r := rule.report with input as regal.parse_module("p.rego", `# comment not directly above package
+
+package policy
+`)
+	r == {{
+		"category": "style",
+		"description": "Package without comment",
+		"level": "error",
+		"location": {"col": 1, "file": "p.rego", "row": 3, "text": "package policy"},
+		"related_resources": [{
+			"description": "documentation",
+			"ref": config.docs.resolve_url("$baseUrl/$category/missing-package-comment", "style"),
+		}],
+		"title": "missing-package-comment",
+	}}
+}
+
+test_success_package_with_comment if {
+	r := rule.report with input as regal.parse_module("p.rego", `# Package policy decides
+# whether requests are allowed.
+package policy
+`)
+	r == set()
+}
+
+test_success_package_with_metadata if {
+	r := rule.report with input as regal.parse_module("p.rego", `# METADATA
+# description: Decides whether requests are allowed.
+package policy
+`)
+	r == set()
+}
+
+test_success_test_package_without_comment if {
+	r := rule.report with input as regal.parse_module("p_test.rego", "package policy_test")
+	r == set()
+}
@@ -0,0 +1,59 @@
+# METADATA
+# description: Prefer raw string over escaped string
+package regal.rules.style["prefer-raw-string"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.result
+
+import data.regal.rules.idiomatic["non-raw-regex-pattern"] as regex_pattern
+
+cfg := config.for_rule("style", "prefer-raw-string")
+
+default max_escapes := 2
+
+max_escapes := cfg["max-escapes"]
+
+report contains violation if {
+	some rule in input.rules
+
+	walk(rule, [_, value])
+
+	value.type == "string"
+
+	text := base64.decode(value.location.text)
+
+	startswith(text, `"`)
+
+	# only escapes of backslashes and quotes, which aren't needed in raw strings, may be converted without
+	# changing the meaning of the string, and raw strings can't contain backticks
+	escapes := regex.find_n(`\\.`, text, -1)
+
+	count(escapes) > max_escapes
+
+	every escape in escapes {
+		escape in {`\\`, `\"`, `\/`}
+	}
+
+	not contains(value.value, "`")
+
+	# patterns of regex functions are reported by the non-raw-regex-pattern rule
+	not value.location in regex_pattern_locations
+
+	violation := result.fail(rego.metadata.chain(), result.location(value))
+}
+
+regex_pattern_locations contains location if {
+	regex_pattern.any_regex_function_called
+
+	some value in ast.all_refs
+
+	value[0].value[0].type == "var"
+	value[0].value[0].value == "regex"
+
+	some pos in regex_pattern.re_pattern_functions[value[0].value[1].value]
+
+	location := value[pos].location
+}
@@ -0,0 +1,69 @@
+package regal.rules.style["prefer-raw-string_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.capabilities
+import data.regal.config
+
+import data.regal.rules.style["prefer-raw-string"] as rule
+
+test_fail_escaped_path if {
+	r := rule.report with input as ast.policy(`path := "C:\\Users\\admin\\policies"`)
+	r == {{
+		"category": "style",
+		"description": "Prefer raw string over escaped string",
+		"level": "error",
+		"location": {"col": 9, "file": "policy.rego", "row": 3, "text": `path := "C:\\Users\\admin\\policies"`},
+		"related_resources": [{
+			"description": "documentation",
+			"ref": config.docs.resolve_url("$baseUrl/$category/prefer-raw-string", "style"),
+		}],
+		"title": "prefer-raw-string",
+	}}
+}
+
+test_fail_escaped_quotes_in_rule_body if {
+	r := rule.report with input as ast.with_rego_v1(`allow if {
+		input.text == "\"a\" and \"b\""
+	}`)
+	count(r) == 1
+}
+
+test_success_few_escapes if {
+	r := rule.report with input as ast.policy(`path := "C:\\Users\\admin"`)
+	r == set()
+}
+
+test_success_raw_string if {
+	r := rule.report with input as ast.policy("path := `C:\\Users\\admin\\policies`")
+	r == set()
+}
+
+test_success_other_escapes if {
+	r := rule.report with input as ast.policy(`x := "a\\b\\c\\d\n"`)
+	r == set()
+}
+
+test_success_backtick if {
+	r := rule.report with input as ast.policy("x := \"a\\\\b\\\\c\\\\d`\"")
+	r == set()
+}
+
+test_success_regex_pattern_reported_by_other_rule if {
+	r := rule.report with input as ast.with_rego_v1(`allow if regex.match("\\d+\\.\\d+", input.version)`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+	r == set()
+}
+
+test_fail_regex_non_pattern_argument if {
+	r := rule.report with input as ast.with_rego_v1(`allow if regex.match(input.pattern, "\\d+\\.\\d+")`)
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+	count(r) == 1
+}
+
+test_success_max_escapes_configured if {
+	r := rule.report with input as ast.policy(`path := "C:\\Users\\admin\\policies"`)
+		with config.for_rule as {"level": "error", "max-escapes": 3}
+	r == set()
+}
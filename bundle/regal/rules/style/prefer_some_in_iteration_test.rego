@@ -234,6 +234,28 @@ test_success_allow_if_contains_check_equal if {
 	r == set()
 }
 
+test_fail_assignment_iteration_with_capabilities if {
+	policy := ast.with_rego_v1(`allow if {
+		var := input.foo[_]
+	}`)
+
+	r := rule.report with config.for_rule as allow_nesting(2)
+		with input as policy
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+	r == with_location({"col": 10, "file": "policy.rego", "row": 6, "text": "\t\tvar := input.foo[_]"})
+}
+
+test_fail_unification_iteration_with_capabilities if {
+	policy := ast.with_rego_v1(`allow if {
+		var = input.foo[_]
+	}`)
+
+	r := rule.report with config.for_rule as allow_nesting(2)
+		with input as policy
+		with data.internal.combined_config as {"capabilities": capabilities.provided}
+	r == with_location({"col": 9, "file": "policy.rego", "row": 6, "text": "\t\tvar = input.foo[_]"})
+}
+
 test_success_iteration_in_args if {
 	policy := ast.with_rego_v1(`no_violation if {
 		startswith(input.foo[_], "f")
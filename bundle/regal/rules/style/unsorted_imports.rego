@@ -0,0 +1,32 @@
+# METADATA
+# description: Imports not sorted
+package regal.rules.style["unsorted-imports"]
+
+import rego.v1
+
+import data.regal.result
+
+report contains violation if {
+	some i, imp in input.imports
+
+	i > 0
+	sort_key(imp) < sort_key(input.imports[i - 1])
+
+	violation := result.fail(rego.metadata.chain(), result.location(imp))
+}
+
+# METADATA
+# description: |
+#   the key imports are sorted by, i.e. by their group, then their path, then their alias,
+#   which within a group is the same order as imports are sorted by opa fmt
+sort_key(imp) := [
+	group(imp.path.value[0].value),
+	[term.value | some term in imp.path.value],
+	object.get(imp, "alias", ""),
+]
+
+group(name) := 0 if name in {"future", "rego"}
+
+group("data") := 1
+
+group("input") := 2
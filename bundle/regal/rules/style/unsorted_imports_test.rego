@@ -0,0 +1,59 @@
+package regal.rules.style["unsorted-imports_test"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.rules.style["unsorted-imports"] as rule
+
+test_fail_data_import_before_rego_v1 if {
+	r := rule.report with input as ast.policy(`import data.foo
+import rego.v1`)
+	r == with_location({"col": 1, "file": "policy.rego", "row": 4, "text": "import rego.v1"})
+}
+
+test_fail_input_import_before_data_import if {
+	r := rule.report with input as ast.policy(`import input.user
+
+import data.foo`)
+	r == with_location({"col": 1, "file": "policy.rego", "row": 5, "text": "import data.foo"})
+}
+
+test_fail_data_imports_not_sorted if {
+	r := rule.report with input as ast.policy(`import data.foo.bar
+import data.foo.bar as baz
+import data.foo.bar as bar
+import data.foo`)
+	r == {
+		violation({"col": 1, "file": "policy.rego", "row": 5, "text": "import data.foo.bar as bar"}),
+		violation({"col": 1, "file": "policy.rego", "row": 6, "text": "import data.foo"}),
+	}
+}
+
+test_success_imports_sorted_and_grouped if {
+	r := rule.report with input as ast.policy(`import future.keywords.if
+import future.keywords.in
+
+import data.foo
+import data.foo.bar
+import data.foo.bar as bar
+import data.foo.bar as baz
+import data.foo_bar
+
+import input.user`)
+	r == set()
+}
+
+with_location(location) := {violation(location)}
+
+violation(location) := {
+	"category": "style",
+	"description": "Imports not sorted",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/unsorted-imports", "style"),
+	}],
+	"title": "unsorted-imports",
+}
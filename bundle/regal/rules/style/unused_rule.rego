@@ -0,0 +1,199 @@
+# METADATA
+# description: Rule never referenced
+package regal.rules.style["unused-rule"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.config
+import data.regal.result
+
+cfg := config.for_rule("style", "unused-rule")
+
+package_path := [part.value | some i, part in input["package"].path; i > 0]
+
+aggregate contains result.aggregate(rego.metadata.chain(), {
+	"rules": rules,
+	"refs": refs,
+	"entrypoints": entrypoints,
+})
+
+# METADATA
+# description: |
+#   the rules of the module, by their path, with the location of their first definition,
+#   leaving out tests, and any rules of test packages or files
+rules[concat(".", path)] := {"path": path, "location": location.location} if {
+	not in_tests
+
+	some path in rule_paths
+	not startswith(path[count(package_path)], "test_")
+	not startswith(path[count(package_path)], "todo_test_")
+
+	location := result.location([rule.head |
+		some i, rule in input.rules
+		rule_paths[i] == path
+	])
+}
+
+in_tests if endswith(input.regal.file.name, "_test.rego")
+
+in_tests if endswith(regal.last(package_path), "_test")
+
+# METADATA
+# description: |
+#   the paths of the rules of the module, by their index, cut short at the
+#   first dynamic part of the ref of their head, like in users[name] := user
+rule_paths[i] := array.concat(package_path, static(rule.head.ref)) if some i, rule in input.rules
+
+# METADATA
+# description: |
+#   the refs and vars of the rules of the module, leaving out the refs of rule heads,
+#   and the vars heading refs, as those are resolved along with the rest of the ref
+terms contains value if {
+	some rule in input.rules
+
+	walk(rule, [location, value])
+	value.type in {"ref", "var"}
+
+	not rule_head_ref(location)
+	not ref_head(rule, location)
+}
+
+rule_head_ref(location) if {
+	location[0] == "head"
+	location[1] == "ref"
+}
+
+ref_head(rule, location) if {
+	regal.last(location) == 0
+	object.get(rule, array.slice(location, 0, count(location) - 2), {}).type == "ref"
+}
+
+# METADATA
+# description: |
+#   the paths referenced by the module, resolved to the packages they point to,
+#   and cut short at the first dynamic part, like in data.users[name]
+refs contains path if {
+	some value in terms
+
+	value.type == "ref"
+	value.value[0].value == "data"
+
+	path := static_parts(array.slice(value.value, 1, count(value.value)))
+}
+
+# a reference to an import, like users.admins following import data.users
+refs contains path if {
+	some value in terms
+
+	value.type == "ref"
+	name := value.value[0].value
+
+	path := array.concat(imported[name], static_parts(array.slice(value.value, 1, count(value.value))))
+}
+
+# a reference to a rule or function of the same package, like admins.alice or is_admin(user)
+refs contains path if {
+	some value in terms
+
+	value.type == "ref"
+	name := value.value[0].value
+	not name in {"data", "input"}
+	not imported[name]
+
+	path := array.concat(package_path, static(value.value))
+}
+
+# a rule of the same package, or an import, referenced by name, like allow if admin
+refs contains path if {
+	some value in terms
+
+	value.type == "var"
+
+	path := object.get(imported, value.value, array.concat(package_path, [value.value]))
+}
+
+# the name heading a ref, followed by its static parts
+static(ref) := array.concat([ref[0].value], static_parts(array.slice(ref, 1, count(ref))))
+
+# the values of the string terms of a ref, up to the first dynamic one
+static_parts(terms) := [term.value |
+	some i, term in terms
+	every t in array.slice(terms, 0, i + 1) {
+		t.type == "string"
+	}
+]
+
+imported[ast.imported_identifier(imp)] := path if {
+	some imp in input.imports
+
+	imp.path.value[0].value == "data"
+	path := [part.value | some i, part in imp.path.value; i > 0]
+}
+
+# METADATA
+# description: |
+#   the paths of rules annotated as entrypoints, or of the package, if annotated as one
+entrypoints contains package_path if {
+	some annotation in input.annotations
+
+	annotation.entrypoint == true
+	annotation.scope in {"package", "subpackages"}
+}
+
+entrypoints contains path if {
+	some annotation in input.annotations
+
+	annotation.entrypoint == true
+	annotation.scope in {"rule", "document"}
+
+	# the annotated rule is the first following the annotation
+	path := rule_paths[min({i |
+		some i, rule in input.rules
+		rule.head.location.row > annotation.location.row
+	})]
+}
+
+# METADATA
+# schemas:
+#   - input: schema.regal.aggregate
+aggregate_report contains violation if {
+	all_refs := {path |
+		some entry in input.aggregate
+		some path in entry.aggregate_data.refs
+	}
+
+	all_entrypoints := {path |
+		some entry in input.aggregate
+		some path in entry.aggregate_data.entrypoints
+	}
+
+	some entry in input.aggregate
+	some rule in entry.aggregate_data.rules
+
+	not excepted_package(entry.aggregate_source.package_path)
+	not used(rule.path, all_refs | all_entrypoints)
+
+	violation := result.fail(rego.metadata.chain(), {"location": rule.location})
+}
+
+# METADATA
+# description: |
+#   a rule is used if a path referenced leads to it, or into it, like data.users
+#   or data.users.admins for a rule data.users.admins, or data.users.admins.alice
+used(path, paths) if {
+	some other in paths
+
+	n := min([count(path), count(other)])
+	array.slice(path, 0, n) == array.slice(other, 0, n)
+}
+
+excepted_package(pkg_path) if {
+	some pattern in cfg["except-packages"]
+
+	glob.match(trim_data(pattern), ["."], concat(".", pkg_path))
+}
+
+trim_data(pattern) := substring(pattern, 5, -1) if startswith(pattern, "data.")
+
+trim_data(pattern) := pattern if not startswith(pattern, "data.")
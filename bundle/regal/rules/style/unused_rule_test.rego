@@ -0,0 +1,129 @@
+package regal.rules.style["unused-rule_test"]
+
+import rego.v1
+
+import data.regal.config
+
+import data.regal.rules.style["unused-rule"] as rule
+
+test_fail_rule_never_referenced if {
+	agg1 := rule.aggregate with input as regal.parse_module("p1.rego", `package policy
+
+import rego.v1
+
+import data.lib
+
+# METADATA
+# entrypoint: true
+allow if lib.is_valid(input.user)
+
+unused if input.user.name == "alice"
+`)
+	agg2 := rule.aggregate with input as regal.parse_module("p2.rego", `package lib
+
+import rego.v1
+
+is_valid(x) if x != ""
+
+is_empty(x) if x == ""
+`)
+
+	r := rule.aggregate_report with input as {"aggregate": (agg1 | agg2)}
+	r == {
+		with_location({"file": "p1.rego", "row": 11, "col": 1, "text": "unused if input.user.name == \"alice\""}),
+		with_location({"file": "p2.rego", "row": 7, "col": 1, "text": "is_empty(x) if x == \"\""}),
+	}
+}
+
+test_success_rules_referenced_across_packages if {
+	agg1 := rule.aggregate with input as regal.parse_module("p1.rego", `package policy
+
+import rego.v1
+
+import data.users.admins as a
+
+# METADATA
+# entrypoint: true
+allow if {
+	admin
+	data.roles.by_name[input.role].read
+}
+
+admin if input.user.name in a
+`)
+	agg2 := rule.aggregate with input as regal.parse_module("p2.rego", `package users
+
+import rego.v1
+
+admins contains "alice"
+`)
+	agg3 := rule.aggregate with input as regal.parse_module("p3.rego", `package roles
+
+by_name.admin.read := true
+`)
+
+	r := rule.aggregate_report with input as {"aggregate": union({agg1, agg2, agg3})}
+	r == set()
+}
+
+test_success_rules_referenced_by_tests_or_dynamically if {
+	agg1 := rule.aggregate with input as regal.parse_module("p1.rego", `package policy
+
+tested := true
+
+dynamic := true
+`)
+	agg2 := rule.aggregate with input as regal.parse_module("p1_test.rego", `package policy_test
+
+import rego.v1
+
+import data.policy
+
+test_tested if policy.tested
+
+test_all if {
+	some name
+	data.policy[name]
+}
+`)
+
+	r := rule.aggregate_report with input as {"aggregate": (agg1 | agg2)}
+	r == set()
+}
+
+test_success_entrypoint_package if {
+	agg1 := rule.aggregate with input as regal.parse_module("p1.rego", `# METADATA
+# entrypoint: true
+package policy
+
+allow := true
+`)
+	agg2 := rule.aggregate with input as regal.parse_module("p2.rego", "package other")
+
+	r := rule.aggregate_report with input as {"aggregate": (agg1 | agg2)}
+	r == set()
+}
+
+test_success_unused_rule_in_excepted_package if {
+	agg1 := rule.aggregate with input as regal.parse_module("p1.rego", `package policy.authz
+
+allow := true
+`)
+	agg2 := rule.aggregate with input as regal.parse_module("p2.rego", "package other")
+
+	r := rule.aggregate_report with input as {"aggregate": (agg1 | agg2)}
+		with config.for_rule as {"level": "error", "except-packages": ["data.policy.*"]}
+	r == set()
+}
+
+with_location(location) := {
+	"category": "style",
+	"description": "Rule never referenced",
+	"level": "error",
+	"location": location,
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/unused-rule", "style"),
+	}],
+	"title": "unused-rule",
+}
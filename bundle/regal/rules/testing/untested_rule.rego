@@ -0,0 +1,44 @@
+# METADATA
+# description: Rule not covered by tests
+package regal.rules.testing["untested-rule"]
+
+import rego.v1
+
+import data.regal.ast
+import data.regal.result
+
+# METADATA
+# description: |
+#   coverage of the file, as reported by `opa test --coverage`, and provided
+#   with `regal lint --coverage` — the rule does nothing without it
+coverage := data.internal.coverage[input.regal.file.name]
+
+report contains violation if {
+	coverage
+
+	not endswith(input.regal.file.name, "_test.rego")
+
+	some name in {ast.name(rule) | some rule in input.rules}
+
+	not startswith(name, "_")
+	not startswith(name, "test_")
+	not startswith(name, "todo_test_")
+
+	definitions := [rule | some rule in input.rules; ast.name(rule) == name]
+
+	every rule in definitions {
+		not covered(rule)
+	}
+
+	violation := result.fail(rego.metadata.chain(), result.location(definitions[0].head))
+}
+
+covered(rule) if {
+	start := rule.location.row
+	end := (start + count(split(base64.decode(rule.location.text), "\n"))) - 1
+
+	some lines in coverage.covered
+
+	lines.start.row <= end
+	lines.end.row >= start
+}
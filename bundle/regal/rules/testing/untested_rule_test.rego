@@ -0,0 +1,81 @@
+package regal.rules.testing["untested-rule_test"]
+
+import rego.v1
+
+import data.regal.config
+import data.regal.rules.testing["untested-rule"] as rule
+
+policy := `package policy
+
+import rego.v1
+
+allow if {
+	input.user == "admin"
+}
+
+allow if {
+	input.user == "root"
+}
+
+deny if {
+	input.user == "guest"
+}
+
+f(x) := x + 1
+
+_helper := true
+`
+
+test_fail_rules_without_coverage if {
+	module := regal.parse_module("policy.rego", policy)
+	r := rule.report with input as module
+		with data.internal.coverage as {"policy.rego": {"covered": [{"start": {"row": 5}, "end": {"row": 7}}]}}
+
+	r == {
+		expected_with_location({"col": 1, "file": "policy.rego", "row": 13, "text": "deny if {"}),
+		expected_with_location({"col": 1, "file": "policy.rego", "row": 17, "text": "f(x) := x + 1"}),
+	}
+}
+
+test_success_all_rules_covered if {
+	module := regal.parse_module("policy.rego", policy)
+	r := rule.report with input as module
+		with data.internal.coverage as {"policy.rego": {"covered": [{"start": {"row": 6}, "end": {"row": 17}}]}}
+
+	r == set()
+}
+
+test_success_no_coverage_provided if {
+	module := regal.parse_module("policy.rego", policy)
+	r := rule.report with input as module
+
+	r == set()
+}
+
+test_success_no_coverage_for_file if {
+	module := regal.parse_module("policy.rego", policy)
+	r := rule.report with input as module
+		with data.internal.coverage as {"other.rego": {"covered": [{"start": {"row": 1}, "end": {"row": 3}}]}}
+
+	r == set()
+}
+
+test_success_test_file_ignored if {
+	module := regal.parse_module("policy_test.rego", "package policy_test\n\nimport rego.v1\n\nhelper := true\n")
+	r := rule.report with input as module
+		with data.internal.coverage as {"policy_test.rego": {"covered": []}}
+
+	r == set()
+}
+
+expected_with_location(location) := {
+	"category": "testing",
+	"description": "Rule not covered by tests",
+	"related_resources": [{
+		"description": "documentation",
+		"ref": config.docs.resolve_url("$baseUrl/$category/untested-rule", "testing"),
+	}],
+	"title": "untested-rule",
+	"location": location,
+	"level": "error",
+}
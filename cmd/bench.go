@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/styrainc/regal/pkg/linter"
+)
+
+type benchCommandParams struct {
+	configFile string
+	format     string
+	outputFile string
+	compare    string
+	rules      repeatedStringFlag
+	count      int
+	warmup     int
+	duration   time.Duration
+	timeout    time.Duration
+}
+
+func (p *benchCommandParams) getConfigFile() string {
+	return p.configFile
+}
+
+func (p *benchCommandParams) getTimeout() time.Duration {
+	return p.timeout
+}
+
+// benchResult is the result of benchmarking the linter, as output by the bench command in JSON format, and read
+// back from a previous run when comparing results.
+type benchResult struct {
+	Paths       []string         `json:"paths"`
+	Runs        int              `json:"runs"`
+	Files       int              `json:"files"`
+	Violations  int              `json:"violations"`
+	Wall        benchWallTime    `json:"wall_time"`
+	Allocations benchAllocations `json:"allocations"`
+	Rules       []benchRuleTime  `json:"rules"`
+}
+
+// benchWallTime holds the wall time of the runs, in nanoseconds.
+type benchWallTime struct {
+	Min    int64 `json:"min_ns"`
+	Max    int64 `json:"max_ns"`
+	Mean   int64 `json:"mean_ns"`
+	Median int64 `json:"median_ns"`
+	Total  int64 `json:"total_ns"`
+}
+
+// benchAllocations holds the mean number of heap allocations, and bytes allocated, per run.
+type benchAllocations struct {
+	Bytes   uint64 `json:"bytes_per_run"`
+	Objects uint64 `json:"objects_per_run"`
+}
+
+// benchRuleTime holds the time spent evaluating the expressions of a rule, or of a shared library module, like
+// the regal.ast package, in a profiled run.
+type benchRuleTime struct {
+	Name    string `json:"name"`
+	Time    int64  `json:"time_ns"`
+	NumEval int    `json:"num_eval"`
+}
+
+func init() {
+	params := &benchCommandParams{}
+
+	benchCommand := &cobra.Command{
+		Use:   "bench <path> [path [...]]",
+		Short: "Benchmark linting of Rego source files",
+		Long: `Lint the provided paths repeatedly, reporting wall time, allocations and the time spent in each rule.
+
+Linting is done either a fixed number of times (--count), or for as long as a duration (--duration) allows. The JSON
+output of one run may later be provided to --compare, which reports the changes from that run to the current one.`,
+
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("at least one file or directory must be provided for benchmarking")
+			}
+
+			if params.count < 1 && params.duration <= 0 {
+				return errors.New("either --count or --duration must be greater than zero")
+			}
+
+			if params.format != formatPretty && params.format != formatJSON {
+				return fmt.Errorf("unknown format %s, expected pretty or json", params.format)
+			}
+
+			return nil
+		},
+
+		RunE: wrapProfiling(func(args []string) error {
+			return bench(args, params)
+		}),
+	}
+
+	benchCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file")
+	benchCommand.Flags().StringVarP(&params.format, "format", "f", formatPretty,
+		"set output format (pretty, json)")
+	benchCommand.Flags().StringVarP(&params.outputFile, "output-file", "o", "",
+		"set file to use for benchmark output, defaults to stdout")
+	benchCommand.Flags().StringVar(&params.compare, "compare", "",
+		"compare results to those of a previous run, as found in the provided JSON file")
+	benchCommand.Flags().VarP(&params.rules, "rules", "r",
+		"set custom rules file(s). This flag can be repeated.")
+	benchCommand.Flags().IntVarP(&params.count, "count", "n", 10,
+		"set number of times to lint the provided paths")
+	benchCommand.Flags().IntVar(&params.warmup, "warmup", 1,
+		"set number of runs to discard before measuring, like the first run which compiles the rules")
+	benchCommand.Flags().DurationVar(&params.duration, "duration", 0,
+		"lint for at least this duration rather than a fixed number of times")
+	benchCommand.Flags().DurationVar(&params.timeout, "timeout", 0,
+		"set timeout for the whole benchmark (default unlimited)")
+
+	addPprofFlag(benchCommand.Flags())
+
+	RootCommand.AddCommand(benchCommand)
+}
+
+func bench(args []string, params *benchCommandParams) error {
+	ctx, cancel := getLinterContext(params)
+	defer cancel()
+
+	regal, _, err := configuredLinter(args, params, params.rules)
+	if err != nil {
+		return err
+	}
+
+	var previous *benchResult
+
+	if params.compare != "" {
+		previous, err = readBenchResult(params.compare)
+		if err != nil {
+			return err
+		}
+	}
+
+	var outputWriter io.Writer = os.Stdout
+
+	if params.outputFile != "" {
+		outputWriter, err = getWriterForOutputFile(params.outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to open output file before use %w", err)
+		}
+	}
+
+	for range params.warmup {
+		if _, err = regal.Lint(ctx); err != nil {
+			return fmt.Errorf("error(s) encountered while linting: %w", err)
+		}
+	}
+
+	result, err := runBenchmark(ctx, regal, params.count, params.duration)
+	if err != nil {
+		return err
+	}
+
+	result.Paths = args
+
+	if result.Rules, err = profileRules(ctx, regal); err != nil {
+		return err
+	}
+
+	if params.format == formatJSON {
+		encoder := json.NewEncoder(outputWriter)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode benchmark result: %w", err)
+		}
+
+		return nil
+	}
+
+	return writeBenchResult(outputWriter, result, previous)
+}
+
+// runBenchmark lints count times, or until duration has passed if provided, and returns the measurements.
+func runBenchmark(ctx context.Context, regal linter.Linter, count int, duration time.Duration) (benchResult, error) {
+	var (
+		result    benchResult
+		durations []int64
+		before    runtime.MemStats
+		after     runtime.MemStats
+	)
+
+	start := time.Now()
+
+	for (duration > 0 && time.Since(start) < duration) || (duration <= 0 && len(durations) < count) {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		runStart := time.Now()
+
+		rep, err := regal.Lint(ctx)
+		if err != nil {
+			return benchResult{}, fmt.Errorf("error(s) encountered while linting: %w", err)
+		}
+
+		durations = append(durations, time.Since(runStart).Nanoseconds())
+
+		runtime.ReadMemStats(&after)
+
+		result.Allocations.Bytes += after.TotalAlloc - before.TotalAlloc
+		result.Allocations.Objects += after.Mallocs - before.Mallocs
+		result.Files = rep.Summary.FilesScanned
+		result.Violations = rep.Summary.NumViolations
+	}
+
+	runs := len(durations)
+
+	result.Runs = runs
+	result.Allocations.Bytes /= uint64(runs)
+	result.Allocations.Objects /= uint64(runs)
+
+	slices.Sort(durations)
+
+	for _, d := range durations {
+		result.Wall.Total += d
+	}
+
+	result.Wall.Min = durations[0]
+	result.Wall.Max = durations[runs-1]
+	result.Wall.Mean = result.Wall.Total / int64(runs)
+	result.Wall.Median = durations[runs/2]
+
+	return result, nil
+}
+
+// profileRules lints once with profiling enabled, and returns the time spent in each rule, sorted by time spent.
+// This is done separately from the measured runs, as the overhead of profiling would otherwise skew their results.
+// Note that files are linted concurrently, so the sum of these times may exceed the wall time of a run.
+func profileRules(ctx context.Context, regal linter.Linter) ([]benchRuleTime, error) {
+	rep, err := regal.WithProfiling(true).WithProfilingLimit(0).Lint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error(s) encountered while linting: %w", err)
+	}
+
+	ruleTimes := make(map[string]benchRuleTime)
+
+	for _, entry := range rep.Profile {
+		name := ruleNameFromLocation(entry.Location)
+
+		rt := ruleTimes[name]
+		rt.Name = name
+		rt.Time += entry.TotalTimeNs
+		rt.NumEval += entry.NumEval
+		ruleTimes[name] = rt
+	}
+
+	rules := make([]benchRuleTime, 0, len(ruleTimes))
+	for _, rt := range ruleTimes {
+		rules = append(rules, rt)
+	}
+
+	slices.SortFunc(rules, func(a, b benchRuleTime) int {
+		if c := cmp.Compare(b.Time, a.Time); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return rules, nil
+}
+
+// ruleNameFromLocation returns the name of the rule evaluated at the location of a profile entry, like
+// "style/line-length" for "/regal/rules/style/line_length.rego:12", or the file name for locations outside
+// of a rule, like "/regal/ast/ast.rego".
+func ruleNameFromLocation(location string) string {
+	file := location
+	if i := strings.LastIndex(location, ":"); i != -1 {
+		file = location[:i]
+	}
+
+	parts := strings.Split(strings.TrimPrefix(file, "/"), "/")
+	if len(parts) == 4 && parts[0] == "regal" && parts[1] == "rules" {
+		title := strings.ReplaceAll(strings.TrimSuffix(parts[3], ".rego"), "_", "-")
+
+		return parts[2] + "/" + title
+	}
+
+	return file
+}
+
+func readBenchResult(path string) (*benchResult, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous benchmark result: %w", err)
+	}
+
+	var result benchResult
+	if err := json.Unmarshal(bs, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode previous benchmark result from %s: %w", path, err)
+	}
+
+	return &result, nil
+}
+
+// writeBenchResult writes the result in a human-readable format, with the change from the previous result in
+// parentheses, if provided.
+func writeBenchResult(w io.Writer, result benchResult, previous *benchResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	delta := func(current, prev int64, found bool) string {
+		if previous == nil || !found || prev == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf("(%+.1f%%)", float64(current-prev)/float64(prev)*100)
+	}
+
+	duration := func(ns int64) string {
+		return time.Duration(ns).Round(time.Microsecond).String()
+	}
+
+	fmt.Fprintf(tw, "Runs:\t%d\t\n", result.Runs)
+	fmt.Fprintf(tw, "Files:\t%d\t\n", result.Files)
+	fmt.Fprintf(tw, "Violations:\t%d\t\n\n", result.Violations)
+
+	var prev benchResult
+	if previous != nil {
+		prev = *previous
+	}
+
+	for _, row := range []struct {
+		name    string
+		current int64
+		prev    int64
+	}{
+		{"Wall time (min):", result.Wall.Min, prev.Wall.Min},
+		{"Wall time (median):", result.Wall.Median, prev.Wall.Median},
+		{"Wall time (mean):", result.Wall.Mean, prev.Wall.Mean},
+		{"Wall time (max):", result.Wall.Max, prev.Wall.Max},
+	} {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", row.name, duration(row.current), delta(row.current, row.prev, true))
+	}
+
+	//nolint:gosec // allocations won't overflow int64
+	fmt.Fprintf(tw, "Allocated bytes per run:\t%d\t%s\n", result.Allocations.Bytes,
+		delta(int64(result.Allocations.Bytes), int64(prev.Allocations.Bytes), true))
+	//nolint:gosec // allocations won't overflow int64
+	fmt.Fprintf(tw, "Allocations per run:\t%d\t%s\n\n", result.Allocations.Objects,
+		delta(int64(result.Allocations.Objects), int64(prev.Allocations.Objects), true))
+
+	previousRules := make(map[string]benchRuleTime, len(prev.Rules))
+	for _, rt := range prev.Rules {
+		previousRules[rt.Name] = rt
+	}
+
+	fmt.Fprintln(tw, "Rule\tTime (profiled)\t\tEvaluations")
+
+	for _, rt := range result.Rules {
+		p, found := previousRules[rt.Name]
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", rt.Name, duration(rt.Time), delta(rt.Time, p.Time, found), rt.NumEval)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write benchmark result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type configEffectiveCommandParams struct {
+	configFile      string
+	format          string
+	capabilities    bool
+	rules           repeatedStringFlag
+	disable         repeatedStringFlag
+	disableAll      bool
+	disableCategory repeatedStringFlag
+	enable          repeatedStringFlag
+	enableAll       bool
+	enableCategory  repeatedStringFlag
+	ignoreFiles     repeatedStringFlag
+	timeout         time.Duration
+}
+
+func (p *configEffectiveCommandParams) getConfigFile() string {
+	return p.configFile
+}
+
+func (p *configEffectiveCommandParams) getTimeout() time.Duration {
+	return p.timeout
+}
+
+func init() {
+	configCommand := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration of Regal",
+	}
+
+	params := &configEffectiveCommandParams{}
+
+	effectiveCommand := &cobra.Command{
+		Use:   "effective <path>",
+		Short: "Print the configuration applying to a file",
+		Long: `Print the configuration applying to the provided file, with the provided defaults and user configuration
+merged, and the enable, disable and ignore flags applied, like when linting with the same flags.
+
+The level of each rule is the one used when linting the file, where rules ignored for the file, by their
+ignore configuration or the flags provided, have level "ignore". If the file is ignored altogether, all rules
+have level "ignore", and a note is printed to stderr.`,
+
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("exactly one file must be provided")
+			}
+
+			if params.format != formatYAML && params.format != formatJSON {
+				return fmt.Errorf("unknown format %s, expected yaml or json", params.format)
+			}
+
+			return nil
+		},
+
+		RunE: wrapProfiling(func(args []string) error {
+			return effectiveConfig(args[0], params, os.Stdout)
+		}),
+	}
+
+	effectiveCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file")
+	effectiveCommand.Flags().StringVarP(&params.format, "format", "f", formatYAML,
+		"set output format (yaml, json)")
+	effectiveCommand.Flags().BoolVar(&params.capabilities, "capabilities", false,
+		"include the capabilities, like the built-in functions available, in the output")
+	effectiveCommand.Flags().VarP(&params.rules, "rules", "r",
+		"set custom rules file(s). This flag can be repeated.")
+	effectiveCommand.Flags().DurationVar(&params.timeout, "timeout", 0,
+		"set timeout for determining the configuration (default unlimited)")
+
+	effectiveCommand.Flags().VarP(&params.disable, "disable", "d",
+		"disable specific rule(s). This flag can be repeated.")
+	effectiveCommand.Flags().BoolVarP(&params.disableAll, "disable-all", "D", false,
+		"disable all rules")
+	effectiveCommand.Flags().VarP(&params.disableCategory, "disable-category", "",
+		"disable all rules in a category. This flag can be repeated.")
+
+	effectiveCommand.Flags().VarP(&params.enable, "enable", "e",
+		"enable specific rule(s). This flag can be repeated.")
+	effectiveCommand.Flags().BoolVarP(&params.enableAll, "enable-all", "E", false,
+		"enable all rules")
+	effectiveCommand.Flags().VarP(&params.enableCategory, "enable-category", "",
+		"enable all rules in a category. This flag can be repeated.")
+
+	effectiveCommand.Flags().VarP(&params.ignoreFiles, "ignore-files", "",
+		"ignore all files matching a glob-pattern. This flag can be repeated.")
+
+	addPprofFlag(effectiveCommand.Flags())
+
+	configCommand.AddCommand(effectiveCommand)
+	RootCommand.AddCommand(configCommand)
+}
+
+func effectiveConfig(path string, params *configEffectiveCommandParams, w io.Writer) error {
+	ctx, cancel := getLinterContext(params)
+	defer cancel()
+
+	regal, _, err := configuredLinter([]string{path}, params, params.rules)
+	if err != nil {
+		return err
+	}
+
+	regal = regal.
+		WithDisableAll(params.disableAll).
+		WithDisabledCategories(params.disableCategory.v...).
+		WithDisabledRules(params.disable.v...).
+		WithEnableAll(params.enableAll).
+		WithEnabledCategories(params.enableCategory.v...).
+		WithEnabledRules(params.enable.v...)
+
+	if params.ignoreFiles.isSet {
+		regal = regal.WithIgnore(params.ignoreFiles.v)
+	}
+
+	conf, ignored, err := regal.EffectiveConfig(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to determine effective config: %w", err)
+	}
+
+	if !params.capabilities {
+		conf.Capabilities = nil
+	}
+
+	if ignored {
+		fmt.Fprintf(os.Stderr, "%s is ignored, and won't be linted\n", path)
+	}
+
+	if params.format == formatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(conf); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+
+		return nil
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(conf); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return encoder.Close() //nolint:wrapcheck
+}
@@ -5,6 +5,8 @@ const (
 	formatJSON = "json"
 	// formatPretty is the pretty format value for the --format flag in various commands.
 	formatPretty = "pretty"
+	// formatSnippet is the snippet format value for the --format flag in various commands.
+	formatSnippet = "snippet"
 	// formatCompact is the compact format value for the --format flag in various commands.
 	formatCompact = "compact"
 	// formatGitHub is the GitHub format value for the --format flag in various commands.
@@ -13,4 +15,26 @@ const (
 	formatFestive = "festive"
 	// formatSarif is the SARIF format value for the --format flag in various commands.
 	formatSarif = "sarif"
+	// formatGitLab is the GitLab Code Quality format value for the --format flag in various commands.
+	formatGitLab = "gitlab"
+	// formatTAP is the Test Anything Protocol format value for the --format flag in various commands.
+	formatTAP = "tap"
+	// formatJUnit is the JUnit XML format value for the --format flag in various commands.
+	formatJUnit = "junit"
+	// formatCheckstyle is the Checkstyle XML format value for the --format flag in various commands.
+	formatCheckstyle = "checkstyle"
+	// formatRdJSON is the Reviewdog rdjson format value for the --format flag in various commands.
+	formatRdJSON = "rdjson"
+	// formatRdJSONL is the Reviewdog rdjsonl format value for the --format flag in various commands.
+	formatRdJSONL = "rdjsonl"
+	// formatMarkdown is the Markdown format value for the --format flag in various commands.
+	formatMarkdown = "markdown"
+	// formatTemplate is the Go template format value for the --format flag in various commands.
+	formatTemplate = "template"
+	// formatYAML is the YAML format value for the --format flag in various commands.
+	formatYAML = "yaml"
+	// formatExecPrefix prefixes the command of an external reporter in the value of the --format flag.
+	formatExecPrefix = "exec:"
+	// formatWebhookPrefix prefixes the URL to post reports to in the value of the --format flag.
+	formatWebhookPrefix = "webhook:"
 )
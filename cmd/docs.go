@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/styrainc/regal/pkg/linter"
+)
+
+type docsVerifyCommandParams struct {
+	configFile string
+	rules      repeatedStringFlag
+	timeout    time.Duration
+}
+
+func (p *docsVerifyCommandParams) getConfigFile() string {
+	return p.configFile
+}
+
+func (p *docsVerifyCommandParams) getTimeout() time.Duration {
+	return p.timeout
+}
+
+func init() {
+	docsCommand := &cobra.Command{
+		Use:   "docs",
+		Short: "Work with the documentation of rules",
+	}
+
+	params := &docsVerifyCommandParams{}
+
+	verifyCommand := &cobra.Command{
+		Use:   "verify [path [...]]",
+		Short: "Verify that rules flag, or pass, the examples of their documentation",
+		Long: `Verify that rules flag the code examples their documentation says to avoid, and pass those it says to
+prefer, keeping the documentation of rules truthful as the rules evolve.
+
+Paths provided are Markdown documents of rules, or directories of such, in the format of the documentation of the
+rules of Regal, where Rego code blocks following an **Avoid** line are expected to be flagged by the rule, and those
+following a **Prefer** line to pass. Consecutive code blocks make up a single example of several files, and examples
+preceded by a "<!-- regal docs verify: skip -->" comment are skipped.
+
+Examples found in the metadata of custom rules, provided with --rules or found in the .regal/rules directory, are
+verified too, as lists of code under the examples.fail and examples.pass attributes of the custom section of the
+package annotation of the rule.
+
+Exits with a non-zero status if any example isn't flagged, or passed, as expected.`,
+
+		RunE: wrapProfiling(func(args []string) error {
+			failed, err := verifyDocs(args, params, os.Stdout)
+			if err != nil {
+				return err
+			}
+
+			if failed {
+				return exit(1)
+			}
+
+			return nil
+		}),
+	}
+
+	verifyCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file")
+	verifyCommand.Flags().VarP(&params.rules, "rules", "r",
+		"set custom rules file(s). This flag can be repeated.")
+	verifyCommand.Flags().DurationVar(&params.timeout, "timeout", 0,
+		"set timeout for verification (default unlimited)")
+
+	addPprofFlag(verifyCommand.Flags())
+
+	docsCommand.AddCommand(verifyCommand)
+	RootCommand.AddCommand(docsCommand)
+}
+
+func verifyDocs(args []string, params *docsVerifyCommandParams, w io.Writer) (bool, error) {
+	ctx, cancel := getLinterContext(params)
+	defer cancel()
+
+	regal, _, err := configuredLinter(args, params, params.rules)
+	if err != nil {
+		return false, err
+	}
+
+	examples, err := regal.RuleExamples()
+	if err != nil {
+		return false, fmt.Errorf("failed to read examples of rules: %w", err)
+	}
+
+	for _, path := range args {
+		docExamples, err := examplesFromDocs(path)
+		if err != nil {
+			return false, err
+		}
+
+		examples = append(examples, docExamples...)
+	}
+
+	failures, err := regal.VerifyExamples(ctx, examples)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("verification timed out: %w", err)
+		}
+
+		return false, fmt.Errorf("failed to verify examples: %w", err)
+	}
+
+	for _, failure := range failures {
+		fmt.Fprintf(w, "%s: %s\n", failure.Example.Source, failure.Reason)
+	}
+
+	fmt.Fprintf(w, "%d examples verified, %d failed\n", len(examples), len(failures))
+
+	return len(failures) > 0, nil
+}
+
+// examplesFromDocs returns the examples of the documents of rules at path, or of the Markdown files of the directory
+// at path.
+func examplesFromDocs(path string) ([]linter.Example, error) {
+	var examples []linter.Example
+
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(p, ".md") {
+			return nil
+		}
+
+		bs, err := os.ReadFile(p)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		examples = append(examples, linter.ExamplesFromDoc(p, string(bs))...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read documentation of rules at %s: %w", path, err)
+	}
+
+	return examples, nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
@@ -14,12 +15,15 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/styrainc/regal/internal/diff"
 	rio "github.com/styrainc/regal/internal/io"
 	"github.com/styrainc/regal/pkg/config"
 	"github.com/styrainc/regal/pkg/fixer"
 	"github.com/styrainc/regal/pkg/fixer/fileprovider"
 	"github.com/styrainc/regal/pkg/fixer/fixes"
 	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
 )
 
 // fixCommandParams is similar to the lint params, but with some fields such as profiling removed.
@@ -36,8 +40,10 @@ type fixCommandParams struct {
 	enableCategory  repeatedStringFlag
 	format          string
 	ignoreFiles     repeatedStringFlag
+	interactive     bool
 	noColor         bool
 	outputFile      string
+	regoV1          bool
 	rules           repeatedStringFlag
 	timeout         time.Duration
 }
@@ -107,6 +113,10 @@ The linter rules with automatic fixes available are currently:
 		"set file to use for fixing output, defaults to stdout")
 	fixCommand.Flags().BoolVar(&params.noColor, "no-color", false,
 		"Disable color output")
+	fixCommand.Flags().BoolVar(&params.interactive, "interactive", false,
+		"show the diff of each fix and ask whether to apply it")
+	fixCommand.Flags().BoolVar(&params.regoV1, "rego-v1", false,
+		"migrate all files to Rego v1, reporting any issues that must be fixed manually")
 	fixCommand.Flags().VarP(&params.rules, "rules", "r",
 		"set custom rules file(s). This flag can be repeated.")
 	fixCommand.Flags().DurationVar(&params.timeout, "timeout", 0,
@@ -234,8 +244,14 @@ func fix(args []string, params *fixCommandParams) error {
 		log.Println("no user-provided config file found, will use the default config")
 	}
 
-	f := fixer.NewFixer()
+	f := fixer.NewFixer().WithRegoV1Migration(params.regoV1)
 	f.RegisterFixes(fixes.NewDefaultFixes()...)
+	// lines are indented with spaces by the width configured for the indentation rule
+	f.RegisterFixes(&fixes.Indentation{Width: rules.IndentWidth(userConfig.Rules["style"]["indentation"])})
+
+	if params.interactive {
+		f.WithPrompt(interactivePrompt(os.Stdin, os.Stdout))
+	}
 
 	fileProvider := fileprovider.NewFSFileProvider(args...)
 
@@ -256,3 +272,58 @@ func fix(args []string, params *fixCommandParams) error {
 
 	return nil
 }
+
+// interactivePrompt returns a prompt showing the diff of each fix proposed, and reading the decision on whether to
+// apply it from in. Reaching the end of in is taken as a decision to quit.
+func interactivePrompt(in io.Reader, out io.Writer) fixer.Prompt {
+	reader := bufio.NewReader(in)
+
+	return func(violation report.Violation, before, after []byte) (fixer.Decision, error) {
+		location := violation.Location.File
+		if violation.Location.Row > 0 {
+			location = fmt.Sprintf("%s:%d:%d", location, violation.Location.Row, violation.Location.Column)
+		}
+
+		fmt.Fprintf(out, "\n%s: %s\n", color.New(color.Bold).Sprint(location), violation.Title)
+
+		for _, line := range strings.SplitAfter(diff.Unified(string(before), string(after), 3), "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				color.New(color.FgCyan).Fprint(out, line)
+			case strings.HasPrefix(line, "+"):
+				color.New(color.FgGreen).Fprint(out, line)
+			case strings.HasPrefix(line, "-"):
+				color.New(color.FgRed).Fprint(out, line)
+			default:
+				fmt.Fprint(out, line)
+			}
+		}
+
+		for {
+			fmt.Fprintf(out, "Apply fix? [y]es, [n]o, [a]ll %s fixes, [q]uit: ", violation.Title)
+
+			answer, err := reader.ReadString('\n')
+
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return fixer.Accept, nil
+			case "n", "no":
+				return fixer.Skip, nil
+			case "a", "all":
+				return fixer.AcceptAll, nil
+			case "q", "quit":
+				return fixer.Quit, nil
+			}
+
+			if errors.Is(err, io.EOF) {
+				fmt.Fprintln(out)
+
+				return fixer.Quit, nil
+			}
+
+			if err != nil {
+				return fixer.Skip, fmt.Errorf("failed to read answer: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/styrainc/regal/internal/lsp"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+type indexCommandParams struct {
+	configFile string
+	format     string
+	outputFile string
+}
+
+func (p *indexCommandParams) getConfigFile() string {
+	return p.configFile
+}
+
+func init() {
+	params := &indexCommandParams{}
+
+	indexCommand := &cobra.Command{
+		Use:   "index [path]",
+		Short: "Write an index of Rego source files for code navigation",
+		Long: `Write an index of the definitions of, and references to, the rules of the Rego files in the provided path,
+or the current directory, along with their documentation, and that of the built-in functions called, in the SCIP or
+LSIF format. Code browsing platforms, like Sourcegraph, use the index to provide the same navigation as the Regal
+language server does in editors.
+
+Files ignored by the configuration are left out of the index.`,
+
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("at most one path may be provided")
+			}
+
+			if params.format != lsp.IndexFormatSCIP && params.format != lsp.IndexFormatLSIF {
+				return fmt.Errorf("unknown format %s, expected scip or lsif", params.format)
+			}
+
+			return nil
+		},
+
+		RunE: wrapProfiling(func(args []string) error {
+			return index(args, params)
+		}),
+	}
+
+	indexCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file")
+	indexCommand.Flags().StringVarP(&params.format, "format", "f", lsp.IndexFormatSCIP,
+		"set format of index (scip, lsif)")
+	indexCommand.Flags().StringVarP(&params.outputFile, "output-file", "o", "",
+		"set file to write index to, defaults to index.scip or dump.lsif, by format")
+
+	addPprofFlag(indexCommand.Flags())
+
+	RootCommand.AddCommand(indexCommand)
+}
+
+func index(args []string, params *indexCommandParams) error {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	root, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of %s: %w", args[0], err)
+	}
+
+	_, userConfig, err := configuredLinter([]string{root}, params, repeatedStringFlag{})
+	if err != nil {
+		return err
+	}
+
+	var ignore []string
+	if userConfig != nil {
+		ignore = userConfig.Ignore.Files
+	}
+
+	paths, err := config.FilterIgnoredPaths([]string{root}, ignore, true, "")
+	if err != nil {
+		return fmt.Errorf("failed to filter paths: %w", err)
+	}
+
+	input, err := rules.InputFromPaths(paths)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	// when a single file is indexed, paths are relative to the directory of the file
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+
+	outputFile := params.outputFile
+	if outputFile == "" {
+		outputFile = "index.scip"
+		if params.format == lsp.IndexFormatLSIF {
+			outputFile = "dump.lsif"
+		}
+	}
+
+	w, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	defer w.Close()
+
+	if err := lsp.WriteIndex(w, params.format, root, input.Modules); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	fmt.Fprintf(os.Stdout, "%d files indexed in %s\n", len(input.Modules), outputFile)
+
+	return nil
+}
@@ -2,18 +2,28 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/styrainc/regal/internal/debugbundle"
 	"github.com/styrainc/regal/internal/lsp"
+	"github.com/styrainc/regal/internal/metrics"
 )
 
 func init() {
 	verboseLogging := false
+	noIndex := false
+	metricsAddr := ""
+	debugBundle := ""
+	debugContent := false
 
 	languageServerCommand := &cobra.Command{
 		Use:   "language-server",
@@ -28,6 +38,34 @@ func init() {
 				ErrorLog: os.Stderr,
 			}
 
+			if !noIndex {
+				if cacheDir, err := os.UserCacheDir(); err == nil {
+					opts.IndexDir = filepath.Join(cacheDir, "regal", "index")
+				}
+			}
+
+			if metricsAddr != "" {
+				opts.Metrics = metrics.NewPrometheus()
+
+				mux := http.NewServeMux()
+				mux.Handle("/metrics", opts.Metrics.Handler())
+
+				srv := &http.Server{Addr: metricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+				// stdout is reserved for the client connection, so errors are logged to stderr
+				go func() {
+					if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						fmt.Fprintln(os.Stderr, "failed to serve metrics:", err)
+					}
+				}()
+
+				defer srv.Close()
+			}
+
+			if debugBundle != "" {
+				opts.DebugBundle = debugbundle.New(os.Args[1:], debugContent)
+			}
+
 			ls := lsp.NewLanguageServer(opts)
 
 			conn := lsp.NewConnectionFromLanguageServer(ctx, ls.Handle, &lsp.ConnectionOptions{
@@ -55,11 +93,29 @@ func init() {
 				fmt.Fprintln(os.Stderr, "signal: ", sig.String())
 			}
 
+			if debugBundle != "" {
+				path, err := ls.WriteDebugBundle(debugBundle)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				fmt.Fprintln(os.Stderr, "debug bundle written to", path)
+			}
+
 			return nil
 		}),
 	}
 
 	languageServerCommand.Flags().BoolVarP(&verboseLogging, "verbose", "v", verboseLogging, "Enable verbose logging")
+	languageServerCommand.Flags().BoolVar(&noIndex, "no-index", noIndex,
+		"Disable saving an index of workspace diagnostics between sessions")
+	languageServerCommand.Flags().StringVar(&metricsAddr, "metrics-addr", metricsAddr,
+		"Expose metrics in the Prometheus format on the /metrics endpoint of this address")
+	languageServerCommand.Flags().StringVar(&debugBundle, "debug-bundle", debugBundle,
+		"Write an archive of the files, configuration, request timings and errors of the session to this directory "+
+			"on exit, for attaching to bug reports")
+	languageServerCommand.Flags().BoolVar(&debugContent, "debug-bundle-include-content", debugContent,
+		"Include the contents of the files in the debug bundle, which are otherwise redacted")
 
 	RootCommand.AddCommand(languageServerCommand)
 }
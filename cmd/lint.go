@@ -1,42 +1,68 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v3"
 
+	"github.com/open-policy-agent/opa/cover"
 	"github.com/open-policy-agent/opa/metrics"
 	"github.com/open-policy-agent/opa/topdown"
 
+	"github.com/styrainc/regal/internal/codeowners"
+	"github.com/styrainc/regal/internal/debugbundle"
+	"github.com/styrainc/regal/internal/git"
 	rio "github.com/styrainc/regal/internal/io"
 	regalmetrics "github.com/styrainc/regal/internal/metrics"
+	"github.com/styrainc/regal/internal/tracing"
 	"github.com/styrainc/regal/pkg/config"
 	"github.com/styrainc/regal/pkg/linter"
 	"github.com/styrainc/regal/pkg/report"
 	"github.com/styrainc/regal/pkg/reporter"
+	"github.com/styrainc/regal/pkg/rules"
 )
 
 type lintCommandParams struct {
 	timeout         time.Duration
+	timeoutSet      bool
+	batchSize       int
+	coverage        string
+	baseline        string
+	genBaseline     string
+	watch           bool
+	diff            string
+	changed         bool
+	diffBase        string
+	blame           bool
 	configFile      string
 	format          string
 	outputFile      string
 	failLevel       string
+	failNewOnly     bool
+	exitZero        bool
+	groupBy         string
+	schema          bool
 	rules           repeatedStringFlag
 	noColor         bool
 	debug           bool
 	enablePrint     bool
 	metrics         bool
 	profile         bool
+	showSuppressed  bool
 	disable         repeatedStringFlag
 	disableAll      bool
 	disableCategory repeatedStringFlag
@@ -44,6 +70,19 @@ type lintCommandParams struct {
 	enableAll       bool
 	enableCategory  repeatedStringFlag
 	ignoreFiles     repeatedStringFlag
+	followSymlinks  bool
+	skipHiddenDirs  bool
+	maxDepth        int
+	noGitignore     bool
+	traversalSet    map[string]bool
+	webhookHeaders  repeatedStringFlag
+	webhookRetries  int
+	template        string
+	templateFile    string
+	stdinFilename   string
+	debugBundle     string
+	debugContent    bool
+	recorder        *debugbundle.Recorder
 }
 
 func (p *lintCommandParams) getConfigFile() string {
@@ -56,6 +95,12 @@ func (p *lintCommandParams) getTimeout() time.Duration {
 
 const stringType = "string"
 
+// stdinPath is the path provided to lint the policy read from stdin.
+const stdinPath = "-"
+
+// snippetContextLines is the number of lines shown before and after the line of each violation by the snippet format.
+const snippetContextLines = 1
+
 type repeatedStringFlag struct {
 	v     []string
 	isSet bool
@@ -82,55 +127,145 @@ func init() {
 	lintCommand := &cobra.Command{
 		Use:   "lint <path> [path [...]]",
 		Short: "Lint Rego source files",
-		Long:  `Lint Rego source files for linter rule violations.`,
+		Long: `Lint Rego source files for linter rule violations.
+
+Provide - as the path to lint a policy read from stdin, like an unsaved editor buffer, and --stdin-filename to set
+the name of its file.`,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// an explicit --timeout 0 overrides the timeout of the configuration too
+			params.timeoutSet = cmd.Flags().Changed("timeout")
+
+			// only traversal flags provided override the configuration, as false is otherwise indistinguishable from
+			// not set
+			params.traversalSet = make(map[string]bool, len(traversalFlags))
+			for _, name := range traversalFlags {
+				params.traversalSet[name] = cmd.Flags().Changed(name)
+			}
+
+			if params.schema {
+				if params.format != formatJSON {
+					return errors.New("--schema is only supported by the json format")
+				}
+
+				return nil
+			}
 
-		PreRunE: func(_ *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				return errors.New("at least one file or directory must be provided for linting")
 			}
 
+			if slices.Contains(args, stdinPath) && len(args) > 1 {
+				return errors.New("- can't be combined with other paths, as only one policy can be read from stdin")
+			}
+
+			if cmd.Flags().Changed("stdin-filename") && !slices.Contains(args, stdinPath) {
+				return errors.New("--stdin-filename is only supported when linting from stdin, with -")
+			}
+
+			if !slices.Contains([]string{"error", "warning", "ignore"}, params.failLevel) {
+				return fmt.Errorf("unknown fail level %s, expected error, warning or ignore", params.failLevel)
+			}
+
+			if params.failNewOnly && params.diff == "" {
+				return errors.New("--fail-new-only requires --diff")
+			}
+
+			if (params.changed || params.diffBase != "") && slices.Contains(args, stdinPath) {
+				return errors.New("--changed can't be used when linting from stdin")
+			}
+
+			if params.watch && (params.changed || params.diffBase != "") {
+				return errors.New("--watch and --changed can't be used together")
+			}
+
+			if params.watch && slices.Contains(args, stdinPath) {
+				return errors.New("--watch can't be used when linting from stdin")
+			}
+
+			if params.watch && params.genBaseline != "" {
+				return errors.New("--watch and --generate-baseline can't be used together")
+			}
+
+			if params.baseline != "" && params.genBaseline != "" {
+				return errors.New("--baseline and --generate-baseline can't be used together")
+			}
+
+			if params.template != "" && params.templateFile != "" {
+				return errors.New("--template and --template-file can't be used together")
+			}
+
+			hasTemplate := params.template != "" || params.templateFile != ""
+
+			if params.format == formatTemplate && !hasTemplate {
+				return errors.New("--template or --template-file is required by the template format")
+			}
+
+			if params.format != formatTemplate && hasTemplate {
+				return errors.New("--template and --template-file are only supported by the template format")
+			}
+
+			if params.groupBy != "" {
+				if !slices.Contains([]string{
+					reporter.GroupByFile, reporter.GroupByRule, reporter.GroupByCategory, reporter.GroupByOwner,
+					reporter.GroupByTarget,
+				}, params.groupBy) {
+					return fmt.Errorf("unknown group %s, expected file, rule, category, owner or target", params.groupBy)
+				}
+
+				if !slices.Contains(
+					[]string{formatSnippet, formatPretty, formatCompact, formatJSON, formatMarkdown}, params.format,
+				) {
+					return errors.New(
+						"--group-by is only supported by the snippet, pretty, compact, json and markdown formats",
+					)
+				}
+			}
+
 			return nil
 		},
 
 		RunE: wrapProfiling(func(args []string) error {
+			if params.schema {
+				_, err := os.Stdout.Write(reporter.JSONReportSchema())
+
+				return err //nolint:wrapcheck
+			}
+
 			// Allow setting debug mode via GitHub UI for failing actions
 			if os.Getenv("RUNNER_DEBUG") != "" {
 				params.debug = true
 			}
 
-			rep, err := lint(args, params)
-			if err != nil {
-				log.SetOutput(os.Stderr)
-				log.Println(err)
+			if params.debugBundle != "" {
+				params.recorder = debugbundle.New(
+					debugbundle.RedactFlags(os.Args[1:], "webhook-header"), params.debugContent)
 
-				return exit(1)
+				defer writeDebugBundle(params.recorder, params.debugBundle)
 			}
 
-			errorsFound := 0
-			warningsFound := 0
+			if params.watch {
+				if err := watchLint(args, params); err != nil {
+					log.SetOutput(os.Stderr)
+					log.Println(err)
 
-			for _, violation := range rep.Violations {
-				if violation.Level == "error" {
-					errorsFound++
-				} else if violation.Level == "warning" {
-					warningsFound++
+					return exit(1)
 				}
+
+				return nil
 			}
 
-			exitCode := 0
+			rep, err := lint(args, params)
+			if err != nil {
+				params.recorder.AddError(err)
 
-			if params.failLevel == "error" && errorsFound > 0 {
-				exitCode = 3
-			}
+				log.SetOutput(os.Stderr)
+				log.Println(err)
 
-			if params.failLevel == "warning" {
-				if errorsFound > 0 {
-					exitCode = 3
-				} else if warningsFound > 0 {
-					exitCode = 2
-				}
+				return exit(1)
 			}
-			if exitCode != 0 {
+
+			if exitCode := lintExitCode(rep, params); exitCode != 0 {
 				return exit(exitCode)
 			}
 
@@ -140,18 +275,70 @@ func init() {
 
 	lintCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
 		"set path of configuration file")
-	lintCommand.Flags().StringVarP(&params.format, "format", "f", formatPretty,
-		"set output format (pretty, compact, json, github, sarif)")
+	lintCommand.Flags().StringVarP(&params.format, "format", "f", formatSnippet,
+		"set output format (snippet, pretty, compact, json, github, sarif, gitlab, rdjson, rdjsonl, tap, junit, "+
+			"checkstyle, markdown, template to render the report with the template provided by --template or "+
+			"--template-file, exec:<command> to have the JSON report provided to a command on stdin, "+
+			"or webhook:<url> to have the JSON report posted to a URL)")
+	lintCommand.Flags().StringVar(&params.template, "template", "",
+		"set Go template rendering the report with the template format, like '{{ range .Violations }}...{{ end }}'")
+	lintCommand.Flags().StringVar(&params.templateFile, "template-file", "",
+		"set path of file with Go template rendering the report with the template format")
+	lintCommand.Flags().StringVar(&params.stdinFilename, "stdin-filename", "stdin.rego",
+		"set name of the file linted from stdin with -, used in the report, for finding the configuration and "+
+			"matching ignored files")
 	lintCommand.Flags().StringVarP(&params.outputFile, "output-file", "o", "",
 		"set file to use for linting output, defaults to stdout")
 	lintCommand.Flags().StringVarP(&params.failLevel, "fail-level", "l", "error",
-		"set level at which to fail with a non-zero exit code (error, warning)")
+		"set level at which to fail with a non-zero exit code (error, warning, or ignore to never fail on violations)")
+	lintCommand.Flags().BoolVar(&params.exitZero, "exit-zero", false,
+		"exit with code 0 when violations are found, for only reporting them, like --fail-level ignore")
+	lintCommand.Flags().BoolVar(&params.failNewOnly, "fail-new-only", false,
+		"only fail on violations new since the ref provided with --diff")
+	lintCommand.Flags().StringVar(&params.diff, "diff", "",
+		"classify violations as new or pre-existing, by the changes made since a git ref (branch, tag or commit)")
+	lintCommand.Flags().BoolVar(&params.changed, "changed", false,
+		"lint only files changed since the git ref of --diff-base or --diff (default HEAD), reading the other files "+
+			"only for aggregate rules")
+	lintCommand.Flags().StringVar(&params.diffBase, "diff-base", "",
+		"set git ref (branch, tag or commit) to find the files changed since with --changed, which it implies")
+	lintCommand.Flags().BoolVar(&params.blame, "blame", false,
+		"annotate violations with the commit and author last changing their line, as reported by git blame")
+	lintCommand.Flags().BoolVar(&params.watch, "watch", false,
+		"lint again whenever files are changed, reporting the violations of all files each time, until interrupted")
+	lintCommand.Flags().BoolVar(&params.schema, "schema", false,
+		"print the JSON Schema of reports in the json format, rather than linting")
+	lintCommand.Flags().StringVar(&params.groupBy, "group-by", "",
+		"group violations by file, rule, category, owner, as found in CODEOWNERS, or target linted "+
+			"(snippet, pretty, compact, json and markdown formats only)")
+	lintCommand.Flags().BoolVar(&params.followSymlinks, "follow-symlinks", false,
+		"walk directories linked to by symbolic links in the paths provided")
+	lintCommand.Flags().BoolVar(&params.skipHiddenDirs, "skip-hidden-dirs", false,
+		"skip directories with names starting with a dot in the paths provided")
+	lintCommand.Flags().IntVar(&params.maxDepth, "max-depth", 0,
+		"set maximum depth of directories walked in the paths provided, where 1 is only the files directly in them "+
+			"(default no limit)")
+	lintCommand.Flags().BoolVar(&params.noGitignore, "no-gitignore", false,
+		"walk paths ignored by .gitignore files too, which are otherwise skipped")
+	lintCommand.Flags().VarP(&params.webhookHeaders, "webhook-header", "",
+		"set header of requests posting the report with the webhook format, as name: value, where the value is a "+
+			"template, like Bearer {{ env \"TOKEN\" }} - may be repeated")
+	lintCommand.Flags().IntVar(&params.webhookRetries, "webhook-retries", reporter.DefaultWebhookRetries,
+		"set number of times to retry posting the report with the webhook format")
 	lintCommand.Flags().BoolVar(&params.noColor, "no-color", false,
 		"Disable color output")
 	lintCommand.Flags().VarP(&params.rules, "rules", "r",
 		"set custom rules file(s). This flag can be repeated.")
 	lintCommand.Flags().DurationVar(&params.timeout, "timeout", 0,
-		"set timeout for linting (default unlimited)")
+		"set timeout for linting, overriding the timeout of the configuration (default unlimited)")
+	lintCommand.Flags().IntVar(&params.batchSize, "batch-size", 0,
+		"lint files in batches of this size to bound memory use in large workspaces (default all files at once)")
+	lintCommand.Flags().StringVar(&params.coverage, "coverage", "",
+		"set path of coverage report from opa test --coverage, for reporting rules not covered by tests")
+	lintCommand.Flags().StringVar(&params.baseline, "baseline", "",
+		"set path of baseline file from --generate-baseline, for reporting only violations not found in the baseline")
+	lintCommand.Flags().StringVar(&params.genBaseline, "generate-baseline", "",
+		"write a baseline of the violations found to this file, for later runs to report only new violations")
 	lintCommand.Flags().BoolVar(&params.debug, "debug", false,
 		"enable debug logging (including print output from custom policy)")
 	lintCommand.Flags().BoolVar(&params.enablePrint, "enable-print", false,
@@ -160,6 +347,14 @@ func init() {
 		"enable metrics reporting (currently supported only for JSON output format)")
 	lintCommand.Flags().BoolVar(&params.profile, "profile", false,
 		"enable profiling metrics to be added to reporting (currently supported only for JSON output format)")
+	lintCommand.Flags().BoolVar(&params.showSuppressed, "show-suppressed", false,
+		"report violations suppressed by ignore directives or configuration, for auditing suppressions")
+
+	lintCommand.Flags().StringVar(&params.debugBundle, "debug-bundle", "",
+		"write an archive of the inputs, configuration, timings and errors of the run to this directory, "+
+			"for attaching to bug reports")
+	lintCommand.Flags().BoolVar(&params.debugContent, "debug-bundle-include-content", false,
+		"include the contents of the files linted in the debug bundle, which are otherwise redacted")
 
 	lintCommand.Flags().VarP(&params.disable, "disable", "d",
 		"disable specific rule(s). This flag can be repeated.")
@@ -179,6 +374,7 @@ func init() {
 		"ignore all files matching a glob-pattern. This flag can be repeated.")
 
 	addPprofFlag(lintCommand.Flags())
+	addTracingFlags(lintCommand.Flags())
 
 	RootCommand.AddCommand(lintCommand)
 }
@@ -189,6 +385,9 @@ func lint(args []string, params *lintCommandParams) (report.Report, error) {
 	ctx, cancel := getLinterContext(params)
 	defer cancel()
 
+	ctx, span := tracing.Start(ctx, "regal lint")
+	defer span.End()
+
 	if params.noColor {
 		color.NoColor = true
 	}
@@ -204,44 +403,261 @@ func lint(args []string, params *lintCommandParams) (report.Report, error) {
 		}
 	}
 
-	var regalDir *os.File
+	m := metrics.New()
+	if params.metrics {
+		m.Timer(regalmetrics.RegalConfigSearch).Start()
+	}
 
-	var customRulesDir string
+	var stdinInput *rules.Input
 
-	var configSearchPath string
+	if len(args) == 1 && args[0] == stdinPath {
+		bs, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to read from stdin: %w", err)
+		}
 
-	cwd, _ := os.Getwd()
+		input, err := rules.InputFromText(params.stdinFilename, string(bs))
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to parse policy from stdin: %w", err)
+		}
+
+		// the policy is linted as if it was the file named, for its configuration and ignore patterns to apply
+		stdinInput, args = &input, []string{params.stdinFilename}
+	}
+
+	targets := resolveLintTargets(args, params)
+
+	if stdinInput != nil {
+		targets[0].input = stdinInput
+	}
 
-	m := metrics.New()
 	if params.metrics {
-		m.Timer(regalmetrics.RegalConfigSearch).Start()
+		m.Timer(regalmetrics.RegalConfigSearch).Stop()
 	}
 
-	if len(args) == 1 {
-		configSearchPath = args[0]
-		if !strings.HasPrefix(args[0], "/") {
-			configSearchPath = filepath.Join(cwd, args[0])
+	var isChanged func(path string) bool
+
+	if params.changed || params.diffBase != "" {
+		ref := changedBase(params)
+
+		diff, err := git.DiffAgainst(ctx, repositoryDir(args[0]), ref)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to find files changed since %s: %w", ref, err)
 		}
-	} else {
-		configSearchPath, _ = os.Getwd()
+
+		isChanged = diff.IsChanged
 	}
 
-	if configSearchPath == "" {
-		log.Println("failed to determine relevant directory for config file search - won't search for custom config or rules")
-	} else {
-		regalDir, err = config.FindRegalDirectory(configSearchPath)
-		if err == nil {
-			customRulesPath := filepath.Join(regalDir.Name(), rio.PathSeparator, "rules")
-			if _, err = os.Stat(customRulesPath); err == nil {
-				customRulesDir = customRulesPath
-			}
+	var result report.Report
+
+	for i, target := range targets {
+		regal, err := newTargetLinter(target, params, m)
+		if err != nil {
+			return report.Report{}, err
+		}
+
+		if isChanged != nil {
+			regal = regal.WithLintedFiles(isChanged)
+		}
+
+		targetResult, err := regal.Lint(ctx)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("error(s) encountered while linting: %w", err)
+		}
+
+		if len(targets) > 1 || params.groupBy == reporter.GroupByTarget {
+			targetResult.AssignTargets(func(file string) string {
+				return targetOf(file, target.paths)
+			})
+		}
+
+		if i == 0 {
+			result = targetResult
+		} else {
+			result.Merge(targetResult)
 		}
 	}
 
-	if params.metrics {
-		m.Timer(regalmetrics.RegalConfigSearch).Stop()
+	// metrics are collected for the timings of the debug bundle, but only reported when asked for
+	if params.recorder != nil {
+		params.recorder.AddMetrics(result.Metrics)
+
+		if !params.metrics {
+			result.Metrics = nil
+		}
 	}
 
+	defaultGroupBy(targets, params)
+
+	if params.genBaseline != "" {
+		if err := writeBaseline(params.genBaseline, result); err != nil {
+			return report.Report{}, err
+		}
+	}
+
+	return publishReport(ctx, args, params, result, outputWriter)
+}
+
+// changedBase returns the git ref to find the files changed since with --changed, which defaults to the ref of --diff,
+// for violations to be classified against the same changes, or else to HEAD, for the changes not yet committed.
+func changedBase(params *lintCommandParams) string {
+	return cmp.Or(params.diffBase, params.diff, "HEAD")
+}
+
+// defaultGroupBy has targets with different configurations reported in sections of their own, unless grouped
+// otherwise.
+func defaultGroupBy(targets []lintTarget, params *lintCommandParams) {
+	if len(targets) > 1 && params.groupBy == "" &&
+		slices.Contains([]string{formatSnippet, formatPretty, formatCompact, formatMarkdown}, params.format) {
+		params.groupBy = reporter.GroupByTarget
+	}
+}
+
+// publishReport annotates the violations of result as asked for by params, like with the changes since a git ref,
+// and publishes the report with the reporter of the format. The annotated report is returned.
+func publishReport(
+	ctx context.Context,
+	args []string,
+	params *lintCommandParams,
+	result report.Report,
+	outputWriter io.Writer,
+) (report.Report, error) {
+	if params.diff != "" {
+		diff, err := git.DiffAgainst(ctx, repositoryDir(args[0]), params.diff)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to diff against %s: %w", params.diff, err)
+		}
+
+		result.ClassifyChanges(diff.IsNew)
+	}
+
+	if params.blame && len(result.Violations) > 0 {
+		files := make([]string, 0, len(result.Violations))
+		for _, violation := range result.Violations {
+			files = append(files, violation.Location.File)
+		}
+
+		blame, err := git.BlameFiles(ctx, repositoryDir(args[0]), files)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to blame files with violations: %w", err)
+		}
+
+		result.AttributeBlame(func(file string, row int) (report.Blame, bool) {
+			commit, ok := blame.Of(file, row)
+
+			return report.Blame{
+				Commit:      commit.Hash,
+				Author:      commit.Author,
+				AuthorEmail: commit.AuthorEmail,
+				AuthorTime:  commit.AuthorTime,
+			}, ok
+		})
+	}
+
+	if params.groupBy == reporter.GroupByOwner {
+		owners, err := codeowners.Find(repositoryDir(args[0]))
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to find owners of files: %w", err)
+		}
+
+		result.AssignOwners(owners.Owners)
+	}
+
+	rep, err := getReporter(params, outputWriter)
+	if err != nil {
+		return report.Report{}, fmt.Errorf("failed to get reporter: %w", err)
+	}
+
+	reportCtx, reportSpan := tracing.Start(ctx, "regal.report", attribute.String("regal.format", params.format))
+
+	err = rep.Publish(reportCtx, result)
+
+	tracing.End(reportSpan, err)
+
+	return result, err //nolint:wrapcheck
+}
+
+// lintTarget is a group of paths linted together, with the configuration and custom rules of the .regal directory
+// nearest to them, if any. The input is set when linting from stdin, in place of reading the paths.
+type lintTarget struct {
+	paths    []string
+	regalDir *os.File
+	input    *rules.Input
+}
+
+// resolveLintTargets groups the paths to lint by the .regal directory nearest to each, for the paths of each project
+// in a monorepo to be linted with the configuration of the project. Paths without a .regal directory of their own use
+// that of the current directory, as do all paths when a configuration file is provided.
+func resolveLintTargets(args []string, params *lintCommandParams) []lintTarget {
+	cwd, _ := os.Getwd()
+
+	findRegalDir := func(path string) *os.File {
+		if path == "" {
+			log.Println("failed to determine relevant directory for config file search - " +
+				"won't search for custom config or rules")
+
+			return nil
+		}
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cwd, path)
+		}
+
+		regalDir, err := config.FindRegalDirectory(path)
+		if err != nil {
+			return nil
+		}
+
+		return regalDir
+	}
+
+	if len(args) < 2 {
+		searchPath := cwd
+		if len(args) == 1 {
+			searchPath = args[0]
+		}
+
+		return []lintTarget{{paths: args, regalDir: findRegalDir(searchPath)}}
+	}
+
+	if params.configFile != "" {
+		return []lintTarget{{paths: args, regalDir: findRegalDir(cwd)}}
+	}
+
+	var (
+		targets    []lintTarget
+		defaultDir *os.File
+		searched   bool
+	)
+
+	for _, path := range args {
+		regalDir := findRegalDir(path)
+		if regalDir == nil {
+			if !searched {
+				defaultDir, searched = findRegalDir(cwd), true
+			}
+
+			regalDir = defaultDir
+		}
+
+		i := slices.IndexFunc(targets, func(target lintTarget) bool {
+			return target.regalDir == regalDir || target.regalDir != nil && regalDir != nil &&
+				target.regalDir.Name() == regalDir.Name()
+		})
+		if i == -1 {
+			targets = append(targets, lintTarget{regalDir: regalDir})
+			i = len(targets) - 1
+		}
+
+		targets[i].paths = append(targets[i].paths, path)
+	}
+
+	return targets
+}
+
+// newTargetLinter creates a linter for the paths of the target, with the custom rules and configuration of its .regal
+// directory, unless provided by flags.
+func newTargetLinter(target lintTarget, params *lintCommandParams, m metrics.Metrics) (linter.Linter, error) {
 	regal := linter.NewLinter().
 		WithDisableAll(params.disableAll).
 		WithDisabledCategories(params.disableCategory.v...).
@@ -250,14 +666,24 @@ func lint(args []string, params *lintCommandParams) (report.Report, error) {
 		WithEnabledCategories(params.enableCategory.v...).
 		WithEnabledRules(params.enable.v...).
 		WithDebugMode(params.debug).
-		WithInputPaths(args)
+		WithBatchSize(params.batchSize).
+		WithDebugBundle(params.recorder)
+
+	if target.input != nil {
+		regal = regal.WithInputModules(target.input)
+	} else {
+		regal = regal.WithInputPaths(target.paths)
+	}
 
 	if params.enablePrint {
 		regal = regal.WithPrintHook(topdown.NewPrintHook(os.Stderr))
 	}
 
-	if customRulesDir != "" {
-		regal = regal.WithCustomRules([]string{customRulesDir})
+	if target.regalDir != nil {
+		customRulesPath := filepath.Join(target.regalDir.Name(), rio.PathSeparator, "rules")
+		if _, err := os.Stat(customRulesPath); err == nil {
+			regal = regal.WithCustomRules([]string{customRulesPath})
+		}
 	}
 
 	if params.rules.isSet {
@@ -268,7 +694,31 @@ func lint(args []string, params *lintCommandParams) (report.Report, error) {
 		regal = regal.WithIgnore(params.ignoreFiles.v)
 	}
 
-	if params.metrics {
+	if params.timeoutSet {
+		regal = regal.WithTimeout(params.timeout)
+	}
+
+	if params.coverage != "" {
+		coverage, err := readCoverageReport(params.coverage)
+		if err != nil {
+			return linter.Linter{}, err
+		}
+
+		regal = regal.WithCoverage(coverage)
+	}
+
+	if params.baseline != "" {
+		baseline, err := readBaseline(params.baseline)
+		if err != nil {
+			return linter.Linter{}, err
+		}
+
+		regal = regal.WithBaseline(baseline)
+	}
+
+	collectMetrics := params.metrics || params.recorder != nil
+
+	if collectMetrics {
 		regal = regal.WithMetrics(m)
 		m.Timer(regalmetrics.RegalConfigParse).Start()
 	}
@@ -277,9 +727,17 @@ func lint(args []string, params *lintCommandParams) (report.Report, error) {
 		regal = regal.WithProfiling(true)
 	}
 
+	if params.showSuppressed {
+		regal = regal.WithShowSuppressed(true)
+	}
+
+	if params.format == formatSnippet {
+		regal = regal.WithSourceSnippets(snippetContextLines)
+	}
+
 	var userConfig config.Config
 
-	userConfigFile, err := readUserConfig(params, regalDir)
+	userConfigFile, err := readUserConfig(params, target.regalDir)
 
 	switch {
 	case err == nil:
@@ -290,52 +748,255 @@ func lint(args []string, params *lintCommandParams) (report.Report, error) {
 		}
 
 		if err := yaml.NewDecoder(userConfigFile).Decode(&userConfig); err != nil {
-			if regalDir != nil {
-				return report.Report{}, fmt.Errorf("failed to decode user config from %s: %w", regalDir.Name(), err)
+			if target.regalDir != nil {
+				return linter.Linter{}, fmt.Errorf("failed to decode user config from %s: %w",
+					target.regalDir.Name(), err)
 			}
 
-			return report.Report{}, fmt.Errorf("failed to decode user config: %w", err)
+			return linter.Linter{}, fmt.Errorf("failed to decode user config: %w", err)
 		}
 
 		regal = regal.WithUserConfig(userConfig)
 	case params.configFile != "":
-		return report.Report{}, fmt.Errorf("user-provided config file not found: %w", err)
+		return linter.Linter{}, fmt.Errorf("user-provided config file not found: %w", err)
 	case params.debug:
 		log.Println("no user-provided config file found, will use the default config")
 	}
 
-	if params.metrics {
+	regal = regal.WithTraversal(params.traversal(userConfig.Traversal))
+
+	if collectMetrics {
 		m.Timer(regalmetrics.RegalConfigParse).Stop()
 	}
 
-	result, err := regal.Lint(ctx)
+	return regal, nil
+}
+
+// traversalFlags are the flags deciding how the directories of the paths provided are walked.
+var traversalFlags = []string{"follow-symlinks", "skip-hidden-dirs", "max-depth", "no-gitignore"}
+
+// traversal returns the traversal options of the configuration, overridden by the traversal flags provided.
+func (p *lintCommandParams) traversal(traversal config.Traversal) config.Traversal {
+	if p.traversalSet["follow-symlinks"] {
+		traversal.FollowSymlinks = p.followSymlinks
+	}
+
+	if p.traversalSet["skip-hidden-dirs"] {
+		traversal.SkipHiddenDirectories = p.skipHiddenDirs
+	}
+
+	if p.traversalSet["max-depth"] {
+		traversal.MaxDepth = p.maxDepth
+	}
+
+	if p.traversalSet["no-gitignore"] {
+		traversal.NoGitignore = p.noGitignore
+	}
+
+	return traversal
+}
+
+// targetOf returns the path, of those linted together, containing the file, or the first path for violations not
+// in any one file.
+func targetOf(file string, paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	target := paths[0]
+	longest := -1
+
+	for _, path := range paths {
+		if clean := filepath.Clean(path); pathContains(clean, file) && len(clean) > longest {
+			target, longest = path, len(clean)
+		}
+	}
+
+	return target
+}
+
+// pathContains returns true if file is the file at path, or a file in the directory at path.
+func pathContains(path, file string) bool {
+	clean := filepath.Clean(path)
+
+	return file == clean || strings.HasPrefix(file, clean+string(filepath.Separator)) || clean == "."
+}
+
+// lintExitCode returns the exit code for the violations of rep, which is 3 if any errors were found, or 2 if any
+// warnings were found and the fail level is warning. Notices are informational, and never affect the exit code.
+func lintExitCode(rep report.Report, params *lintCommandParams) int {
+	// violations written to a baseline are accepted, for later runs to fail only on new ones
+	if params.exitZero || params.failLevel == "ignore" || params.genBaseline != "" {
+		return 0
+	}
+
+	errorsFound := 0
+	warningsFound := 0
+
+	for _, violation := range rep.Violations {
+		if params.failNewOnly && violation.Change != report.ChangeNew {
+			continue
+		}
+
+		// violations of rules within their budget are allowed, until the budget is exceeded
+		if violation.WithinBudget {
+			continue
+		}
+
+		if violation.Level == "error" {
+			errorsFound++
+		} else if violation.Level == "warning" {
+			warningsFound++
+		}
+	}
+
+	switch {
+	case errorsFound > 0:
+		return 3
+	case warningsFound > 0 && params.failLevel == "warning":
+		return 2
+	default:
+		return 0
+	}
+}
+
+func getTemplateReporter(params *lintCommandParams, outputWriter io.Writer) (reporter.Reporter, error) {
+	text := params.template
+
+	if params.templateFile != "" {
+		bs, err := os.ReadFile(params.templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file: %w", err)
+		}
+
+		text = string(bs)
+	}
+
+	rep, err := reporter.NewTemplateReporter(outputWriter, text)
 	if err != nil {
-		return report.Report{}, fmt.Errorf("error(s) encountered while linting: %w", err)
+		return nil, fmt.Errorf("failed to create template reporter: %w", err)
 	}
 
-	rep, err := getReporter(params.format, outputWriter)
+	return rep, nil
+}
+
+func getWebhookReporter(url string, headerFlags []string, retries int) (reporter.Reporter, error) {
+	if url == "" {
+		return nil, errors.New("no URL provided after webhook:")
+	}
+
+	headers := make(map[string]string, len(headerFlags))
+
+	for _, header := range headerFlags {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid webhook header %q, expected name: value", header)
+		}
+
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	rep, err := reporter.NewWebhookReporter(url, headers)
 	if err != nil {
-		return report.Report{}, fmt.Errorf("failed to get reporter: %w", err)
+		return nil, fmt.Errorf("failed to create webhook reporter: %w", err)
+	}
+
+	return rep.WithRetries(retries), nil
+}
+
+func readCoverageReport(path string) (*cover.Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage report: %w", err)
+	}
+
+	defer rio.CloseFileIgnore(file)
+
+	var coverage cover.Report
+	if err = json.NewDecoder(file).Decode(&coverage); err != nil {
+		return nil, fmt.Errorf("failed to decode coverage report %s: %w", path, err)
+	}
+
+	return &coverage, nil
+}
+
+func readBaseline(path string) (*report.Baseline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline: %w", err)
 	}
 
-	return result, rep.Publish(ctx, result) //nolint:wrapcheck
+	defer rio.CloseFileIgnore(file)
+
+	var baseline report.Baseline
+	if err = json.NewDecoder(file).Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("failed to decode baseline %s: %w", path, err)
+	}
+
+	return &baseline, nil
 }
 
-func getReporter(format string, outputWriter io.Writer) (reporter.Reporter, error) {
+func writeBaseline(path string, rep report.Report) error {
+	bs, err := json.MarshalIndent(report.NewBaseline(rep), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+
+	if err = os.WriteFile(path, append(bs, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write baseline %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func getReporter(params *lintCommandParams, outputWriter io.Writer) (reporter.Reporter, error) {
+	format, groupBy := params.format, params.groupBy
+
 	switch format {
 	case formatPretty:
-		return reporter.NewPrettyReporter(outputWriter), nil
+		return reporter.NewPrettyReporter(outputWriter).WithGroupBy(groupBy), nil
+	case formatSnippet:
+		return reporter.NewSnippetReporter(outputWriter).WithGroupBy(groupBy), nil
 	case formatCompact:
-		return reporter.NewCompactReporter(outputWriter), nil
+		return reporter.NewCompactReporter(outputWriter).WithGroupBy(groupBy), nil
 	case formatJSON:
-		return reporter.NewJSONReporter(outputWriter), nil
+		return reporter.NewJSONReporter(outputWriter).WithGroupBy(groupBy), nil
 	case formatGitHub:
 		return reporter.NewGitHubReporter(outputWriter), nil
 	case formatFestive:
 		return reporter.NewFestiveReporter(outputWriter), nil
 	case formatSarif:
 		return reporter.NewSarifReporter(outputWriter), nil
+	case formatGitLab:
+		return reporter.NewGitLabReporter(outputWriter), nil
+	case formatMarkdown:
+		return reporter.NewMarkdownReporter(outputWriter).WithGroupBy(groupBy), nil
+	case formatTAP:
+		return reporter.NewTAPReporter(outputWriter), nil
+	case formatJUnit:
+		return reporter.NewJUnitReporter(outputWriter), nil
+	case formatCheckstyle:
+		return reporter.NewCheckstyleReporter(outputWriter), nil
+	case formatRdJSON:
+		return reporter.NewRdJSONReporter(outputWriter), nil
+	case formatRdJSONL:
+		return reporter.NewRdJSONLReporter(outputWriter), nil
+	case formatTemplate:
+		return getTemplateReporter(params, outputWriter)
 	default:
+		if command, ok := strings.CutPrefix(format, formatExecPrefix); ok {
+			args := strings.Fields(command)
+			if len(args) == 0 {
+				return nil, errors.New("no reporter command provided after exec:")
+			}
+
+			return reporter.NewExecReporter(outputWriter, args), nil
+		}
+
+		if url, ok := strings.CutPrefix(format, formatWebhookPrefix); ok {
+			return getWebhookReporter(url, params.webhookHeaders.v, params.webhookRetries)
+		}
+
 		return nil, fmt.Errorf("unknown format %s", format)
 	}
 }
@@ -357,3 +1018,13 @@ func getWriterForOutputFile(filename string) (io.Writer, error) {
 
 	return f, nil
 }
+
+// repositoryDir returns the directory from which to find the git repository of the path linted. Paths which don't
+// exist, like the name of a policy linted from stdin which was never saved, are considered files.
+func repositoryDir(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path
+	}
+
+	return filepath.Dir(path)
+}
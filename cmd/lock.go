@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/styrainc/regal/pkg/linter"
+)
+
+type lockCommandParams struct {
+	configFile string
+	lockFile   string
+	check      bool
+	rules      repeatedStringFlag
+}
+
+func (p *lockCommandParams) getConfigFile() string {
+	return p.configFile
+}
+
+func init() {
+	params := &lockCommandParams{}
+
+	lockCommand := &cobra.Command{
+		Use:   "lock [path]",
+		Short: "Lock the rule set used for linting",
+		Long: `Write a lockfile of the rule set used for linting the provided path, or the current directory, with the
+rules enabled by the configuration found for it, their levels, the version of Regal, and the digests of the rules
+and the bundles they're from.
+
+With --check, the lockfile is compared to the rule set currently in use instead, and any drift from it, like rules
+changed by an upgrade of Regal, or enabled by a change of configuration, is printed, with an exit code of 1. This
+keeps the rules applied in CI from changing without the lockfile being updated along with the change.`,
+
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("at most one path may be provided")
+			}
+
+			return nil
+		},
+
+		RunE: wrapProfiling(func(args []string) error {
+			return lock(args, params, os.Stdout)
+		}),
+	}
+
+	lockCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file")
+	lockCommand.Flags().StringVar(&params.lockFile, "lockfile", "regal.lock",
+		"set path of lockfile")
+	lockCommand.Flags().BoolVar(&params.check, "check", false,
+		"check the rule set in use against the lockfile, rather than writing it, failing on any drift")
+	lockCommand.Flags().VarP(&params.rules, "rules", "r",
+		"set custom rules file(s). This flag can be repeated.")
+
+	addPprofFlag(lockCommand.Flags())
+
+	RootCommand.AddCommand(lockCommand)
+}
+
+func lock(args []string, params *lockCommandParams, w io.Writer) error {
+	regal, _, err := configuredLinter(args, params, params.rules)
+	if err != nil {
+		return err
+	}
+
+	ruleSet, err := regal.RuleSet()
+	if err != nil {
+		return fmt.Errorf("failed to determine rule set: %w", err)
+	}
+
+	if !params.check {
+		bs, err := json.MarshalIndent(ruleSet, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal rule set: %w", err)
+		}
+
+		if err := os.WriteFile(params.lockFile, append(bs, '\n'), 0o600); err != nil {
+			return fmt.Errorf("failed to write lockfile: %w", err)
+		}
+
+		fmt.Fprintf(w, "%d rules locked in %s\n", len(ruleSet.Rules), params.lockFile)
+
+		return nil
+	}
+
+	bs, err := os.ReadFile(params.lockFile)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var locked linter.RuleSet
+	if err := json.Unmarshal(bs, &locked); err != nil {
+		return fmt.Errorf("failed to unmarshal lockfile %s: %w", params.lockFile, err)
+	}
+
+	if locked.Version != linter.RuleSetVersion {
+		return fmt.Errorf("lockfile %s has version %d, expected %d, run regal lock to update it",
+			params.lockFile, locked.Version, linter.RuleSetVersion)
+	}
+
+	drift := ruleSet.Drift(locked)
+	if len(drift) == 0 {
+		fmt.Fprintf(w, "Rule set matches %s\n", params.lockFile)
+
+		return nil
+	}
+
+	fmt.Fprintf(w, "Rule set drifted from %s:\n", params.lockFile)
+
+	for _, line := range drift {
+		fmt.Fprintf(w, "- %s\n", line)
+	}
+
+	log.SetOutput(os.Stderr)
+	log.Println("run regal lock to update the lockfile, if the drift is intended")
+
+	return exit(1)
+}
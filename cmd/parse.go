@@ -9,8 +9,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/open-policy-agent/opa/ast"
-
 	rp "github.com/styrainc/regal/internal/parse"
 )
 
@@ -53,7 +51,7 @@ func parse(args []string) error {
 
 	content := string(bs)
 
-	module, err := ast.ParseModuleWithOpts(filename, content, rp.ParserOptions())
+	module, err := rp.Module(filename, content)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"log"
+	"os"
 
 	"github.com/pkg/profile"
 	"github.com/spf13/cobra"
@@ -12,6 +14,16 @@ func wrapProfiling(f func([]string) error) func(*cobra.Command, []string) error
 		cmd.SilenceUsage = true
 		cmd.SilenceErrors = true
 
+		stopTracing, err := startTracing(cmd)
+		if err != nil {
+			log.SetOutput(os.Stderr)
+			log.Println(err)
+
+			return exit(1)
+		}
+
+		defer stopTracing()
+
 		if !cmd.Flags().Changed("pprof") {
 			return f(args)
 		}
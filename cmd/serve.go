@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"github.com/styrainc/regal/internal/metrics"
+	"github.com/styrainc/regal/internal/server"
+	"github.com/styrainc/regal/pkg/config"
+)
+
+type serveCommandParams struct {
+	addr           string
+	grpcAddr       string
+	metrics        bool
+	configFile     string
+	maxRequestSize int64
+	timeout        time.Duration
+}
+
+func init() {
+	params := serveCommandParams{}
+
+	serveCommand := &cobra.Command{
+		Use:   "serve",
+		Short: "Run Regal as a server for linting policies over HTTP and gRPC",
+		Long: `Start an HTTP server linting Rego policies sent to it, for integrations that can't run the regal command.
+
+Policies are sent to the /v1/lint endpoint, either as the body of a POST request, for a single file, or as a
+multipart/form-data request, with a part for each file in the workspace, and an optional config part. The report
+is returned in JSON format, or SARIF with the format=sarif query parameter.
+
+The gRPC API (see pkg/api/regalv1/regal.proto) is served on a separate address, for services linting policies at
+high throughput.
+
+Examples:
+
+  curl --data-binary @policy.rego 'http://localhost:8282/v1/lint?filename=policy.rego'
+  curl -F policy/authz.rego=@authz.rego -F config=@.regal/config.yaml 'http://localhost:8282/v1/lint?format=sarif'`,
+
+		RunE: wrapProfiling(func([]string) error {
+			return serve(params)
+		}),
+	}
+
+	serveCommand.Flags().StringVar(&params.addr, "addr", "localhost:8282", "address to listen on")
+	serveCommand.Flags().StringVar(&params.grpcAddr, "grpc-addr", "localhost:8283",
+		"address to serve the gRPC API on, or empty to disable it")
+	serveCommand.Flags().BoolVar(&params.metrics, "metrics", false,
+		"expose metrics in the Prometheus format on the /metrics endpoint")
+	serveCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file used for requests not providing their own")
+	serveCommand.Flags().Int64Var(&params.maxRequestSize, "max-request-size", server.DefaultMaxRequestSize,
+		"maximum size of request bodies, in bytes")
+	serveCommand.Flags().DurationVar(&params.timeout, "timeout", server.DefaultTimeout,
+		"maximum time spent linting the policies of a request")
+
+	addPprofFlag(serveCommand.Flags())
+	addTracingFlags(serveCommand.Flags())
+
+	RootCommand.AddCommand(serveCommand)
+}
+
+func serve(params serveCommandParams) error {
+	opts := server.Options{
+		MaxRequestSize: params.maxRequestSize,
+		Timeout:        params.timeout,
+	}
+
+	if params.metrics {
+		opts.Metrics = metrics.NewPrometheus()
+	}
+
+	if params.configFile != "" {
+		conf, err := config.FromFile(params.configFile)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		opts.Config = &conf
+	}
+
+	regalServer := server.New(opts)
+
+	srv := &http.Server{
+		Addr:              params.addr,
+		Handler:           regalServer.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 2)
+
+	go func() {
+		log.Printf("listening on %s", params.addr)
+
+		errCh <- srv.ListenAndServe()
+	}()
+
+	if params.grpcAddr != "" {
+		listener, err := net.Listen("tcp", params.grpcAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", params.grpcAddr, err)
+		}
+
+		grpcServer := grpc.NewServer(regalServer.GRPCServerOptions()...)
+		regalServer.RegisterGRPC(grpcServer)
+
+		defer grpcServer.GracefulStop()
+
+		go func() {
+			log.Printf("serving gRPC API on %s", params.grpcAddr)
+
+			errCh <- grpcServer.Serve(listener)
+		}()
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve: %w", err)
+		}
+	case sig := <-sigChan:
+		fmt.Fprintln(os.Stderr, "signal: ", sig.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), params.timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+
+	return nil
+}
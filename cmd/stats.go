@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/styrainc/regal/internal/stats"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+type statsCommandParams struct {
+	configFile string
+	format     string
+	outputFile string
+	rules      repeatedStringFlag
+	timeout    time.Duration
+}
+
+func (p *statsCommandParams) getConfigFile() string {
+	return p.configFile
+}
+
+func (p *statsCommandParams) getTimeout() time.Duration {
+	return p.timeout
+}
+
+func init() {
+	params := &statsCommandParams{}
+
+	statsCommand := &cobra.Command{
+		Use:   "stats <path> [path [...]]",
+		Short: "Report statistics of Rego source files",
+		Long: `Report statistics of the provided paths, like the number of packages, rules and tests, the average complexity
+of rules, the built-in functions called, and the number of violations in each category.
+
+The JSON output is suitable for tracking the health of a policy library over time, like in a dashboard.`,
+
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("at least one file or directory must be provided for statistics")
+			}
+
+			if params.format != formatPretty && params.format != formatJSON {
+				return fmt.Errorf("unknown format %s, expected pretty or json", params.format)
+			}
+
+			return nil
+		},
+
+		RunE: wrapProfiling(func(args []string) error {
+			return workspaceStats(args, params)
+		}),
+	}
+
+	statsCommand.Flags().StringVarP(&params.configFile, "config-file", "c", "",
+		"set path of configuration file")
+	statsCommand.Flags().StringVarP(&params.format, "format", "f", formatPretty,
+		"set output format (pretty, json)")
+	statsCommand.Flags().StringVarP(&params.outputFile, "output-file", "o", "",
+		"set file to use for statistics output, defaults to stdout")
+	statsCommand.Flags().VarP(&params.rules, "rules", "r",
+		"set custom rules file(s). This flag can be repeated.")
+	statsCommand.Flags().DurationVar(&params.timeout, "timeout", 0,
+		"set timeout for linting (default unlimited)")
+
+	addPprofFlag(statsCommand.Flags())
+
+	RootCommand.AddCommand(statsCommand)
+}
+
+func workspaceStats(args []string, params *statsCommandParams) error {
+	ctx, cancel := getLinterContext(params)
+	defer cancel()
+
+	regal, userConfig, err := configuredLinter(args, params, params.rules)
+	if err != nil {
+		return err
+	}
+
+	var ignore []string
+	if userConfig != nil {
+		ignore = userConfig.Ignore.Files
+	}
+
+	paths, err := config.FilterIgnoredPaths(args, ignore, true, "")
+	if err != nil {
+		return fmt.Errorf("failed to filter paths: %w", err)
+	}
+
+	input, err := rules.InputFromPaths(paths)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	// the files are parsed once, for both the statistics and the linter
+	rep, err := regal.WithInputPaths(nil).WithInputModules(&input).Lint(ctx)
+	if err != nil {
+		return fmt.Errorf("error(s) encountered while linting: %w", err)
+	}
+
+	var outputWriter io.Writer = os.Stdout
+
+	if params.outputFile != "" {
+		outputWriter, err = getWriterForOutputFile(params.outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to open output file before use %w", err)
+		}
+	}
+
+	result := stats.Collect(input.Modules, rep)
+
+	if params.format == formatJSON {
+		encoder := json.NewEncoder(outputWriter)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode statistics: %w", err)
+		}
+
+		return nil
+	}
+
+	return writeStats(outputWriter, result)
+}
+
+// writeStats writes the statistics in a human-readable format, with built-in functions and categories sorted by
+// number of calls and violations respectively.
+func writeStats(w io.Writer, result stats.Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Files:\t%d\n", result.Files)
+	fmt.Fprintf(tw, "Packages:\t%d\n", result.Packages)
+	fmt.Fprintf(tw, "Rules:\t%d\n", result.Rules)
+	fmt.Fprintf(tw, "Tests:\t%d\n", result.Tests)
+	fmt.Fprintf(tw, "Average complexity:\t%.2f\n", result.AverageComplexity)
+
+	for _, table := range []struct {
+		header string
+		counts map[string]int
+	}{
+		{"Built-in function\tCalls", result.Builtins},
+		{"Category\tViolations", result.Violations},
+	} {
+		if len(table.counts) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(tw, "\n%s\n", table.header)
+
+		for _, name := range sortedByCount(table.counts) {
+			fmt.Fprintf(tw, "%s\t%d\n", name, table.counts[name])
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write statistics: %w", err)
+	}
+
+	return nil
+}
+
+func sortedByCount(counts map[string]int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	return names
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/styrainc/regal/internal/tracing"
+)
+
+// tracingShutdownTimeout limits the time spent exporting the remaining spans before exiting.
+const tracingShutdownTimeout = 5 * time.Second
+
+func addTracingFlags(fs *pflag.FlagSet) {
+	fs.String("otlp-endpoint", "",
+		"enable tracing, exporting spans using OTLP to this endpoint (e.g. http://localhost:4318)")
+	fs.String("otlp-protocol", tracing.ProtocolHTTP,
+		"set protocol used for exporting spans (http/protobuf, grpc)")
+}
+
+// startTracing sets up tracing if an OTLP endpoint was provided to the command, and returns a function for exporting
+// the remaining spans when done.
+func startTracing(cmd *cobra.Command) (func(), error) {
+	endpoint, _ := cmd.Flags().GetString("otlp-endpoint")
+	if endpoint == "" {
+		return func() {}, nil
+	}
+
+	protocol, _ := cmd.Flags().GetString("otlp-protocol")
+
+	shutdown, err := tracing.Setup(context.Background(), endpoint, protocol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tracing: %w", err)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdown(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to export spans:", err)
+		}
+	}, nil
+}
@@ -4,9 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/styrainc/regal/internal/debugbundle"
+	rio "github.com/styrainc/regal/internal/io"
 	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/linter"
 )
 
 // configFileParams supports extracting the config file path from various command
@@ -52,3 +58,61 @@ func getLinterContext(params timeoutParams) (context.Context, func()) {
 
 	return ctx, cancel
 }
+
+// configuredLinter returns a linter configured like the lint command would, for the provided paths, along with the
+// user configuration, if any was found.
+func configuredLinter(
+	args []string,
+	params configFileParams,
+	customRules repeatedStringFlag,
+) (linter.Linter, *config.Config, error) {
+	regal := linter.NewLinter().WithInputPaths(args)
+
+	searchPath, _ := os.Getwd()
+	if len(args) == 1 {
+		searchPath, _ = filepath.Abs(args[0])
+	}
+
+	regalDir, err := config.FindRegalDirectory(searchPath)
+	if err == nil {
+		customRulesPath := filepath.Join(regalDir.Name(), rio.PathSeparator, "rules")
+		if _, err = os.Stat(customRulesPath); err == nil {
+			regal = regal.WithCustomRules([]string{customRulesPath})
+		}
+	}
+
+	if customRules.isSet {
+		regal = regal.WithCustomRules(customRules.v)
+	}
+
+	userConfigFile, err := readUserConfig(params, regalDir)
+
+	switch {
+	case err == nil:
+		defer rio.CloseFileIgnore(userConfigFile)
+
+		var userConfig config.Config
+
+		if err := yaml.NewDecoder(userConfigFile).Decode(&userConfig); err != nil {
+			return linter.Linter{}, nil, fmt.Errorf("failed to decode user config: %w", err)
+		}
+
+		return regal.WithUserConfig(userConfig), &userConfig, nil
+	case params.getConfigFile() != "":
+		return linter.Linter{}, nil, fmt.Errorf("user-provided config file not found: %w", err)
+	}
+
+	return regal, nil, nil
+}
+
+// writeDebugBundle writes the debug bundle recorded to dir, reporting where to stderr, as stdout may hold a report.
+func writeDebugBundle(recorder *debugbundle.Recorder, dir string) {
+	path, err := recorder.Write(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write debug bundle:", err)
+
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "debug bundle written to", path)
+}
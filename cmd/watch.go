@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/metrics"
+
+	"github.com/styrainc/regal/internal/util"
+	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/reporter"
+)
+
+// watchDebounce is the time waited for more changes after a file is changed, before linting, as editors and tools
+// like git commonly change several files, or the same file several times, at once.
+const watchDebounce = 100 * time.Millisecond
+
+// clearScreen moves the cursor of the terminal to the top left corner, and clears the screen.
+const clearScreen = "\033[H\033[2J"
+
+// lintWatcher lints the paths provided, and lints them again whenever their Rego files are changed. The results of
+// each file are kept, so that only the files changed are linted again, along with the aggregate rules, which use the
+// aggregates collected from all files.
+type lintWatcher struct {
+	args    []string
+	params  *lintCommandParams
+	targets []*watchedTarget
+}
+
+// watchedTarget is a lint target, along with its linter and the results of linting its files.
+type watchedTarget struct {
+	lintTarget
+	linter linter.Linter
+	files  map[string]*fileResult
+	// aggregateViolations are the violations of the aggregate rules, linted again whenever a file is changed
+	aggregateViolations []report.Violation
+	notices             []report.Notice
+	// budgets are the max violations of the rules with a budget, for applying them to the violations of all files
+	budgets map[string]int
+	// complete is set once all files have been linted, after which only files changed are linted again
+	complete bool
+	// failed are the files failing to lint the last time they were linted, along with the error encountered
+	failed []string
+	err    error
+}
+
+// fileResult is the result of linting a single file.
+type fileResult struct {
+	violations []report.Violation
+	suppressed []report.Violation
+	aggregates map[string][]report.Aggregate
+}
+
+// watchLint lints the paths of args, and lints the files changed again whenever files are changed, publishing the
+// report after each time, until interrupted.
+func watchLint(args []string, params *lintCommandParams) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if params.noColor {
+		color.NoColor = true
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+
+	defer fsWatcher.Close()
+
+	for _, path := range args {
+		if err := addWatches(fsWatcher, path); err != nil {
+			return err
+		}
+	}
+
+	w, err := newLintWatcher(args, params)
+	if err != nil {
+		return err
+	}
+
+	w.publish(ctx, w.lintAll(ctx))
+
+	changed := make(map[string]struct{})
+
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+
+			for _, file := range w.changedFiles(fsWatcher, event) {
+				changed[file] = struct{}{}
+				debounce = time.After(watchDebounce)
+			}
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			fmt.Fprintf(os.Stderr, "watcher error: %v\n", err)
+		case <-debounce:
+			files := util.Keys(changed)
+
+			slices.Sort(files)
+
+			clear(changed)
+
+			debounce = nil
+
+			w.publish(ctx, w.lintFiles(ctx, files))
+		}
+	}
+}
+
+func newLintWatcher(args []string, params *lintCommandParams) (*lintWatcher, error) {
+	targets := resolveLintTargets(args, params)
+
+	defaultGroupBy(targets, params)
+
+	w := &lintWatcher{args: args, params: params, targets: make([]*watchedTarget, 0, len(targets))}
+
+	for _, target := range targets {
+		regal, err := newTargetLinter(target, params, metrics.New())
+		if err != nil {
+			return nil, err
+		}
+
+		w.targets = append(w.targets, &watchedTarget{
+			lintTarget: target,
+			linter:     regal.WithExportAggregates(true),
+			files:      make(map[string]*fileResult),
+			budgets:    make(map[string]int),
+		})
+	}
+
+	return w, nil
+}
+
+// addWatches watches path, along with the directories below it, or the directory of path when path is a file, as
+// files are commonly replaced, rather than written to, when saved.
+func addWatches(fsWatcher *fsnotify.Watcher, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		if err := fsWatcher.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+
+		return nil
+	}
+
+	err = filepath.WalkDir(path, func(walked string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		// like when linting, the directories of version control and IDEs are skipped
+		if walked != path && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return fsWatcher.Add(walked)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// changedFiles returns the Rego files changed by event, which are those in any directory created, as directories
+// moved into place, or checked out, are already populated when first seen.
+func (w *lintWatcher) changedFiles(fsWatcher *fsnotify.Watcher, event fsnotify.Event) []string {
+	name := filepath.Clean(event.Name)
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(name); err == nil && info.IsDir() {
+			if err := addWatches(fsWatcher, name); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+
+			var files []string
+
+			_ = filepath.WalkDir(name, func(walked string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && w.watches(walked) {
+					files = append(files, walked)
+				}
+
+				return nil
+			})
+
+			return files
+		}
+	}
+
+	if w.watches(name) {
+		return []string{name}
+	}
+
+	return nil
+}
+
+// watches returns true if file is a Rego file of any of the paths linted.
+func (w *lintWatcher) watches(file string) bool {
+	return strings.HasSuffix(file, bundle.RegoExt) && w.targetOf(file) != nil
+}
+
+func (w *lintWatcher) targetOf(file string) *watchedTarget {
+	for _, target := range w.targets {
+		for _, path := range target.paths {
+			if pathContains(path, file) {
+				return target
+			}
+		}
+	}
+
+	return nil
+}
+
+// lintAll lints all files of the paths provided.
+func (w *lintWatcher) lintAll(ctx context.Context) error {
+	var errs error
+
+	for _, target := range w.targets {
+		errs = errors.Join(errs, target.lintAll(ctx))
+	}
+
+	return errs
+}
+
+// lintFiles lints the files provided again, with any file no longer found removed from the report, and runs the
+// aggregate rules again with the aggregates of all files. Files failing to lint, like those failing to parse while
+// being edited, are linted again along with the files changed next, and their errors are returned until fixed.
+func (w *lintWatcher) lintFiles(ctx context.Context, files []string) error {
+	var errs error
+
+	for _, target := range w.targets {
+		changed := slices.Clone(target.failed)
+
+		for _, file := range files {
+			if w.targetOf(file) == target && !slices.Contains(changed, file) {
+				changed = append(changed, file)
+			}
+		}
+
+		if len(changed) == 0 {
+			errs = errors.Join(errs, target.err)
+
+			continue
+		}
+
+		// a target failing to lint at first has no results for files not changed, so all its files are linted again
+		if !target.complete {
+			errs = errors.Join(errs, target.lintAll(ctx))
+
+			continue
+		}
+
+		errs = errors.Join(errs, target.lintFiles(ctx, changed))
+	}
+
+	return errs
+}
+
+func (t *watchedTarget) lintAll(ctx context.Context) error {
+	clear(t.files)
+
+	t.aggregateViolations = nil
+
+	rep, err := t.linter.Lint(ctx)
+	if err != nil {
+		t.complete, t.err = false, fmt.Errorf("error(s) encountered while linting: %w", err)
+
+		return t.err
+	}
+
+	t.complete, t.failed, t.err = true, nil, nil
+	t.notices = rep.Notices
+
+	t.update(rep)
+
+	for _, violation := range rep.Violations {
+		if violation.IsAggregate {
+			t.aggregateViolations = append(t.aggregateViolations, violation)
+		}
+	}
+
+	return nil
+}
+
+func (t *watchedTarget) lintFiles(ctx context.Context, files []string) error {
+	existing := make([]string, 0, len(files))
+
+	for _, file := range files {
+		delete(t.files, file)
+
+		if _, err := os.Stat(file); err == nil {
+			existing = append(existing, file)
+		}
+	}
+
+	t.failed, t.err = nil, nil
+
+	if len(existing) > 0 {
+		rep, err := t.linter.WithInputPaths(existing).Lint(ctx)
+		if err != nil {
+			t.failed, t.err = existing, fmt.Errorf("error(s) encountered while linting: %w", err)
+
+			return t.err
+		}
+
+		t.update(rep)
+	}
+
+	return t.lintAggregates(ctx)
+}
+
+// update keeps the results of the files of rep, leaving out the violations of aggregate rules, which are only
+// complete when linting all files.
+func (t *watchedTarget) update(rep report.Report) {
+	for _, file := range rep.Files {
+		t.files[file] = &fileResult{aggregates: make(map[string][]report.Aggregate)}
+	}
+
+	for _, violation := range rep.Violations {
+		if result, ok := t.files[violation.Location.File]; ok && !violation.IsAggregate {
+			result.violations = append(result.violations, violation)
+		}
+	}
+
+	for _, violation := range rep.Suppressed {
+		if result, ok := t.files[violation.Location.File]; ok && !violation.IsAggregate {
+			result.suppressed = append(result.suppressed, violation)
+		}
+	}
+
+	for key, aggregates := range rep.Aggregates {
+		for _, aggregate := range aggregates {
+			if result, ok := t.files[aggregate.SourceFile()]; ok {
+				result.aggregates[key] = append(result.aggregates[key], aggregate)
+			}
+		}
+	}
+
+	for _, budget := range rep.Budgets {
+		t.budgets[budget.Category+"/"+budget.Title] = budget.MaxViolations
+	}
+}
+
+// lintAggregates runs the aggregate rules with the aggregates of all files, which like when linting, is only done
+// when there's more than one file to lint.
+func (t *watchedTarget) lintAggregates(ctx context.Context) error {
+	t.aggregateViolations = nil
+
+	if len(t.files) < 2 {
+		return nil
+	}
+
+	aggregates := make(map[string][]report.Aggregate)
+
+	for _, result := range t.files {
+		for key, collected := range result.aggregates {
+			aggregates[key] = append(aggregates[key], collected...)
+		}
+	}
+
+	rep, err := t.linter.LintAggregates(ctx, aggregates)
+	if err != nil {
+		return fmt.Errorf("error(s) encountered while linting: %w", err)
+	}
+
+	t.aggregateViolations = rep.Violations
+
+	return nil
+}
+
+// report returns the report of the results of all files, as if all files were linted again.
+func (w *lintWatcher) report() report.Report {
+	var result report.Report
+
+	for i, target := range w.targets {
+		var targetResult report.Report
+
+		files := util.Keys(target.files)
+
+		slices.Sort(files)
+
+		for _, file := range files {
+			targetResult.Files = append(targetResult.Files, file)
+			targetResult.Violations = append(targetResult.Violations, target.files[file].violations...)
+			targetResult.Suppressed = append(targetResult.Suppressed, target.files[file].suppressed...)
+		}
+
+		targetResult.Violations = append(targetResult.Violations, target.aggregateViolations...)
+		targetResult.Notices = target.notices
+
+		targetResult.ApplyBudgets(func(category, title string) (int, bool) {
+			limit, ok := target.budgets[category+"/"+title]
+
+			return limit, ok
+		})
+
+		rulesSkipped := 0
+
+		for _, notice := range target.notices {
+			if notice.Severity != "none" {
+				rulesSkipped++
+			}
+		}
+
+		targetResult.Summary = report.Summary{
+			FilesScanned:  len(targetResult.Files),
+			FilesFailed:   len(targetResult.ViolationsFileCount()),
+			RulesSkipped:  rulesSkipped,
+			NumViolations: len(targetResult.Violations),
+			NumSuppressed: len(targetResult.Suppressed),
+			NumByLevel:    report.CountByLevel(targetResult.Violations),
+		}
+
+		if len(w.targets) > 1 || w.params.groupBy == reporter.GroupByTarget {
+			targetResult.AssignTargets(func(file string) string {
+				return targetOf(file, target.paths)
+			})
+		}
+
+		if i == 0 {
+			result = targetResult
+		} else {
+			result.Merge(targetResult)
+		}
+	}
+
+	return result
+}
+
+// publish publishes the report of the results of all files, in place of the report published before when printed
+// to a terminal, or the error encountered while linting, like when a file being edited fails to parse.
+func (w *lintWatcher) publish(ctx context.Context, lintErr error) {
+	var outputWriter io.Writer = os.Stdout
+
+	if w.params.outputFile != "" {
+		writer, err := getWriterForOutputFile(w.params.outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open output file before use %v\n", err)
+
+			return
+		}
+
+		outputWriter = writer
+	} else if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stdout, clearScreen)
+	}
+
+	if lintErr != nil {
+		fmt.Fprintln(os.Stderr, lintErr)
+
+		return
+	}
+
+	if _, err := publishReport(ctx, w.args, w.params, w.report(), outputWriter); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to publish report: %v\n", err)
+	}
+}
@@ -4,6 +4,7 @@ package e2e
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,8 +14,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"gopkg.in/yaml.v3"
@@ -154,7 +157,9 @@ func TestLintAllViolations(t *testing.T) {
 	cwd := testutil.Must(os.Getwd())(t)
 	cfg := readProvidedConfig(t)
 
-	err := regal(&stdout, &stderr)("lint", "--format", "json", cwd+filepath.FromSlash("/testdata/violations"))
+	// the coverage report enables the untested-rule rule
+	err := regal(&stdout, &stderr)("lint", "--format", "json",
+		"--coverage", filepath.FromSlash("testdata/coverage/coverage.json"), cwd+filepath.FromSlash("/testdata/violations"))
 
 	expectExitCode(t, err, 3, &stdout, &stderr)
 
@@ -311,6 +316,65 @@ func TestLintRuleIgnoreFiles(t *testing.T) {
 	}
 }
 
+func TestLintMultipleTargetsWithOwnConfig(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	policy := "package p\n\nimport rego.v1\n\nallow if {input.a == \"b\"}\n"
+
+	// opa-fmt is ignored only in the configuration of service-a
+	files := map[string]string{
+		"service-a/.regal/config.yaml": "rules:\n  style:\n    opa-fmt:\n      level: ignore\n",
+		"service-a/p/p.rego":           policy,
+		"service-b/.regal/config.yaml": "rules: {}\n",
+		"service-b/p/p.rego":           policy,
+	}
+
+	for file, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	serviceA, serviceB := filepath.Join(root, "service-a"), filepath.Join(root, "service-b")
+
+	err := regal(&stdout, &stderr)("lint", "--format", "json", serviceA, serviceB)
+
+	expectExitCode(t, err, 3, &stdout, &stderr)
+
+	var rep report.Report
+	if err = json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+		t.Fatalf("expected JSON response, got %v", stdout.String())
+	}
+
+	if rep.Summary.FilesScanned != 2 {
+		t.Errorf("expected 2 files scanned, got %d", rep.Summary.FilesScanned)
+	}
+
+	for _, violation := range rep.Violations {
+		if violation.Title != "opa-fmt" {
+			continue
+		}
+
+		if violation.Target != serviceB {
+			t.Errorf("expected opa-fmt violation only in target %s, got %s", serviceB, violation.Target)
+		}
+	}
+
+	if len(rep.Violations) != 1 {
+		t.Errorf("expected 1 violation, got %d", len(rep.Violations))
+	}
+}
+
 func TestLintWithDebugOption(t *testing.T) {
 	t.Parallel()
 
@@ -330,6 +394,280 @@ func TestLintWithDebugOption(t *testing.T) {
 	}
 }
 
+func TestLintWithExecReporter(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat command not found")
+	}
+
+	cwd := testutil.Must(os.Getwd())(t)
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("lint", "--format", "exec:cat", cwd+filepath.FromSlash("/testdata/violations"))
+
+	expectExitCode(t, err, 3, &stdout, &stderr)
+
+	var rep report.Report
+	if err = json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+		t.Fatalf("expected JSON report to be output by command, got %v", err)
+	}
+
+	if rep.Summary.NumViolations == 0 {
+		t.Errorf("expected violations in report, got %+v", rep.Summary)
+	}
+}
+
+func TestLintFromStdin(t *testing.T) {
+	t.Parallel()
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	c := exec.Command(binary(), "lint", "--format", "json", "--stdin-filename", "policy/p.rego", "-")
+	c.Stdin = strings.NewReader("package p\n\nimport rego.v1\n\nallow = true\n")
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	expectExitCode(t, c.Run(), 3, &stdout, &stderr)
+
+	var rep report.Report
+	if err := json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+		t.Fatalf("expected JSON response, got %v", stdout.String())
+	}
+
+	if len(rep.Violations) != 1 {
+		t.Fatalf("expected 1 violation, got %d", len(rep.Violations))
+	}
+
+	if exp, act := "use-assignment-operator", rep.Violations[0].Title; exp != act {
+		t.Errorf("expected violation of %s, got %s", exp, act)
+	}
+
+	if exp, act := "policy/p.rego", rep.Violations[0].Location.File; exp != act {
+		t.Errorf("expected violation in %s, got %s", exp, act)
+	}
+}
+
+func TestLintBaseline(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "p.rego")
+	baseline := filepath.Join(dir, "baseline.json")
+
+	if err := os.WriteFile(policy, []byte("package p\n\nimport rego.v1\n\nallow = true\n"), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("lint", "--format", "json", "--generate-baseline", baseline, policy)
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	// the known violation moves down a line, and a new one is added below it
+	newPolicy := "package p\n\nimport rego.v1\n\n# allow all\nallow = true\n\ndeny = false\n"
+	if err = os.WriteFile(policy, []byte(newPolicy), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+
+	err = regal(&stdout, &stderr)("lint", "--format", "json", "--baseline", baseline, "--show-suppressed", policy)
+
+	expectExitCode(t, err, 3, &stdout, &stderr)
+
+	var rep report.Report
+	if err = json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+		t.Fatalf("expected JSON response, got %v", stdout.String())
+	}
+
+	if len(rep.Violations) != 1 || rep.Violations[0].Location.Row != 8 {
+		t.Fatalf("expected only the violation added since the baseline, got %v", rep.Violations)
+	}
+
+	if len(rep.Suppressed) != 1 || rep.Suppressed[0].Suppression != report.SuppressedByBaseline {
+		t.Errorf("expected violation in baseline to be suppressed by baseline, got %v", rep.Suppressed)
+	}
+}
+
+func TestLintWatch(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on Windows as interrupting the process isn't supported")
+	}
+
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "p.rego")
+
+	if err := os.WriteFile(policy, []byte("package p\n\nimport rego.v1\n\nallow = true\n"), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+
+	stderr := bytes.Buffer{}
+
+	c := exec.Command(binary(), "lint", "--watch", "--format", "json", dir)
+	c.Stderr = &stderr
+
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to get stdout: %v", err)
+	}
+
+	if err = c.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	timer := time.AfterFunc(30*time.Second, func() {
+		_ = c.Process.Kill()
+	})
+	defer timer.Stop()
+
+	decoder := json.NewDecoder(stdout)
+
+	var rep report.Report
+	if err = decoder.Decode(&rep); err != nil {
+		t.Fatalf("expected JSON report, got error %v, stderr: %s", err, stderr.String())
+	}
+
+	if len(rep.Violations) != 1 || rep.Violations[0].Title != "use-assignment-operator" {
+		t.Fatalf("expected violation of use-assignment-operator, got %v", rep.Violations)
+	}
+
+	if err = os.WriteFile(policy, []byte("package p\n\nimport rego.v1\n\nallow := true\n"), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+
+	rep = report.Report{}
+	if err = decoder.Decode(&rep); err != nil {
+		t.Fatalf("expected JSON report after change, got error %v, stderr: %s", err, stderr.String())
+	}
+
+	if len(rep.Violations) != 0 {
+		t.Errorf("expected no violations after fixing the file, got %v", rep.Violations)
+	}
+
+	if err = c.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("failed to interrupt: %v", err)
+	}
+
+	if err = c.Wait(); err != nil {
+		t.Errorf("expected exit code 0 when interrupted, got %v, stderr: %s", err, stderr.String())
+	}
+}
+
+func TestLintExitZero(t *testing.T) {
+	t.Parallel()
+
+	cwd := testutil.Must(os.Getwd())(t)
+
+	for _, flags := range [][]string{{"--exit-zero"}, {"--fail-level", "ignore"}} {
+		t.Run(strings.Join(flags, " "), func(t *testing.T) {
+			t.Parallel()
+
+			stdout := bytes.Buffer{}
+			stderr := bytes.Buffer{}
+
+			args := append([]string{"lint", "--format", "json"}, flags...)
+
+			err := regal(&stdout, &stderr)(append(args, cwd+filepath.FromSlash("/testdata/violations"))...)
+
+			expectExitCode(t, err, 0, &stdout, &stderr)
+
+			var rep report.Report
+			if err = json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+				t.Fatalf("expected JSON response, got %v", stdout.String())
+			}
+
+			if rep.Summary.NumByLevel["error"] == 0 {
+				t.Errorf("expected errors to be counted in summary, got %v", rep.Summary.NumByLevel)
+			}
+		})
+	}
+}
+
+func TestLintTraversalFlagsOverrideConfig(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+
+	files := map[string]string{
+		".regal/config.yaml": "traversal:\n  skip-hidden-directories: true\n",
+		"p.rego":             "package p\n",
+		".hidden/q.rego":     "package q\n",
+	}
+
+	for file, contents := range files {
+		path := filepath.Join(td, filepath.FromSlash(file))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the flag set to false overrides the configuration, while not setting it keeps it
+	for flags, expected := range map[string]int{"": 1, "--skip-hidden-dirs=false": 2} {
+		t.Run(flags, func(t *testing.T) {
+			t.Parallel()
+
+			stdout := bytes.Buffer{}
+			stderr := bytes.Buffer{}
+
+			args := []string{"lint", "--format", "json", "--exit-zero"}
+			if flags != "" {
+				args = append(args, flags)
+			}
+
+			err := regal(&stdout, &stderr)(append(args, td)...)
+
+			expectExitCode(t, err, 0, &stdout, &stderr)
+
+			var rep report.Report
+			if err = json.Unmarshal(stdout.Bytes(), &rep); err != nil {
+				t.Fatalf("expected JSON response, got %v", stdout.String())
+			}
+
+			if rep.Summary.FilesScanned != expected {
+				t.Errorf("expected %d files scanned, got %d", expected, rep.Summary.FilesScanned)
+			}
+		})
+	}
+}
+
+func TestLintTemplateFormatFromFile(t *testing.T) {
+	t.Parallel()
+
+	cwd := testutil.Must(os.Getwd())(t)
+	templateFile := filepath.Join(t.TempDir(), "report.tmpl")
+
+	err := os.WriteFile(templateFile, []byte("{{ range .Violations }}{{ .Title }}\n{{ end }}"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err = regal(&stdout, &stderr)("lint", "--format", "template", "--template-file", templateFile,
+		cwd+filepath.FromSlash("/testdata/violations"))
+
+	expectExitCode(t, err, 3, &stdout, &stderr)
+
+	if titles := strings.Split(strings.TrimSpace(stdout.String()), "\n"); !slices.Contains(titles, "opa-fmt") {
+		t.Errorf("expected title of each violation on a line of its own, got %q", stdout.String())
+	}
+}
+
 func TestLintRuleNamingConventionFromCustomCategory(t *testing.T) {
 	t.Parallel()
 
@@ -791,6 +1129,49 @@ allow := true
 	}
 }
 
+func TestFixInteractive(t *testing.T) {
+	t.Parallel()
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	td := t.TempDir()
+
+	// only violations are for the no-whitespace-comment rule
+	contents := []byte(`package wow
+
+import rego.v1
+
+#accepted
+#skipped
+allow := true
+`)
+	if err := os.WriteFile(filepath.Join(td, "main.rego"), contents, 0o644); err != nil {
+		t.Fatalf("failed to write main.rego: %v", err)
+	}
+
+	c := exec.Command(binary(), "fix", "--interactive", "--no-color", td)
+	c.Stdin = strings.NewReader("y\nn\n")
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	expectExitCode(t, c.Run(), 0, &stdout, &stderr)
+
+	for _, exp := range []string{"-#accepted\n+# accepted\n", "-#skipped\n+# skipped\n", "1 fix applied:"} {
+		if !strings.Contains(stdout.String(), exp) {
+			t.Errorf("expected stdout to contain %q, got:\n%s", exp, stdout.String())
+		}
+	}
+
+	bs, err := os.ReadFile(filepath.Join(td, "main.rego"))
+	if err != nil {
+		t.Fatalf("failed to read main.rego: %v", err)
+	}
+
+	if exp, act := strings.Replace(string(contents), "#accepted", "# accepted", 1), string(bs); exp != act {
+		t.Errorf("expected\n%s, got\n%s", exp, act)
+	}
+}
+
 func binary() string {
 	var location string
 	if runtime.GOOS == "windows" {
@@ -858,3 +1239,277 @@ func expectExitCode(t *testing.T, err error, exp int, stdout *bytes.Buffer, stde
 			exp, act, stdout.String(), stderr.String())
 	}
 }
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	cwd := testutil.Must(os.Getwd())(t)
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("stats", "--format", "json", cwd+filepath.FromSlash("/testdata/violations"))
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	var result struct {
+		Files      int            `json:"files"`
+		Packages   int            `json:"packages"`
+		Rules      int            `json:"rules"`
+		Tests      int            `json:"tests"`
+		Builtins   map[string]int `json:"builtins"`
+		Violations map[string]int `json:"violations_by_category"`
+	}
+
+	if err = json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected JSON result, got %v", err)
+	}
+
+	if result.Files != 3 || result.Packages != 3 || result.Rules == 0 || result.Tests != 1 {
+		t.Errorf("expected 3 files and packages, rules and a test, got %+v", result)
+	}
+
+	if result.Builtins["indexof"] != 2 {
+		t.Errorf("expected 2 calls to indexof, got %v", result.Builtins)
+	}
+
+	if result.Violations["bugs"] == 0 || result.Violations["style"] == 0 {
+		t.Errorf("expected violations by category, got %v", result.Violations)
+	}
+}
+
+func TestLintJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("lint", "--format", "json", "--schema")
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	var schema map[string]any
+
+	if err = json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("expected JSON schema, got %v", err)
+	}
+
+	if schema["title"] != "Regal lint report" {
+		t.Errorf("expected report schema, got %v", schema["title"])
+	}
+}
+
+func TestConfigEffective(t *testing.T) {
+	t.Parallel()
+
+	cwd := testutil.Must(os.Getwd())(t)
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("config", "effective", "--format", "json", "--disable", "opa-fmt",
+		"--config-file", cwd+filepath.FromSlash("/testdata/configs/ignore_files_prefer_snake_case.yaml"),
+		cwd+filepath.FromSlash("/testdata/violations/most_violations.rego"))
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	var conf config.Config
+
+	if err = json.Unmarshal(stdout.Bytes(), &conf); err != nil {
+		t.Fatalf("expected JSON config, got %v", err)
+	}
+
+	for rule, level := range map[string]string{
+		"file-length":       "ignore",
+		"prefer-snake-case": "ignore",
+		"opa-fmt":           "ignore",
+		"line-length":       "error",
+	} {
+		if act := conf.Rules["style"][rule].Level; act != level {
+			t.Errorf("expected level of %s to be %s, got %s", rule, level, act)
+		}
+	}
+
+	if conf.Capabilities != nil {
+		t.Errorf("expected capabilities to be omitted")
+	}
+}
+
+func TestBenchCompare(t *testing.T) {
+	t.Parallel()
+
+	cwd := testutil.Must(os.Getwd())(t)
+	previous := filepath.Join(t.TempDir(), "bench.json")
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("bench", "--count", "1", "--warmup", "0", "--format", "json",
+		"--output-file", previous, cwd+filepath.FromSlash("/testdata/aggregates"))
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	var result struct {
+		Runs  int `json:"runs"`
+		Files int `json:"files"`
+		Rules []struct {
+			Name string `json:"name"`
+		} `json:"rules"`
+	}
+
+	if err = json.Unmarshal(testutil.Must(os.ReadFile(previous))(t), &result); err != nil {
+		t.Fatalf("expected JSON result, got %v", err)
+	}
+
+	if result.Runs != 1 || result.Files == 0 || len(result.Rules) == 0 {
+		t.Errorf("expected one run with files linted and rules profiled, got %+v", result)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+
+	err = regal(&stdout, &stderr)("bench", "--count", "1", "--warmup", "0", "--compare", previous,
+		cwd+filepath.FromSlash("/testdata/aggregates"))
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	if !strings.Contains(stdout.String(), "Wall time (mean):") || !strings.Contains(stdout.String(), "%)") {
+		t.Errorf("expected comparison with previous result in output, got %q", stdout.String())
+	}
+}
+
+func TestLockCheck(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	configFile := filepath.Join(root, ".regal", "config.yaml")
+	lockFile := filepath.Join(root, "regal.lock")
+
+	if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(configFile, []byte("rules: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("lock", "--lockfile", lockFile, root)
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	stdout.Reset()
+	stderr.Reset()
+
+	err = regal(&stdout, &stderr)("lock", "--check", "--lockfile", lockFile, root)
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	config := "rules:\n  style:\n    opa-fmt:\n      level: warning\n"
+	if err = os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+
+	err = regal(&stdout, &stderr)("lock", "--check", "--lockfile", lockFile, root)
+
+	expectExitCode(t, err, 1, &stdout, &stderr)
+
+	if exp := "- rule style/opa-fmt level changed from error to warning"; !strings.Contains(stdout.String(), exp) {
+		t.Errorf("expected drift %q, got %s", exp, stdout.String())
+	}
+}
+
+func TestLintDebugBundle(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	bundleDir := filepath.Join(root, "debug")
+	policy := filepath.Join(root, "p.rego")
+
+	if err := os.WriteFile(policy, []byte("package p\n\nimport rego.v1\n\nsecret := \"hunter2\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("lint", "--debug-bundle", bundleDir, "--webhook-header", "X-Token: hunter2", policy)
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	archives, err := filepath.Glob(filepath.Join(bundleDir, "regal-debug-*.tar.gz"))
+	if err != nil || len(archives) != 1 {
+		t.Fatalf("expected one debug bundle in %s, got %v: %v", bundleDir, archives, err)
+	}
+
+	if !strings.Contains(stderr.String(), "debug bundle written to "+archives[0]) {
+		t.Errorf("expected path of debug bundle reported, got %s", stderr.String())
+	}
+
+	f, err := os.Open(archives[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(bs), "hunter2") {
+		t.Errorf("expected secrets and policy contents redacted from debug bundle")
+	}
+
+	if !strings.Contains(string(bs), policy) {
+		t.Errorf("expected path of file linted in debug bundle")
+	}
+}
+
+func TestDocsVerify(t *testing.T) {
+	t.Parallel()
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+
+	err := regal(&stdout, &stderr)("docs", "verify", filepath.Join("..", "docs", "rules"))
+
+	expectExitCode(t, err, 0, &stdout, &stderr)
+
+	if !strings.HasSuffix(stdout.String(), " verified, 0 failed\n") {
+		t.Errorf("expected all examples verified, got %s", stdout.String())
+	}
+
+	root := t.TempDir()
+	doc := filepath.Join(root, "use-assignment-operator.md")
+	content := "# use-assignment-operator\n\n**Category**: Style\n\n**Avoid**\n```rego\npackage p\n\nx := 1\n```\n"
+
+	if err := os.WriteFile(doc, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+
+	err = regal(&stdout, &stderr)("docs", "verify", doc)
+
+	expectExitCode(t, err, 1, &stdout, &stderr)
+
+	expected := doc + ":7: expected style/use-assignment-operator to flag example, but it passed\n" +
+		"1 examples verified, 1 failed\n"
+
+	if stdout.String() != expected {
+		t.Errorf("expected %q, got %q", expected, stdout.String())
+	}
+}
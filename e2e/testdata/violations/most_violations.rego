@@ -89,6 +89,22 @@ impossible_not if {
 	not partial
 }
 
+partial_rule_compared_to_scalar if partial == "foo"
+
+inconsistent_else := "foo" if {
+	input.foo
+} else if {
+	input.bar
+}
+
+impossible_comparison if count(partial) == "1"
+
+time_misuse if time.now_ns() > 1700000000
+
+unresolved_with_target if {
+	partial with data.all_violations.unknown as true
+}
+
 ### Idiomatic ###
 
 custom_has_key_construct(map, key) if {
@@ -120,6 +136,9 @@ boolean_assignment := 1 < input.two
 # avoid-get-and-list-prefix
 get_foo(foo) := foo
 
+# commented-out-code
+# commented_out := "code"
+
 # METADATA
 # description: detached-metadata
 
@@ -140,6 +159,8 @@ line_length_should_be_no_longer_than_120_characters_but_this_line_is_really_long
 
  opa_fmt := "fail"
 
+prefer_raw_string := "C:\\Users\\admin\\policies"
+
 preferSnakeCase := "fail"
 
 # todo-comment
@@ -0,0 +1,210 @@
+// Package builtindb provides metadata on built-in functions, like their arguments and documentation, the version of
+// OPA they were introduced in, and whether they are deprecated. The built-in functions in a DB are those of some
+// capabilities, while their metadata is taken from the capabilities, from OPA, from the versions of OPA introducing
+// each built-in function, as generated from the capabilities of all versions, and from documentation curated for
+// Regal. This is shared by the features documenting built-in functions, like hover and completions in the language
+// server, and by rules like deprecated-builtin.
+package builtindb
+
+//go:generate go run ./gen -o since.json
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/types"
+)
+
+// since holds the version of OPA introducing each built-in function, except those present in the first version of
+// which capabilities are known.
+//
+//go:embed since.json
+var since []byte
+
+//go:embed curated.yaml
+var curated []byte
+
+type curatedDocs struct {
+	Replacement string `yaml:"replacement"`
+}
+
+//nolint:gochecknoglobals
+var (
+	sinceVersions = sync.OnceValue(func() map[string]string {
+		versions := make(map[string]string)
+		if err := json.Unmarshal(since, &versions); err != nil {
+			panic(fmt.Sprintf("failed to unmarshal versions of built-in functions: %v", err))
+		}
+
+		return versions
+	})
+
+	curatedBuiltins = sync.OnceValue(func() map[string]curatedDocs {
+		docs := make(map[string]curatedDocs)
+		if err := yaml.Unmarshal(curated, &docs); err != nil {
+			panic(fmt.Sprintf("failed to unmarshal curated docs of built-in functions: %v", err))
+		}
+
+		return docs
+	})
+
+	defaultDB = sync.OnceValue(func() *DB {
+		return New(ast.CapabilitiesForThisVersion())
+	})
+)
+
+// Arg describes an argument, or the result, of a built-in function. Arguments without a name have only a type.
+type Arg struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Builtin holds the metadata of a built-in function.
+type Builtin struct {
+	Name        string
+	Description string
+	// Category is the first category of the built-in function, or the first part of its name, like "strings".
+	Category string
+	Args     []Arg
+	// Result is nil for built-in functions without a result, like print.
+	Result *Arg
+	// Since is the version of OPA introducing the built-in function, or empty if it was present in the first
+	// version of which capabilities are known.
+	Since       string
+	Deprecated  bool
+	Replacement string
+	// Infix is the operator of built-in functions called as such, like "+" for plus.
+	Infix            string
+	Nondeterministic bool
+}
+
+// DocumentationURL returns the URL of the documentation of the built-in function in the OPA policy reference.
+func (b *Builtin) DocumentationURL() string {
+	return fmt.Sprintf(
+		"https://www.openpolicyagent.org/docs/latest/policy-reference/#builtin-%s-%s",
+		b.Category,
+		strings.ReplaceAll(b.Name, ".", ""),
+	)
+}
+
+// DB holds the metadata of the built-in functions of some capabilities. A DB is safe for concurrent use.
+type DB struct {
+	builtins map[string]*Builtin
+	sorted   []*Builtin
+}
+
+// Default returns the DB of the built-in functions of the version of OPA Regal depends on.
+func Default() *DB {
+	return defaultDB()
+}
+
+// New returns a DB of the built-in functions of the capabilities. The documentation and deprecation of built-in
+// functions known to the version of OPA Regal depends on is taken from that version, as capabilities of other
+// versions may lack either.
+func New(caps *ast.Capabilities) *DB {
+	db := &DB{
+		builtins: make(map[string]*Builtin, len(caps.Builtins)),
+		sorted:   make([]*Builtin, 0, len(caps.Builtins)),
+	}
+
+	for _, builtin := range caps.Builtins {
+		b := fromOPABuiltin(builtin)
+
+		db.builtins[b.Name] = b
+		db.sorted = append(db.sorted, b)
+	}
+
+	slices.SortFunc(db.sorted, func(a, b *Builtin) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return db
+}
+
+// Get returns the built-in function of the name, if in the DB.
+func (db *DB) Get(name string) (*Builtin, bool) {
+	b, ok := db.builtins[name]
+
+	return b, ok
+}
+
+// All returns all built-in functions of the DB, sorted by name. The slice returned must not be modified.
+func (db *DB) All() []*Builtin {
+	return db.sorted
+}
+
+func fromOPABuiltin(builtin *ast.Builtin) *Builtin {
+	b := &Builtin{
+		Name:             builtin.Name,
+		Description:      builtin.Description,
+		Category:         category(builtin),
+		Since:            sinceVersions()[builtin.Name],
+		Deprecated:       builtin.IsDeprecated(),
+		Infix:            builtin.Infix,
+		Nondeterministic: builtin.Nondeterministic,
+	}
+
+	decl := builtin.Decl
+
+	if known, ok := ast.BuiltinMap[builtin.Name]; ok {
+		b.Deprecated = b.Deprecated || known.IsDeprecated()
+		b.Nondeterministic = b.Nondeterministic || known.Nondeterministic
+
+		if b.Description == "" {
+			b.Description = known.Description
+		}
+
+		// declarations of capabilities loaded from JSON may lack the names and descriptions of arguments
+		if decl == nil || types.Compare(known.Decl, decl) == 0 {
+			decl = known.Decl
+		}
+	}
+
+	if docs, ok := curatedBuiltins()[builtin.Name]; ok {
+		b.Replacement = docs.Replacement
+	}
+
+	if decl == nil {
+		return b
+	}
+
+	for _, arg := range decl.NamedFuncArgs().Args {
+		b.Args = append(b.Args, toArg(arg))
+	}
+
+	if result := decl.NamedResult(); result != nil {
+		r := toArg(result)
+		b.Result = &r
+	}
+
+	return b
+}
+
+func toArg(t types.Type) Arg {
+	if named, ok := t.(*types.NamedType); ok {
+		return Arg{Name: named.Name, Type: named.Type.String(), Description: named.Descr}
+	}
+
+	return Arg{Type: t.String()}
+}
+
+func category(builtin *ast.Builtin) string {
+	if len(builtin.Categories) > 0 {
+		return builtin.Categories[0]
+	}
+
+	if s := strings.Split(builtin.Name, "."); len(s) > 1 {
+		return s[0]
+	}
+
+	return builtin.Name
+}
@@ -0,0 +1,93 @@
+package builtindb
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func TestDefault(t *testing.T) {
+	t.Parallel()
+
+	b, ok := Default().Get("graph.reachable")
+	if !ok {
+		t.Fatal("expected graph.reachable to be in the default DB")
+	}
+
+	if b.Category != "graph" {
+		t.Errorf("expected category graph, got %s", b.Category)
+	}
+
+	if b.Since != "v0.20.0" {
+		t.Errorf("expected since v0.20.0, got %s", b.Since)
+	}
+
+	if len(b.Args) != 2 || b.Args[0].Name != "graph" || b.Args[0].Description == "" {
+		t.Errorf("expected two named and described arguments, got %v", b.Args)
+	}
+
+	if b.Result == nil || b.Result.Name != "output" {
+		t.Errorf("expected result named output, got %v", b.Result)
+	}
+
+	expectedURL := "https://www.openpolicyagent.org/docs/latest/policy-reference/#builtin-graph-graphreachable"
+	if b.DocumentationURL() != expectedURL {
+		t.Errorf("expected documentation URL %s, got %s", expectedURL, b.DocumentationURL())
+	}
+
+	if b.Deprecated {
+		t.Error("expected graph.reachable not to be deprecated")
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	t.Parallel()
+
+	b, ok := Default().Get("re_match")
+	if !ok {
+		t.Fatal("expected re_match to be in the default DB")
+	}
+
+	if !b.Deprecated {
+		t.Error("expected re_match to be deprecated")
+	}
+
+	if b.Replacement != "`regex.match`" {
+		t.Errorf("expected replacement `regex.match`, got %s", b.Replacement)
+	}
+
+	if b.Since != "" {
+		t.Errorf("expected no since version for a built-in function present in the first version, got %s", b.Since)
+	}
+}
+
+func TestNewFromCapabilitiesJSON(t *testing.T) {
+	t.Parallel()
+
+	caps, err := ast.LoadCapabilitiesVersion("v0.55.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := New(caps)
+
+	if _, ok := db.Get("uuid.parse"); ok {
+		t.Error("expected uuid.parse, introduced in v0.57.0, not to be in the DB of v0.55.0")
+	}
+
+	b, ok := db.Get("graph.reachable")
+	if !ok {
+		t.Fatal("expected graph.reachable to be in the DB of v0.55.0")
+	}
+
+	if b.Description == "" || len(b.Args) != 2 || b.Args[0].Name != "graph" {
+		t.Errorf("expected documentation to be taken from OPA, got %+v", b)
+	}
+
+	all := db.All()
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Name >= all[i].Name {
+			t.Fatalf("expected built-in functions to be sorted by name, got %s before %s", all[i-1].Name, all[i].Name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Command gen generates the versions of OPA each built-in function was introduced in, from the capabilities of all
+// versions of OPA known to the version Regal depends on. Run it with go generate after upgrading OPA.
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func main() {
+	output := flag.String("o", "since.json", "file to write the versions to")
+
+	flag.Parse()
+
+	versions, err := ast.LoadCapabilitiesVersions()
+	if err != nil {
+		log.Fatalf("failed to list capabilities versions: %v", err)
+	}
+
+	// release candidates are left out, as built-in functions are documented as available from a release
+	versions = slices.DeleteFunc(versions, func(version string) bool {
+		return strings.Contains(version, "-")
+	})
+
+	slices.SortFunc(versions, compareVersions)
+
+	since := make(map[string]string)
+	seen := make(map[string]bool)
+
+	for i, version := range versions {
+		caps, err := ast.LoadCapabilitiesVersion(version)
+		if err != nil {
+			log.Fatalf("failed to load capabilities of %s: %v", version, err)
+		}
+
+		for _, builtin := range caps.Builtins {
+			if seen[builtin.Name] {
+				continue
+			}
+
+			seen[builtin.Name] = true
+
+			// the first version known may not be the one built-in functions present in it were introduced in
+			if i > 0 {
+				since[builtin.Name] = version
+			}
+		}
+	}
+
+	bs, err := json.MarshalIndent(since, "", "  ")
+	if err != nil {
+		log.Fatalf("failed to marshal versions: %v", err)
+	}
+
+	if err := os.WriteFile(*output, append(bs, '\n'), 0o600); err != nil {
+		log.Fatalf("failed to write %s: %v", *output, err)
+	}
+}
+
+// compareVersions compares versions like v0.64.1 by their numeric parts.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := range min(len(as), len(bs)) {
+		an, _ := strconv.Atoi(as[i])
+		bn, _ := strconv.Atoi(bs[i])
+
+		if c := cmp.Compare(an, bn); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(as), len(bs))
+}
@@ -0,0 +1,193 @@
+// Package codeowners finds the owners of files from the CODEOWNERS file of a repository, in the format used by
+// GitHub and GitLab.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+
+	rio "github.com/styrainc/regal/internal/io"
+)
+
+// Locations are the paths, relative to the root of a repository, where CODEOWNERS files are looked for, in order.
+//
+//nolint:gochecknoglobals
+var Locations = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// ErrNotFound is returned by Find when no CODEOWNERS file is found.
+var ErrNotFound = errors.New("no CODEOWNERS file found")
+
+type rule struct {
+	// patterns matched by the rule, any of which is enough for a match
+	patterns []glob.Glob
+	// dirOnly rules, with patterns ending with a slash, only match directories, and so the files in them
+	dirOnly bool
+	owners  []string
+}
+
+// CodeOwners holds the rules of a CODEOWNERS file, deciding the owners of the files of a repository.
+type CodeOwners struct {
+	root  string
+	rules []rule
+}
+
+// Find returns the rules of the CODEOWNERS file of the repository containing dir, which is looked for in the
+// locations of dir and each of its parents, stopping at the first found.
+func Find(dir string) (*CodeOwners, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", dir, err)
+	}
+
+	for {
+		for _, location := range Locations {
+			f, err := os.Open(filepath.Join(dir, filepath.FromSlash(location)))
+			if err != nil {
+				continue
+			}
+
+			defer rio.CloseFileIgnore(f)
+
+			return Parse(dir, f)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, ErrNotFound
+		}
+
+		dir = parent
+	}
+}
+
+// Parse parses the rules of a CODEOWNERS file of the repository at root. Each line holds a pattern, like those of
+// .gitignore files, followed by the owners of the files matching it. Section headers, as used by GitLab, are ignored.
+func Parse(root string, r io.Reader) (*CodeOwners, error) {
+	c := &CodeOwners{root: root}
+
+	scanner := bufio.NewScanner(r)
+	row := 0
+
+	for scanner.Scan() {
+		row++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") ||
+			strings.HasPrefix(line, "^[") {
+			continue
+		}
+
+		if i := strings.Index(line, " #"); i != -1 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+
+		patterns, dirOnly, err := compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern on line %d of CODEOWNERS: %w", row, err)
+		}
+
+		c.rules = append(c.rules, rule{patterns: patterns, dirOnly: dirOnly, owners: fields[1:]})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+
+	return c, nil
+}
+
+// Owners returns the owners of the file, as decided by the last rule matching it, or nil if the file has no owners.
+func (c *CodeOwners) Owners(file string) []string {
+	if file == "" {
+		return nil
+	}
+
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil
+	}
+
+	rel, err := filepath.Rel(c.root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].matches(rel) {
+			return c.rules[i].owners
+		}
+	}
+
+	return nil
+}
+
+// matches returns whether the rule matches the file at path, or any of the directories containing it.
+func (r rule) matches(path string) bool {
+	if !r.dirOnly && r.match(path) {
+		return true
+	}
+
+	for dir := path; strings.Contains(dir, "/"); {
+		dir = dir[:strings.LastIndex(dir, "/")]
+
+		if r.match(dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r rule) match(path string) bool {
+	for _, pattern := range r.patterns {
+		if pattern.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compile compiles a pattern of a CODEOWNERS file. Like in .gitignore files, patterns containing a slash, other than
+// a trailing one, are relative to the root of the repository, while others match at any depth.
+func compile(pattern string) ([]glob.Glob, bool, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		pattern = rest
+		anchored = false
+	}
+
+	patterns := []string{pattern}
+	if !anchored {
+		patterns = append(patterns, "**/"+pattern)
+	}
+
+	globs := make([]glob.Glob, 0, len(patterns))
+
+	for _, p := range patterns {
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to compile %s: %w", p, err)
+		}
+
+		globs = append(globs, g)
+	}
+
+	return globs, dirOnly, nil
+}
@@ -0,0 +1,87 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/repo")
+
+	c, err := Parse(root, strings.NewReader(`# default owners
+*                   @org/everyone
+
+*.rego              @org/policy # policies
+/build/             @org/build
+docs/               @org/docs
+/policy/authz/**    @org/authz alice@example.com
+
+[Section]
+policy/unowned.rego
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"README.md":                     {"@org/everyone"},
+		"main.rego":                     {"@org/policy"},
+		"build/main.rego":               {"@org/build"},
+		"policy/build/main.rego":        {"@org/policy"},
+		"docs/README.md":                {"@org/docs"},
+		"policy/docs/README.md":         {"@org/docs"},
+		"policy/authz/main.rego":        {"@org/authz", "alice@example.com"},
+		"policy/authz/nested/main.rego": {"@org/authz", "alice@example.com"},
+		"policy/unowned.rego":           {},
+	}
+
+	for file, expected := range testCases {
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			if owners := c.Owners(filepath.Join(root, filepath.FromSlash(file))); !slices.Equal(owners, expected) {
+				t.Errorf("expected owners %v, got %v", expected, owners)
+			}
+		})
+	}
+
+	if owners := c.Owners(""); owners != nil {
+		t.Errorf("expected no owners of violations without file, got %v", owners)
+	}
+
+	if owners := c.Owners(filepath.FromSlash("/other/main.rego")); owners != nil {
+		t.Errorf("expected no owners of file outside repository, got %v", owners)
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "policy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @org/everyone\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Find(filepath.Join(root, "policy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if owners := c.Owners(filepath.Join(root, "policy", "p.rego")); !slices.Equal(owners, []string{"@org/everyone"}) {
+		t.Errorf("expected owners [@org/everyone], got %v", owners)
+	}
+}
@@ -0,0 +1,403 @@
+// Package debugbundle records what Regal was run on, and how that went, into an archive for users to attach to bug
+// reports. The contents of the files linted are redacted unless asked for, with only their paths, sizes and digests
+// recorded, for bundles to be shared without sharing the policies of an organization.
+package debugbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/util"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/version"
+)
+
+// Redacted replaces values left out of a bundle.
+const Redacted = "[redacted]"
+
+// Recorder records the inputs, configuration, timings and errors of a run of Regal, to be written as a bundle by
+// Write. All methods are safe to call concurrently, and on a nil *Recorder, in which case they do nothing, so that
+// callers needn't check whether a bundle is recorded.
+type Recorder struct {
+	lock           sync.Mutex
+	start          time.Time
+	includeContent bool
+	command        []string
+	context        map[string]string
+	configs        []namedConfig
+	files          map[string]string
+	timings        map[string]*Timing
+	errors         []Error
+}
+
+// Manifest describes the run of Regal recorded in a bundle.
+type Manifest struct {
+	Version  version.Info      `json:"version"`
+	Command  []string          `json:"command"`
+	Context  map[string]string `json:"context,omitempty"`
+	Start    time.Time         `json:"start"`
+	Duration int64             `json:"duration_ns"`
+	// Redacted is set when the contents of the files are left out of the bundle.
+	Redacted bool `json:"redacted"`
+}
+
+// File describes a file linted, with Content only set when the contents of files are included in the bundle, as the
+// path of the file holding the contents in the archive.
+type File struct {
+	Path    string `json:"path"`
+	Size    int    `json:"size"`
+	Lines   int    `json:"lines"`
+	SHA256  string `json:"sha256"`
+	Content string `json:"content,omitempty"`
+}
+
+// Timing sums up the time taken by all occurrences of an operation, like requests of a method of the language server.
+type Timing struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Total int64  `json:"total_ns"`
+	Max   int64  `json:"max_ns"`
+}
+
+// Error is an error encountered during the run.
+type Error struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+type namedConfig struct {
+	name string
+	conf config.Config
+}
+
+// New creates a Recorder for the command run, with the contents of files included only if includeContent is set.
+// Values of flags that may hold secrets should be redacted from the command first, with RedactFlags.
+func New(command []string, includeContent bool) *Recorder {
+	return &Recorder{
+		start:          time.Now(),
+		includeContent: includeContent,
+		command:        command,
+		context:        make(map[string]string),
+		files:          make(map[string]string),
+		timings:        make(map[string]*Timing),
+	}
+}
+
+// RedactFlags returns a copy of args with the values of the flags named redacted, whether provided as --flag value, or
+// as --flag=value.
+func RedactFlags(args []string, flags ...string) []string {
+	redacted := slices.Clone(args)
+
+	for i := 0; i < len(redacted); i++ {
+		for _, flag := range flags {
+			switch {
+			case redacted[i] == "--"+flag && i+1 < len(redacted):
+				i++
+				redacted[i] = Redacted
+			case strings.HasPrefix(redacted[i], "--"+flag+"="):
+				redacted[i] = "--" + flag + "=" + Redacted
+			}
+		}
+	}
+
+	return redacted
+}
+
+// AddContext records a piece of context of the run, like the client of the language server.
+func (r *Recorder) AddContext(key, value string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.context[key] = value
+}
+
+// AddConfig records the configuration used, where name tells the configuration apart from others of the same run,
+// like those of several projects linted together.
+func (r *Recorder) AddConfig(name string, conf config.Config) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.configs = append(r.configs, namedConfig{name: name, conf: conf})
+}
+
+// AddFile records a file linted, and its contents.
+func (r *Recorder) AddFile(path, content string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.files[path] = content
+}
+
+// AddFileFromPath records a file linted, reading its contents from disk, as files failing to parse are of particular
+// interest, while never read by the linter otherwise.
+func (r *Recorder) AddFileFromPath(path string) {
+	if r == nil {
+		return
+	}
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		r.AddError(fmt.Errorf("failed to read %s for debug bundle: %w", path, err))
+
+		return
+	}
+
+	r.AddFile(path, string(bs))
+}
+
+// AddTiming records the time taken by an occurrence of the operation named.
+func (r *Recorder) AddTiming(name string, duration time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	timing, ok := r.timings[name]
+	if !ok {
+		timing = &Timing{Name: name}
+		r.timings[name] = timing
+	}
+
+	timing.Count++
+	timing.Total += int64(duration)
+	timing.Max = max(timing.Max, int64(duration))
+}
+
+// AddMetrics records the timers of metrics collected by the linter, like timer_regal_lint_total_ns.
+func (r *Recorder) AddMetrics(metrics map[string]any) {
+	for name, value := range metrics {
+		if ns, ok := value.(int64); ok && strings.HasPrefix(name, "timer_") && strings.HasSuffix(name, "_ns") {
+			r.AddTiming(strings.TrimSuffix(strings.TrimPrefix(name, "timer_"), "_ns"), time.Duration(ns))
+		}
+	}
+}
+
+// AddError records an error encountered. Unless the contents of files are included, the details of parse errors are
+// left out, as they hold the lines of the policy failing to parse.
+func (r *Recorder) AddError(err error) {
+	if r == nil || err == nil {
+		return
+	}
+
+	message := err.Error()
+	if !r.includeContent {
+		message = redactErrorDetails(err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.errors = append(r.errors, Error{Time: time.Now(), Message: message})
+}
+
+// redactErrorDetails returns the message of err without the details of any OPA errors it wraps, and without the
+// tab indented lines following the first line of errors flattened to strings, which is how OPA prints those details.
+func redactErrorDetails(err error) string {
+	message := err.Error()
+
+	var astErrs ast.Errors
+	if errors.As(err, &astErrs) {
+		for _, astErr := range astErrs {
+			message = withoutDetails(message, astErr)
+		}
+	}
+
+	var astErr *ast.Error
+	if errors.As(err, &astErr) {
+		message = withoutDetails(message, astErr)
+	}
+
+	lines := strings.Split(message, "\n")
+
+	return strings.Join(slices.DeleteFunc(lines, func(line string) bool {
+		return strings.HasPrefix(line, "\t")
+	}), "\n")
+}
+
+func withoutDetails(message string, astErr *ast.Error) string {
+	if astErr == nil || astErr.Details == nil {
+		return message
+	}
+
+	redacted := *astErr
+	redacted.Details = nil
+
+	return strings.ReplaceAll(message, astErr.Error(), redacted.Error())
+}
+
+// Write writes the bundle recorded so far to a gzipped tar archive in dir, created if missing, and returns the path
+// of the archive.
+func (r *Recorder) Write(dir string) (string, error) {
+	if r == nil {
+		return "", nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory for debug bundle: %w", err)
+	}
+
+	path := filepath.Join(dir, "regal-debug-"+time.Now().UTC().Format("20060102T150405Z")+".tar.gz")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create debug bundle: %w", err)
+	}
+
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	if err := r.writeEntries(tw); err != nil {
+		return "", err
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("failed to close debug bundle archive: %w", err)
+	}
+
+	if err := gw.Close(); err != nil {
+		return "", fmt.Errorf("failed to close debug bundle archive: %w", err)
+	}
+
+	return path, nil
+}
+
+func (r *Recorder) writeEntries(tw *tar.Writer) error {
+	manifest := Manifest{
+		Version:  version.New(),
+		Command:  r.command,
+		Context:  r.context,
+		Start:    r.start,
+		Duration: int64(time.Since(r.start)),
+		Redacted: !r.includeContent,
+	}
+
+	if err := writeJSON(tw, "manifest.json", manifest); err != nil {
+		return err
+	}
+
+	paths := util.Keys(r.files)
+	slices.Sort(paths)
+
+	files := make([]File, 0, len(paths))
+
+	for i, path := range paths {
+		content := r.files[path]
+		sum := sha256.Sum256([]byte(content))
+
+		file := File{
+			Path:   path,
+			Size:   len(content),
+			Lines:  strings.Count(content, "\n") + 1,
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+
+		if r.includeContent {
+			// numbered, as paths of files may be URIs, or point outside of the directory linted
+			file.Content = fmt.Sprintf("files/%04d-%s", i, filepath.Base(path))
+
+			if err := writeEntry(tw, file.Content, []byte(content)); err != nil {
+				return err
+			}
+		}
+
+		files = append(files, file)
+	}
+
+	if err := writeJSON(tw, "files.json", files); err != nil {
+		return err
+	}
+
+	var configs strings.Builder
+
+	for i, named := range r.configs {
+		if i > 0 {
+			configs.WriteString("---\n")
+		}
+
+		bs, err := yaml.Marshal(named.conf)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config for debug bundle: %w", err)
+		}
+
+		fmt.Fprintf(&configs, "# %s\n%s", named.name, bs)
+	}
+
+	if err := writeEntry(tw, "config.yaml", []byte(configs.String())); err != nil {
+		return err
+	}
+
+	timings := make([]Timing, 0, len(r.timings))
+	for _, timing := range r.timings {
+		timings = append(timings, *timing)
+	}
+
+	slices.SortFunc(timings, func(a, b Timing) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if err := writeJSON(tw, "timings.json", timings); err != nil {
+		return err
+	}
+
+	return writeJSON(tw, "errors.json", append(make([]Error, 0, len(r.errors)), r.errors...))
+}
+
+func writeJSON(tw *tar.Writer, name string, v any) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s for debug bundle: %w", name, err)
+	}
+
+	return writeEntry(tw, name, append(bs, '\n'))
+}
+
+func writeEntry(tw *tar.Writer, name string, bs []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(bs)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to write %s to debug bundle: %w", name, err)
+	}
+
+	if _, err := tw.Write(bs); err != nil {
+		return fmt.Errorf("failed to write %s to debug bundle: %w", name, err)
+	}
+
+	return nil
+}
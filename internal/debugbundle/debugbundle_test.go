@@ -0,0 +1,205 @@
+package debugbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/pkg/config"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	for _, includeContent := range []bool{false, true} {
+		recorder := New([]string{"lint", "--webhook-header", Redacted, "p.rego"}, includeContent)
+
+		recorder.AddContext("client", "vscode")
+		recorder.AddConfig("p.rego", config.Config{Timeout: config.Duration(time.Minute)})
+		recorder.AddFile("p.rego", "package p\n\nallow := true\n")
+		recorder.AddTiming("lsp textDocument/hover", 2*time.Millisecond)
+		recorder.AddTiming("lsp textDocument/hover", 4*time.Millisecond)
+		recorder.AddMetrics(map[string]any{"timer_regal_lint_total_ns": int64(1000), "counter_ignored": 1})
+		recorder.AddError(errors.New("something went wrong"))
+
+		path, err := recorder.Write(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries := readArchive(t, path)
+
+		var manifest Manifest
+		unmarshal(t, entries["manifest.json"], &manifest)
+
+		if manifest.Redacted == includeContent || manifest.Context["client"] != "vscode" {
+			t.Errorf("unexpected manifest %+v", manifest)
+		}
+
+		var files []File
+		unmarshal(t, entries["files.json"], &files)
+
+		if len(files) != 1 || files[0].Path != "p.rego" || files[0].Size != 25 || files[0].Lines != 4 {
+			t.Fatalf("unexpected files %+v", files)
+		}
+
+		if content, ok := entries[files[0].Content]; includeContent != ok || ok && content != "package p\n\nallow := true\n" {
+			t.Errorf("expected content included to be %t, got %q", includeContent, content)
+		}
+
+		for name, content := range entries {
+			if !includeContent && strings.Contains(content, "allow := true") {
+				t.Errorf("expected contents of files to be redacted, found in %s", name)
+			}
+		}
+
+		if conf := entries["config.yaml"]; !strings.Contains(conf, "# p.rego\n") || !strings.Contains(conf, "timeout: 1m0s") {
+			t.Errorf("unexpected config:\n%s", entries["config.yaml"])
+		}
+
+		var timings []Timing
+		unmarshal(t, entries["timings.json"], &timings)
+
+		expectedTimings := []Timing{
+			{Name: "lsp textDocument/hover", Count: 2, Total: int64(6 * time.Millisecond), Max: int64(4 * time.Millisecond)},
+			{Name: "regal_lint_total", Count: 1, Total: 1000, Max: 1000},
+		}
+
+		if !slices.Equal(timings, expectedTimings) {
+			t.Errorf("expected timings %v, got %v", expectedTimings, timings)
+		}
+
+		var errs []Error
+		unmarshal(t, entries["errors.json"], &errs)
+
+		if len(errs) != 1 || errs[0].Message != "something went wrong" {
+			t.Errorf("unexpected errors %+v", errs)
+		}
+	}
+}
+
+func TestWriteRedactsParseErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	source := "package p\n\nallow if { SECRET_TOKEN_abc == = 1 }\n"
+
+	_, parseErr := ast.ParseModule("p.rego", source)
+	if parseErr == nil {
+		t.Fatal("expected parse error")
+	}
+
+	for _, includeContent := range []bool{false, true} {
+		recorder := New([]string{"lint", "p.rego"}, includeContent)
+
+		recorder.AddFile("p.rego", source)
+		recorder.AddError(fmt.Errorf("failed to parse: %w", parseErr))
+		recorder.AddError(fmt.Errorf("textDocument/formatting: %v", parseErr)) //nolint:errorlint
+
+		path, err := recorder.Write(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries := readArchive(t, path)
+
+		for name, content := range entries {
+			if includeContent != strings.Contains(content, "SECRET_TOKEN_abc") && name == "errors.json" {
+				t.Errorf("expected source in errors to be included %t, got:\n%s", includeContent, content)
+			}
+
+			if !includeContent && strings.Contains(content, "SECRET_TOKEN_abc") {
+				t.Errorf("expected source to be redacted, found in %s:\n%s", name, content)
+			}
+		}
+
+		var errs []Error
+		unmarshal(t, entries["errors.json"], &errs)
+
+		if len(errs) != 2 || !strings.Contains(errs[0].Message, "p.rego:3: rego_parse_error") {
+			t.Errorf("expected location of parse error to be kept, got %+v", errs)
+		}
+	}
+}
+
+func TestRedactFlags(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"lint", "--webhook-header", "Authorization: secret", "--webhook-header=X-Token: secret", "p.rego"}
+	expected := []string{"lint", "--webhook-header", Redacted, "--webhook-header=" + Redacted, "p.rego"}
+
+	if redacted := RedactFlags(args, "webhook-header"); !slices.Equal(redacted, expected) {
+		t.Errorf("expected %v, got %v", expected, redacted)
+	}
+
+	if args[2] != "Authorization: secret" {
+		t.Errorf("expected args provided to be left unchanged")
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	t.Parallel()
+
+	var recorder *Recorder
+
+	recorder.AddFile("p.rego", "package p")
+	recorder.AddError(errors.New("ignored"))
+
+	if path, err := recorder.Write(t.TempDir()); path != "" || err != nil {
+		t.Errorf("expected nothing written by nil recorder, got %s, %v", path, err)
+	}
+}
+
+func readArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := make(map[string]string)
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bs, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries[header.Name] = string(bs)
+	}
+}
+
+func unmarshal(t *testing.T, s string, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", s, err)
+	}
+}
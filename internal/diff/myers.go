@@ -1,10 +1,11 @@
 // Copyright 2019 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
-// license that can be found in the LICENSE file.package langserver
+// license that can be found in the LICENSE file.
 // Source:
 // https://github.com/golang/tools/blob/78b158585360beccadc3faac6e35759f491831f3/internal/lsp/diff/myers/diff.go
 
-package lsp
+// Package diff computes the differences between the lines of two texts.
+package diff
 
 import (
 	"strings"
@@ -28,16 +29,17 @@ const (
 // https://blog.jcoglan.com/2017/02/17/the-myers-diff-algorithm-part-3/
 // https://www.codeproject.com/Articles/42279/%2FArticles%2F42279%2FInvestigating-Myers-diff-algorithm-Part-1-of-2
 
-type operation struct {
+// Operation is an operation converting one text into another.
+type Operation struct {
 	Kind    OpKind
 	Content []string // content from b
 	I1, I2  uint     // indices of the line in a
 	J1      uint     // indices of the line in b, J2 implied by len(Content)
 }
 
-// operations returns the list of operations to convert a into b, consolidating
+// Operations returns the list of operations to convert a into b, consolidating
 // operations for multiple lines and not including equal lines.
-func operations(a, b []string) []*operation {
+func Operations(a, b []string) []*Operation {
 	if len(a) == 0 && len(b) == 0 {
 		return nil
 	}
@@ -49,9 +51,9 @@ func operations(a, b []string) []*operation {
 
 	var i int
 
-	solution := make([]*operation, len(a)+len(b))
+	solution := make([]*Operation, len(a)+len(b))
 
-	add := func(op *operation, i2, j2 int) {
+	add := func(op *Operation, i2, j2 int) {
 		if op == nil {
 			return
 		}
@@ -72,11 +74,11 @@ func operations(a, b []string) []*operation {
 			continue
 		}
 
-		var op *operation
+		var op *Operation
 		// delete (horizontal)
 		for snake[0]-snake[1] > x-y {
 			if op == nil {
-				op = &operation{
+				op = &Operation{
 					Kind: Delete,
 					I1:   uint(x),
 					J1:   uint(y),
@@ -93,7 +95,7 @@ func operations(a, b []string) []*operation {
 		// insert (vertical)
 		for snake[0]-snake[1] < x-y {
 			if op == nil {
-				op = &operation{
+				op = &Operation{
 					Kind: Insert,
 					I1:   uint(x),
 					J1:   uint(y),
@@ -206,7 +208,8 @@ func shortestEditSequence(a, b []string) ([][]int, int) {
 	return nil, 0
 }
 
-func splitLines(text string) []string {
+// SplitLines splits text into lines, each ending with its line break, if any.
+func SplitLines(text string) []string {
 	lines := strings.SplitAfter(text, "\n")
 	if lines[len(lines)-1] == "" {
 		lines = lines[:len(lines)-1]
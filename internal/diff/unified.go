@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unified returns the differences between before and after in the unified format, like diff -u, without the file
+// headers. Changes are surrounded by up to contextLines of unchanged lines, and changes closer to each other than
+// twice that are grouped in the same hunk. An empty string is returned if the texts are equal.
+func Unified(before, after string, contextLines int) string {
+	a, b := SplitLines(before), SplitLines(after)
+	ops := Operations(a, b)
+
+	var sb strings.Builder
+
+	// delta is the difference between the line numbers of before and after, up to the current hunk
+	delta := 0
+
+	for start := 0; start < len(ops); {
+		end := start + 1
+		for end < len(ops) && int(ops[end].I1-ops[end-1].I2) <= 2*contextLines {
+			end++
+		}
+
+		hunk := ops[start:end]
+
+		from := max(0, int(hunk[0].I1)-contextLines)
+		to := min(len(a), int(hunk[len(hunk)-1].I2)+contextLines)
+		hunkDelta := 0
+
+		var lines strings.Builder
+
+		i := from
+
+		for _, op := range hunk {
+			writeLines(&lines, " ", a[i:op.I1])
+
+			switch op.Kind {
+			case Delete:
+				writeLines(&lines, "-", a[op.I1:op.I2])
+				hunkDelta -= int(op.I2 - op.I1)
+				i = int(op.I2)
+			case Insert:
+				writeLines(&lines, "+", op.Content)
+				hunkDelta += len(op.Content)
+				i = int(op.I1)
+			case Equal:
+			}
+		}
+
+		writeLines(&lines, " ", a[i:to])
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(from, to-from), hunkRange(from+delta, to-from+hunkDelta))
+		sb.WriteString(lines.String())
+
+		delta += hunkDelta
+		start = end
+	}
+
+	return sb.String()
+}
+
+func writeLines(sb *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// hunkRange returns the range of lines of a hunk, where start is the index of its first line. Like diff -u, empty
+// ranges start at the line before them.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,49 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		before   string
+		after    string
+		expected string
+	}{
+		"equal": {
+			before:   "a\nb\n",
+			after:    "a\nb\n",
+			expected: "",
+		},
+		"changed line": {
+			before:   "a\nb\nc\nd\ne\n",
+			after:    "a\nb\nC\nd\ne\n",
+			expected: "@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n",
+		},
+		"inserted line": {
+			before:   "a\nb\n",
+			after:    "a\nx\nb\n",
+			expected: "@@ -1,2 +1,3 @@\n a\n+x\n b\n",
+		},
+		"separate hunks": {
+			before:   "1\n2\n3\n4\n5\n6\n7\n8\n",
+			after:    "one\n2\n3\n4\n5\n6\n7\neight\n",
+			expected: "@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -7,2 +7,2 @@\n 7\n-8\n+eight\n",
+		},
+		"no trailing newline": {
+			before:   "a\nb",
+			after:    "a\nc",
+			expected: "@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Unified(tc.before, tc.after, 1); got != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, got)
+			}
+		})
+	}
+}
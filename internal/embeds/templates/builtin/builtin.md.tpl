@@ -32,7 +32,7 @@ This linter rule provides the following configuration options:
 rules:
   {{.Category}}:
     {{.NameOriginal}}:
-      # one of "error", "warning", "ignore"
+      # one of "error", "warning", "notice", "ignore"
       level: error
 ```
 
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uncommitted is the hash git blame reports for lines not yet committed.
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// Commit is the commit last changing a line, as reported by git blame.
+type Commit struct {
+	Hash        string
+	Author      string
+	AuthorEmail string
+	AuthorTime  time.Time
+}
+
+// Blame holds the commits last changing each line of a set of files of a repository.
+type Blame struct {
+	// commits of the lines of each file, by absolute path, where the commit of row n is at index n-1
+	lines map[string][]*Commit
+}
+
+// BlameFiles returns the commits last changing each line of the files, which must be in the repository containing
+// dir. Files not tracked by git, like those just created, have no commits to report.
+func BlameFiles(ctx context.Context, dir string, files []string) (*Blame, error) {
+	out, err := run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	root := strings.TrimSpace(out)
+	b := &Blame{lines: make(map[string][]*Commit, len(files))}
+
+	for _, file := range files {
+		path := resolve(file)
+		if _, ok := b.lines[path]; ok {
+			continue
+		}
+
+		if tracked, err := run(ctx, root, "ls-files", "--", path); err != nil || strings.TrimSpace(tracked) == "" {
+			b.lines[path] = nil
+
+			continue
+		}
+
+		out, err := run(ctx, root, "blame", "--porcelain", "--", path)
+		if err != nil {
+			return nil, err
+		}
+
+		if b.lines[path], err = parseBlame(out); err != nil {
+			return nil, fmt.Errorf("failed to parse blame of %s: %w", file, err)
+		}
+	}
+
+	return b, nil
+}
+
+// Of returns the commit last changing the line of the file. Rows below 1 refer to the file as a whole, and lines
+// not yet committed have no commit to report.
+func (b *Blame) Of(file string, row int) (Commit, bool) {
+	lines := b.lines[resolve(file)]
+	if row < 1 || row > len(lines) || lines[row-1] == nil || lines[row-1].Hash == uncommitted {
+		return Commit{}, false
+	}
+
+	return *lines[row-1], true
+}
+
+// parseBlame parses the output of git blame --porcelain, and returns the commit of each line. The details of each
+// commit are only included the first time the commit is reported, and so the same commit is shared by its lines.
+func parseBlame(out string) ([]*Commit, error) {
+	commits := make(map[string]*Commit)
+	lines := make([]*Commit, 0)
+
+	var current *Commit
+
+	var row int
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case current == nil:
+			// the header of a line: the hash of the commit, followed by the row of the line in the commit, its row
+			// in the file, and for the first line of a group, the number of lines in the group
+			fields := strings.Fields(line)
+			if len(fields) < 3 || len(fields[0]) != len(uncommitted) {
+				return nil, fmt.Errorf("malformed blame header: %s", line)
+			}
+
+			var err error
+			if row, err = strconv.Atoi(fields[2]); err != nil || row < 1 {
+				return nil, fmt.Errorf("malformed blame header: %s", line)
+			}
+
+			if current = commits[fields[0]]; current == nil {
+				current = &Commit{Hash: fields[0]}
+				commits[fields[0]] = current
+			}
+		case strings.HasPrefix(line, "\t"):
+			// the contents of the line, which ends its entry
+			for len(lines) < row {
+				lines = append(lines, nil)
+			}
+
+			lines[row-1] = current
+			current = nil
+		case strings.HasPrefix(line, "author "):
+			current.Author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			current.AuthorEmail = strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+		case strings.HasPrefix(line, "author-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current.AuthorTime = time.Unix(seconds, 0).UTC()
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blame: %w", err)
+	}
+
+	return lines, nil
+}
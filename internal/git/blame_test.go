@@ -0,0 +1,117 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBlame(t *testing.T) {
+	t.Parallel()
+
+	out := `1111111111111111111111111111111111111111 1 1 2
+author Alice
+author-mail <alice@example.com>
+author-time 1700000000
+author-tz +0000
+summary initial
+filename p.rego
+	package p
+1111111111111111111111111111111111111111 2 2
+	
+0000000000000000000000000000000000000000 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1800000000
+filename p.rego
+	x := 1
+`
+
+	lines, err := parseBlame(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+
+	if lines[0] != lines[1] || lines[1].Author != "Alice" || lines[1].AuthorEmail != "alice@example.com" {
+		t.Errorf("expected first two lines to be committed by Alice, got %v and %v", lines[0], lines[1])
+	}
+
+	if exp := int64(1700000000); lines[0].AuthorTime.Unix() != exp {
+		t.Errorf("expected author time %d, got %d", exp, lines[0].AuthorTime.Unix())
+	}
+
+	if lines[2].Hash != uncommitted {
+		t.Errorf("expected last line to be uncommitted, got %v", lines[2])
+	}
+}
+
+func TestBlameFiles(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	write := func(name, content string) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := run(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("p.rego", "package p\n\nx := 1\n")
+	git("init", "--quiet")
+	git("add", "p.rego")
+	git("commit", "--quiet", "-m", "initial")
+
+	write("p.rego", "package p\n\nx := 1\n\ny := 2\n")
+	write("q.rego", "package q\n")
+
+	p, q := filepath.Join(dir, "p.rego"), filepath.Join(dir, "q.rego")
+
+	blame, err := BlameFiles(ctx, dir, []string{p, q, p})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, ok := blame.Of(p, 3)
+	if !ok {
+		t.Fatal("expected commit of committed line")
+	}
+
+	if commit.Author != "test" || commit.AuthorEmail != "test@example.com" || len(commit.Hash) != 40 {
+		t.Errorf("unexpected commit %v", commit)
+	}
+
+	for _, tc := range []struct {
+		file string
+		row  int
+	}{
+		{p, 0},
+		{p, 5},
+		{q, 1},
+	} {
+		if commit, ok := blame.Of(tc.file, tc.row); ok {
+			t.Errorf("expected no commit for %s:%d, got %v", tc.file, tc.row, commit)
+		}
+	}
+}
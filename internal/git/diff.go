@@ -0,0 +1,237 @@
+// Package git provides the information Regal needs from the git repository of the policies linted, by way of the
+// git command.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lineRange is a range of lines, from start to end inclusive.
+type lineRange struct {
+	start int
+	end   int
+}
+
+// Diff holds the lines added or changed in each file of a repository since a ref, including files not yet tracked
+// by git, which are considered new in their entirety.
+type Diff struct {
+	root string
+	// changed lines of each file, by absolute path, with no lines for files where lines were only removed
+	changed map[string][]lineRange
+	// files added since the ref, or not yet tracked
+	added map[string]bool
+}
+
+// DiffAgainst returns the changes made to the working tree of the repository containing dir since ref, which may
+// be anything accepted by git diff, like a branch, tag or commit.
+func DiffAgainst(ctx context.Context, dir, ref string) (*Diff, error) {
+	root, err := run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diff{
+		root:    strings.TrimSpace(root),
+		changed: make(map[string][]lineRange),
+		added:   make(map[string]bool),
+	}
+
+	// the prefixes are set explicitly, as they may otherwise be changed by the configuration of the user
+	out, err := run(ctx, d.root,
+		"diff", "--no-color", "--no-ext-diff", "--unified=0", "--src-prefix=a/", "--dst-prefix=b/", ref, "--",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, added, err := parseDiff(out)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, ranges := range changed {
+		d.changed[filepath.Join(d.root, name)] = ranges
+	}
+
+	for _, name := range added {
+		d.added[filepath.Join(d.root, name)] = true
+	}
+
+	untracked, err := run(ctx, d.root, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range strings.Split(strings.TrimSpace(untracked), "\n") {
+		if name != "" {
+			d.added[filepath.Join(d.root, filepath.FromSlash(name))] = true
+		}
+	}
+
+	return d, nil
+}
+
+// IsNew returns whether the line of the file was added or changed since the ref. Rows below 1 refer to the file as
+// a whole, which is only considered new if the file itself is.
+func (d *Diff) IsNew(file string, row int) bool {
+	path := resolve(file)
+
+	if d.added[path] {
+		return true
+	}
+
+	for _, r := range d.changed[path] {
+		if row >= r.start && row <= r.end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsChanged returns whether the file was changed in any way since the ref, including by only removing lines, or
+// was added. Files deleted are not considered changed, as there's nothing left of them to lint.
+func (d *Diff) IsChanged(file string) bool {
+	path := resolve(file)
+
+	if d.added[path] {
+		return true
+	}
+
+	_, ok := d.changed[path]
+
+	return ok
+}
+
+// resolve returns the absolute path of a file, with any symlinks resolved, as the root of the repository reported
+// by git is.
+func resolve(file string) string {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return file
+	}
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	return path
+}
+
+// parseDiff parses the output of git diff with no lines of context, and returns the ranges of lines added or changed
+// in each file, along with the files added, by path relative to the root of the repository. Files where lines were
+// only removed are included with no ranges.
+func parseDiff(out string) (map[string][]lineRange, []string, error) {
+	changed := make(map[string][]lineRange)
+
+	var added []string
+
+	var current string
+
+	var fromNull bool
+
+	// lines of hunks may look like those of the header, e.g. a removed line starting with "-- "
+	var inHeader bool
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = ""
+			fromNull = false
+			inHeader = true
+		case inHeader && strings.HasPrefix(line, "--- "):
+			fromNull = line == "--- /dev/null"
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			// deleted files have no lines to report on
+			if line == "+++ /dev/null" {
+				current = ""
+
+				continue
+			}
+
+			current = filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/"))
+
+			if _, ok := changed[current]; !ok {
+				changed[current] = nil
+			}
+
+			if fromNull {
+				added = append(added, current)
+			}
+		case strings.HasPrefix(line, "@@ ") && current != "":
+			inHeader = false
+
+			r, ok, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if ok {
+				changed[current] = append(changed[current], r)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	return changed, added, nil
+}
+
+// parseHunkHeader returns the range of lines of the new version of a file covered by a hunk, from a header like
+// "@@ -10,2 +12,3 @@". Hunks removing lines only cover no lines of the new version, in which case false is returned.
+func parseHunkHeader(header string) (lineRange, bool, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return lineRange{}, false, fmt.Errorf("malformed hunk header: %s", header)
+	}
+
+	startStr, countStr, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return lineRange{}, false, fmt.Errorf("malformed hunk header: %s", header)
+	}
+
+	count := 1
+
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return lineRange{}, false, fmt.Errorf("malformed hunk header: %s", header)
+		}
+	}
+
+	if count == 0 {
+		return lineRange{}, false, nil
+	}
+
+	return lineRange{start: start, end: start + count - 1}, true, nil
+}
+
+func run(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
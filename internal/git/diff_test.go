@@ -0,0 +1,136 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	t.Parallel()
+
+	out := `diff --git a/policy/authz.rego b/policy/authz.rego
+index 3b18e51..a8c4f2d 100644
+--- a/policy/authz.rego
++++ b/policy/authz.rego
+@@ -3,0 +4,2 @@ package authz
++import rego.v1
++
+@@ -10 +12 @@ allow if {
+-	input.user == "admin"
++	input.user in admins
+@@ -20,3 +21,0 @@ deny if {
+-	false
+--- not a header
+-	false
+diff --git a/policy/trimmed.rego b/policy/trimmed.rego
+index 5d1f2a4..c9e0b71 100644
+--- a/policy/trimmed.rego
++++ b/policy/trimmed.rego
+@@ -5 +4,0 @@ package trimmed
+-x := 1
+diff --git a/policy/new.rego b/policy/new.rego
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/policy/new.rego
+@@ -0,0 +1,3 @@
++package new
++
++x := 1
+diff --git a/policy/old.rego b/policy/old.rego
+deleted file mode 100644
+index e69de29..0000000
+--- a/policy/old.rego
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+`
+
+	changed, added, err := parseDiff(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedChanged := map[string][]lineRange{
+		filepath.FromSlash("policy/authz.rego"):   {{start: 4, end: 5}, {start: 12, end: 12}},
+		filepath.FromSlash("policy/trimmed.rego"): nil,
+		filepath.FromSlash("policy/new.rego"):     {{start: 1, end: 3}},
+	}
+
+	if !reflect.DeepEqual(changed, expectedChanged) {
+		t.Errorf("expected changed lines %v, got %v", expectedChanged, changed)
+	}
+
+	if expectedAdded := []string{filepath.FromSlash("policy/new.rego")}; !reflect.DeepEqual(added, expectedAdded) {
+		t.Errorf("expected added files %v, got %v", expectedAdded, added)
+	}
+}
+
+func TestDiffAgainst(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	write := func(name, content string) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := run(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("p.rego", "package p\n\nx := 1\n")
+	write("r.rego", "package r\n\nx := 1\n")
+	write("s.rego", "package s\n")
+	git("init", "--quiet")
+	git("add", "p.rego", "r.rego", "s.rego")
+	git("commit", "--quiet", "-m", "initial")
+
+	write("p.rego", "package p\n\nx := 1\n\ny := 2\n")
+	write("q.rego", "package q\n")
+	write("r.rego", "package r\n")
+
+	diff, err := DiffAgainst(ctx, dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		file string
+		row  int
+		new  bool
+	}{
+		{"p.rego", 3, false},
+		{"p.rego", 5, true},
+		{"q.rego", 1, true},
+		{"q.rego", 0, true},
+	} {
+		if got := diff.IsNew(filepath.Join(dir, tc.file), tc.row); got != tc.new {
+			t.Errorf("expected %s:%d new to be %t, got %t", tc.file, tc.row, tc.new, got)
+		}
+	}
+
+	for file, changed := range map[string]bool{"p.rego": true, "q.rego": true, "r.rego": true, "s.rego": false} {
+		if got := diff.IsChanged(filepath.Join(dir, file)); got != changed {
+			t.Errorf("expected %s changed to be %t, got %t", file, changed, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package inputdoc finds and reads the input documents of a workspace, i.e. the sample input.json or input.yaml files
+// used by editors to evaluate policies, and by the language server for completions and hover of input.
+package inputdoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	rio "github.com/styrainc/regal/internal/io"
+)
+
+// FileNames are the names of input documents, in order of precedence when more than one is found in a directory.
+var FileNames = []string{"input.json", "input.yaml"} //nolint:gochecknoglobals
+
+// Find returns the path of the input document nearest to dir, i.e. found in dir or its parents up to rootPath. If
+// dir is outside of rootPath, like for files opened from elsewhere, the input document at the root of the workspace
+// is used instead.
+func Find(dir, rootPath string) (string, bool) {
+	dir = filepath.Clean(dir)
+
+	if rootPath != "" {
+		rootPath = filepath.Clean(rootPath)
+
+		if !within(dir, rootPath) {
+			return inDir(rootPath)
+		}
+	}
+
+	for {
+		if path, ok := inDir(dir); ok {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if rootPath == "" || dir == rootPath || parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+// Read returns the contents of the input document at path, decoded as YAML for files with a .yaml or .yml extension,
+// and as JSON otherwise. YAML documents are converted to the values JSON decodes to, like float64 for all numbers, for
+// input to be the same whatever the format.
+func Read(path string) (any, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input document: %w", err)
+	}
+
+	var input any
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		var doc any
+		if err = yaml.Unmarshal(bs, &doc); err == nil {
+			err = rio.JSONRoundTrip(doc, &input)
+		}
+	default:
+		err = json.Unmarshal(bs, &input)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode input document %s: %w", path, err)
+	}
+
+	return input, nil
+}
+
+func inDir(dir string) (string, bool) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,97 @@
+package inputdoc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"input.yaml":               "user: root\n",
+		"policy/authz/input.json":  `{"user": "json"}`,
+		"policy/authz/input.yaml":  "user: yaml\n",
+		"policy/authz/deep/p.rego": "package p\n",
+		"policy/other/p.rego":      "package p\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := map[string]struct {
+		dir      string
+		root     string
+		expected string
+	}{
+		"json preferred in same directory": {dir: "policy/authz", root: root, expected: "policy/authz/input.json"},
+		"nearest parent":                   {dir: "policy/authz/deep", root: root, expected: "policy/authz/input.json"},
+		"workspace root":                   {dir: "policy/other", root: root, expected: "input.yaml"},
+		"outside of workspace": {
+			dir:      "policy/other",
+			root:     filepath.Join(root, "policy", "authz"),
+			expected: "policy/authz/input.json",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path, ok := Find(filepath.Join(root, tc.dir), tc.root)
+			if !ok || path != filepath.Join(root, tc.expected) {
+				t.Errorf("expected %s, got %s (%t)", filepath.Join(root, tc.expected), path, ok)
+			}
+		})
+	}
+
+	if path, ok := Find(t.TempDir(), ""); ok {
+		t.Errorf("expected no input document found, got %s", path)
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "input.json")
+	yamlPath := filepath.Join(dir, "input.yaml")
+
+	if err := os.WriteFile(jsonPath, []byte(`{"user": {"name": "alice", "age": 42}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(yamlPath, []byte("user:\n  name: alice\n  age: 42\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fromJSON, err := Read(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromYAML, err := Read(yamlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("expected input from YAML to equal that from JSON, got %v and %v", fromYAML, fromJSON)
+	}
+
+	if _, err := Read(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error reading missing input document")
+	}
+}
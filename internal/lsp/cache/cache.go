@@ -1,56 +1,186 @@
 package cache
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sync"
 
 	"github.com/open-policy-agent/opa/ast"
 
 	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/pkg/report"
 )
 
+// Limits bound the memory used by the cache. When either limit is exceeded, the parsed modules and diagnostics
+// of files not currently open in the client are evicted, least recently used first. Zero means no limit.
+type Limits struct {
+	// MaxFiles is the maximum number of parsed modules to keep
+	MaxFiles int
+	// MaxBytes is the maximum total size of the contents of files for which parsed modules are kept
+	MaxBytes int
+}
+
 // Cache is used to store: current file contents (which includes unsaved changes), the latest parsed modules, and
 // diagnostics for each file (including diagnostics gathered from linting files alongside other files).
 type Cache struct {
 	// fileContents is a map of file URI to raw file contents received from the client
-	fileContents   map[string]string
-	fileContentsMu sync.Mutex
+	fileContents map[string]string
+	// fileVersions is a map of file URI to the document version of the contents, as sent by the client.
+	// Contents not received from the client (e.g. read from disk) have no version.
+	fileVersions map[string]uint
+	// fileHashes is a map of file URI to the hash of the contents, used to determine whether new contents
+	// received differ from the cached contents
+	fileHashes     map[string]string
+	fileContentsMu sync.RWMutex
 
 	// modules is a map of file URI to parsed AST modules from the latest file contents value
 	modules  map[string]*ast.Module
-	moduleMu sync.Mutex
+	moduleMu sync.RWMutex
 
 	// diagnosticsFile is a map of file URI to diagnostics for that file
 	diagnosticsFile   map[string][]types.Diagnostic
-	diagnosticsFileMu sync.Mutex
+	diagnosticsFileMu sync.RWMutex
 
 	// diagnosticsAggregate is a map of file URI to aggregate diagnostics for that file
 	diagnosticsAggregate   map[string][]types.Diagnostic
-	diagnosticsAggregateMu sync.Mutex
+	diagnosticsAggregateMu sync.RWMutex
 
 	// diagnosticsParseErrors is a map of file URI to parse errors for that file
 	diagnosticsParseErrors map[string][]types.Diagnostic
-	diagnosticsParseMu     sync.Mutex
-
-	builtinPositionsFile map[string]map[uint][]types.BuiltinPosition
-	builtinPositionsMu   sync.Mutex
+	diagnosticsParseMu     sync.RWMutex
+
+	// tests is a map of file URI to the test rules found in that file
+	tests   map[string][]types.TestItem
+	testsMu sync.RWMutex
+
+	// builtinPositions is a map of file URI to the positions of built-in function calls on each line
+	builtinPositions *Store[map[uint][]types.BuiltinPosition]
+	// symbols is a map of file URI to the rule definitions, references and imports found in that file
+	symbols *Store[types.Symbols]
+
+	// KeywordPositions is a map of file URI to the positions of keywords on each line of that file
+	KeywordPositions *Store[map[uint][]types.KeywordPosition]
+	// CodeLenses is a map of file URI to the code lenses computed for that file
+	CodeLenses *Store[[]types.CodeLens]
+	// FoldingRanges is a map of file URI to the folding ranges computed for that file
+	FoldingRanges *Store[[]types.FoldingRange]
+	// SemanticTokens is a map of file URI to the semantic tokens computed for that file
+	SemanticTokens *Store[types.SemanticTokens]
+
+	// AggregateData is a map of file URI to the aggregates collected when linting that file. The aggregate
+	// diagnostics of the workspace are computed from the aggregates of all files, and when a file changes,
+	// only its aggregates need to be collected again.
+	AggregateData *Store[map[string][]report.Aggregate]
+
+	// DataFiles is a map of file URI to the contents of the data files (data.json and data.yaml) of the
+	// workspace, which are not parsed as modules, but validated for DataFileDiagnostics
+	DataFiles *Store[string]
+	// DataFileDiagnostics is a map of file URI to the syntax errors and schema violations found in that data file
+	DataFileDiagnostics *Store[[]types.Diagnostic]
+
+	// PreparedInputs holds the linter input prepared from the contents of each file, which is reused
+	// as long as the contents are unchanged
+	PreparedInputs *parse.PreparedASTCache
+
+	// LintResults holds the results of linting the contents of files, keyed by the contents and the rules
+	// used, which are reused whenever the same contents are linted again with the same rules
+	LintResults *LintResults
+
+	// stores is the list of all stores, from which data is deleted or moved along with the file
+	stores []store
+	// derived is the list of stores holding data computed from the contents or module of a file,
+	// which is invalidated whenever either changes
+	derived []store
+	// evictable is the list of stores from which data is evicted along with the module of a file
+	evictable []store
+
+	limits Limits
+	// openFiles is the set of file URIs currently open in the client, which are never evicted
+	openFiles map[string]bool
+	// lastUsed is a map of file URI to the value of clock when its module was last set or retrieved
+	lastUsed map[string]uint64
+	clock    uint64
+	// evictionMu guards limits, openFiles, lastUsed and clock, and is never acquired while holding any
+	// other lock of the cache
+	evictionMu sync.Mutex
 }
 
 func NewCache() *Cache {
-	return &Cache{
+	c := &Cache{
 		fileContents: make(map[string]string),
+		fileVersions: make(map[string]uint),
+		fileHashes:   make(map[string]string),
 		modules:      make(map[string]*ast.Module),
 
 		diagnosticsFile:        make(map[string][]types.Diagnostic),
 		diagnosticsAggregate:   make(map[string][]types.Diagnostic),
 		diagnosticsParseErrors: make(map[string][]types.Diagnostic),
 
-		builtinPositionsFile: make(map[string]map[uint][]types.BuiltinPosition),
+		tests: make(map[string][]types.TestItem),
+
+		builtinPositions: newStore[map[uint][]types.BuiltinPosition](),
+		symbols:          newStore[types.Symbols](),
+
+		KeywordPositions: newStore[map[uint][]types.KeywordPosition](),
+		CodeLenses:       newStore[[]types.CodeLens](),
+		FoldingRanges:    newStore[[]types.FoldingRange](),
+		SemanticTokens:   newStore[types.SemanticTokens](),
+
+		AggregateData:  newStore[map[string][]report.Aggregate](),
+		PreparedInputs: parse.NewPreparedASTCache(),
+		LintResults:    NewLintResults(DefaultLintResultsSize),
+
+		DataFiles:           newStore[string](),
+		DataFileDiagnostics: newStore[[]types.Diagnostic](),
+
+		openFiles: make(map[string]bool),
+		lastUsed:  make(map[string]uint64),
+	}
+
+	c.derived = []store{c.KeywordPositions, c.CodeLenses, c.FoldingRanges, c.SemanticTokens}
+	c.evictable = append([]store{c.builtinPositions}, c.derived...)
+	c.stores = append([]store{c.symbols, c.AggregateData, c.DataFiles, c.DataFileDiagnostics}, c.evictable...)
+
+	return c
+}
+
+// SetLimits sets the limits for the cache, evicting data right away if the new limits are exceeded.
+func (c *Cache) SetLimits(limits Limits) {
+	c.evictionMu.Lock()
+	c.limits = limits
+	c.evictionMu.Unlock()
+
+	c.evict()
+}
+
+// SetOpen marks the file at uri as open or closed in the client. Data for open files is never evicted.
+func (c *Cache) SetOpen(uri string, open bool) {
+	c.evictionMu.Lock()
+
+	if open {
+		c.openFiles[uri] = true
+	} else {
+		delete(c.openFiles, uri)
+	}
+
+	c.evictionMu.Unlock()
+
+	if !open {
+		c.evict()
 	}
 }
 
 func (c *Cache) GetAllDiagnosticsForURI(uri string) []types.Diagnostic {
+	if dataDiags, ok := c.DataFileDiagnostics.Get(uri); ok {
+		return dataDiags
+	}
+
 	parseDiags, ok := c.GetParseErrors(uri)
 	if ok && len(parseDiags) > 0 {
 		return parseDiags
@@ -71,55 +201,216 @@ func (c *Cache) GetAllDiagnosticsForURI(uri string) []types.Diagnostic {
 	return allDiags
 }
 
+// GetAllFiles returns a snapshot of the contents of all files in the cache. The returned map
+// is a copy, and is safe to iterate while the cache is being updated.
 func (c *Cache) GetAllFiles() map[string]string {
-	c.fileContentsMu.Lock()
-	defer c.fileContentsMu.Unlock()
+	c.fileContentsMu.RLock()
+	defer c.fileContentsMu.RUnlock()
 
-	return c.fileContents
+	return maps.Clone(c.fileContents)
 }
 
 func (c *Cache) GetFileContents(uri string) (string, bool) {
-	c.fileContentsMu.Lock()
-	defer c.fileContentsMu.Unlock()
+	c.fileContentsMu.RLock()
+	defer c.fileContentsMu.RUnlock()
 
 	val, ok := c.fileContents[uri]
 
 	return val, ok
 }
 
+// GetFileHash returns the hash of the cached contents of the file at uri. Callers may compare this to
+// the Hash of new contents to skip work when the contents are unchanged, or use it as a key to reuse
+// results computed for identical contents.
+func (c *Cache) GetFileHash(uri string) (string, bool) {
+	c.fileContentsMu.RLock()
+	defer c.fileContentsMu.RUnlock()
+
+	val, ok := c.fileHashes[uri]
+
+	return val, ok
+}
+
+// HasContents returns whether the cached contents of the file at uri hash identically to content.
+func (c *Cache) HasContents(uri string, content string) bool {
+	hash, ok := c.GetFileHash(uri)
+
+	return ok && hash == Hash(content)
+}
+
+// GetFileContentsAndVersion returns the contents of the file at uri, along with the document version of
+// those contents, or 0 if the contents have no version.
+func (c *Cache) GetFileContentsAndVersion(uri string) (string, uint, bool) {
+	c.fileContentsMu.RLock()
+	defer c.fileContentsMu.RUnlock()
+
+	val, ok := c.fileContents[uri]
+
+	return val, c.fileVersions[uri], ok
+}
+
+// SetFileContents sets the contents of the file at uri, regardless of the version of any previous contents.
+// The version of the file is reset, as the contents set are not known to correspond to any document version.
 func (c *Cache) SetFileContents(uri string, content string) {
 	c.fileContentsMu.Lock()
 	defer c.fileContentsMu.Unlock()
 
+	c.setFileContents(uri, content)
+	delete(c.fileVersions, uri)
+}
+
+// SetFileContentsVersion sets the contents of the file at uri, unless contents of a newer document
+// version have already been set. Returns whether the contents were set.
+func (c *Cache) SetFileContentsVersion(uri string, content string, version uint) bool {
+	c.fileContentsMu.Lock()
+	defer c.fileContentsMu.Unlock()
+
+	if c.fileVersions[uri] > version {
+		return false
+	}
+
+	c.setFileContents(uri, content)
+	c.fileVersions[uri] = version
+
+	return true
+}
+
+// setFileContents sets the contents and hash of the file at uri, invalidating any derived data if the contents
+// have changed. Must be called with the lock on the file contents held.
+func (c *Cache) setFileContents(uri string, content string) {
+	hash := Hash(content)
+	if c.fileHashes[uri] != hash {
+		c.invalidate(uri)
+	}
+
 	c.fileContents[uri] = content
+	c.fileHashes[uri] = hash
 }
 
+// invalidate deletes the data derived from the contents or module of the file at uri.
+func (c *Cache) invalidate(uri string) {
+	for _, s := range c.derived {
+		s.lock()
+		s.deleteLocked(uri)
+		s.unlock()
+	}
+}
+
+// ifCurrentVersion calls set only if no contents newer than version have been set for uri,
+// and returns whether set was called. The lock on the file contents is held while calling set,
+// so that newer contents can't be set in between the check and the update.
+func (c *Cache) ifCurrentVersion(uri string, version uint, set func()) bool {
+	c.fileContentsMu.RLock()
+	defer c.fileContentsMu.RUnlock()
+
+	if c.fileVersions[uri] > version {
+		return false
+	}
+
+	set()
+
+	return true
+}
+
+// GetAllModules returns a snapshot of all parsed modules in the cache. The returned map
+// is a copy, and is safe to iterate while the cache is being updated.
 func (c *Cache) GetAllModules() map[string]*ast.Module {
-	c.moduleMu.Lock()
-	defer c.moduleMu.Unlock()
+	c.moduleMu.RLock()
+	defer c.moduleMu.RUnlock()
 
-	return c.modules
+	return maps.Clone(c.modules)
 }
 
+// GetModule returns the parsed module for the file at uri. Note that modules of files not open in the client
+// may have been evicted, in which case the module must be parsed again from the file contents.
 func (c *Cache) GetModule(uri string) (*ast.Module, bool) {
-	c.moduleMu.Lock()
-	defer c.moduleMu.Unlock()
-
+	c.moduleMu.RLock()
 	val, ok := c.modules[uri]
+	c.moduleMu.RUnlock()
+
+	if ok {
+		c.touch(uri)
+	}
 
 	return val, ok
 }
 
 func (c *Cache) SetModule(uri string, module *ast.Module) {
+	c.setModule(uri, module)
+	c.touch(uri)
+	c.evict()
+}
+
+func (c *Cache) setModule(uri string, module *ast.Module) {
 	c.moduleMu.Lock()
 	defer c.moduleMu.Unlock()
 
 	c.modules[uri] = module
+
+	c.invalidate(uri)
+}
+
+// SetModuleVersion sets the module parsed from the contents of the given document version,
+// unless newer contents have since been set. Returns whether the module was set.
+func (c *Cache) SetModuleVersion(uri string, module *ast.Module, version uint) bool {
+	// eviction must happen outside of ifCurrentVersion, as the eviction lock must never be
+	// acquired while holding the lock on the file contents
+	if !c.ifCurrentVersion(uri, version, func() { c.setModule(uri, module) }) {
+		return false
+	}
+
+	c.touch(uri)
+	c.evict()
+
+	return true
+}
+
+// GetAllDiagnostics returns the diagnostics of all files with any, as they're sent to the client for each file.
+func (c *Cache) GetAllDiagnostics() map[string][]types.Diagnostic {
+	uris := make(map[string]struct{})
+
+	c.diagnosticsFileMu.RLock()
+
+	for uri := range c.diagnosticsFile {
+		uris[uri] = struct{}{}
+	}
+
+	c.diagnosticsFileMu.RUnlock()
+
+	c.diagnosticsAggregateMu.RLock()
+
+	for uri := range c.diagnosticsAggregate {
+		uris[uri] = struct{}{}
+	}
+
+	c.diagnosticsAggregateMu.RUnlock()
+
+	c.diagnosticsParseMu.RLock()
+
+	for uri := range c.diagnosticsParseErrors {
+		uris[uri] = struct{}{}
+	}
+
+	c.diagnosticsParseMu.RUnlock()
+
+	for uri := range c.DataFileDiagnostics.GetAll() {
+		uris[uri] = struct{}{}
+	}
+
+	diags := make(map[string][]types.Diagnostic, len(uris))
+
+	for uri := range uris {
+		if fileDiags := c.GetAllDiagnosticsForURI(uri); len(fileDiags) > 0 {
+			diags[uri] = fileDiags
+		}
+	}
+
+	return diags
 }
 
 func (c *Cache) GetFileDiagnostics(uri string) ([]types.Diagnostic, bool) {
-	c.diagnosticsFileMu.Lock()
-	defer c.diagnosticsFileMu.Unlock()
+	c.diagnosticsFileMu.RLock()
+	defer c.diagnosticsFileMu.RUnlock()
 
 	val, ok := c.diagnosticsFile[uri]
 
@@ -133,6 +424,21 @@ func (c *Cache) SetFileDiagnostics(uri string, diags []types.Diagnostic) {
 	c.diagnosticsFile[uri] = diags
 }
 
+// SetFileDiagnosticsVersion sets the diagnostics from linting the contents of the given document version,
+// unless newer contents have since been set. Returns whether the diagnostics were set.
+func (c *Cache) SetFileDiagnosticsVersion(uri string, diags []types.Diagnostic, version uint) bool {
+	return c.ifCurrentVersion(uri, version, func() { c.SetFileDiagnostics(uri, diags) })
+}
+
+// SetDiagnosticsForFiles sets the diagnostics of many files at once, taking the lock only once. This is
+// preferred over calling SetFileDiagnostics for each file when writing the results of linting a workspace.
+func (c *Cache) SetDiagnosticsForFiles(diags map[string][]types.Diagnostic) {
+	c.diagnosticsFileMu.Lock()
+	defer c.diagnosticsFileMu.Unlock()
+
+	maps.Copy(c.diagnosticsFile, diags)
+}
+
 func (c *Cache) ClearFileDiagnostics() {
 	c.diagnosticsFileMu.Lock()
 	defer c.diagnosticsFileMu.Unlock()
@@ -141,8 +447,8 @@ func (c *Cache) ClearFileDiagnostics() {
 }
 
 func (c *Cache) GetAggregateDiagnostics(uri string) ([]types.Diagnostic, bool) {
-	c.diagnosticsAggregateMu.Lock()
-	defer c.diagnosticsAggregateMu.Unlock()
+	c.diagnosticsAggregateMu.RLock()
+	defer c.diagnosticsAggregateMu.RUnlock()
 
 	val, ok := c.diagnosticsAggregate[uri]
 
@@ -156,6 +462,14 @@ func (c *Cache) SetAggregateDiagnostics(uri string, diags []types.Diagnostic) {
 	c.diagnosticsAggregate[uri] = diags
 }
 
+// SetAggregateDiagnosticsForFiles sets the aggregate diagnostics of many files at once, taking the lock only once.
+func (c *Cache) SetAggregateDiagnosticsForFiles(diags map[string][]types.Diagnostic) {
+	c.diagnosticsAggregateMu.Lock()
+	defer c.diagnosticsAggregateMu.Unlock()
+
+	maps.Copy(c.diagnosticsAggregate, diags)
+}
+
 func (c *Cache) ClearAggregateDiagnostics() {
 	c.diagnosticsAggregateMu.Lock()
 	defer c.diagnosticsAggregateMu.Unlock()
@@ -164,8 +478,8 @@ func (c *Cache) ClearAggregateDiagnostics() {
 }
 
 func (c *Cache) GetParseErrors(uri string) ([]types.Diagnostic, bool) {
-	c.diagnosticsParseMu.Lock()
-	defer c.diagnosticsParseMu.Unlock()
+	c.diagnosticsParseMu.RLock()
+	defer c.diagnosticsParseMu.RUnlock()
 
 	val, ok := c.diagnosticsParseErrors[uri]
 
@@ -179,33 +493,71 @@ func (c *Cache) SetParseErrors(uri string, diags []types.Diagnostic) {
 	c.diagnosticsParseErrors[uri] = diags
 }
 
+// SetParseErrorsVersion sets the errors from parsing the contents of the given document version,
+// unless newer contents have since been set. Returns whether the errors were set.
+func (c *Cache) SetParseErrorsVersion(uri string, diags []types.Diagnostic, version uint) bool {
+	return c.ifCurrentVersion(uri, version, func() { c.SetParseErrors(uri, diags) })
+}
+
 func (c *Cache) GetBuiltinPositions(uri string) (map[uint][]types.BuiltinPosition, bool) {
-	c.builtinPositionsMu.Lock()
-	defer c.builtinPositionsMu.Unlock()
+	return c.builtinPositions.Get(uri)
+}
 
-	val, ok := c.builtinPositionsFile[uri]
+func (c *Cache) SetBuiltinPositions(uri string, positions map[uint][]types.BuiltinPosition) {
+	c.builtinPositions.Set(uri, positions)
+}
+
+// GetAllBuiltInPositions returns a snapshot of the built-in function positions of all files
+// in the cache. The returned map is a copy, and is safe to iterate while the cache is being updated.
+func (c *Cache) GetAllBuiltInPositions() map[string]map[uint][]types.BuiltinPosition {
+	return c.builtinPositions.GetAll()
+}
+
+func (c *Cache) GetTests(uri string) ([]types.TestItem, bool) {
+	c.testsMu.RLock()
+	defer c.testsMu.RUnlock()
+
+	val, ok := c.tests[uri]
 
 	return val, ok
 }
 
-func (c *Cache) SetBuiltinPositions(uri string, positions map[uint][]types.BuiltinPosition) {
-	c.builtinPositionsMu.Lock()
-	defer c.builtinPositionsMu.Unlock()
+func (c *Cache) SetTests(uri string, tests []types.TestItem) {
+	c.testsMu.Lock()
+	defer c.testsMu.Unlock()
 
-	c.builtinPositionsFile[uri] = positions
+	c.tests[uri] = tests
 }
 
-func (c *Cache) GetAllBuiltInPositions() map[string]map[uint][]types.BuiltinPosition {
-	c.builtinPositionsMu.Lock()
-	defer c.builtinPositionsMu.Unlock()
+// GetAllTests returns a snapshot of the tests found in all files in the cache. The returned map
+// is a copy, and is safe to iterate while the cache is being updated.
+func (c *Cache) GetAllTests() map[string][]types.TestItem {
+	c.testsMu.RLock()
+	defer c.testsMu.RUnlock()
+
+	return maps.Clone(c.tests)
+}
 
-	return c.builtinPositionsFile
+func (c *Cache) GetSymbols(uri string) (types.Symbols, bool) {
+	return c.symbols.Get(uri)
+}
+
+func (c *Cache) SetSymbols(uri string, symbols types.Symbols) {
+	c.symbols.Set(uri, symbols)
+}
+
+// GetAllSymbols returns a snapshot of the symbols of all files in the cache. The returned map
+// is a copy, and is safe to iterate while the cache is being updated.
+func (c *Cache) GetAllSymbols() map[string]types.Symbols {
+	return c.symbols.GetAll()
 }
 
 // Delete removes all cached data for a given URI.
 func (c *Cache) Delete(uri string) {
 	c.fileContentsMu.Lock()
 	delete(c.fileContents, uri)
+	delete(c.fileVersions, uri)
+	delete(c.fileHashes, uri)
 	c.fileContentsMu.Unlock()
 
 	c.moduleMu.Lock()
@@ -224,9 +576,177 @@ func (c *Cache) Delete(uri string) {
 	delete(c.diagnosticsParseErrors, uri)
 	c.diagnosticsParseMu.Unlock()
 
-	c.builtinPositionsMu.Lock()
-	delete(c.builtinPositionsFile, uri)
-	c.builtinPositionsMu.Unlock()
+	c.testsMu.Lock()
+	delete(c.tests, uri)
+	c.testsMu.Unlock()
+
+	for _, s := range c.stores {
+		s.lock()
+		s.deleteLocked(uri)
+		s.unlock()
+	}
+
+	c.PreparedInputs.Delete(uri)
+
+	c.evictionMu.Lock()
+	delete(c.openFiles, uri)
+	delete(c.lastUsed, uri)
+	c.evictionMu.Unlock()
+}
+
+// Rename moves all cached data for oldURI to newURI, replacing any data cached for newURI. All locks are
+// held for the duration of the move, so that no reader observes the file under both, or neither, URI.
+// Returns false if there were no contents cached for oldURI, in which case nothing is moved.
+func (c *Cache) Rename(oldURI, newURI string) bool {
+	// the eviction lock is taken first, like in evict, for the two not to wait on each other
+	c.evictionMu.Lock()
+	defer c.evictionMu.Unlock()
+
+	c.fileContentsMu.Lock()
+	defer c.fileContentsMu.Unlock()
+
+	c.moduleMu.Lock()
+	defer c.moduleMu.Unlock()
+
+	c.diagnosticsFileMu.Lock()
+	defer c.diagnosticsFileMu.Unlock()
+
+	c.diagnosticsAggregateMu.Lock()
+	defer c.diagnosticsAggregateMu.Unlock()
+
+	c.diagnosticsParseMu.Lock()
+	defer c.diagnosticsParseMu.Unlock()
+
+	c.testsMu.Lock()
+	defer c.testsMu.Unlock()
+
+	for _, s := range c.stores {
+		s.lock()
+		defer s.unlock()
+	}
+
+	if _, ok := c.fileContents[oldURI]; !ok {
+		return false
+	}
+
+	move(c.fileContents, oldURI, newURI)
+	move(c.fileVersions, oldURI, newURI)
+	move(c.fileHashes, oldURI, newURI)
+	move(c.modules, oldURI, newURI)
+	move(c.diagnosticsFile, oldURI, newURI)
+	move(c.diagnosticsAggregate, oldURI, newURI)
+	move(c.diagnosticsParseErrors, oldURI, newURI)
+
+	// tests are not moved, but discovered again for the new URI once parsed
+	delete(c.tests, oldURI)
+	delete(c.tests, newURI)
+
+	for _, s := range c.stores {
+		s.moveLocked(oldURI, newURI)
+	}
+
+	// the prepared input includes the name of the file, and is prepared again for the new URI
+	c.PreparedInputs.Delete(oldURI)
+	c.PreparedInputs.Delete(newURI)
+
+	move(c.openFiles, oldURI, newURI)
+	move(c.lastUsed, oldURI, newURI)
+
+	return true
+}
+
+// touch marks the module of the file at uri as used, moving it last in line for eviction.
+func (c *Cache) touch(uri string) {
+	c.evictionMu.Lock()
+	defer c.evictionMu.Unlock()
+
+	c.clock++
+	c.lastUsed[uri] = c.clock
+}
+
+// evict removes the parsed modules and evictable data of the least recently used files not open
+// in the client, until the cache is within its limits. Diagnostics are kept, as they're reported
+// for the whole workspace, and not derived again when evicted modules are parsed.
+func (c *Cache) evict() {
+	c.evictionMu.Lock()
+	defer c.evictionMu.Unlock()
+
+	if c.limits.MaxFiles <= 0 && c.limits.MaxBytes <= 0 {
+		return
+	}
+
+	c.fileContentsMu.RLock()
+	c.moduleMu.RLock()
+
+	files, size := len(c.modules), 0
+	candidates := make([]string, 0)
+
+	for uri := range c.modules {
+		size += len(c.fileContents[uri])
+
+		if !c.openFiles[uri] {
+			candidates = append(candidates, uri)
+		}
+	}
+
+	sizes := make(map[string]int, len(candidates))
+	for _, uri := range candidates {
+		sizes[uri] = len(c.fileContents[uri])
+	}
+
+	c.moduleMu.RUnlock()
+	c.fileContentsMu.RUnlock()
+
+	exceeded := func() bool {
+		return (c.limits.MaxFiles > 0 && files > c.limits.MaxFiles) ||
+			(c.limits.MaxBytes > 0 && size > c.limits.MaxBytes)
+	}
+
+	if !exceeded() {
+		return
+	}
+
+	slices.SortFunc(candidates, func(a, b string) int {
+		return cmp.Compare(c.lastUsed[a], c.lastUsed[b])
+	})
+
+	evicted := make([]string, 0)
+
+	for _, uri := range candidates {
+		if !exceeded() {
+			break
+		}
+
+		evicted = append(evicted, uri)
+		files--
+		size -= sizes[uri]
+	}
+
+	c.moduleMu.Lock()
+
+	for _, uri := range evicted {
+		delete(c.modules, uri)
+		delete(c.lastUsed, uri)
+
+		c.PreparedInputs.Delete(uri)
+
+		for _, s := range c.evictable {
+			s.lock()
+			s.deleteLocked(uri)
+			s.unlock()
+		}
+	}
+
+	c.moduleMu.Unlock()
+}
+
+func move[V any](m map[string]V, from, to string) {
+	if val, ok := m[from]; ok {
+		m[to] = val
+		delete(m, from)
+	} else {
+		delete(m, to)
+	}
 }
 
 func UpdateCacheForURIFromDisk(cache *Cache, uri, path string) (string, error) {
@@ -237,12 +757,18 @@ func UpdateCacheForURIFromDisk(cache *Cache, uri, path string) (string, error) {
 
 	currentContent := string(content)
 
-	cachedContent, ok := cache.GetFileContents(uri)
-	if ok && cachedContent == currentContent {
-		return cachedContent, nil
+	if cache.HasContents(uri, currentContent) {
+		return currentContent, nil
 	}
 
 	cache.SetFileContents(uri, currentContent)
 
 	return currentContent, nil
 }
+
+// Hash returns the hash of the provided file contents.
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestGetAllReturnsSnapshots(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+
+	c.SetFileContents("file:///a.rego", "package a")
+	c.SetModule("file:///a.rego", ast.MustParseModule("package a"))
+
+	files := c.GetAllFiles()
+	modules := c.GetAllModules()
+
+	c.SetFileContents("file:///b.rego", "package b")
+	c.SetModule("file:///b.rego", ast.MustParseModule("package b"))
+	c.Delete("file:///a.rego")
+
+	if len(files) != 1 || files["file:///a.rego"] != "package a" {
+		t.Errorf("expected snapshot of files to be unaffected by later updates, got %v", files)
+	}
+
+	if _, ok := modules["file:///a.rego"]; !ok || len(modules) != 1 {
+		t.Errorf("expected snapshot of modules to be unaffected by later updates, got %v", modules)
+	}
+
+	delete(files, "file:///a.rego")
+
+	if len(c.GetAllFiles()) != 1 {
+		t.Errorf("expected cache to be unaffected by changes to snapshot")
+	}
+}
+
+func TestConcurrentIterationAndUpdates(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				c.SetFileContents("file:///"+strconv.Itoa(i)+"/"+strconv.Itoa(j)+".rego", "package p")
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				for uri, contents := range c.GetAllFiles() {
+					if contents != "package p" {
+						t.Errorf("unexpected contents for %s: %s", uri, contents)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := len(c.GetAllFiles()); n != 1000 {
+		t.Errorf("expected 1000 files, got %d", n)
+	}
+}
+
+func TestVersionedUpdates(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+	uri := "file:///p.rego"
+
+	if !c.SetFileContentsVersion(uri, "package p # v2", 2) {
+		t.Fatalf("expected contents of version 2 to be set")
+	}
+
+	if c.SetFileContentsVersion(uri, "package p # v1", 1) {
+		t.Errorf("expected contents of older version 1 to be ignored")
+	}
+
+	contents, version, _ := c.GetFileContentsAndVersion(uri)
+	if contents != "package p # v2" || version != 2 {
+		t.Errorf("expected contents and version of v2, got %q (%d)", contents, version)
+	}
+
+	if c.SetModuleVersion(uri, ast.MustParseModule("package p"), 1) {
+		t.Errorf("expected module parsed from older version to be ignored")
+	}
+
+	if _, ok := c.GetModule(uri); ok {
+		t.Errorf("expected no module to be set")
+	}
+
+	if !c.SetFileDiagnosticsVersion(uri, nil, 2) {
+		t.Errorf("expected diagnostics for current version to be set")
+	}
+
+	c.SetFileContents(uri, "package p # from disk")
+
+	if _, version, _ = c.GetFileContentsAndVersion(uri); version != 0 {
+		t.Errorf("expected version to be reset for unversioned contents, got %d", version)
+	}
+}
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+
+	c.SetFileContentsVersion("file:///old.rego", "package p", 3)
+	c.SetModule("file:///old.rego", ast.MustParseModule("package p"))
+	c.SetFileDiagnostics("file:///old.rego", []types.Diagnostic{{Code: "opa-fmt"}})
+	c.SetBuiltinPositions("file:///old.rego", map[uint][]types.BuiltinPosition{1: {}})
+
+	if !c.Rename("file:///old.rego", "file:///new.rego") {
+		t.Fatalf("expected rename to succeed")
+	}
+
+	if _, ok := c.GetFileContents("file:///old.rego"); ok {
+		t.Errorf("expected no contents for old URI")
+	}
+
+	if _, ok := c.GetModule("file:///old.rego"); ok {
+		t.Errorf("expected no module for old URI")
+	}
+
+	contents, version, ok := c.GetFileContentsAndVersion("file:///new.rego")
+	if !ok || contents != "package p" || version != 3 {
+		t.Errorf("expected contents and version to be moved, got %q (%d)", contents, version)
+	}
+
+	if _, ok := c.GetModule("file:///new.rego"); !ok {
+		t.Errorf("expected module to be moved")
+	}
+
+	if diags, _ := c.GetFileDiagnostics("file:///new.rego"); len(diags) != 1 {
+		t.Errorf("expected diagnostics to be moved, got %v", diags)
+	}
+
+	if _, ok := c.GetBuiltinPositions("file:///new.rego"); !ok {
+		t.Errorf("expected builtin positions to be moved")
+	}
+
+	if c.Rename("file:///unknown.rego", "file:///other.rego") {
+		t.Errorf("expected rename of unknown URI to fail")
+	}
+}
+
+func TestFileHash(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+	uri := "file:///p.rego"
+
+	if c.HasContents(uri, "package p") {
+		t.Errorf("expected no contents for unknown file")
+	}
+
+	c.SetFileContents(uri, "package p")
+
+	hash, ok := c.GetFileHash(uri)
+	if !ok || hash != Hash("package p") {
+		t.Errorf("expected hash of contents to be cached, got %q", hash)
+	}
+
+	if !c.HasContents(uri, "package p") {
+		t.Errorf("expected identical contents to be detected")
+	}
+
+	c.SetFileContentsVersion(uri, "package q", 1)
+
+	if c.HasContents(uri, "package p") {
+		t.Errorf("expected hash to be updated with contents")
+	}
+
+	c.Delete(uri)
+
+	if _, ok := c.GetFileHash(uri); ok {
+		t.Errorf("expected hash to be deleted along with contents")
+	}
+}
+
+func TestEviction(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+	c.SetLimits(Limits{MaxFiles: 2})
+
+	for _, name := range []string{"a", "b", "c"} {
+		uri := "file:///" + name + ".rego"
+
+		c.SetFileContents(uri, "package "+name)
+		c.SetFileDiagnostics(uri, []types.Diagnostic{{Code: "opa-fmt"}})
+	}
+
+	c.SetOpen("file:///a.rego", true)
+
+	c.SetModule("file:///a.rego", ast.MustParseModule("package a"))
+	c.SetModule("file:///b.rego", ast.MustParseModule("package b"))
+	c.SetModule("file:///c.rego", ast.MustParseModule("package c"))
+
+	if _, ok := c.GetModule("file:///b.rego"); ok {
+		t.Errorf("expected least recently used closed file to be evicted")
+	}
+
+	if diags, ok := c.GetFileDiagnostics("file:///b.rego"); !ok || len(diags) != 1 {
+		t.Errorf("expected diagnostics of evicted file to be kept, got %v", diags)
+	}
+
+	if all := c.GetAllDiagnostics(); len(all["file:///b.rego"]) != 1 {
+		t.Errorf("expected diagnostics of evicted file in all diagnostics, got %v", all)
+	}
+
+	if _, ok := c.GetFileContents("file:///b.rego"); !ok {
+		t.Errorf("expected contents of evicted file to be kept")
+	}
+
+	for _, uri := range []string{"file:///a.rego", "file:///c.rego"} {
+		if _, ok := c.GetModule(uri); !ok {
+			t.Errorf("expected module for %s to be kept", uri)
+		}
+	}
+
+	// closing a.rego makes it the least recently used candidate for eviction
+	c.SetOpen("file:///a.rego", false)
+	c.GetModule("file:///c.rego")
+	c.SetModule("file:///b.rego", ast.MustParseModule("package b"))
+
+	if _, ok := c.GetModule("file:///a.rego"); ok {
+		t.Errorf("expected closed file to be evicted")
+	}
+}
+
+func TestConcurrentRenameAndEviction(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+	c.SetLimits(Limits{MaxFiles: 2})
+
+	c.SetFileContents("file:///a.rego", "package a")
+	c.SetModule("file:///a.rego", ast.MustParseModule("package a"))
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for range 10000 {
+			c.Rename("file:///a.rego", "file:///b.rego")
+			c.Rename("file:///b.rego", "file:///a.rego")
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		module := ast.MustParseModule("package c")
+
+		for i := range 10000 {
+			uri := "file:///c" + strconv.Itoa(i%10) + ".rego"
+
+			c.SetFileContents(uri, "package c")
+			c.SetModule(uri, module)
+		}
+	}()
+
+	wg.Wait()
+
+	if n := len(c.GetAllModules()); n > 2 {
+		t.Errorf("expected at most 2 modules after eviction, got %d", n)
+	}
+}
+
+func TestEvictionMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+	c.SetLimits(Limits{MaxBytes: 20})
+
+	c.SetFileContents("file:///a.rego", "package a # 1234567")
+	c.SetModule("file:///a.rego", ast.MustParseModule("package a"))
+
+	c.SetFileContents("file:///b.rego", "package b")
+	c.SetModule("file:///b.rego", ast.MustParseModule("package b"))
+
+	if _, ok := c.GetModule("file:///a.rego"); ok {
+		t.Errorf("expected file to be evicted when exceeding max bytes")
+	}
+
+	if _, ok := c.GetModule("file:///b.rego"); !ok {
+		t.Errorf("expected most recently used file to be kept")
+	}
+}
+
+func TestBatchSetters(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+
+	c.SetFileDiagnostics("file:///a.rego", []types.Diagnostic{{Code: "opa-fmt"}})
+
+	c.SetDiagnosticsForFiles(map[string][]types.Diagnostic{
+		"file:///b.rego": {{Code: "use-rego-v1"}},
+		"file:///c.rego": {},
+	})
+	c.SetAggregateDiagnosticsForFiles(map[string][]types.Diagnostic{
+		"file:///b.rego": {{Code: "prefer-package-imports"}},
+	})
+
+	if diags, _ := c.GetFileDiagnostics("file:///a.rego"); len(diags) != 1 {
+		t.Errorf("expected diagnostics of files not in batch to be kept, got %v", diags)
+	}
+
+	if diags, _ := c.GetFileDiagnostics("file:///b.rego"); len(diags) != 1 || diags[0].Code != "use-rego-v1" {
+		t.Errorf("expected diagnostics to be set, got %v", diags)
+	}
+
+	if diags, _ := c.GetAggregateDiagnostics("file:///b.rego"); len(diags) != 1 {
+		t.Errorf("expected aggregate diagnostics to be set, got %v", diags)
+	}
+}
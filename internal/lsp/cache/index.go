@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+// indexFormatVersion is incremented whenever the format of the index changes, invalidating existing indexes.
+const indexFormatVersion = 2
+
+// Index is the persisted form of the data cached for a workspace: the symbols and tests found in each file, and the
+// aggregates and diagnostics found when linting it. It is saved after linting the workspace, and loaded when the
+// language server starts, so that definitions and diagnostics are available before the whole workspace has been
+// parsed and linted again.
+type Index struct {
+	FormatVersion int `json:"formatVersion"`
+	// RegalVersion is the version of Regal that produced the index, as the data may differ between versions
+	RegalVersion string `json:"regalVersion"`
+	// ConfigHash is the hash of the configuration and custom rules used when linting
+	ConfigHash string `json:"configHash"`
+	// Files is a map of file URI to the data cached for that file
+	Files map[string]IndexEntry `json:"files"`
+}
+
+type IndexEntry struct {
+	// Hash is the hash of the file contents the data was produced from
+	Hash string `json:"hash"`
+	// Symbols and Tests are found when parsing the file, and are only set for files which parsed
+	Symbols *types.Symbols   `json:"symbols,omitempty"`
+	Tests   []types.TestItem `json:"tests,omitempty"`
+	// Aggregates are those collected when linting the file, for aggregate diagnostics to be recomputed
+	Aggregates           map[string][]report.Aggregate `json:"aggregates,omitempty"`
+	Diagnostics          []types.Diagnostic            `json:"diagnostics,omitempty"`
+	AggregateDiagnostics []types.Diagnostic            `json:"aggregateDiagnostics,omitempty"`
+}
+
+// Index returns an index of the data currently cached for all files.
+func (c *Cache) Index(regalVersion, configHash string) *Index {
+	index := &Index{
+		FormatVersion: indexFormatVersion,
+		RegalVersion:  regalVersion,
+		ConfigHash:    configHash,
+		Files:         make(map[string]IndexEntry),
+	}
+
+	for uri := range c.GetAllFiles() {
+		hash, ok := c.GetFileHash(uri)
+		if !ok {
+			continue
+		}
+
+		entry := IndexEntry{Hash: hash}
+
+		if parseErrors, ok := c.GetParseErrors(uri); !ok || len(parseErrors) == 0 {
+			if symbols, ok := c.GetSymbols(uri); ok {
+				entry.Symbols = &symbols
+				entry.Tests, _ = c.GetTests(uri)
+			}
+		}
+
+		entry.Aggregates, _ = c.AggregateData.Get(uri)
+		entry.Diagnostics, _ = c.GetFileDiagnostics(uri)
+		entry.AggregateDiagnostics, _ = c.GetAggregateDiagnostics(uri)
+
+		index.Files[uri] = entry
+	}
+
+	return index
+}
+
+// RestoreParsed restores the symbols and tests of the file at uri from the index, if its contents are unchanged
+// since the index was saved, for the file not to be parsed when loading the workspace. As these depend only on the
+// contents of the file, the configuration isn't checked. The module of the file is then parsed when needed, like
+// that of a file evicted from the cache. Returns whether the symbols and tests were restored.
+func (c *Cache) RestoreParsed(index *Index, regalVersion, uri string) bool {
+	if index == nil || index.FormatVersion != indexFormatVersion || index.RegalVersion != regalVersion {
+		return false
+	}
+
+	entry, ok := index.Files[uri]
+	if !ok || entry.Symbols == nil {
+		return false
+	}
+
+	if hash, ok := c.GetFileHash(uri); !ok || hash != entry.Hash {
+		return false
+	}
+
+	c.SetSymbols(uri, *entry.Symbols)
+	c.SetTests(uri, entry.Tests)
+
+	return true
+}
+
+// RestoreIndex restores the aggregates and diagnostics from the index for all files whose contents are unchanged
+// since the index was saved. As aggregate diagnostics depend on the contents of all files, these are only restored
+// if no file in the workspace has changed. Returns whether any diagnostics were restored.
+func (c *Cache) RestoreIndex(index *Index, regalVersion, configHash string) bool {
+	if index == nil || index.FormatVersion != indexFormatVersion ||
+		index.RegalVersion != regalVersion || index.ConfigHash != configHash {
+		return false
+	}
+
+	files := c.GetAllFiles()
+	unchanged := make([]string, 0, len(files))
+
+	for uri := range files {
+		hash, ok := c.GetFileHash(uri)
+		if entry, found := index.Files[uri]; ok && found && entry.Hash == hash {
+			unchanged = append(unchanged, uri)
+		}
+	}
+
+	aggregates := make(map[string]map[string][]report.Aggregate, len(unchanged))
+	fileDiags := make(map[string][]types.Diagnostic, len(unchanged))
+	aggDiags := make(map[string][]types.Diagnostic, len(unchanged))
+
+	for _, uri := range unchanged {
+		if index.Files[uri].Aggregates != nil {
+			aggregates[uri] = index.Files[uri].Aggregates
+		}
+
+		fileDiags[uri] = index.Files[uri].Diagnostics
+		aggDiags[uri] = index.Files[uri].AggregateDiagnostics
+	}
+
+	c.AggregateData.SetForFiles(aggregates)
+	c.SetDiagnosticsForFiles(fileDiags)
+
+	if len(unchanged) == len(files) && len(files) == len(index.Files) {
+		c.SetAggregateDiagnosticsForFiles(aggDiags)
+	}
+
+	return len(unchanged) > 0
+}
+
+// LoadIndex reads an index previously saved to path.
+func LoadIndex(path string) (*Index, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var index Index
+	if err := json.Unmarshal(bs, &index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal index: %w", err)
+	}
+
+	return &index, nil
+}
+
+// SaveIndex writes the index to path, creating any missing directories. The index is first written to a
+// temporary file, which is then renamed, so that a concurrently starting server never reads a partial index.
+func SaveIndex(path string, index *Index) error {
+	bs, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index file: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bs); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close index file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move index into place: %w", err)
+	}
+
+	return nil
+}
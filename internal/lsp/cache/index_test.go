@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+func TestIndexSaveAndRestore(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+
+	c.SetFileContents("file:///a.rego", "package a")
+	c.SetSymbols("file:///a.rego", types.Symbols{Definitions: []types.Symbol{{Name: "data.a.allow"}}})
+	c.SetTests("file:///a.rego", []types.TestItem{{ID: "data.a.test_allow"}})
+	c.AggregateData.Set("file:///a.rego", map[string][]report.Aggregate{"imports/prefer-package-imports": {{}}})
+	c.SetFileDiagnostics("file:///a.rego", []types.Diagnostic{{Code: "opa-fmt"}})
+	c.SetAggregateDiagnostics("file:///a.rego", []types.Diagnostic{{Code: "prefer-package-imports"}})
+
+	c.SetFileContents("file:///b.rego", "package b")
+	c.SetFileDiagnostics("file:///b.rego", []types.Diagnostic{{Code: "use-rego-v1"}})
+
+	path := filepath.Join(t.TempDir(), "index", "workspace.json")
+
+	if err := SaveIndex(path, c.Index("v1.0.0", "config")); err != nil {
+		t.Fatalf("failed to save index: %s", err)
+	}
+
+	index, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("failed to load index: %s", err)
+	}
+
+	// b.rego has changed since the index was saved
+	restored := NewCache()
+	restored.SetFileContents("file:///a.rego", "package a")
+	restored.SetFileContents("file:///b.rego", "package b\n\nallow := true")
+
+	if restored.RestoreParsed(index, "v1.0.1", "file:///a.rego") {
+		t.Errorf("expected symbols from other Regal version not to be restored")
+	}
+
+	if restored.RestoreParsed(index, "v1.0.0", "file:///b.rego") {
+		t.Errorf("expected symbols of changed file not to be restored")
+	}
+
+	if !restored.RestoreParsed(index, "v1.0.0", "file:///a.rego") {
+		t.Fatalf("expected symbols of unchanged file to be restored")
+	}
+
+	if symbols, _ := restored.GetSymbols("file:///a.rego"); len(symbols.Definitions) != 1 {
+		t.Errorf("expected symbols of unchanged file to be restored, got %v", symbols)
+	}
+
+	if tests, _ := restored.GetTests("file:///a.rego"); len(tests) != 1 || tests[0].ID != "data.a.test_allow" {
+		t.Errorf("expected tests of unchanged file to be restored, got %v", tests)
+	}
+
+	if restored.RestoreIndex(index, "v1.0.1", "config") {
+		t.Errorf("expected index from other Regal version not to be restored")
+	}
+
+	if restored.RestoreIndex(index, "v1.0.0", "other config") {
+		t.Errorf("expected index for other config not to be restored")
+	}
+
+	if !restored.RestoreIndex(index, "v1.0.0", "config") {
+		t.Fatalf("expected index to be restored")
+	}
+
+	if diags, _ := restored.GetFileDiagnostics("file:///a.rego"); len(diags) != 1 || diags[0].Code != "opa-fmt" {
+		t.Errorf("expected diagnostics of unchanged file to be restored, got %v", diags)
+	}
+
+	if aggregates, _ := restored.AggregateData.Get("file:///a.rego"); len(aggregates) != 1 {
+		t.Errorf("expected aggregates of unchanged file to be restored, got %v", aggregates)
+	}
+
+	if diags, ok := restored.GetFileDiagnostics("file:///b.rego"); ok {
+		t.Errorf("expected no diagnostics to be restored for changed file, got %v", diags)
+	}
+
+	if diags, ok := restored.GetAggregateDiagnostics("file:///a.rego"); ok {
+		t.Errorf("expected no aggregate diagnostics to be restored when any file changed, got %v", diags)
+	}
+
+	unchanged := NewCache()
+	unchanged.SetFileContents("file:///a.rego", "package a")
+	unchanged.SetFileContents("file:///b.rego", "package b")
+
+	unchanged.RestoreIndex(index, "v1.0.0", "config")
+
+	if diags, _ := unchanged.GetAggregateDiagnostics("file:///a.rego"); len(diags) != 1 {
+		t.Errorf("expected aggregate diagnostics to be restored when no file changed, got %v", diags)
+	}
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/styrainc/regal/internal/metrics"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+// DefaultLintResultsSize is the number of results kept by the LintResults cache of the language server.
+const DefaultLintResultsSize = 512
+
+// LintResultKey identifies the result of linting the contents of a file with a set of rules.
+type LintResultKey [sha256.Size]byte
+
+// NewLintResultKey returns the key for the result of linting the contents of the file with URI, using the set of
+// rules (and their configuration) identified by ruleSet. The URI is part of the key, as the results of some rules
+// depend on the name of the file, and as locations of violations include it.
+func NewLintResultKey(ruleSet [sha256.Size]byte, uri, contents string) LintResultKey {
+	h := sha256.New()
+
+	h.Write(ruleSet[:])
+	h.Write([]byte(uri))
+	h.Write([]byte{0})
+	h.Write([]byte(contents))
+
+	return LintResultKey(h.Sum(nil))
+}
+
+// LintResults memoizes the results of linting files, keyed by the contents of each file and the rules used to lint
+// it, so that contents linted before, like when switching between files or undoing an edit, are not linted again.
+// Unlike the other data cached, results are kept for any contents seen, rather than only the latest contents of
+// each file, up to a limit on the number of results, beyond which the least recently used are evicted.
+// LintResults is safe for concurrent use.
+type LintResults struct {
+	entries map[LintResultKey]*list.Element
+	// order holds the entries from the most to the least recently used
+	order *list.List
+	size  int
+	mu    sync.Mutex
+	stats metrics.CacheStats
+}
+
+type lintResult struct {
+	key    LintResultKey
+	report report.Report
+}
+
+// NewLintResults returns a LintResults cache keeping at most size results.
+func NewLintResults(size int) *LintResults {
+	return &LintResults{
+		entries: make(map[LintResultKey]*list.Element),
+		order:   list.New(),
+		size:    size,
+	}
+}
+
+// Get returns the result previously stored for the key, if any. The returned report is shared, and must not be
+// modified.
+func (c *LintResults) Get(key LintResultKey) (report.Report, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+
+	c.stats.Record(ok)
+
+	if !ok {
+		return report.Report{}, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*lintResult).report, true //nolint:forcetypeassert
+}
+
+// Set stores the result for the key, evicting the least recently used result if the cache is full.
+func (c *LintResults) Set(key LintResultKey, rpt report.Report) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lintResult).report = rpt //nolint:forcetypeassert
+		c.order.MoveToFront(elem)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lintResult{key: key, report: rpt})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lintResult).key) //nolint:forcetypeassert
+	}
+}
+
+// Len returns the number of results currently cached.
+func (c *LintResults) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats returns the number of lookups in the cache, by whether a result was found.
+func (c *LintResults) Stats() *metrics.CacheStats {
+	return &c.stats
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/styrainc/regal/pkg/report"
+)
+
+func TestLintResults(t *testing.T) {
+	t.Parallel()
+
+	results := NewLintResults(2)
+	ruleSet := sha256.Sum256([]byte("rules"))
+
+	a := NewLintResultKey(ruleSet, "file:///p.rego", "package a")
+	b := NewLintResultKey(ruleSet, "file:///p.rego", "package b")
+	c := NewLintResultKey(ruleSet, "file:///p.rego", "package c")
+
+	if a == NewLintResultKey(sha256.Sum256([]byte("other")), "file:///p.rego", "package a") {
+		t.Fatalf("expected keys to differ by rule set")
+	}
+
+	if a == NewLintResultKey(ruleSet, "file:///q.rego", "package a") {
+		t.Fatalf("expected keys to differ by URI")
+	}
+
+	if _, ok := results.Get(a); ok {
+		t.Fatalf("expected no result before set")
+	}
+
+	results.Set(a, report.Report{Violations: []report.Violation{{Title: "a"}}})
+	results.Set(b, report.Report{Violations: []report.Violation{{Title: "b"}}})
+
+	// a is now the most recently used, so b is evicted when c is added
+	if rpt, ok := results.Get(a); !ok || rpt.Violations[0].Title != "a" {
+		t.Fatalf("expected result for a, got %v", rpt)
+	}
+
+	results.Set(c, report.Report{})
+
+	if _, ok := results.Get(b); ok {
+		t.Errorf("expected b to be evicted")
+	}
+
+	if _, ok := results.Get(a); !ok {
+		t.Errorf("expected a to be kept")
+	}
+
+	if results.Len() != 2 {
+		t.Errorf("expected 2 results, got %d", results.Len())
+	}
+
+	if hits, misses := results.Stats().Hits(), results.Stats().Misses(); hits != 2 || misses != 2 {
+		t.Errorf("expected 2 hits and 2 misses, got %d and %d", hits, misses)
+	}
+}
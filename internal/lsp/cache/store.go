@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"maps"
+	"sync"
+)
+
+// Store is a map of file URI to data of type V computed for that file, safe for concurrent use.
+// Features of the language server keep their per-file data in a Store created by the cache, which
+// takes care of removing, moving and invalidating the data along with the file it belongs to.
+type Store[V any] struct {
+	data map[string]V
+	mu   sync.RWMutex
+}
+
+// store is implemented by Store for any V, allowing the cache to manage all stores alike.
+type store interface {
+	lock()
+	unlock()
+	deleteLocked(uri string)
+	moveLocked(from, to string)
+}
+
+func newStore[V any]() *Store[V] {
+	return &Store[V]{data: make(map[string]V)}
+}
+
+func (s *Store[V]) Get(uri string) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.data[uri]
+
+	return val, ok
+}
+
+func (s *Store[V]) Set(uri string, val V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[uri] = val
+}
+
+// SetForFiles sets the data of many files at once, taking the lock only once.
+func (s *Store[V]) SetForFiles(values map[string]V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maps.Copy(s.data, values)
+}
+
+func (s *Store[V]) Delete(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, uri)
+}
+
+// GetAll returns a snapshot of the data of all files in the store. The returned map
+// is a copy, and is safe to iterate while the store is being updated.
+func (s *Store[V]) GetAll() map[string]V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.data)
+}
+
+func (s *Store[V]) lock() {
+	s.mu.Lock()
+}
+
+func (s *Store[V]) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *Store[V]) deleteLocked(uri string) {
+	delete(s.data, uri)
+}
+
+func (s *Store[V]) moveLocked(from, to string) {
+	move(s.data, from, to)
+}
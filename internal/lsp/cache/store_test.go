@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	c := NewCache()
+
+	c.SetFileContents("file:///a.rego", "package a")
+	c.FoldingRanges.Set("file:///a.rego", []types.FoldingRange{{StartLine: 0, EndLine: 2}})
+	c.SemanticTokens.Set("file:///a.rego", types.SemanticTokens{Data: []uint{0, 0, 7, 0, 0}})
+	c.SetBuiltinPositions("file:///a.rego", map[uint][]types.BuiltinPosition{1: {}})
+
+	if !c.Rename("file:///a.rego", "file:///b.rego") {
+		t.Fatalf("expected rename to succeed")
+	}
+
+	if _, ok := c.FoldingRanges.Get("file:///a.rego"); ok {
+		t.Errorf("expected no folding ranges for old URI")
+	}
+
+	if ranges, _ := c.FoldingRanges.Get("file:///b.rego"); len(ranges) != 1 {
+		t.Errorf("expected folding ranges to be moved, got %v", ranges)
+	}
+
+	// setting identical contents keeps derived data
+	c.SetFileContents("file:///b.rego", "package a")
+
+	if _, ok := c.SemanticTokens.Get("file:///b.rego"); !ok {
+		t.Errorf("expected semantic tokens to be kept for unchanged contents")
+	}
+
+	c.SetFileContents("file:///b.rego", "package b")
+
+	if _, ok := c.SemanticTokens.Get("file:///b.rego"); ok {
+		t.Errorf("expected semantic tokens to be invalidated when contents change")
+	}
+
+	c.FoldingRanges.Set("file:///b.rego", []types.FoldingRange{})
+	c.SetModule("file:///b.rego", ast.MustParseModule("package b"))
+
+	if _, ok := c.FoldingRanges.Get("file:///b.rego"); ok {
+		t.Errorf("expected folding ranges to be invalidated when module is set")
+	}
+
+	c.CodeLenses.Set("file:///b.rego", []types.CodeLens{})
+	c.Delete("file:///b.rego")
+
+	if _, ok := c.CodeLenses.Get("file:///b.rego"); ok {
+		t.Errorf("expected code lenses to be deleted along with file")
+	}
+
+	if _, ok := c.GetBuiltinPositions("file:///b.rego"); ok {
+		t.Errorf("expected builtin positions to be deleted along with file")
+	}
+}
@@ -20,11 +20,11 @@ func FmtCommand(args []string) types.Command {
 	}
 }
 
-func FmtV1Command(args []string) types.Command {
+func RegoV1Command(args []string) types.Command {
 	return types.Command{
-		Title:     "Format for Rego v1 using opa-fmt",
+		Title:     "Migrate to Rego v1",
 		Command:   "regal.fix.use-rego-v1",
-		Tooltip:   "Format for Rego v1 using opa-fmt",
+		Tooltip:   "Migrate to Rego v1, adding if and contains keywords and replacing deprecated built-in functions",
 		Arguments: toAnySlice(args),
 	}
 }
@@ -46,3 +46,75 @@ func NoWhiteSpaceCommentCommand(args []string) types.Command {
 		Arguments: toAnySlice(args),
 	}
 }
+
+func RedundantAliasCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Remove redundant alias",
+		Command:   "regal.fix.redundant-alias",
+		Tooltip:   "Remove alias identical to the last part of the import path",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func ImportShadowsImportCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Remove duplicate import",
+		Command:   "regal.fix.import-shadows-import",
+		Tooltip:   "Remove import identical to an earlier import",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func UnsortedImportsCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Sort imports",
+		Command:   "regal.fix.unsorted-imports",
+		Tooltip:   "Sort imports, grouping future and rego.v1 imports, data imports and input imports",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func TrailingWhitespaceCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Remove trailing whitespace",
+		Command:   "regal.fix.trailing-whitespace",
+		Tooltip:   "Remove whitespace at the end of the line",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func IndentationCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Fix indentation",
+		Command:   "regal.fix.indentation",
+		Tooltip:   "Indent line with the configured indentation character",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func MissingFinalNewlineCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Add final newline",
+		Command:   "regal.fix.missing-final-newline",
+		Tooltip:   "Add newline to the end of the file",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func PreferRawStringCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Convert to raw string",
+		Command:   "regal.fix.prefer-raw-string",
+		Tooltip:   "Convert string to raw string, removing the escaping of backslashes and quotes",
+		Arguments: toAnySlice(args),
+	}
+}
+
+func GenerateMetadataCommand(args []string) types.Command {
+	return types.Command{
+		Title:     "Generate METADATA block",
+		Command:   "regal.metadata.generate",
+		Tooltip:   "Generate METADATA block for rule",
+		Arguments: toAnySlice(args),
+	}
+}
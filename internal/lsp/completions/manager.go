@@ -31,6 +31,7 @@ func NewDefaultManager(c *cache.Cache) *Manager {
 	m.RegisterProvider(&providers.PackageName{})
 	m.RegisterProvider(&providers.BuiltIns{})
 	m.RegisterProvider(&providers.RegoV1{})
+	m.RegisterProvider(&providers.Input{})
 
 	return m
 }
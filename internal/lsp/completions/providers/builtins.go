@@ -3,9 +3,9 @@ package providers
 import (
 	"strings"
 
+	"github.com/styrainc/regal/internal/builtindb"
 	"github.com/styrainc/regal/internal/lsp/cache"
 	"github.com/styrainc/regal/internal/lsp/hover"
-	"github.com/styrainc/regal/internal/lsp/rego"
 	"github.com/styrainc/regal/internal/lsp/types"
 )
 
@@ -44,18 +44,18 @@ func (*BuiltIns) Run(c *cache.Cache, params types.CompletionParams, _ *Options)
 
 	items := []types.CompletionItem{}
 
-	for key, builtIn := range rego.BuiltIns {
+	for _, builtIn := range builtindb.Default().All() {
 		if builtIn.Infix != "" {
 			continue
 		}
 
-		if builtIn.IsDeprecated() {
+		if builtIn.Deprecated {
 			continue
 		}
 
-		if strings.HasPrefix(key, lastWord) {
+		if strings.HasPrefix(builtIn.Name, lastWord) {
 			items = append(items, types.CompletionItem{
-				Label:  key,
+				Label:  builtIn.Name,
 				Kind:   3, // 3 is the kind for a function
 				Detail: "",
 				Documentation: &types.MarkupContent{
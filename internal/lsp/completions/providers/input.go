@@ -0,0 +1,94 @@
+package providers
+
+import (
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/cache"
+	"github.com/styrainc/regal/internal/lsp/clients"
+	"github.com/styrainc/regal/internal/lsp/rego"
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/lsp/uri"
+	"github.com/styrainc/regal/internal/schemas"
+)
+
+// Input will return completions for the fields of input references, like input.user.name, as declared in the
+// schema of input, or found in the input document of the workspace, like input.json.
+type Input struct{}
+
+func (*Input) Run(c *cache.Cache, params types.CompletionParams, opts *Options) ([]types.CompletionItem, error) {
+	fileURI := params.TextDocument.URI
+
+	fileContents, ok := c.GetFileContents(fileURI)
+	if !ok {
+		// if the file contents is missing then we can't provide completions
+		return nil, nil
+	}
+
+	lines := strings.Split(fileContents, "\n")
+	if params.Position.Line >= uint(len(lines)) {
+		return nil, nil
+	}
+
+	ref, _, _, ok := rego.RefAt(lines[params.Position.Line], params.Position.Character)
+	if !ok || !strings.HasPrefix(ref, "input.") {
+		return nil, nil
+	}
+
+	parts := strings.Split(ref, ".")[1:]
+	path, prefix := parts[:len(parts)-1], parts[len(parts)-1]
+
+	// while typing, the file likely fails to parse, in which case the annotations of the module last parsed are used
+	module, ok := c.GetModule(fileURI)
+	if !ok {
+		module = &ast.Module{}
+	}
+
+	rootPath := ""
+	if opts != nil && opts.RootURI != "" {
+		rootPath = uri.ToPath(clients.IdentifierGeneric, opts.RootURI)
+	}
+
+	inputPath := ""
+	if opts != nil && opts.InputDocumentPath != nil {
+		inputPath = opts.InputDocumentPath(fileURI)
+	}
+
+	schema, ok := schemas.Input(module, int(params.Position.Line)+1, inputPath, rootPath)
+	if !ok {
+		return nil, nil
+	}
+
+	items := []types.CompletionItem{}
+
+	for _, field := range schemas.Fields(schema, path) {
+		if !strings.HasPrefix(field.Name, prefix) {
+			continue
+		}
+
+		item := types.CompletionItem{
+			Label:  field.Name,
+			Kind:   5, // 5 is the kind for a field
+			Detail: field.Type,
+			TextEdit: &types.TextEdit{
+				Range: types.Range{
+					Start: types.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - uint(len(prefix)),
+					},
+					End: params.Position,
+				},
+				NewText: field.Name,
+			},
+		}
+
+		if field.Description != "" {
+			item.Documentation = &types.MarkupContent{Kind: "markdown", Value: field.Description}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
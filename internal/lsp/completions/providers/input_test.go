@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/cache"
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestInput(t *testing.T) {
+	t.Parallel()
+
+	parsedContents := `# METADATA
+# schemas:
+#   - input: {"type": "object", "properties": {"user": {"type": "object", "properties": {
+#       "name": {"type": "string", "description": "name of the user"},
+#       "nickname": {"type": "string"},
+#       "roles": {"type": "array", "items": {"type": "string"}}}}}}
+package p
+
+import rego.v1
+
+allow if input.user.name == "alice"
+`
+
+	c := cache.NewCache()
+
+	// the module last parsed is used for its annotations, as the file fails to parse while typing
+	c.SetModule(testCaseFileURI, parse.MustParseModule(parsedContents))
+	c.SetFileContents(testCaseFileURI, parsedContents+"\nallow if input.user.n")
+
+	p := &Input{}
+
+	completionParams := types.CompletionParams{
+		TextDocument: types.TextDocumentIdentifier{
+			URI: testCaseFileURI,
+		},
+		Position: types.Position{
+			Line:      12,
+			Character: 21,
+		},
+	}
+
+	completions, err := p.Run(c, completionParams, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	labels := completionLabels(completions)
+	if !slices.Equal(labels, []string{"name", "nickname"}) {
+		t.Fatalf("Expected completions name and nickname, got: %v", labels)
+	}
+
+	if completions[0].Detail != "string" || completions[0].Documentation.Value != "name of the user" {
+		t.Errorf("Expected type and description of name, got: %v", completions[0])
+	}
+
+	if completions[0].TextEdit.Range.Start.Character != 20 {
+		t.Errorf("Expected the typed prefix to be replaced, got: %v", completions[0].TextEdit.Range)
+	}
+}
+
+func TestInputNoSchema(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewCache()
+
+	c.SetFileContents(testCaseFileURI, "package p\n\nallow if input.user.n")
+
+	p := &Input{}
+
+	completionParams := types.CompletionParams{
+		TextDocument: types.TextDocumentIdentifier{
+			URI: testCaseFileURI,
+		},
+		Position: types.Position{
+			Line:      2,
+			Character: 21,
+		},
+	}
+
+	completions, err := p.Run(c, completionParams, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(completions) != 0 {
+		t.Fatalf("Expected no completions, got: %v", completions)
+	}
+}
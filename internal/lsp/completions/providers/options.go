@@ -2,4 +2,6 @@ package providers
 
 type Options struct {
 	RootURI string
+	// InputDocumentPath returns the path of the input document used for evaluating the file, or an empty string
+	InputDocumentPath func(fileURI string) string
 }
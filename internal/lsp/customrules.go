@@ -0,0 +1,70 @@
+package lsp
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	rio "github.com/styrainc/regal/internal/io"
+	"github.com/styrainc/regal/pkg/config"
+)
+
+// customRules are the custom rules of the workspace, found in the rules directory of the .regal directory nearest
+// to its root, which files are linted with along with the embedded rules. The zero value is no custom rules.
+type customRules struct {
+	dir string
+	// digest of the paths and contents of all files of the rules, as the results of linting with an earlier
+	// version of the rules must not be reused
+	digest [sha256.Size]byte
+}
+
+// findCustomRules returns the custom rules of the workspace with the root provided, if any.
+func findCustomRules(rootDir string) (customRules, error) {
+	regalDir, err := config.FindRegalDirectory(rootDir)
+	if err != nil {
+		// no .regal directory, and so no custom rules
+		return customRules{}, nil //nolint:nilerr
+	}
+
+	defer rio.CloseFileIgnore(regalDir)
+
+	dir := filepath.Join(regalDir.Name(), "rules")
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return customRules{}, nil
+	}
+
+	h := sha256.New()
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		h.Write(bs)
+		h.Write([]byte{0})
+
+		return nil
+	})
+	if err != nil {
+		return customRules{}, fmt.Errorf("failed to read custom rules in %s: %w", dir, err)
+	}
+
+	return customRules{dir: dir, digest: [sha256.Size]byte(h.Sum(nil))}, nil
+}
+
+// isCustomRuleFile returns whether the path is of a file in the rules directory of a .regal directory, which may be
+// that of the workspace, even if it didn't exist when the custom rules were last found.
+func isCustomRuleFile(path string) bool {
+	return strings.Contains(filepath.ToSlash(path), "/.regal/rules/")
+}
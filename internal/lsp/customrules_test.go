@@ -0,0 +1,147 @@
+package lsp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/cache"
+)
+
+func TestUpdateFileDiagnosticsWithCustomRules(t *testing.T) {
+	t.Parallel()
+
+	rule := `# METADATA
+# description: Package must not be named p
+package custom.regal.rules.naming["no-p-package"]
+
+import rego.v1
+
+import data.regal.result
+
+report contains violation if {
+	input["package"].path[1].value == "p"
+
+	violation := result.fail(rego.metadata.chain(), result.location(input["package"]))
+}
+`
+
+	rootDir := t.TempDir()
+	rulesDir := filepath.Join(rootDir, ".regal", "rules", "naming")
+
+	if err := os.MkdirAll(rulesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(rulesDir, "no_p_package.rego"), []byte(rule), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	custom, err := findCustomRules(rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if custom.dir != filepath.Join(rootDir, ".regal", "rules") {
+		t.Fatalf("expected custom rules to be found in %s, got %q", rootDir, custom.dir)
+	}
+
+	uri := "file:///p.rego"
+
+	c := cache.NewCache()
+	c.SetFileContents(uri, "package p\n\nimport rego.v1\n")
+
+	if _, err := updateParse(c, uri); err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+
+	if _, err := updateFileDiagnostics(context.Background(), c, nil, custom, uri, ""); err != nil {
+		t.Fatalf("failed to update diagnostics: %s", err)
+	}
+
+	diags, _ := c.GetFileDiagnostics(uri)
+
+	found := false
+
+	for _, diag := range diags {
+		if diag.Code == "no-p-package" && diag.Source == "regal/naming" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("expected no-p-package diagnostic from custom rule, got %v", diags)
+	}
+
+	// changing the rules changes the digest, for earlier results not to be reused
+	if err := os.WriteFile(filepath.Join(rulesDir, "no_p_package.rego"), []byte(rule+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := findCustomRules(rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed.digest == custom.digest {
+		t.Errorf("expected digest of custom rules to change with their contents")
+	}
+}
+
+func TestConfigHashIncludesCustomRules(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	rulesDir := filepath.Join(rootDir, ".regal", "rules")
+
+	if err := os.MkdirAll(rulesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr})
+	ls.clientRootURI = fileURIScheme + rootDir
+
+	ls.loadCustomRules()
+
+	before := ls.configHash()
+
+	if err := os.WriteFile(filepath.Join(rulesDir, "rule.rego"), []byte("package custom.regal.rules.x.y\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ls.loadCustomRules() {
+		t.Fatal("expected custom rules to have changed")
+	}
+
+	if ls.configHash() == before {
+		t.Errorf("expected config hash to change with the custom rules, for the index not to be restored")
+	}
+}
+
+func TestFindCustomRulesWithoutRegalDirectory(t *testing.T) {
+	t.Parallel()
+
+	custom, err := findCustomRules(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if custom != (customRules{}) {
+		t.Errorf("expected no custom rules, got %v", custom)
+	}
+}
+
+func TestIsCustomRuleFile(t *testing.T) {
+	t.Parallel()
+
+	for path, expected := range map[string]bool{
+		filepath.FromSlash("/project/.regal/rules/naming/rule.rego"): true,
+		filepath.FromSlash("/project/.regal/config.yaml"):            false,
+		filepath.FromSlash("/project/policy/rules/authz.rego"):       false,
+	} {
+		if got := isCustomRuleFile(path); got != expected {
+			t.Errorf("expected %s to be custom rule file: %t, got %t", path, expected, got)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/topdown"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/schemas"
+)
+
+const (
+	dataFileSource = "regal/data"
+
+	codeDataParseError      = "data-parse-error"
+	codeDataSchemaViolation = "data-schema-violation"
+)
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// dataSchema is a schema of data, as declared in the schemas of an annotation.
+type dataSchema struct {
+	// path is the path of the data the schema applies to, without the leading data
+	path []string
+	// name is the ref of the schema, like schema.acl, or describes the definition of inline schemas
+	name   string
+	schema any
+}
+
+// dataFileDiagnostics returns the syntax errors of the data file, or if it parses, the violations of the schemas
+// that apply to the data in the file. dataPath is the path of the data of the file, as determined by the directory
+// it's in, like for data files in bundles.
+func dataFileDiagnostics(fileURI, contents string, dataPath []string, schemas []dataSchema) []types.Diagnostic {
+	lines := strings.Split(contents, "\n")
+
+	var document yaml.Node
+
+	// JSON is parsed as such first, as the errors of the YAML parser would be confusing
+	if strings.HasSuffix(fileURI, ".json") {
+		var v any
+		if err := json.Unmarshal([]byte(contents), &v); err != nil {
+			return []types.Diagnostic{jsonErrorDiagnostic(err, contents, lines)}
+		}
+	}
+
+	// as JSON is also YAML, the YAML parser is used for both, to find the positions of the values in the file
+	if err := yaml.Unmarshal([]byte(contents), &document); err != nil {
+		return []types.Diagnostic{yamlErrorDiagnostic(err, lines)}
+	}
+
+	if len(document.Content) == 0 {
+		return []types.Diagnostic{}
+	}
+
+	var data any
+	if err := document.Content[0].Decode(&data); err != nil {
+		return []types.Diagnostic{yamlErrorDiagnostic(err, lines)}
+	}
+
+	diags := make([]types.Diagnostic, 0)
+
+	for _, schema := range schemas {
+		if len(schema.path) < len(dataPath) || !slices.Equal(schema.path[:len(dataPath)], dataPath) {
+			continue
+		}
+
+		value, ok := lookupPath(data, schema.path[len(dataPath):])
+		if !ok {
+			continue
+		}
+
+		violations, err := matchSchema(value, schema.schema)
+		if err != nil {
+			diags = append(diags, dataFileDiagnostic(
+				codeDataSchemaViolation,
+				fmt.Sprintf("Failed to validate against %s: %s", schema.name, err),
+				lines, 0, 0,
+			))
+
+			continue
+		}
+
+		for _, violation := range violations {
+			field := slices.Clone(schema.path[len(dataPath):])
+			if violation.Field != "(root)" {
+				field = append(field, strings.Split(violation.Field, ".")...)
+			}
+
+			line, col := nodePosition(document.Content[0], field)
+
+			diags = append(diags, dataFileDiagnostic(
+				codeDataSchemaViolation,
+				fmt.Sprintf("%s does not match %s: %s", fieldName(field), schema.name, violation.Description),
+				lines, line, col,
+			))
+		}
+	}
+
+	return diags
+}
+
+// dataPathOf returns the path of the data of a data file in the workspace, which is the path of the directory the
+// file is in, relative to the root of the workspace.
+func dataPathOf(filePath, rootPath string) []string {
+	rel, err := filepath.Rel(rootPath, filepath.Dir(filePath))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return []string{}
+	}
+
+	return strings.Split(filepath.ToSlash(rel), "/")
+}
+
+// dataSchemas returns the schemas of data declared in the annotations of the modules. Schemas referenced by name
+// are loaded from the schemas directory of the workspace, while those failing to load are left out, as OPA will
+// report them when the policy is compiled.
+func dataSchemas(modules map[string]*ast.Module, rootPath string) []dataSchema {
+	declared := make([]dataSchema, 0)
+
+	for _, module := range modules {
+		for _, annotation := range module.Annotations {
+			for _, schemaAnnotation := range annotation.Schemas {
+				if len(schemaAnnotation.Path) == 0 || !schemaAnnotation.Path[0].Equal(ast.DefaultRootDocument) {
+					continue
+				}
+
+				dataPath, ok := refToPath(schemaAnnotation.Path[1:])
+				if !ok {
+					continue
+				}
+
+				schema, name, err := schemas.FromAnnotation(schemaAnnotation, rootPath)
+				if err != nil {
+					continue
+				}
+
+				declared = append(declared, dataSchema{path: dataPath, name: name, schema: schema})
+			}
+		}
+	}
+
+	return declared
+}
+
+// schemaViolation is a violation of a schema, as reported by json.match_schema.
+type schemaViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"desc"`
+}
+
+// matchSchema validates the value against the schema using the json.match_schema built-in function, the same as
+// used in policies, and returns the violations found.
+func matchSchema(value, schema any) ([]schemaViolation, error) {
+	// json.match_schema only accepts objects, or JSON encoded documents of any type
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	var result *ast.Term
+
+	if err = topdown.GetBuiltin(ast.JSONMatchSchema.Name)(
+		topdown.BuiltinContext{},
+		[]*ast.Term{ast.StringTerm(string(valueJSON)), ast.StringTerm(string(schemaJSON))},
+		func(t *ast.Term) error {
+			result = t
+
+			return nil
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failed to match schema: %w", err)
+	}
+
+	var matched []any
+	if err = ast.As(result.Value, &matched); err != nil || len(matched) != 2 {
+		return nil, errors.New("unexpected result of matching schema")
+	}
+
+	bs, err := json.Marshal(matched[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema violations: %w", err)
+	}
+
+	var violations []schemaViolation
+	if err = json.Unmarshal(bs, &violations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema violations: %w", err)
+	}
+
+	return violations, nil
+}
+
+func refToPath(ref ast.Ref) ([]string, bool) {
+	p := make([]string, 0, len(ref))
+
+	for _, term := range ref {
+		s, ok := term.Value.(ast.String)
+		if !ok {
+			return nil, false
+		}
+
+		p = append(p, string(s))
+	}
+
+	return p, true
+}
+
+func lookupPath(value any, p []string) (any, bool) {
+	for _, key := range p {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// nodePosition returns the line and column, starting at 0, of the value at the path in the document, or of its
+// closest parent found.
+func nodePosition(node *yaml.Node, p []string) (int, int) {
+	line, col := node.Line-1, node.Column-1
+
+	for _, key := range p {
+		var next *yaml.Node
+
+		switch node.Kind { //nolint:exhaustive
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					line, col = node.Content[i].Line-1, node.Content[i].Column-1
+					next = node.Content[i+1]
+
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+				line, col = next.Line-1, next.Column-1
+			}
+		}
+
+		if next == nil {
+			break
+		}
+
+		node = next
+	}
+
+	return line, col
+}
+
+func fieldName(field []string) string {
+	if len(field) == 0 {
+		return "Data"
+	}
+
+	return strings.Join(field, ".")
+}
+
+func jsonErrorDiagnostic(err error, contents string, lines []string) types.Diagnostic {
+	line, col := 0, 0
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) {
+		before := contents[:min(int(syntaxError.Offset), len(contents))]
+		line = strings.Count(before, "\n")
+		col = len(before) - strings.LastIndex(before, "\n") - 1
+	}
+
+	return dataFileDiagnostic(codeDataParseError, err.Error(), lines, line, col)
+}
+
+func yamlErrorDiagnostic(err error, lines []string) types.Diagnostic {
+	line := 0
+	message := err.Error()
+
+	if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+		line, _ = strconv.Atoi(match[1])
+		line--
+		message = strings.TrimPrefix(message, match[0])
+	}
+
+	return dataFileDiagnostic(codeDataParseError, message, lines, line, 0)
+}
+
+// dataFileDiagnostic returns a diagnostic highlighting the line of the data file from the column to its end.
+func dataFileDiagnostic(code, message string, lines []string, line, col int) types.Diagnostic {
+	line = max(0, min(line, len(lines)-1))
+
+	end := col + 1
+	if line < len(lines) {
+		end = max(end, len(lines[line]))
+	}
+
+	return types.Diagnostic{
+		Severity: 1,
+		Range: types.Range{
+			Start: types.Position{Line: uint(line), Character: uint(col)},
+			End:   types.Position{Line: uint(line), Character: uint(end)},
+		},
+		Message: message,
+		Source:  dataFileSource,
+		Code:    code,
+	}
+}
@@ -0,0 +1,169 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestDataFileDiagnosticsSyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		uri          string
+		contents     string
+		expectedLine uint
+	}{
+		"valid json": {
+			uri:      "file:///workspace/data.json",
+			contents: "{\n  \"users\": [\"alice\"]\n}\n",
+		},
+		"invalid json": {
+			uri:          "file:///workspace/data.json",
+			contents:     "{\n  \"users\": [\"alice\"],\n}\n",
+			expectedLine: 2,
+		},
+		"valid yaml": {
+			uri:      "file:///workspace/data.yaml",
+			contents: "users:\n  - alice\n",
+		},
+		"invalid yaml": {
+			uri:          "file:///workspace/data.yaml",
+			contents:     "users:\n  - alice\nroles:\n\t- admin\n",
+			expectedLine: 3,
+		},
+		"empty yaml": {
+			uri:      "file:///workspace/data.yaml",
+			contents: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := dataFileDiagnostics(tc.uri, tc.contents, []string{}, nil)
+
+			if tc.expectedLine == 0 {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got %v", diags)
+				}
+
+				return
+			}
+
+			if len(diags) != 1 {
+				t.Fatalf("expected one diagnostic, got %v", diags)
+			}
+
+			if diags[0].Code != codeDataParseError {
+				t.Errorf("expected code %s, got %s", codeDataParseError, diags[0].Code)
+			}
+
+			if diags[0].Range.Start.Line != tc.expectedLine {
+				t.Errorf("expected diagnostic on line %d, got %d", tc.expectedLine, diags[0].Range.Start.Line)
+			}
+		})
+	}
+}
+
+func TestDataFileDiagnosticsSchemaViolations(t *testing.T) {
+	t.Parallel()
+
+	module := parse.MustParseModule(`# METADATA
+# schemas:
+#   - data.acl.users: {"type": "array", "items": {"type": "string"}}
+#   - data.other: {"type": "string"}
+package p
+
+import rego.v1
+
+allow if "alice" in data.acl.users
+`)
+
+	schemas := dataSchemas(map[string]*ast.Module{"p.rego": module}, "")
+	if len(schemas) != 2 {
+		t.Fatalf("expected two schemas, got %v", schemas)
+	}
+
+	contents := "{\n  \"users\": [\n    \"alice\",\n    1\n  ]\n}\n"
+
+	diags := dataFileDiagnostics("file:///workspace/acl/data.json", contents, []string{"acl"}, schemas)
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diags)
+	}
+
+	if diags[0].Code != codeDataSchemaViolation {
+		t.Errorf("expected code %s, got %s", codeDataSchemaViolation, diags[0].Code)
+	}
+
+	if diags[0].Range.Start.Line != 3 || diags[0].Range.Start.Character != 4 {
+		t.Errorf("expected diagnostic at 3:4, got %v", diags[0].Range.Start)
+	}
+
+	expectedMessage := "users.1 does not match the schema of data.acl.users: " +
+		"Invalid type. Expected: string, given: integer"
+	if diags[0].Message != expectedMessage {
+		t.Errorf("expected message %q, got %q", expectedMessage, diags[0].Message)
+	}
+
+	// data of other paths is not validated against the schema
+	diags = dataFileDiagnostics("file:///workspace/data.json", contents, []string{}, schemas)
+	if len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", diags)
+	}
+}
+
+func TestDataSchemasFromSchemasDirectory(t *testing.T) {
+	t.Parallel()
+
+	rootPath := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(rootPath, "schemas"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := []byte(`{"type": "object"}`)
+	if err := os.WriteFile(filepath.Join(rootPath, "schemas", "acl.json"), schema, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	module := parse.MustParseModule(`# METADATA
+# schemas:
+#   - data.acl: schema.acl
+#   - data.missing: schema.missing
+package p
+`)
+
+	schemas := dataSchemas(map[string]*ast.Module{"p.rego": module}, rootPath)
+	if len(schemas) != 1 {
+		t.Fatalf("expected one schema, got %v", schemas)
+	}
+
+	if schemas[0].name != "schema.acl" {
+		t.Errorf("expected schema.acl, got %s", schemas[0].name)
+	}
+
+	diags := dataFileDiagnostics("file:///workspace/data.yaml", "acl: [1]\n", []string{}, schemas)
+	if len(diags) != 1 || diags[0].Range.Start.Line != 0 {
+		t.Fatalf("expected one diagnostic on the first line, got %v", diags)
+	}
+}
+
+func TestDataPathOf(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/workspace")
+
+	if p := dataPathOf(filepath.FromSlash("/workspace/data.json"), root); len(p) != 0 {
+		t.Errorf("expected empty path, got %v", p)
+	}
+
+	if p := dataPathOf(filepath.FromSlash("/workspace/a/b/data.yaml"), root); len(p) != 2 || p[0] != "a" || p[1] != "b" {
+		t.Errorf("expected path a.b, got %v", p)
+	}
+}
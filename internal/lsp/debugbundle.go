@@ -0,0 +1,28 @@
+package lsp
+
+import "fmt"
+
+// WriteDebugBundle records the files open, and the configuration loaded, along with the timings of requests and the
+// errors recorded during the session, into a debug bundle in dir, returning the path of the archive written.
+func (l *LanguageServer) WriteDebugBundle(dir string) (string, error) {
+	if l.debugBundle == nil {
+		return "", nil
+	}
+
+	for fileURI, contents := range l.cache.GetAllFiles() {
+		l.debugBundle.AddFile(fileURI, contents)
+	}
+
+	l.loadedConfigLock.Lock()
+	if l.loadedConfig != nil {
+		l.debugBundle.AddConfig(l.clientRootURI, *l.loadedConfig)
+	}
+	l.loadedConfigLock.Unlock()
+
+	path, err := l.debugBundle.Write(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to write debug bundle: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,60 @@
+package lsp
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/styrainc/regal/internal/debugbundle"
+)
+
+func TestWriteDebugBundle(t *testing.T) {
+	t.Parallel()
+
+	ls := NewLanguageServer(&LanguageServerOptions{DebugBundle: debugbundle.New([]string{"language-server"}, false)})
+	ls.cache.SetFileContents("file:///p.rego", "package p\n\nallow := true\n")
+
+	params := json.RawMessage("{}")
+
+	if _, err := ls.Handle(context.Background(), nil, &jsonrpc2.Request{Method: "unknown", Params: &params}); err == nil {
+		t.Fatal("expected error for unknown method")
+	}
+
+	path, err := ls.WriteDebugBundle(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"file:///p.rego", `"name": "lsp unknown"`, "method not supported: unknown"} {
+		if !strings.Contains(string(bs), expected) {
+			t.Errorf("expected debug bundle to contain %q", expected)
+		}
+	}
+
+	if strings.Contains(string(bs), "allow := true") {
+		t.Errorf("expected contents of files to be redacted")
+	}
+}
@@ -0,0 +1,73 @@
+package lsp
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+// severityNames are the names of the severities of diagnostics, as named by the LSP specification.
+var severityNames = map[uint]string{ //nolint:gochecknoglobals
+	1: "error",
+	2: "warning",
+	3: "information",
+	4: "hint",
+}
+
+// diagnosticsSummary counts the diagnostics of all files, by severity, category and rule. The category of a
+// diagnostic is that of its source, like bugs for regal/bugs, and the rule its code.
+func diagnosticsSummary(diags map[string][]types.Diagnostic) types.WorkspaceDiagnosticsSummary {
+	summary := types.WorkspaceDiagnosticsSummary{
+		BySeverity: make(map[string]int),
+		ByCategory: make(map[string]int),
+		ByRule:     make([]types.RuleDiagnosticsSummary, 0),
+	}
+
+	rules := make(map[string]*types.RuleDiagnosticsSummary)
+
+	for _, fileDiags := range diags {
+		if len(fileDiags) == 0 {
+			continue
+		}
+
+		summary.Files++
+
+		rulesInFile := make(map[string]struct{})
+
+		for _, diag := range fileDiags {
+			category := strings.TrimPrefix(diag.Source, "regal/")
+			severity := severityNames[diag.Severity]
+
+			summary.Total++
+			summary.BySeverity[severity]++
+			summary.ByCategory[category]++
+
+			key := category + "/" + diag.Code
+
+			rule, ok := rules[key]
+			if !ok {
+				rule = &types.RuleDiagnosticsSummary{Rule: diag.Code, Category: category, Severity: severity}
+				rules[key] = rule
+			}
+
+			rule.Count++
+
+			if _, ok := rulesInFile[key]; !ok {
+				rulesInFile[key] = struct{}{}
+				rule.Files++
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		summary.ByRule = append(summary.ByRule, *rule)
+	}
+
+	slices.SortFunc(summary.ByRule, func(a, b types.RuleDiagnosticsSummary) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Category, b.Category), cmp.Compare(a.Rule, b.Rule))
+	})
+
+	return summary
+}
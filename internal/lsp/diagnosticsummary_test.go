@@ -0,0 +1,43 @@
+package lsp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestDiagnosticsSummary(t *testing.T) {
+	t.Parallel()
+
+	diags := map[string][]types.Diagnostic{
+		"file:///a.rego": {
+			{Severity: 2, Source: "regal/style", Code: "line-length"},
+			{Severity: 2, Source: "regal/style", Code: "line-length"},
+			{Severity: 3, Source: "regal/bugs", Code: "constant-condition"},
+		},
+		"file:///b.rego": {
+			{Severity: 2, Source: "regal/style", Code: "line-length"},
+		},
+		"file:///c.rego": {
+			{Severity: 1, Source: "regal/parse", Code: "rego_parse_error"},
+		},
+		"file:///d.rego": {},
+	}
+
+	expected := types.WorkspaceDiagnosticsSummary{
+		Total:      5,
+		Files:      3,
+		BySeverity: map[string]int{"error": 1, "warning": 3, "information": 1},
+		ByCategory: map[string]int{"bugs": 1, "parse": 1, "style": 3},
+		ByRule: []types.RuleDiagnosticsSummary{
+			{Rule: "line-length", Category: "style", Severity: "warning", Count: 3, Files: 2},
+			{Rule: "constant-condition", Category: "bugs", Severity: "information", Count: 1, Files: 1},
+			{Rule: "rego_parse_error", Category: "parse", Severity: "error", Count: 1, Files: 1},
+		},
+	}
+
+	if summary := diagnosticsSummary(diags); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected summary %+v, got %+v", expected, summary)
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/open-policy-agent/opa/ast"
 
-	"github.com/styrainc/regal/internal/lsp/rego"
+	"github.com/styrainc/regal/internal/builtindb"
 	"github.com/styrainc/regal/internal/lsp/types"
 	"github.com/styrainc/regal/internal/lsp/types/symbols"
 )
@@ -214,10 +214,8 @@ func getRuleDetail(rule *ast.Rule) string {
 	case ast.Call:
 		name := v[0].String()
 
-		if builtin, ok := rego.BuiltIns[name]; ok {
-			retType := builtin.Decl.NamedResult().String()
-
-			detail += fmt.Sprintf(" (%s)", simplifyType(retType))
+		if builtin, ok := builtindb.Default().Get(name); ok && builtin.Result != nil {
+			detail += fmt.Sprintf(" (%s)", simplifyType(builtin.Result.Type))
 		}
 	}
 
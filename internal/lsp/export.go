@@ -0,0 +1,521 @@
+package lsp
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/clients"
+	"github.com/styrainc/regal/internal/lsp/hover"
+	"github.com/styrainc/regal/internal/lsp/rego"
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/lsp/uri"
+	"github.com/styrainc/regal/internal/util"
+	"github.com/styrainc/regal/pkg/version"
+)
+
+// Formats of the index written by WriteIndex.
+const (
+	// IndexFormatLSIF is the Language Server Index Format, a graph of vertices and edges encoded as JSON lines.
+	IndexFormatLSIF = "lsif"
+	// IndexFormatSCIP is the SCIP Code Intelligence Protocol, encoded as protobuf.
+	IndexFormatSCIP = "scip"
+)
+
+// monikerScheme is the scheme of the monikers of LSIF indexes, and of the symbols of SCIP indexes.
+const monikerScheme = "rego"
+
+// indexedSymbol is a rule, identified by its path in the data document, or a built-in function called, along with
+// the documentation shown on hover.
+type indexedSymbol struct {
+	name    string
+	builtin bool
+	hover   string
+}
+
+type indexedOccurrence struct {
+	symbol     string
+	rng        types.Range
+	definition bool
+}
+
+type indexedDocument struct {
+	// path of the document, relative to the root of the index, with forward slashes
+	path        string
+	occurrences []indexedOccurrence
+}
+
+type workspaceIndex struct {
+	root      string
+	documents []indexedDocument
+	symbols   map[string]*indexedSymbol
+}
+
+// WriteIndex writes an index of the definitions of, and references to, the rules of the modules, along with the
+// documentation shown on hover for rules and the built-in functions called, in the format provided. Paths of modules
+// are written relative to root, which is expected to contain them all. Indexes are made for code browsing platforms,
+// like Sourcegraph, to provide the same navigation as the language server does in editors.
+func WriteIndex(w io.Writer, format string, root string, modules map[string]*ast.Module) error {
+	idx, err := buildIndex(root, modules)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case IndexFormatLSIF:
+		return writeLSIF(w, idx)
+	case IndexFormatSCIP:
+		return writeSCIP(w, idx)
+	}
+
+	return fmt.Errorf("unknown index format %s, expected %s or %s", format, IndexFormatLSIF, IndexFormatSCIP)
+}
+
+func buildIndex(root string, modules map[string]*ast.Module) (workspaceIndex, error) {
+	idx := workspaceIndex{root: root, symbols: make(map[string]*indexedSymbol)}
+
+	files := util.Keys(modules)
+	slices.Sort(files)
+
+	for _, file := range files {
+		module := modules[file]
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return workspaceIndex{}, fmt.Errorf("failed to find path of %s relative to %s: %w", file, root, err)
+		}
+
+		doc := indexedDocument{path: filepath.ToSlash(rel)}
+
+		symbols := indexSymbols(module)
+
+		for _, definition := range symbols.Definitions {
+			doc.occurrences = append(doc.occurrences, indexedOccurrence{
+				symbol:     idx.symbol(definition.Name, false),
+				rng:        definition.Range,
+				definition: true,
+			})
+		}
+
+		for _, reference := range symbols.References {
+			doc.occurrences = append(doc.occurrences, indexedOccurrence{
+				symbol: idx.symbol(reference.Name, false),
+				rng:    reference.Range,
+			})
+		}
+
+		if module.Package != nil {
+			// annotations failing to build, like when invalid, are left out of the documentation
+			annotations, _ := ast.BuildAnnotationSet([]*ast.Module{module})
+
+			for _, rule := range module.Rules {
+				if symbol := idx.symbols[ruleSymbolName(module.Package.Path, rule)]; symbol.hover == "" {
+					symbol.hover = ruleHoverContent(symbol.name, annotations.GetRuleScope(rule))
+				}
+			}
+		}
+
+		for _, call := range rego.AllBuiltinCalls(module) {
+			name := idx.symbol(call.Builtin.Name, true)
+			if symbol := idx.symbols[name]; symbol.hover == "" {
+				symbol.hover = hover.CreateHoverContent(call.Builtin)
+			}
+
+			doc.occurrences = append(doc.occurrences, indexedOccurrence{symbol: name, rng: termRange(call.Location)})
+		}
+
+		slices.SortFunc(doc.occurrences, func(a, b indexedOccurrence) int {
+			return cmp.Or(
+				cmp.Compare(a.rng.Start.Line, b.rng.Start.Line),
+				cmp.Compare(a.rng.Start.Character, b.rng.Start.Character),
+			)
+		})
+
+		idx.documents = append(idx.documents, doc)
+	}
+
+	return idx, nil
+}
+
+// symbol returns the name of the symbol, added to the index if not yet found in it.
+func (idx workspaceIndex) symbol(name string, builtin bool) string {
+	if _, ok := idx.symbols[name]; !ok {
+		idx.symbols[name] = &indexedSymbol{name: name, builtin: builtin}
+	}
+
+	return name
+}
+
+// sortedSymbols returns the symbols of the index, sorted by name, for the index to be the same on every run.
+func (idx workspaceIndex) sortedSymbols() []*indexedSymbol {
+	names := util.Keys(idx.symbols)
+	slices.Sort(names)
+
+	symbols := make([]*indexedSymbol, 0, len(names))
+	for _, name := range names {
+		symbols = append(symbols, idx.symbols[name])
+	}
+
+	return symbols
+}
+
+// ruleHoverContent returns the documentation of a rule, with the title and description of its metadata, if any.
+func ruleHoverContent(name string, annotations []*ast.Annotations) string {
+	sb := &strings.Builder{}
+
+	fmt.Fprintf(sb, "```rego\n%s\n```\n", name)
+
+	for _, annotation := range annotations {
+		if annotation.Title != "" {
+			fmt.Fprintf(sb, "\n**%s**\n", annotation.Title)
+		}
+
+		if annotation.Description != "" {
+			fmt.Fprintf(sb, "\n%s\n", annotation.Description)
+		}
+	}
+
+	return sb.String()
+}
+
+// lsifWriter writes the vertices and edges of an LSIF index, as JSON lines, numbering them as they're written.
+type lsifWriter struct {
+	enc *json.Encoder
+	id  int
+	err error
+}
+
+func (lw *lsifWriter) vertex(label string, properties map[string]any) int {
+	return lw.write("vertex", label, properties)
+}
+
+func (lw *lsifWriter) edge(label string, outV int, inVs []int, properties map[string]any) {
+	if properties == nil {
+		properties = make(map[string]any, 2)
+	}
+
+	properties["outV"] = outV
+
+	if len(inVs) == 1 && label != "contains" && label != "item" {
+		properties["inV"] = inVs[0]
+	} else {
+		properties["inVs"] = inVs
+	}
+
+	lw.write("edge", label, properties)
+}
+
+func (lw *lsifWriter) write(typ, label string, properties map[string]any) int {
+	lw.id++
+
+	if lw.err != nil {
+		return lw.id
+	}
+
+	element := map[string]any{"id": lw.id, "type": typ, "label": label}
+	for k, v := range properties {
+		element[k] = v
+	}
+
+	if err := lw.enc.Encode(element); err != nil {
+		lw.err = fmt.Errorf("failed to write LSIF index: %w", err)
+	}
+
+	return lw.id
+}
+
+func writeLSIF(w io.Writer, idx workspaceIndex) error {
+	lw := &lsifWriter{enc: json.NewEncoder(w)}
+
+	lw.vertex("metaData", map[string]any{
+		"version":          "0.4.3",
+		"projectRoot":      uri.FromPath(clients.IdentifierGeneric, idx.root),
+		"positionEncoding": "utf-16",
+		"toolInfo":         map[string]any{"name": "regal", "version": version.New().Version},
+	})
+
+	project := lw.vertex("project", map[string]any{"kind": monikerScheme})
+
+	resultSets := make(map[string]int, len(idx.symbols))
+
+	for _, symbol := range idx.sortedSymbols() {
+		resultSet := lw.vertex("resultSet", nil)
+		resultSets[symbol.name] = resultSet
+
+		if symbol.hover != "" {
+			hoverResult := lw.vertex("hoverResult", map[string]any{
+				"result": map[string]any{"contents": types.MarkupContent{Kind: "markdown", Value: symbol.hover}},
+			})
+
+			lw.edge("textDocument/hover", resultSet, []int{hoverResult}, nil)
+		}
+
+		kind := "export"
+		if symbol.builtin {
+			kind = "import"
+		}
+
+		moniker := lw.vertex("moniker", map[string]any{
+			"scheme":     monikerScheme,
+			"identifier": symbol.name,
+			"kind":       kind,
+			"unique":     "scheme",
+		})
+
+		lw.edge("moniker", resultSet, []int{moniker}, nil)
+	}
+
+	// ranges of the definitions of and references to each symbol, by the document they're in
+	definitions := make(map[string]map[int][]int)
+	references := make(map[string]map[int][]int)
+
+	documents := make([]int, 0, len(idx.documents))
+
+	for _, doc := range idx.documents {
+		document := lw.vertex("document", map[string]any{
+			"uri":        uri.FromPath(clients.IdentifierGeneric, filepath.Join(idx.root, filepath.FromSlash(doc.path))),
+			"languageId": monikerScheme,
+		})
+
+		documents = append(documents, document)
+
+		ranges := make([]int, 0, len(doc.occurrences))
+
+		for _, occurrence := range doc.occurrences {
+			rng := lw.vertex("range", map[string]any{"start": occurrence.rng.Start, "end": occurrence.rng.End})
+			ranges = append(ranges, rng)
+
+			lw.edge("next", rng, []int{resultSets[occurrence.symbol]}, nil)
+
+			byDocument := references
+			if occurrence.definition {
+				byDocument = definitions
+			}
+
+			if byDocument[occurrence.symbol] == nil {
+				byDocument[occurrence.symbol] = make(map[int][]int)
+			}
+
+			byDocument[occurrence.symbol][document] = append(byDocument[occurrence.symbol][document], rng)
+		}
+
+		if len(ranges) > 0 {
+			lw.edge("contains", document, ranges, nil)
+		}
+	}
+
+	if len(documents) > 0 {
+		lw.edge("contains", project, documents, nil)
+	}
+
+	for _, symbol := range idx.sortedSymbols() {
+		resultSet := resultSets[symbol.name]
+
+		if len(definitions[symbol.name]) > 0 {
+			definitionResult := lw.vertex("definitionResult", nil)
+			lw.edge("textDocument/definition", resultSet, []int{definitionResult}, nil)
+
+			for _, document := range sortedDocuments(definitions[symbol.name]) {
+				lw.edge("item", definitionResult, definitions[symbol.name][document], map[string]any{"document": document})
+			}
+		}
+
+		referenceResult := lw.vertex("referenceResult", nil)
+		lw.edge("textDocument/references", resultSet, []int{referenceResult}, nil)
+
+		for _, items := range []struct {
+			property   string
+			byDocument map[int][]int
+		}{
+			{"definitions", definitions[symbol.name]},
+			{"references", references[symbol.name]},
+		} {
+			for _, document := range sortedDocuments(items.byDocument) {
+				lw.edge("item", referenceResult, items.byDocument[document], map[string]any{
+					"document": document,
+					"property": items.property,
+				})
+			}
+		}
+	}
+
+	return lw.err
+}
+
+func sortedDocuments(byDocument map[int][]int) []int {
+	documents := util.Keys(byDocument)
+	slices.Sort(documents)
+
+	return documents
+}
+
+// Numbers of the fields and values of the SCIP protobuf messages written, as defined by scip.proto.
+const (
+	scipIndexMetadata        = 1
+	scipIndexDocuments       = 2
+	scipIndexExternalSymbols = 3
+
+	scipMetadataToolInfo             = 2
+	scipMetadataProjectRoot          = 3
+	scipMetadataTextDocumentEncoding = 4
+	scipTextEncodingUTF8             = 1
+
+	scipToolInfoName    = 1
+	scipToolInfoVersion = 2
+
+	scipDocumentRelativePath     = 1
+	scipDocumentOccurrences      = 2
+	scipDocumentSymbols          = 3
+	scipDocumentLanguage         = 4
+	scipDocumentPositionEncoding = 6
+	scipPositionEncodingUTF8     = 1
+
+	scipOccurrenceRange       = 1
+	scipOccurrenceSymbol      = 2
+	scipOccurrenceSymbolRoles = 3
+	scipSymbolRoleDefinition  = 1
+
+	scipSymbolInformationSymbol        = 1
+	scipSymbolInformationDocumentation = 3
+	scipSymbolInformationDisplayName   = 6
+)
+
+func writeSCIP(w io.Writer, idx workspaceIndex) error {
+	var toolInfo, metadata, index []byte
+
+	toolInfo = appendString(toolInfo, scipToolInfoName, "regal")
+	toolInfo = appendString(toolInfo, scipToolInfoVersion, version.New().Version)
+
+	metadata = appendBytes(metadata, scipMetadataToolInfo, toolInfo)
+	metadata = appendString(metadata, scipMetadataProjectRoot, uri.FromPath(clients.IdentifierGeneric, idx.root))
+	metadata = appendVarint(metadata, scipMetadataTextDocumentEncoding, scipTextEncodingUTF8)
+
+	index = appendBytes(index, scipIndexMetadata, metadata)
+
+	// symbols are documented in the first document defining them, or as external symbols when not defined in any
+	documented := make(map[string]bool, len(idx.symbols))
+
+	for _, doc := range idx.documents {
+		var document []byte
+
+		document = appendString(document, scipDocumentLanguage, monikerScheme)
+		document = appendString(document, scipDocumentRelativePath, doc.path)
+		document = appendVarint(document, scipDocumentPositionEncoding, scipPositionEncodingUTF8)
+
+		for _, occurrence := range doc.occurrences {
+			symbol := idx.symbols[occurrence.symbol]
+
+			var occ []byte
+
+			occ = appendPacked(occ, scipOccurrenceRange, scipRange(occurrence.rng))
+			occ = appendString(occ, scipOccurrenceSymbol, scipSymbol(symbol))
+
+			if occurrence.definition {
+				occ = appendVarint(occ, scipOccurrenceSymbolRoles, scipSymbolRoleDefinition)
+
+				if !documented[symbol.name] {
+					documented[symbol.name] = true
+					document = appendBytes(document, scipDocumentSymbols, scipSymbolInformation(symbol))
+				}
+			}
+
+			document = appendBytes(document, scipDocumentOccurrences, occ)
+		}
+
+		index = appendBytes(index, scipIndexDocuments, document)
+	}
+
+	for _, symbol := range idx.sortedSymbols() {
+		if !documented[symbol.name] {
+			index = appendBytes(index, scipIndexExternalSymbols, scipSymbolInformation(symbol))
+		}
+	}
+
+	if _, err := w.Write(index); err != nil {
+		return fmt.Errorf("failed to write SCIP index: %w", err)
+	}
+
+	return nil
+}
+
+// scipSymbol returns the SCIP symbol of a rule or built-in function, where the package is left empty, as rules are
+// identified by their full path in the data document, e.g. rego . . . `data.authz.allow`., while built-in functions
+// are methods, e.g. rego . . . count().
+func scipSymbol(symbol *indexedSymbol) string {
+	suffix := "."
+	if symbol.builtin {
+		suffix = "()."
+	}
+
+	return monikerScheme + " . . . " + scipDescriptorName(symbol.name) + suffix
+}
+
+// scipDescriptorName returns the name as is if made up only of identifier characters, or escaped with backticks.
+func scipDescriptorName(name string) string {
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '+' ||
+			c == '-' || c == '$') {
+			return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+		}
+	}
+
+	return name
+}
+
+// scipRange returns the range in the form of SCIP, with the end line left out for ranges on a single line.
+func scipRange(rng types.Range) []uint64 {
+	if rng.Start.Line == rng.End.Line {
+		return []uint64{uint64(rng.Start.Line), uint64(rng.Start.Character), uint64(rng.End.Character)}
+	}
+
+	return []uint64{
+		uint64(rng.Start.Line), uint64(rng.Start.Character), uint64(rng.End.Line), uint64(rng.End.Character),
+	}
+}
+
+func scipSymbolInformation(symbol *indexedSymbol) []byte {
+	var info []byte
+
+	info = appendString(info, scipSymbolInformationSymbol, scipSymbol(symbol))
+
+	if symbol.hover != "" {
+		info = appendString(info, scipSymbolInformationDocumentation, symbol.hover)
+	}
+
+	return appendString(info, scipSymbolInformationDisplayName, symbol.name)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+
+	return protowire.AppendString(b, s)
+}
+
+func appendBytes(b []byte, num protowire.Number, bs []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+
+	return protowire.AppendBytes(b, bs)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+
+	return protowire.AppendVarint(b, v)
+}
+
+func appendPacked(b []byte, num protowire.Number, vs []uint64) []byte {
+	var packed []byte
+	for _, v := range vs {
+		packed = protowire.AppendVarint(packed, v)
+	}
+
+	return appendBytes(b, num, packed)
+}
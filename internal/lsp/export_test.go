@@ -0,0 +1,182 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func indexModules(t *testing.T) map[string]*ast.Module {
+	t.Helper()
+
+	files := map[string]string{
+		"/ws/p/a.rego": `package a
+
+import rego.v1
+
+import data.b
+
+# METADATA
+# title: Allow
+allow if {
+	count(input.roles) > 0
+	b.admin
+}
+`,
+		"/ws/p/b.rego": `package b
+
+import rego.v1
+
+admin if "admin" in input.roles
+`,
+	}
+
+	modules := make(map[string]*ast.Module, len(files))
+
+	for file, policy := range files {
+		module, err := parse.Module(file, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		modules[file] = module
+	}
+
+	return modules
+}
+
+func TestWriteIndexLSIF(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := WriteIndex(&buf, IndexFormatLSIF, "/ws", indexModules(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	elements := make(map[int]map[string]any)
+	monikers := make([]string, 0)
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var element map[string]any
+		if err := json.Unmarshal([]byte(line), &element); err != nil {
+			t.Fatalf("expected JSON line, got %s", line)
+		}
+
+		elements[int(element["id"].(float64))] = element
+
+		if element["type"] == "vertex" && element["label"] == "moniker" {
+			monikers = append(monikers, element["identifier"].(string))
+		}
+	}
+
+	if exp := []string{"count", "data.a.allow", "data.b.admin"}; !slices.Equal(monikers, exp) {
+		t.Errorf("expected monikers %v, got %v", exp, monikers)
+	}
+
+	// the definition of data.b.admin is found in b.rego, referenced from a.rego
+	var found bool
+
+	for _, element := range elements {
+		if element["label"] != "item" || element["property"] != nil {
+			continue
+		}
+
+		document := elements[int(element["document"].(float64))]
+		if document["uri"] != "file:///ws/p/b.rego" {
+			continue
+		}
+
+		found = true
+
+		rng := elements[int(element["inVs"].([]any)[0].(float64))]
+		if start := rng["start"].(map[string]any); start["line"] != float64(4) || start["character"] != float64(0) {
+			t.Errorf("expected definition of admin at 4:0, got %v", start)
+		}
+	}
+
+	if !found {
+		t.Error("expected definition of admin in b.rego")
+	}
+}
+
+func TestWriteIndexSCIP(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := WriteIndex(&buf, IndexFormatSCIP, "/ws", indexModules(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	index := decodeMessage(t, buf.Bytes())
+
+	paths := make([]string, 0)
+	occurrences := make(map[string][]string)
+
+	for _, document := range index[scipIndexDocuments] {
+		fields := decodeMessage(t, document)
+		path := string(fields[scipDocumentRelativePath][0])
+
+		paths = append(paths, path)
+
+		for _, occurrence := range fields[scipDocumentOccurrences] {
+			occurrences[path] = append(occurrences[path], string(decodeMessage(t, occurrence)[scipOccurrenceSymbol][0]))
+		}
+	}
+
+	if exp := []string{"p/a.rego", "p/b.rego"}; !slices.Equal(paths, exp) {
+		t.Errorf("expected documents %v, got %v", exp, paths)
+	}
+
+	exp := []string{"rego . . . `data.a.allow`.", "rego . . . count().", "rego . . . `data.b.admin`."}
+	if !slices.Equal(occurrences["p/a.rego"], exp) {
+		t.Errorf("expected occurrences %v, got %v", exp, occurrences["p/a.rego"])
+	}
+
+	// built-in functions aren't defined in any document, and so are external symbols
+	external := index[scipIndexExternalSymbols]
+	if len(external) != 1 || string(decodeMessage(t, external[0])[scipSymbolInformationSymbol][0]) != exp[1] {
+		t.Errorf("expected count to be the only external symbol, got %d symbols", len(external))
+	}
+}
+
+// decodeMessage returns the values of the length-delimited fields of a protobuf message, by field number.
+func decodeMessage(t *testing.T, bs []byte) map[protowire.Number][][]byte {
+	t.Helper()
+
+	fields := make(map[protowire.Number][][]byte)
+
+	for len(bs) > 0 {
+		num, typ, n := protowire.ConsumeTag(bs)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+
+		bs = bs[n:]
+
+		if typ == protowire.BytesType {
+			value, m := protowire.ConsumeBytes(bs)
+			fields[num] = append(fields[num], value)
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, bs)
+		}
+
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+
+		bs = bs[n:]
+	}
+
+	return fields
+}
@@ -27,23 +27,24 @@ package lsp
 import (
 	"strings"
 
+	"github.com/styrainc/regal/internal/diff"
 	"github.com/styrainc/regal/internal/lsp/types"
 )
 
 // ComputeEdits computes diff edits from 2 string inputs.
 func ComputeEdits(before, after string) []types.TextEdit {
-	ops := operations(splitLines(before), splitLines(after))
+	ops := diff.Operations(diff.SplitLines(before), diff.SplitLines(after))
 	edits := make([]types.TextEdit, 0, len(ops))
 
 	for _, op := range ops {
 		switch op.Kind {
-		case Delete:
+		case diff.Delete:
 			// Delete: unformatted[i1:i2] is deleted.
 			edits = append(edits, types.TextEdit{Range: types.Range{
 				Start: types.Position{Line: op.I1, Character: 0},
 				End:   types.Position{Line: op.I2, Character: 0},
 			}})
-		case Insert:
+		case diff.Insert:
 			// Insert: formatted[j1:j2] is inserted at unformatted[i1:i1].
 			if content := strings.Join(op.Content, ""); content != "" {
 				edits = append(edits, types.TextEdit{
@@ -54,7 +55,7 @@ func ComputeEdits(before, after string) []types.TextEdit {
 					NewText: content,
 				})
 			}
-		case Equal:
+		case diff.Equal:
 		}
 	}
 
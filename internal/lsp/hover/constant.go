@@ -0,0 +1,177 @@
+package hover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+
+	"github.com/styrainc/regal/internal/builtindb"
+	types2 "github.com/styrainc/regal/internal/lsp/types"
+)
+
+const (
+	constantEvalTimeout = 1 * time.Second
+
+	// constantMaxLines is the maximum number of lines of a value to show in hover,
+	// as large lookup tables would otherwise fill the whole screen.
+	constantMaxLines = 50
+)
+
+// ConstantRuleAt returns the rule whose head name is found at the provided position (0-indexed),
+// if that rule evaluates to a constant value, i.e. has no dependency on input, data outside of the
+// module, imports, or non-deterministic built-in functions. The range of the rule name is returned
+// along with the rule.
+func ConstantRuleAt(module *ast.Module, line, character uint) (*ast.Rule, types2.Range, bool) {
+	for _, rule := range module.Rules {
+		ref := rule.Head.Ref()
+
+		first, last := ref[0].Location, ref[len(ref)-1].Location
+		if first == nil || last == nil || uint(first.Row-1) != line {
+			continue
+		}
+
+		start, end := uint(first.Col-1), uint(last.Col-1+len(last.Text))
+		if character < start || character > end {
+			continue
+		}
+
+		if len(rule.Head.Args) > 0 || !isConstant(module, ref.GroundPrefix(), map[string]bool{}) {
+			return nil, types2.Range{}, false
+		}
+
+		return rule, types2.Range{
+			Start: types2.Position{Line: line, Character: start},
+			End:   types2.Position{Line: line, Character: end},
+		}, true
+	}
+
+	return nil, types2.Range{}, false
+}
+
+// isConstant checks all rules in the module contributing to ref, and transitively, any rules
+// or functions of the module referenced by them, for dependencies on anything outside the module.
+func isConstant(module *ast.Module, ref ast.Ref, visited map[string]bool) bool {
+	if visited[ref.String()] {
+		return true
+	}
+
+	visited[ref.String()] = true
+
+	imported := make(map[ast.Var]bool, len(module.Imports))
+	for _, imp := range module.Imports {
+		imported[imp.Name()] = true
+	}
+
+	constant := true
+
+	for _, rule := range module.Rules {
+		if !rule.Head.Ref().GroundPrefix().Equal(ref) {
+			continue
+		}
+
+		ast.WalkTerms(rule, func(term *ast.Term) bool {
+			var head ast.Var
+
+			switch v := term.Value.(type) {
+			case ast.Var:
+				head = v
+			case ast.Ref:
+				if builtin, ok := builtindb.Default().Get(v.String()); ok {
+					constant = constant && !builtin.Nondeterministic
+
+					return !constant
+				}
+
+				first, ok := v[0].Value.(ast.Var)
+				if !ok {
+					return false
+				}
+
+				head = first
+			default:
+				return false
+			}
+
+			switch {
+			case head.Equal(ast.InputRootDocument.Value) || head.Equal(ast.DefaultRootDocument.Value):
+				constant = false
+			case imported[head]:
+				constant = false
+			default:
+				for _, other := range module.Rules {
+					otherRef := other.Head.Ref()
+					if otherRef[0].Value.Compare(head) == 0 {
+						constant = constant && isConstant(module, otherRef.GroundPrefix(), visited)
+					}
+				}
+			}
+
+			return !constant
+		})
+
+		if !constant {
+			return false
+		}
+	}
+
+	return constant
+}
+
+// EvalConstant evaluates the provided rule in the context of its module only.
+func EvalConstant(ctx context.Context, module *ast.Module, rule *ast.Rule) (any, bool, error) {
+	ref := module.Package.Path.Copy()
+
+	for i, term := range rule.Head.Ref().GroundPrefix() {
+		if v, ok := term.Value.(ast.Var); ok && i == 0 {
+			ref = append(ref, ast.StringTerm(string(v)))
+		} else {
+			ref = append(ref, term)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constantEvalTimeout)
+	defer cancel()
+
+	// the compiler modifies the modules provided, so a copy is used to leave the cached module untouched
+	rs, err := rego.New(
+		rego.Query(ref.String()),
+		rego.ParsedModule(module.Copy()),
+	).Eval(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to evaluate %s: %w", ref, err)
+	}
+
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, false, nil
+	}
+
+	return rs[0].Expressions[0].Value, true, nil
+}
+
+// CreateConstantHoverContent returns the hover content for a rule evaluating to value.
+func CreateConstantHoverContent(rule *ast.Rule, value any) (string, error) {
+	bs, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	lines := strings.Split(string(bs), "\n")
+	if len(lines) > constantMaxLines {
+		lines = append(lines[:constantMaxLines], "...")
+	}
+
+	sb := &strings.Builder{}
+
+	sb.WriteString("### ")
+	sb.WriteString(rule.Head.Ref().GroundPrefix().String())
+	sb.WriteString("\n\nEvaluates to:\n\n```json\n")
+	sb.WriteString(strings.Join(lines, "\n"))
+	sb.WriteString("\n```\n")
+
+	return sb.String(), nil
+}
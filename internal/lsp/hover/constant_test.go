@@ -0,0 +1,105 @@
+package hover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestConstantRuleAt(t *testing.T) {
+	t.Parallel()
+
+	module := parse.MustParseModule(`package p
+
+import rego.v1
+
+import data.users
+
+roles := {"admin", "user"}
+
+role_count := count(roles)
+
+default allow := false
+
+allow if input.admin
+
+now := time.now_ns()
+
+admins := [u | some u in users]
+
+f(x) := x
+`)
+
+	testCases := map[string]struct {
+		line     uint
+		char     uint
+		constant bool
+	}{
+		"constant set":                  {line: 6, char: 2, constant: true},
+		"constant from constant rule":   {line: 8, char: 0, constant: true},
+		"default with input dependency": {line: 10, char: 9},
+		"input dependency":              {line: 12, char: 2},
+		"non-deterministic builtin":     {line: 14, char: 1},
+		"import dependency":             {line: 16, char: 1},
+		"function":                      {line: 18, char: 0},
+		"outside of rule name":          {line: 6, char: 12},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, ok := ConstantRuleAt(module, tc.line, tc.char)
+			if ok != tc.constant {
+				t.Errorf("expected constant to be %t, got %t", tc.constant, ok)
+			}
+		})
+	}
+}
+
+func TestEvalConstant(t *testing.T) {
+	t.Parallel()
+
+	module := parse.MustParseModule(`package p
+
+import rego.v1
+
+roles := {"admin", "user"}
+
+role_count := count(roles)
+`)
+
+	rule, rng, ok := ConstantRuleAt(module, 6, 3)
+	if !ok {
+		t.Fatalf("expected role_count to be constant")
+	}
+
+	if rng.Start.Character != 0 || rng.End.Character != 10 {
+		t.Errorf("expected range to cover rule name, got %v", rng)
+	}
+
+	value, ok, err := EvalConstant(context.Background(), module, rule)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !ok {
+		t.Fatalf("expected value to be defined")
+	}
+
+	content, err := CreateConstantHoverContent(rule, value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "### role_count\n\nEvaluates to:\n\n```json\n2\n```\n"
+	if content != expected {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+
+	// the cached module must not have been modified by compilation
+	if expr := module.Rules[1].Head.Value.String(); expr != "count(roles)" {
+		t.Errorf("expected module to be left unmodified, got %s", expr)
+	}
+}
@@ -1,30 +1,29 @@
 package hover
 
 import (
+	"cmp"
 	"fmt"
 	"strings"
 	"sync"
 
 	"github.com/olekukonko/tablewriter"
 
-	"github.com/open-policy-agent/opa/ast"
-	"github.com/open-policy-agent/opa/types"
-
+	"github.com/styrainc/regal/internal/builtindb"
 	"github.com/styrainc/regal/internal/lsp/cache"
 	"github.com/styrainc/regal/internal/lsp/rego"
 	types2 "github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/schemas"
 )
 
-var builtinCache = make(map[*ast.Builtin]string) //nolint:gochecknoglobals
+var builtinCache = make(map[*builtindb.Builtin]string) //nolint:gochecknoglobals
 
 var builtinCacheLock = &sync.Mutex{} //nolint:gochecknoglobals
 
-func writeFunctionSnippet(sb *strings.Builder, builtin *ast.Builtin) {
+func writeFunctionSnippet(sb *strings.Builder, builtin *builtindb.Builtin) {
 	sb.WriteString("```rego\n")
 
-	resType := builtin.Decl.NamedResult()
-	if n, ok := resType.(*types.NamedType); ok {
-		sb.WriteString(n.Name)
+	if builtin.Result != nil && builtin.Result.Name != "" {
+		sb.WriteString(builtin.Result.Name)
 	} else {
 		sb.WriteString("output")
 	}
@@ -34,22 +33,18 @@ func writeFunctionSnippet(sb *strings.Builder, builtin *ast.Builtin) {
 	sb.WriteString(builtin.Name)
 	sb.WriteString("(")
 
-	for i, arg := range builtin.Decl.NamedFuncArgs().Args {
+	for i, arg := range builtin.Args {
 		if i > 0 {
 			sb.WriteString(", ")
 		}
 
-		if n, ok := arg.(*types.NamedType); ok {
-			sb.WriteString(n.Name)
-		} else {
-			sb.WriteString(arg.String())
-		}
+		sb.WriteString(cmp.Or(arg.Name, arg.Type))
 	}
 
 	sb.WriteString(")\n```")
 }
 
-func CreateHoverContent(builtin *ast.Builtin) string {
+func CreateHoverContent(builtin *builtindb.Builtin) string {
 	builtinCacheLock.Lock()
 	if content, ok := builtinCache[builtin]; ok {
 		builtinCacheLock.Unlock()
@@ -58,12 +53,7 @@ func CreateHoverContent(builtin *ast.Builtin) string {
 	}
 	builtinCacheLock.Unlock()
 
-	title := fmt.Sprintf(
-		"[%s](https://www.openpolicyagent.org/docs/latest/policy-reference/#builtin-%s-%s)",
-		builtin.Name,
-		rego.BuiltinCategory(builtin),
-		strings.ReplaceAll(builtin.Name, ".", ""),
-	)
+	title := fmt.Sprintf("[%s](%s)", builtin.Name, builtin.DocumentationURL())
 
 	sb := &strings.Builder{}
 
@@ -77,7 +67,25 @@ func CreateHoverContent(builtin *ast.Builtin) string {
 	sb.WriteString(builtin.Description)
 	sb.WriteString("\n")
 
-	if len(builtin.Decl.FuncArgs().Args) == 0 {
+	if builtin.Deprecated {
+		sb.WriteString("\n**Deprecated**")
+
+		if builtin.Replacement != "" {
+			sb.WriteString(": use ")
+			sb.WriteString(builtin.Replacement)
+			sb.WriteString(" instead")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	if builtin.Since != "" {
+		sb.WriteString("\nAvailable since OPA ")
+		sb.WriteString(builtin.Since)
+		sb.WriteString("\n")
+	}
+
+	if len(builtin.Args) == 0 {
 		return sb.String()
 	}
 
@@ -95,11 +103,11 @@ func CreateHoverContent(builtin *ast.Builtin) string {
 
 	argsData := make([][]string, 0)
 
-	for _, arg := range builtin.Decl.NamedFuncArgs().Args {
-		if n, ok := arg.(*types.NamedType); ok {
-			argsData = append(argsData, []string{"`" + n.Name + "`", n.Type.String(), n.Descr})
+	for _, arg := range builtin.Args {
+		if arg.Name != "" {
+			argsData = append(argsData, []string{"`" + arg.Name + "`", arg.Type, arg.Description})
 		} else {
-			argsData = append(argsData, []string{"`" + arg.String() + "`", "", ""})
+			argsData = append(argsData, []string{"`" + arg.Type + "`", "", ""})
 		}
 	}
 
@@ -110,16 +118,15 @@ func CreateHoverContent(builtin *ast.Builtin) string {
 
 	sb.WriteString("\n\nReturns ")
 
-	ret := builtin.Decl.NamedResult()
-	if n, ok := ret.(*types.NamedType); ok {
+	if ret := builtin.Result; ret != nil && ret.Name != "" {
 		sb.WriteString("`")
-		sb.WriteString(n.Name)
+		sb.WriteString(ret.Name)
 		sb.WriteString("` of type `")
-		sb.WriteString(n.Type.String())
+		sb.WriteString(ret.Type)
 		sb.WriteString("`: ")
-		sb.WriteString(n.Descr)
+		sb.WriteString(ret.Description)
 	} else if ret != nil {
-		sb.WriteString(ret.String())
+		sb.WriteString(ret.Type)
 	}
 
 	sb.WriteString("\n")
@@ -156,3 +163,23 @@ func UpdateBuiltinPositions(cache *cache.Cache, uri string) error {
 
 	return nil
 }
+
+// CreateInputHoverContent returns the hover content of a reference to input, showing the type and description of
+// the field referenced, as found in the schema of input.
+func CreateInputHoverContent(ref string, field schemas.Field) string {
+	sb := &strings.Builder{}
+
+	sb.WriteString("```rego\n")
+	sb.WriteString(ref)
+	sb.WriteString("\n```\n\nType: `")
+	sb.WriteString(field.Type)
+	sb.WriteString("`\n")
+
+	if field.Description != "" {
+		sb.WriteString("\n")
+		sb.WriteString(field.Description)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
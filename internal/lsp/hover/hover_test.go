@@ -4,26 +4,26 @@ import (
 	"os"
 	"testing"
 
-	"github.com/open-policy-agent/opa/ast"
+	"github.com/styrainc/regal/internal/builtindb"
 )
 
 func TestCreateHoverContent(t *testing.T) {
 	t.Parallel()
 
 	cases := []struct {
-		builtin  *ast.Builtin
+		builtin  string
 		testdata string
 	}{
 		{
-			ast.IndexOf,
+			"indexof",
 			"testdata/hover/indexof.md",
 		},
 		{
-			ast.ReachableBuiltin,
+			"graph.reachable",
 			"testdata/hover/graphreachable.md",
 		},
 		{
-			ast.JSONFilter,
+			"json.filter",
 			"testdata/hover/jsonfilter.md",
 		},
 	}
@@ -34,7 +34,12 @@ func TestCreateHoverContent(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		hoverContent := CreateHoverContent(c.builtin)
+		builtin, ok := builtindb.Default().Get(c.builtin)
+		if !ok {
+			t.Fatalf("expected built-in function %s", c.builtin)
+		}
+
+		hoverContent := CreateHoverContent(builtin)
 
 		if string(file) != hoverContent {
 			t.Errorf("Expected %s, got %s", string(file), hoverContent)
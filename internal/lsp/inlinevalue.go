@@ -0,0 +1,87 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+// getInlineValues returns the variables of the rules containing the location a debugger stopped at, for the
+// client to look up their values in the stopped frame, and render them inline. Only variables found in the range
+// requested, and before the stopped location, are included, as those after it aren't bound yet.
+func getInlineValues(module *ast.Module, rng types.Range, stopped types.Range) []types.InlineValueVariableLookup {
+	inlineValues := make([]types.InlineValueVariableLookup, 0)
+
+	// references to rules are parsed as variables too, but aren't bound in the frame of the debugger
+	ruleNames := make(map[ast.Var]struct{}, len(module.Rules))
+	for _, rule := range module.Rules {
+		ruleNames[rule.Head.Ref()[0].Value.(ast.Var)] = struct{}{} //nolint:forcetypeassert
+	}
+
+	for _, rule := range module.Rules {
+		if rule.Location == nil || !contains(locationToRange(rule.Location), stopped.Start) {
+			continue
+		}
+
+		var visitor *ast.GenericVisitor
+
+		visitor = ast.NewGenericVisitor(func(x any) bool {
+			switch x := x.(type) {
+			case *ast.Expr:
+				// operators of calls, like assign or count, aren't variables
+				if x.IsCall() {
+					for _, operand := range x.Operands() {
+						visitor.Walk(operand)
+					}
+
+					for _, with := range x.With {
+						visitor.Walk(with)
+					}
+
+					return true
+				}
+			case ast.Call:
+				for _, operand := range x[1:] {
+					visitor.Walk(operand)
+				}
+
+				return true
+			case *ast.Term:
+				name, ok := x.Value.(ast.Var)
+				if !ok || x.Location == nil || !isInlineValueVar(name) {
+					return false
+				}
+
+				if _, ok := ruleNames[name]; ok {
+					return false
+				}
+
+				r := termRange(x.Location)
+				if !contains(rng, r.Start) || !contains(types.Range{Start: rng.Start, End: stopped.End}, r.End) {
+					return false
+				}
+
+				inlineValues = append(inlineValues, types.InlineValueVariableLookup{
+					Range:               r,
+					VariableName:        string(name),
+					CaseSensitiveLookup: true,
+				})
+			}
+
+			return false
+		})
+
+		visitor.Walk(rule)
+	}
+
+	return inlineValues
+}
+
+// isInlineValueVar returns true if the variable may be bound in the frame of a debugger, unlike the input and data
+// documents, and variables generated by the compiler, like those of wildcards.
+func isInlineValueVar(name ast.Var) bool {
+	return !name.Equal(ast.InputRootDocument.Value) && !name.Equal(ast.DefaultRootDocument.Value) &&
+		!strings.HasPrefix(string(name), "$")
+}
@@ -0,0 +1,62 @@
+package lsp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestGetInlineValues(t *testing.T) {
+	t.Parallel()
+
+	policy := `package p
+
+import rego.v1
+
+allow if {
+	some role in input.user.roles
+	_ := input.x
+	n := count(role)
+	n > other
+	m := n + 1
+}
+
+other := 1
+`
+
+	module := parse.MustParseModule(policy)
+
+	visible := types.Range{Start: types.Position{Line: 0}, End: types.Position{Line: 12}}
+	// stopped at the expression n > other
+	stopped := types.Range{
+		Start: types.Position{Line: 8, Character: 1},
+		End:   types.Position{Line: 8, Character: 10},
+	}
+
+	inlineValues := getInlineValues(module, visible, stopped)
+
+	names := make([]string, 0, len(inlineValues))
+	for _, inlineValue := range inlineValues {
+		names = append(names, inlineValue.VariableName)
+	}
+
+	// input, wildcards, the count call, the other rule, and m after the stopped location, aren't included
+	if exp := []string{"role", "n", "role", "n"}; !slices.Equal(names, exp) {
+		t.Fatalf("expected variables %v, got %v", exp, names)
+	}
+
+	exp := types.Range{
+		Start: types.Position{Line: 7, Character: 1},
+		End:   types.Position{Line: 7, Character: 2},
+	}
+
+	if inlineValues[1].Range != exp {
+		t.Errorf("expected range %v, got %v", exp, inlineValues[1].Range)
+	}
+
+	if outside := getInlineValues(module, visible, types.Range{}); len(outside) != 0 {
+		t.Errorf("expected no inline values when stopped outside of rules, got %v", outside)
+	}
+}
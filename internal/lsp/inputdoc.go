@@ -0,0 +1,140 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/styrainc/regal/internal/inputdoc"
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/lsp/uri"
+)
+
+const (
+	commandSetInputDocument = "regal.input.set"
+
+	inputSourceActive     = "active"
+	inputSourceConfig     = "config"
+	inputSourceDiscovered = "discovered"
+	inputSourceNone       = "none"
+)
+
+// inputDocument returns the input document used for evaluating the file, in order of precedence: the one made active
+// by the regal.input.set command, the one set by language-server.input-path in the configuration, or the input.json
+// or input.yaml file nearest to the file, falling back to the one at the root of the workspace.
+func (l *LanguageServer) inputDocument(fileURI string) types.InputDocument {
+	l.inputDocumentLock.Lock()
+	active := l.activeInputDocument
+	l.inputDocumentLock.Unlock()
+
+	if active != "" {
+		return types.InputDocument{URI: uri.FromPath(l.clientIdentifier, active), Source: inputSourceActive}
+	}
+
+	rootPath := ""
+	if l.clientRootURI != "" {
+		rootPath = uri.ToPath(l.clientIdentifier, l.clientRootURI)
+	}
+
+	l.loadedConfigLock.Lock()
+	configured := ""
+	if l.loadedConfig != nil {
+		configured = l.loadedConfig.LanguageServer.InputPath
+	}
+	l.loadedConfigLock.Unlock()
+
+	if configured != "" {
+		if !filepath.IsAbs(configured) {
+			configured = filepath.Join(rootPath, configured)
+		}
+
+		// a path configured but missing is likely a mistake, but one better reported by discovering the input
+		// document as usual than by having none
+		if _, err := os.Stat(configured); err == nil {
+			return types.InputDocument{URI: uri.FromPath(l.clientIdentifier, configured), Source: inputSourceConfig}
+		}
+
+		l.logError(fmt.Errorf("input document %s set in configuration not found", configured))
+	}
+
+	path, ok := inputdoc.Find(filepath.Dir(uri.ToPath(l.clientIdentifier, fileURI)), rootPath)
+	if !ok {
+		return types.InputDocument{Source: inputSourceNone}
+	}
+
+	return types.InputDocument{URI: uri.FromPath(l.clientIdentifier, path), Source: inputSourceDiscovered}
+}
+
+// inputDocumentPath returns the path of the input document used for evaluating the file, or an empty string if none.
+func (l *LanguageServer) inputDocumentPath(fileURI string) string {
+	if doc := l.inputDocument(fileURI); doc.URI != "" {
+		return uri.ToPath(l.clientIdentifier, doc.URI)
+	}
+
+	return ""
+}
+
+// setActiveInputDocument handles the regal.input.set command, making the input document provided, as a URI or a path,
+// the one used for evaluating all files, until switched to another, or cleared by providing no document, or an empty
+// one, after which input documents are found as usual again.
+func (l *LanguageServer) setActiveInputDocument(params types.ExecuteCommandParams) (types.InputDocument, error) {
+	target := ""
+
+	if len(params.Arguments) > 0 {
+		s, ok := params.Arguments[0].(string)
+		if !ok {
+			return types.InputDocument{}, fmt.Errorf("expected input document to be a string, got %T", params.Arguments[0])
+		}
+
+		target = s
+	}
+
+	path := target
+	if strings.HasPrefix(target, "file://") {
+		path = uri.ToPath(l.clientIdentifier, target)
+	}
+
+	if path != "" {
+		if !filepath.IsAbs(path) && l.clientRootURI != "" {
+			path = filepath.Join(uri.ToPath(l.clientIdentifier, l.clientRootURI), path)
+		}
+
+		// read, to tell the user right away if the document can't be used
+		if _, err := inputdoc.Read(path); err != nil {
+			return types.InputDocument{}, fmt.Errorf("failed to set input document: %w", err)
+		}
+	}
+
+	l.inputDocumentLock.Lock()
+	l.activeInputDocument = path
+	l.inputDocumentLock.Unlock()
+
+	if path == "" {
+		return types.InputDocument{Source: inputSourceNone}, nil
+	}
+
+	return types.InputDocument{URI: uri.FromPath(l.clientIdentifier, path), Source: inputSourceActive}, nil
+}
+
+func (l *LanguageServer) handleRegalInputDocument(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.InputDocumentParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	if params.TextDocument.URI == "" {
+		return nil, errors.New("textDocument is required")
+	}
+
+	return l.inputDocument(params.TextDocument.URI), nil
+}
@@ -0,0 +1,118 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/pkg/config"
+)
+
+func TestInputDocument(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"input.json":              `{"user": "root"}`,
+		"authz/input.yaml":        "user: alice\n",
+		"authz/p.rego":            "package authz\n",
+		"other/p.rego":            "package other\n",
+		"inputs/admin.json":       `{"user": "admin"}`,
+		"inputs/broken.json":      `{"user":`,
+		"configured/testing.json": `{"user": "configured"}`,
+	}
+
+	for f, fc := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(tempDir, f)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(tempDir, f), []byte(fc), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr})
+	ls.clientRootURI = fileURIScheme + tempDir
+
+	authzURI := fileURIScheme + tempDir + "/authz/p.rego"
+
+	inputDocument := func(fileURI string) types.InputDocument {
+		t.Helper()
+
+		params := json.RawMessage(`{"textDocument": {"uri": "` + fileURI + `"}}`)
+
+		result, err := ls.Handle(context.Background(), nil, &jsonrpc2.Request{Method: "regal/inputDocument", Params: &params})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc, ok := result.(types.InputDocument)
+		if !ok {
+			t.Fatalf("expected result to be an input document, got %T", result)
+		}
+
+		return doc
+	}
+
+	setInputDocument := func(args ...any) error {
+		t.Helper()
+
+		bs, err := json.Marshal(types.ExecuteCommandParams{Command: commandSetInputDocument, Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params := json.RawMessage(bs)
+
+		_, err = ls.Handle(context.Background(), nil, &jsonrpc2.Request{Method: "workspace/executeCommand", Params: &params})
+
+		return err
+	}
+
+	expect := func(doc types.InputDocument, path, source string) {
+		t.Helper()
+
+		expectedURI := ""
+		if path != "" {
+			expectedURI = fileURIScheme + filepath.Join(tempDir, path)
+		}
+
+		if doc.URI != expectedURI || doc.Source != source {
+			t.Errorf("expected input document %q from %s, got %q from %s", expectedURI, source, doc.URI, doc.Source)
+		}
+	}
+
+	expect(inputDocument(authzURI), "authz/input.yaml", inputSourceDiscovered)
+	expect(inputDocument(fileURIScheme+tempDir+"/other/p.rego"), "input.json", inputSourceDiscovered)
+
+	ls.loadedConfig = &config.Config{LanguageServer: config.LanguageServer{InputPath: "configured/testing.json"}}
+
+	expect(inputDocument(authzURI), "configured/testing.json", inputSourceConfig)
+
+	if err := setInputDocument("inputs/admin.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(inputDocument(authzURI), "inputs/admin.json", inputSourceActive)
+
+	if err := setInputDocument(fileURIScheme + tempDir + "/inputs/broken.json"); err == nil {
+		t.Error("expected error setting invalid input document")
+	}
+
+	expect(inputDocument(authzURI), "inputs/admin.json", inputSourceActive)
+
+	if err := setInputDocument(); err != nil {
+		t.Fatal(err)
+	}
+
+	ls.loadedConfig = nil
+
+	expect(inputDocument(authzURI), "authz/input.yaml", inputSourceDiscovered)
+}
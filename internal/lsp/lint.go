@@ -2,6 +2,7 @@ package lsp
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,14 +16,50 @@ import (
 	"github.com/styrainc/regal/pkg/config"
 	"github.com/styrainc/regal/pkg/hints"
 	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/report"
 	"github.com/styrainc/regal/pkg/rules"
 )
 
+// diagnosticTagUnnecessary and diagnosticTagDeprecated are the diagnostic tags defined in the LSP
+// specification, which clients may use to render diagnostics as faded out or struck through.
+const (
+	diagnosticTagUnnecessary uint = 1
+	diagnosticTagDeprecated  uint = 2
+)
+
+// diagnosticTags maps rules reporting unnecessary or deprecated code to the tag that
+// should be attached to their diagnostics.
+var diagnosticTags = map[string][]uint{ //nolint:gochecknoglobals
+	"deprecated-builtin":        {diagnosticTagDeprecated},
+	"import-shadows-import":     {diagnosticTagUnnecessary},
+	"redundant-alias":           {diagnosticTagUnnecessary},
+	"redundant-data-import":     {diagnosticTagUnnecessary},
+	"redundant-existence-check": {diagnosticTagUnnecessary},
+	"unnecessary-some":          {diagnosticTagUnnecessary},
+	"unused-rule":               {diagnosticTagUnnecessary},
+}
+
+// violationTags returns the tags to attach to the diagnostic of a violation. The strict-mode rule reports both unused
+// variables, arguments and imports, which are tagged as unnecessary, and shadowing, which isn't, under the same title.
+func violationTags(item report.Violation) []uint {
+	if item.Title != "strict-mode" {
+		return diagnosticTags[item.Title]
+	}
+
+	_, message, _ := strings.Cut(item.Description, ": ")
+	if strings.HasSuffix(message, " unused") || strings.HasPrefix(message, "unused argument ") {
+		return []uint{diagnosticTagUnnecessary}
+	}
+
+	return nil
+}
+
 // updateParse updates the module cache with the latest parse result for a given URI,
 // if the module cannot be parsed, the parse errors are saved as diagnostics for the
-// URI instead.
+// URI instead. Results are discarded if contents of a newer document version have been
+// received while parsing.
 func updateParse(cache *cache.Cache, uri string) (bool, error) {
-	content, ok := cache.GetFileContents(uri)
+	content, version, ok := cache.GetFileContentsAndVersion(uri)
 	if !ok {
 		return false, fmt.Errorf("failed to get file contents for uri %q", uri)
 	}
@@ -32,8 +69,13 @@ func updateParse(cache *cache.Cache, uri string) (bool, error) {
 	module, err := rparse.Module(uri, content)
 	if err == nil {
 		// if the parse was ok, clear the parse errors
-		cache.SetParseErrors(uri, []types.Diagnostic{})
-		cache.SetModule(uri, module)
+		if !cache.SetParseErrorsVersion(uri, []types.Diagnostic{}, version) {
+			return false, nil
+		}
+
+		if cache.SetModuleVersion(uri, module, version) {
+			cache.SetSymbols(uri, indexSymbols(module))
+		}
 
 		return true, nil
 	}
@@ -109,170 +151,171 @@ func updateParse(cache *cache.Cache, uri string) (bool, error) {
 		})
 	}
 
-	cache.SetParseErrors(uri, diags)
+	cache.SetParseErrorsVersion(uri, diags, version)
 
 	return false, nil
 }
 
+// allModules returns the modules of all files in the cache. Modules evicted from the cache are parsed
+// again from the file contents, without being added back to the cache, while files which failed to
+// parse are left out.
+func allModules(cache *cache.Cache) map[string]*ast.Module {
+	modules := cache.GetAllModules()
+
+	for uri, contents := range cache.GetAllFiles() {
+		if _, ok := modules[uri]; ok {
+			continue
+		}
+
+		if parseErrors, ok := cache.GetParseErrors(uri); ok && len(parseErrors) > 0 {
+			continue
+		}
+
+		module, err := rparse.Module(uri, contents)
+		if err != nil {
+			continue
+		}
+
+		modules[uri] = module
+	}
+
+	return modules
+}
+
+// updateFileDiagnostics lints the file at uri and updates its diagnostics, returning the report of linting it, or nil
+// when the file wasn't linted, as it failed to parse, or the result of linting the same contents was reused.
 func updateFileDiagnostics(
 	ctx context.Context,
 	cache *cache.Cache,
 	regalConfig *config.Config,
+	custom customRules,
 	uri string,
 	rootDir string,
-) error {
+) (*report.Report, error) {
 	module, ok := cache.GetModule(uri)
 	if !ok {
 		// then there must have been a parse error
-		return nil
+		return nil, nil //nolint:nilnil
 	}
 
-	contents, ok := cache.GetFileContents(uri)
+	contents, version, ok := cache.GetFileContentsAndVersion(uri)
 	if !ok {
-		return fmt.Errorf("failed to get file contents for uri %q", uri)
-	}
-
-	input := rules.NewInput(map[string]string{uri: contents}, map[string]*ast.Module{uri: module})
-
-	regalInstance := linter.NewLinter().
-		WithInputModules(&input).
-		WithRootDir(rootDir)
-
-	if regalConfig != nil {
-		regalInstance = regalInstance.WithUserConfig(*regalConfig)
+		return nil, fmt.Errorf("failed to get file contents for uri %q", uri)
 	}
 
-	rpt, err := regalInstance.Lint(ctx)
+	key, err := lintResultKey(regalConfig, custom, rootDir, uri, contents)
 	if err != nil {
-		return fmt.Errorf("failed to lint: %w", err)
+		return nil, err
 	}
 
-	diags := make([]types.Diagnostic, 0)
+	// contents linted before, e.g. prior to an edit since undone, are not linted again
+	var linted *report.Report
 
-	for _, item := range rpt.Violations {
-		itemLen := 0
-		if item.Location.Text != nil {
-			itemLen = len(*item.Location.Text)
-		}
+	rpt, ok := cache.LintResults.Get(key)
+	if !ok {
+		input := rules.NewInput(map[string]string{uri: contents}, map[string]*ast.Module{uri: module})
 
-		line := item.Location.Row - 1
-		if line < 0 {
-			line = 0
+		regalInstance := linter.NewLinter().
+			WithInputModules(&input).
+			WithRootDir(rootDir).
+			WithExportAggregates(true).
+			WithPreparedASTCache(cache.PreparedInputs)
+
+		if regalConfig != nil {
+			regalInstance = regalInstance.WithUserConfig(*regalConfig)
 		}
 
-		char := item.Location.Column - 1
-		if char < 0 {
-			char = 0
+		if custom.dir != "" {
+			regalInstance = regalInstance.WithCustomRules([]string{custom.dir})
 		}
 
-		// here errors are presented as warnings, and warnings as info
-		// to differentiate from parse errors
-		severity := uint(2)
-		if item.Level == "warning" {
-			severity = 3
+		if rpt, err = regalInstance.Lint(ctx); err != nil {
+			return nil, fmt.Errorf("failed to lint: %w", err)
 		}
 
-		diags = append(diags, types.Diagnostic{
-			Severity: severity,
-			Range: types.Range{
-				Start: types.Position{
-					Line:      uint(line),
-					Character: uint(char),
-				},
-				End: types.Position{
-					Line:      uint(line),
-					Character: uint(char + itemLen + 1),
-				},
-			},
-			Message: item.Description,
-			Source:  "regal/" + item.Category,
-			Code:    item.Title,
-			CodeDescription: &types.CodeDescription{
-				Href: fmt.Sprintf(
-					"https://docs.styra.com/regal/rules/%s/%s",
-					item.Category,
-					item.Title,
-				),
-			},
-		})
+		cache.LintResults.Set(key, rpt)
+
+		linted = &rpt
 	}
 
-	cache.SetFileDiagnostics(uri, diags)
+	diags := make([]types.Diagnostic, 0, len(rpt.Violations))
 
-	return nil
+	for _, item := range rpt.Violations {
+		diags = append(diags, violationToDiagnostic(item))
+	}
+
+	// diagnostics for outdated contents are dropped, as newer contents will be linted separately
+	if cache.SetFileDiagnosticsVersion(uri, diags, version) {
+		cache.AggregateData.Set(uri, rpt.Aggregates)
+	}
+
+	return linted, nil
 }
 
+// lintResultKey returns the key of the result of linting the contents of a file. The rules run are identified by
+// the configuration, as the rules of the embedded bundle are fixed, and the digest of any custom rules, along with
+// the root directory, which some rules depend on. The defaults of the configuration are included separately, as
+// they're left out when marshalling it.
+func lintResultKey(
+	regalConfig *config.Config,
+	custom customRules,
+	rootDir, uri, contents string,
+) (cache.LintResultKey, error) {
+	var defaults *config.Defaults
+	if regalConfig != nil {
+		defaults = &regalConfig.Defaults
+	}
+
+	bs, err := json.Marshal(struct {
+		Config   *config.Config   `json:"config"`
+		Defaults *config.Defaults `json:"defaults"`
+	}{regalConfig, defaults})
+	if err != nil {
+		return cache.LintResultKey{}, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	bs = append(append(append(bs, 0), custom.digest[:]...), rootDir...)
+
+	return cache.NewLintResultKey(sha256.Sum256(bs), uri, contents), nil
+}
+
+// updateAllDiagnostics lints all files of the workspace and replaces their diagnostics, returning the report.
 func updateAllDiagnostics(
 	ctx context.Context,
 	cache *cache.Cache,
 	regalConfig *config.Config,
+	custom customRules,
 	detachedURI string,
-) error {
-	modules := cache.GetAllModules()
+) (*report.Report, error) {
+	modules := allModules(cache)
 	files := cache.GetAllFiles()
 
 	input := rules.NewInput(files, modules)
 
-	regalInstance := linter.NewLinter().WithInputModules(&input).WithRootDir(detachedURI)
+	regalInstance := linter.NewLinter().
+		WithInputModules(&input).
+		WithRootDir(detachedURI).
+		WithExportAggregates(true).
+		WithPreparedASTCache(cache.PreparedInputs)
 
 	if regalConfig != nil {
 		regalInstance = regalInstance.WithUserConfig(*regalConfig)
 	}
 
+	if custom.dir != "" {
+		regalInstance = regalInstance.WithCustomRules([]string{custom.dir})
+	}
+
 	rpt, err := regalInstance.Lint(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to lint: %w", err)
+		return nil, fmt.Errorf("failed to lint: %w", err)
 	}
 
 	aggDiags := make(map[string][]types.Diagnostic)
 	fileDiags := make(map[string][]types.Diagnostic)
 
 	for _, item := range rpt.Violations {
-		itemLen := 0
-		if item.Location.Text != nil {
-			itemLen = len(*item.Location.Text)
-		}
-
-		line := item.Location.Row - 1
-		if line < 0 {
-			line = 0
-		}
-
-		char := item.Location.Column - 1
-		if char < 0 {
-			char = 0
-		}
-
-		// here errors are presented as warnings, and warnings as info
-		// to differentiate from parse errors
-		severity := uint(2)
-		if item.Level == "warning" {
-			severity = 3
-		}
-
-		diag := types.Diagnostic{
-			Severity: severity,
-			Range: types.Range{
-				Start: types.Position{
-					Line:      uint(line),
-					Character: uint(char),
-				},
-				End: types.Position{
-					Line:      uint(line),
-					Character: uint(char + itemLen + 1),
-				},
-			},
-			Message: item.Description,
-			Source:  "regal/" + item.Category,
-			Code:    item.Title,
-			CodeDescription: &types.CodeDescription{
-				Href: fmt.Sprintf(
-					"https://docs.styra.com/regal/rules/%s/%s",
-					item.Category,
-					item.Title,
-				),
-			},
-		}
+		diag := violationToDiagnostic(item)
 
 		// TODO(charlieegan3): it'd be nice to be able to only run aggregate rules in some cases, but for now, we
 		// can just run all rules each time.
@@ -287,8 +330,28 @@ func updateAllDiagnostics(
 		}
 	}
 
+	// keep the aggregates collected from each file, so that when a file changes, the aggregate
+	// diagnostics can be recomputed by collecting the aggregates of only that file again
+	aggregates := make(map[string]map[string][]report.Aggregate, len(files))
+	for uri := range files {
+		aggregates[uri] = make(map[string][]report.Aggregate)
+	}
+
+	for key, collected := range rpt.Aggregates {
+		for _, aggregate := range collected {
+			if fileAggregates, ok := aggregates[aggregate.SourceFile()]; ok {
+				fileAggregates[key] = append(fileAggregates[key], aggregate)
+			}
+		}
+	}
+
+	cache.AggregateData.SetForFiles(aggregates)
+
 	// this lint contains authoritative information about all files
 	// all diagnostics are cleared and replaced with the new lint
+	newAggDiags := make(map[string][]types.Diagnostic, len(files)+1)
+	newFileDiags := make(map[string][]types.Diagnostic, len(files))
+
 	for uri := range files {
 		// if a file has parse errors, then we continue to show these until they're addressed
 		// as if there are lint results they must be based on an old, parsed version of the file
@@ -302,14 +365,14 @@ func updateAllDiagnostics(
 			ad = []types.Diagnostic{}
 		}
 
-		cache.SetAggregateDiagnostics(uri, ad)
+		newAggDiags[uri] = ad
 
 		fd, ok := fileDiags[uri]
 		if !ok {
 			fd = []types.Diagnostic{}
 		}
 
-		cache.SetFileDiagnostics(uri, fd)
+		newFileDiags[uri] = fd
 	}
 
 	// handle the diagnostics for the workspace, under the detachedURI
@@ -318,11 +381,152 @@ func updateAllDiagnostics(
 		ad = []types.Diagnostic{}
 	}
 
-	cache.SetAggregateDiagnostics(detachedURI, ad)
+	newAggDiags[detachedURI] = ad
+
+	cache.SetAggregateDiagnosticsForFiles(newAggDiags)
+	cache.SetDiagnosticsForFiles(newFileDiags)
+
+	return &rpt, nil
+}
+
+// updateAggregateDiagnostics recomputes the aggregate diagnostics of the workspace from the aggregates previously
+// collected from each file, without linting any file again. Falls back to linting the whole workspace if aggregates
+// have not yet been collected from all files.
+func updateAggregateDiagnostics(
+	ctx context.Context,
+	cache *cache.Cache,
+	regalConfig *config.Config,
+	custom customRules,
+	detachedURI string,
+) error {
+	files := cache.GetAllFiles()
+	collected := cache.AggregateData.GetAll()
+
+	aggregates := make(map[string][]report.Aggregate)
+
+	for uri := range files {
+		fileAggregates, ok := collected[uri]
+		if !ok {
+			_, err := updateAllDiagnostics(ctx, cache, regalConfig, custom, detachedURI)
+
+			return err
+		}
+
+		for key, a := range fileAggregates {
+			aggregates[key] = append(aggregates[key], a...)
+		}
+	}
+
+	aggDiags := make(map[string][]types.Diagnostic)
+
+	// as when linting the workspace, aggregate rules only apply when there's more than one file
+	if len(files) > 1 {
+		regalInstance := linter.NewLinter().WithRootDir(detachedURI)
+
+		if regalConfig != nil {
+			regalInstance = regalInstance.WithUserConfig(*regalConfig)
+		}
+
+		if custom.dir != "" {
+			regalInstance = regalInstance.WithCustomRules([]string{custom.dir})
+		}
+
+		rpt, err := regalInstance.LintAggregates(ctx, aggregates)
+		if err != nil {
+			return fmt.Errorf("failed to lint aggregates: %w", err)
+		}
+
+		for _, item := range rpt.Violations {
+			file := item.Location.File
+			if file == "" {
+				file = detachedURI
+			}
+
+			aggDiags[file] = append(aggDiags[file], violationToDiagnostic(item))
+		}
+	}
+
+	newAggDiags := make(map[string][]types.Diagnostic, len(files)+1)
+
+	for uri := range files {
+		// parse errors continue to be shown until they're addressed, as in updateAllDiagnostics
+		if parseErrs, ok := cache.GetParseErrors(uri); ok && len(parseErrs) > 0 {
+			continue
+		}
+
+		diags, ok := aggDiags[uri]
+		if !ok {
+			diags = []types.Diagnostic{}
+		}
+
+		newAggDiags[uri] = diags
+	}
+
+	diags, ok := aggDiags[detachedURI]
+	if !ok {
+		diags = []types.Diagnostic{}
+	}
+
+	newAggDiags[detachedURI] = diags
+
+	cache.SetAggregateDiagnosticsForFiles(newAggDiags)
 
 	return nil
 }
 
+func violationToDiagnostic(item report.Violation) types.Diagnostic {
+	itemLen := 0
+	if item.Location.Text != nil {
+		itemLen = len(*item.Location.Text)
+	}
+
+	line := item.Location.Row - 1
+	if line < 0 {
+		line = 0
+	}
+
+	char := item.Location.Column - 1
+	if char < 0 {
+		char = 0
+	}
+
+	// here errors are presented as warnings, and warnings as info
+	// to differentiate from parse errors, while notices are hints
+	severity := uint(2)
+
+	switch item.Level {
+	case "warning":
+		severity = 3
+	case "notice":
+		severity = 4
+	}
+
+	return types.Diagnostic{
+		Severity: severity,
+		Range: types.Range{
+			Start: types.Position{
+				Line:      uint(line),
+				Character: uint(char),
+			},
+			End: types.Position{
+				Line:      uint(line),
+				Character: uint(char + itemLen + 1),
+			},
+		},
+		Message: item.Description,
+		Source:  "regal/" + item.Category,
+		Code:    item.Title,
+		CodeDescription: &types.CodeDescription{
+			Href: fmt.Sprintf(
+				"https://docs.styra.com/regal/rules/%s/%s",
+				item.Category,
+				item.Title,
+			),
+		},
+		Tags: violationTags(item),
+	}
+}
+
 // astError is copied from OPA but drop details as I (charlieegan3) had issues unmarsalling the field.
 type astError struct {
 	Code     string        `json:"code"`
@@ -0,0 +1,276 @@
+package lsp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/cache"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+func TestUpdateFileDiagnosticsTags(t *testing.T) {
+	t.Parallel()
+
+	uri := "file:///p.rego"
+	contents := `package p
+
+import rego.v1
+
+import data.foo as foo
+
+allow if any([input.a, input.b])
+`
+
+	c := cache.NewCache()
+	c.SetFileContents(uri, contents)
+
+	if _, err := updateParse(c, uri); err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+
+	if _, err := updateFileDiagnostics(context.Background(), c, nil, customRules{}, uri, ""); err != nil {
+		t.Fatalf("failed to update diagnostics: %s", err)
+	}
+
+	diags, ok := c.GetFileDiagnostics(uri)
+	if !ok {
+		t.Fatalf("expected diagnostics to be set")
+	}
+
+	expected := map[string]uint{
+		"redundant-alias":    diagnosticTagUnnecessary,
+		"deprecated-builtin": diagnosticTagDeprecated,
+		"strict-mode":        diagnosticTagUnnecessary,
+	}
+
+	for code, tag := range expected {
+		found := false
+
+		for _, diag := range diags {
+			if diag.Code != code {
+				continue
+			}
+
+			found = true
+
+			if !slices.Contains(diag.Tags, tag) {
+				t.Errorf("expected %s diagnostic to have tag %d, got %v", code, tag, diag.Tags)
+			}
+		}
+
+		if !found {
+			t.Errorf("expected diagnostic for %s", code)
+		}
+	}
+
+	for _, diag := range diags {
+		if _, ok := expected[diag.Code]; !ok && len(diag.Tags) > 0 {
+			t.Errorf("expected no tags for %s diagnostic, got %v", diag.Code, diag.Tags)
+		}
+	}
+}
+
+func TestAllModulesParsesEvictedModules(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewCache()
+	c.SetLimits(cache.Limits{MaxFiles: 1})
+
+	c.SetFileContents("file:///a.rego", "package a")
+	c.SetFileContents("file:///b.rego", "package b")
+	c.SetFileContents("file:///c.rego", "package")
+
+	for _, uri := range []string{"file:///a.rego", "file:///b.rego", "file:///c.rego"} {
+		if _, err := updateParse(c, uri); err != nil {
+			t.Fatalf("failed to parse %s: %s", uri, err)
+		}
+	}
+
+	if n := len(c.GetAllModules()); n != 1 {
+		t.Fatalf("expected 1 module to be cached, got %d", n)
+	}
+
+	modules := allModules(c)
+
+	if len(modules) != 2 {
+		t.Fatalf("expected modules for both parseable files, got %d", len(modules))
+	}
+
+	if modules["file:///a.rego"].Package.Path.String() != "data.a" {
+		t.Errorf("expected evicted module to be parsed again, got %v", modules["file:///a.rego"])
+	}
+
+	if n := len(c.GetAllModules()); n != 1 {
+		t.Errorf("expected modules parsed again not to be added to the cache, got %d", n)
+	}
+}
+
+func TestUpdateAggregateDiagnosticsIncrementally(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := cache.NewCache()
+
+	c.SetFileContents("file:///foo.rego", "package foo\n\nimport rego.v1\n\nimport data.bar\n\ndefault allow := false\n")
+	c.SetFileContents("file:///bar.rego", "package bar\n\nimport rego.v1\n\nimport data.foo.allow\n")
+
+	for _, uri := range []string{"file:///foo.rego", "file:///bar.rego"} {
+		if _, err := updateParse(c, uri); err != nil {
+			t.Fatalf("failed to parse %s: %s", uri, err)
+		}
+	}
+
+	if _, err := updateAllDiagnostics(ctx, c, nil, customRules{}, "file:///"); err != nil {
+		t.Fatalf("failed to update diagnostics: %s", err)
+	}
+
+	if diags, _ := c.GetAggregateDiagnostics("file:///bar.rego"); len(diags) != 1 {
+		t.Fatalf("expected prefer-package-imports diagnostic for bar.rego, got %v", diags)
+	}
+
+	if len(c.AggregateData.GetAll()) != 2 {
+		t.Fatalf("expected aggregates to be kept for both files")
+	}
+
+	// importing the package instead resolves the violation, which is found without linting foo.rego again
+	c.SetFileContents("file:///bar.rego", "package bar\n\nimport rego.v1\n\nimport data.foo\n")
+
+	if _, err := updateParse(c, "file:///bar.rego"); err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+
+	if _, err := updateFileDiagnostics(ctx, c, nil, customRules{}, "file:///bar.rego", "file:///"); err != nil {
+		t.Fatalf("failed to update file diagnostics: %s", err)
+	}
+
+	if err := updateAggregateDiagnostics(ctx, c, nil, customRules{}, "file:///"); err != nil {
+		t.Fatalf("failed to update aggregate diagnostics: %s", err)
+	}
+
+	if diags, _ := c.GetAggregateDiagnostics("file:///bar.rego"); len(diags) != 0 {
+		t.Errorf("expected no aggregate diagnostics for bar.rego, got %v", diags)
+	}
+}
+
+func TestUpdateFileDiagnosticsReusesLintResults(t *testing.T) {
+	t.Parallel()
+
+	uri := "file:///p.rego"
+	original := "package p\n\nimport data.foo as foo\n"
+	edited := "package p\n\nimport data.bar as bar\n"
+
+	c := cache.NewCache()
+
+	for i, contents := range []string{original, edited, original} {
+		c.SetFileContents(uri, contents)
+
+		if _, err := updateParse(c, uri); err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+
+		if _, err := updateFileDiagnostics(context.Background(), c, nil, customRules{}, uri, ""); err != nil {
+			t.Fatalf("failed to update diagnostics: %s", err)
+		}
+
+		diags, ok := c.GetFileDiagnostics(uri)
+		if !ok || len(diags) == 0 {
+			t.Fatalf("expected diagnostics to be set after update %d", i)
+		}
+	}
+
+	// undoing the edit reuses the result of linting the original contents
+	if hits, misses := c.LintResults.Stats().Hits(), c.LintResults.Stats().Misses(); hits != 1 || misses != 2 {
+		t.Errorf("expected 1 hit and 2 misses, got %d and %d", hits, misses)
+	}
+}
+
+func TestLintResultKeyIncludesDefaults(t *testing.T) {
+	t.Parallel()
+
+	keyForLevel := func(level string) cache.LintResultKey {
+		conf := &config.Config{Defaults: config.Defaults{
+			Global:     config.Default{Level: "error"},
+			Categories: map[string]config.Default{"style": {Level: level}},
+		}}
+
+		key, err := lintResultKey(conf, customRules{}, "", "file:///p.rego", "package p")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return key
+	}
+
+	if keyForLevel("error") == keyForLevel("ignore") {
+		t.Error("expected key to change with the default level of a category")
+	}
+
+	if keyForLevel("error") != keyForLevel("error") {
+		t.Error("expected key to be the same for the same config")
+	}
+}
+
+func TestViolationToDiagnosticSeverity(t *testing.T) {
+	t.Parallel()
+
+	for level, severity := range map[string]uint{"error": 2, "warning": 3, "notice": 4} {
+		diag := violationToDiagnostic(report.Violation{Title: "todo-comment", Level: level})
+		if diag.Severity != severity {
+			t.Errorf("expected severity %d for level %s, got %d", severity, level, diag.Severity)
+		}
+	}
+}
+
+func TestViolationToDiagnosticTags(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		violation report.Violation
+		expected  []uint
+	}{
+		"unused rule": {
+			violation: report.Violation{Title: "unused-rule", Description: "Rule never referenced"},
+			expected:  []uint{diagnosticTagUnnecessary},
+		},
+		"deprecated built-in": {
+			violation: report.Violation{Title: "deprecated-builtin", Description: "Avoid using deprecated built-in functions"},
+			expected:  []uint{diagnosticTagDeprecated},
+		},
+		"strict mode unused variable": {
+			violation: report.Violation{Title: "strict-mode", Description: "Strict mode check failed: assigned var x unused"},
+			expected:  []uint{diagnosticTagUnnecessary},
+		},
+		"strict mode unused import": {
+			violation: report.Violation{Title: "strict-mode", Description: "Strict mode check failed: import data.foo unused"},
+			expected:  []uint{diagnosticTagUnnecessary},
+		},
+		"strict mode unused argument": {
+			violation: report.Violation{
+				Title:       "strict-mode",
+				Description: "Strict mode check failed: unused argument y. (hint: use _ (wildcard variable) instead)",
+			},
+			expected: []uint{diagnosticTagUnnecessary},
+		},
+		"strict mode shadowing": {
+			violation: report.Violation{
+				Title:       "strict-mode",
+				Description: "Strict mode check failed: rules must not shadow input (use a different rule name)",
+			},
+		},
+		"untagged rule": {
+			violation: report.Violation{Title: "todo-comment", Description: "Avoid TODO comments"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tags := violationToDiagnostic(tc.violation).Tags; !slices.Equal(tags, tc.expected) {
+				t.Errorf("expected tags %v, got %v", tc.expected, tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+// ruleMissingMetadata returns the rule with a head starting on the provided line (0-indexed),
+// if there is one, and it isn't already preceded by a METADATA annotation block.
+func ruleMissingMetadata(module *ast.Module, line uint) *ast.Rule {
+	for _, rule := range module.Rules {
+		if rule.Location == nil || uint(rule.Location.Row-1) != line {
+			continue
+		}
+
+		if hasMetadata(module, rule) {
+			return nil
+		}
+
+		return rule
+	}
+
+	return nil
+}
+
+// hasMetadata checks whether the block of comments directly above the rule is a METADATA block.
+func hasMetadata(module *ast.Module, rule *ast.Rule) bool {
+	commentsByRow := make(map[int]*ast.Comment, len(module.Comments))
+	for _, comment := range module.Comments {
+		commentsByRow[comment.Location.Row] = comment
+	}
+
+	for row := rule.Location.Row - 1; row > 0; row-- {
+		comment, ok := commentsByRow[row]
+		if !ok {
+			return false
+		}
+
+		if strings.TrimSpace(string(comment.Text)) == "METADATA" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// metadataSkeleton returns a METADATA block to be inserted above the rule, indented to
+// match the rule. If the rule references input, a schemas attribute is included too.
+func metadataSkeleton(rule *ast.Rule, indent string) string {
+	sb := &strings.Builder{}
+
+	lines := []string{"METADATA", "description: TODO"}
+
+	if referencesInput(rule) {
+		lines = append(lines, "schemas:", "  - input: schema.input")
+	}
+
+	for _, line := range lines {
+		sb.WriteString(indent)
+		sb.WriteString("# ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func referencesInput(rule *ast.Rule) bool {
+	found := false
+
+	ast.WalkRefs(rule, func(ref ast.Ref) bool {
+		if ref.HasPrefix(ast.InputRootRef) {
+			found = true
+		}
+
+		return found
+	})
+
+	return found
+}
+
+// metadataEdit returns the edit inserting a METADATA block above the rule at row (1-indexed),
+// or nil if there is no rule lacking metadata at that row.
+func metadataEdit(module *ast.Module, contents string, row int) *types.TextEdit {
+	if row < 1 {
+		return nil
+	}
+
+	line := uint(row - 1)
+
+	rule := ruleMissingMetadata(module, line)
+	if rule == nil {
+		return nil
+	}
+
+	lines := strings.Split(contents, "\n")
+
+	indent := ""
+	if int(line) < len(lines) {
+		text := lines[line]
+		indent = text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+	}
+
+	return &types.TextEdit{
+		Range: types.Range{
+			Start: types.Position{Line: line, Character: 0},
+			End:   types.Position{Line: line, Character: 0},
+		},
+		NewText: metadataSkeleton(rule, indent),
+	}
+}
@@ -0,0 +1,99 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestMetadataEdit(t *testing.T) {
+	t.Parallel()
+
+	contents := `package p
+
+import rego.v1
+
+# METADATA
+# description: allow admins
+allow if input.user.admin
+
+deny := false
+
+# just a comment
+users := {"alice", "bob"}
+`
+
+	module := parse.MustParseModule(contents)
+
+	testCases := map[string]struct {
+		row      int
+		expected string
+	}{
+		"rule with metadata": {
+			row: 7,
+		},
+		"rule without metadata": {
+			row:      9,
+			expected: "# METADATA\n# description: TODO\n",
+		},
+		"rule with comment but no metadata": {
+			row:      12,
+			expected: "# METADATA\n# description: TODO\n",
+		},
+		"not a rule head": {
+			row: 3,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			edit := metadataEdit(module, contents, tc.row)
+
+			if tc.expected == "" {
+				if edit != nil {
+					t.Fatalf("expected no edit, got %v", edit)
+				}
+
+				return
+			}
+
+			if edit == nil {
+				t.Fatalf("expected edit, got nil")
+			}
+
+			if edit.NewText != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, edit.NewText)
+			}
+
+			if edit.Range.Start.Line != uint(tc.row-1) || edit.Range.Start.Character != 0 {
+				t.Errorf("expected edit at start of line %d, got %v", tc.row-1, edit.Range.Start)
+			}
+		})
+	}
+}
+
+func TestMetadataEditSchemasFromInput(t *testing.T) {
+	t.Parallel()
+
+	contents := `package p
+
+import rego.v1
+
+allow if {
+	input.user.admin
+}
+`
+
+	edit := metadataEdit(parse.MustParseModule(contents), contents, 5)
+	if edit == nil {
+		t.Fatalf("expected edit, got nil")
+	}
+
+	expected := "# METADATA\n# description: TODO\n# schemas:\n#   - input: schema.input\n"
+
+	if edit.NewText != expected {
+		t.Errorf("expected %q, got %q", expected, edit.NewText)
+	}
+}
@@ -0,0 +1,102 @@
+package lsp
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/util"
+)
+
+// moduleGraph returns the graph of packages found in the provided modules, with an edge
+// for each import of one package from another. Imports are resolved to the package with
+// the longest matching path, so that e.g. `import data.users.admins` points to a package
+// `data.users` if there is no package `data.users.admins`. Imports not matching any known
+// package are added as external nodes.
+func moduleGraph(modules map[string]*ast.Module) types.ModuleGraph {
+	nodes := make(map[string]*types.ModuleGraphNode)
+
+	for uri, module := range modules {
+		if module == nil || module.Package == nil {
+			continue
+		}
+
+		pkg := module.Package.Path.String()
+		if _, ok := nodes[pkg]; !ok {
+			nodes[pkg] = &types.ModuleGraphNode{ID: pkg, Files: make([]string, 0)}
+		}
+
+		nodes[pkg].Files = append(nodes[pkg].Files, uri)
+	}
+
+	packages := util.Keys(nodes)
+
+	edges := make([]types.ModuleGraphEdge, 0)
+
+	for uri, module := range modules {
+		if module == nil || module.Package == nil {
+			continue
+		}
+
+		for _, imp := range module.Imports {
+			path, ok := imp.Path.Value.(ast.Ref)
+			if !ok || !path.HasPrefix(ast.DefaultRootRef) {
+				continue
+			}
+
+			target := resolveImport(path, packages)
+			if _, ok := nodes[target]; !ok {
+				nodes[target] = &types.ModuleGraphNode{ID: target, Files: make([]string, 0), External: true}
+			}
+
+			edges = append(edges, types.ModuleGraphEdge{
+				From:   module.Package.Path.String(),
+				To:     target,
+				Import: path.String(),
+				URI:    uri,
+				Range:  locationToRange(imp.Location),
+			})
+		}
+	}
+
+	graph := types.ModuleGraph{
+		Nodes: make([]types.ModuleGraphNode, 0, len(nodes)),
+		Edges: edges,
+	}
+
+	for _, node := range nodes {
+		slices.Sort(node.Files)
+
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+
+	slices.SortFunc(graph.Nodes, func(a, b types.ModuleGraphNode) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	slices.SortFunc(graph.Edges, func(a, b types.ModuleGraphEdge) int {
+		if c := strings.Compare(a.URI, b.URI); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Range.Start.Line, b.Range.Start.Line)
+	})
+
+	return graph
+}
+
+// resolveImport returns the longest package path that the import path starts with,
+// or the import path itself if it doesn't match any package.
+func resolveImport(path ast.Ref, packages []string) string {
+	for i := len(path); i > 1; i-- {
+		prefix := path[:i].String()
+		if slices.Contains(packages, prefix) {
+			return prefix
+		}
+	}
+
+	return path.String()
+}
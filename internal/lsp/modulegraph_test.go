@@ -0,0 +1,85 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestModuleGraph(t *testing.T) {
+	t.Parallel()
+
+	modules := map[string]*ast.Module{
+		"file:///authz.rego": parse.MustParseModule(`package authz
+
+import rego.v1
+
+import data.users.admins
+import data.roles
+import data.external.thing
+`),
+		"file:///users.rego": parse.MustParseModule(`package users
+
+admins := {"alice"}
+`),
+		"file:///roles/roles.rego": parse.MustParseModule(`package roles
+
+import data.users
+`),
+		"file:///roles/more.rego": parse.MustParseModule(`package roles
+`),
+	}
+
+	graph := moduleGraph(modules)
+
+	expectedNodes := []struct {
+		id       string
+		files    int
+		external bool
+	}{
+		{id: "data.authz", files: 1},
+		{id: "data.external.thing", external: true},
+		{id: "data.roles", files: 2},
+		{id: "data.users", files: 1},
+	}
+
+	if len(graph.Nodes) != len(expectedNodes) {
+		t.Fatalf("expected %d nodes, got %d: %v", len(expectedNodes), len(graph.Nodes), graph.Nodes)
+	}
+
+	for i, expected := range expectedNodes {
+		node := graph.Nodes[i]
+
+		if node.ID != expected.id || len(node.Files) != expected.files || node.External != expected.external {
+			t.Errorf("expected node %v, got %v", expected, node)
+		}
+	}
+
+	expectedEdges := []struct {
+		from string
+		to   string
+	}{
+		{from: "data.authz", to: "data.users"},
+		{from: "data.authz", to: "data.roles"},
+		{from: "data.authz", to: "data.external.thing"},
+		{from: "data.roles", to: "data.users"},
+	}
+
+	if len(graph.Edges) != len(expectedEdges) {
+		t.Fatalf("expected %d edges, got %d: %v", len(expectedEdges), len(graph.Edges), graph.Edges)
+	}
+
+	for i, expected := range expectedEdges {
+		edge := graph.Edges[i]
+
+		if edge.From != expected.from || edge.To != expected.to {
+			t.Errorf("expected edge %s -> %s, got %s -> %s", expected.from, expected.to, edge.From, edge.To)
+		}
+	}
+
+	if graph.Edges[0].Import != "data.users.admins" {
+		t.Errorf("expected import data.users.admins, got %s", graph.Edges[0].Import)
+	}
+}
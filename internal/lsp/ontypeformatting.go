@@ -0,0 +1,93 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/styrainc/regal/internal/lsp/opa/scanner"
+	"github.com/styrainc/regal/internal/lsp/opa/tokens"
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+// onTypeFormattingEdits returns the edits needed to fix the indentation of the line at position,
+// after ch was typed. Indentation follows that of opa fmt, i.e. one tab per level of nesting. When
+// triggered by a closing brace, the line is only re-indented if it starts with that brace.
+func onTypeFormattingEdits(text string, position types.Position, ch string) []types.TextEdit {
+	edits := make([]types.TextEdit, 0)
+
+	lines := strings.Split(text, "\n")
+	if int(position.Line) >= len(lines) {
+		return edits
+	}
+
+	line := lines[position.Line]
+	trimmed := strings.TrimLeft(line, " \t")
+
+	if ch == "}" && !strings.HasPrefix(trimmed, "}") {
+		return edits
+	}
+
+	preceding := strings.Join(lines[:position.Line], "\n")
+	indent := strings.Repeat("\t", indentLevel(preceding, trimmed))
+	current := line[:len(line)-len(trimmed)]
+
+	if current == indent {
+		return edits
+	}
+
+	return append(edits, types.TextEdit{
+		Range: types.Range{
+			Start: types.Position{Line: position.Line, Character: 0},
+			End:   types.Position{Line: position.Line, Character: uint(len(current))},
+		},
+		NewText: indent,
+	})
+}
+
+// indentLevel returns the level of indentation for a line, given the text preceding it. Strings and
+// comments are handled by the scanner, so brackets inside of those are not counted.
+func indentLevel(preceding string, line string) int {
+	scn, err := scanner.New(strings.NewReader(preceding))
+	if err != nil {
+		return 0
+	}
+
+	open := stack{}
+
+	for {
+		token, position, _, errors := scn.Scan()
+
+		if token == tokens.EOF || len(errors) > 0 {
+			break
+		}
+
+		switch {
+		case token == tokens.LBrace || token == tokens.LBrack || token == tokens.LParen:
+			open = open.Push(position)
+		case (token == tokens.RBrace || token == tokens.RBrack || token == tokens.RParen) && len(open) > 0:
+			open, _ = open.Pop()
+		}
+	}
+
+	// closing brackets at the start of the line are indented to the level of
+	// the line where they were opened
+	for _, c := range line {
+		if len(open) == 0 || !strings.ContainsRune("}])", c) {
+			break
+		}
+
+		open, _ = open.Pop()
+	}
+
+	// several brackets opened on the same line, like `[{`, only add one level
+	level := 0
+	lastRow := 0
+
+	for _, position := range open {
+		if position.Row != lastRow {
+			level++
+			lastRow = position.Row
+		}
+	}
+
+	return level
+}
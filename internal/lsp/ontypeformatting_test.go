@@ -0,0 +1,135 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestOnTypeFormattingEdits(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text     string
+		position types.Position
+		ch       string
+		expected string
+		noEdit   bool
+	}{
+		"newline in rule body": {
+			text:     "package p\n\nallow if {\n\n}\n",
+			position: types.Position{Line: 3, Character: 0},
+			ch:       "\n",
+			expected: "\t",
+		},
+		"newline in nested comprehension": {
+			text:     "package p\n\nallow if {\n\tx := [y |\n    \n",
+			position: types.Position{Line: 4, Character: 4},
+			ch:       "\n",
+			expected: "\t\t",
+		},
+		"newline after brackets opened on the same line": {
+			text:     "package p\n\nx := [{\n\n",
+			position: types.Position{Line: 3, Character: 0},
+			ch:       "\n",
+			expected: "\t",
+		},
+		"newline ignores braces in strings and comments": {
+			text:     "package p\n\nallow if {\n\tx := \"{\" # {\n\n",
+			position: types.Position{Line: 4, Character: 0},
+			ch:       "\n",
+			expected: "\t",
+		},
+		"newline already correctly indented": {
+			text:     "package p\n\nallow if {\n\t\n",
+			position: types.Position{Line: 3, Character: 1},
+			ch:       "\n",
+			noEdit:   true,
+		},
+		"closing brace dedented": {
+			text:     "package p\n\nallow if {\n\tinput.x\n\t}\n",
+			position: types.Position{Line: 4, Character: 2},
+			ch:       "}",
+			expected: "",
+		},
+		"closing brace of nested object": {
+			text:     "package p\n\nallow if {\n\tx := {\n\t\t\"a\": 1,\n}\n",
+			position: types.Position{Line: 5, Character: 1},
+			ch:       "}",
+			expected: "\t",
+		},
+		"closing brace not at start of line": {
+			text:     "package p\n\nallow if {\n\tx := {}\n",
+			position: types.Position{Line: 3, Character: 8},
+			ch:       "}",
+			noEdit:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			edits := onTypeFormattingEdits(tc.text, tc.position, tc.ch)
+
+			if tc.noEdit {
+				if len(edits) != 0 {
+					t.Fatalf("expected no edits, got %v", edits)
+				}
+
+				return
+			}
+
+			if len(edits) != 1 {
+				t.Fatalf("expected 1 edit, got %d", len(edits))
+			}
+
+			if edits[0].NewText != tc.expected {
+				t.Errorf("expected indentation %q, got %q", tc.expected, edits[0].NewText)
+			}
+
+			if edits[0].Range.Start.Line != tc.position.Line || edits[0].Range.Start.Character != 0 {
+				t.Errorf("expected edit at start of line %d, got %v", tc.position.Line, edits[0].Range.Start)
+			}
+		})
+	}
+}
+
+func TestOnTypeFormattingRightAfterChange(t *testing.T) {
+	t.Parallel()
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr})
+	fileURI := "file:///p.rego"
+
+	ls.cache.SetFileContentsVersion(fileURI, "package p\n\nallow if {\n}\n", 1)
+
+	handle := func(method, params string) any {
+		t.Helper()
+
+		raw := json.RawMessage(params)
+
+		result, err := ls.Handle(context.Background(), nil, &jsonrpc2.Request{Method: method, Params: &raw})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return result
+	}
+
+	// no worker processes the change, so the edits are computed from the contents set by the handler
+	handle("textDocument/didChange", `{"textDocument": {"uri": "`+fileURI+`", "version": 2},
+		"contentChanges": [{"text": "package p\n\nallow if {\n\n}\n"}]}`)
+
+	result := handle("textDocument/onTypeFormatting", `{"textDocument": {"uri": "`+fileURI+`"},
+		"position": {"line": 3, "character": 0}, "ch": "\n", "options": {"tabSize": 4, "insertSpaces": false}}`)
+
+	edits, ok := result.([]types.TextEdit)
+	if !ok || len(edits) != 1 || edits[0].NewText != "\t" {
+		t.Errorf("expected edit indenting the new line, got %v", result)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/open-policy-agent/opa/ast"
 
 	"github.com/styrainc/regal/internal/compile"
+	rparse "github.com/styrainc/regal/internal/parse"
 )
 
 // Error defines the structure of errors returned by the oracle.
@@ -157,7 +158,7 @@ func compileUpto(stage string, modules map[string]*ast.Module, bs []byte, filena
 
 	if len(bs) > 0 {
 		var err error
-		module, err = ast.ParseModule(filename, string(bs))
+		module, err = rparse.Module(filename, string(bs))
 		if err != nil {
 			return nil, nil, err
 		}
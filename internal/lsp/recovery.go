@@ -0,0 +1,124 @@
+package lsp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/pkg/version"
+)
+
+// recoverRequest recovers from a panic while handling a request, reporting it, and responding to the request with an
+// internal error, for one bad file or request not to take down the server, and with it all diagnostics.
+func (l *LanguageServer) recoverRequest(req *jsonrpc2.Request, result *any, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	l.reportPanic(req.Method, paramsURI(req.Params), r)
+
+	*result = nil
+	*err = &jsonrpc2.Error{
+		Code:    jsonrpc2.CodeInternalError,
+		Message: fmt.Sprintf("internal error handling %s: %v", req.Method, r),
+	}
+}
+
+// runWorker runs a worker of the server until it returns, restarting it whenever it panics, like when failing on an
+// update to a single file, for the updates to all other files to still be handled.
+func (l *LanguageServer) runWorker(ctx context.Context, name string, worker func(context.Context)) {
+	for {
+		panicked := func() (panicked bool) {
+			defer func() {
+				if r := recover(); r != nil {
+					l.reportPanic(name, "", r)
+
+					panicked = true
+				}
+			}()
+
+			worker(ctx)
+
+			return false
+		}()
+
+		if !panicked || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// paramsURI returns the URI of the text document of the params of a request, if any.
+func paramsURI(params *json.RawMessage) string {
+	if params == nil {
+		return ""
+	}
+
+	var document struct {
+		TextDocument types.TextDocumentIdentifier `json:"textDocument"`
+	}
+
+	if err := json.Unmarshal(*params, &document); err != nil {
+		return ""
+	}
+
+	return document.TextDocument.URI
+}
+
+// reportPanic logs a report of the panic to the error log, and keeps it as the last panic of the server status.
+func (l *LanguageServer) reportPanic(source, fileURI string, r any) {
+	files := l.cache.GetAllFiles()
+
+	report := types.PanicReport{
+		Time:   time.Now(),
+		Source: source,
+		Panic:  fmt.Sprint(r),
+		URI:    fileURI,
+		Files:  make(map[string]string, len(files)),
+		Stack:  string(debug.Stack()),
+	}
+
+	for fileURI, contents := range files {
+		sum := sha256.Sum256([]byte(contents))
+		report.Files[fileURI] = hex.EncodeToString(sum[:])
+	}
+
+	l.statusLock.Lock()
+	l.panics++
+	l.lastPanic = &report
+	l.statusLock.Unlock()
+
+	bs, err := json.Marshal(report)
+	if err != nil {
+		l.logError(fmt.Errorf("recovered from panic in %s: %v", source, r))
+
+		return
+	}
+
+	l.logError(fmt.Errorf("recovered from panic in %s: %s", source, bs))
+}
+
+func (l *LanguageServer) handleRegalServerStatus(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	_ *jsonrpc2.Request,
+) (result any, err error) {
+	l.statusLock.Lock()
+	defer l.statusLock.Unlock()
+
+	return types.ServerStatus{
+		Version:   version.New().Version,
+		Uptime:    int64(time.Since(l.startTime)),
+		Files:     len(l.cache.GetAllFiles()),
+		Panics:    l.panics,
+		LastPanic: l.lastPanic,
+	}, nil
+}
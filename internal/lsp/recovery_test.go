@@ -0,0 +1,110 @@
+package lsp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestRecoverRequest(t *testing.T) {
+	t.Parallel()
+
+	var errorLog bytes.Buffer
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: &errorLog})
+	ls.cache.SetFileContents("file:///p.rego", "package p")
+
+	req := &jsonrpc2.Request{Method: "textDocument/hover"}
+
+	_, err := func() (result any, err error) {
+		defer ls.recoverRequest(req, &result, &err)
+
+		panic("boom")
+	}()
+
+	var rpcErr *jsonrpc2.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc2.CodeInternalError {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+
+	if !strings.Contains(errorLog.String(), "recovered from panic in textDocument/hover") {
+		t.Errorf("expected panic to be logged, got %s", errorLog.String())
+	}
+
+	result, err := ls.handleRegalServerStatus(context.Background(), nil, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, ok := result.(types.ServerStatus)
+	if !ok || status.Panics != 1 || status.Files != 1 || status.LastPanic == nil {
+		t.Fatalf("expected status with 1 file and 1 panic, got %+v", result)
+	}
+
+	if status.LastPanic.Source != req.Method || status.LastPanic.Panic != "boom" {
+		t.Errorf("expected panic boom in %s, got %s in %s", req.Method, status.LastPanic.Panic, status.LastPanic.Source)
+	}
+
+	sum := sha256.Sum256([]byte("package p"))
+	if digest := status.LastPanic.Files["file:///p.rego"]; digest != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected digest of file, got %q", digest)
+	}
+}
+
+func TestRunWorkerRestartsOnPanic(t *testing.T) {
+	t.Parallel()
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: &bytes.Buffer{}})
+
+	runs := 0
+
+	ls.runWorker(context.Background(), "test worker", func(context.Context) {
+		runs++
+
+		if runs == 1 {
+			panic("boom")
+		}
+	})
+
+	if runs != 2 {
+		t.Errorf("expected worker to be restarted once, got %d runs", runs)
+	}
+
+	if ls.panics != 1 {
+		t.Errorf("expected 1 panic, got %d", ls.panics)
+	}
+}
+
+func TestRecoverRequestLeavesOutContents(t *testing.T) {
+	t.Parallel()
+
+	var errorLog bytes.Buffer
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: &errorLog})
+
+	params := json.RawMessage(`{"textDocument":{"uri":"file:///p.rego","text":"package p\n\nsecret := \"abc\"\n"}}`)
+	req := &jsonrpc2.Request{Method: "textDocument/didOpen", Params: &params}
+
+	_, _ = func() (result any, err error) {
+		defer ls.recoverRequest(req, &result, &err)
+
+		panic("boom")
+	}()
+
+	if strings.Contains(errorLog.String(), "secret") {
+		t.Errorf("expected contents of file to be left out of log, got %s", errorLog.String())
+	}
+
+	if ls.lastPanic == nil || ls.lastPanic.URI != "file:///p.rego" {
+		t.Errorf("expected URI of document in panic report, got %+v", ls.lastPanic)
+	}
+}
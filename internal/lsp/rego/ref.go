@@ -0,0 +1,35 @@
+package rego
+
+import "strings"
+
+// RefAt returns the reference on the line, like input.user.name, at the character, up to the end of the part of
+// the reference the character is in. The columns of the start and end of the reference are returned along with it.
+// Parts in brackets, like in input.users[_].name, are returned as parts following a dot, like input.users._.name.
+func RefAt(line string, character uint) (string, uint, uint, bool) {
+	if character > uint(len(line)) {
+		return "", 0, 0, false
+	}
+
+	start := character
+	for start > 0 && isRefChar(line[start-1]) {
+		start--
+	}
+
+	end := character
+	for end < uint(len(line)) && isRefChar(line[end]) && line[end] != '.' && line[end] != '[' {
+		end++
+	}
+
+	if start == end {
+		return "", 0, 0, false
+	}
+
+	ref := strings.NewReplacer("[", ".", "]", "").Replace(line[start:end])
+
+	return ref, start, end, true
+}
+
+func isRefChar(c byte) bool {
+	return c == '.' || c == '_' || c == '[' || c == ']' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
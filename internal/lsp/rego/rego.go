@@ -3,11 +3,12 @@ package rego
 import (
 	"github.com/open-policy-agent/opa/ast"
 
+	"github.com/styrainc/regal/internal/builtindb"
 	"github.com/styrainc/regal/internal/lsp/types"
 )
 
 type BuiltInCall struct {
-	Builtin  *ast.Builtin
+	Builtin  *builtindb.Builtin
 	Location *ast.Location
 	Args     []*ast.Term
 }
@@ -42,7 +43,7 @@ func AllBuiltinCalls(module *ast.Module) []BuiltInCall {
 			return false
 		}
 
-		if b, ok := BuiltIns[terms[0].Value.String()]; ok {
+		if b, ok := builtindb.Default().Get(terms[0].Value.String()); ok {
 			// Exclude operators and similar builtins
 			if b.Infix != "" {
 				return false
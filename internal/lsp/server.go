@@ -2,16 +2,23 @@
 package lsp
 
 import (
+	"cmp"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sourcegraph/jsonrpc2"
 	"gopkg.in/yaml.v3"
@@ -19,6 +26,7 @@ import (
 	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/format"
 
+	"github.com/styrainc/regal/internal/debugbundle"
 	"github.com/styrainc/regal/internal/lsp/cache"
 	"github.com/styrainc/regal/internal/lsp/clients"
 	"github.com/styrainc/regal/internal/lsp/commands"
@@ -27,25 +35,40 @@ import (
 	lsconfig "github.com/styrainc/regal/internal/lsp/config"
 	"github.com/styrainc/regal/internal/lsp/hover"
 	"github.com/styrainc/regal/internal/lsp/opa/oracle"
+	"github.com/styrainc/regal/internal/lsp/rego"
 	"github.com/styrainc/regal/internal/lsp/types"
 	"github.com/styrainc/regal/internal/lsp/uri"
+	"github.com/styrainc/regal/internal/metrics"
 	rparse "github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/internal/schemas"
 	"github.com/styrainc/regal/internal/util"
 	"github.com/styrainc/regal/pkg/config"
 	"github.com/styrainc/regal/pkg/fixer/fixes"
 	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/rules"
+	"github.com/styrainc/regal/pkg/version"
 )
 
 const (
 	methodTextDocumentPublishDiagnostics = "textDocument/publishDiagnostics"
 	methodWorkspaceApplyEdit             = "workspace/applyEdit"
 
-	ruleNameOPAFmt    = "opa-fmt"
-	ruleNameUseRegoV1 = "use-rego-v1"
+	ruleNameOPAFmt                 = "opa-fmt"
+	ruleNameUseRegoV1              = "use-rego-v1"
+	ruleNameImplicitFutureKeywords = "implicit-future-keywords"
+	ruleNameDeprecatedBuiltin      = "deprecated-builtin"
+	ruleNameMissingMetadata        = "missing-metadata"
 )
 
 type LanguageServerOptions struct {
 	ErrorLog io.Writer
+	// IndexDir is the directory where an index of the diagnostics of each workspace is saved between sessions.
+	// When empty, no index is saved.
+	IndexDir string
+	// Metrics, when provided, collects metrics of the requests handled, and the time spent linting.
+	Metrics *metrics.Prometheus
+	// DebugBundle, when provided, records the timings of requests, and errors, for a debug bundle written on exit.
+	DebugBundle *debugbundle.Recorder
 }
 
 func NewLanguageServer(opts *LanguageServerOptions) *LanguageServer {
@@ -53,14 +76,22 @@ func NewLanguageServer(opts *LanguageServerOptions) *LanguageServer {
 	ls := &LanguageServer{
 		cache:                      c,
 		errorLog:                   opts.ErrorLog,
+		indexDir:                   opts.IndexDir,
 		diagnosticRequestFile:      make(chan fileUpdateEvent, 10),
 		diagnosticRequestWorkspace: make(chan string, 10),
 		builtinsPositionFile:       make(chan fileUpdateEvent, 10),
 		commandRequest:             make(chan types.ExecuteCommandParams, 10),
 		configWatcher:              lsconfig.NewWatcher(&lsconfig.WatcherOpts{ErrorWriter: opts.ErrorLog}),
 		completionsManager:         completions.NewDefaultManager(c),
+		metrics:                    opts.Metrics,
+		debugBundle:                opts.DebugBundle,
+		startTime:                  time.Now(),
 	}
 
+	ls.metrics.RegisterCache("prepared_inputs", c.PreparedInputs.Stats())
+	ls.metrics.RegisterCache("lint_results", c.LintResults.Stats())
+	ls.metrics.RegisterCache("compiled_rules", linter.CompiledRulesCacheStats())
+
 	return ls
 }
 
@@ -75,6 +106,15 @@ type LanguageServer struct {
 	loadedConfig     *config.Config
 	loadedConfigLock sync.Mutex
 
+	// customRules are those of the .regal/rules directory of the workspace, linted with along with the embedded rules
+	customRules     customRules
+	customRulesLock sync.Mutex
+
+	// activeInputDocument is the path of the input document set by the regal.input.set command, used for evaluating
+	// all files in place of the input documents found for each
+	activeInputDocument string
+	inputDocumentLock   sync.Mutex
+
 	diagnosticRequestFile      chan fileUpdateEvent
 	diagnosticRequestWorkspace chan string
 
@@ -89,7 +129,29 @@ type LanguageServer struct {
 	// a clientRootURI.
 	workspaceMode bool
 
+	// testDiscovery is set to true when the client has announced support for
+	// regal/testsDiscovered notifications.
+	testDiscovery bool
+
+	// initializationOptions holds the feature toggles provided by the client on initialization.
+	initializationOptions types.InitializationOptions
+
+	// indexDir is where the workspace index is persisted, and indexRestored is set when
+	// diagnostics were restored from a previously saved index on initialization.
+	indexDir      string
+	indexRestored bool
+
 	completionsManager *completions.Manager
+
+	metrics *metrics.Prometheus
+
+	debugBundle *debugbundle.Recorder
+
+	// startTime, along with the number of panics recovered from, and the last of them, make up the server status
+	startTime  time.Time
+	statusLock sync.Mutex
+	panics     int
+	lastPanic  *types.PanicReport
 }
 
 // fileUpdateEvent is sent to a channel when an update is required for a file.
@@ -98,6 +160,8 @@ type fileUpdateEvent struct {
 	URI     string
 	OldURI  string
 	Content string
+	// Version is the document version of Content, when sent by the client
+	Version *uint
 }
 
 func (l *LanguageServer) Handle(
@@ -105,8 +169,34 @@ func (l *LanguageServer) Handle(
 	conn *jsonrpc2.Conn,
 	req *jsonrpc2.Request,
 ) (result any, err error) {
+	if l.metrics != nil {
+		defer func() {
+			code := "ok"
+			if err != nil {
+				code = "error"
+			}
+
+			l.metrics.ObserveRequest("lsp", req.Method, code)
+		}()
+	}
+
+	if l.debugBundle != nil {
+		start := time.Now()
+
+		defer func() {
+			l.debugBundle.AddTiming("lsp "+req.Method, time.Since(start))
+
+			if err != nil {
+				l.debugBundle.AddError(fmt.Errorf("%s: %w", req.Method, err))
+			}
+		}()
+	}
+
+	defer l.recoverRequest(req, &result, &err)
+
 	// null params are allowed, but only for certain methods
-	if req.Params == nil && req.Method != "shutdown" && req.Method != "exit" {
+	if req.Params == nil && req.Method != "shutdown" && req.Method != "exit" && req.Method != "regal/serverStatus" &&
+		req.Method != "regal/workspaceDiagnosticsSummary" {
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
 	}
 
@@ -119,12 +209,14 @@ func (l *LanguageServer) Handle(
 		return l.handleTextDocumentCodeAction(ctx, conn, req)
 	case "textDocument/definition":
 		return l.handleTextDocumentDefinition(ctx, conn, req)
+	case "textDocument/references":
+		return l.handleTextDocumentReferences(ctx, conn, req)
 	case "textDocument/diagnostic":
 		return l.handleTextDocumentDiagnostic(ctx, conn, req)
 	case "textDocument/didOpen":
 		return l.handleTextDocumentDidOpen(ctx, conn, req)
 	case "textDocument/didClose":
-		return struct{}{}, nil
+		return l.handleTextDocumentDidClose(ctx, conn, req)
 	case "textDocument/didSave":
 		return l.handleTextDocumentDidSave(ctx, conn, req)
 	case "textDocument/documentSymbol":
@@ -137,10 +229,16 @@ func (l *LanguageServer) Handle(
 		return l.handleTextDocumentFormatting(ctx, conn, req)
 	case "textDocument/hover":
 		return l.handleTextDocumentHover(ctx, conn, req)
+	case "textDocument/onTypeFormatting":
+		return l.handleTextDocumentOnTypeFormatting(ctx, conn, req)
 	case "textDocument/inlayHint":
 		return l.handleTextDocumentInlayHint(ctx, conn, req)
+	case "textDocument/inlineValue":
+		return l.handleTextDocumentInlineValue(ctx, conn, req)
 	case "textDocument/completion":
 		return l.handleTextDocumentCompletion(ctx, conn, req)
+	case "textDocument/signatureHelp":
+		return l.handleTextDocumentSignatureHelp(ctx, conn, req)
 	case "workspace/didChangeWatchedFiles":
 		return l.handleWorkspaceDidChangeWatchedFiles(ctx, conn, req)
 	case "workspace/diagnostic":
@@ -155,6 +253,18 @@ func (l *LanguageServer) Handle(
 		return l.handleWorkspaceExecuteCommand(ctx, conn, req)
 	case "workspace/symbol":
 		return l.handleWorkspaceSymbol(ctx, conn, req)
+	case "regal/tests":
+		return l.handleRegalTests(ctx, conn, req)
+	case "regal/runTests":
+		return l.handleRegalRunTests(ctx, conn, req)
+	case "regal/moduleGraph":
+		return l.handleRegalModuleGraph(ctx, conn, req)
+	case "regal/serverStatus":
+		return l.handleRegalServerStatus(ctx, conn, req)
+	case "regal/inputDocument":
+		return l.handleRegalInputDocument(ctx, conn, req)
+	case "regal/workspaceDiagnosticsSummary":
+		return l.handleRegalWorkspaceDiagnosticsSummary(ctx, conn, req)
 	case "shutdown":
 		// no-op as we wait for the exit signal before closing channel
 		return struct{}{}, nil
@@ -184,12 +294,40 @@ func (l *LanguageServer) SetConn(conn *jsonrpc2.Conn) {
 	l.conn = conn
 }
 
+// updateAggregateDiagnostics recomputes the aggregate diagnostics of the workspace, and sends the diagnostics
+// of all files, as those of any file may have changed.
+func (l *LanguageServer) updateAggregateDiagnostics(ctx context.Context) {
+	err := updateAggregateDiagnostics(ctx, l.cache, l.loadedConfig, l.getCustomRules(), l.clientRootURI)
+	if err != nil {
+		l.logError(fmt.Errorf("failed to update aggregate diagnostics: %w", err))
+
+		return
+	}
+
+	for fileURI := range l.cache.GetAllFiles() {
+		if err := l.sendFileDiagnostics(ctx, fileURI); err != nil {
+			l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
+		}
+	}
+}
+
 func (l *LanguageServer) StartDiagnosticsWorker(ctx context.Context) {
+	l.runWorker(ctx, "diagnostics worker", l.diagnosticsWorker)
+}
+
+func (l *LanguageServer) diagnosticsWorker(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case evt := <-l.diagnosticRequestFile:
+			// data files are validated rather than linted
+			if config.IsDataFile(evt.URI) {
+				l.processDataFileUpdate(ctx, evt)
+
+				continue
+			}
+
 			// if file has been deleted, clear diagnostics in the client
 			if evt.Reason == "textDocument/didDelete" {
 				err := l.sendFileDiagnostics(ctx, evt.URI)
@@ -197,6 +335,14 @@ func (l *LanguageServer) StartDiagnosticsWorker(ctx context.Context) {
 					l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
 				}
 
+				err = l.sendTestsDiscovered(ctx, evt.URI, []types.TestItem{})
+				if err != nil {
+					l.logError(fmt.Errorf("failed to send tests: %w", err))
+				}
+
+				// aggregates collected from the deleted file no longer apply to the workspace
+				l.updateAggregateDiagnostics(ctx)
+
 				continue
 			}
 
@@ -206,11 +352,29 @@ func (l *LanguageServer) StartDiagnosticsWorker(ctx context.Context) {
 					l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
 				}
 
-				continue
+				err = l.sendTestsDiscovered(ctx, evt.OldURI, []types.TestItem{})
+				if err != nil {
+					l.logError(fmt.Errorf("failed to send tests: %w", err))
+				}
+			}
+
+			var success bool
+
+			var err error
+
+			switch evt.Reason {
+			case "textDocument/didRename":
+				// the contents have already been moved to the new URI in the cache, but must be
+				// parsed again for locations in the module to reference the new URI
+				success, err = l.processParse(ctx, evt.URI)
+			case "textDocument/didChange":
+				// the contents have already been set when the change was received
+				success, err = l.processParse(ctx, evt.URI)
+			default:
+				// if there is new content, we need to update the parse errors or module first
+				success, err = l.processTextContentUpdate(ctx, evt.URI, evt.Content, evt.Version)
 			}
 
-			// if there is new content, we need to update the parse errors or module first
-			success, err := l.processTextContentUpdate(ctx, evt.URI, evt.Content)
 			if err != nil {
 				l.logError(fmt.Errorf("failed to process text content update: %w", err))
 
@@ -221,28 +385,46 @@ func (l *LanguageServer) StartDiagnosticsWorker(ctx context.Context) {
 				continue
 			}
 
+			oldAggregates, _ := l.cache.AggregateData.Get(evt.URI)
+
 			// otherwise, lint the file and send the diagnostics
-			err = updateFileDiagnostics(ctx, l.cache, l.loadedConfig, evt.URI, l.clientRootURI)
+			start := time.Now()
+
+			rpt, err := updateFileDiagnostics(
+				ctx, l.cache, l.loadedConfig, l.getCustomRules(), evt.URI, l.clientRootURI,
+			)
 			if err != nil {
 				l.logError(fmt.Errorf("failed to update file diagnostics: %w", err))
 			}
 
+			l.metrics.ObserveLint(time.Since(start), rpt)
+
 			err = l.sendFileDiagnostics(ctx, evt.URI)
 			if err != nil {
 				l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
 			}
 
-			// if the file has agg diagnostics, we trigger a run for the workspace as by changing this file,
-			// these may now be out of date
-			aggDiags, ok := l.cache.GetAggregateDiagnostics(evt.URI)
-			if ok && len(aggDiags) > 0 {
-				l.diagnosticRequestWorkspace <- fmt.Sprintf("file %q with aggregate violation changed", evt.URI)
+			// if the aggregates collected from the file changed, or the file has aggregate diagnostics, the
+			// aggregate diagnostics of the workspace may now be out of date. these are recomputed using the
+			// aggregates previously collected from all other files, so no other file needs to be linted again
+			newAggregates, _ := l.cache.AggregateData.Get(evt.URI)
+			aggDiags, _ := l.cache.GetAggregateDiagnostics(evt.URI)
+
+			if len(aggDiags) > 0 || !reflect.DeepEqual(oldAggregates, newAggregates) {
+				l.updateAggregateDiagnostics(ctx)
 			}
 		case <-l.diagnosticRequestWorkspace:
 			// results will be sent in response to the next workspace/diagnostics request
-			err := updateAllDiagnostics(ctx, l.cache, l.loadedConfig, l.clientRootURI)
+			start := time.Now()
+
+			rpt, err := updateAllDiagnostics(ctx, l.cache, l.loadedConfig, l.getCustomRules(), l.clientRootURI)
+
+			l.metrics.ObserveLint(time.Since(start), rpt)
+
 			if err != nil {
 				l.logError(fmt.Errorf("failed to update aggregate diagnostics (trigger): %w", err))
+			} else if err = l.saveIndex(); err != nil {
+				l.logError(fmt.Errorf("failed to save index: %w", err))
 			}
 
 			// send diagnostics for all files
@@ -252,17 +434,30 @@ func (l *LanguageServer) StartDiagnosticsWorker(ctx context.Context) {
 					l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
 				}
 			}
+
+			// the schemas declared in annotations may have changed, so all data files are validated again
+			for fileURI, contents := range l.cache.DataFiles.GetAll() {
+				l.updateDataFileDiagnostics(fileURI, contents)
+
+				if err = l.sendFileDiagnostics(ctx, fileURI); err != nil {
+					l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
+				}
+			}
 		}
 	}
 }
 
 func (l *LanguageServer) StartHoverWorker(ctx context.Context) {
+	l.runWorker(ctx, "hover worker", l.hoverWorker)
+}
+
+func (l *LanguageServer) hoverWorker(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case evt := <-l.builtinsPositionFile:
-			err := l.processBuiltinsUpdate(ctx, evt.URI, evt.Content)
+			err := l.processBuiltinsUpdate(ctx, evt.URI, evt.Content, evt.Version)
 			if err != nil {
 				l.logError(fmt.Errorf("failed to process builtin positions update: %w", err))
 			}
@@ -308,6 +503,9 @@ func (l *LanguageServer) StartConfigWorker(ctx context.Context) {
 			}
 			l.loadedConfigLock.Unlock()
 
+			// a .regal directory with custom rules may have been created along with the config file
+			l.loadCustomRules()
+
 			l.diagnosticRequestWorkspace <- "config file changed"
 		case <-l.configWatcher.Drop:
 			l.loadedConfigLock.Lock()
@@ -319,7 +517,34 @@ func (l *LanguageServer) StartConfigWorker(ctx context.Context) {
 	}
 }
 
+// loadCustomRules finds the custom rules of the workspace, and returns whether they changed since last found.
+func (l *LanguageServer) loadCustomRules() bool {
+	custom, err := findCustomRules(uri.ToPath(l.clientIdentifier, l.clientRootURI))
+	if err != nil {
+		l.logError(fmt.Errorf("failed to load custom rules: %w", err))
+	}
+
+	l.customRulesLock.Lock()
+	defer l.customRulesLock.Unlock()
+
+	changed := custom != l.customRules
+	l.customRules = custom
+
+	return changed
+}
+
+func (l *LanguageServer) getCustomRules() customRules {
+	l.customRulesLock.Lock()
+	defer l.customRulesLock.Unlock()
+
+	return l.customRules
+}
+
 func (l *LanguageServer) StartCommandWorker(ctx context.Context) {
+	l.runWorker(ctx, "command worker", l.commandWorker)
+}
+
+func (l *LanguageServer) commandWorker(ctx context.Context) {
 	// note, in this function conn.Call is used as the workspace/applyEdit message is a request, not a notification
 	// as per the spec. In order to be 'routed' to the correct handler on the client it must have an ID
 	// receive responses too.
@@ -345,8 +570,8 @@ func (l *LanguageServer) StartCommandWorker(ctx context.Context) {
 				)
 			case "regal.fix.use-rego-v1":
 				fixed, editParams, err = l.fixEditParams(
-					"Format for Rego v1 using opa-fmt",
-					&fixes.Fmt{OPAFmtOpts: format.Opts{RegoVersion: ast.RegoV0CompatV1}},
+					"Migrate to Rego v1",
+					&fixes.RegoV1{},
 					commands.ParseOptions{TargetArgIndex: 0},
 					params,
 				)
@@ -364,6 +589,72 @@ func (l *LanguageServer) StartCommandWorker(ctx context.Context) {
 					commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2},
 					params,
 				)
+			case "regal.fix.redundant-alias":
+				fixed, editParams, err = l.fixEditParams(
+					"Remove redundant alias",
+					&fixes.RedundantAlias{},
+					commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2},
+					params,
+				)
+			case "regal.fix.import-shadows-import":
+				fixed, editParams, err = l.fixEditParams(
+					"Remove duplicate import",
+					&fixes.ImportShadowsImport{},
+					commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2},
+					params,
+				)
+			case "regal.fix.unsorted-imports":
+				fixed, editParams, err = l.fixEditParams(
+					"Sort imports",
+					&fixes.UnsortedImports{},
+					commands.ParseOptions{TargetArgIndex: 0},
+					params,
+				)
+			case "regal.fix.trailing-whitespace":
+				fixed, editParams, err = l.fixEditParams(
+					"Remove trailing whitespace",
+					&fixes.TrailingWhitespace{},
+					commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2},
+					params,
+				)
+			case "regal.fix.indentation":
+				var ruleConfig config.Rule
+
+				l.loadedConfigLock.Lock()
+				if l.loadedConfig != nil {
+					ruleConfig = l.loadedConfig.Rules["style"]["indentation"]
+				}
+				l.loadedConfigLock.Unlock()
+
+				fixed, editParams, err = l.fixEditParams(
+					"Fix indentation",
+					&fixes.Indentation{Width: rules.IndentWidth(ruleConfig)},
+					commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2},
+					params,
+				)
+			case "regal.fix.missing-final-newline":
+				fixed, editParams, err = l.fixEditParams(
+					"Add final newline",
+					&fixes.MissingFinalNewline{},
+					commands.ParseOptions{TargetArgIndex: 0},
+					params,
+				)
+			case "regal.fix.prefer-raw-string":
+				fixed, editParams, err = l.fixEditParams(
+					"Convert to raw string",
+					&fixes.PreferRawString{},
+					commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2},
+					params,
+				)
+			case "regal.metadata.generate":
+				fixed, editParams, err = l.generateMetadataEditParams(params)
+			}
+
+			var regoV1Err *fixes.RegoV1Error
+			if errors.As(err, &regoV1Err) {
+				l.showRegoV1Issues(ctx, regoV1Err)
+
+				break
 			}
 
 			if err != nil {
@@ -382,6 +673,32 @@ func (l *LanguageServer) StartCommandWorker(ctx context.Context) {
 	}
 }
 
+// showRegoV1Issues tells the user about the issues which prevented a file from being migrated to Rego v1, as these
+// must be fixed by hand.
+func (l *LanguageServer) showRegoV1Issues(ctx context.Context, regoV1Err *fixes.RegoV1Error) {
+	issues := make([]string, 0, len(regoV1Err.Issues))
+
+	for _, issue := range regoV1Err.Issues {
+		if issue.Location != nil {
+			issues = append(issues, fmt.Sprintf("line %d: %s", issue.Location.Row, issue.Message))
+		} else {
+			issues = append(issues, issue.Message)
+		}
+	}
+
+	resp := types.ShowMessageParams{
+		Type: 2, // warning
+		Message: fmt.Sprintf(
+			"%s can't be migrated to Rego v1 until these issues are fixed: %s",
+			regoV1Err.Filename, strings.Join(issues, "; "),
+		),
+	}
+
+	if err := l.conn.Notify(ctx, "window/showMessage", resp); err != nil {
+		l.logError(fmt.Errorf("failed to notify: %w", err))
+	}
+}
+
 func (l *LanguageServer) fixEditParams(
 	label string,
 	fix fixes.Fix,
@@ -433,27 +750,91 @@ func (l *LanguageServer) fixEditParams(
 	return true, editParams, nil
 }
 
+func (l *LanguageServer) generateMetadataEditParams(
+	params types.ExecuteCommandParams,
+) (bool, *types.ApplyWorkspaceEditParams, error) {
+	pr, err := commands.Parse(params, commands.ParseOptions{TargetArgIndex: 0, RowArgIndex: 1, ColArgIndex: 2})
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to parse command params: %w", err)
+	}
+
+	if pr.Location == nil {
+		return false, nil, errors.New("no location provided for METADATA generation")
+	}
+
+	contents, ok := l.cache.GetFileContents(pr.Target)
+	if !ok {
+		return false, nil, fmt.Errorf("could not get file contents for uri %q", pr.Target)
+	}
+
+	module, ok := l.cache.GetModule(pr.Target)
+	if !ok {
+		return false, nil, fmt.Errorf("could not get module for uri %q", pr.Target)
+	}
+
+	edit := metadataEdit(module, contents, pr.Location.Row)
+	if edit == nil {
+		return false, &types.ApplyWorkspaceEditParams{}, nil
+	}
+
+	editParams := &types.ApplyWorkspaceEditParams{
+		Label: "Generate METADATA block",
+		Edit: types.WorkspaceEdit{
+			DocumentChanges: []types.TextDocumentEdit{
+				{
+					TextDocument: types.OptionalVersionedTextDocumentIdentifier{URI: pr.Target},
+					Edits:        []types.TextEdit{*edit},
+				},
+			},
+		},
+	}
+
+	return true, editParams, nil
+}
+
 // processTextContentUpdate updates the cache with the new content for the file at the given URI, attempts to parse the
 // file, and returns whether the parse was successful. If it was not successful, the parse errors will be sent
-// on the diagnostic channel.
+// on the diagnostic channel. Updates older than the document version already cached are ignored.
 func (l *LanguageServer) processTextContentUpdate(
 	ctx context.Context,
 	uri string,
 	content string,
+	version *uint,
 ) (bool, error) {
-	currentContent, ok := l.cache.GetFileContents(uri)
-	if ok && currentContent == content {
+	// skip parsing and linting when the contents are unchanged, e.g. when a file
+	// loaded from the workspace is opened in the editor
+	if l.cache.HasContents(uri, content) {
+		if _, ok := l.cache.GetModule(uri); ok {
+			return false, nil
+		}
+
+		// the module has been evicted from the cache, and must be parsed again
+		return l.processParse(ctx, uri)
+	}
+
+	if !l.setFileContents(uri, content, version) {
 		return false, nil
 	}
 
-	l.cache.SetFileContents(uri, content)
+	return l.processParse(ctx, uri)
+}
 
+// processParse parses the cached contents of the file at the given URI, and returns whether the parse was
+// successful. If it was, the tests of the file are updated, and otherwise, the parse errors are sent to the client.
+func (l *LanguageServer) processParse(ctx context.Context, uri string) (bool, error) {
 	success, err := updateParse(l.cache, uri)
 	if err != nil {
 		return false, fmt.Errorf("failed to update parse: %w", err)
 	}
 
 	if success {
+		if tests, changed := updateTests(l.cache, uri); changed {
+			err = l.sendTestsDiscovered(ctx, uri, tests)
+			if err != nil {
+				return false, fmt.Errorf("failed to send tests: %w", err)
+			}
+		}
+
 		return true, nil
 	}
 
@@ -465,7 +846,7 @@ func (l *LanguageServer) processTextContentUpdate(
 	return false, nil
 }
 
-func (l *LanguageServer) processBuiltinsUpdate(_ context.Context, uri string, content string) error {
+func (l *LanguageServer) processBuiltinsUpdate(_ context.Context, uri string, content string, version *uint) error {
 	if _, ok := l.cache.GetFileContents(uri); !ok {
 		// If the file is not in the cache, exit early or else
 		// we might accidentally put it in the cache after it's been
@@ -473,7 +854,9 @@ func (l *LanguageServer) processBuiltinsUpdate(_ context.Context, uri string, co
 		return nil
 	}
 
-	l.cache.SetFileContents(uri, content)
+	if !l.setFileContents(uri, content, version) {
+		return nil
+	}
 
 	success, err := updateParse(l.cache, uri)
 	if err != nil {
@@ -492,7 +875,86 @@ func (l *LanguageServer) processBuiltinsUpdate(_ context.Context, uri string, co
 	return nil
 }
 
+// indexPath returns the path of the index for the current workspace, or an empty string if
+// no index should be used.
+func (l *LanguageServer) indexPath() string {
+	if l.indexDir == "" || l.clientRootURI == "" {
+		return ""
+	}
+
+	return filepath.Join(l.indexDir, cache.Hash(l.clientRootURI)+".json")
+}
+
+// configHash returns the hash of the contents of the Regal config file for the workspace, if any,
+// along with the digest of the custom rules of the workspace, as both decide the results of linting.
+func (l *LanguageServer) configHash() string {
+	custom := l.getCustomRules()
+	digest := hex.EncodeToString(custom.digest[:])
+
+	configFile, err := config.FindConfig(uri.ToPath(l.clientIdentifier, l.clientRootURI))
+	if err != nil {
+		return cache.Hash(digest)
+	}
+
+	defer configFile.Close()
+
+	bs, err := io.ReadAll(configFile)
+	if err != nil {
+		return cache.Hash(digest)
+	}
+
+	return cache.Hash(digest + string(bs))
+}
+
+// loadIndex loads the index saved in a previous session, if any, for the data of unchanged files to
+// be restored from it.
+func (l *LanguageServer) loadIndex() *cache.Index {
+	path := l.indexPath()
+	if path == "" {
+		return nil
+	}
+
+	index, err := cache.LoadIndex(path)
+	if err != nil {
+		// there's no index the first time a workspace is opened
+		if !errors.Is(err, os.ErrNotExist) {
+			l.logError(fmt.Errorf("failed to load index: %w", err))
+		}
+
+		return nil
+	}
+
+	return index
+}
+
+func (l *LanguageServer) saveIndex() error {
+	path := l.indexPath()
+	if path == "" {
+		return nil
+	}
+
+	if err := cache.SaveIndex(path, l.cache.Index(version.Version, l.configHash())); err != nil {
+		return fmt.Errorf("failed to save index to %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// setFileContents updates the cached contents for the file at uri, and returns false if the update was
+// ignored as contents of a newer document version had already been received.
+func (l *LanguageServer) setFileContents(uri string, content string, version *uint) bool {
+	if version == nil {
+		l.cache.SetFileContents(uri, content)
+
+		return true
+	}
+
+	return l.cache.SetFileContentsVersion(uri, content, *version)
+}
+
 func (l *LanguageServer) logError(err error) {
+	l.debugBundle.AddError(err)
+
 	if l.errorLog != nil {
 		fmt.Fprintf(l.errorLog, "ERROR: %s\n", err)
 	}
@@ -504,7 +966,7 @@ type HoverResponse struct {
 }
 
 func (l *LanguageServer) handleTextDocumentHover(
-	_ context.Context,
+	ctx context.Context,
 	_ *jsonrpc2.Conn,
 	req *jsonrpc2.Request,
 ) (result any, err error) {
@@ -540,8 +1002,88 @@ func (l *LanguageServer) handleTextDocumentHover(
 		}
 	}
 
-	// return "null" as per the spec
-	return nil, nil
+	module, ok := l.cache.GetModule(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+
+	if response, ok := l.inputHover(module, params); ok {
+		return response, nil
+	}
+
+	rule, rng, ok := hover.ConstantRuleAt(module, params.Position.Line, params.Position.Character)
+	if !ok {
+		return nil, nil
+	}
+
+	// failing to evaluate is expected when e.g. the module doesn't compile, and
+	// there's nothing to show in that case
+	value, ok, err := hover.EvalConstant(ctx, module, rule)
+	if err != nil || !ok {
+		return nil, nil
+	}
+
+	contents, err := hover.CreateConstantHoverContent(rule, value)
+	if err != nil {
+		l.logError(fmt.Errorf("failed to create hover content: %w", err))
+
+		return nil, nil
+	}
+
+	return HoverResponse{
+		Contents: types.MarkupContent{
+			Kind:  "markdown",
+			Value: contents,
+		},
+		Range: rng,
+	}, nil
+}
+
+// inputHover returns the hover response for a reference to input at the position, if the field referenced is found
+// in the schema of input.
+func (l *LanguageServer) inputHover(module *ast.Module, params types.TextDocumentHoverParams) (HoverResponse, bool) {
+	contents, ok := l.cache.GetFileContents(params.TextDocument.URI)
+	if !ok {
+		return HoverResponse{}, false
+	}
+
+	lines := strings.Split(contents, "\n")
+	if params.Position.Line >= uint(len(lines)) {
+		return HoverResponse{}, false
+	}
+
+	ref, start, end, ok := rego.RefAt(lines[params.Position.Line], params.Position.Character)
+	if !ok || !strings.HasPrefix(ref, "input.") {
+		return HoverResponse{}, false
+	}
+
+	rootPath := ""
+	if l.clientRootURI != "" {
+		rootPath = uri.ToPath(l.clientIdentifier, l.clientRootURI)
+	}
+
+	inputPath := l.inputDocumentPath(params.TextDocument.URI)
+
+	schema, ok := schemas.Input(module, int(params.Position.Line)+1, inputPath, rootPath)
+	if !ok {
+		return HoverResponse{}, false
+	}
+
+	field, ok := schemas.Lookup(schema, strings.Split(ref, ".")[1:])
+	if !ok {
+		return HoverResponse{}, false
+	}
+
+	return HoverResponse{
+		Contents: types.MarkupContent{
+			Kind:  "markdown",
+			Value: hover.CreateInputHoverContent(ref, field),
+		},
+		Range: types.Range{
+			Start: types.Position{Line: params.Position.Line, Character: start},
+			End:   types.Position{Line: params.Position.Line, Character: end},
+		},
+	}, true
 }
 
 func (l *LanguageServer) handleTextDocumentCodeAction(
@@ -557,8 +1099,24 @@ func (l *LanguageServer) handleTextDocumentCodeAction(
 	yes := true
 	actions := make([]types.CodeAction, 0)
 
+	metadataOffered := false
+
 	for _, diag := range params.Context.Diagnostics {
 		switch diag.Code {
+		case ruleNameMissingMetadata:
+			metadataOffered = true
+
+			actions = append(actions, types.CodeAction{
+				Title:       "Generate METADATA block",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command: GenerateMetadataCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(diag.Range.Start.Line+1), 10),
+					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
+				}),
+			})
 		case ruleNameOPAFmt:
 			actions = append(actions, types.CodeAction{
 				Title:       "Format using opa fmt",
@@ -567,13 +1125,13 @@ func (l *LanguageServer) handleTextDocumentCodeAction(
 				IsPreferred: &yes,
 				Command:     FmtCommand([]string{params.TextDocument.URI}),
 			})
-		case ruleNameUseRegoV1:
+		case ruleNameUseRegoV1, ruleNameImplicitFutureKeywords, ruleNameDeprecatedBuiltin:
 			actions = append(actions, types.CodeAction{
-				Title:       "Format for Rego v1 using opa fmt",
+				Title:       "Migrate to Rego v1",
 				Kind:        "quickfix",
 				Diagnostics: []types.Diagnostic{diag},
 				IsPreferred: &yes,
-				Command:     FmtV1Command([]string{params.TextDocument.URI}),
+				Command:     RegoV1Command([]string{params.TextDocument.URI}),
 			})
 		case "use-assignment-operator":
 			actions = append(actions, types.CodeAction{
@@ -599,6 +1157,88 @@ func (l *LanguageServer) handleTextDocumentCodeAction(
 					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
 				}),
 			})
+		case "redundant-alias":
+			actions = append(actions, types.CodeAction{
+				Title:       "Remove redundant alias",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command: RedundantAliasCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(diag.Range.Start.Line+1), 10),
+					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
+				}),
+			})
+		case "import-shadows-import":
+			// only duplicates can be removed, while imports shadowing another path must be fixed by hand
+			module, ok := l.cache.GetModule(params.TextDocument.URI)
+			if !ok || !fixes.IsDuplicateImport(module, int(diag.Range.Start.Line+1)) {
+				break
+			}
+
+			actions = append(actions, types.CodeAction{
+				Title:       "Remove duplicate import",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command: ImportShadowsImportCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(diag.Range.Start.Line+1), 10),
+					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
+				}),
+			})
+		case "unsorted-imports":
+			actions = append(actions, types.CodeAction{
+				Title:       "Sort imports",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command:     UnsortedImportsCommand([]string{params.TextDocument.URI}),
+			})
+		case "trailing-whitespace":
+			actions = append(actions, types.CodeAction{
+				Title:       "Remove trailing whitespace",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command: TrailingWhitespaceCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(diag.Range.Start.Line+1), 10),
+					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
+				}),
+			})
+		case "indentation":
+			actions = append(actions, types.CodeAction{
+				Title:       "Fix indentation",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command: IndentationCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(diag.Range.Start.Line+1), 10),
+					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
+				}),
+			})
+		case "missing-final-newline":
+			actions = append(actions, types.CodeAction{
+				Title:       "Add final newline",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command:     MissingFinalNewlineCommand([]string{params.TextDocument.URI}),
+			})
+		case "prefer-raw-string":
+			actions = append(actions, types.CodeAction{
+				Title:       "Convert to raw string",
+				Kind:        "quickfix",
+				Diagnostics: []types.Diagnostic{diag},
+				IsPreferred: &yes,
+				Command: PreferRawStringCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(diag.Range.Start.Line+1), 10),
+					strconv.FormatUint(uint64(diag.Range.Start.Character+1), 10),
+				}),
+			})
 		}
 
 		if l.clientIdentifier == clients.IdentifierVSCode {
@@ -618,6 +1258,22 @@ func (l *LanguageServer) handleTextDocumentCodeAction(
 		}
 	}
 
+	// offer to generate a METADATA block for any rule head in range that lacks one,
+	// regardless of whether the missing-metadata rule is enabled
+	if module, ok := l.cache.GetModule(params.TextDocument.URI); ok && !metadataOffered {
+		if ruleMissingMetadata(module, params.Range.Start.Line) != nil {
+			actions = append(actions, types.CodeAction{
+				Title: "Generate METADATA block",
+				Kind:  "refactor",
+				Command: GenerateMetadataCommand([]string{
+					params.TextDocument.URI,
+					strconv.FormatUint(uint64(params.Range.Start.Line+1), 10),
+					"1",
+				}),
+			})
+		}
+	}
+
 	return actions, nil
 }
 
@@ -631,6 +1287,11 @@ func (l *LanguageServer) handleWorkspaceExecuteCommand(
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
+	// switching the input document makes no edits, and the client is answered with the document now active
+	if params.Command == commandSetInputDocument {
+		return l.setActiveInputDocument(params)
+	}
+
 	// this must not block, so we send the request to the worker on a buffered channel.
 	// the response to the workspace/executeCommand request must be sent before the command is executed
 	// so that the client can complete the request and be ready to receive the follow-on request for
@@ -651,6 +1312,10 @@ func (l *LanguageServer) handleTextDocumentInlayHint(
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
+	if !featureEnabled(l.initializationOptions.InlayHints) {
+		return []types.InlayHint{}, nil
+	}
+
 	// when a file cannot be parsed, we do a best effort attempt to provide inlay hints
 	// by finding the location of the first parse error and attempting to parse up to that point
 	parseErrors, ok := l.cache.GetParseErrors(params.TextDocument.URI)
@@ -676,6 +1341,25 @@ func (l *LanguageServer) handleTextDocumentInlayHint(
 	return inlayHints, nil
 }
 
+func (l *LanguageServer) handleTextDocumentInlineValue(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.InlineValueParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	// inline values are requested while a debug session is stopped, where the file was parsed before it started
+	module, ok := l.cache.GetModule(params.TextDocument.URI)
+	if !ok {
+		return []types.InlineValueVariableLookup{}, nil
+	}
+
+	return getInlineValues(module, params.Range, params.Context.StoppedLocation), nil
+}
+
 func (l *LanguageServer) handleTextDocumentCompletion(
 	_ context.Context,
 	_ *jsonrpc2.Conn,
@@ -686,7 +1370,14 @@ func (l *LanguageServer) handleTextDocumentCompletion(
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
-	items, err := l.completionsManager.Run(params, &providers.Options{RootURI: l.clientRootURI})
+	if !featureEnabled(l.initializationOptions.Completion) {
+		return types.CompletionList{Items: make([]types.CompletionItem, 0)}, nil
+	}
+
+	items, err := l.completionsManager.Run(params, &providers.Options{
+		RootURI:           l.clientRootURI,
+		InputDocumentPath: l.inputDocumentPath,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find completions: %w", err)
 	}
@@ -705,6 +1396,30 @@ func (l *LanguageServer) handleTextDocumentCompletion(
 	}, nil
 }
 
+func (l *LanguageServer) handleTextDocumentSignatureHelp(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.SignatureHelpParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	contents, ok := l.cache.GetFileContents(params.TextDocument.URI)
+	if !ok {
+		// return "null" as per the spec
+		return nil, nil
+	}
+
+	signatureHelp, ok := getSignatureHelp(contents, params.Position)
+	if !ok {
+		return nil, nil
+	}
+
+	return signatureHelp, nil
+}
+
 func partialInlayHints(parseErrors []types.Diagnostic, contents, uri string) []types.InlayHint {
 	firstErrorLine := uint(0)
 	for _, parseError := range parseErrors {
@@ -718,53 +1433,142 @@ func partialInlayHints(parseErrors []types.Diagnostic, contents, uri string) []t
 		return []types.InlayHint{}
 	}
 
-	if firstErrorLine > uint(len(strings.Split(contents, "\n"))) {
-		// if the last valid line is beyond the end of the file, we exit as something is up
-		return []types.InlayHint{}
+	if firstErrorLine > uint(len(strings.Split(contents, "\n"))) {
+		// if the last valid line is beyond the end of the file, we exit as something is up
+		return []types.InlayHint{}
+	}
+
+	// select the lines from the contents up to the first parse error
+	lines := strings.Join(strings.Split(contents, "\n")[:firstErrorLine], "\n")
+
+	// parse the part of the module that might work
+	module, err := rparse.Module(uri, lines)
+	if err != nil {
+		// if we still can't parse the bit we hoped was valid, we exit as this is 'too hard'
+		return []types.InlayHint{}
+	}
+
+	return getInlayHints(module)
+}
+
+func (l *LanguageServer) handleWorkspaceSymbol(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.WorkspaceSymbolParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	symbols := make([]types.WorkspaceSymbol, 0)
+	contents := l.cache.GetAllFiles()
+
+	// Note: currently ignoring params.Query, as the client seems to do a good
+	// job of filtering anyway, and that would merely be an optimization here.
+	// But perhaps a good one to do at some point, and I'm not sure all clients
+	// do this filtering.
+
+	for moduleURL, module := range allModules(l.cache) {
+		content := contents[moduleURL]
+		docSyms := documentSymbols(content, module)
+		wrkSyms := make([]types.WorkspaceSymbol, 0)
+
+		toWorkspaceSymbols(docSyms, moduleURL, &wrkSyms)
+
+		symbols = append(symbols, wrkSyms...)
+	}
+
+	return symbols, nil
+}
+
+func (l *LanguageServer) handleRegalTests(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.TestsParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	if params.TextDocument != nil {
+		tests, ok := l.cache.GetTests(params.TextDocument.URI)
+		if !ok {
+			return []types.TestItem{}, nil
+		}
+
+		return tests, nil
 	}
 
-	// select the lines from the contents up to the first parse error
-	lines := strings.Join(strings.Split(contents, "\n")[:firstErrorLine], "\n")
+	tests := make([]types.TestItem, 0)
 
-	// parse the part of the module that might work
-	module, err := rparse.Module(uri, lines)
-	if err != nil {
-		// if we still can't parse the bit we hoped was valid, we exit as this is 'too hard'
-		return []types.InlayHint{}
+	for _, fileTests := range l.cache.GetAllTests() {
+		tests = append(tests, fileTests...)
 	}
 
-	return getInlayHints(module)
+	slices.SortFunc(tests, func(a, b types.TestItem) int {
+		if c := strings.Compare(a.URI, b.URI); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Range.Start.Line, b.Range.Start.Line)
+	})
+
+	return tests, nil
 }
 
-func (l *LanguageServer) handleWorkspaceSymbol(
-	_ context.Context,
+func (l *LanguageServer) handleRegalRunTests(
+	ctx context.Context,
 	_ *jsonrpc2.Conn,
 	req *jsonrpc2.Request,
 ) (result any, err error) {
-	var params types.WorkspaceSymbolParams
+	var params types.RunTestsParams
 	if err := json.Unmarshal(*req.Params, &params); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
-	symbols := make([]types.WorkspaceSymbol, 0)
-	contents := l.cache.GetAllFiles()
+	ids := params.IDs
 
-	// Note: currently ignoring params.Query, as the client seems to do a good
-	// job of filtering anyway, and that would merely be an optimization here.
-	// But perhaps a good one to do at some point, and I'm not sure all clients
-	// do this filtering.
+	// when a file is provided without specific tests, all the tests in that file are run
+	if params.TextDocument != nil && len(ids) == 0 {
+		tests, ok := l.cache.GetTests(params.TextDocument.URI)
+		if !ok || len(tests) == 0 {
+			return []types.TestResult{}, nil
+		}
 
-	for moduleURL, module := range l.cache.GetAllModules() {
-		content := contents[moduleURL]
-		docSyms := documentSymbols(content, module)
-		wrkSyms := make([]types.WorkspaceSymbol, 0)
+		for _, test := range tests {
+			ids = append(ids, test.ID)
+		}
+	}
 
-		toWorkspaceSymbols(docSyms, moduleURL, &wrkSyms)
+	results, err := runTests(ctx, allModules(l.cache), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tests: %w", err)
+	}
 
-		symbols = append(symbols, wrkSyms...)
+	return results, nil
+}
+
+func (l *LanguageServer) handleRegalModuleGraph(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	_ *jsonrpc2.Request,
+) (result any, err error) {
+	modules, err := l.getFilteredModules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter ignored paths: %w", err)
 	}
 
-	return symbols, nil
+	return moduleGraph(modules), nil
+}
+
+func (l *LanguageServer) handleRegalWorkspaceDiagnosticsSummary(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	_ *jsonrpc2.Request,
+) (result any, err error) {
+	return diagnosticsSummary(l.cache.GetAllDiagnostics()), nil
 }
 
 func (l *LanguageServer) handleTextDocumentDefinition(
@@ -819,6 +1623,31 @@ func (l *LanguageServer) handleTextDocumentDefinition(
 	return loc, nil
 }
 
+func (l *LanguageServer) handleTextDocumentReferences(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.ReferenceParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	symbols, ok := l.cache.GetSymbols(params.TextDocument.URI)
+	if !ok {
+		return []types.Location{}, nil
+	}
+
+	all := l.cache.GetAllSymbols()
+
+	name, ok := symbolAt(symbols, params.Position, all)
+	if !ok {
+		return []types.Location{}, nil
+	}
+
+	return findReferences(all, name, params.Context.IncludeDeclaration), nil
+}
+
 func (l *LanguageServer) handleTextDocumentDidOpen(
 	_ context.Context,
 	_ *jsonrpc2.Conn,
@@ -829,6 +1658,8 @@ func (l *LanguageServer) handleTextDocumentDidOpen(
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
+	l.cache.SetOpen(params.TextDocument.URI, true)
+
 	evt := fileUpdateEvent{
 		Reason:  "textDocument/didOpen",
 		URI:     params.TextDocument.URI,
@@ -836,7 +1667,26 @@ func (l *LanguageServer) handleTextDocumentDidOpen(
 	}
 
 	l.diagnosticRequestFile <- evt
-	l.builtinsPositionFile <- evt
+
+	if !config.IsDataFile(evt.URI) {
+		l.builtinsPositionFile <- evt
+	}
+
+	return struct{}{}, nil
+}
+
+func (l *LanguageServer) handleTextDocumentDidClose(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.TextDocumentDidCloseParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	// once closed, the parsed module of the file may be evicted from the cache
+	l.cache.SetOpen(params.TextDocument.URI, false)
 
 	return struct{}{}, nil
 }
@@ -855,10 +1705,20 @@ func (l *LanguageServer) handleTextDocumentDidChange(
 		Reason:  "textDocument/didChange",
 		URI:     params.TextDocument.URI,
 		Content: params.ContentChanges[0].Text,
+		Version: &params.TextDocument.Version,
+	}
+
+	// the contents are set right away, and not by the workers, for requests following the change, like
+	// on-type formatting, to see it
+	if !config.IsDataFile(evt.URI) {
+		l.setFileContents(evt.URI, evt.Content, evt.Version)
 	}
 
 	l.diagnosticRequestFile <- evt
-	l.builtinsPositionFile <- evt
+
+	if !config.IsDataFile(evt.URI) {
+		l.builtinsPositionFile <- evt
+	}
 
 	return struct{}{}, nil
 }
@@ -873,6 +1733,12 @@ func (l *LanguageServer) handleTextDocumentDidSave(
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
+	// custom rules are read from disk when linting, so files must be linted again once changes to them are saved
+	if l.workspaceMode && isCustomRuleFile(uri.ToPath(l.clientIdentifier, params.TextDocument.URI)) &&
+		l.loadCustomRules() {
+		l.diagnosticRequestWorkspace <- "custom rules changed"
+	}
+
 	if params.Text != nil && l.loadedConfig != nil {
 		if !strings.Contains(*params.Text, "\r\n") {
 			return struct{}{}, nil
@@ -955,12 +1821,20 @@ func (l *LanguageServer) handleTextDocumentFoldingRange(
 		return []types.FoldingRange{}, nil
 	}
 
+	if ranges, ok := l.cache.FoldingRanges.Get(params.TextDocument.URI); ok {
+		return ranges, nil
+	}
+
 	text, ok := l.cache.GetFileContents(params.TextDocument.URI)
 	if !ok {
 		return []types.FoldingRange{}, nil
 	}
 
-	return findFoldingRanges(text, module), nil
+	ranges := findFoldingRanges(text, module)
+
+	l.cache.FoldingRanges.Set(params.TextDocument.URI, ranges)
+
+	return ranges, nil
 }
 
 func (l *LanguageServer) handleTextDocumentFormatting(
@@ -973,6 +1847,10 @@ func (l *LanguageServer) handleTextDocumentFormatting(
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
 
+	if !featureEnabled(l.initializationOptions.Formatting) {
+		return []types.TextEdit{}, nil
+	}
+
 	if warnings := validateFormattingOptions(params.Options); len(warnings) > 0 {
 		l.logError(fmt.Errorf("formatting params validation warnings: %v", warnings))
 	}
@@ -1002,6 +1880,28 @@ func (l *LanguageServer) handleTextDocumentFormatting(
 	return ComputeEdits(oldContent, string(fixResults[0].Contents)), nil
 }
 
+func (l *LanguageServer) handleTextDocumentOnTypeFormatting(
+	_ context.Context,
+	_ *jsonrpc2.Conn,
+	req *jsonrpc2.Request,
+) (result any, err error) {
+	var params types.DocumentOnTypeFormattingParams
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+
+	if !featureEnabled(l.initializationOptions.Formatting) {
+		return []types.TextEdit{}, nil
+	}
+
+	contents, ok := l.cache.GetFileContents(params.TextDocument.URI)
+	if !ok {
+		return []types.TextEdit{}, nil
+	}
+
+	return onTypeFormattingEdits(contents, params.Position, params.Ch), nil
+}
+
 func (l *LanguageServer) handleWorkspaceDidCreateFiles(
 	_ context.Context,
 	_ *jsonrpc2.Conn,
@@ -1069,16 +1969,21 @@ func (l *LanguageServer) handleWorkspaceDidRenameFiles(
 	}
 
 	for _, renameOp := range params.Files {
-		content, err := cache.UpdateCacheForURIFromDisk(
-			l.cache,
-			uri.FromPath(l.clientIdentifier, renameOp.NewURI),
-			uri.ToPath(l.clientIdentifier, renameOp.NewURI),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to update cache for uri %q: %w", renameOp.NewURI, err)
-		}
+		var content string
 
-		l.cache.Delete(renameOp.OldURI)
+		if l.cache.Rename(renameOp.OldURI, renameOp.NewURI) {
+			content, _ = l.cache.GetFileContents(renameOp.NewURI)
+		} else {
+			// the file was not previously known, e.g. if renamed from a non-Rego file
+			content, err = cache.UpdateCacheForURIFromDisk(
+				l.cache,
+				uri.FromPath(l.clientIdentifier, renameOp.NewURI),
+				uri.ToPath(l.clientIdentifier, renameOp.NewURI),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update cache for uri %q: %w", renameOp.NewURI, err)
+			}
+		}
 
 		evt := fileUpdateEvent{
 			Reason:  "textDocument/didRename",
@@ -1120,8 +2025,8 @@ func (l *LanguageServer) handleWorkspaceDiagnostic(
 }
 
 func (l *LanguageServer) handleInitialize(
-	_ context.Context,
-	_ *jsonrpc2.Conn,
+	ctx context.Context,
+	conn *jsonrpc2.Conn,
 	req *jsonrpc2.Request,
 ) (result any, err error) {
 	var params types.InitializeParams
@@ -1131,6 +2036,14 @@ func (l *LanguageServer) handleInitialize(
 
 	l.clientRootURI = params.RootURI
 	l.clientIdentifier = clients.DetermineClientIdentifier(params.ClientInfo.Name)
+	l.debugBundle.AddContext("client", params.ClientInfo.Name)
+	l.testDiscovery = params.Capabilities.Experimental.TestDiscovery
+	l.initializationOptions = params.InitializationOptions
+
+	l.cache.SetLimits(cache.Limits{
+		MaxFiles: params.InitializationOptions.CacheMaxFiles,
+		MaxBytes: params.InitializationOptions.CacheMaxBytes,
+	})
 
 	if l.clientIdentifier == clients.IdentifierGeneric {
 		l.logError(
@@ -1146,70 +2059,105 @@ func (l *LanguageServer) handleInitialize(
 		},
 	}
 
-	result = types.InitializeResult{
-		Capabilities: types.ServerCapabilities{
-			TextDocumentSyncOptions: types.TextDocumentSyncOptions{
-				OpenClose: true,
-				Change:    1, // TODO: write logic to use 2, for incremental updates
-				Save: types.TextDocumentSaveOptions{
-					IncludeText: true,
-				},
+	capabilities := types.ServerCapabilities{
+		TextDocumentSyncOptions: types.TextDocumentSyncOptions{
+			OpenClose: true,
+			Change:    1, // TODO: write logic to use 2, for incremental updates
+			Save: types.TextDocumentSaveOptions{
+				IncludeText: true,
 			},
-			DiagnosticProvider: types.DiagnosticOptions{
-				Identifier:            "rego",
-				InterFileDependencies: true,
-				WorkspaceDiagnostics:  true,
-			},
-			Workspace: types.WorkspaceOptions{
-				FileOperations: types.FileOperationsServerCapabilities{
-					DidCreate: types.FileOperationRegistrationOptions{
-						Filters: []types.FileOperationFilter{regoFilter},
-					},
-					DidRename: types.FileOperationRegistrationOptions{
-						Filters: []types.FileOperationFilter{regoFilter},
-					},
-					DidDelete: types.FileOperationRegistrationOptions{
-						Filters: []types.FileOperationFilter{regoFilter},
-					},
+		},
+		DiagnosticProvider: types.DiagnosticOptions{
+			Identifier:            "rego",
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		},
+		Workspace: types.WorkspaceOptions{
+			FileOperations: types.FileOperationsServerCapabilities{
+				DidCreate: types.FileOperationRegistrationOptions{
+					Filters: []types.FileOperationFilter{regoFilter},
 				},
-			},
-			InlayHintProvider: types.InlayHintOptions{
-				ResolveProvider: false,
-			},
-			HoverProvider: true,
-			CodeActionProvider: types.CodeActionOptions{
-				CodeActionKinds: []string{"quickfix"},
-			},
-			ExecuteCommandProvider: types.ExecuteCommandOptions{
-				Commands: []string{
-					"regal.fix.opa-fmt",
-					"regal.fix.use-rego-v1",
-					"regal.fix.use-assignment-operator",
-					"regal.fix.no-whitespace-comment",
+				DidRename: types.FileOperationRegistrationOptions{
+					Filters: []types.FileOperationFilter{regoFilter},
 				},
-			},
-			DocumentFormattingProvider: true,
-			FoldingRangeProvider:       true,
-			DefinitionProvider:         true,
-			DocumentSymbolProvider:     true,
-			WorkspaceSymbolProvider:    true,
-			CompletionProvider: types.CompletionOptions{
-				ResolveProvider: false,
-				CompletionItem: types.CompletionItemOptions{
-					LabelDetailsSupport: true,
+				DidDelete: types.FileOperationRegistrationOptions{
+					Filters: []types.FileOperationFilter{regoFilter},
 				},
 			},
 		},
+		HoverProvider: true,
+		CodeActionProvider: types.CodeActionOptions{
+			CodeActionKinds: []string{"quickfix", "refactor"},
+		},
+		ExecuteCommandProvider: types.ExecuteCommandOptions{
+			Commands: []string{
+				"regal.fix.opa-fmt",
+				"regal.fix.use-rego-v1",
+				"regal.fix.use-assignment-operator",
+				"regal.fix.no-whitespace-comment",
+				"regal.fix.redundant-alias",
+				"regal.fix.import-shadows-import",
+				"regal.fix.unsorted-imports",
+				"regal.fix.trailing-whitespace",
+				"regal.fix.indentation",
+				"regal.fix.missing-final-newline",
+				"regal.fix.prefer-raw-string",
+				"regal.metadata.generate",
+				commandSetInputDocument,
+			},
+		},
+		DocumentFormattingProvider: featureEnabled(l.initializationOptions.Formatting),
+		FoldingRangeProvider:       true,
+		DefinitionProvider:         true,
+		DocumentSymbolProvider:     true,
+		WorkspaceSymbolProvider:    true,
+		ReferencesProvider:         true,
+		InlineValueProvider:        true,
+		SignatureHelpProvider: &types.SignatureHelpOptions{
+			TriggerCharacters:   []string{"(", ","},
+			RetriggerCharacters: []string{")"},
+		},
+	}
+
+	if featureEnabled(l.initializationOptions.Formatting) {
+		capabilities.DocumentOnTypeFormattingProvider = &types.DocumentOnTypeFormattingOptions{
+			FirstTriggerCharacter: "\n",
+			MoreTriggerCharacter:  []string{"}"},
+		}
+	}
+
+	if featureEnabled(l.initializationOptions.Completion) {
+		capabilities.CompletionProvider = &types.CompletionOptions{
+			ResolveProvider: false,
+			CompletionItem: types.CompletionItemOptions{
+				LabelDetailsSupport: true,
+			},
+		}
+	}
+
+	if featureEnabled(l.initializationOptions.InlayHints) {
+		capabilities.InlayHintProvider = &types.InlayHintOptions{
+			ResolveProvider: false,
+		}
 	}
 
+	result = types.InitializeResult{Capabilities: capabilities}
+
 	if l.clientRootURI != "" {
 		l.workspaceMode = true
 
-		err = l.loadWorkspaceContents()
+		// custom rules are found first, as the index is only restored if they're unchanged
+		l.loadCustomRules()
+
+		index := l.loadIndex()
+
+		err = l.loadWorkspaceContents(ctx, conn, params.WorkDoneToken, index)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load workspace contents: %w", err)
 		}
 
+		l.indexRestored = l.cache.RestoreIndex(index, version.Version, l.configHash())
+
 		configFile, err := config.FindConfig(uri.ToPath(l.clientIdentifier, l.clientRootURI))
 		if err == nil {
 			l.configWatcher.Watch(configFile.Name())
@@ -1219,45 +2167,169 @@ func (l *LanguageServer) handleInitialize(
 	return result, nil
 }
 
-func (l *LanguageServer) loadWorkspaceContents() error {
+// loadWorkspaceContents reads and parses all Rego files in the workspace. Files are loaded concurrently by a
+// bounded pool of workers, each writing its results to the cache as soon as a file has been parsed. If the
+// client provided a progress token, the progress of loading the workspace is reported to the client. Files
+// unchanged since the index provided, if any, was saved aren't parsed, but restored from the index.
+func (l *LanguageServer) loadWorkspaceContents(
+	ctx context.Context,
+	conn *jsonrpc2.Conn,
+	token any,
+	index *cache.Index,
+) error {
 	workspaceRootPath := uri.ToPath(l.clientIdentifier, l.clientRootURI)
 
-	err := filepath.WalkDir(workspaceRootPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return fmt.Errorf("failed to walk workspace dir %q: %w", path, err)
-		}
+	var traversal config.Traversal
 
-		// TODO(charlieegan3): make this configurable for things like .rq etc?
-		if d.IsDir() || !strings.HasSuffix(path, ".rego") {
-			return nil
-		}
+	l.loadedConfigLock.Lock()
+	if l.loadedConfig != nil {
+		traversal.NoGitignore = l.loadedConfig.Traversal.NoGitignore
+	}
+	l.loadedConfigLock.Unlock()
+
+	// files ignored by .regalignore and .gitignore files are not loaded, while those ignored by the configuration
+	// are, as the configuration may change while the server is running
+	paths, err := config.FilterIgnoredPathsWithTraversal([]string{workspaceRootPath}, nil, "", traversal)
+	if err != nil {
+		return fmt.Errorf("failed to walk workspace dir %q: %w", workspaceRootPath, err)
+	}
+
+	l.sendProgress(ctx, conn, token, types.WorkDoneProgressBegin{
+		Kind:       "begin",
+		Title:      "Loading workspace",
+		Message:    fmt.Sprintf("0/%d files", len(paths)),
+		Percentage: 0,
+	})
+
+	jobs := make(chan string)
+	errs := make(chan error, len(paths))
+
+	var (
+		wg     sync.WaitGroup
+		loaded atomic.Int64
+	)
+
+	for range min(runtime.NumCPU(), len(paths)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for path := range jobs {
+				if err := l.loadWorkspaceFile(path, index); err != nil {
+					errs <- err
+
+					continue
+				}
+
+				n := int(loaded.Add(1))
+
+				// only report when the percentage changes, to not flood the client with notifications
+				if percentage := n * 100 / len(paths); percentage != (n-1)*100/len(paths) {
+					l.sendProgress(ctx, conn, token, types.WorkDoneProgressReport{
+						Kind:       "report",
+						Message:    fmt.Sprintf("%d/%d files", n, len(paths)),
+						Percentage: uint(percentage),
+					})
+				}
+			}
+		}()
+	}
+
+	for _, path := range paths {
+		jobs <- path
+	}
 
-		fileURI := uri.FromPath(l.clientIdentifier, path)
+	close(jobs)
+	wg.Wait()
+	close(errs)
 
-		_, err = cache.UpdateCacheForURIFromDisk(l.cache, fileURI, path)
+	l.sendProgress(ctx, conn, token, types.WorkDoneProgressEnd{
+		Kind:    "end",
+		Message: fmt.Sprintf("Loaded %d files", loaded.Load()),
+	})
+
+	// data files are validated along with the first lint of the workspace, once all annotations are known
+	dataPaths, err := config.FilterIgnoredDataPaths([]string{workspaceRootPath}, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to walk workspace dir %q: %w", workspaceRootPath, err)
+	}
+
+	for _, path := range dataPaths {
+		bs, err := os.ReadFile(path)
 		if err != nil {
-			return fmt.Errorf("failed to update cache for uri %q: %w", path, err)
+			l.logError(fmt.Errorf("failed to read data file %q: %w", path, err))
+
+			continue
 		}
 
-		_, err = updateParse(l.cache, fileURI)
-		if err != nil {
-			return fmt.Errorf("failed to update parse: %w", err)
+		l.cache.DataFiles.Set(uri.FromPath(l.clientIdentifier, path), string(bs))
+	}
+
+	// the first error encountered, if any
+	return <-errs
+}
+
+// loadWorkspaceFile loads a file of the workspace into the cache, recovering from any panic doing so, for one bad file
+// not to take down the server while loading the workspace. Files unchanged since the index was saved aren't parsed,
+// as their symbols and tests are restored from the index.
+func (l *LanguageServer) loadWorkspaceFile(path string, index *cache.Index) (err error) {
+	fileURI := uri.FromPath(l.clientIdentifier, path)
+
+	defer func() {
+		if r := recover(); r != nil {
+			l.reportPanic("load workspace file", fileURI, r)
+
+			err = fmt.Errorf("failed to load %q: panic: %v", path, r)
 		}
+	}()
+
+	_, err = cache.UpdateCacheForURIFromDisk(l.cache, fileURI, path)
+	if err != nil {
+		return fmt.Errorf("failed to update cache for uri %q: %w", path, err)
+	}
 
+	if l.cache.RestoreParsed(index, version.Version, fileURI) {
 		return nil
-	})
+	}
+
+	success, err := updateParse(l.cache, fileURI)
 	if err != nil {
-		return fmt.Errorf("failed to walk workspace dir %q: %w", workspaceRootPath, err)
+		return fmt.Errorf("failed to update parse: %w", err)
+	}
+
+	if success {
+		updateTests(l.cache, fileURI)
 	}
 
 	return nil
 }
 
+// sendProgress reports work done progress to the client, if the client provided a token to report progress on.
+func (l *LanguageServer) sendProgress(ctx context.Context, conn *jsonrpc2.Conn, token any, value any) {
+	if token == nil || conn == nil {
+		return
+	}
+
+	if err := conn.Notify(ctx, "$/progress", types.ProgressParams{Token: token, Value: value}); err != nil {
+		l.logError(fmt.Errorf("failed to notify client of progress: %w", err))
+	}
+}
+
 func (l *LanguageServer) handleInitialized(
-	_ context.Context,
+	ctx context.Context,
 	_ *jsonrpc2.Conn,
 	_ *jsonrpc2.Request,
 ) (result any, err error) {
+	// show diagnostics restored from the index right away, as linting the workspace may take a while
+	if l.indexRestored {
+		for fileURI := range l.cache.GetAllFiles() {
+			if err := l.sendFileDiagnostics(ctx, fileURI); err != nil {
+				l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
+			}
+		}
+	}
+
 	// if running without config, then we should send the diagnostic request now
 	// otherwise it'll happen when the config is loaded
 	if !l.configWatcher.IsWatching() {
@@ -1300,6 +2372,12 @@ func (l *LanguageServer) handleWorkspaceDidChangeWatchedFiles(
 			continue
 		}
 
+		if config.IsDataFile(change.URI) {
+			l.diagnosticRequestFile <- l.dataFileChangeEvent(change)
+
+			continue
+		}
+
 		regoFiles = append(regoFiles, change.URI)
 	}
 
@@ -1311,6 +2389,51 @@ func (l *LanguageServer) handleWorkspaceDidChangeWatchedFiles(
 	return struct{}{}, nil
 }
 
+// processDataFileUpdate validates the contents of a data file, sent by the client or read from disk, and sends the
+// diagnostics of the file.
+func (l *LanguageServer) processDataFileUpdate(ctx context.Context, evt fileUpdateEvent) {
+	if evt.Reason == "textDocument/didDelete" {
+		l.cache.DataFiles.Delete(evt.URI)
+		l.cache.DataFileDiagnostics.Delete(evt.URI)
+	} else {
+		l.cache.DataFiles.Set(evt.URI, evt.Content)
+		l.updateDataFileDiagnostics(evt.URI, evt.Content)
+	}
+
+	if err := l.sendFileDiagnostics(ctx, evt.URI); err != nil {
+		l.logError(fmt.Errorf("failed to send diagnostic: %w", err))
+	}
+}
+
+// updateDataFileDiagnostics validates the contents of a data file against the schemas declared for its data in
+// the annotations of the workspace, unless schema validation is disabled, in which case only syntax is checked.
+func (l *LanguageServer) updateDataFileDiagnostics(fileURI, contents string) {
+	rootPath := uri.ToPath(l.clientIdentifier, l.clientRootURI)
+	dataPath := dataPathOf(uri.ToPath(l.clientIdentifier, fileURI), rootPath)
+
+	var schemas []dataSchema
+	if featureEnabled(l.initializationOptions.SchemaValidation) {
+		schemas = dataSchemas(allModules(l.cache), rootPath)
+	}
+
+	l.cache.DataFileDiagnostics.Set(fileURI, dataFileDiagnostics(fileURI, contents, dataPath, schemas))
+}
+
+// dataFileChangeEvent returns the update event of a data file changed on disk.
+func (l *LanguageServer) dataFileChangeEvent(change types.FileEvent) fileUpdateEvent {
+	evt := fileUpdateEvent{Reason: "workspace/didChangeWatchedFiles", URI: change.URI}
+
+	bs, err := os.ReadFile(uri.ToPath(l.clientIdentifier, change.URI))
+	if err != nil {
+		// the file was deleted, or can't be read, which to the client means the same
+		evt.Reason = "textDocument/didDelete"
+	}
+
+	evt.Content = string(bs)
+
+	return evt
+}
+
 func (l *LanguageServer) sendFileDiagnostics(ctx context.Context, uri string) error {
 	resp := types.FileDiagnostics{
 		Items: l.cache.GetAllDiagnosticsForURI(uri),
@@ -1325,6 +2448,21 @@ func (l *LanguageServer) sendFileDiagnostics(ctx context.Context, uri string) er
 	return nil
 }
 
+// sendTestsDiscovered notifies the client of the tests found in the file at uri, provided
+// that the client has opted in to receive such notifications.
+func (l *LanguageServer) sendTestsDiscovered(ctx context.Context, uri string, tests []types.TestItem) error {
+	if !l.testDiscovery {
+		return nil
+	}
+
+	err := l.conn.Notify(ctx, methodRegalTestsDiscovered, types.TestsDiscoveredParams{URI: uri, Tests: tests})
+	if err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+
+	return nil
+}
+
 func (l *LanguageServer) getFilteredModules() (map[string]*ast.Module, error) {
 	ignore := make([]string, 0)
 
@@ -1332,8 +2470,8 @@ func (l *LanguageServer) getFilteredModules() (map[string]*ast.Module, error) {
 		ignore = l.loadedConfig.Ignore.Files
 	}
 
-	allModules := l.cache.GetAllModules()
-	paths := util.Keys(allModules)
+	all := allModules(l.cache)
+	paths := util.Keys(all)
 
 	filtered, err := config.FilterIgnoredPaths(paths, ignore, false, l.clientRootURI)
 	if err != nil {
@@ -1342,12 +2480,18 @@ func (l *LanguageServer) getFilteredModules() (map[string]*ast.Module, error) {
 
 	modules := make(map[string]*ast.Module, len(filtered))
 	for _, path := range filtered {
-		modules[path] = allModules[path]
+		modules[path] = all[path]
 	}
 
 	return modules, nil
 }
 
+// featureEnabled returns whether a feature toggled in the initialization options is enabled,
+// which is the case unless it has been explicitly disabled.
+func featureEnabled(toggle *bool) bool {
+	return toggle == nil || *toggle
+}
+
 func positionToOffset(text string, p types.Position) int {
 	bytesRead := 0
 	lines := strings.Split(text, "\n")
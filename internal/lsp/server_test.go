@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +18,7 @@ import (
 
 	"github.com/styrainc/regal/internal/lsp/cache"
 	"github.com/styrainc/regal/internal/lsp/types"
+	"github.com/styrainc/regal/internal/metrics"
 )
 
 const mainRegoFileName = "/main.rego"
@@ -186,7 +190,7 @@ allow = true
 	// 3. Client sends textDocument/didChange notification with new contents for main.rego
 	// no response to the call is expected
 	err = connClient.Call(ctx, "textDocument/didChange", types.TextDocumentDidChangeParams{
-		TextDocument: types.TextDocumentIdentifier{
+		TextDocument: types.VersionedTextDocumentIdentifier{
 			URI: mainRegoURI,
 		},
 		ContentChanges: []types.TextDocumentContentChangeEvent{
@@ -450,7 +454,7 @@ ignore:
 	// 3. Client sends textDocument/didChange notification with new contents for authz.rego
 	// no response to the call is expected
 	err = connClient.Call(ctx, "textDocument/didChange", types.TextDocumentDidChangeParams{
-		TextDocument: types.TextDocumentIdentifier{
+		TextDocument: types.VersionedTextDocumentIdentifier{
 			URI: authzRegoURI,
 		},
 		ContentChanges: []types.TextDocumentContentChangeEvent{
@@ -518,7 +522,7 @@ func TestProcessBuiltinUpdateExitsOnMissingFile(t *testing.T) {
 		cache: cache.NewCache(),
 	}
 
-	err := ls.processBuiltinsUpdate(context.Background(), "file://missing.rego", "foo")
+	err := ls.processBuiltinsUpdate(context.Background(), "file://missing.rego", "foo", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -758,3 +762,287 @@ func createConnections(
 
 	return connServer, connClient, cleanup
 }
+
+// TestLanguageServerInitializationOptions tests that features disabled in the initialization
+// options are neither announced in the server capabilities, nor provided when requested.
+func TestLanguageServerInitializationOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr})
+
+	clientHandler := func(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+		t.Fatalf("unexpected request: %v", req)
+
+		return struct{}{}, nil
+	}
+
+	connServer, connClient, cleanup := createConnections(ctx, ls.Handle, clientHandler)
+	defer cleanup()
+
+	ls.SetConn(connServer)
+
+	disabled := false
+
+	request := types.InitializeParams{
+		ClientInfo: types.Client{Name: "go test"},
+		InitializationOptions: types.InitializationOptions{
+			Formatting: &disabled,
+			InlayHints: &disabled,
+		},
+	}
+
+	var response types.InitializeResult
+
+	if err := connClient.Call(ctx, "initialize", request, &response); err != nil {
+		t.Fatalf("failed to send initialize request: %s", err)
+	}
+
+	if response.Capabilities.DocumentFormattingProvider {
+		t.Errorf("expected formatting provider to be disabled")
+	}
+
+	if response.Capabilities.DocumentOnTypeFormattingProvider != nil {
+		t.Errorf("expected on-type formatting provider to be disabled")
+	}
+
+	if response.Capabilities.InlayHintProvider != nil {
+		t.Errorf("expected inlay hint provider to be disabled")
+	}
+
+	if response.Capabilities.CompletionProvider == nil {
+		t.Errorf("expected completion provider to be enabled")
+	}
+
+	ls.cache.SetFileContents("file:///p.rego", "package p\n\nallow if {\n\n}\n")
+
+	var edits []types.TextEdit
+
+	err := connClient.Call(ctx, "textDocument/formatting", types.DocumentFormattingParams{
+		TextDocument: types.TextDocumentIdentifier{URI: "file:///p.rego"},
+	}, &edits)
+	if err != nil {
+		t.Fatalf("failed to send formatting request: %s", err)
+	}
+
+	if len(edits) != 0 {
+		t.Errorf("expected no formatting edits when formatting is disabled, got %v", edits)
+	}
+}
+
+func TestLoadWorkspaceContents(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+
+	for i := range 50 {
+		contents := fmt.Sprintf("package p%d\n\nimport rego.v1\n\ntest_allow if true\n", i)
+		if i == 0 {
+			contents = "package"
+		}
+
+		path := filepath.Join(tempDir, fmt.Sprintf("dir%d", i%5), fmt.Sprintf("p%d.rego", i))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %s", err)
+		}
+
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatalf("failed to write file %s: %s", path, err)
+		}
+	}
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr})
+	ls.clientRootURI = fileURIScheme + tempDir
+
+	if err := ls.loadWorkspaceContents(context.Background(), nil, nil, nil); err != nil {
+		t.Fatalf("failed to load workspace contents: %s", err)
+	}
+
+	if n := len(ls.cache.GetAllFiles()); n != 50 {
+		t.Errorf("expected 50 files, got %d", n)
+	}
+
+	if n := len(ls.cache.GetAllModules()); n != 49 {
+		t.Errorf("expected 49 parsed modules, got %d", n)
+	}
+
+	if n := len(ls.cache.GetAllTests()); n != 49 {
+		t.Errorf("expected tests of 49 files, got %d", n)
+	}
+
+	parseErrors, _ := ls.cache.GetParseErrors(fileURIScheme + filepath.Join(tempDir, "dir0", "p0.rego"))
+	if len(parseErrors) == 0 {
+		t.Errorf("expected parse errors for invalid file")
+	}
+}
+
+func TestLanguageServerDataFiles(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"main.rego": `# METADATA
+# schemas:
+#   - data.acl.users: {"type": "array", "items": {"type": "string"}}
+package main
+
+import rego.v1
+
+allow if "alice" in data.acl.users
+`,
+		"acl/data.json":    `{"users": ["alice", 1]}`,
+		"broken/data.yaml": "users:\n\t- alice\n",
+	}
+
+	for f, fc := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(tempDir, f)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(tempDir, f), []byte(fc), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	aclURI := fileURIScheme + tempDir + "/acl/data.json"
+	brokenURI := fileURIScheme + tempDir + "/broken/data.yaml"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr})
+	go ls.StartDiagnosticsWorker(ctx)
+
+	receivedMessages := make(chan types.FileDiagnostics, 20)
+	clientHandler := func(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+		if req.Method == methodTextDocumentPublishDiagnostics {
+			var requestData types.FileDiagnostics
+			if err := json.Unmarshal(*req.Params, &requestData); err != nil {
+				t.Fatalf("failed to unmarshal diagnostics: %s", err)
+			}
+
+			receivedMessages <- requestData
+		}
+
+		return struct{}{}, nil
+	}
+
+	connServer, connClient, cleanup := createConnections(ctx, ls.Handle, clientHandler)
+	defer cleanup()
+
+	ls.SetConn(connServer)
+
+	request := types.InitializeParams{
+		RootURI:    fileURIScheme + tempDir,
+		ClientInfo: types.Client{Name: "go test"},
+	}
+
+	var response types.InitializeResult
+	if err := connClient.Call(ctx, "initialize", request, &response); err != nil {
+		t.Fatalf("failed to send initialize request: %s", err)
+	}
+
+	if err := connClient.Call(ctx, "initialized", struct{}{}, nil); err != nil {
+		t.Fatalf("failed to send initialized notification: %s", err)
+	}
+
+	expected := map[string][]string{
+		aclURI:    {codeDataSchemaViolation},
+		brokenURI: {codeDataParseError},
+	}
+
+	timeout := time.NewTimer(defaultTimeout)
+	defer timeout.Stop()
+
+	for len(expected) > 0 {
+		select {
+		case requestData := <-receivedMessages:
+			if codes, ok := expected[requestData.URI]; ok && testRequestDataCodes(t, requestData, requestData.URI, codes) {
+				delete(expected, requestData.URI)
+			}
+		case <-timeout.C:
+			t.Fatalf("timed out waiting for data file diagnostics, still expecting %v", expected)
+		}
+	}
+
+	// the client fixes the data file
+	if err := connClient.Call(ctx, "textDocument/didChange", types.TextDocumentDidChangeParams{
+		TextDocument:   types.VersionedTextDocumentIdentifier{URI: aclURI, Version: 1},
+		ContentChanges: []types.TextDocumentContentChangeEvent{{Text: `{"users": ["alice", "bob"]}`}},
+	}, nil); err != nil {
+		t.Fatalf("failed to send didChange notification: %s", err)
+	}
+
+	timeout.Reset(defaultTimeout)
+
+	for {
+		select {
+		case requestData := <-receivedMessages:
+			if requestData.URI == aclURI && testRequestDataCodes(t, requestData, aclURI, []string{}) {
+				return
+			}
+		case <-timeout.C:
+			t.Fatalf("timed out waiting for data file diagnostics to be cleared")
+		}
+	}
+}
+
+func TestLanguageServerLintMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prometheus := metrics.NewPrometheus()
+
+	ls := NewLanguageServer(&LanguageServerOptions{ErrorLog: os.Stderr, Metrics: prometheus})
+	go ls.StartDiagnosticsWorker(ctx)
+
+	published := make(chan types.FileDiagnostics, defaultBufferedChannelSize)
+	clientHandler := func(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
+		if req.Method == methodTextDocumentPublishDiagnostics {
+			var diags types.FileDiagnostics
+			if err := json.Unmarshal(*req.Params, &diags); err == nil {
+				published <- diags
+			}
+		}
+
+		return struct{}{}, nil
+	}
+
+	connServer, connClient, cleanup := createConnections(ctx, ls.Handle, clientHandler)
+	defer cleanup()
+
+	ls.SetConn(connServer)
+
+	if err := connClient.Notify(ctx, "textDocument/didOpen", types.TextDocumentDidOpenParams{
+		TextDocument: types.TextDocumentItem{
+			URI:  fileURIScheme + "/p.rego",
+			Text: "package p\n\nimport rego.v1\n\ncamelCase := true\n",
+		},
+	}); err != nil {
+		t.Fatalf("failed to send didOpen notification: %s", err)
+	}
+
+	select {
+	case <-published:
+	case <-time.After(defaultTimeout):
+		t.Fatal("timed out waiting for diagnostics")
+	}
+
+	rec := httptest.NewRecorder()
+	prometheus.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, expected := range []string{
+		"regal_files_linted_total 1",
+		`regal_violations_total{category="style",title="prefer-snake-case"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), expected) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", expected, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/styrainc/regal/internal/builtindb"
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+// openCall is a bracket opened before the position of a signature help request.
+type openCall struct {
+	bracket byte
+	name    string
+	commas  uint
+}
+
+// getSignatureHelp returns the signature of the built-in function called at the position, with the argument at the
+// position as the active parameter. Only calls on the line of the position are considered.
+func getSignatureHelp(contents string, position types.Position) (*types.SignatureHelp, bool) {
+	lines := strings.Split(contents, "\n")
+	if position.Line >= uint(len(lines)) {
+		return nil, false
+	}
+
+	line := lines[position.Line]
+	if position.Character > uint(len(line)) {
+		return nil, false
+	}
+
+	call, ok := innermostCall(line[:position.Character])
+	if !ok {
+		return nil, false
+	}
+
+	builtin, ok := builtindb.Default().Get(call.name)
+	if !ok || builtin.Infix != "" {
+		return nil, false
+	}
+
+	signature := types.SignatureInformation{
+		Parameters: make([]types.ParameterInformation, 0, len(builtin.Args)),
+	}
+
+	if builtin.Description != "" {
+		signature.Documentation = &types.MarkupContent{Kind: "markdown", Value: builtin.Description}
+	}
+
+	label := &strings.Builder{}
+
+	label.WriteString(builtin.Name)
+	label.WriteString("(")
+
+	for i, arg := range builtin.Args {
+		if i > 0 {
+			label.WriteString(", ")
+		}
+
+		start := uint(label.Len())
+
+		if arg.Name != "" {
+			label.WriteString(arg.Name)
+		} else {
+			label.WriteString(arg.Type)
+		}
+
+		signature.Parameters = append(signature.Parameters, types.ParameterInformation{
+			Label: [2]uint{start, uint(label.Len())},
+			Documentation: &types.MarkupContent{
+				Kind:  "markdown",
+				Value: createInlayTooltip(arg),
+			},
+		})
+	}
+
+	label.WriteString(")")
+
+	signature.Label = label.String()
+
+	return &types.SignatureHelp{
+		Signatures:      []types.SignatureInformation{signature},
+		ActiveParameter: call.commas,
+	}, true
+}
+
+// innermostCall returns the innermost call left open in the text, and the number of its arguments before the end of
+// the text. Brackets and commas in strings are not counted.
+func innermostCall(text string) (openCall, bool) {
+	stack := make([]openCall, 0)
+
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '"':
+			for i++; i < len(text) && text[i] != '"'; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+			}
+		case '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(text)
+			}
+		case '#':
+			// the rest of the line is a comment
+			i = len(text)
+		case '(', '[', '{':
+			stack = append(stack, openCall{bracket: c, name: nameBefore(text[:i])})
+		case ')', ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].commas++
+			}
+		}
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].bracket == '(' {
+			return stack[i], stack[i].name != ""
+		}
+	}
+
+	return openCall{}, false
+}
+
+// nameBefore returns the name, like "graph.reachable", ending the text.
+func nameBefore(text string) string {
+	start := len(text)
+
+	for start > 0 {
+		c := text[start-1]
+		if c != '.' && c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			break
+		}
+
+		start--
+	}
+
+	return text[start:]
+}
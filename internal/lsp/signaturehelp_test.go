@@ -0,0 +1,90 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestGetSignatureHelp(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		line            string
+		expectedLabel   string
+		expectedActive  uint
+		expectSignature bool
+	}{
+		"first argument": {
+			line:            "r := graph.reachable(",
+			expectedLabel:   "graph.reachable(graph, initial)",
+			expectSignature: true,
+		},
+		"second argument": {
+			line:            "r := graph.reachable({}, ",
+			expectedLabel:   "graph.reachable(graph, initial)",
+			expectedActive:  1,
+			expectSignature: true,
+		},
+		"commas in nested terms and strings": {
+			line:            `r := json.filter({"a,(": [1, 2]}, ["a", "b,c"`,
+			expectedLabel:   "json.filter(object, paths)",
+			expectedActive:  1,
+			expectSignature: true,
+		},
+		"innermost call": {
+			line:            `r := count(split("a,b", `,
+			expectedLabel:   "split(x, delimiter)",
+			expectedActive:  1,
+			expectSignature: true,
+		},
+		"outer call after inner call closed": {
+			line:            `r := concat(",", [split("a,b", ",")[0], `,
+			expectedLabel:   "concat(delimiter, collection)",
+			expectedActive:  1,
+			expectSignature: true,
+		},
+		"closed call": {
+			line: `r := count([1, 2])`,
+		},
+		"custom function": {
+			line: `r := my_func(1, `,
+		},
+		"in comment": {
+			line: `# count(`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			contents := "package p\n\n" + tc.line + "\n"
+			position := types.Position{Line: 2, Character: uint(len(tc.line))}
+
+			signatureHelp, ok := getSignatureHelp(contents, position)
+			if ok != tc.expectSignature {
+				t.Fatalf("expected signature %v, got %v", tc.expectSignature, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			signature := signatureHelp.Signatures[0]
+			if signature.Label != tc.expectedLabel {
+				t.Errorf("expected label %s, got %s", tc.expectedLabel, signature.Label)
+			}
+
+			if signatureHelp.ActiveParameter != tc.expectedActive {
+				t.Errorf("expected active parameter %d, got %d", tc.expectedActive, signatureHelp.ActiveParameter)
+			}
+
+			for _, param := range signature.Parameters {
+				if param.Label[0] >= param.Label[1] || param.Label[1] > uint(len(signature.Label)) {
+					t.Errorf("invalid parameter label offsets %v", param.Label)
+				}
+			}
+		})
+	}
+}
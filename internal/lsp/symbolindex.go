@@ -0,0 +1,254 @@
+package lsp
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+// indexSymbols returns the rule definitions, references and imports of the module. References
+// to rules of the same package, imported packages and data are resolved to their full path in
+// the data document, while references to anything else, like local variables, are left out.
+func indexSymbols(module *ast.Module) types.Symbols {
+	symbols := types.Symbols{
+		Definitions: make([]types.Symbol, 0),
+		References:  make([]types.Symbol, 0),
+		Imports:     make([]types.Symbol, 0),
+	}
+
+	if module == nil || module.Package == nil {
+		return symbols
+	}
+
+	rules := make(map[ast.Var]bool, len(module.Rules))
+
+	for _, rule := range module.Rules {
+		if name, ok := rule.Head.Ref()[0].Value.(ast.Var); ok {
+			rules[name] = true
+		}
+	}
+
+	imports := make(map[ast.Var]ast.Ref, len(module.Imports))
+
+	for _, imp := range module.Imports {
+		path, ok := imp.Path.Value.(ast.Ref)
+		if !ok || !path.HasPrefix(ast.DefaultRootRef) {
+			continue
+		}
+
+		imports[imp.Name()] = path
+
+		symbols.Imports = append(symbols.Imports, types.Symbol{
+			Name:  path.String(),
+			Range: termRange(imp.Path.Location),
+		})
+	}
+
+	pkg := module.Package.Path
+
+	resolve := func(ref ast.Ref) (string, bool) {
+		head, ok := ref[0].Value.(ast.Var)
+		if !ok {
+			return "", false
+		}
+
+		var full ast.Ref
+
+		switch {
+		case head.Equal(ast.DefaultRootDocument.Value):
+			full = ref
+		case imports[head] != nil:
+			full = imports[head].Concat(ref[1:])
+		case rules[head]:
+			full = pkg.Append(ast.StringTerm(string(head))).Concat(ref[1:])
+		default:
+			return "", false
+		}
+
+		full = full.GroundPrefix()
+		if len(full) < 2 {
+			return "", false
+		}
+
+		return full.String(), true
+	}
+
+	var visit func(term *ast.Term) bool
+
+	visit = func(term *ast.Term) bool {
+		var ref ast.Ref
+
+		switch v := term.Value.(type) {
+		case ast.Ref:
+			ref = v
+		case ast.Var:
+			ref = ast.Ref{term}
+		default:
+			return false
+		}
+
+		if name, ok := resolve(ref); ok && term.Location != nil {
+			symbols.References = append(symbols.References, types.Symbol{Name: name, Range: termRange(term.Location)})
+		}
+
+		// the head of the ref has been handled, but any refs nested in the rest of it are visited too
+		for _, t := range ref[1:] {
+			ast.WalkTerms(t, visit)
+		}
+
+		return true
+	}
+
+	for _, rule := range module.Rules {
+		symbols.Definitions = append(symbols.Definitions, types.Symbol{
+			Name:  ruleSymbolName(pkg, rule),
+			Range: refRange(rule),
+		})
+
+		for r := rule; r != nil; r = r.Else {
+			ast.WalkTerms(r.Body, visit)
+
+			if r.Head.Key != nil {
+				ast.WalkTerms(r.Head.Key, visit)
+			}
+
+			if r.Head.Value != nil {
+				ast.WalkTerms(r.Head.Value, visit)
+			}
+		}
+	}
+
+	return symbols
+}
+
+// ruleSymbolName returns the full path of the rule in the data document, e.g. `data.authz.allow`, as the rule is
+// named by its symbols.
+func ruleSymbolName(pkg ast.Ref, rule *ast.Rule) string {
+	ref := rule.Head.Ref()
+
+	return pkg.Append(ast.StringTerm(ref[0].Value.(ast.Var).String())).Concat(ref[1:]).GroundPrefix().String()
+}
+
+// refRange returns the range of the ref in the head of the rule, e.g. `allow`, or `users.admins`.
+func refRange(rule *ast.Rule) types.Range {
+	ref := rule.Head.Ref()
+
+	first, last := ref[0].Location, ref[len(ref)-1].Location
+	if first == nil || last == nil {
+		return termRange(rule.Location)
+	}
+
+	end := termRange(last).End
+
+	return types.Range{
+		Start: types.Position{Line: uint(first.Row - 1), Character: uint(first.Col - 1)},
+		End:   end,
+	}
+}
+
+// termRange returns the range of the text at location.
+func termRange(location *ast.Location) types.Range {
+	start := types.Position{Line: uint(location.Row - 1), Character: uint(location.Col - 1)}
+	end := types.Position{Line: start.Line, Character: start.Character + uint(len(location.Text))}
+
+	if n := bytes.Count(location.Text, []byte("\n")); n > 0 {
+		end.Line += uint(n)
+		end.Character = uint(len(location.Text) - bytes.LastIndexByte(location.Text, '\n') - 1)
+	}
+
+	return types.Range{Start: start, End: end}
+}
+
+// symbolAt returns the name of the symbol found at the position in a file, resolved to the name of
+// the rule it refers to when there is a matching definition among the provided symbols of all files.
+func symbolAt(fileSymbols types.Symbols, position types.Position, all map[string]types.Symbols) (string, bool) {
+	var name string
+
+	for _, symbols := range [][]types.Symbol{fileSymbols.Definitions, fileSymbols.References, fileSymbols.Imports} {
+		for _, symbol := range symbols {
+			if contains(symbol.Range, position) {
+				name = symbol.Name
+
+				break
+			}
+		}
+
+		if name != "" {
+			break
+		}
+	}
+
+	if name == "" {
+		return "", false
+	}
+
+	// a reference like data.users.admins.alice points to the definition of data.users.admins
+	longest := ""
+
+	for _, symbols := range all {
+		for _, definition := range symbols.Definitions {
+			if refersTo(name, definition.Name) && len(definition.Name) > len(longest) {
+				longest = definition.Name
+			}
+		}
+	}
+
+	if longest != "" {
+		return longest, true
+	}
+
+	return name, true
+}
+
+// findReferences returns the locations of all references to, and optionally the definitions of, name.
+func findReferences(all map[string]types.Symbols, name string, includeDeclaration bool) []types.Location {
+	locations := make([]types.Location, 0)
+
+	for uri, symbols := range all {
+		if includeDeclaration {
+			for _, definition := range symbols.Definitions {
+				if definition.Name == name {
+					locations = append(locations, types.Location{URI: uri, Range: definition.Range})
+				}
+			}
+		}
+
+		for _, reference := range slices.Concat(symbols.References, symbols.Imports) {
+			if refersTo(reference.Name, name) {
+				locations = append(locations, types.Location{URI: uri, Range: reference.Range})
+			}
+		}
+	}
+
+	slices.SortFunc(locations, func(a, b types.Location) int {
+		if c := strings.Compare(a.URI, b.URI); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(a.Range.Start.Line, b.Range.Start.Line); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Range.Start.Character, b.Range.Start.Character)
+	})
+
+	return locations
+}
+
+// refersTo returns whether the reference, like data.users.admins.alice, refers to the definition
+// with name, like data.users.admins.
+func refersTo(reference, name string) bool {
+	return reference == name || strings.HasPrefix(reference, name+".") || strings.HasPrefix(reference, name+"[")
+}
+
+func contains(r types.Range, p types.Position) bool {
+	afterStart := p.Line > r.Start.Line || (p.Line == r.Start.Line && p.Character >= r.Start.Character)
+	beforeEnd := p.Line < r.End.Line || (p.Line == r.End.Line && p.Character <= r.End.Character)
+
+	return afterStart && beforeEnd
+}
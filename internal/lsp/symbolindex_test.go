@@ -0,0 +1,114 @@
+package lsp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+func TestIndexSymbols(t *testing.T) {
+	t.Parallel()
+
+	symbols := indexSymbols(ast.MustParseModule(`package policy
+
+import data.users as u
+import rego.v1
+
+admins := {name | some name in u.all; u.all[name].admin}
+
+allow if input.user in admins
+
+deny contains msg if {
+	not allow
+	msg := data.policy.messages.denied
+}
+`))
+
+	definitions := make([]string, 0, len(symbols.Definitions))
+	for _, symbol := range symbols.Definitions {
+		definitions = append(definitions, symbol.Name)
+	}
+
+	expectedDefinitions := []string{"data.policy.admins", "data.policy.allow", "data.policy.deny"}
+	if !slices.Equal(definitions, expectedDefinitions) {
+		t.Errorf("expected definitions %v, got %v", expectedDefinitions, definitions)
+	}
+
+	references := make([]string, 0, len(symbols.References))
+	for _, symbol := range symbols.References {
+		references = append(references, symbol.Name)
+	}
+
+	expectedReferences := []string{
+		"data.users.all",
+		"data.users.all",
+		"data.policy.admins",
+		"data.policy.allow",
+		"data.policy.messages.denied",
+	}
+	if !slices.Equal(references, expectedReferences) {
+		t.Errorf("expected references %v, got %v", expectedReferences, references)
+	}
+
+	if len(symbols.Imports) != 1 || symbols.Imports[0].Name != "data.users" {
+		t.Errorf("expected import of data.users, got %v", symbols.Imports)
+	}
+
+	expectedRange := types.Range{
+		Start: types.Position{Line: 7, Character: 23},
+		End:   types.Position{Line: 7, Character: 29},
+	}
+	if symbols.References[2].Range != expectedRange {
+		t.Errorf("expected range %v for reference to admins, got %v", expectedRange, symbols.References[2].Range)
+	}
+}
+
+func TestFindReferences(t *testing.T) {
+	t.Parallel()
+
+	all := map[string]types.Symbols{
+		"file:///users.rego": indexSymbols(ast.MustParseModule("package users\n\nadmins := {\"alice\"}\n")),
+		"file:///policy.rego": indexSymbols(ast.MustParseModule(`package policy
+
+import data.users
+import rego.v1
+
+allow if input.user in users.admins
+
+deny if data.users.admins.bob
+`)),
+	}
+
+	// cursor on `admins` in `users.admins` of the allow rule
+	name, ok := symbolAt(all["file:///policy.rego"], types.Position{Line: 5, Character: 30}, all)
+	if !ok || name != "data.users.admins" {
+		t.Fatalf("expected symbol data.users.admins at position, got %q", name)
+	}
+
+	locations := findReferences(all, name, true)
+
+	expected := []types.Location{
+		{URI: "file:///policy.rego", Range: types.Range{
+			Start: types.Position{Line: 5, Character: 23}, End: types.Position{Line: 5, Character: 35},
+		}},
+		{URI: "file:///policy.rego", Range: types.Range{
+			Start: types.Position{Line: 7, Character: 8}, End: types.Position{Line: 7, Character: 29},
+		}},
+		{URI: "file:///users.rego", Range: types.Range{
+			Start: types.Position{Line: 2, Character: 0}, End: types.Position{Line: 2, Character: 6},
+		}},
+	}
+
+	if len(locations) != len(expected) {
+		t.Fatalf("expected %d locations, got %d: %v", len(expected), len(locations), locations)
+	}
+
+	for i := range expected {
+		if locations[i] != expected[i] {
+			t.Errorf("expected location %v, got %v", expected[i], locations[i])
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"github.com/open-policy-agent/opa/tester"
+
+	"github.com/styrainc/regal/internal/lsp/cache"
+	"github.com/styrainc/regal/internal/lsp/types"
+)
+
+const (
+	methodRegalTestsDiscovered = "regal/testsDiscovered"
+
+	testStatusPass  = "pass"
+	testStatusFail  = "fail"
+	testStatusError = "error"
+	testStatusSkip  = "skip"
+
+	testTimeout = 5 * time.Second
+)
+
+// findTests returns all test rules (i.e. rules prefixed with test_ or todo_test_) in the module.
+func findTests(uri string, module *ast.Module) []types.TestItem {
+	items := make([]types.TestItem, 0)
+
+	if module == nil || module.Package == nil {
+		return items
+	}
+
+	pkg := module.Package.Path.String()
+
+	for _, rule := range module.Rules {
+		name := testRuleName(rule)
+
+		skip := strings.HasPrefix(name, tester.SkipTestPrefix)
+		if !skip && !strings.HasPrefix(name, tester.TestPrefix) {
+			continue
+		}
+
+		items = append(items, types.TestItem{
+			ID:      pkg + "." + name,
+			Name:    name,
+			Package: pkg,
+			URI:     uri,
+			Range:   locationToRange(rule.Location),
+			Skip:    skip,
+		})
+	}
+
+	return items
+}
+
+// testRuleName returns the last part of the rule's ref, which is what OPA uses to determine
+// whether a rule is a test or not.
+func testRuleName(rule *ast.Rule) string {
+	ref := rule.Head.Ref()
+
+	switch last := ref[len(ref)-1].Value.(type) {
+	case ast.Var:
+		return string(last)
+	case ast.String:
+		return string(last)
+	}
+
+	return ""
+}
+
+// updateTests updates the test index for the file at uri from its latest parsed module,
+// and returns the tests found, along with whether they differ from those previously indexed.
+func updateTests(cache *cache.Cache, uri string) ([]types.TestItem, bool) {
+	module, ok := cache.GetModule(uri)
+	if !ok {
+		return nil, false
+	}
+
+	tests := findTests(uri, module)
+
+	oldTests, ok := cache.GetTests(uri)
+
+	cache.SetTests(uri, tests)
+
+	return tests, !ok || !slices.Equal(oldTests, tests)
+}
+
+// runTests runs the tests identified by ids (or all tests, if none provided) found in the
+// provided modules, and returns the results. The modules are copied before compilation, as
+// the compiler would otherwise modify the modules cached by the language server.
+func runTests(ctx context.Context, modules map[string]*ast.Module, ids []string) ([]types.TestResult, error) {
+	toCompile := make(map[string]*ast.Module, len(modules))
+	for uri, module := range modules {
+		toCompile[uri] = module.Copy()
+	}
+
+	store := inmem.NewWithOpts(inmem.OptRoundTripOnWrite(false))
+
+	txn, err := store.NewTransaction(ctx, storage.WriteParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	defer store.Abort(ctx, txn)
+
+	runner := tester.NewRunner().
+		SetStore(store).
+		SetModules(toCompile).
+		CapturePrintOutput(true).
+		SetTimeout(testTimeout)
+
+	if len(ids) > 0 {
+		quoted := make([]string, 0, len(ids))
+		for _, id := range ids {
+			quoted = append(quoted, regexp.QuoteMeta(id))
+		}
+
+		runner = runner.Filter("^(" + strings.Join(quoted, "|") + ")$")
+	}
+
+	ch, err := runner.RunTests(ctx, txn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tests: %w", err)
+	}
+
+	results := make([]types.TestResult, 0)
+
+	for tr := range ch {
+		results = append(results, testResult(tr.Package+"."+tr.Name, tr))
+	}
+
+	return results, nil
+}
+
+func testResult(id string, tr *tester.Result) types.TestResult {
+	result := types.TestResult{
+		ID:       id,
+		Status:   testStatusPass,
+		Duration: tr.Duration.Nanoseconds(),
+		Output:   string(tr.Output),
+	}
+
+	if tr.Location != nil {
+		result.URI = tr.Location.File
+		result.Range = locationToRange(tr.Location)
+	}
+
+	switch {
+	case tr.Skip:
+		result.Status = testStatusSkip
+	case tr.Error != nil:
+		result.Status = testStatusError
+		result.Message = tr.Error.Error()
+	case tr.Fail:
+		result.Status = testStatusFail
+
+		if tr.FailedAt != nil {
+			result.Message = "failed at: " + tr.FailedAt.String()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+package lsp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestFindTests(t *testing.T) {
+	t.Parallel()
+
+	module := parse.MustParseModule(`package p_test
+
+import rego.v1
+
+test_allow if true
+
+todo_test_deny if false
+
+helper := true
+`)
+
+	tests := findTests("file:///p_test.rego", module)
+
+	if len(tests) != 2 {
+		t.Fatalf("expected 2 tests, got %d", len(tests))
+	}
+
+	if tests[0].ID != "data.p_test.test_allow" {
+		t.Errorf("expected ID data.p_test.test_allow, got %s", tests[0].ID)
+	}
+
+	if tests[0].Skip {
+		t.Errorf("expected test_allow not to be skipped")
+	}
+
+	if tests[0].Range.Start.Line != 4 {
+		t.Errorf("expected test_allow to start on line 4, got %d", tests[0].Range.Start.Line)
+	}
+
+	if tests[1].Name != "todo_test_deny" {
+		t.Errorf("expected name todo_test_deny, got %s", tests[1].Name)
+	}
+
+	if !tests[1].Skip {
+		t.Errorf("expected todo_test_deny to be skipped")
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	t.Parallel()
+
+	policy, err := parse.Module("file:///p.rego", `package p
+
+import rego.v1
+
+allow if input.admin
+`)
+	if err != nil {
+		t.Fatalf("failed to parse module: %s", err)
+	}
+
+	policyTest, err := parse.Module("file:///p_test.rego", `package p_test
+
+import rego.v1
+
+import data.p
+
+test_allow if p.allow with input.admin as true
+
+test_deny if p.allow with input.admin as false
+`)
+	if err != nil {
+		t.Fatalf("failed to parse module: %s", err)
+	}
+
+	modules := map[string]*ast.Module{
+		"file:///p.rego":      policy,
+		"file:///p_test.rego": policyTest,
+	}
+
+	results, err := runTests(context.Background(), modules, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	statuses := make(map[string]string, len(results))
+	for _, result := range results {
+		statuses[result.ID] = result.Status
+	}
+
+	if statuses["data.p_test.test_allow"] != testStatusPass {
+		t.Errorf("expected test_allow to pass, got %s", statuses["data.p_test.test_allow"])
+	}
+
+	if statuses["data.p_test.test_deny"] != testStatusFail {
+		t.Errorf("expected test_deny to fail, got %s", statuses["data.p_test.test_deny"])
+	}
+
+	results, err = runTests(context.Background(), modules, []string{"data.p_test.test_allow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(results) != 1 || results[0].ID != "data.p_test.test_allow" {
+		t.Fatalf("expected only test_allow to run, got %v", results)
+	}
+
+	if results[0].URI != "file:///p_test.rego" {
+		t.Errorf("expected result URI to be file:///p_test.rego, got %s", results[0].URI)
+	}
+
+	// the cached modules must not have been modified by compilation
+	if expr := policyTest.Rules[0].Body[0].String(); expr != "p.allow with input.admin as true" {
+		t.Errorf("expected cached module to be left unmodified, got %s", expr)
+	}
+}
@@ -1,10 +1,17 @@
 package types
 
-import "github.com/open-policy-agent/opa/ast"
+import "github.com/styrainc/regal/internal/builtindb"
 
 type BuiltinPosition struct {
-	Builtin *ast.Builtin
+	Builtin *builtindb.Builtin
 	Line    uint
 	Start   uint
 	End     uint
 }
+
+type KeywordPosition struct {
+	Name  string
+	Line  uint
+	Start uint
+	End   uint
+}
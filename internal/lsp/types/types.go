@@ -1,6 +1,10 @@
 package types
 
-import "github.com/styrainc/regal/internal/lsp/types/symbols"
+import (
+	"time"
+
+	"github.com/styrainc/regal/internal/lsp/types/symbols"
+)
 
 type FileDiagnostics struct {
 	URI   string       `json:"uri"`
@@ -25,6 +29,28 @@ type InitializeParams struct {
 	Capabilities     ClientCapabilities `json:"capabilities"`
 	Trace            string             `json:"trace"`
 	WorkspaceFolders []WorkspaceFolder  `json:"workspaceFolders"`
+	// WorkDoneToken is provided by clients that want the server to report progress while initializing
+	WorkDoneToken any `json:"workDoneToken,omitempty"`
+
+	InitializationOptions InitializationOptions `json:"initializationOptions"`
+}
+
+// InitializationOptions allows clients to disable groups of features, which is useful when
+// Regal runs alongside another Rego language server providing the same features. All
+// features are enabled unless explicitly set to false.
+type InitializationOptions struct {
+	Formatting   *bool `json:"formatting,omitempty"`
+	Completion   *bool `json:"completion,omitempty"`
+	CodeLenses   *bool `json:"codeLenses,omitempty"`
+	InlayHints   *bool `json:"inlayHints,omitempty"`
+	EvalCommands *bool `json:"evalCommands,omitempty"`
+	// SchemaValidation toggles the validation of data files against the schemas declared in annotations
+	SchemaValidation *bool `json:"schemaValidation,omitempty"`
+
+	// CacheMaxFiles and CacheMaxBytes bound the number and total size of parsed files kept in memory
+	// for files not open in the client. Zero, or omitted, means no limit.
+	CacheMaxFiles int `json:"cacheMaxFiles,omitempty"`
+	CacheMaxBytes int `json:"cacheMaxBytes,omitempty"`
 }
 
 type WorkspaceFolder struct {
@@ -38,10 +64,18 @@ type Client struct {
 }
 
 type ClientCapabilities struct {
-	Workspace WorkspaceClientCapabilities    `json:"workspace"`
-	Text      TextDocumentClientCapabilities `json:"textDocument"`
-	Window    WindowClientCapabilities       `json:"window"`
-	General   GeneralClientCapabilities      `json:"general"`
+	Workspace    WorkspaceClientCapabilities    `json:"workspace"`
+	Text         TextDocumentClientCapabilities `json:"textDocument"`
+	Window       WindowClientCapabilities       `json:"window"`
+	General      GeneralClientCapabilities      `json:"general"`
+	Experimental ExperimentalClientCapabilities `json:"experimental"`
+}
+
+// ExperimentalClientCapabilities are the capabilities a client may announce for
+// Regal-specific extensions to the protocol.
+type ExperimentalClientCapabilities struct {
+	// TestDiscovery signals that the client wants regal/testsDiscovered notifications.
+	TestDiscovery bool `json:"testDiscovery"`
 }
 
 type WorkspaceClientCapabilities struct {
@@ -87,7 +121,8 @@ type ServerCapabilities struct {
 	TextDocumentSyncOptions    TextDocumentSyncOptions `json:"textDocumentSync"`
 	DiagnosticProvider         DiagnosticOptions       `json:"diagnosticProvider"`
 	Workspace                  WorkspaceOptions        `json:"workspace"`
-	InlayHintProvider          InlayHintOptions        `json:"inlayHintProvider"`
+	InlayHintProvider          *InlayHintOptions       `json:"inlayHintProvider,omitempty"`
+	InlineValueProvider        bool                    `json:"inlineValueProvider"`
 	HoverProvider              bool                    `json:"hoverProvider"`
 	CodeActionProvider         CodeActionOptions       `json:"codeActionProvider"`
 	ExecuteCommandProvider     ExecuteCommandOptions   `json:"executeCommandProvider"`
@@ -96,7 +131,16 @@ type ServerCapabilities struct {
 	DocumentSymbolProvider     bool                    `json:"documentSymbolProvider"`
 	WorkspaceSymbolProvider    bool                    `json:"workspaceSymbolProvider"`
 	DefinitionProvider         bool                    `json:"definitionProvider"`
-	CompletionProvider         CompletionOptions       `json:"completionProvider"`
+	CompletionProvider         *CompletionOptions      `json:"completionProvider,omitempty"`
+	ReferencesProvider         bool                    `json:"referencesProvider"`
+	SignatureHelpProvider      *SignatureHelpOptions   `json:"signatureHelpProvider,omitempty"`
+
+	DocumentOnTypeFormattingProvider *DocumentOnTypeFormattingOptions `json:"documentOnTypeFormattingProvider,omitempty"`
+}
+
+type DocumentOnTypeFormattingOptions struct {
+	FirstTriggerCharacter string   `json:"firstTriggerCharacter"`
+	MoreTriggerCharacter  []string `json:"moreTriggerCharacter,omitempty"`
 }
 
 type CompletionOptions struct {
@@ -208,6 +252,13 @@ type DocumentFormattingParams struct {
 	Options      FormattingOptions      `json:"options"`
 }
 
+type DocumentOnTypeFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	Ch           string                 `json:"ch"`
+	Options      FormattingOptions      `json:"options"`
+}
+
 type DocumentSymbolParams struct {
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
 }
@@ -294,6 +345,25 @@ type TextDocumentInlayHintParams struct {
 	Range        Range                  `json:"range"`
 }
 
+type InlineValueParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      InlineValueContext     `json:"context"`
+}
+
+type InlineValueContext struct {
+	FrameID         int   `json:"frameId"`
+	StoppedLocation Range `json:"stoppedLocation"`
+}
+
+// InlineValueVariableLookup is a variable for the client to look up the value of, in the frame of the debugger, and
+// render inline.
+type InlineValueVariableLookup struct {
+	Range               Range  `json:"range"`
+	VariableName        string `json:"variableName,omitempty"`
+	CaseSensitiveLookup bool   `json:"caseSensitiveLookup"`
+}
+
 type TextDocumentSaveOptions struct {
 	IncludeText bool `json:"includeText"`
 }
@@ -320,8 +390,13 @@ type OptionalVersionedTextDocumentIdentifier struct {
 	Version *uint `json:"version"`
 }
 
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version uint   `json:"version"`
+}
+
 type TextDocumentDidChangeParams struct {
-	TextDocument   TextDocumentIdentifier           `json:"textDocument"`
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
 	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
 }
 
@@ -336,6 +411,8 @@ type Diagnostic struct {
 	Source          string           `json:"source"`
 	Code            string           `json:"code"`
 	CodeDescription *CodeDescription `json:"codeDescription,omitempty"`
+	// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#diagnosticTag
+	Tags []uint `json:"tags,omitempty"`
 }
 
 type CodeDescription struct {
@@ -366,6 +443,10 @@ type TextDocumentDidOpenParams struct {
 	TextDocument TextDocumentItem `json:"textDocument"`
 }
 
+type TextDocumentDidCloseParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
 type TextDocumentItem struct {
 	LanguageID string `json:"languageId"`
 	Text       string `json:"text"`
@@ -383,6 +464,34 @@ type Location struct {
 	Range Range  `json:"range"`
 }
 
+type SignatureHelpOptions struct {
+	TriggerCharacters   []string `json:"triggerCharacters"`
+	RetriggerCharacters []string `json:"retriggerCharacters"`
+}
+
+type SignatureHelpParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type SignatureHelp struct {
+	Signatures      []SignatureInformation `json:"signatures"`
+	ActiveSignature uint                   `json:"activeSignature"`
+	ActiveParameter uint                   `json:"activeParameter"`
+}
+
+type SignatureInformation struct {
+	Label         string                 `json:"label"`
+	Documentation *MarkupContent         `json:"documentation,omitempty"`
+	Parameters    []ParameterInformation `json:"parameters"`
+}
+
+type ParameterInformation struct {
+	// Label is the start and end offset of the parameter in the label of the signature
+	Label         [2]uint        `json:"label"`
+	Documentation *MarkupContent `json:"documentation,omitempty"`
+}
+
 type TextDocumentHoverParams struct {
 	Position     Position               `json:"position"`
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
@@ -423,3 +532,188 @@ type WorkspaceFullDocumentDiagnosticReport struct {
 	Kind    string       `json:"kind"` // full, or incremental. We always use full
 	Items   []Diagnostic `json:"items"`
 }
+
+// TestItem represents a single Rego test rule, as listed by regal/tests
+// and sent in regal/testsDiscovered notifications.
+type TestItem struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	URI     string `json:"uri"`
+	Range   Range  `json:"range"`
+	Skip    bool   `json:"skip"`
+}
+
+type TestsParams struct {
+	// TextDocument is optional, and when omitted, tests for the whole workspace are returned.
+	TextDocument *TextDocumentIdentifier `json:"textDocument,omitempty"`
+}
+
+type TestsDiscoveredParams struct {
+	URI   string     `json:"uri"`
+	Tests []TestItem `json:"tests"`
+}
+
+type RunTestsParams struct {
+	// TextDocument is optional, and when provided, only tests in that file are run.
+	TextDocument *TextDocumentIdentifier `json:"textDocument,omitempty"`
+	// IDs is optional, and when provided, only tests with matching IDs are run.
+	IDs []string `json:"ids,omitempty"`
+}
+
+type TestResult struct {
+	ID       string `json:"id"`
+	URI      string `json:"uri"`
+	Range    Range  `json:"range"`
+	Status   string `json:"status"` // one of pass, fail, error or skip
+	Duration int64  `json:"duration"`
+	Message  string `json:"message,omitempty"`
+	Output   string `json:"output,omitempty"`
+}
+
+// ModuleGraph represents the packages of the workspace, and the imports between them,
+// as returned by regal/moduleGraph.
+type ModuleGraph struct {
+	Nodes []ModuleGraphNode `json:"nodes"`
+	Edges []ModuleGraphEdge `json:"edges"`
+}
+
+type ModuleGraphNode struct {
+	// ID is the path of the package, like data.policy.authz
+	ID    string   `json:"id"`
+	Files []string `json:"files"`
+	// External is set for imported packages not found in the workspace
+	External bool `json:"external"`
+}
+
+type ModuleGraphEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Import string `json:"import"`
+	URI    string `json:"uri"`
+	Range  Range  `json:"range"`
+}
+
+// WorkspaceDiagnosticsSummary counts the diagnostics of all files of the workspace, by severity, category and rule,
+// as returned by regal/workspaceDiagnosticsSummary.
+type WorkspaceDiagnosticsSummary struct {
+	// Total is the number of diagnostics of all files
+	Total int `json:"total"`
+	// Files is the number of files with any diagnostics
+	Files int `json:"files"`
+	// BySeverity counts the diagnostics of each severity, by the name of the severity, like warning
+	BySeverity map[string]int `json:"bySeverity"`
+	// ByCategory counts the diagnostics of each category, like bugs, or parse for parse errors
+	ByCategory map[string]int `json:"byCategory"`
+	// ByRule counts the diagnostics of each rule, the most common first
+	ByRule []RuleDiagnosticsSummary `json:"byRule"`
+}
+
+type RuleDiagnosticsSummary struct {
+	Rule     string `json:"rule"`
+	Category string `json:"category"`
+	Severity string `json:"severity"`
+	Count    int    `json:"count"`
+	// Files is the number of files with diagnostics of the rule
+	Files int `json:"files"`
+}
+
+// Symbols holds the rule definitions, references and imports of a file, as indexed when the
+// file is parsed. Names are full paths in the data document, like data.policy.allow.
+type Symbols struct {
+	Definitions []Symbol `json:"definitions"`
+	References  []Symbol `json:"references"`
+	Imports     []Symbol `json:"imports"`
+}
+
+type Symbol struct {
+	Name  string `json:"name"`
+	Range Range  `json:"range"`
+}
+
+type ReferenceParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	Context      ReferenceContext       `json:"context"`
+}
+
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+type CodeLens struct {
+	Range   Range    `json:"range"`
+	Command *Command `json:"command,omitempty"`
+	Data    any      `json:"data,omitempty"`
+}
+
+type SemanticTokens struct {
+	ResultID string `json:"resultId,omitempty"`
+	Data     []uint `json:"data"`
+}
+
+type ProgressParams struct {
+	Token any `json:"token"`
+	Value any `json:"value"`
+}
+
+type WorkDoneProgressBegin struct {
+	Kind       string `json:"kind"`
+	Title      string `json:"title"`
+	Message    string `json:"message,omitempty"`
+	Percentage uint   `json:"percentage"`
+}
+
+type WorkDoneProgressReport struct {
+	Kind       string `json:"kind"`
+	Message    string `json:"message,omitempty"`
+	Percentage uint   `json:"percentage"`
+}
+
+type WorkDoneProgressEnd struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message,omitempty"`
+}
+
+// ServerStatus describes the health of the language server, as returned by regal/serverStatus.
+type ServerStatus struct {
+	Version string `json:"version"`
+	// Uptime is the time since the server was started, in nanoseconds
+	Uptime int64 `json:"uptime"`
+	// Files is the number of files currently known to the server
+	Files int `json:"files"`
+	// Panics is the number of panics recovered from since the server was started
+	Panics    int          `json:"panics"`
+	LastPanic *PanicReport `json:"lastPanic,omitempty"`
+}
+
+// PanicReport describes a panic recovered from while handling a request, or in a worker of the server, along with
+// the URI of the document concerned, and the digests of the files known to the server, for the state of the workspace
+// to be told apart without including the contents of files.
+type PanicReport struct {
+	Time time.Time `json:"time"`
+	// Source is the method of the request handled, or the name of the worker, when the panic occurred
+	Source string `json:"source"`
+	Panic  string `json:"panic"`
+	// URI is that of the document of the request, or of the file loaded, if any. The params of requests aren't
+	// included, as those of e.g. textDocument/didChange hold the contents of files
+	URI string `json:"uri,omitempty"`
+	// Files are the SHA-256 digests of the contents of the files known to the server, by URI
+	Files map[string]string `json:"files"`
+	Stack string            `json:"stack"`
+}
+
+// InputDocumentParams are the params of regal/inputDocument, for the file the input document is requested for.
+type InputDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// InputDocument is the input document used for evaluating a file, as returned by regal/inputDocument, and by the
+// regal.input.set command.
+type InputDocument struct {
+	// URI is that of the input document, or empty if there is none
+	URI string `json:"uri"`
+	// Source is how the input document was found: "active" when set by the regal.input.set command, "config" when
+	// set in the configuration, "discovered" when found nearest to the file, or "none"
+	Source string `json:"source"`
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import "sync/atomic"
+
+// CacheStats counts the lookups in a cache, by whether an entry was found. Safe for concurrent use.
+type CacheStats struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Record records a lookup in the cache.
+func (s *CacheStats) Record(hit bool) {
+	if hit {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+}
+
+// Hits returns the number of lookups that found an entry in the cache.
+func (s *CacheStats) Hits() uint64 {
+	return s.hits.Load()
+}
+
+// Misses returns the number of lookups that didn't find an entry in the cache.
+func (s *CacheStats) Misses() uint64 {
+	return s.misses.Load()
+}
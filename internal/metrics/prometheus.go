@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/styrainc/regal/pkg/report"
+)
+
+// Prometheus collects metrics of Regal running as a server or language server, for operators to monitor, exposed
+// in the Prometheus format by Handler. All methods are safe to call on a nil *Prometheus, in which case they do
+// nothing, so that callers needn't check whether metrics are enabled.
+type Prometheus struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	lintDuration prometheus.Histogram
+	filesLinted  prometheus.Counter
+	violations   *prometheus.CounterVec
+}
+
+// NewPrometheus creates a new Prometheus collector, including the standard Go runtime and process metrics.
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "regal_requests_total",
+			Help: "Number of requests handled, by API, method and status code.",
+		}, []string{"api", "method", "code"}),
+		lintDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "regal_lint_duration_seconds",
+			Help:    "Time taken to lint the files of a request, or a change in the language server.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		}),
+		filesLinted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "regal_files_linted_total",
+			Help: "Number of files linted.",
+		}),
+		violations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "regal_violations_total",
+			Help: "Number of violations found, by rule.",
+		}, []string{"category", "title"}),
+	}
+
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.requests,
+		p.lintDuration,
+		p.filesLinted,
+		p.violations,
+	)
+
+	return p
+}
+
+// Handler returns the HTTP handler exposing the metrics collected.
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
+
+// RegisterCache adds the hits and misses of a cache to the metrics collected, under the name provided.
+func (p *Prometheus) RegisterCache(name string, stats *CacheStats) {
+	if p == nil {
+		return
+	}
+
+	for result, count := range map[string]func() uint64{"hit": stats.Hits, "miss": stats.Misses} {
+		p.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "regal_cache_lookups_total",
+			Help:        "Number of lookups in caches, by cache and whether an entry was found.",
+			ConstLabels: prometheus.Labels{"cache": name, "result": result},
+		}, func() float64 {
+			return float64(count())
+		}))
+	}
+}
+
+// ObserveRequest records a request handled by the API, e.g. http, grpc or lsp, with the status code of the response.
+func (p *Prometheus) ObserveRequest(api, method, code string) {
+	if p == nil {
+		return
+	}
+
+	p.requests.WithLabelValues(api, method, code).Inc()
+}
+
+// ObserveLint records the time taken to lint, and the files linted and violations found, if a report is provided.
+func (p *Prometheus) ObserveLint(duration time.Duration, rep *report.Report) {
+	if p == nil {
+		return
+	}
+
+	p.lintDuration.Observe(duration.Seconds())
+
+	if rep == nil {
+		return
+	}
+
+	p.filesLinted.Add(float64(rep.Summary.FilesScanned))
+
+	for _, violation := range rep.Violations {
+		p.violations.WithLabelValues(violation.Category, violation.Title).Inc()
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/styrainc/regal/pkg/report"
+)
+
+func TestPrometheus(t *testing.T) {
+	t.Parallel()
+
+	p := NewPrometheus()
+
+	var stats CacheStats
+
+	stats.Record(true)
+	stats.Record(true)
+	stats.Record(false)
+
+	p.RegisterCache("test", &stats)
+	p.ObserveRequest("http", "/v1/lint", "200")
+	p.ObserveLint(50*time.Millisecond, &report.Report{
+		Summary: report.Summary{FilesScanned: 2},
+		Violations: []report.Violation{
+			{Category: "style", Title: "prefer-snake-case"},
+			{Category: "style", Title: "prefer-snake-case"},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`regal_requests_total{api="http",code="200",method="/v1/lint"} 1`,
+		`regal_lint_duration_seconds_count 1`,
+		`regal_files_linted_total 2`,
+		`regal_violations_total{category="style",title="prefer-snake-case"} 2`,
+		`regal_cache_lookups_total{cache="test",result="hit"} 2`,
+		`regal_cache_lookups_total{cache="test",result="miss"} 1`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", expected, body)
+		}
+	}
+}
+
+func TestPrometheusNil(t *testing.T) {
+	t.Parallel()
+
+	var p *Prometheus
+
+	// should not panic when metrics are disabled
+	p.RegisterCache("test", &CacheStats{})
+	p.ObserveRequest("http", "/v1/lint", "200")
+	p.ObserveLint(time.Second, &report.Report{})
+}
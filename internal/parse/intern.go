@@ -0,0 +1,318 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// only strings up to this length are interned, which covers all object keys, term types, and most names
+	// found in policy, like those of variables, rules and built-in functions, while leaving out the text of
+	// locations, comments and other longer strings unlikely to be repeated.
+	maxInternedStringLength = 32
+
+	// the number of strings interned is capped, as the language server may run for a long time, and see many
+	// different names.
+	maxInternedStrings = 1 << 16
+
+	// whole numbers below this are interned, which covers the row and column of nearly all locations.
+	maxInternedNumber = 1 << 12
+)
+
+// internedString holds an interned string, along with the same string boxed in an interface value, so that
+// decoding a string value doesn't need to allocate either.
+type internedString struct {
+	str   string
+	boxed any
+}
+
+//nolint:gochecknoglobals
+var (
+	internedStrings   = make(map[string]internedString)
+	internedStringsMu sync.RWMutex
+
+	internedNumbers = sync.OnceValue(func() []any {
+		numbers := make([]any, maxInternedNumber)
+		for i := range numbers {
+			numbers[i] = float64(i)
+		}
+
+		return numbers
+	})
+)
+
+// decodeInterned decodes JSON like json.Unmarshal does into an any, but with object keys, short strings and
+// small whole numbers interned, i.e. shared between all values decoded. The prepared input of thousands of
+// files mostly consists of the same keys, types and locations, so this reduces both the memory needed to hold
+// it, and the number of allocations the GC needs to deal with.
+func decodeInterned(bs []byte) (any, error) {
+	d := decoder{data: bs}
+
+	value, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.skipWhitespace(); d.pos != len(d.data) {
+		return nil, d.errorf("unexpected data after JSON value")
+	}
+
+	return value, nil
+}
+
+// decoder decodes JSON from a byte slice. As opposed to encoding/json, strings without escape sequences (i.e.
+// almost all of them) are looked up in the table of interned strings without first being copied.
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) value() (any, error) {
+	d.skipWhitespace()
+
+	if d.pos >= len(d.data) {
+		return nil, d.errorf("unexpected end of JSON input")
+	}
+
+	switch c := d.data[d.pos]; {
+	case c == '{':
+		return d.object()
+	case c == '[':
+		return d.array()
+	case c == '"':
+		bs, escaped, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+
+		if escaped {
+			return unescape(bs)
+		}
+
+		return lookupInterned(bs).boxed, nil
+	case c == 't':
+		return true, d.literal("true")
+	case c == 'f':
+		return false, d.literal("false")
+	case c == 'n':
+		return nil, d.literal("null")
+	default:
+		return d.number()
+	}
+}
+
+func (d *decoder) object() (map[string]any, error) {
+	object := make(map[string]any)
+
+	d.pos++ // {
+
+	if d.skipWhitespace(); d.pos < len(d.data) && d.data[d.pos] == '}' {
+		d.pos++
+
+		return object, nil
+	}
+
+	for {
+		if d.skipWhitespace(); d.pos >= len(d.data) || d.data[d.pos] != '"' {
+			return nil, d.errorf("expected object key")
+		}
+
+		bs, escaped, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+
+		var key string
+
+		if escaped {
+			unescaped, err := unescape(bs)
+			if err != nil {
+				return nil, err
+			}
+
+			key = unescaped.(string) //nolint:forcetypeassert
+		} else {
+			key = lookupInterned(bs).str
+		}
+
+		if d.skipWhitespace(); d.pos >= len(d.data) || d.data[d.pos] != ':' {
+			return nil, d.errorf("expected colon after object key")
+		}
+
+		d.pos++
+
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		object[key] = value
+
+		if done, err := d.next('}'); err != nil || done {
+			return object, err
+		}
+	}
+}
+
+func (d *decoder) array() ([]any, error) {
+	array := make([]any, 0)
+
+	d.pos++ // [
+
+	if d.skipWhitespace(); d.pos < len(d.data) && d.data[d.pos] == ']' {
+		d.pos++
+
+		return array, nil
+	}
+
+	for {
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		array = append(array, value)
+
+		if done, err := d.next(']'); err != nil || done {
+			return array, err
+		}
+	}
+}
+
+// next consumes the comma separating elements of an object or array, or the closing delimiter, in which case
+// done is true.
+func (d *decoder) next(closing byte) (done bool, err error) {
+	if d.skipWhitespace(); d.pos >= len(d.data) {
+		return false, d.errorf("unexpected end of JSON input")
+	}
+
+	switch d.data[d.pos] {
+	case ',':
+		d.pos++
+
+		return false, nil
+	case closing:
+		d.pos++
+
+		return true, nil
+	default:
+		return false, d.errorf("expected comma or %c", closing)
+	}
+}
+
+// string returns the raw string at the current position, including the quotes, and whether it contains any
+// escape sequences.
+func (d *decoder) string() (raw []byte, escaped bool, err error) {
+	start := d.pos
+
+	for d.pos++; d.pos < len(d.data); d.pos++ {
+		switch d.data[d.pos] {
+		case '\\':
+			escaped = true
+			d.pos++
+		case '"':
+			d.pos++
+
+			if escaped {
+				return d.data[start:d.pos], true, nil
+			}
+
+			return d.data[start+1 : d.pos-1], false, nil
+		}
+	}
+
+	return nil, false, d.errorf("unterminated string")
+}
+
+func (d *decoder) number() (any, error) {
+	start := d.pos
+
+	for d.pos < len(d.data) && strings.IndexByte("+-.0123456789eE", d.data[d.pos]) != -1 {
+		d.pos++
+	}
+
+	f, err := strconv.ParseFloat(string(d.data[start:d.pos]), 64)
+	if err != nil {
+		return nil, d.errorf("invalid number")
+	}
+
+	if f >= 0 && f < maxInternedNumber && f == math.Trunc(f) {
+		return internedNumbers()[int(f)], nil
+	}
+
+	return f, nil
+}
+
+func (d *decoder) literal(literal string) error {
+	if !bytes.HasPrefix(d.data[d.pos:], []byte(literal)) {
+		return d.errorf("invalid literal")
+	}
+
+	d.pos += len(literal)
+
+	return nil
+}
+
+func (d *decoder) skipWhitespace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *decoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("failed to decode JSON: %s at offset %d", fmt.Sprintf(format, args...), d.pos)
+}
+
+// unescape decodes a quoted string containing escape sequences, which are few enough to leave to encoding/json.
+func unescape(quoted []byte) (any, error) {
+	var s string
+	if err := json.Unmarshal(quoted, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON string: %w", err)
+	}
+
+	return s, nil
+}
+
+// lookupInterned returns the interned string equal to bs, interning it first if not already done, unless it's
+// too long, or too many strings are interned already.
+func lookupInterned(bs []byte) internedString {
+	if len(bs) > maxInternedStringLength {
+		s := string(bs)
+
+		return internedString{str: s, boxed: s}
+	}
+
+	internedStringsMu.RLock()
+	interned, ok := internedStrings[string(bs)]
+	internedStringsMu.RUnlock()
+
+	if ok {
+		return interned
+	}
+
+	internedStringsMu.Lock()
+	defer internedStringsMu.Unlock()
+
+	if interned, ok = internedStrings[string(bs)]; ok {
+		return interned
+	}
+
+	s := string(bs)
+	interned = internedString{str: s, boxed: s}
+
+	if len(internedStrings) < maxInternedStrings {
+		internedStrings[s] = interned
+	}
+
+	return interned
+}
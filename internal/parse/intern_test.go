@@ -0,0 +1,81 @@
+package parse
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/styrainc/regal/internal/testutil"
+)
+
+func TestDecodeInternedMatchesUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	module := MustParseModule(`package p
+
+import rego.v1
+
+# METADATA
+# description: text with <escaped> &characters, and unicode ✓
+allow if {
+	input.users[_].age >= -1.5e3
+	count(input.roles) == 0
+	not input.admin == null
+	input.flags == [true, false, {}, []]
+}
+`)
+
+	for name, bs := range map[string][]byte{
+		"module":  testutil.Must(json.Marshal(module))(t),
+		"escapes": []byte(` {"a\"b": ["å\n", "\\", 1, 2.5, -0, 1e2, 4096, null]} `),
+		"empty":   []byte(`{"object": {}, "array": []}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var expected any
+			if err := json.Unmarshal(bs, &expected); err != nil {
+				t.Fatal(err)
+			}
+
+			decoded := testutil.Must(decodeInterned(bs))(t)
+
+			if !reflect.DeepEqual(expected, decoded) {
+				t.Errorf("expected %v, got %v", expected, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeInternedSharesStrings(t *testing.T) {
+	t.Parallel()
+
+	first := testutil.Must(decodeInterned([]byte(`{"type": "var", "row": 12}`)))(t).(map[string]any)
+	second := testutil.Must(decodeInterned([]byte(`{"type": "var", "row": 12}`)))(t).(map[string]any)
+
+	firstVar, _ := first["type"].(string)
+	secondVar, _ := second["type"].(string)
+
+	if unsafe.StringData(firstVar) != unsafe.StringData(secondVar) {
+		t.Errorf("expected equal string values to share memory")
+	}
+
+	for key := range first {
+		for other := range second {
+			if key == other && unsafe.StringData(key) != unsafe.StringData(other) {
+				t.Errorf("expected equal keys to share memory")
+			}
+		}
+	}
+}
+
+func TestDecodeInternedInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, invalid := range []string{``, `{`, `{"a" 1}`, `[1,]`, `"unterminated`, `tru`, `{} {}`, `[1 2]`} {
+		if _, err := decodeInterned([]byte(invalid)); err == nil {
+			t.Errorf("expected error decoding %q", invalid)
+		}
+	}
+}
@@ -1,22 +1,31 @@
 package parse
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/open-policy-agent/opa/ast"
-	"github.com/open-policy-agent/opa/ast/json"
+	astjson "github.com/open-policy-agent/opa/ast/json"
 
-	rio "github.com/styrainc/regal/internal/io"
+	"github.com/styrainc/regal/internal/metrics"
 )
 
-// ParserOptions provides the parse options necessary to include location data in AST results.
-func ParserOptions() ast.ParserOptions {
+// parserOptions are computed only once, as the same options are used for every file parsed, whether by the
+// linter, the language server or any command. This includes the capabilities, which would otherwise be computed
+// again by the parser for every file parsed.
+//
+//nolint:gochecknoglobals
+var parserOptions = sync.OnceValue(func() ast.ParserOptions {
 	return ast.ParserOptions{
+		Capabilities:      ast.CapabilitiesForThisVersion(),
+		RegoVersion:       ast.RegoV0,
 		ProcessAnnotation: true,
-		JSONOptions: &json.Options{
-			MarshalOptions: json.MarshalOptions{
-				IncludeLocation: json.NodeToggle{
+		JSONOptions: &astjson.Options{
+			MarshalOptions: astjson.MarshalOptions{
+				IncludeLocation: astjson.NodeToggle{
 					Term:           true,
 					Package:        true,
 					Comment:        true,
@@ -35,6 +44,14 @@ func ParserOptions() ast.ParserOptions {
 			},
 		},
 	}
+})
+
+// ParserOptions provides the parse options necessary to include location data in AST results. All Rego
+// parsed by Regal should use these options, preferably by way of Module, so that the resulting ASTs (and
+// their JSON representation) are the same everywhere. The capabilities and JSON options are shared, and must not
+// be modified.
+func ParserOptions() ast.ParserOptions {
+	return parserOptions()
 }
 
 // MustParseModule works like ast.MustParseModule but with the Regal parser options applied.
@@ -54,12 +71,21 @@ func Module(filename, policy string) (*ast.Module, error) {
 
 // PrepareAST prepares the AST to be used as linter input.
 func PrepareAST(name string, content string, module *ast.Module) (map[string]any, error) {
-	var preparedAST map[string]any
+	bs, err := json.Marshal(module)
+	if err != nil {
+		return nil, fmt.Errorf("JSON marshalling failed for module: %w", err)
+	}
 
-	if err := rio.JSONRoundTrip(module, &preparedAST); err != nil {
+	decoded, err := decodeInterned(bs)
+	if err != nil {
 		return nil, fmt.Errorf("JSON rountrip failed for module: %w", err)
 	}
 
+	preparedAST, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected module to be a JSON object, got %T", decoded)
+	}
+
 	preparedAST["regal"] = map[string]any{
 		"file": map[string]any{
 			"name":  name,
@@ -69,3 +95,63 @@ func PrepareAST(name string, content string, module *ast.Module) (map[string]any
 
 	return preparedAST, nil
 }
+
+// PreparedASTCache memoizes the linter input prepared from each file, keyed by file name and the hash of its
+// contents, so that the same contents are not converted again each time they are linted. Only the input for
+// the latest contents seen for each file is kept. A PreparedASTCache is safe for concurrent use.
+type PreparedASTCache struct {
+	entries map[string]preparedAST
+	mu      sync.Mutex
+	stats   metrics.CacheStats
+}
+
+type preparedAST struct {
+	hash  [sha256.Size]byte
+	input map[string]any
+}
+
+func NewPreparedASTCache() *PreparedASTCache {
+	return &PreparedASTCache{entries: make(map[string]preparedAST)}
+}
+
+// PrepareAST works like PrepareAST, but returns the input prepared previously if the contents are unchanged.
+// The returned input is shared, and must not be modified.
+func (c *PreparedASTCache) PrepareAST(name string, content string, module *ast.Module) (map[string]any, error) {
+	hash := sha256.Sum256([]byte(content))
+
+	c.mu.Lock()
+	entry, ok := c.entries[name]
+	c.mu.Unlock()
+
+	hit := ok && entry.hash == hash
+
+	c.stats.Record(hit)
+
+	if hit {
+		return entry.input, nil
+	}
+
+	input, err := PrepareAST(name, content, module)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[name] = preparedAST{hash: hash, input: input}
+	c.mu.Unlock()
+
+	return input, nil
+}
+
+// Stats returns the number of lookups in the cache, by whether input prepared for the same contents was found.
+func (c *PreparedASTCache) Stats() *metrics.CacheStats {
+	return &c.stats
+}
+
+// Delete removes the input prepared for the file with name.
+func (c *PreparedASTCache) Delete(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, name)
+}
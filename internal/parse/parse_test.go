@@ -1,8 +1,11 @@
 package parse
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/open-policy-agent/opa/ast"
+
 	"github.com/styrainc/regal/internal/testutil"
 )
 
@@ -15,3 +18,46 @@ func TestParseModule(t *testing.T) {
 		t.Errorf("expected %q, got %q", exp, got)
 	}
 }
+
+func TestParserOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := ParserOptions()
+
+	if opts.RegoVersion != ast.RegoV0 {
+		t.Errorf("expected Rego version %v, got %v", ast.RegoV0, opts.RegoVersion)
+	}
+
+	if opts.Capabilities == nil || opts.Capabilities != ParserOptions().Capabilities {
+		t.Errorf("expected capabilities to be computed once and shared")
+	}
+
+	if !opts.ProcessAnnotation || opts.JSONOptions == nil {
+		t.Errorf("expected annotations to be processed and JSON options to be set")
+	}
+}
+
+func TestPreparedASTCache(t *testing.T) {
+	t.Parallel()
+
+	c := NewPreparedASTCache()
+
+	first := testutil.Must(c.PrepareAST("p.rego", "package p", MustParseModule("package p")))(t)
+	second := testutil.Must(c.PrepareAST("p.rego", "package p", MustParseModule("package p")))(t)
+
+	if fmt.Sprintf("%p", first) != fmt.Sprintf("%p", second) {
+		t.Errorf("expected input to be reused for unchanged contents")
+	}
+
+	changed := testutil.Must(c.PrepareAST("p.rego", "package q", MustParseModule("package q")))(t)
+
+	if fmt.Sprintf("%p", first) == fmt.Sprintf("%p", changed) {
+		t.Errorf("expected input to be prepared again for changed contents")
+	}
+
+	c.Delete("p.rego")
+
+	if _, ok := c.entries["p.rego"]; ok {
+		t.Errorf("expected input to be deleted")
+	}
+}
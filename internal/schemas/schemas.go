@@ -0,0 +1,370 @@
+// Package schemas provides the JSON schemas declared for input and data in the schemas of annotations, and the
+// fields they describe, for linting and for features of the language server like completions and hover. Where no
+// schema of input is declared, one is derived from the input document of the workspace instead, like input.json.
+package schemas
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/inputdoc"
+)
+
+const (
+	// DirName is the directory of the workspace from which schemas referenced in annotations, like schema.acl, are
+	// loaded, like with opa eval --schema schemas.
+	DirName = "schemas"
+)
+
+// Field describes a field of an object, or the elements of an array, as found in a schema.
+type Field struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Load returns the schema referenced by ref, like schema.acl, from the schemas directory of the workspace at
+// rootPath.
+func Load(ref ast.Ref, rootPath string) (any, error) {
+	if len(ref) == 0 || !ref[0].Equal(ast.SchemaRootDocument) {
+		return nil, fmt.Errorf("not a schema reference: %s", ref)
+	}
+
+	parts := make([]string, 0, len(ref)-1)
+
+	for _, term := range ref[1:] {
+		s, ok := term.Value.(ast.String)
+		if !ok {
+			return nil, fmt.Errorf("unsupported schema reference: %s", ref)
+		}
+
+		parts = append(parts, string(s))
+	}
+
+	bs, err := os.ReadFile(filepath.Join(rootPath, DirName, path.Join(parts...)+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema %s: %w", ref, err)
+	}
+
+	var schema any
+	if err := json.Unmarshal(bs, &schema); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema %s: %w", ref, err)
+	}
+
+	return schema, nil
+}
+
+// FromAnnotation returns the schema of a schemas annotation, either defined inline or referenced, along with a
+// name for it, like schema.acl.
+func FromAnnotation(annotation *ast.SchemaAnnotation, rootPath string) (any, string, error) {
+	if annotation.Definition != nil {
+		return *annotation.Definition, "the schema of " + annotation.Path.String(), nil
+	}
+
+	if rootPath == "" {
+		return nil, "", fmt.Errorf("no workspace to load schema %s from", annotation.Schema)
+	}
+
+	schema, err := Load(annotation.Schema, rootPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return schema, annotation.Schema.String(), nil
+}
+
+// Input returns the schema of input applying at the line, starting at 1, of the module. Schemas declared for the
+// rule on the line take precedence over those declared for the package. If none is declared, a schema is derived
+// from the input document at inputPath, as found by the inputdoc package, unless empty.
+func Input(module *ast.Module, line int, inputPath, rootPath string) (any, bool) {
+	for _, annotation := range Declared(module, line) {
+		if !annotation.Path.Equal(ast.InputRootRef) {
+			continue
+		}
+
+		if schema, _, err := FromAnnotation(annotation, rootPath); err == nil {
+			return schema, true
+		}
+	}
+
+	if inputPath == "" {
+		return nil, false
+	}
+
+	sample, err := inputdoc.Read(inputPath)
+	if err != nil {
+		return nil, false
+	}
+
+	return FromSample(sample), true
+}
+
+// Declared returns the schemas declared in the annotations of the module applying at the line, starting at 1, in
+// order of precedence: those declared for the rule on the line first, followed by those declared for the package.
+func Declared(module *ast.Module, line int) []*ast.SchemaAnnotation {
+	declared := make([]*ast.SchemaAnnotation, 0)
+
+	for _, annotation := range ruleAnnotations(module, line) {
+		declared = append(declared, annotation.Schemas...)
+	}
+
+	for _, annotation := range module.Annotations {
+		if annotation.Scope == "package" || annotation.Scope == "subpackages" {
+			declared = append(declared, annotation.Schemas...)
+		}
+	}
+
+	return declared
+}
+
+// FromSample returns a schema describing the fields found in a sample document. The elements of arrays are
+// described by the schema of their first element.
+func FromSample(sample any) map[string]any {
+	switch v := sample.(type) {
+	case map[string]any:
+		properties := make(map[string]any, len(v))
+		for key, value := range v {
+			properties[key] = FromSample(value)
+		}
+
+		return map[string]any{"type": "object", "properties": properties}
+	case []any:
+		schema := map[string]any{"type": "array"}
+		if len(v) > 0 {
+			schema["items"] = FromSample(v[0])
+		}
+
+		return schema
+	case string:
+		return map[string]any{"type": "string"}
+	case bool:
+		return map[string]any{"type": "boolean"}
+	case float64, json.Number:
+		return map[string]any{"type": "number"}
+	}
+
+	return map[string]any{"type": "null"}
+}
+
+// Fields returns the fields of the object at path in the schema, sorted by name. Path elements that are numbers,
+// or _, refer to the elements of arrays.
+func Fields(schema any, p []string) []Field {
+	node, ok := lookup(schema, schema, p)
+	if !ok {
+		return nil
+	}
+
+	properties, _ := node["properties"].(map[string]any)
+	fields := make([]Field, 0, len(properties))
+
+	for name, property := range properties {
+		fields = append(fields, field(schema, name, property))
+	}
+
+	slices.SortFunc(fields, func(a, b Field) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return fields
+}
+
+// Lookup returns the field at path in the schema.
+func Lookup(schema any, p []string) (Field, bool) {
+	if len(p) == 0 {
+		return Field{}, false
+	}
+
+	node, ok := lookup(schema, schema, p)
+	if !ok {
+		return Field{}, false
+	}
+
+	return field(schema, p[len(p)-1], node), true
+}
+
+// Unknown returns the index of the first element of path that can't exist according to the schema, if any. Like for
+// the type checker of OPA, objects with properties are considered closed unless they allow additional properties,
+// with the schema of additional properties applying to fields not listed.
+// Parts of the schema that don't describe their contents, like objects without properties, accept any path.
+// Path elements that are _ refer to any element of an array, or any field of an object.
+func Unknown(schema any, p []string) (int, bool) {
+	node := resolve(schema, schema)
+
+	for i, key := range p {
+		if node == nil || key == "_" && node["items"] == nil {
+			return 0, false
+		}
+
+		if isIndex(key) && (node["items"] != nil || node["type"] == "array") {
+			node = resolve(schema, node["items"])
+
+			continue
+		}
+
+		if t, ok := node["type"].(string); ok && t != "object" || node["items"] != nil {
+			return i, true
+		}
+
+		properties, _ := node["properties"].(map[string]any)
+		if property, ok := properties[key]; ok {
+			node = resolve(schema, property)
+
+			continue
+		}
+
+		additional, limited := node["additionalProperties"]
+		if additional, isSchema := additional.(map[string]any); isSchema {
+			node = resolve(schema, additional)
+
+			continue
+		}
+
+		if _, ok := node["patternProperties"]; ok || additional == true || properties == nil && !limited {
+			return 0, false
+		}
+
+		return i, true
+	}
+
+	return 0, false
+}
+
+func lookup(root, schema any, p []string) (map[string]any, bool) {
+	node := resolve(root, schema)
+
+	for _, key := range p {
+		if node == nil {
+			return nil, false
+		}
+
+		if items, ok := node["items"]; ok && isIndex(key) {
+			node = resolve(root, items)
+
+			continue
+		}
+
+		properties, ok := node["properties"].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if node = resolve(root, properties[key]); node == nil {
+			return nil, false
+		}
+	}
+
+	return node, node != nil
+}
+
+// resolve returns the schema, following references to definitions in the root schema.
+func resolve(root, schema any) map[string]any {
+	node, _ := schema.(map[string]any)
+
+	// a limit on the number of references followed avoids looping on circular references
+	for range 32 {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+
+		target, _ := root.(map[string]any)
+
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			target, _ = target[part].(map[string]any)
+		}
+
+		node = target
+	}
+
+	return nil
+}
+
+func field(root any, name string, schema any) Field {
+	node := resolve(root, schema)
+	description, _ := node["description"].(string)
+
+	return Field{Name: name, Type: typeOf(root, node), Description: description}
+}
+
+func typeOf(root any, node map[string]any) string {
+	switch t := node["type"].(type) {
+	case string:
+		if t == "array" {
+			if items := resolve(root, node["items"]); items != nil {
+				return "array[" + typeOf(root, items) + "]"
+			}
+		}
+
+		return t
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			types = append(types, fmt.Sprint(v))
+		}
+
+		return strings.Join(types, "|")
+	}
+
+	if _, ok := node["properties"]; ok {
+		return "object"
+	}
+
+	return "any"
+}
+
+func isIndex(key string) bool {
+	if key == "_" {
+		return true
+	}
+
+	_, err := strconv.Atoi(key)
+
+	return err == nil
+}
+
+func lastRow(location *ast.Location) int {
+	return location.Row + strings.Count(string(location.Text), "\n")
+}
+
+// ruleAnnotations returns the annotations of the rule on the line, which are those between the rule and the rule
+// preceding it.
+func ruleAnnotations(module *ast.Module, line int) []*ast.Annotations {
+	previousRow := 0
+
+	for _, rule := range module.Rules {
+		if rule.Location == nil {
+			continue
+		}
+
+		if line < rule.Location.Row || line > lastRow(rule.Location) {
+			previousRow = lastRow(rule.Location)
+
+			continue
+		}
+
+		annotations := make([]*ast.Annotations, 0)
+
+		for _, annotation := range module.Annotations {
+			if annotation.Scope != "rule" && annotation.Scope != "document" {
+				continue
+			}
+
+			if row := annotation.Location.Row; row > previousRow && row < rule.Location.Row {
+				annotations = append(annotations, annotation)
+			}
+		}
+
+		return annotations
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package schemas
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+func TestFieldsFromSample(t *testing.T) {
+	t.Parallel()
+
+	schema := FromSample(map[string]any{
+		"user": map[string]any{"name": "alice", "roles": []any{"admin"}, "age": 42.0},
+		"request": map[string]any{
+			"headers": []any{map[string]any{"name": "x", "value": "y"}},
+		},
+	})
+
+	names := fieldNames(Fields(schema, []string{"user"}))
+	if !slices.Equal(names, []string{"age", "name", "roles"}) {
+		t.Errorf("expected fields age, name and roles, got %v", names)
+	}
+
+	names = fieldNames(Fields(schema, []string{"request", "headers", "_"}))
+	if !slices.Equal(names, []string{"name", "value"}) {
+		t.Errorf("expected fields name and value, got %v", names)
+	}
+
+	field, ok := Lookup(schema, []string{"user", "roles"})
+	if !ok || field.Type != "array[string]" {
+		t.Errorf("expected roles of type array[string], got %v", field)
+	}
+
+	if _, ok := Lookup(schema, []string{"user", "nmae"}); ok {
+		t.Error("expected no field nmae")
+	}
+}
+
+func TestFieldsWithDefinitions(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"user": map[string]any{"$ref": "#/definitions/user"},
+		},
+		"definitions": map[string]any{
+			"user": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{"type": "string", "description": "Name of the user"},
+				},
+			},
+		},
+	}
+
+	field, ok := Lookup(schema, []string{"user", "name"})
+	if !ok {
+		t.Fatal("expected field user.name")
+	}
+
+	if field.Type != "string" || field.Description != "Name of the user" {
+		t.Errorf("unexpected field %v", field)
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"user":    map[string]any{"$ref": "#/definitions/user"},
+			"groups":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"labels":  map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"context": map[string]any{"type": "object"},
+		},
+		"definitions": map[string]any{
+			"user": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"name": map[string]any{"type": "string"}},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		path     []string
+		expected int
+		unknown  bool
+	}{
+		"known field":                  {path: []string{"user", "name"}},
+		"unknown field":                {path: []string{"user", "nmae"}, expected: 1, unknown: true},
+		"unknown top level field":      {path: []string{"users", "name"}, expected: 0, unknown: true},
+		"field of string":              {path: []string{"user", "name", "first"}, expected: 2, unknown: true},
+		"element of array":             {path: []string{"groups", "0"}},
+		"any element of array":         {path: []string{"groups", "_"}},
+		"field of array":               {path: []string{"groups", "name"}, expected: 1, unknown: true},
+		"additional property":          {path: []string{"labels", "team"}},
+		"field of additional property": {path: []string{"labels", "team", "name"}, expected: 2, unknown: true},
+		"object without properties":    {path: []string{"context", "anything"}},
+		"any field of object":          {path: []string{"_", "name"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			i, unknown := Unknown(schema, tc.path)
+			if unknown != tc.unknown || i != tc.expected {
+				t.Errorf("expected %d, %t, got %d, %t", tc.expected, tc.unknown, i, unknown)
+			}
+		})
+	}
+}
+
+func TestInput(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	inputPath := filepath.Join(root, "input.json")
+
+	if err := os.WriteFile(inputPath, []byte(`{"sample": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	module := parse.MustParseModule(`# METADATA
+# scope: package
+# schemas:
+#   - input: {"type": "object", "properties": {"package_level": {"type": "string"}}}
+package p
+
+import rego.v1
+
+# METADATA
+# schemas:
+#   - input: {"type": "object", "properties": {"rule_level": {"type": "string"}}}
+allow if {
+	input.rule_level == "yes"
+}
+
+deny if {
+	input.package_level == "yes"
+}
+`)
+
+	testCases := map[string]struct {
+		line     int
+		expected []string
+	}{
+		"rule with schema":    {line: 13, expected: []string{"rule_level"}},
+		"rule without schema": {line: 17, expected: []string{"package_level"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, ok := Input(module, tc.line, inputPath, root)
+			if !ok {
+				t.Fatal("expected schema of input")
+			}
+
+			if names := fieldNames(Fields(schema, nil)); !slices.Equal(names, tc.expected) {
+				t.Errorf("expected fields %v, got %v", tc.expected, names)
+			}
+		})
+	}
+
+	// without any schema declared, the input document of the workspace is used
+	schema, ok := Input(parse.MustParseModule("package p\n"), 1, inputPath, root)
+	if !ok {
+		t.Fatal("expected schema derived from sample input")
+	}
+
+	if names := fieldNames(Fields(schema, nil)); !slices.Equal(names, []string{"sample"}) {
+		t.Errorf("expected field sample, got %v", names)
+	}
+}
+
+func fieldNames(fields []Field) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,360 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	rbundle "github.com/styrainc/regal/bundle"
+	"github.com/styrainc/regal/internal/docs"
+	rio "github.com/styrainc/regal/internal/io"
+	"github.com/styrainc/regal/internal/tracing"
+	"github.com/styrainc/regal/pkg/api/regalv1"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/fixer"
+	"github.com/styrainc/regal/pkg/fixer/fileprovider"
+	"github.com/styrainc/regal/pkg/fixer/fixes"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+// bundledRules returns the rules of the embedded bundle, along with the Go rules, without regard to whether they're
+// enabled. Loaded once, as the annotations of the bundle don't change.
+//
+//nolint:gochecknoglobals
+var bundledRules = sync.OnceValue(func() []*regalv1.Rule {
+	rulesPrefix := ast.MustParseRef("data.regal.rules")
+
+	var all []*regalv1.Rule
+
+	for _, module := range rio.MustLoadRegalBundleFS(rbundle.Bundle).Modules {
+		path := module.Parsed.Package.Path
+		if len(path) != len(rulesPrefix)+2 || !path.HasPrefix(rulesPrefix) {
+			continue
+		}
+
+		category, ok1 := path[len(rulesPrefix)].Value.(ast.String)
+		title, ok2 := path[len(rulesPrefix)+1].Value.(ast.String)
+
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		rule := &regalv1.Rule{
+			Name:             string(title),
+			Category:         string(category),
+			DocumentationUrl: docs.CreateDocsURL(string(category), string(title)),
+		}
+
+		for _, annotation := range module.Parsed.Annotations {
+			if annotation.Scope == "package" {
+				rule.Description = annotation.Description
+			}
+		}
+
+		all = append(all, rule)
+	}
+
+	for _, rule := range rules.AllGoRules(config.Config{}) {
+		all = append(all, &regalv1.Rule{
+			Name:             rule.Name(),
+			Category:         rule.Category(),
+			Description:      rule.Description(),
+			DocumentationUrl: rule.Documentation(),
+		})
+	}
+
+	slices.SortFunc(all, func(a, b *regalv1.Rule) int {
+		if a.GetCategory() != b.GetCategory() {
+			return cmp.Compare(a.GetCategory(), b.GetCategory())
+		}
+
+		return cmp.Compare(a.GetName(), b.GetName())
+	})
+
+	return all
+})
+
+// grpcService implements the regal.v1.Linter gRPC service.
+type grpcService struct {
+	regalv1.UnimplementedLinterServer
+
+	server *Server
+}
+
+// RegisterGRPC registers the gRPC API of the server with the provided gRPC server, to be served alongside the HTTP
+// endpoints from the same process.
+func (s *Server) RegisterGRPC(registrar grpc.ServiceRegistrar) {
+	regalv1.RegisterLinterServer(registrar, &grpcService{server: s})
+}
+
+// GRPCServerOptions returns the options for a gRPC server serving the API of the server, recording the requests
+// handled in spans, and in the metrics of the server, if enabled.
+func (s *Server) GRPCServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(
+			ctx context.Context,
+			req any,
+			info *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler,
+		) (any, error) {
+			ctx, span := tracing.Start(ctx, info.FullMethod, attribute.String("rpc.system", "grpc"))
+
+			res, err := handler(ctx, req)
+
+			s.observeGRPC(span, info.FullMethod, err)
+
+			return res, err
+		}),
+		grpc.ChainStreamInterceptor(func(
+			srv any,
+			stream grpc.ServerStream,
+			info *grpc.StreamServerInfo,
+			handler grpc.StreamHandler,
+		) error {
+			ctx, span := tracing.Start(stream.Context(), info.FullMethod, attribute.String("rpc.system", "grpc"))
+
+			err := handler(srv, &tracedStream{ServerStream: stream, ctx: ctx})
+
+			s.observeGRPC(span, info.FullMethod, err)
+
+			return err
+		}),
+	}
+}
+
+// tracedStream provides the context of the span of a stream to its handler.
+type tracedStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+func (t *tracedStream) Context() context.Context {
+	return t.ctx
+}
+
+func (s *Server) observeGRPC(span trace.Span, method string, err error) {
+	code := status.Code(err)
+
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+	tracing.End(span, err)
+
+	s.metrics.ObserveRequest("grpc", method, code.String())
+}
+
+// LintFiles lints the files of a workspace, and returns the report.
+func (g *grpcService) LintFiles(
+	ctx context.Context,
+	req *regalv1.LintFilesRequest,
+) (*regalv1.LintFilesResponse, error) {
+	return g.lintFiles(ctx, req)
+}
+
+// LintStream lints each workspace received on the stream, and sends back its report, until the client closes the
+// stream. Failure to lint the files of a request is reported in its response, rather than ending the stream.
+func (g *grpcService) LintStream(stream regalv1.Linter_LintStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		res := &regalv1.LintStreamResponse{Id: req.GetId()}
+
+		if res.Response, err = g.lintFiles(stream.Context(), req.GetRequest()); err != nil {
+			res.Error = status.Convert(err).Message()
+		}
+
+		if err = stream.Send(res); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+}
+
+// ListRules lists the rules known to the linter, and whether they're enabled by the configuration.
+func (g *grpcService) ListRules(
+	ctx context.Context,
+	req *regalv1.ListRulesRequest,
+) (*regalv1.ListRulesResponse, error) {
+	conf, err := decodeConfig(req.GetConfig())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	enabled, err := g.server.linter(conf).DetermineEnabledRules(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to determine enabled rules")
+	}
+
+	all := bundledRules()
+	res := &regalv1.ListRulesResponse{Rules: make([]*regalv1.Rule, 0, len(all))}
+
+	for _, rule := range all {
+		res.Rules = append(res.Rules, &regalv1.Rule{
+			Name:             rule.GetName(),
+			Category:         rule.GetCategory(),
+			Description:      rule.GetDescription(),
+			DocumentationUrl: rule.GetDocumentationUrl(),
+			Enabled:          slices.Contains(enabled, rule.GetName()),
+		})
+	}
+
+	return res, nil
+}
+
+// Fix fixes the violations found in the files of a workspace, and returns the contents of the files fixed.
+func (g *grpcService) Fix(ctx context.Context, req *regalv1.FixRequest) (*regalv1.FixResponse, error) {
+	conf, err := decodeConfig(req.GetConfig())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	files := make(map[string][]byte, len(req.GetFiles()))
+
+	for _, file := range req.GetFiles() {
+		files[file.GetName()] = []byte(file.GetContent())
+	}
+
+	if len(files) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no policy files provided")
+	}
+
+	f := fixer.NewFixer()
+	f.RegisterFixes(fixes.NewDefaultFixes()...)
+
+	fp := fileprovider.NewInMemoryFileProvider(files)
+	l := g.server.linter(conf)
+
+	// errors fixing aren't returned, as they may hold details of the environment of the server
+	fixReport, err := f.Fix(ctx, &l, fp)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "failed to fix")
+	}
+
+	res := &regalv1.FixResponse{}
+
+	for _, name := range fixReport.FixedFiles() {
+		content, err := fp.GetFile(name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to read fixed file")
+		}
+
+		res.Files = append(res.Files, &regalv1.FixedFile{
+			Name:    name,
+			Content: string(content),
+			Fixes:   fixReport.FixedViolationsForFile(name),
+		})
+	}
+
+	return res, nil
+}
+
+func (g *grpcService) lintFiles(
+	ctx context.Context,
+	req *regalv1.LintFilesRequest,
+) (*regalv1.LintFilesResponse, error) {
+	conf, err := decodeConfig(req.GetConfig())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	lr := lintRequest{files: make(map[string]string, len(req.GetFiles())), config: conf}
+
+	for _, file := range req.GetFiles() {
+		if _, ok := lr.files[file.GetName()]; ok {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("duplicate file %s", file.GetName()))
+		}
+
+		lr.files[file.GetName()] = file.GetContent()
+		lr.names = append(lr.names, file.GetName())
+	}
+
+	if len(lr.names) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no policy files provided")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.server.timeout)
+	defer cancel()
+
+	result, err := g.server.lint(ctx, lr)
+	if err != nil {
+		var parseErr parseError
+		if errors.As(err, &parseErr) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, errLint.Error())
+	}
+
+	return toLintFilesResponse(result), nil
+}
+
+func toLintFilesResponse(rep report.Report) *regalv1.LintFilesResponse {
+	res := &regalv1.LintFilesResponse{
+		Violations: make([]*regalv1.Violation, 0, len(rep.Violations)),
+		Notices:    make([]*regalv1.Notice, 0, len(rep.Notices)),
+		Summary: &regalv1.Summary{
+			FilesScanned:  int32(rep.Summary.FilesScanned),  //nolint:gosec
+			FilesFailed:   int32(rep.Summary.FilesFailed),   //nolint:gosec
+			RulesSkipped:  int32(rep.Summary.RulesSkipped),  //nolint:gosec
+			NumViolations: int32(rep.Summary.NumViolations), //nolint:gosec
+		},
+	}
+
+	for _, violation := range rep.Violations {
+		location := &regalv1.Location{
+			File:   violation.Location.File,
+			Row:    int32(violation.Location.Row),    //nolint:gosec
+			Column: int32(violation.Location.Column), //nolint:gosec
+		}
+
+		if violation.Location.Text != nil {
+			location.Text = *violation.Location.Text
+		}
+
+		v := &regalv1.Violation{
+			Title:       violation.Title,
+			Description: violation.Description,
+			Category:    violation.Category,
+			Level:       violation.Level,
+			Location:    location,
+		}
+
+		for _, resource := range violation.RelatedResources {
+			if resource.Description == "documentation" {
+				v.DocumentationUrl = resource.Reference
+			}
+		}
+
+		res.Violations = append(res.Violations, v)
+	}
+
+	for _, notice := range rep.Notices {
+		res.Notices = append(res.Notices, &regalv1.Notice{
+			Title:       notice.Title,
+			Description: notice.Description,
+			Category:    notice.Category,
+			Level:       notice.Level,
+			Severity:    notice.Severity,
+		})
+	}
+
+	return res
+}
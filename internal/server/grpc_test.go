@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/styrainc/regal/pkg/api/regalv1"
+)
+
+func newTestClient(t *testing.T) regalv1.LinterClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	grpcServer := grpc.NewServer()
+	New(Options{}).RegisterGRPC(grpcServer)
+
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return regalv1.NewLinterClient(conn)
+}
+
+func TestGRPCLintFiles(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t)
+
+	res, err := client.LintFiles(context.Background(), &regalv1.LintFilesRequest{
+		Files: []*regalv1.File{{Name: "p.rego", Content: policy}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.GetSummary().GetFilesScanned() != 1 {
+		t.Errorf("expected 1 file scanned, got %d", res.GetSummary().GetFilesScanned())
+	}
+
+	found := false
+
+	for _, violation := range res.GetViolations() {
+		if violation.GetTitle() == "prefer-snake-case" && violation.GetLocation().GetRow() == 5 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("expected prefer-snake-case violation on row 5, got %v", res.GetViolations())
+	}
+
+	_, err = client.LintFiles(context.Background(), &regalv1.LintFilesRequest{
+		Files: []*regalv1.File{{Name: "p.rego", Content: "package"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument error for unparsable policy, got %v", err)
+	}
+}
+
+func TestGRPCLintStream(t *testing.T) {
+	t.Parallel()
+
+	stream, err := newTestClient(t).LintStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []*regalv1.LintStreamRequest{
+		{Id: "ok", Request: &regalv1.LintFilesRequest{Files: []*regalv1.File{{Name: "p.rego", Content: policy}}}},
+		{Id: "invalid", Request: &regalv1.LintFilesRequest{Files: []*regalv1.File{{Name: "p.rego", Content: "package"}}}},
+	}
+
+	for _, req := range requests {
+		if err = stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, req := range requests {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.GetId() != req.GetId() {
+			t.Errorf("expected response for %s, got %s", req.GetId(), res.GetId())
+		}
+
+		if failed := res.GetError() != ""; failed != (req.GetId() == "invalid") {
+			t.Errorf("unexpected error for %s: %q", req.GetId(), res.GetError())
+		}
+	}
+}
+
+func TestGRPCListRules(t *testing.T) {
+	t.Parallel()
+
+	res, err := newTestClient(t).ListRules(context.Background(), &regalv1.ListRulesRequest{
+		Config: "rules:\n  style:\n    prefer-snake-case:\n      level: ignore\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := make(map[string]bool)
+
+	for _, rule := range res.GetRules() {
+		if rule.GetDescription() == "" || rule.GetDocumentationUrl() == "" {
+			t.Errorf("expected description and documentation URL for rule %s", rule.GetName())
+		}
+
+		enabled[rule.GetName()] = rule.GetEnabled()
+	}
+
+	if e, ok := enabled["prefer-snake-case"]; !ok || e {
+		t.Errorf("expected prefer-snake-case to be listed as disabled")
+	}
+
+	for _, name := range []string{"opa-fmt", "use-assignment-operator"} {
+		if !enabled[name] {
+			t.Errorf("expected %s to be listed as enabled", name)
+		}
+	}
+}
+
+func TestGRPCConfigCapabilitiesFromRejected(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t)
+	conf := "capabilities:\n  from:\n    file: /etc/passwd\n"
+
+	_, err := client.LintFiles(context.Background(), &regalv1.LintFilesRequest{
+		Files:  []*regalv1.File{{Name: "p.rego", Content: policy}},
+		Config: conf,
+	})
+	if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != errCapabilitiesFrom.Error() {
+		t.Errorf("expected capabilities.from to be rejected when linting, got %v", err)
+	}
+
+	_, err = client.ListRules(context.Background(), &regalv1.ListRulesRequest{Config: conf})
+	if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != errCapabilitiesFrom.Error() {
+		t.Errorf("expected capabilities.from to be rejected when listing rules, got %v", err)
+	}
+
+	_, err = client.Fix(context.Background(), &regalv1.FixRequest{
+		Files:  []*regalv1.File{{Name: "p.rego", Content: policy}},
+		Config: conf,
+	})
+	if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != errCapabilitiesFrom.Error() {
+		t.Errorf("expected capabilities.from to be rejected when fixing, got %v", err)
+	}
+}
+
+func TestGRPCFix(t *testing.T) {
+	t.Parallel()
+
+	res, err := newTestClient(t).Fix(context.Background(), &regalv1.FixRequest{
+		Files: []*regalv1.File{
+			{Name: "fix.rego", Content: "package p\n\nimport rego.v1\n\nallow = true\n"},
+			{Name: "ok.rego", Content: "package q\n\nimport rego.v1\n\nallow := true\n"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.GetFiles()) != 1 {
+		t.Fatalf("expected 1 file fixed, got %d", len(res.GetFiles()))
+	}
+
+	fixed := res.GetFiles()[0]
+
+	if fixed.GetName() != "fix.rego" || fixed.GetContent() != "package p\n\nimport rego.v1\n\nallow := true\n" {
+		t.Errorf("unexpected fixed file %s:\n%s", fixed.GetName(), fixed.GetContent())
+	}
+
+	if !slices.Contains(fixed.GetFixes(), "use-assignment-operator") {
+		t.Errorf("expected use-assignment-operator fix, got %v", fixed.GetFixes())
+	}
+}
@@ -0,0 +1,425 @@
+// Package server provides an HTTP server for linting Rego policies, for integrations like policy portals and web
+// based editors, which can't run the regal command themselves.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/metrics"
+	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/internal/tracing"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/reporter"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+const (
+	// DefaultMaxRequestSize is the default limit on the size of request bodies, in bytes.
+	DefaultMaxRequestSize = 10 << 20
+
+	// DefaultTimeout is the default limit on the time spent linting the policies of a request.
+	DefaultTimeout = 30 * time.Second
+
+	// defaultFileName is the name given to a policy sent as the body of a request, unless provided.
+	defaultFileName = "policy.rego"
+
+	// configPart is the name of the part of a multipart request holding the configuration.
+	configPart = "config"
+)
+
+var (
+	// errInvalidConfig is returned for configuration provided with a request which can't be decoded. The cause isn't
+	// included, as decoding errors may hold details of the environment of the server.
+	errInvalidConfig = errors.New("failed to decode config")
+
+	// errCapabilitiesFrom is returned for configuration provided with a request loading capabilities, which would
+	// have the server read files of its own.
+	errCapabilitiesFrom = errors.New("capabilities.from is not allowed in the config of a request")
+
+	// errLint is returned in place of errors linting the policies of a request, other than parse errors.
+	errLint = errors.New("failed to lint")
+)
+
+// Options configures the Server.
+type Options struct {
+	// Config is used for requests not providing their own configuration.
+	Config *config.Config
+	// MaxRequestSize limits the size of request bodies, in bytes. Defaults to DefaultMaxRequestSize.
+	MaxRequestSize int64
+	// Timeout limits the time spent linting the policies of a request. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Metrics, when provided, collects metrics of the requests handled, which are exposed on the /metrics endpoint.
+	Metrics *metrics.Prometheus
+}
+
+// Server lints policies sent to it over HTTP.
+type Server struct {
+	config         *config.Config
+	maxRequestSize int64
+	timeout        time.Duration
+	metrics        *metrics.Prometheus
+}
+
+// lintRequest holds the policies and configuration read from a request.
+type lintRequest struct {
+	files  map[string]string
+	names  []string
+	config *config.Config
+}
+
+// New creates a new Server.
+func New(opts Options) *Server {
+	s := &Server{
+		config:         opts.Config,
+		maxRequestSize: opts.MaxRequestSize,
+		timeout:        opts.Timeout,
+		metrics:        opts.Metrics,
+	}
+
+	if s.maxRequestSize <= 0 {
+		s.maxRequestSize = DefaultMaxRequestSize
+	}
+
+	if s.timeout <= 0 {
+		s.timeout = DefaultTimeout
+	}
+
+	s.metrics.RegisterCache("compiled_rules", linter.CompiledRulesCacheStats())
+
+	return s
+}
+
+// Handler returns the HTTP handler for the endpoints of the server:
+//
+//	POST /v1/lint  lint the policies of the request, and return the report
+//	GET  /health   check that the server is up
+//	GET  /metrics  metrics in the Prometheus format, if enabled
+//
+// Policies are sent to the lint endpoint either as the body of the request, for a single file, named by the filename
+// query parameter, or as a multipart/form-data request, with a part for each file in the workspace, named by its
+// filename, and an optional part named config, holding the configuration in YAML (or JSON) format. The format query
+// parameter decides the format of the report, either json (default) or sarif.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("/v1/lint", s.instrument("/v1/lint", s.handleLint))
+	mux.Handle("/health", s.instrument("/health", handleHealth))
+
+	if s.metrics != nil {
+		mux.Handle("/metrics", s.metrics.Handler())
+	}
+
+	return mux
+}
+
+// statusRecorder records the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// instrument records each request handled by handler in a span, and in the metrics of the server, if enabled.
+func (s *Server) instrument(endpoint string, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracing.Start(r.Context(), r.Method+" "+endpoint,
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", endpoint),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		handler(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.status_code", recorder.status))
+
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+
+		s.metrics.ObserveRequest("http", endpoint, strconv.Itoa(recorder.status))
+	})
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("only GET is allowed"))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) handleLint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("only POST is allowed"))
+
+		return
+	}
+
+	rep, err := reporterForFormat(r.URL.Query().Get("format"), w)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestSize)
+
+	req, err := readLintRequest(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+
+			return
+		}
+
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+
+	result, err := s.lint(ctx, req)
+	if err != nil {
+		var parseErr parseError
+		if errors.As(err, &parseErr) {
+			writeError(w, http.StatusUnprocessableEntity, err)
+
+			return
+		}
+
+		writeError(w, http.StatusInternalServerError, errLint)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// the reporters write directly to the response, so failures can't be reported once started
+	_ = rep.Publish(ctx, result)
+}
+
+// parseError is returned when the policies of a request can't be parsed.
+type parseError struct {
+	err error
+}
+
+func (e parseError) Error() string {
+	return e.err.Error()
+}
+
+func (e parseError) Unwrap() error {
+	return e.err
+}
+
+func (s *Server) lint(ctx context.Context, req lintRequest) (report.Report, error) {
+	modules := make(map[string]*ast.Module, len(req.files))
+
+	for _, name := range req.names {
+		module, err := parse.Module(name, req.files[name])
+		if err != nil {
+			return report.Report{}, parseError{err: fmt.Errorf("failed to parse %s: %w", name, err)}
+		}
+
+		modules[name] = module
+	}
+
+	input := rules.NewInput(req.files, modules)
+
+	start := time.Now()
+
+	result, err := s.linter(req.config).WithInputModules(&input).Lint(ctx)
+	if err != nil {
+		return report.Report{}, fmt.Errorf("failed to lint: %w", err)
+	}
+
+	s.metrics.ObserveLint(time.Since(start), &result)
+
+	return result, nil
+}
+
+func readLintRequest(r *http.Request) (lintRequest, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return readSingleFileRequest(r)
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return lintRequest{}, fmt.Errorf("failed to read multipart request: %w", err)
+	}
+
+	req := lintRequest{files: make(map[string]string)}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return lintRequest{}, fmt.Errorf("failed to read multipart request: %w", err)
+		}
+
+		if err = req.addPart(part); err != nil {
+			return lintRequest{}, err
+		}
+	}
+
+	if len(req.names) == 0 {
+		return lintRequest{}, errors.New("no policy files provided")
+	}
+
+	return req, nil
+}
+
+func readSingleFileRequest(r *http.Request) (lintRequest, error) {
+	name := r.URL.Query().Get("filename")
+	if name == "" {
+		name = defaultFileName
+	}
+
+	bs, err := io.ReadAll(r.Body)
+	if err != nil {
+		return lintRequest{}, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if len(bs) == 0 {
+		return lintRequest{}, errors.New("no policy provided")
+	}
+
+	return lintRequest{files: map[string]string{name: string(bs)}, names: []string{name}}, nil
+}
+
+func (req *lintRequest) addPart(part *multipart.Part) error {
+	defer part.Close()
+
+	bs, err := io.ReadAll(part)
+	if err != nil {
+		return fmt.Errorf("failed to read multipart request: %w", err)
+	}
+
+	if part.FormName() == configPart {
+		req.config, err = decodeConfig(string(bs))
+
+		return err
+	}
+
+	// the multipart reader strips any directories from the filename parameter, so the form name is preferred when
+	// it's a path to a policy, allowing files of the same name in different directories
+	name := part.FormName()
+	if path.Ext(name) != ".rego" {
+		name = part.FileName()
+	}
+
+	if name == "" {
+		return fmt.Errorf("no filename provided for part %q", part.FormName())
+	}
+
+	if _, ok := req.files[name]; ok {
+		return fmt.Errorf("duplicate file %s", name)
+	}
+
+	req.files[name] = string(bs)
+	req.names = append(req.names, name)
+
+	return nil
+}
+
+// requestConfig holds the parts of configuration provided with a request which are checked before it's decoded.
+type requestConfig struct {
+	Capabilities struct {
+		From map[string]any `yaml:"from"`
+	} `yaml:"capabilities"`
+}
+
+// decodeConfig decodes configuration in YAML (or JSON) format, provided with a request. Nil is returned for empty
+// configuration, in which case the configuration of the server is used. Loading capabilities from a file or an engine
+// is rejected, as it has the server read files, the errors of which would be returned to the client.
+func decodeConfig(s string) (*config.Config, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	var restricted requestConfig
+	if err := yaml.Unmarshal([]byte(s), &restricted); err != nil {
+		return nil, errInvalidConfig
+	}
+
+	if len(restricted.Capabilities.From) > 0 {
+		return nil, errCapabilitiesFrom
+	}
+
+	var conf config.Config
+	if err := yaml.Unmarshal([]byte(s), &conf); err != nil {
+		return nil, errInvalidConfig
+	}
+
+	return &conf, nil
+}
+
+// linter returns a linter using the configuration provided with a request, or else that of the server.
+func (s *Server) linter(conf *config.Config) linter.Linter {
+	if conf == nil {
+		conf = s.config
+	}
+
+	l := linter.NewLinter()
+	if conf != nil {
+		l = l.WithUserConfig(*conf)
+	}
+
+	return l
+}
+
+func reporterForFormat(format string, w io.Writer) (reporter.Reporter, error) {
+	switch format {
+	case "", "json":
+		return reporter.NewJSONReporter(w), nil
+	case "sarif":
+		return reporter.NewSarifReporter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected json or sarif", format)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
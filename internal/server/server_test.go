@@ -0,0 +1,231 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/styrainc/regal/internal/metrics"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+const policy = `package p
+
+import rego.v1
+
+camelCase := true
+`
+
+func TestLintSingleFile(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/lint?filename=p/policy.rego", strings.NewReader(policy))
+
+	New(Options{}).Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var rep report.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+
+	if !hasViolation(rep, "prefer-snake-case", "p/policy.rego") {
+		t.Errorf("expected prefer-snake-case violation in p/policy.rego, got %v", rep.Violations)
+	}
+}
+
+func TestLintMultipartWithConfig(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	for name, content := range map[string]string{
+		"a/policy.rego": policy,
+		"b/policy.rego": strings.ReplaceAll(policy, "package p", "package q"),
+		"config":        "rules:\n  style:\n    prefer-snake-case:\n      level: ignore\n",
+	} {
+		part, err := mw.CreateFormFile(name, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = part.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/lint", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	New(Options{}).Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var rep report.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+
+	if rep.Summary.FilesScanned != 2 {
+		t.Errorf("expected 2 files scanned, got %d", rep.Summary.FilesScanned)
+	}
+
+	if hasViolation(rep, "prefer-snake-case", "a/policy.rego") {
+		t.Errorf("expected prefer-snake-case to be disabled by config, got %v", rep.Violations)
+	}
+}
+
+func TestLintSarifFormat(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/lint?format=sarif", strings.NewReader(policy))
+
+	New(Options{}).Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if !strings.Contains(rec.Body.String(), `"version": "2.1.0"`) {
+		t.Errorf("expected SARIF report, got %s", rec.Body.String())
+	}
+}
+
+func TestLintErrors(t *testing.T) {
+	t.Parallel()
+
+	handler := New(Options{MaxRequestSize: 64}).Handler()
+
+	for name, tc := range map[string]struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		"wrong method":   {http.MethodGet, "/v1/lint", "", http.StatusMethodNotAllowed},
+		"unknown format": {http.MethodPost, "/v1/lint?format=xml", policy, http.StatusBadRequest},
+		"empty body":     {http.MethodPost, "/v1/lint", "", http.StatusBadRequest},
+		"parse error":    {http.MethodPost, "/v1/lint", "package", http.StatusUnprocessableEntity},
+		"too large":      {http.MethodPost, "/v1/lint", policy + strings.Repeat("#\n", 64), http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+
+			if rec.Code != tc.status {
+				t.Errorf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Errorf("expected error in response, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestLintConfigCapabilitiesFromRejected(t *testing.T) {
+	t.Parallel()
+
+	handler := New(Options{}).Handler()
+
+	for name, conf := range map[string]string{
+		"file":    "capabilities:\n  from:\n    file: /etc/passwd\n",
+		"engine":  "capabilities:\n  from:\n    engine: opa\n    version: v0.60.0\n",
+		"invalid": "rules: [",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var body bytes.Buffer
+
+			mw := multipart.NewWriter(&body)
+
+			for name, content := range map[string]string{"p/policy.rego": policy, "config": conf} {
+				part, err := mw.CreateFormFile(name, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err = part.Write([]byte(content)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := mw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/v1/lint", &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			if strings.Contains(rec.Body.String(), "passwd") || strings.Contains(rec.Body.String(), "root") {
+				t.Errorf("expected error not to hold details of the config, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	handler := New(Options{Metrics: metrics.NewPrometheus()}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/lint", strings.NewReader(policy)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, expected := range []string{
+		`regal_requests_total{api="http",code="200",method="/v1/lint"} 1`,
+		`regal_violations_total{category="style",title="prefer-snake-case"} 1`,
+		`regal_cache_lookups_total{cache="compiled_rules",result="hit"}`,
+	} {
+		if !strings.Contains(rec.Body.String(), expected) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", expected, rec.Body.String())
+		}
+	}
+}
+
+func hasViolation(rep report.Report, title, file string) bool {
+	for _, violation := range rep.Violations {
+		if violation.Title == title && violation.Location.File == file {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+// Package stats collects statistics about the policies of a workspace, like the number of packages, rules and tests,
+// and the built-in functions used, to give an overview of the health of a policy library over time.
+package stats
+
+import (
+	"math"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/pkg/report"
+)
+
+// Stats holds the statistics of a workspace, as output by the stats command.
+type Stats struct {
+	Files    int `json:"files"`
+	Packages int `json:"packages"`
+	// Rules counts the rules and functions of the workspace, not including tests. Rules defined incrementally, or
+	// in multiple files of the same package, are counted once.
+	Rules int `json:"rules"`
+	Tests int `json:"tests"`
+	// AverageComplexity is the mean complexity of the definitions of rules and functions, where the complexity of a
+	// definition is the number of expressions it contains, including those of else branches and nested bodies.
+	AverageComplexity float64 `json:"average_complexity"`
+	// Builtins counts calls to each built-in function, not including operators like == or +, outside of tests.
+	Builtins map[string]int `json:"builtins"`
+	// Violations counts the violations reported by the linter in each category.
+	Violations map[string]int `json:"violations_by_category"`
+}
+
+// Collect returns the statistics of the provided modules, and the violations of the report.
+func Collect(modules map[string]*ast.Module, rep report.Report) Stats {
+	s := Stats{
+		Files:      len(modules),
+		Builtins:   make(map[string]int),
+		Violations: make(map[string]int),
+	}
+
+	packages := make(map[string]struct{})
+	rules := make(map[string]struct{})
+	tests := make(map[string]struct{})
+
+	definitions, expressions := 0, 0
+
+	for _, module := range modules {
+		packages[module.Package.Path.String()] = struct{}{}
+
+		for _, rule := range module.Rules {
+			path := module.Package.Path.Extend(rule.Head.Ref().GroundPrefix()).String()
+
+			if isTest(rule) {
+				tests[path] = struct{}{}
+
+				continue
+			}
+
+			rules[path] = struct{}{}
+			definitions++
+			expressions += complexity(rule)
+
+			countBuiltins(rule, s.Builtins)
+		}
+	}
+
+	s.Packages = len(packages)
+	s.Rules = len(rules)
+	s.Tests = len(tests)
+
+	if definitions > 0 {
+		s.AverageComplexity = math.Round(float64(expressions)/float64(definitions)*100) / 100
+	}
+
+	for _, violation := range rep.Violations {
+		s.Violations[violation.Category]++
+	}
+
+	return s
+}
+
+func isTest(rule *ast.Rule) bool {
+	return strings.HasPrefix(rule.Head.Ref()[0].Value.String(), "test_")
+}
+
+func complexity(rule *ast.Rule) int {
+	n := 0
+
+	ast.WalkExprs(rule, func(*ast.Expr) bool {
+		n++
+
+		return false
+	})
+
+	return n
+}
+
+func countBuiltins(rule *ast.Rule, counts map[string]int) {
+	count := func(operator *ast.Term) {
+		name := operator.Value.String()
+
+		if builtin, ok := ast.BuiltinMap[name]; ok && builtin.Infix == "" {
+			counts[name]++
+		}
+	}
+
+	ast.NewGenericVisitor(func(x any) bool {
+		switch x := x.(type) {
+		case *ast.Expr:
+			if x.IsCall() {
+				count(x.OperatorTerm())
+			}
+		case ast.Call:
+			if len(x) > 0 {
+				count(x[0])
+			}
+		}
+
+		return false
+	}).Walk(rule)
+}
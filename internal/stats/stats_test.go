@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	modules := map[string]*ast.Module{
+		"p.rego": parse.MustParseModule(`package p
+
+import rego.v1
+
+allow if {
+	startswith(input.path, "/api")
+	count(input.roles) > 0
+}
+
+deny contains "denied" if not allow
+
+deny contains "forbidden" if {
+	input.user == "anonymous"
+}
+
+f(x) := upper(x)
+`),
+		"p_test.rego": parse.MustParseModule(`package p_test
+
+import rego.v1
+
+test_allow if data.p.allow with input as {"path": "/api", "roles": ["admin"]}
+`),
+	}
+
+	rep := report.Report{Violations: []report.Violation{{Category: "style"}, {Category: "style"}, {Category: "bugs"}}}
+
+	s := Collect(modules, rep)
+
+	if s.Files != 2 || s.Packages != 2 || s.Rules != 3 || s.Tests != 1 {
+		t.Errorf("expected 2 files, 2 packages, 3 rules and 1 test, got %+v", s)
+	}
+
+	// 2 + 1 + 1 expressions, and the implicit true body of the function, in 4 definitions
+	if s.AverageComplexity != 1.25 {
+		t.Errorf("expected average complexity of 1.25, got %f", s.AverageComplexity)
+	}
+
+	// operators like > and == are not counted, and neither are the calls of tests
+	expectedBuiltins := map[string]int{"startswith": 1, "count": 1, "upper": 1}
+	if !maps.Equal(s.Builtins, expectedBuiltins) {
+		t.Errorf("expected builtins %v, got %v", expectedBuiltins, s.Builtins)
+	}
+
+	expectedViolations := map[string]int{"style": 2, "bugs": 1}
+	if !maps.Equal(s.Violations, expectedViolations) {
+		t.Errorf("expected violations %v, got %v", expectedViolations, s.Violations)
+	}
+}
@@ -0,0 +1,105 @@
+// Package tracing provides OpenTelemetry tracing of the steps of linting, like loading, parsing, evaluation and
+// reporting, for diagnosing slow lints with existing observability stacks. Spans are recorded using the global
+// tracer provider, and so are no-ops unless tracing has been set up, either by Setup, or by an application
+// embedding Regal.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/styrainc/regal/pkg/version"
+)
+
+const (
+	// ProtocolGRPC exports spans using OTLP over gRPC.
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP exports spans using OTLP over HTTP, encoded as protobuf.
+	ProtocolHTTP = "http/protobuf"
+
+	tracerName = "github.com/styrainc/regal"
+)
+
+// Start starts a span, as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, marking it as failed if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Setup sets up the global tracer provider to export spans using OTLP to the endpoint, which is a URL like
+// http://localhost:4318, where the scheme decides whether TLS is used (https) or not (http). The protocol is
+// either ProtocolHTTP (default) or ProtocolGRPC. The returned function exports any spans not yet exported, and
+// must be called before exiting.
+func Setup(ctx context.Context, endpoint, protocol string) (func(context.Context) error, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %s: %w", endpoint, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %s, expected URL like http://localhost:4318", endpoint)
+	}
+
+	var exporter *otlptrace.Exporter
+
+	switch protocol {
+	case "", ProtocolHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		if u.Path != "" && u.Path != "/" {
+			opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+		}
+
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(u.Host)}
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q, expected %s or %s", protocol, ProtocolHTTP, ProtocolGRPC)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "regal"),
+		attribute.String("service.version", version.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetupInvalid(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		endpoint string
+		protocol string
+	}{
+		"no scheme":        {"localhost:4318", ProtocolHTTP},
+		"unknown scheme":   {"ftp://localhost:4318", ProtocolHTTP},
+		"unknown protocol": {"http://localhost:4318", "http/json"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Setup(context.Background(), tc.endpoint, tc.protocol); err == nil {
+				t.Errorf("expected error for endpoint %s and protocol %s", tc.endpoint, tc.protocol)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package regalv1 contains the protobuf messages and gRPC service of the Regal API, as served by `regal serve`.
+package regalv1
+
+//go:generate protoc --go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:. regal.proto
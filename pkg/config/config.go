@@ -7,24 +7,35 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/open-policy-agent/opa/ast"
 
+	"github.com/styrainc/regal/internal/builtindb"
 	rio "github.com/styrainc/regal/internal/io"
 )
 
 const (
 	capabilitiesEngineOPA = "opa"
 	keyIgnore             = "ignore"
+	keyTraversal          = "traversal"
+	keyTimeout            = "timeout"
+	keyLanguageServer     = "language-server"
 	keyLevel              = "level"
+	keyMaxViolations      = "max-violations"
 )
 
 type Config struct {
 	Rules        map[string]Category `json:"rules"                  yaml:"rules"`
 	Ignore       Ignore              `json:"ignore,omitempty"       yaml:"ignore,omitempty"`
+	Traversal    Traversal           `json:"traversal,omitempty"    yaml:"traversal,omitempty"`
 	Capabilities *Capabilities       `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	// Timeout limits the time linting may take, unless overridden by the --timeout flag. Zero means no limit.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// LanguageServer configures features of the language server, not used when linting.
+	LanguageServer LanguageServer `json:"language-server,omitempty" yaml:"language-server,omitempty"`
 
 	// Defaults state is loaded from configuration under rules and so is not (un)marshalled
 	// in the same way.
@@ -40,10 +51,11 @@ type Defaults struct {
 	Categories map[string]Default
 }
 
-// Default represents global or category settings for rules,
-// currently only the level is supported.
+// Default represents global or category settings for rules, i.e. the level of the rules, and files ignored by them,
+// which are ignored in addition to those ignored by the configuration of each rule.
 type Default struct {
-	Level string `json:"level" yaml:"level"`
+	Level  string  `json:"level,omitempty"  yaml:"level,omitempty"`
+	Ignore *Ignore `json:"ignore,omitempty" yaml:"ignore,omitempty"`
 }
 
 func (d *Default) mapToConfig(result any) error {
@@ -57,6 +69,16 @@ func (d *Default) mapToConfig(result any) error {
 		d.Level = level
 	}
 
+	if ignore, ok := resultMap[keyIgnore]; ok {
+		var dst Ignore
+
+		if err := rio.JSONRoundTrip(ignore, &dst); err != nil {
+			return fmt.Errorf("unmarshalling default ignore failed: %w", err)
+		}
+
+		d.Ignore = &dst
+	}
+
 	return nil
 }
 
@@ -64,12 +86,82 @@ type Ignore struct {
 	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
 }
 
+// Traversal decides how the directories of the paths provided are walked to find the files to lint in them.
+type Traversal struct {
+	// FollowSymlinks has symbolic links to directories walked, like those of shared bundles, which are otherwise
+	// skipped. Symbolic links to files are always followed.
+	FollowSymlinks bool `json:"follow-symlinks,omitempty" yaml:"follow-symlinks,omitempty"`
+	// SkipHiddenDirectories has directories with names starting with a dot skipped, other than the paths provided.
+	SkipHiddenDirectories bool `json:"skip-hidden-directories,omitempty" yaml:"skip-hidden-directories,omitempty"`
+	// MaxDepth limits the depth of the directories walked below each path, where 1 has only the files directly in
+	// the path linted. Zero means no limit.
+	MaxDepth int `json:"max-depth,omitempty" yaml:"max-depth,omitempty"`
+	// NoGitignore has the paths ignored by .gitignore files walked too, which are otherwise skipped, like those of
+	// .regalignore files.
+	NoGitignore bool `json:"no-gitignore,omitempty" yaml:"no-gitignore,omitempty"`
+}
+
+// LanguageServer configures features of the language server.
+type LanguageServer struct {
+	// InputPath is the path of the input document, relative to the root of the project, used by features evaluating
+	// policies, like completions of input. When not set, the input.json or input.yaml file nearest to the file edited
+	// is used, or that at the root of the workspace.
+	InputPath string `json:"input-path,omitempty" yaml:"input-path,omitempty"`
+}
+
+// Duration is a time.Duration (un)marshalled in the format of time.ParseDuration, like 30s or 2m.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String()) //nolint:wrapcheck
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration was not a string: %w", err)
+	}
+
+	return d.parse(s)
+}
+
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("duration was not a string: %w", err)
+	}
+
+	return d.parse(s)
+}
+
+func (d *Duration) parse(s string) error {
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse duration: %w", err)
+	}
+
+	if duration < 0 {
+		return fmt.Errorf("duration %s must not be negative", s)
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
+
 type ExtraAttributes map[string]any
 
 type Rule struct {
 	Level  string
 	Ignore *Ignore `json:"ignore,omitempty" yaml:"ignore,omitempty"`
-	Extra  ExtraAttributes
+	// MaxViolations is the budget of violations of the rule, which only fail linting once there are more of them,
+	// for the number of violations to be brought down over time. Unset, any violation fails linting.
+	MaxViolations *int
+	Extra         ExtraAttributes
 }
 
 type Capabilities struct {
@@ -84,7 +176,8 @@ type Decl struct {
 }
 
 type Builtin struct {
-	Decl Decl `json:"decl" yaml:"decl"`
+	Decl       Decl `json:"decl"                 yaml:"decl"`
+	Deprecated bool `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 }
 
 const (
@@ -156,6 +249,47 @@ func FindConfig(path string) (*os.File, error) {
 	return os.Open(filepath.Join(regalDir.Name(), rio.PathSeparator, configFileName)) //nolint:wrapcheck
 }
 
+// ErrNotFound is returned by Load when no configuration file is found for the path.
+var ErrNotFound = errors.New("no configuration file found")
+
+// Load reads the configuration from the .regal/config.yaml file of the .regal directory nearest to path, like the
+// CLI does, for applications linting with the same configuration to provide it to the linter with WithUserConfig.
+// ErrNotFound is returned when there's no such file, in which case the default configuration applies.
+func Load(path string) (Config, error) {
+	regalDir, err := FindRegalDirectory(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	defer rio.CloseFileIgnore(regalDir)
+
+	configFile := filepath.Join(regalDir.Name(), configFileName)
+
+	if _, err := os.Stat(configFile); errors.Is(err, os.ErrNotExist) {
+		return Config{}, fmt.Errorf("%w in %s", ErrNotFound, regalDir.Name())
+	}
+
+	return FromFile(configFile)
+}
+
+// FromFile reads the configuration from the file at path.
+func FromFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to open config file: %w", err)
+	}
+
+	defer rio.CloseFileIgnore(f)
+
+	var conf Config
+
+	if err := yaml.NewDecoder(f).Decode(&conf); err != nil {
+		return Config{}, fmt.Errorf("failed to decode config from %s: %w", path, err)
+	}
+
+	return conf, nil
+}
+
 func FromMap(confMap map[string]any) (Config, error) {
 	var conf Config
 
@@ -176,7 +310,7 @@ func (config Config) MarshalYAML() (any, error) {
 	}
 
 	// place the global defaults at the top level under rules
-	if config.Defaults.Global.Level != "" {
+	if config.Defaults.Global.Level != "" || config.Defaults.Global.Ignore != nil {
 		r, ok := unstructuredConfig["rules"].(map[string]any)
 		if !ok {
 			return nil, errors.New("rules in config were not a map")
@@ -204,6 +338,18 @@ func (config Config) MarshalYAML() (any, error) {
 		delete(unstructuredConfig, keyIgnore)
 	}
 
+	if config.Traversal == (Traversal{}) {
+		delete(unstructuredConfig, keyTraversal)
+	}
+
+	if config.Timeout == 0 {
+		delete(unstructuredConfig, keyTimeout)
+	}
+
+	if config.LanguageServer == (LanguageServer{}) {
+		delete(unstructuredConfig, keyLanguageServer)
+	}
+
 	return unstructuredConfig, nil
 }
 
@@ -212,9 +358,12 @@ func (config Config) MarshalYAML() (any, error) {
 type marshallingIntermediary struct {
 	// rules are unmarshalled as any since the defaulting needs to be extracted from here
 	// and configured elsewhere in the struct.
-	Rules        map[string]any `yaml:"rules"`
-	Ignore       Ignore         `yaml:"ignore"`
-	Capabilities struct {
+	Rules          map[string]any `yaml:"rules"`
+	Ignore         Ignore         `yaml:"ignore"`
+	Traversal      Traversal      `yaml:"traversal"`
+	Timeout        Duration       `yaml:"timeout"`
+	LanguageServer LanguageServer `yaml:"language-server"`
+	Capabilities   struct {
 		From struct {
 			Engine  string `yaml:"engine"`
 			Version string `yaml:"version"`
@@ -250,6 +399,9 @@ func (config *Config) UnmarshalYAML(value *yaml.Node) error {
 	}
 
 	config.Ignore = result.Ignore
+	config.Traversal = result.Traversal
+	config.Timeout = result.Timeout
+	config.LanguageServer = result.LanguageServer
 
 	capabilitiesFile := result.Capabilities.From.File
 	capabilitiesEngine := result.Capabilities.From.Engine
@@ -394,7 +546,12 @@ func fromOPABuiltin(builtin ast.Builtin) *Builtin {
 		args[i] = arg.String()
 	}
 
-	rb := &Builtin{Decl: Decl{Args: args}}
+	rb := &Builtin{Decl: Decl{Args: args}, Deprecated: builtin.IsDeprecated()}
+
+	// capabilities loaded from JSON don't tell whether a built-in function is deprecated
+	if known, ok := builtindb.Default().Get(builtin.Name); ok {
+		rb.Deprecated = rb.Deprecated || known.Deprecated
+	}
 
 	if builtin.Decl != nil && builtin.Decl.Result() != nil {
 		// internal.print has no result
@@ -465,8 +622,12 @@ func (rule Rule) MarshalYAML() (interface{}, error) {
 		result[keyIgnore] = rule.Ignore
 	}
 
+	if rule.MaxViolations != nil {
+		result[keyMaxViolations] = *rule.MaxViolations
+	}
+
 	for key, val := range rule.Extra {
-		if key != keyIgnore && key != keyLevel {
+		if key != keyIgnore && key != keyLevel && key != keyMaxViolations {
 			result[key] = val
 		}
 	}
@@ -508,10 +669,22 @@ func (rule *Rule) mapToConfig(result any) error {
 		rule.Ignore = &dst
 	}
 
+	if maxViolations, ok := ruleMap[keyMaxViolations]; ok {
+		var dst int
+
+		// numbers are float64 when unmarshalled from JSON, and int when from YAML
+		if err := rio.JSONRoundTrip(maxViolations, &dst); err != nil || dst < 0 {
+			return fmt.Errorf("%s must be a non-negative integer, got %v", keyMaxViolations, maxViolations)
+		}
+
+		rule.MaxViolations = &dst
+	}
+
 	rule.Extra = ruleMap
 
 	delete(rule.Extra, keyLevel)
 	delete(rule.Extra, keyIgnore)
+	delete(rule.Extra, keyMaxViolations)
 
 	return nil
 }
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -81,6 +85,44 @@ func TestFindConfig(t *testing.T) {
 	})
 }
 
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	fs := map[string]string{
+		"/foo/bar/baz/p.rego": "",
+		"/foo/bar/.regal/config.yaml": `rules:
+  style:
+    line-length:
+      level: warning
+      max-line-length: 100
+`,
+	}
+
+	test.WithTempFS(fs, func(root string) {
+		conf, err := Load(filepath.Join(root, "/foo/bar/baz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rule := conf.Rules["style"]["line-length"]
+		if rule.Level != "warning" || rule.Extra["max-line-length"] != 100 {
+			t.Errorf("expected line-length rule loaded from config, got %+v", rule)
+		}
+	})
+
+	test.WithTempFS(map[string]string{"/foo/p.rego": "", "/foo/.regal/rules/.keep": ""}, func(root string) {
+		if _, err := Load(filepath.Join(root, "foo")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound for .regal directory without config file, got %v", err)
+		}
+	})
+
+	test.WithTempFS(map[string]string{"/foo/p.rego": ""}, func(root string) {
+		if _, err := Load(filepath.Join(root, "foo")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound without .regal directory, got %v", err)
+		}
+	})
+}
+
 func TestMarshalConfig(t *testing.T) {
 	t.Parallel()
 
@@ -132,6 +174,9 @@ rules:
   bugs:
     default:
       level: error
+      ignore:
+        files:
+          - "**/*_gen.rego"
     constant-condition:
       level: ignore
   testing:
@@ -177,6 +222,11 @@ rules:
 	if roundTrippedConfig.Defaults.Categories["bugs"].Level != levelError {
 		t.Errorf("expected bugs default to be level error")
 	}
+
+	if ignore := roundTrippedConfig.Defaults.Categories["bugs"].Ignore; ignore == nil ||
+		!slices.Equal(ignore.Files, []string{"**/*_gen.rego"}) {
+		t.Errorf("expected bugs default to ignore files **/*_gen.rego, got %v", ignore)
+	}
 }
 
 func TestUnmarshalConfig(t *testing.T) {
@@ -283,6 +333,85 @@ capabilities:
 	}
 }
 
+func TestUnmarshalConfigMaxViolations(t *testing.T) {
+	t.Parallel()
+
+	bs := []byte(`rules:
+  style:
+    line-length:
+      level: error
+      max-violations: 50
+`)
+
+	var conf Config
+
+	if err := yaml.Unmarshal(bs, &conf); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := conf.Rules["style"]["line-length"]
+
+	if rule.MaxViolations == nil || *rule.MaxViolations != 50 {
+		t.Fatalf("expected max-violations to be 50, got %v", rule.MaxViolations)
+	}
+
+	if _, ok := rule.Extra["max-violations"]; ok {
+		t.Errorf("expected extra attribute 'max-violations' to be removed")
+	}
+
+	// numbers unmarshalled from JSON are float64, and must be read as well
+	var roundTripped Rule
+	if err := rio.JSONRoundTrip(rule, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+
+	if roundTripped.MaxViolations == nil || *roundTripped.MaxViolations != 50 {
+		t.Errorf("expected max-violations to be 50 after JSON round trip, got %v", roundTripped.MaxViolations)
+	}
+
+	if err := yaml.Unmarshal([]byte("rules:\n  style:\n    line-length:\n      max-violations: -1\n"), &conf); err == nil {
+		t.Errorf("expected error for negative max-violations")
+	}
+}
+
+func TestUnmarshalConfigTimeout(t *testing.T) {
+	t.Parallel()
+
+	var conf Config
+
+	if err := yaml.Unmarshal([]byte("rules: {}\ntimeout: 1m30s\n"), &conf); err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Timeout != Duration(90*time.Second) {
+		t.Fatalf("expected timeout to be 1m30s, got %v", time.Duration(conf.Timeout))
+	}
+
+	bs, err := yaml.Marshal(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(bs), "timeout: 1m30s") {
+		t.Errorf("expected marshalled config to contain timeout, got:\n%s", bs)
+	}
+
+	var roundTripped Config
+	if err := rio.JSONRoundTrip(conf, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+
+	if roundTripped.Timeout != conf.Timeout {
+		t.Errorf("expected timeout to be 1m30s after JSON round trip, got %v", time.Duration(roundTripped.Timeout))
+	}
+
+	for _, invalid := range []string{"30", "-5s"} {
+		if err := yaml.Unmarshal([]byte("rules: {}\ntimeout: "+invalid+"\n"), &conf); err == nil {
+			t.Errorf("expected error for timeout %s", invalid)
+		}
+	}
+}
+
 func TestUnmarshalConfigWithBuiltinsFile(t *testing.T) {
 	t.Parallel()
 
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,40 +14,115 @@ import (
 )
 
 func FilterIgnoredPaths(paths, ignore []string, checkFileExists bool, rootDir string) ([]string, error) {
+	if checkFileExists {
+		return FilterIgnoredPathsWithTraversal(paths, ignore, rootDir, Traversal{})
+	}
+
 	// if set, rootDir is normalized to end with a platform appropriate separator
 	if rootDir != "" && !strings.HasSuffix(rootDir, string(filepath.Separator)) {
 		rootDir += string(filepath.Separator)
 	}
 
-	if checkFileExists {
-		filtered := make([]string, 0, len(paths))
+	if len(ignore) == 0 {
+		return paths, nil
+	}
+
+	return filterPaths(paths, ignore, rootDir)
+}
+
+// FilterIgnoredPathsWithTraversal returns the policy files found in paths, with directories walked as decided by
+// traversal, leaving out those ignored by .regalignore and .gitignore files, or the ignore patterns provided.
+func FilterIgnoredPathsWithTraversal(paths, ignore []string, rootDir string, traversal Traversal) ([]string, error) {
+	if rootDir != "" && !strings.HasSuffix(rootDir, string(filepath.Separator)) {
+		rootDir += string(filepath.Separator)
+	}
+
+	filtered, err := walkUnignoredFiles(paths, traversal, func(path string) bool {
+		return strings.HasSuffix(path, bundle.RegoExt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return filterPaths(filtered, ignore, rootDir)
+}
+
+// FilterIgnoredDataPaths returns the data files, i.e. data.json and data.yaml files, found in paths. Like policy
+// files, data files ignored by .regalignore files or the ignore patterns provided are left out.
+func FilterIgnoredDataPaths(paths, ignore []string, rootDir string) ([]string, error) {
+	if rootDir != "" && !strings.HasSuffix(rootDir, string(filepath.Separator)) {
+		rootDir += string(filepath.Separator)
+	}
 
-		if err := walkPaths(paths, func(path string, info os.DirEntry, err error) error {
-			if info.IsDir() && (info.Name() == ".git" || info.Name() == ".idea") {
+	filtered, err := walkUnignoredFiles(paths, Traversal{}, IsDataFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterPaths(filtered, ignore, rootDir)
+}
+
+// IsDataFile returns true if the file at path is a data file, named like OPA expects data files in bundles.
+func IsDataFile(path string) bool {
+	switch filepath.Base(path) {
+	case "data.json", "data.yaml", "data.yml":
+		return true
+	}
+
+	return false
+}
+
+// walkUnignoredFiles returns the files in paths for which include returns true, skipping those ignored by
+// .regalignore and .gitignore files, and the directories of version control and IDEs.
+func walkUnignoredFiles(paths []string, traversal Traversal, include func(path string) bool) ([]string, error) {
+	filtered := make([]string, 0, len(paths))
+
+	ignoreFiles := NewIgnoreFiles(traversal)
+
+	for _, path := range paths {
+		if err := ignoreFiles.LoadParents(path); err != nil {
+			return nil, fmt.Errorf("failed to load ignore files: %w", err)
+		}
+	}
+
+	if err := walkPaths(paths, traversal, func(path string, info os.DirEntry, err error) error {
+		if info.IsDir() && (info.Name() == ".git" || info.Name() == ".idea") {
+			return filepath.SkipDir
+		}
+
+		ignored, ignoreErr := ignoreFiles.Ignored(path, info.IsDir())
+		if ignoreErr != nil {
+			return ignoreErr
+		}
+
+		if info.IsDir() {
+			if ignored {
 				return filepath.SkipDir
 			}
-			if !info.IsDir() && strings.HasSuffix(path, bundle.RegoExt) {
-				filtered = append(filtered, path)
-			}
 
-			return err
-		}); err != nil {
-			return nil, fmt.Errorf("failed to filter paths:\n%w", err)
+			// directories are walked before their contents, so any ignore file applies to the paths below
+			if loadErr := ignoreFiles.Load(path); loadErr != nil {
+				return loadErr
+			}
 		}
 
-		return filterPaths(filtered, ignore, rootDir)
-	}
+		if !info.IsDir() && !ignored && include(path) {
+			filtered = append(filtered, path)
+		}
 
-	if len(ignore) == 0 {
-		return paths, nil
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to filter paths:\n%w", err)
 	}
 
-	return filterPaths(paths, ignore, rootDir)
+	return filtered, nil
 }
 
-func walkPaths(paths []string, filter func(path string, info os.DirEntry, err error) error) error {
+func walkPaths(paths []string, traversal Traversal, filter fs.WalkDirFunc) error {
 	var errs error
 
+	w := walker{traversal: traversal, filter: filter, visited: make(map[string]struct{})}
+
 	for _, path := range paths {
 		// We need to stat the initial set of paths, as filepath.WalkDir
 		// will panic on non-existent paths.
@@ -57,7 +133,18 @@ func walkPaths(paths []string, filter func(path string, info os.DirEntry, err er
 			continue
 		}
 
-		if err := filepath.WalkDir(path, filter); err != nil {
+		dir := path
+
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			w.visited[resolved] = struct{}{}
+
+			// paths provided which are symbolic links to directories are walked too, when following links
+			if traversal.FollowSymlinks {
+				dir = resolved
+			}
+		}
+
+		if err := w.walk(path, dir, 0); err != nil {
 			errs = errors.Join(errs, err)
 		}
 	}
@@ -65,6 +152,88 @@ func walkPaths(paths []string, filter func(path string, info os.DirEntry, err er
 	return errs
 }
 
+// walker walks directories as decided by its traversal, calling filter for each file and directory walked.
+type walker struct {
+	traversal Traversal
+	filter    fs.WalkDirFunc
+	// visited holds the resolved paths of the directories walked, as provided or through symbolic links, for
+	// symbolic links to directories already walked, like those to a parent, not to be walked again
+	visited map[string]struct{}
+}
+
+// walk walks the directory at dir, reporting the paths in it as relative to path, which differs from dir when path is
+// a symbolic link to dir. Depth is that of path below the path provided.
+func (w walker) walk(path, dir string, depth int) error {
+	return filepath.WalkDir(dir, func(walked string, d fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(dir, walked)
+		if relErr != nil {
+			return relErr
+		}
+
+		if dir != path {
+			walked = filepath.Join(path, rel)
+		}
+
+		if err != nil {
+			return w.filter(walked, d, err)
+		}
+
+		// the directory walked itself was decided on by the caller
+		if rel == "." {
+			return w.filter(walked, d, err)
+		}
+
+		walkedDepth := depth + strings.Count(rel, string(filepath.Separator)) + 1
+
+		isDir := d.IsDir()
+		isSymlink := d.Type()&fs.ModeSymlink != 0
+
+		if isSymlink && w.traversal.FollowSymlinks {
+			info, statErr := os.Stat(walked)
+			isDir = statErr == nil && info.IsDir()
+		}
+
+		if isDir && w.skipDir(d.Name(), walkedDepth) {
+			if isSymlink {
+				return nil
+			}
+
+			return filepath.SkipDir
+		}
+
+		if isDir && isSymlink {
+			return w.walkSymlink(walked, walkedDepth)
+		}
+
+		return w.filter(walked, d, err)
+	})
+}
+
+// skipDir returns whether the directory named name, at depth below the path provided, is skipped.
+func (w walker) skipDir(name string, depth int) bool {
+	if w.traversal.SkipHiddenDirectories && strings.HasPrefix(name, ".") {
+		return true
+	}
+
+	return w.traversal.MaxDepth > 0 && depth >= w.traversal.MaxDepth
+}
+
+// walkSymlink walks the directory linked to by the symbolic link at path, unless already walked.
+func (w walker) walkSymlink(path string, depth int) error {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve symbolic link %s: %w", path, err)
+	}
+
+	if _, ok := w.visited[resolved]; ok {
+		return nil
+	}
+
+	w.visited[resolved] = struct{}{}
+
+	return w.walk(path, resolved, depth)
+}
+
 func filterPaths(policyPaths []string, ignore []string, rootDir string) ([]string, error) {
 	filtered := make([]string, 0, len(policyPaths))
 
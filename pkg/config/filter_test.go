@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"testing"
 )
@@ -109,3 +112,133 @@ func TestFilterIgnoredPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterIgnoredDataPaths(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	files := map[string]string{
+		".regalignore":          "vendor/\n",
+		"data.json":             "{}",
+		"acl/data.yaml":         "",
+		"acl/roles/data.yml":    "",
+		"acl/policy.rego":       "",
+		"acl/other.json":        "{}",
+		"vendor/lib/data.json":  "{}",
+		".git/data.json":        "{}",
+		"schemas/acl/data.json": "{}",
+	}
+
+	for file, contents := range files {
+		path := filepath.Join(root, file)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filtered, err := FilterIgnoredDataPaths([]string{root}, []string{"schemas/"}, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]string, 0, len(filtered))
+
+	for _, path := range filtered {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual = append(actual, filepath.ToSlash(rel))
+	}
+
+	slices.Sort(actual)
+
+	expected := []string{"acl/data.yaml", "acl/roles/data.yml", "data.json"}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestFilterIgnoredPathsWithTraversal(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	policy := filepath.Join(root, "policy")
+
+	for _, file := range []string{"policy/p.rego", "policy/a/b/p.rego", "policy/.hidden/p.rego", "shared/p.rego"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte("package p\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a link to a directory outside the path linted, and one to a parent, which must not be walked in circles
+	if err := os.Symlink(filepath.Join(root, "shared"), filepath.Join(policy, "shared")); err != nil {
+		t.Skipf("failed to create symbolic link: %v", err)
+	}
+
+	if err := os.Symlink(policy, filepath.Join(policy, "a", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		traversal Traversal
+		expected  []string
+	}{
+		"default": {
+			expected: []string{".hidden/p.rego", "a/b/p.rego", "p.rego"},
+		},
+		"follow symlinks": {
+			traversal: Traversal{FollowSymlinks: true},
+			expected:  []string{".hidden/p.rego", "a/b/p.rego", "p.rego", "shared/p.rego"},
+		},
+		"skip hidden directories": {
+			traversal: Traversal{SkipHiddenDirectories: true},
+			expected:  []string{"a/b/p.rego", "p.rego"},
+		},
+		"max depth": {
+			traversal: Traversal{MaxDepth: 2, FollowSymlinks: true},
+			expected:  []string{".hidden/p.rego", "p.rego", "shared/p.rego"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered, err := FilterIgnoredPathsWithTraversal([]string{policy}, nil, "", tc.traversal)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make([]string, 0, len(filtered))
+
+			for _, path := range filtered {
+				rel, err := filepath.Rel(policy, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual = append(actual, filepath.ToSlash(rel))
+			}
+
+			slices.Sort(actual)
+
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the name of files listing paths to ignore, using the syntax of .gitignore files. Patterns apply
+// to the paths of the directory holding the file, and of its subdirectories, where more .regalignore files may be
+// placed, with patterns taking precedence over those of files in parent directories.
+const IgnoreFileName = ".regalignore"
+
+// GitignoreFileName is the name of the ignore files of Git, which are loaded along with .regalignore files, unless
+// opted out of, for files like those of vendor/ and node_modules/ directories to be ignored by Regal too. Patterns of
+// .regalignore files take precedence over those of the .gitignore file of the same directory, for files ignored by
+// Git to be linted all the same, like with !vendor/.
+const GitignoreFileName = ".gitignore"
+
+type ignorePattern struct {
+	pattern string
+	negate  bool
+}
+
+// IgnoreFiles holds the patterns of the ignore files loaded, by the absolute path of the directory holding them.
+type IgnoreFiles struct {
+	patterns map[string][]ignorePattern
+	// names of the ignore files loaded from each directory, in order of precedence, lowest first
+	names []string
+}
+
+// NewIgnoreFiles creates a new IgnoreFiles, with no ignore files loaded. Both .gitignore and .regalignore files are
+// loaded from directories, unless .gitignore files are opted out of by the traversal.
+func NewIgnoreFiles(traversal Traversal) *IgnoreFiles {
+	names := []string{GitignoreFileName, IgnoreFileName}
+	if traversal.NoGitignore {
+		names = []string{IgnoreFileName}
+	}
+
+	return &IgnoreFiles{patterns: make(map[string][]ignorePattern), names: names}
+}
+
+// Load loads the ignore files of a directory, if it has any. Directories already loaded are not loaded again.
+func (i *IgnoreFiles) Load(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of %s: %w", dir, err)
+	}
+
+	if _, ok := i.patterns[dir]; ok {
+		return nil
+	}
+
+	var patterns []ignorePattern
+
+	for _, name := range i.names {
+		filePatterns, err := readIgnoreFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		patterns = append(patterns, filePatterns...)
+	}
+
+	i.patterns[dir] = patterns
+
+	return nil
+}
+
+// readIgnoreFile reads the patterns of the ignore file at path, returning none if there's no such file.
+func readIgnoreFile(path string) ([]ignorePattern, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ignore file: %w", err)
+	}
+
+	defer file.Close()
+
+	var patterns []ignorePattern
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if pattern, ok := parseIgnorePattern(scanner.Text()); ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", path, err)
+	}
+
+	return patterns, nil
+}
+
+// LoadParents loads the ignore files of the directories from the root of the workspace holding path, down to the
+// directory holding path, or path itself if a directory. The root of the workspace is the closest directory holding
+// a .regal or .git directory. If there is none, only the ignore file of the directory holding path is loaded.
+func (i *IgnoreFiles) LoadParents(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of %s: %w", path, err)
+	}
+
+	dir := path
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	dirs := []string{dir}
+
+	for current := dir; !isWorkspaceRoot(current); {
+		parent := filepath.Dir(current)
+		if parent == current {
+			// no workspace root found
+			dirs = dirs[:1]
+
+			break
+		}
+
+		dirs = append(dirs, parent)
+		current = parent
+	}
+
+	for _, d := range dirs {
+		if err := i.Load(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Ignored returns whether the path, a directory or not, is ignored by the patterns of the ignore files loaded. The
+// patterns of each file are applied in order, with later patterns, and those of files in subdirectories, taking
+// precedence, so that a path ignored may be included again by a negated pattern, like !policy.rego.
+func (i *IgnoreFiles) Ignored(path string, isDir bool) (bool, error) {
+	if i == nil || len(i.patterns) == 0 {
+		return false, nil
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to get absolute path of %s: %w", path, err)
+	}
+
+	// directories holding the path, from the closest to the most distant
+	var dirs []string
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	ignored := false
+
+	for j := len(dirs) - 1; j >= 0; j-- {
+		patterns := i.patterns[dirs[j]]
+		if len(patterns) == 0 {
+			continue
+		}
+
+		rel, err := filepath.Rel(dirs[j], path)
+		if err != nil {
+			return false, fmt.Errorf("failed to get relative path of %s: %w", path, err)
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		// trailing slashes in patterns match directories only, which is imitated by matching directories with one
+		if isDir {
+			rel += "/"
+		}
+
+		for _, p := range patterns {
+			excluded, err := excludeFile(p.pattern, rel, "")
+			if err != nil {
+				return false, err
+			}
+
+			if excluded {
+				ignored = !p.negate
+			}
+		}
+	}
+
+	return ignored, nil
+}
+
+// parseIgnorePattern parses a line of an ignore file, returning false for blank lines and comments.
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	var p ignorePattern
+
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+
+	// a leading backslash escapes a literal # or !
+	line = strings.TrimPrefix(line, "\\")
+
+	if line == "" || line == "/" {
+		return ignorePattern{}, false
+	}
+
+	p.pattern = line
+
+	return p, true
+}
+
+func isWorkspaceRoot(dir string) bool {
+	for _, marker := range []string{regalDirName, ".git"} {
+		if info, err := os.Stat(filepath.Join(dir, marker)); err == nil && info.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
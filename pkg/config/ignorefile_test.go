@@ -0,0 +1,190 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFilterIgnoredPathsWithIgnoreFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	files := map[string]string{
+		".regalignore":               "# generated policies\n*_gen.rego\nvendor/\n",
+		"policy/.regalignore":        "!keep_gen.rego\nlocal.rego\n",
+		"policy/allow.rego":          "",
+		"policy/allow_gen.rego":      "",
+		"policy/keep_gen.rego":       "",
+		"policy/local.rego":          "",
+		"policy/nested/local.rego":   "",
+		"other/local.rego":           "",
+		"vendor/lib.rego":            "",
+		"other/vendor/nested/x.rego": "",
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// marks the root of the workspace, for ignore files of parent directories to be found
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		path     string
+		expected []string
+	}{
+		"workspace root": {
+			path:     root,
+			expected: []string{"other/local.rego", "policy/allow.rego", "policy/keep_gen.rego"},
+		},
+		"subdirectory, with ignore file of parent directory": {
+			path:     filepath.Join(root, "policy"),
+			expected: []string{"policy/allow.rego", "policy/keep_gen.rego"},
+		},
+		"ignored file provided": {
+			path:     filepath.Join(root, "policy", "allow_gen.rego"),
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered, err := FilterIgnoredPaths([]string{tc.path}, nil, true, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make([]string, 0, len(filtered))
+
+			for _, path := range filtered {
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual = append(actual, filepath.ToSlash(rel))
+			}
+
+			slices.Sort(actual)
+
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFilterIgnoredPathsWithGitignore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":                "node_modules/\nvendor/\n/build/\n",
+		".regalignore":              "!vendor/\n",
+		"policy/allow.rego":         "",
+		"node_modules/pkg/lib.rego": "",
+		"vendor/lib.rego":           "",
+		"build/out.rego":            "",
+		"policy/build/keep.rego":    "",
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := map[string]struct {
+		traversal Traversal
+		expected  []string
+	}{
+		"gitignore honored, with .regalignore taking precedence": {
+			expected: []string{"policy/allow.rego", "policy/build/keep.rego", "vendor/lib.rego"},
+		},
+		"gitignore opted out of": {
+			traversal: Traversal{NoGitignore: true},
+			expected: []string{
+				"build/out.rego", "node_modules/pkg/lib.rego", "policy/allow.rego", "policy/build/keep.rego",
+				"vendor/lib.rego",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered, err := FilterIgnoredPathsWithTraversal([]string{root}, nil, "", tc.traversal)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make([]string, 0, len(filtered))
+
+			for _, path := range filtered {
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual = append(actual, filepath.ToSlash(rel))
+			}
+
+			slices.Sort(actual)
+
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseIgnorePattern(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line     string
+		expected ignorePattern
+		ok       bool
+	}{
+		"blank":            {line: "  ", ok: false},
+		"comment":          {line: "# comment", ok: false},
+		"pattern":          {line: "*.rego  ", expected: ignorePattern{pattern: "*.rego"}, ok: true},
+		"negated":          {line: "!keep.rego", expected: ignorePattern{pattern: "keep.rego", negate: true}, ok: true},
+		"escaped comment":  {line: `\#file.rego`, expected: ignorePattern{pattern: "#file.rego"}, ok: true},
+		"escaped negation": {line: `\!file.rego`, expected: ignorePattern{pattern: "!file.rego"}, ok: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, ok := parseIgnorePattern(tc.line)
+			if ok != tc.ok || actual != tc.expected {
+				t.Errorf("expected %v (%t), got %v (%t)", tc.expected, tc.ok, actual, ok)
+			}
+		})
+	}
+}
@@ -1,22 +1,78 @@
 package fixer
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 
 	"github.com/open-policy-agent/opa/ast"
 
 	"github.com/styrainc/regal/pkg/fixer/fileprovider"
 	"github.com/styrainc/regal/pkg/fixer/fixes"
 	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/report"
 )
 
+// Fix applies the default fixes for the violations provided, as reported by the linter, to the source of a policy,
+// and returns the fixed source along with the violations fixed. Unlike Fixer.Fix, no linter is needed, which allows
+// applications like CI bots and editor integrations to apply fixes for violations already at hand. See
+// Fixer.FixViolations for details.
+func Fix(
+	ctx context.Context,
+	source fixes.FixCandidate,
+	violations []report.Violation,
+) ([]byte, []report.Violation, error) {
+	f := NewFixer()
+	f.RegisterFixes(fixes.NewDefaultFixes()...)
+
+	return f.FixViolations(ctx, source, violations)
+}
+
 func NewFixer() *Fixer {
 	return &Fixer{}
 }
 
 type Fixer struct {
 	registeredFixes map[string]any
+	migrateRegoV1   bool
+	prompt          Prompt
+}
+
+// Decision is the decision made on a fix proposed by the Fixer in interactive mode.
+type Decision int
+
+const (
+	// Accept applies the fix.
+	Accept Decision = iota
+	// Skip leaves the violation unfixed.
+	Skip
+	// AcceptAll applies the fix, along with the fixes of all further violations of the same rule.
+	AcceptAll
+	// Quit leaves the violation, and all further violations, unfixed.
+	Quit
+)
+
+// Prompt decides whether to apply a fix proposed by the Fixer, given the violation fixed, and the contents of its
+// file before and after the fix.
+type Prompt func(violation report.Violation, before, after []byte) (Decision, error)
+
+// WithRegoV1Migration sets whether all files should be migrated to Rego v1 before fixing violations, regardless
+// of whether the use-rego-v1 rule is enabled.
+func (f *Fixer) WithRegoV1Migration(enabled bool) *Fixer {
+	f.migrateRegoV1 = enabled
+
+	return f
+}
+
+// WithPrompt sets the prompt deciding on each fix before it's applied, for fixing in interactive mode. Violations
+// skipped aren't proposed again. Without a prompt, all fixes are applied.
+func (f *Fixer) WithPrompt(prompt Prompt) *Fixer {
+	f.prompt = prompt
+
+	return f
 }
 
 func (f *Fixer) RegisterFixes(fixes ...fixes.Fix) {
@@ -58,6 +114,17 @@ func (f *Fixer) Fix(ctx context.Context, l *linter.Linter, fp fileprovider.FileP
 	}
 
 	fixReport := NewReport()
+	decided := newDecisions(f.prompt)
+
+	if f.migrateRegoV1 {
+		if err := migrateRegoV1(fp, fixReport, decided); err != nil {
+			return nil, err
+		}
+	}
+
+	// the contents each file has had, to tell when fixes undo each other, like opa-fmt indenting with tabs, and
+	// indentation configured to indent with spaces, which would otherwise have them applied in turn forever
+	seen := make(map[string]struct{})
 
 	for {
 		fixMadeInIteration := false
@@ -87,6 +154,8 @@ func (f *Fixer) Fix(ctx context.Context, l *linter.Linter, fp fileprovider.FileP
 				return nil, fmt.Errorf("failed to get file %s: %w", violation.Location.File, err)
 			}
 
+			seen[violation.Location.File+"\x00"+string(fc)] = struct{}{}
+
 			fixCandidate := fixes.FixCandidate{
 				Filename: violation.Location.File,
 				Contents: fc,
@@ -100,6 +169,13 @@ func (f *Fixer) Fix(ctx context.Context, l *linter.Linter, fp fileprovider.FileP
 					},
 				},
 			})
+			var regoV1Err *fixes.RegoV1Error
+			if errors.As(err, &regoV1Err) {
+				fixReport.SetFileIssues(violation.Location.File, regoV1Err.Issues)
+
+				continue
+			}
+
 			if err != nil {
 				return nil, fmt.Errorf("failed to fix %s: %w", violation.Location.File, err)
 			}
@@ -108,6 +184,20 @@ func (f *Fixer) Fix(ctx context.Context, l *linter.Linter, fp fileprovider.FileP
 				// Note: Only one content update fix result is currently supported
 				fixResult := fixResults[0]
 
+				if _, ok := seen[violation.Location.File+"\x00"+string(fixResult.Contents)]; ok {
+					return nil, fmt.Errorf("fix of %s violation in %s undoes an earlier fix, as fixes of rules enabled "+
+						"undo each other", violation.Title, violation.Location.File)
+				}
+
+				apply, err := decided.apply(violation, fc, fixResult.Contents)
+				if err != nil {
+					return nil, err
+				}
+
+				if !apply {
+					continue
+				}
+
 				err = fp.PutFile(violation.Location.File, fixResult.Contents)
 				if err != nil {
 					return nil, fmt.Errorf("failed to write fixed content to file %s: %w", violation.Location.File, err)
@@ -119,10 +209,196 @@ func (f *Fixer) Fix(ctx context.Context, l *linter.Linter, fp fileprovider.FileP
 			}
 		}
 
-		if !fixMadeInIteration {
+		if !fixMadeInIteration || decided.quit {
 			break
 		}
 	}
 
 	return fixReport, nil
 }
+
+// FixViolations applies the registered fixes for the violations provided to the source of a policy, and returns the
+// fixed source along with the violations fixed. Violations without a registered fix, or reported for other files,
+// are ignored. Violations are fixed from the last location in the policy to the first, so that fixing one doesn't
+// move the location of those yet to be fixed, while fixes like opa-fmt, which rewrite the whole policy, are reported
+// at its package declaration, and so fixed after those of the rules below it. If a violation of use-rego-v1 can't
+// be fixed, the remaining violations are still fixed, and a *fixes.RegoV1Error is returned along with the result.
+func (f *Fixer) FixViolations(
+	ctx context.Context,
+	source fixes.FixCandidate,
+	violations []report.Violation,
+) ([]byte, []report.Violation, error) {
+	pending := make([]report.Violation, 0, len(violations))
+
+	for _, violation := range violations {
+		if violation.Location.File != "" && violation.Location.File != source.Filename {
+			continue
+		}
+
+		if _, ok := f.GetFixForName(violation.Title); ok {
+			pending = append(pending, violation)
+		}
+	}
+
+	slices.SortStableFunc(pending, func(a, b report.Violation) int {
+		if c := cmp.Compare(b.Location.Row, a.Location.Row); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(b.Location.Column, a.Location.Column)
+	})
+
+	contents := source.Contents
+
+	var applied []report.Violation
+
+	var regoV1Err *fixes.RegoV1Error
+
+	for _, violation := range pending {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+
+		fix, _ := f.GetFixForName(violation.Title)
+
+		fixResults, err := fix.Fix(&fixes.FixCandidate{Filename: source.Filename, Contents: contents}, &fixes.RuntimeOptions{
+			Locations: []ast.Location{{Row: violation.Location.Row, Col: violation.Location.Column}},
+		})
+		if errors.As(err, &regoV1Err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fix %s: %w", violation.Title, err)
+		}
+
+		if len(fixResults) > 0 {
+			// Note: Only one content update fix result is currently supported
+			contents = fixResults[0].Contents
+			applied = append(applied, violation)
+		}
+	}
+
+	if regoV1Err != nil {
+		return contents, applied, regoV1Err
+	}
+
+	return contents, applied, nil
+}
+
+// migrateRegoV1 migrates all Rego files of the file provider to Rego v1, recording any issues preventing the
+// migration of a file in the report.
+func migrateRegoV1(fp fileprovider.FileProvider, fixReport *Report, decided *decisions) error {
+	files, err := fp.ListFiles()
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+
+	migration := &fixes.RegoV1{}
+
+	for _, file := range files {
+		if filepath.Ext(file) != ".rego" {
+			continue
+		}
+
+		contents, err := fp.GetFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to get file %s: %w", file, err)
+		}
+
+		fixResults, err := migration.Fix(&fixes.FixCandidate{Filename: file, Contents: contents}, nil)
+
+		var regoV1Err *fixes.RegoV1Error
+		if errors.As(err, &regoV1Err) {
+			fixReport.SetFileIssues(file, regoV1Err.Issues)
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s to Rego v1: %w", file, err)
+		}
+
+		if len(fixResults) > 0 {
+			violation := report.Violation{Title: migration.Name(), Location: report.Location{File: file}}
+
+			apply, err := decided.apply(violation, contents, fixResults[0].Contents)
+			if err != nil {
+				return err
+			}
+
+			if !apply {
+				continue
+			}
+
+			if err = fp.PutFile(file, fixResults[0].Contents); err != nil {
+				return fmt.Errorf("failed to write migrated content to file %s: %w", file, err)
+			}
+
+			fixReport.SetFileFixedViolation(file, migration.Name())
+		}
+	}
+
+	return nil
+}
+
+// decisions keeps track of the decisions made on the fixes proposed in interactive mode.
+type decisions struct {
+	prompt Prompt
+	// accepted are the rules for which the fixes of all violations were accepted
+	accepted map[string]struct{}
+	// skipped are the violations left unfixed, which may be reported again after other fixes were applied
+	skipped map[string]struct{}
+	quit    bool
+}
+
+func newDecisions(prompt Prompt) *decisions {
+	return &decisions{prompt: prompt, accepted: make(map[string]struct{}), skipped: make(map[string]struct{})}
+}
+
+// apply returns whether the fix of the violation should be applied, prompting for a decision unless one applying
+// to the violation was made before. Without a prompt, all fixes are applied.
+func (d *decisions) apply(violation report.Violation, before, after []byte) (bool, error) {
+	if d.prompt == nil {
+		return true, nil
+	}
+
+	if d.quit {
+		return false, nil
+	}
+
+	if _, ok := d.accepted[violation.Title]; ok {
+		return true, nil
+	}
+
+	key := fmt.Sprintf("%s:%d:%d:%s", violation.Location.File, violation.Location.Row, violation.Location.Column,
+		violation.Title)
+	if violation.Location.Text != nil {
+		key += ":" + *violation.Location.Text
+	}
+
+	if _, ok := d.skipped[key]; ok {
+		return false, nil
+	}
+
+	decision, err := d.prompt(violation, before, after)
+	if err != nil {
+		return false, fmt.Errorf("failed to decide on fix for %s: %w", violation.Title, err)
+	}
+
+	switch decision {
+	case Accept:
+	case AcceptAll:
+		d.accepted[violation.Title] = struct{}{}
+	case Skip:
+		d.skipped[key] = struct{}{}
+
+		return false, nil
+	case Quit:
+		d.quit = true
+
+		return false, nil
+	}
+
+	return true, nil
+}
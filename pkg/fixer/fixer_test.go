@@ -3,12 +3,16 @@ package fixer
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"slices"
 	"testing"
 
+	"github.com/styrainc/regal/pkg/config"
 	"github.com/styrainc/regal/pkg/fixer/fileprovider"
 	"github.com/styrainc/regal/pkg/fixer/fixes"
 	"github.com/styrainc/regal/pkg/linter"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
 )
 
 func TestFixer(t *testing.T) {
@@ -102,3 +106,294 @@ deny := true
 		}
 	}
 }
+
+func TestFixerImports(t *testing.T) {
+	t.Parallel()
+
+	memfp := fileprovider.NewInMemoryFileProvider(map[string][]byte{
+		"main.rego": []byte(`package test
+
+import rego.v1
+
+import data.roles
+import data.roles
+import data.users
+import data.roles
+import data.users.admins as admins
+
+allow if input.user in admins
+
+deny if input.role in roles
+
+audit if input.user in users
+`),
+	})
+
+	input, err := memfp.ToInput()
+	if err != nil {
+		t.Fatalf("failed to create input: %v", err)
+	}
+
+	l := linter.NewLinter().
+		WithDisableAll(true).
+		WithEnabledRules("redundant-alias", "import-shadows-import").
+		WithInputModules(&input)
+
+	f := NewFixer()
+	f.RegisterFixes(fixes.NewDefaultFixes()...)
+
+	fixReport, err := f.Fix(context.Background(), &l, memfp)
+	if err != nil {
+		t.Fatalf("failed to fix: %v", err)
+	}
+
+	expected := `package test
+
+import rego.v1
+
+import data.roles
+import data.users
+import data.users.admins
+
+allow if input.user in admins
+
+deny if input.role in roles
+
+audit if input.user in users
+`
+
+	content, err := memfp.GetFile("main.rego")
+	if err != nil {
+		t.Fatalf("failed to get file: %v", err)
+	}
+
+	if string(content) != expected {
+		t.Fatalf("unexpected content:\ngot:\n%s---\nexpected:\n%s---", content, expected)
+	}
+
+	if fixed := fixReport.FixedViolationsForFile("main.rego"); !slices.Equal(
+		fixed, []string{"import-shadows-import", "redundant-alias"},
+	) {
+		t.Errorf("unexpected fixed violations: %v", fixed)
+	}
+}
+
+func TestFixerRegoV1Migration(t *testing.T) {
+	t.Parallel()
+
+	memfp := fileprovider.NewInMemoryFileProvider(map[string][]byte{
+		"main.rego": []byte(`package test
+
+import future.keywords.if
+
+allow if re_match("^admin", input.user)
+`),
+		"deprecated.rego": []byte(`package deprecated
+
+deny {
+	any([input.admin, input.root])
+}
+`),
+	})
+
+	l := linter.NewLinter().WithDisableAll(true)
+
+	f := NewFixer().WithRegoV1Migration(true)
+	f.RegisterFixes(fixes.NewDefaultFixes()...)
+
+	fixReport, err := f.Fix(context.Background(), &l, memfp)
+	if err != nil {
+		t.Fatalf("failed to fix: %v", err)
+	}
+
+	if got, exp := fixReport.FixedFiles(), []string{"main.rego"}; !slices.Equal(got, exp) {
+		t.Fatalf("expected fixed files %v, got %v", exp, got)
+	}
+
+	content, err := memfp.GetFile("main.rego")
+	if err != nil {
+		t.Fatalf("failed to get file: %v", err)
+	}
+
+	expected := `package test
+
+import rego.v1
+
+allow if regex.match("^admin", input.user)
+`
+	if string(content) != expected {
+		t.Fatalf("unexpected content, got:\n%s---\nexpected:\n%s---", content, expected)
+	}
+
+	if got, exp := fixReport.FilesWithIssues(), []string{"deprecated.rego"}; !slices.Equal(got, exp) {
+		t.Fatalf("expected files with issues %v, got %v", exp, got)
+	}
+
+	exp := []string{"4:2: deprecated built-in function calls in expression: any"}
+	if got := fixReport.IssuesForFile("deprecated.rego"); !slices.Equal(got, exp) {
+		t.Fatalf("expected issues %v, got %v", exp, got)
+	}
+}
+
+func TestFixerWithPrompt(t *testing.T) {
+	t.Parallel()
+
+	memfp := fileprovider.NewInMemoryFileProvider(map[string][]byte{
+		"main.rego": []byte(`package test
+
+import rego.v1
+
+#one
+#two
+#three
+x = 1
+`),
+	})
+
+	l := linter.NewLinter().WithDisableAll(true).WithEnabledRules("no-whitespace-comment", "use-assignment-operator")
+
+	prompted := make([]string, 0)
+
+	f := NewFixer().WithPrompt(func(violation report.Violation, _, _ []byte) (Decision, error) {
+		prompted = append(prompted, fmt.Sprintf("%s:%d", violation.Title, violation.Location.Row))
+
+		switch {
+		case violation.Title == "use-assignment-operator":
+			return Quit, nil
+		case violation.Location.Row == 5:
+			return Skip, nil
+		}
+
+		return AcceptAll, nil
+	})
+	f.RegisterFixes(fixes.NewDefaultFixes()...)
+
+	fixReport, err := f.Fix(context.Background(), &l, memfp)
+	if err != nil {
+		t.Fatalf("failed to fix: %v", err)
+	}
+
+	// the fixes of all further comments are accepted along with the first, and the skipped one isn't proposed again
+	exp := []string{"no-whitespace-comment:5", "no-whitespace-comment:6", "use-assignment-operator:8"}
+	if !slices.Equal(prompted, exp) {
+		t.Errorf("expected prompts for %v, got %v", exp, prompted)
+	}
+
+	if got := fixReport.FixedViolationsForFile("main.rego"); !slices.Equal(got, []string{"no-whitespace-comment"}) {
+		t.Errorf("expected fixed violations [no-whitespace-comment], got %v", got)
+	}
+
+	content, err := memfp.GetFile("main.rego")
+	if err != nil {
+		t.Fatalf("failed to get file: %v", err)
+	}
+
+	expected := `package test
+
+import rego.v1
+
+#one
+# two
+# three
+x = 1
+`
+	if string(content) != expected {
+		t.Fatalf("unexpected content, got:\n%s---\nexpected:\n%s---", content, expected)
+	}
+}
+
+func TestFix(t *testing.T) {
+	t.Parallel()
+
+	source := fixes.FixCandidate{
+		Filename: "main.rego",
+		Contents: []byte(`package test
+
+import rego.v1
+
+allow if {
+	input.admin #no space
+}
+
+deny = true #no space
+`),
+	}
+
+	input, err := rules.InputFromText(source.Filename, string(source.Contents))
+	if err != nil {
+		t.Fatalf("failed to create input: %v", err)
+	}
+
+	rep, err := linter.NewLinter().WithInputModules(&input).Lint(context.Background())
+	if err != nil {
+		t.Fatalf("failed to lint: %v", err)
+	}
+
+	// violations of other files are ignored
+	violations := slices.Concat(rep.Violations, []report.Violation{{
+		Title:    "no-whitespace-comment",
+		Location: report.Location{File: "other.rego", Row: 1, Column: 1},
+	}})
+
+	fixed, applied, err := Fix(context.Background(), source, violations)
+	if err != nil {
+		t.Fatalf("failed to fix: %v", err)
+	}
+
+	expected := `package test
+
+import rego.v1
+
+allow if {
+	input.admin # no space
+}
+
+deny := true # no space
+`
+	if string(fixed) != expected {
+		t.Fatalf("unexpected content, got:\n%s---\nexpected:\n%s---", fixed, expected)
+	}
+
+	titles := make([]string, 0, len(applied))
+	for _, violation := range applied {
+		titles = append(titles, violation.Title)
+	}
+
+	// fixed from the last location to the first
+	exp := []string{"no-whitespace-comment", "use-assignment-operator", "no-whitespace-comment"}
+	if !slices.Equal(titles, exp) {
+		t.Fatalf("expected applied fixes %v, got %v", exp, titles)
+	}
+}
+
+func TestFixerFixesUndoingEachOther(t *testing.T) {
+	t.Parallel()
+
+	memfp := fileprovider.NewInMemoryFileProvider(map[string][]byte{
+		"main.rego": []byte("package test\n\nimport rego.v1\n\nallow if {\n\tinput.x\n}\n"),
+	})
+
+	input, err := memfp.ToInput()
+	if err != nil {
+		t.Fatalf("failed to create input: %v", err)
+	}
+
+	// opa-fmt indents with tabs, which the indentation rule configured to indent with spaces reports
+	l := linter.NewLinter().
+		WithUserConfig(config.Config{Rules: map[string]config.Category{
+			"style": {
+				"opa-fmt":     config.Rule{Level: "error"},
+				"indentation": config.Rule{Level: "error", Extra: config.ExtraAttributes{"indent-with": "spaces"}},
+			},
+		}}).
+		WithDisableAll(true).
+		WithEnabledRules("opa-fmt", "indentation").
+		WithInputModules(&input)
+
+	f := NewFixer()
+	f.RegisterFixes(fixes.NewDefaultFixes()...)
+
+	if _, err := f.Fix(context.Background(), &l, memfp); err == nil {
+		t.Fatal("expected error for fixes undoing each other")
+	}
+}
@@ -2,7 +2,6 @@ package fixes
 
 import (
 	"github.com/open-policy-agent/opa/ast"
-	"github.com/open-policy-agent/opa/format"
 )
 
 // NewDefaultFixes returns a list of default fixes that are applied by the fix command.
@@ -10,14 +9,16 @@ import (
 func NewDefaultFixes() []Fix {
 	return []Fix{
 		&Fmt{},
-		&Fmt{
-			NameOverride: "use-rego-v1",
-			OPAFmtOpts: format.Opts{
-				RegoVersion: ast.RegoV0CompatV1,
-			},
-		},
+		&RegoV1{},
 		&UseAssignmentOperator{},
 		&NoWhitespaceComment{},
+		&RedundantAlias{},
+		&ImportShadowsImport{},
+		&UnsortedImports{},
+		&TrailingWhitespace{},
+		&Indentation{},
+		&MissingFinalNewline{},
+		&PreferRawString{},
 	}
 }
 
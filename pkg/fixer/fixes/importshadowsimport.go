@@ -0,0 +1,87 @@
+package fixes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+// ImportShadowsImport fixes the import-shadows-import rule by removing imports which are exact duplicates of an
+// earlier import, i.e. of the same path and alias. Imports shadowing another import of a different path are left
+// for the user to fix, as removing either would change what the policy refers to.
+type ImportShadowsImport struct{}
+
+func (*ImportShadowsImport) Name() string {
+	return "import-shadows-import"
+}
+
+func (*ImportShadowsImport) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]FixResult, error) {
+	if opts == nil {
+		return nil, errors.New("missing runtime options")
+	}
+
+	module, err := parse.Module(fc.Filename, string(fc.Contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse module: %w", err)
+	}
+
+	lines := bytes.Split(fc.Contents, []byte("\n"))
+
+	var remove []int
+
+	for _, loc := range opts.Locations {
+		if !IsDuplicateImport(module, loc.Row) || slices.Contains(remove, loc.Row) {
+			continue
+		}
+
+		// only remove lines with nothing but the import, keeping those with comments or other code
+		imp := importAt(module, loc.Row)
+		expected := []string{"import", string(imp.Path.Location.Text)}
+
+		if imp.Alias != "" {
+			expected = append(expected, "as", string(imp.Alias))
+		}
+
+		if !slices.Equal(strings.Fields(string(lines[loc.Row-1])), expected) {
+			continue
+		}
+
+		remove = append(remove, loc.Row)
+	}
+
+	if len(remove) == 0 {
+		return nil, nil
+	}
+
+	// remove from the last line to the first, for rows of lines not yet removed to remain the same
+	slices.SortFunc(remove, func(a, b int) int { return b - a })
+
+	for _, row := range remove {
+		lines = slices.Delete(lines, row-1, row)
+	}
+
+	return []FixResult{{Contents: bytes.Join(lines, []byte("\n"))}}, nil
+}
+
+// IsDuplicateImport returns true if the import declared at row is an exact duplicate of an earlier import of the
+// module, and so may be removed without changing what the policy refers to.
+func IsDuplicateImport(module *ast.Module, row int) bool {
+	imp := importAt(module, row)
+	if imp == nil || imp.Path.Location == nil {
+		return false
+	}
+
+	for _, other := range module.Imports {
+		if other.Location != nil && other.Location.Row < row && other.Path.Equal(imp.Path) && other.Alias == imp.Alias {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package fixes
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func TestImportShadowsImport(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents        string
+		contentAfterFix string
+		rows            []int
+	}{
+		"shadowing import of other path kept": {
+			contents: "package test\n\nimport data.users\nimport data.other.users\n",
+			rows:     []int{4},
+		},
+		"duplicate with comment kept": {
+			contents: "package test\n\nimport data.users\nimport data.users # users\n",
+			rows:     []int{4},
+		},
+		"single change": {
+			contents:        "package test\n\nimport data.users\nimport data.roles\nimport data.users\n\nx := 1\n",
+			contentAfterFix: "package test\n\nimport data.users\nimport data.roles\n\nx := 1\n",
+			rows:            []int{5},
+		},
+		"many changes": {
+			contents: "package test\n\nimport data.users as u\nimport data.users as u\nimport data.roles\n" +
+				"import  data.roles\n",
+			contentAfterFix: "package test\n\nimport data.users as u\nimport data.roles\n",
+			rows:            []int{4, 6},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			locations := make([]ast.Location, 0, len(tc.rows))
+			for _, row := range tc.rows {
+				locations = append(locations, ast.Location{Row: row, Col: 8})
+			}
+
+			fixResults, err := (&ImportShadowsImport{}).Fix(
+				&FixCandidate{Filename: "test.rego", Contents: []byte(tc.contents)},
+				&RuntimeOptions{Locations: locations},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.contentAfterFix == "" {
+				if len(fixResults) != 0 {
+					t.Fatalf("unexpected fix applied: %s", fixResults[0].Contents)
+				}
+
+				return
+			}
+
+			if len(fixResults) != 1 || string(fixResults[0].Contents) != tc.contentAfterFix {
+				t.Fatalf("expected fixed content:\n%s\ngot:\n%v", tc.contentAfterFix, fixResults)
+			}
+		})
+	}
+}
@@ -23,7 +23,7 @@ func (*NoWhitespaceComment) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]FixRe
 
 	for _, loc := range opts.Locations {
 		// unexpected line in file, skipping
-		if loc.Row > len(lines) {
+		if loc.Row > len(lines) || loc.Row < 1 {
 			continue
 		}
 
@@ -0,0 +1,87 @@
+package fixes
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"slices"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+// PreferRawString fixes the prefer-raw-string rule by converting strings to raw strings, where only backslashes and
+// quotes are escaped, and the string contains no backticks, as the string is otherwise changed by the conversion.
+type PreferRawString struct{}
+
+func (*PreferRawString) Name() string {
+	return "prefer-raw-string"
+}
+
+func (*PreferRawString) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]FixResult, error) {
+	if opts == nil {
+		return nil, errors.New("missing runtime options")
+	}
+
+	lines := bytes.Split(fc.Contents, []byte("\n"))
+	fixed := false
+
+	// strings converted shrink the line, so they are converted from the end of each line to keep columns valid
+	locations := slices.Clone(opts.Locations)
+	slices.SortFunc(locations, func(a, b ast.Location) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(b.Col, a.Col))
+	})
+
+	for _, loc := range locations {
+		if loc.Row < 1 || loc.Row > len(lines) {
+			continue
+		}
+
+		line := lines[loc.Row-1]
+
+		if loc.Col < 1 || loc.Col > len(line) || line[loc.Col-1] != '"' {
+			continue
+		}
+
+		raw, length, ok := rawString(line[loc.Col-1:])
+		if !ok {
+			continue
+		}
+
+		lines[loc.Row-1] = slices.Concat(line[:loc.Col-1], raw, line[loc.Col-1+length:])
+		fixed = true
+	}
+
+	if !fixed {
+		return nil, nil
+	}
+
+	return []FixResult{{Contents: bytes.Join(lines, []byte("\n"))}}, nil
+}
+
+// rawString returns the raw string equivalent of the string at the start of text, along with the length of the
+// string in text, or false if the string can't be converted without changing it.
+func rawString(text []byte) ([]byte, int, bool) {
+	raw := []byte{'`'}
+
+	for i := 1; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '"':
+			return append(raw, '`'), i + 1, true
+		case '`':
+			return nil, 0, false
+		case '\\':
+			if i+1 == len(text) || !slices.Contains([]byte(`\"/`), text[i+1]) {
+				return nil, 0, false
+			}
+
+			i++
+
+			raw = append(raw, text[i])
+		default:
+			raw = append(raw, c)
+		}
+	}
+
+	// unterminated string
+	return nil, 0, false
+}
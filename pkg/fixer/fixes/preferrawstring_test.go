@@ -0,0 +1,71 @@
+package fixes
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func TestPreferRawString(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents        string
+		locations       []ast.Location
+		contentAfterFix string
+	}{
+		"escaped backslashes": {
+			contents:        "package test\n\npath := \"C:\\\\Users\\\\admin\\\\policies\"\n",
+			locations:       []ast.Location{{Row: 3, Col: 9}},
+			contentAfterFix: "package test\n\npath := `C:\\Users\\admin\\policies`\n",
+		},
+		"escaped quotes and slashes": {
+			contents:        "package test\n\nx := \"\\\"a\\/b\\\"\"\n",
+			locations:       []ast.Location{{Row: 3, Col: 6}},
+			contentAfterFix: "package test\n\nx := `\"a/b\"`\n",
+		},
+		"several strings on line": {
+			contents:        "package test\n\nx := [\"a\\\\b\", \"c\\\\d\"]\n",
+			locations:       []ast.Location{{Row: 3, Col: 7}, {Row: 3, Col: 15}},
+			contentAfterFix: "package test\n\nx := [`a\\b`, `c\\d`]\n",
+		},
+		"other escapes": {
+			contents:  "package test\n\nx := \"a\\\\b\\n\"\n",
+			locations: []ast.Location{{Row: 3, Col: 6}},
+		},
+		"backtick": {
+			contents:  "package test\n\nx := \"a\\\\b`\"\n",
+			locations: []ast.Location{{Row: 3, Col: 6}},
+		},
+		"no string at location": {
+			contents:  "package test\n\nx := \"a\\\\b\"\n",
+			locations: []ast.Location{{Row: 3, Col: 1}},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			fixResults, err := (&PreferRawString{}).Fix(
+				&FixCandidate{Filename: "test.rego", Contents: []byte(tc.contents)},
+				&RuntimeOptions{Locations: tc.locations},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.contentAfterFix == "" {
+				if len(fixResults) != 0 {
+					t.Fatalf("unexpected fix applied: %s", fixResults[0].Contents)
+				}
+
+				return
+			}
+
+			if len(fixResults) != 1 || string(fixResults[0].Contents) != tc.contentAfterFix {
+				t.Fatalf("expected fixed content:\n%s\ngot:\n%v", tc.contentAfterFix, fixResults)
+			}
+		})
+	}
+}
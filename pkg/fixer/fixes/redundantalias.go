@@ -0,0 +1,83 @@
+package fixes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+// redundantAliasPattern matches the alias following the path of an import.
+var redundantAliasPattern = regexp.MustCompile(`^\s+as\s+(\w+)`)
+
+// RedundantAlias fixes the redundant-alias rule by removing aliases which are the same as the last part of the
+// path imported.
+type RedundantAlias struct{}
+
+func (*RedundantAlias) Name() string {
+	return "redundant-alias"
+}
+
+func (*RedundantAlias) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]FixResult, error) {
+	if opts == nil {
+		return nil, errors.New("missing runtime options")
+	}
+
+	module, err := parse.Module(fc.Filename, string(fc.Contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse module: %w", err)
+	}
+
+	lines := bytes.Split(fc.Contents, []byte("\n"))
+	fixed := false
+
+	for _, loc := range opts.Locations {
+		imp := importAt(module, loc.Row)
+		if imp == nil || imp.Alias == "" || imp.Path.Location == nil || imp.Path.Location.Row != loc.Row {
+			continue
+		}
+
+		// the alias is only redundant if the same as the last part of the path
+		ref, ok := imp.Path.Value.(ast.Ref)
+		if !ok || !ref[len(ref)-1].Equal(ast.StringTerm(string(imp.Alias))) {
+			continue
+		}
+
+		line := lines[loc.Row-1]
+		end := imp.Path.Location.Col - 1 + len(imp.Path.Location.Text)
+
+		if end > len(line) {
+			continue
+		}
+
+		match := redundantAliasPattern.FindSubmatchIndex(line[end:])
+		if match == nil || string(line[end+match[2]:end+match[3]]) != string(imp.Alias) {
+			continue
+		}
+
+		lines[loc.Row-1] = slices.Concat(line[:end], line[end+match[1]:])
+		fixed = true
+	}
+
+	if !fixed {
+		return nil, nil
+	}
+
+	return []FixResult{{Contents: bytes.Join(lines, []byte("\n"))}}, nil
+}
+
+// importAt returns the import declared at row, or nil if there's none.
+func importAt(module *ast.Module, row int) *ast.Import {
+	for _, imp := range module.Imports {
+		if imp.Location != nil && imp.Location.Row == row {
+			return imp
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package fixes
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func TestRedundantAlias(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents        string
+		contentAfterFix string
+		rows            []int
+	}{
+		"no change needed": {
+			contents: "package test\n\nimport data.users as u\n",
+			rows:     []int{3},
+		},
+		"single change": {
+			contents:        "package test\n\nimport data.users as users\n",
+			contentAfterFix: "package test\n\nimport data.users\n",
+			rows:            []int{3},
+		},
+		"comment kept": {
+			contents:        "package test\n\nimport data.users.admins  as   admins # admins\n",
+			contentAfterFix: "package test\n\nimport data.users.admins # admins\n",
+			rows:            []int{3},
+		},
+		"many changes": {
+			contents:        "package test\n\nimport data.users as users\nimport data.roles\nimport input.user as user\n",
+			contentAfterFix: "package test\n\nimport data.users\nimport data.roles\nimport input.user\n",
+			rows:            []int{3, 5},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			locations := make([]ast.Location, 0, len(tc.rows))
+			for _, row := range tc.rows {
+				locations = append(locations, ast.Location{Row: row, Col: 8})
+			}
+
+			fixResults, err := (&RedundantAlias{}).Fix(
+				&FixCandidate{Filename: "test.rego", Contents: []byte(tc.contents)},
+				&RuntimeOptions{Locations: locations},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.contentAfterFix == "" {
+				if len(fixResults) != 0 {
+					t.Fatalf("unexpected fix applied: %s", fixResults[0].Contents)
+				}
+
+				return
+			}
+
+			if len(fixResults) != 1 || string(fixResults[0].Contents) != tc.contentAfterFix {
+				t.Fatalf("expected fixed content:\n%s\ngot:\n%v", tc.contentAfterFix, fixResults)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package fixes
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/format"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+// deprecatedBuiltinReplacements maps deprecated built-in functions to replacements with the exact same semantics,
+// which may therefore be swapped without changing the meaning of a policy. The remaining deprecated built-in
+// functions, like any and all, have no such replacement, and must be rewritten by hand.
+//
+//nolint:gochecknoglobals
+var deprecatedBuiltinReplacements = map[string]ast.Ref{
+	ast.RegexMatchDeprecated.Name: ast.RegexMatch.Ref(),
+	ast.NetCIDROverlap.Name:       ast.NetCIDRContains.Ref(),
+	ast.SetDiff.Name:              ast.Minus.Ref(),
+}
+
+// RegoV1Error is returned when a policy can't safely be migrated to Rego v1, and lists the issues which must be
+// addressed by hand before it can be.
+type RegoV1Error struct {
+	Filename string
+	Issues   ast.Errors
+}
+
+func (e *RegoV1Error) Error() string {
+	return fmt.Sprintf("%s can't be migrated to Rego v1: %v", e.Filename, e.Issues)
+}
+
+// RegoV1 migrates a policy to the syntax of Rego v1, by adding the if and contains keywords where required,
+// replacing imports of future keywords with rego.v1, and replacing deprecated built-in functions where that can
+// safely be done. Policies with issues that can't be fixed automatically are left unchanged, and a *RegoV1Error
+// listing the issues is returned.
+type RegoV1 struct{}
+
+func (*RegoV1) Name() string {
+	return "use-rego-v1"
+}
+
+func (*RegoV1) Fix(fc *FixCandidate, _ *RuntimeOptions) ([]FixResult, error) {
+	if fc.Filename == "" {
+		return nil, errors.New("filename is required when migrating to Rego v1")
+	}
+
+	migrated, err := MigrateRegoV1(filepath.Base(fc.Filename), fc.Contents)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(migrated) == string(fc.Contents) {
+		return nil, nil
+	}
+
+	return []FixResult{
+		{
+			Contents: migrated,
+		},
+	}, nil
+}
+
+// MigrateRegoV1 returns the contents of a policy migrated to Rego v1, or a *RegoV1Error if issues prevent that
+// from being done safely.
+func MigrateRegoV1(filename string, contents []byte) ([]byte, error) {
+	module, err := ast.ParseModuleWithOpts(filename, string(contents), parse.ParserOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	replaceDeprecatedBuiltins(module)
+
+	if issues := ast.CheckRegoV1(module); len(issues) > 0 {
+		return nil, &RegoV1Error{Filename: filename, Issues: issues}
+	}
+
+	migrated, err := format.AstWithOpts(module, format.Opts{RegoVersion: ast.RegoV0CompatV1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format: %w", err)
+	}
+
+	return migrated, nil
+}
+
+func replaceDeprecatedBuiltins(module *ast.Module) {
+	replace := func(operator *ast.Term) {
+		if replacement, ok := deprecatedBuiltinReplacements[operator.Value.String()]; ok {
+			operator.Value = replacement.Copy()
+		}
+	}
+
+	ast.NewGenericVisitor(func(x any) bool {
+		switch x := x.(type) {
+		case *ast.Expr:
+			if x.IsCall() {
+				replace(x.Terms.([]*ast.Term)[0]) //nolint:forcetypeassert
+			}
+		case ast.Call:
+			if len(x) > 0 {
+				replace(x[0])
+			}
+		}
+
+		return false
+	}).Walk(module)
+}
@@ -0,0 +1,136 @@
+package fixes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegoV1(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents        string
+		contentAfterFix string
+		fixExpected     bool
+	}{
+		"no change": {
+			contents:        "package test\n\nimport rego.v1\n\nallow if input.admin\n",
+			contentAfterFix: "package test\n\nimport rego.v1\n\nallow if input.admin\n",
+		},
+		"keywords added": {
+			contents: `package test
+
+allow {
+	input.user == "admin"
+}
+
+users[name] {
+	name := input.users[_]
+}
+`,
+			contentAfterFix: `package test
+
+import rego.v1
+
+allow if {
+	input.user == "admin"
+}
+
+users contains name if {
+	name := input.users[_]
+}
+`,
+			fixExpected: true,
+		},
+		"future keywords replaced": {
+			contents: `package test
+
+import future.keywords.if
+import future.keywords.in
+
+allow if "admin" in input.roles
+`,
+			contentAfterFix: `package test
+
+import rego.v1
+
+allow if "admin" in input.roles
+`,
+			fixExpected: true,
+		},
+		"deprecated built-in functions replaced": {
+			contents: `package test
+
+import rego.v1
+
+allow if re_match("^admin", input.user)
+
+diff := set_diff({1, 2}, {2})
+
+internal if net.cidr_overlap("10.0.0.0/8", input.ip)
+`,
+			contentAfterFix: `package test
+
+import rego.v1
+
+allow if regex.match("^admin", input.user)
+
+diff := {1, 2} - {2}
+
+internal if net.cidr_contains("10.0.0.0/8", input.ip)
+`,
+			fixExpected: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			fixResults, err := (&RegoV1{}).Fix(&FixCandidate{Filename: "test.rego", Contents: []byte(tc.contents)}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tc.fixExpected {
+				if len(fixResults) != 0 {
+					t.Fatalf("unexpected fix applied:\n%s", fixResults[0].Contents)
+				}
+
+				return
+			}
+
+			if len(fixResults) != 1 {
+				t.Fatalf("expected 1 fix result, got %d", len(fixResults))
+			}
+
+			if got := string(fixResults[0].Contents); got != tc.contentAfterFix {
+				t.Fatalf("unexpected content, got:\n%s---\nexpected:\n%s---", got, tc.contentAfterFix)
+			}
+		})
+	}
+}
+
+func TestRegoV1Issues(t *testing.T) {
+	t.Parallel()
+
+	contents := `package test
+
+allow {
+	any([input.admin, input.root])
+}
+
+input := {"user": "admin"}
+`
+
+	_, err := (&RegoV1{}).Fix(&FixCandidate{Filename: "test.rego", Contents: []byte(contents)}, nil)
+
+	var regoV1Err *RegoV1Error
+	if !errors.As(err, &regoV1Err) {
+		t.Fatalf("expected RegoV1Error, got %v", err)
+	}
+
+	if len(regoV1Err.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %v", len(regoV1Err.Issues), regoV1Err.Issues)
+	}
+}
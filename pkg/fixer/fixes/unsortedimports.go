@@ -0,0 +1,110 @@
+package fixes
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+)
+
+// UnsortedImports fixes the unsorted-imports rule by rewriting the imports of a module in groups separated by
+// an empty line, with future and rego.v1 imports first, followed by data imports, and then input imports, each
+// group sorted like opa fmt sorts imports. Modules with comments or other statements among the imports are left
+// for the user to fix, as there's no telling where those belong once the imports are rearranged.
+type UnsortedImports struct{}
+
+func (*UnsortedImports) Name() string {
+	return "unsorted-imports"
+}
+
+func (*UnsortedImports) Fix(fc *FixCandidate, _ *RuntimeOptions) ([]FixResult, error) {
+	module, err := parse.Module(fc.Filename, string(fc.Contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse module: %w", err)
+	}
+
+	if len(module.Imports) < 2 {
+		return nil, nil
+	}
+
+	first, last := module.Imports[0].Location.Row, module.Imports[0].Location.Row
+
+	for _, imp := range module.Imports {
+		first, last = min(first, imp.Location.Row), max(last, imp.Location.Row)
+	}
+
+	for _, comment := range module.Comments {
+		if comment.Location.Row >= first && comment.Location.Row <= last {
+			return nil, nil
+		}
+	}
+
+	lines := bytes.Split(fc.Contents, []byte("\n"))
+
+	// the imports must be the only thing declared on the lines between the first and the last import, each on
+	// a line of its own
+	imports := 0
+
+	for _, line := range lines[first-1 : last] {
+		switch trimmed := bytes.TrimSpace(line); {
+		case bytes.HasPrefix(trimmed, []byte("import ")):
+			imports++
+		case len(trimmed) != 0:
+			return nil, nil
+		}
+	}
+
+	if imports != len(module.Imports) {
+		return nil, nil
+	}
+
+	sorted := slices.Clone(module.Imports)
+	slices.SortStableFunc(sorted, func(a, b *ast.Import) int {
+		if ga, gb := importGroup(a), importGroup(b); ga != gb {
+			return ga - gb
+		}
+
+		return a.Compare(b)
+	})
+
+	var sb strings.Builder
+
+	for i, imp := range sorted {
+		if i > 0 && importGroup(sorted[i-1]) != importGroup(imp) {
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(imp.String() + "\n")
+	}
+
+	fixed := slices.Concat(lines[:first-1], [][]byte{[]byte(strings.TrimSuffix(sb.String(), "\n"))}, lines[last:])
+	contents := bytes.Join(fixed, []byte("\n"))
+
+	if bytes.Equal(contents, fc.Contents) {
+		return nil, nil
+	}
+
+	return []FixResult{{Contents: contents}}, nil
+}
+
+// importGroup returns the group of an import, by the order of the groups: future and rego.v1 imports, data imports,
+// and input imports.
+func importGroup(imp *ast.Import) int {
+	ref, ok := imp.Path.Value.(ast.Ref)
+	if !ok {
+		return 1
+	}
+
+	switch ref[0].String() {
+	case "future", "rego":
+		return 0
+	case "input":
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,64 @@
+package fixes
+
+import "testing"
+
+func TestUnsortedImports(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents        string
+		contentAfterFix string
+	}{
+		"no change needed": {
+			contents: "package test\n\nimport rego.v1\n\nimport data.roles\nimport data.users\n\nimport input.user\n",
+		},
+		"sorted within groups": {
+			contents:        "package test\n\nimport data.users\nimport data.roles as r\nimport data.roles\n\nallow := true\n",
+			contentAfterFix: "package test\n\nimport data.roles\nimport data.roles as r\nimport data.users\n\nallow := true\n",
+		},
+		"grouped": {
+			contents: "package test\n\nimport input.user\nimport data.users\nimport rego.v1\nimport data.roles\n",
+			contentAfterFix: "package test\n\nimport rego.v1\n\nimport data.roles\nimport data.users\n\n" +
+				"import input.user\n",
+		},
+		"empty lines between imports collapsed": {
+			contents:        "package test\n\nimport data.users\n\n\nimport data.roles\n",
+			contentAfterFix: "package test\n\nimport data.roles\nimport data.users\n",
+		},
+		"comments among imports": {
+			contents: "package test\n\nimport data.users\n# roles\nimport data.roles\n",
+		},
+		"trailing comment": {
+			contents: "package test\n\nimport data.users # users\nimport data.roles\n",
+		},
+		"rule among imports": {
+			contents: "package test\n\nimport data.users\n\nallow := true\n\nimport data.roles\n",
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			fixResults, err := (&UnsortedImports{}).Fix(
+				&FixCandidate{Filename: "test.rego", Contents: []byte(tc.contents)},
+				&RuntimeOptions{},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.contentAfterFix == "" {
+				if len(fixResults) != 0 {
+					t.Fatalf("unexpected fix applied: %s", fixResults[0].Contents)
+				}
+
+				return
+			}
+
+			if len(fixResults) != 1 || string(fixResults[0].Contents) != tc.contentAfterFix {
+				t.Fatalf("expected fixed content:\n%s\ngot:\n%v", tc.contentAfterFix, fixResults)
+			}
+		})
+	}
+}
@@ -22,12 +22,16 @@ func (*UseAssignmentOperator) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]Fix
 	fixed := false
 
 	for _, loc := range opts.Locations {
-		if loc.Row > len(lines) {
+		if loc.Row > len(lines) || loc.Row < 1 {
 			continue
 		}
 
 		line := lines[loc.Row-1]
 
+		if loc.Col > len(line) || loc.Col < 1 {
+			continue
+		}
+
 		// unexpected character at location column, skipping
 		if line[loc.Col-1] != byte('=') {
 			continue
@@ -0,0 +1,118 @@
+package fixes
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+)
+
+// TrailingWhitespace fixes the trailing-whitespace rule by removing the whitespace at the end of lines.
+type TrailingWhitespace struct{}
+
+func (*TrailingWhitespace) Name() string {
+	return "trailing-whitespace"
+}
+
+func (*TrailingWhitespace) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]FixResult, error) {
+	if opts == nil {
+		return nil, errors.New("missing runtime options")
+	}
+
+	lines := bytes.Split(fc.Contents, []byte("\n"))
+	fixed := false
+
+	for _, loc := range opts.Locations {
+		if loc.Row < 1 || loc.Row > len(lines) {
+			continue
+		}
+
+		if trimmed := bytes.TrimRight(lines[loc.Row-1], " \t"); len(trimmed) != len(lines[loc.Row-1]) {
+			lines[loc.Row-1] = trimmed
+			fixed = true
+		}
+	}
+
+	if !fixed {
+		return nil, nil
+	}
+
+	return []FixResult{{Contents: bytes.Join(lines, []byte("\n"))}}, nil
+}
+
+// Indentation fixes the indentation rule by replacing the indentation of lines with tabs, or with spaces, depending
+// on the character reported as wrong, i.e. the character at the location of the violation.
+type Indentation struct {
+	// Width is the number of spaces to a level of indentation, defaulting to 4.
+	Width int
+}
+
+func (*Indentation) Name() string {
+	return "indentation"
+}
+
+func (i *Indentation) Fix(fc *FixCandidate, opts *RuntimeOptions) ([]FixResult, error) {
+	if opts == nil {
+		return nil, errors.New("missing runtime options")
+	}
+
+	width := cmp.Or(i.Width, 4)
+	lines := bytes.Split(fc.Contents, []byte("\n"))
+	fixed := false
+
+	for _, loc := range opts.Locations {
+		if loc.Row < 1 || loc.Row > len(lines) {
+			continue
+		}
+
+		line := lines[loc.Row-1]
+		content := bytes.TrimLeft(line, " \t")
+		indentation := line[:len(line)-len(content)]
+
+		if loc.Col < 1 || loc.Col > len(indentation) {
+			continue
+		}
+
+		columns := 0
+
+		for _, c := range indentation {
+			if c == '\t' {
+				columns += width
+			} else {
+				columns++
+			}
+		}
+
+		var replacement []byte
+
+		if indentation[loc.Col-1] == ' ' {
+			// spaces short of a full level of indentation are taken to be a level of their own
+			replacement = bytes.Repeat([]byte("\t"), (columns+width-1)/width)
+		} else {
+			replacement = bytes.Repeat([]byte(" "), columns)
+		}
+
+		lines[loc.Row-1] = append(replacement, content...)
+		fixed = true
+	}
+
+	if !fixed {
+		return nil, nil
+	}
+
+	return []FixResult{{Contents: bytes.Join(lines, []byte("\n"))}}, nil
+}
+
+// MissingFinalNewline fixes the missing-final-newline rule by adding a newline to the end of the file.
+type MissingFinalNewline struct{}
+
+func (*MissingFinalNewline) Name() string {
+	return "missing-final-newline"
+}
+
+func (*MissingFinalNewline) Fix(fc *FixCandidate, _ *RuntimeOptions) ([]FixResult, error) {
+	if len(fc.Contents) == 0 || bytes.HasSuffix(fc.Contents, []byte("\n")) {
+		return nil, nil
+	}
+
+	return []FixResult{{Contents: append(bytes.Clone(fc.Contents), '\n')}}, nil
+}
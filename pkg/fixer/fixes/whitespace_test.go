@@ -0,0 +1,77 @@
+package fixes
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func TestWhitespace(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fix             Fix
+		contents        string
+		contentAfterFix string
+		locations       []ast.Location
+	}{
+		"trailing whitespace": {
+			fix:             &TrailingWhitespace{},
+			contents:        "package test\n\nx := 1 \t\ny := 2  \n",
+			contentAfterFix: "package test\n\nx := 1\ny := 2\n",
+			locations:       []ast.Location{{Row: 3, Col: 7}, {Row: 4, Col: 7}},
+		},
+		"no trailing whitespace": {
+			fix:       &TrailingWhitespace{},
+			contents:  "package test\n\nx := 1\n",
+			locations: []ast.Location{{Row: 3, Col: 7}},
+		},
+		"spaces to tabs": {
+			fix:             &Indentation{},
+			contents:        "package test\n\nallow {\n    input.x\n\t  input.y\n}\n",
+			contentAfterFix: "package test\n\nallow {\n\tinput.x\n\t\tinput.y\n}\n",
+			locations:       []ast.Location{{Row: 4, Col: 1}, {Row: 5, Col: 2}},
+		},
+		"tabs to spaces": {
+			fix:             &Indentation{Width: 2},
+			contents:        "package test\n\nallow {\n\tinput.x\n\t\tinput.y\n}\n",
+			contentAfterFix: "package test\n\nallow {\n  input.x\n    input.y\n}\n",
+			locations:       []ast.Location{{Row: 4, Col: 1}, {Row: 5, Col: 1}},
+		},
+		"missing final newline": {
+			fix:             &MissingFinalNewline{},
+			contents:        "package test\n\nx := 1",
+			contentAfterFix: "package test\n\nx := 1\n",
+		},
+		"final newline": {
+			fix:      &MissingFinalNewline{},
+			contents: "package test\n\nx := 1\n",
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			fixResults, err := tc.fix.Fix(
+				&FixCandidate{Filename: "test.rego", Contents: []byte(tc.contents)},
+				&RuntimeOptions{Locations: tc.locations},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.contentAfterFix == "" {
+				if len(fixResults) != 0 {
+					t.Fatalf("unexpected fix applied: %s", fixResults[0].Contents)
+				}
+
+				return
+			}
+
+			if len(fixResults) != 1 || string(fixResults[0].Contents) != tc.contentAfterFix {
+				t.Fatalf("expected fixed content:\n%s\ngot:\n%v", tc.contentAfterFix, fixResults)
+			}
+		})
+	}
+}
@@ -1,20 +1,60 @@
 package fixer
 
-import "slices"
+import (
+	"fmt"
+	"slices"
+
+	"github.com/open-policy-agent/opa/ast"
+)
 
 // Report contains updated file contents and summary information about the fixes that were applied
 // during a fix operation.
 type Report struct {
 	totalFixes          int
 	fileFixedViolations map[string]map[string]struct{}
+	fileIssues          map[string][]string
 }
 
 func NewReport() *Report {
 	return &Report{
 		fileFixedViolations: make(map[string]map[string]struct{}),
+		fileIssues:          make(map[string][]string),
 	}
 }
 
+// SetFileIssues records the issues which prevented a file from being fixed, and which must be addressed by hand.
+// Any issues previously recorded for the file are replaced.
+func (r *Report) SetFileIssues(file string, issues ast.Errors) {
+	messages := make([]string, 0, len(issues))
+
+	for _, issue := range issues {
+		if issue.Location != nil {
+			messages = append(messages, fmt.Sprintf("%d:%d: %s", issue.Location.Row, issue.Location.Col, issue.Message))
+		} else {
+			messages = append(messages, issue.Message)
+		}
+	}
+
+	r.fileIssues[file] = messages
+}
+
+// IssuesForFile returns the issues which prevented a file from being fixed.
+func (r *Report) IssuesForFile(file string) []string {
+	return r.fileIssues[file]
+}
+
+// FilesWithIssues returns the files which could not be fixed due to issues, in sorted order.
+func (r *Report) FilesWithIssues() []string {
+	files := make([]string, 0, len(r.fileIssues))
+	for file := range r.fileIssues {
+		files = append(files, file)
+	}
+
+	slices.Sort(files)
+
+	return files
+}
+
 func (r *Report) SetFileFixedViolation(file string, violation string) {
 	if _, ok := r.fileFixedViolations[file]; !ok {
 		r.fileFixedViolations[file] = make(map[string]struct{})
@@ -32,10 +32,28 @@ func NewPrettyReporter(outputWriter io.Writer) *PrettyReporter {
 }
 
 func (r *PrettyReporter) Report(fixReport *Report) error {
+	r.reportFixes(fixReport)
+
+	if files := fixReport.FilesWithIssues(); len(files) > 0 {
+		fmt.Fprintln(r.outputWriter, "\nIssues that must be fixed manually:")
+
+		for _, file := range files {
+			fmt.Fprintf(r.outputWriter, "%s:\n", file)
+
+			for _, issue := range fixReport.IssuesForFile(file) {
+				fmt.Fprintf(r.outputWriter, "- %s\n", issue)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *PrettyReporter) reportFixes(fixReport *Report) {
 	if fixReport.TotalFixes() == 0 {
 		fmt.Fprintln(r.outputWriter, "No fixes applied.")
 
-		return nil
+		return
 	}
 
 	if fixReport.TotalFixes() == 1 {
@@ -53,6 +71,4 @@ func (r *PrettyReporter) Report(fixReport *Report) error {
 			fmt.Fprintf(r.outputWriter, "- %s\n", f)
 		}
 	}
-
-	return nil
 }
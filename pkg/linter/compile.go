@@ -0,0 +1,309 @@
+package linter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"dario.cat/mergo"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/rego"
+
+	rbundle "github.com/styrainc/regal/bundle"
+	rio "github.com/styrainc/regal/internal/io"
+	regalmetrics "github.com/styrainc/regal/internal/metrics"
+	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/internal/util"
+	"github.com/styrainc/regal/pkg/builtins"
+)
+
+// compiledRules holds the compiled linter rules, along with any data provided alongside them, like the default
+// configuration. These are shared by all queries evaluated by a linter, so that the rules are compiled only once,
+// rather than once for each query.
+type compiledRules struct {
+	compiler *ast.Compiler
+	data     map[string]any
+}
+
+// compiledOnce holds rules compiled at most once.
+type compiledOnce struct {
+	once  sync.Once
+	rules *compiledRules
+	err   error
+}
+
+//nolint:gochecknoglobals
+var (
+	embeddedBundleOnce sync.Once
+	embeddedBundle     *bundle.Bundle
+
+	// the embedded rules are compiled at most once per process for each set of enabled rules, the first time a
+	// linter needs them, and reused by all linters created after, like those of the language server on every change,
+	// or the server on every request. linters using any other rules, or print statements, compile their own.
+	embeddedRulesMu    sync.Mutex
+	embeddedRules      = make(map[string]*compiledOnce)
+	embeddedRulesStats regalmetrics.CacheStats
+)
+
+// loadEmbeddedBundle returns the Regal rules bundle embedded in the binary, which is loaded only once.
+func loadEmbeddedBundle() *bundle.Bundle {
+	embeddedBundleOnce.Do(func() {
+		regalRules := rio.MustLoadRegalBundleFS(rbundle.Bundle)
+		embeddedBundle = &regalRules
+	})
+
+	return embeddedBundle
+}
+
+// compile returns the compiled rules of the linter, from the rules bundles and any custom rules provided.
+func (l Linter) compile() (*compiledRules, error) {
+	if l.compiled != nil {
+		return l.compiled, nil
+	}
+
+	embeddedOnly := len(l.ruleBundles) == 1 && l.ruleBundles[0] == loadEmbeddedBundle() &&
+		l.customRulesPaths == nil && l.customRuleFS == nil && !l.printStatementsEnabled()
+
+	if !embeddedOnly {
+		return l.compileRules()
+	}
+
+	enabled, err := l.enabledRules(bundleModules(loadEmbeddedBundle()))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(enabled))
+	for rule := range enabled {
+		keys = append(keys, rule)
+	}
+
+	slices.Sort(keys)
+
+	embeddedRulesMu.Lock()
+
+	entry, ok := embeddedRules[strings.Join(keys, ",")]
+	if !ok {
+		entry = &compiledOnce{}
+		embeddedRules[strings.Join(keys, ",")] = entry
+	}
+
+	embeddedRulesMu.Unlock()
+
+	embeddedRulesStats.Record(ok)
+
+	entry.once.Do(func() {
+		entry.rules, entry.err = l.compileRules()
+	})
+
+	return entry.rules, entry.err
+}
+
+// CompiledRulesCacheStats returns the number of times the embedded rules were compiled, or reused from a previous
+// compilation for the same set of enabled rules, by any linter in the process.
+func CompiledRulesCacheStats() *regalmetrics.CacheStats {
+	return &embeddedRulesStats
+}
+
+func (l Linter) compileRules() (*compiledRules, error) {
+	modules, data, err := l.ruleModules()
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := l.enabledRules(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	// rules not enabled are left out, unless referenced by rules that are, like for their helpers, which
+	// specializes the compiled rules, and the queries prepared with them, to the configuration
+	required := requiredRules(modules, enabled)
+
+	for path, module := range modules {
+		if category, title, ok := ruleFromPackage(module.Package.Path); ok && !required[category+"/"+title] {
+			delete(modules, path)
+		}
+	}
+
+	compiler := ast.NewCompiler().
+		WithBuiltins(customBuiltins()).
+		WithEnablePrintStatements(l.printStatementsEnabled()).
+		WithUseTypeCheckAnnotations(true)
+
+	if compiler.Compile(modules); compiler.Failed() {
+		return nil, fmt.Errorf("failed to compile rules: %w", compiler.Errors)
+	}
+
+	return &compiledRules{compiler: compiler, data: data}, nil
+}
+
+// ruleModules returns the modules of the rules bundles and any custom rules provided, keyed by the name of their
+// bundle followed by their path, or by the path of custom rules, along with the data provided alongside them.
+func (l Linter) ruleModules() (map[string]*ast.Module, map[string]any, error) {
+	modules := make(map[string]*ast.Module)
+	data := make(map[string]any)
+
+	for _, ruleBundle := range l.ruleBundles {
+		name, _ := ruleBundle.Manifest.Metadata["name"].(string)
+
+		for path, module := range bundleModules(ruleBundle) {
+			modules[name+"/"+path] = module
+		}
+
+		// the data is copied, as bundles (like the embedded one) may be shared between linters
+		if err := mergo.Merge(&data, rio.ToMap(ruleBundle.Data)); err != nil {
+			return nil, nil, fmt.Errorf("failed to merge data of bundle %s: %w", name, err)
+		}
+	}
+
+	if l.customRulesPaths != nil {
+		opts := parse.ParserOptions()
+
+		result, err := loader.NewFileLoader().
+			WithProcessAnnotation(opts.ProcessAnnotation).
+			WithCapabilities(opts.Capabilities).
+			WithRegoVersion(opts.RegoVersion).
+			WithJSONOptions(opts.JSONOptions).
+			Filtered(l.customRulesPaths, rio.ExcludeTestFilter())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load custom rules: %w", err)
+		}
+
+		for name, module := range result.ParsedModules() {
+			modules[name] = module
+		}
+
+		if err := mergo.Merge(&data, result.Documents); err != nil {
+			return nil, nil, fmt.Errorf("failed to merge data of custom rules: %w", err)
+		}
+	}
+
+	if l.customRuleFS != nil && l.customRuleFSRootPath != "" {
+		files, err := loadModulesFromCustomRuleFS(l.customRuleFS, l.customRuleFSRootPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load custom rules from FS: %w", err)
+		}
+
+		for path, content := range files {
+			module, err := parse.Module(path, content)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse custom rule %s: %w", path, err)
+			}
+
+			modules[path] = module
+		}
+	}
+
+	return modules, data, nil
+}
+
+func bundleModules(b *bundle.Bundle) map[string]*ast.Module {
+	modules := make(map[string]*ast.Module, len(b.Modules))
+
+	for _, module := range b.Modules {
+		modules[module.Path] = module.Parsed
+	}
+
+	return modules
+}
+
+// enabledRules returns the rules found in modules, as category/title, that are enabled, either by the configuration,
+// or by command line flags. This mirrors how data.regal.config.for_rule and the main policy determine which rules to
+// run.
+func (l Linter) enabledRules(modules map[string]*ast.Module) (map[string]bool, error) {
+	conf, err := l.mergedConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	enabled := make(map[string]bool)
+
+	for _, module := range modules {
+		category, title, ok := ruleFromPackage(module.Package.Path)
+		if !ok {
+			continue
+		}
+
+		ruleConfig, configured := conf.Rules[category][title]
+
+		// bundled rules are only run when found in the configuration, while custom rules are run by default
+		if !configured && module.Package.Path.HasPrefix(rulePackagePrefixes[0]) {
+			continue
+		}
+
+		if l.ruleEnabled(category, title, ruleConfig.Level) {
+			enabled[category+"/"+title] = true
+		}
+	}
+
+	return enabled, nil
+}
+
+// requiredRules returns the enabled rules, along with the rules referenced by any of them, or by any other module
+// which isn't a rule, as category/title.
+func requiredRules(modules map[string]*ast.Module, enabled map[string]bool) map[string]bool {
+	required := maps.Clone(enabled)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, module := range modules {
+			if category, title, ok := ruleFromPackage(module.Package.Path); ok && !required[category+"/"+title] {
+				continue
+			}
+
+			ast.WalkRefs(module, func(ref ast.Ref) bool {
+				if category, title, ok := ruleOfRef(ref); ok && !required[category+"/"+title] {
+					required[category+"/"+title] = true
+					changed = true
+				}
+
+				return false
+			})
+		}
+	}
+
+	return required
+}
+
+func (l Linter) ruleEnabled(category, title, level string) bool {
+	switch {
+	case util.Contains(l.disable, title):
+		return false
+	case l.disableAll && !util.Contains(l.enableCategory, category) && !util.Contains(l.enable, title):
+		return false
+	case util.Contains(l.disableCategory, category) && !util.Contains(l.enable, title):
+		return false
+	case util.Contains(l.enable, title):
+		return true
+	case l.enableAll && !util.Contains(l.disableCategory, category):
+		return true
+	case util.Contains(l.enableCategory, category):
+		return true
+	}
+
+	return level != "ignore"
+}
+
+func (l Linter) printStatementsEnabled() bool {
+	return l.debugMode || l.printHook != nil
+}
+
+// customBuiltins returns the declarations of the custom built-in functions provided to the linter rules.
+func customBuiltins() map[string]*ast.Builtin {
+	decls := make(map[string]*ast.Builtin)
+
+	functions := []*rego.Function{builtins.RegalParseModuleMeta, builtins.RegalJSONPrettyMeta, builtins.RegalLastMeta}
+
+	for _, f := range functions {
+		decls[f.Name] = &ast.Builtin{Name: f.Name, Decl: f.Decl}
+	}
+
+	return decls
+}
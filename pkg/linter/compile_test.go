@@ -0,0 +1,156 @@
+package linter
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+func TestEmbeddedRulesCompiledOnce(t *testing.T) {
+	t.Parallel()
+
+	first, err := NewLinter().compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	second, err := NewLinter().compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	if first != second {
+		t.Errorf("expected embedded rules to be compiled only once")
+	}
+
+	third, err := NewLinter().WithDisabledRules("line-length").compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	if third == first {
+		t.Errorf("expected linter with other rules enabled to compile its own rules")
+	}
+
+	if _, ok := first.data["regal"]; !ok {
+		t.Errorf("expected data of embedded bundle to be included")
+	}
+
+	custom, err := NewLinter().WithCustomRules([]string{"testdata/custom.rego"}).compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	if custom == first {
+		t.Errorf("expected linter with custom rules to compile its own rules")
+	}
+}
+
+func TestOnlyEnabledCategoriesCompiled(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := NewLinter().WithDisableAll(true).WithEnabledCategories("bugs").compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	categories := make(map[string]bool)
+
+	for _, module := range compiled.compiler.Modules {
+		if category, _, ok := ruleFromPackage(module.Package.Path); ok {
+			categories[category] = true
+		}
+	}
+
+	if len(categories) != 1 || !categories["bugs"] {
+		t.Errorf("expected only rules of the bugs category to be compiled, got %v", categories)
+	}
+
+	if compiled.compiler.GetRules(ast.MustParseRef("data.regal.config.for_rule")) == nil {
+		t.Errorf("expected modules other than rules to be compiled")
+	}
+
+	custom, err := NewLinter().
+		WithDisabledCategories("bugs").
+		WithCustomRules([]string{"testdata/custom.rego"}).
+		compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	customCategories := make(map[string]bool)
+
+	for _, module := range custom.compiler.Modules {
+		if category, _, ok := ruleFromPackage(module.Package.Path); ok {
+			customCategories[category] = true
+		}
+	}
+
+	if customCategories["bugs"] || !customCategories["naming"] {
+		t.Errorf("expected custom rules, but not the disabled bugs category, to be compiled, got %v", customCategories)
+	}
+}
+
+func TestDisabledRulesNotCompiled(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := NewLinter().
+		WithDisabledRules("line-length", "non-raw-regex-pattern").
+		WithEnabledRules("prefer-raw-string").
+		compile()
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err)
+	}
+
+	rules := make(map[string]bool)
+
+	for _, module := range compiled.compiler.Modules {
+		if category, title, ok := ruleFromPackage(module.Package.Path); ok {
+			rules[category+"/"+title] = true
+		}
+	}
+
+	if rules["style/line-length"] {
+		t.Errorf("expected disabled rule to not be compiled")
+	}
+
+	if !rules["style/prefer-snake-case"] {
+		t.Errorf("expected enabled rule to be compiled")
+	}
+
+	// prefer-raw-string imports non-raw-regex-pattern for its helpers
+	if !rules["idiomatic/non-raw-regex-pattern"] {
+		t.Errorf("expected disabled rule referenced by enabled rule to be compiled")
+	}
+
+	query, err := NewLinter().
+		WithDisabledRules("line-length").
+		prepareQuery(context.Background(), ast.MustParseBody(`rules := object.keys(data.regal.rules.style)`))
+	if err != nil {
+		t.Fatalf("failed to prepare query: %s", err)
+	}
+
+	rs, err := query.Eval(context.Background())
+	if err != nil {
+		t.Fatalf("failed to evaluate query: %s", err)
+	}
+
+	styleRules, _ := rs[0].Bindings["rules"].([]any)
+	if len(styleRules) == 0 || slices.Contains(styleRules, any("line-length")) {
+		t.Errorf("expected disabled rule to be absent from prepared query, got %v", styleRules)
+	}
+}
+
+// BenchmarkCompileEmbeddedRules measures the compilation of the embedded rules, which a single run of the lint
+// command pays once at startup.
+func BenchmarkCompileEmbeddedRules(b *testing.B) {
+	linter := NewLinter()
+
+	for range b.N {
+		if _, err := linter.compileRules(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package linter
+
+import (
+	"path/filepath"
+
+	"github.com/open-policy-agent/opa/cover"
+
+	rio "github.com/styrainc/regal/internal/io"
+)
+
+// WithCoverage provides a coverage report, as produced by `opa test --coverage`, for the untested-rule rule to
+// report rules not covered by tests. Files linted but missing from the report are not checked.
+func (l Linter) WithCoverage(report *cover.Report) Linter {
+	l.coverage = report
+
+	return l
+}
+
+// coverageByFile returns the coverage of each of the named files, for use in data.internal.coverage. As the files
+// of the report may be referred to by other paths than the files linted, like when opa test was run from another
+// directory, files not found by name are matched by absolute path.
+func coverageByFile(report *cover.Report, names []string) map[string]any {
+	byAbsPath := make(map[string]*cover.FileReport, len(report.Files))
+
+	for name, file := range report.Files {
+		if abs, err := filepath.Abs(name); err == nil {
+			byAbsPath[abs] = file
+		}
+	}
+
+	coverage := make(map[string]any, len(names))
+
+	for _, name := range names {
+		file, ok := report.Files[name]
+		if !ok {
+			abs, err := filepath.Abs(name)
+			if err != nil {
+				continue
+			}
+
+			if file, ok = byAbsPath[abs]; !ok {
+				continue
+			}
+		}
+
+		// only the covered lines are used, so the rest of the report is left out
+		coverage[name] = rio.ToMap(cover.FileReport{Covered: file.Covered})
+	}
+
+	return coverage
+}
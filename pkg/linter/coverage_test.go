@@ -0,0 +1,72 @@
+package linter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cover"
+
+	"github.com/styrainc/regal/internal/test"
+	"github.com/styrainc/regal/internal/testutil"
+)
+
+func TestLintWithCoverage(t *testing.T) {
+	t.Parallel()
+
+	policy := `package p
+
+import rego.v1
+
+allow if input.user == "admin"
+
+deny if input.user == "guest"
+`
+
+	abs := testutil.Must(filepath.Abs("q.rego"))(t)
+
+	input := test.InputPolicy("p.rego", policy)
+	input.FileNames = append(input.FileNames, "q.rego", "r.rego")
+	input.Modules["q.rego"] = input.Modules["p.rego"]
+	input.FileContent["q.rego"] = policy
+	input.Modules["r.rego"] = input.Modules["p.rego"]
+	input.FileContent["r.rego"] = policy
+
+	covered := &cover.FileReport{Covered: []cover.Range{{Start: cover.Position{Row: 5}, End: cover.Position{Row: 5}}}}
+
+	linter := NewLinter().
+		WithDisableAll(true).
+		WithEnabledRules("untested-rule").
+		WithInputModules(&input).
+		WithCoverage(&cover.Report{Files: map[string]*cover.FileReport{
+			"p.rego": covered,
+			// matched by absolute path
+			abs: {},
+		}})
+
+	result := testutil.Must(linter.Lint(context.Background()))(t)
+
+	found := make(map[string][]int)
+
+	for _, violation := range result.Violations {
+		if violation.Title != "untested-rule" {
+			t.Errorf("expected only untested-rule violations, got %s", violation.Title)
+		}
+
+		found[violation.Location.File] = append(found[violation.Location.File], violation.Location.Row)
+	}
+
+	if len(found["p.rego"]) != 1 || found["p.rego"][0] != 7 {
+		t.Errorf("expected violation for deny in p.rego, got %v", found["p.rego"])
+	}
+
+	// no lines covered, so both rules are untested
+	if len(found["q.rego"]) != 2 {
+		t.Errorf("expected 2 violations in q.rego, got %v", found["q.rego"])
+	}
+
+	// not in the coverage report, so not checked
+	if len(found["r.rego"]) != 0 {
+		t.Errorf("expected no violations in r.rego, got %v", found["r.rego"])
+	}
+}
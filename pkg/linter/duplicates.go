@@ -0,0 +1,226 @@
+package linter
+
+import (
+	"maps"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+//nolint:gochecknoglobals
+var (
+	// the result package only includes the name of the file as it is, in locations and the source of aggregates,
+	// and is replaced with that of the duplicate when results are copied from one file to another.
+	resultPackage = ast.MustParseRef("data.regal.result")
+	fileNameRef   = ast.MustParseRef("input.regal.file.name")
+)
+
+// duplicateFiles returns the files of input with contents identical to a file linted before them, mapped to the
+// name of that file. As vendored or generated policies are often found in many places, the rules need only be
+// evaluated once for each distinct contents, with the results copied to the duplicates.
+func duplicateFiles(input rules.Input) map[string]string {
+	first := make(map[string]string, len(input.FileNames))
+	duplicates := make(map[string]string)
+
+	for _, name := range input.FileNames {
+		content := input.FileContent[name]
+
+		if original, ok := first[content]; ok {
+			duplicates[name] = original
+		} else {
+			first[content] = name
+		}
+	}
+
+	return duplicates
+}
+
+// fileNameDependentRules returns the rules, by "category/title", whose results may depend on the name of the file
+// linted rather than only its contents. These rules are evaluated for every file, even if its contents are
+// identical to those of another file. A rule is considered to depend on the name of the file when it, or any rule
+// or function it depends on, refers to the name (or anything that may include it, like input.regal), or when it's
+// configured to ignore files by name.
+func fileNameDependentRules(compiler *ast.Compiler, conf config.Config) map[string]bool {
+	dependent := make(map[string]bool)
+	visited := make(map[*ast.Rule]bool)
+	refersToName := make(map[*ast.Rule]bool)
+
+	var visit func(rule *ast.Rule) bool
+
+	visit = func(rule *ast.Rule) bool {
+		if visited[rule] {
+			return refersToName[rule]
+		}
+
+		visited[rule] = true
+
+		if rule.Module != nil && rule.Module.Package.Path.HasPrefix(resultPackage) {
+			return false
+		}
+
+		found := false
+
+		ast.WalkRefs(rule, func(ref ast.Ref) bool {
+			if mayReferToFileName(ref) {
+				found = true
+			}
+
+			return found
+		})
+
+		for dependency := range compiler.Graph.Dependencies(rule) {
+			if dep, ok := dependency.(*ast.Rule); ok && !found && visit(dep) {
+				found = true
+			}
+		}
+
+		refersToName[rule] = found
+
+		return found
+	}
+
+	for _, module := range compiler.Modules {
+		category, title, ok := ruleFromPackage(module.Package.Path)
+		if !ok {
+			continue
+		}
+
+		if ignore := conf.Rules[category][title].Ignore; ignore != nil && len(ignore.Files) > 0 {
+			dependent[category+"/"+title] = true
+
+			continue
+		}
+
+		for _, rule := range module.Rules {
+			if visit(rule) {
+				dependent[category+"/"+title] = true
+
+				break
+			}
+		}
+	}
+
+	return dependent
+}
+
+// mayReferToFileName returns whether the ref may refer to input.regal.file.name, either directly, by referring
+// to a document including it, or by a variable in its path.
+func mayReferToFileName(ref ast.Ref) bool {
+	if !ref.HasPrefix(ast.InputRootRef) {
+		return false
+	}
+
+	for i := 1; i < len(ref) && i < len(fileNameRef); i++ {
+		if !ref[i].IsGround() {
+			return true
+		}
+
+		if !ref[i].Equal(fileNameRef[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// duplicateInput returns the input prepared for the original file, but with the name of the duplicate, and
+// only the rules provided to be evaluated. The input of the original file is shared, and is not modified.
+func duplicateInput(original map[string]any, name string, ruleNames []string) map[string]any {
+	input := maps.Clone(original)
+
+	regal, _ := original["regal"].(map[string]any)
+	regal = maps.Clone(regal)
+
+	file, _ := regal["file"].(map[string]any)
+	file = maps.Clone(file)
+	file["name"] = name
+
+	only := make([]any, 0, len(ruleNames))
+	for _, ruleName := range ruleNames {
+		only = append(only, ruleName)
+	}
+
+	regal["file"] = file
+	regal["rules"] = only
+	input["regal"] = regal
+
+	return input
+}
+
+// copyReport returns the results of linting the original file as those of the duplicate file, leaving out the
+// results of the rules provided, which are evaluated for the duplicate.
+func copyReport(original report.Report, from, to string, exclude map[string]bool) report.Report {
+	copied := report.Report{
+		Notices:    original.Notices,
+		Aggregates: make(map[string][]report.Aggregate, len(original.Aggregates)),
+	}
+
+	for _, violation := range original.Violations {
+		if exclude[violation.Category+"/"+violation.Title] {
+			continue
+		}
+
+		if violation.Location.File == from {
+			violation.Location.File = to
+		}
+
+		copied.Violations = append(copied.Violations, violation)
+	}
+
+	for _, violation := range original.Suppressed {
+		if exclude[violation.Category+"/"+violation.Title] {
+			continue
+		}
+
+		if violation.Location.File == from {
+			violation.Location.File = to
+		}
+
+		copied.Suppressed = append(copied.Suppressed, violation)
+	}
+
+	for key, aggregates := range original.Aggregates {
+		if exclude[key] {
+			continue
+		}
+
+		for _, aggregate := range aggregates {
+			renamed, _ := replaceFileName(map[string]any(aggregate), from, to).(map[string]any)
+
+			copied.Aggregates[key] = append(copied.Aggregates[key], renamed)
+		}
+	}
+
+	return copied
+}
+
+// replaceFileName returns a copy of value with the name of the file replaced wherever found. Besides the source of
+// the aggregate, the name is included in any location provided by the result package, like those of the imports
+// collected by the unresolved-import rule.
+func replaceFileName(value any, from, to string) any {
+	switch v := value.(type) {
+	case string:
+		if v == from {
+			return to
+		}
+	case map[string]any:
+		replaced := make(map[string]any, len(v))
+		for key, item := range v {
+			replaced[key] = replaceFileName(item, from, to)
+		}
+
+		return replaced
+	case []any:
+		replaced := make([]any, len(v))
+		for i, item := range v {
+			replaced[i] = replaceFileName(item, from, to)
+		}
+
+		return replaced
+	}
+
+	return value
+}
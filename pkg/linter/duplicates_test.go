@@ -0,0 +1,109 @@
+package linter
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/internal/testutil"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+func TestLintDuplicateFiles(t *testing.T) {
+	t.Parallel()
+
+	policy := `package p
+
+import rego.v1
+
+import data.unresolved
+
+test_long_line if "this line is long enough to be reported by the line-length rule, when configured to be short"
+`
+
+	names := []string{"a/p.rego", "b/p_test.rego", "c/p.rego"}
+
+	content := make(map[string]string, len(names))
+	modules := make(map[string]*ast.Module, len(names))
+
+	for _, name := range names {
+		content[name] = policy
+		modules[name] = parse.MustParseModule(policy)
+	}
+
+	userConfig := config.Config{Rules: map[string]config.Category{
+		"style": {"line-length": config.Rule{Level: "error", Extra: config.ExtraAttributes{"max-line-length": 80}}},
+	}}
+
+	input := rules.NewInput(content, modules)
+	result := testutil.Must(NewLinter().WithUserConfig(userConfig).WithInputModules(&input).Lint(context.Background()))(t)
+
+	// the expected violations are those found when linting each file on its own, along with those of the
+	// aggregate rules, i.e. unresolved-import for each file, and no-defined-entrypoint
+	var expected []report.Violation
+
+	for _, name := range names {
+		single := rules.NewInput(map[string]string{name: policy}, map[string]*ast.Module{name: modules[name]})
+		singleResult := testutil.Must(
+			NewLinter().WithUserConfig(userConfig).WithInputModules(&single).Lint(context.Background()),
+		)(t)
+
+		expected = append(expected, singleResult.Violations...)
+	}
+
+	titles := func(violations []report.Violation) []string {
+		result := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			result = append(result, violation.Location.File+":"+violation.Title)
+		}
+
+		slices.Sort(result)
+
+		return result
+	}
+
+	got := titles(result.Violations)
+	want := titles(expected)
+
+	for _, name := range names {
+		want = append(want, name+":unresolved-import")
+	}
+
+	want = append(want, ":no-defined-entrypoint")
+
+	slices.Sort(want)
+
+	if !slices.Equal(want, got) {
+		t.Errorf("expected violations %v, got %v", want, got)
+	}
+}
+
+func TestFileNameDependentRules(t *testing.T) {
+	t.Parallel()
+
+	linter := NewLinter().WithUserConfig(config.Config{Rules: map[string]config.Category{
+		"bugs": {"constant-condition": config.Rule{Level: "error", Ignore: &config.Ignore{Files: []string{"*_test.rego"}}}},
+	}})
+
+	compiled := testutil.Must(linter.compile())(t)
+	conf := testutil.Must(linter.mergedConfig())(t)
+
+	dependent := fileNameDependentRules(compiled.compiler, conf)
+
+	for _, rule := range []string{"style/line-length", "testing/file-missing-test-suffix", "bugs/constant-condition"} {
+		if !dependent[rule] {
+			t.Errorf("expected %s to depend on the name of the file", rule)
+		}
+	}
+
+	for _, rule := range []string{"imports/unresolved-import", "style/prefer-snake-case"} {
+		if dependent[rule] {
+			t.Errorf("expected %s not to depend on the name of the file", rule)
+		}
+	}
+}
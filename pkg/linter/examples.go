@@ -0,0 +1,273 @@
+package linter
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+// SkipExampleMarker placed between the line announcing an example of a rule's documentation and its code has the
+// example skipped by ExamplesFromDoc, for examples depending on more than their code, like coverage or schemas.
+const SkipExampleMarker = "<!-- regal docs verify: skip -->"
+
+// Example is a code example of a rule, like those of its documentation, which the rule is expected to flag, when Fail
+// is set, or otherwise to pass, for the documentation of rules to be kept truthful as rules evolve.
+type Example struct {
+	Category string
+	Title    string
+	// Source is where the example is from, like the path of a documentation file, and the line the example is on.
+	Source string
+	// Modules are the Rego code of the example, of which there may be more than one, like a policy and its tests.
+	Modules []string
+	Fail    bool
+}
+
+// ID returns the identifier of the rule of the example, made up of its category and title.
+func (e Example) ID() string {
+	return e.Category + "/" + e.Title
+}
+
+// ExampleFailure is an example not flagged, or passed, by its rule as expected, with the reason why.
+type ExampleFailure struct {
+	Example Example
+	Reason  string
+}
+
+// ExamplesFromDoc returns the examples of the documentation of a rule, in the format of the documentation of the
+// rules of Regal, where the Rego code blocks following an **Avoid** line are expected to be flagged by the rule, and
+// those following a **Prefer** line to pass. Consecutive code blocks make up a single example of several modules.
+// The title of the rule is that of the document, and the category that of the **Category** line. Documents without
+// a title and category have no examples returned.
+func ExamplesFromDoc(path, content string) []Example {
+	var (
+		examples []Example
+		category string
+		title    string
+		current  *Example
+		skip     bool
+		fence    string
+		code     strings.Builder
+	)
+
+	finish := func() {
+		if current != nil && len(current.Modules) > 0 && !skip {
+			examples = append(examples, *current)
+		}
+
+		current, skip = nil, false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+
+	for row := 1; scanner.Scan(); row++ {
+		line := scanner.Text()
+
+		switch {
+		case fence != "":
+			if strings.TrimSpace(line) == fence {
+				current.Modules = append(current.Modules, code.String())
+				fence = ""
+
+				code.Reset()
+
+				continue
+			}
+
+			code.WriteString(line + "\n")
+		case title == "" && strings.HasPrefix(line, "# "):
+			title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		case strings.HasPrefix(line, "**Category**:"):
+			category = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "**Category**:")))
+		case line == "**Avoid**" || line == "**Prefer**":
+			finish()
+
+			current = &Example{Fail: line == "**Avoid**"}
+		case current != nil && strings.TrimSpace(line) == "":
+			// blank lines between the line announcing an example and its code blocks
+		case current != nil && strings.TrimSpace(line) == SkipExampleMarker:
+			skip = true
+		case current != nil && strings.HasPrefix(line, "```") && strings.TrimLeft(line, "`") == "rego":
+			fence = strings.TrimSuffix(line, "rego")
+
+			if len(current.Modules) == 0 {
+				current.Source = fmt.Sprintf("%s:%d", path, row+1)
+			}
+		default:
+			finish()
+		}
+	}
+
+	finish()
+
+	if title == "" || category == "" {
+		return nil
+	}
+
+	for i := range examples {
+		examples[i].Category = category
+		examples[i].Title = title
+	}
+
+	return examples
+}
+
+// RuleExamples returns the examples of the Rego rules of the linter, including custom rules, found in the metadata of
+// their packages, as lists of code examples expected to fail or pass under the examples attribute of custom:
+//
+//	# METADATA
+//	# description: Avoid using deprecated functions
+//	# custom:
+//	#   examples:
+//	#     fail:
+//	#       - |
+//	#         package policy
+//	#         ...
+//	#     pass:
+//	#       - ...
+func (l Linter) RuleExamples() ([]Example, error) {
+	modules, _, err := l.ruleModules()
+	if err != nil {
+		return nil, err
+	}
+
+	var examples []Example
+
+	for path, module := range modules {
+		category, title, ok := ruleFromPackage(module.Package.Path)
+		if !ok {
+			continue
+		}
+
+		for _, annotation := range module.Annotations {
+			if annotation.Scope != "package" {
+				continue
+			}
+
+			exampleMap, ok := annotation.Custom["examples"].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for key, fail := range map[string]bool{"fail": true, "pass": false} {
+				codes, ok := exampleMap[key].([]any)
+				if !ok {
+					continue
+				}
+
+				for i, code := range codes {
+					s, ok := code.(string)
+					if !ok {
+						return nil, fmt.Errorf("%s: example %d of %s was not a string", path, i, key)
+					}
+
+					examples = append(examples, Example{
+						Category: category,
+						Title:    title,
+						Source:   fmt.Sprintf("%s (%s example %d)", path, key, i+1),
+						Modules:  []string{s},
+						Fail:     fail,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(examples, func(a, b Example) int {
+		return cmp.Compare(a.Source, b.Source)
+	})
+
+	return examples, nil
+}
+
+// VerifyExamples lints each example with only its rule enabled, returning the examples not flagged, or passed, by the
+// rule as expected. Examples failing to parse fail verification too. Examples with a test package are linted as test
+// files, i.e. with a name ending with _test.rego.
+func (l Linter) VerifyExamples(ctx context.Context, examples []Example) ([]ExampleFailure, error) {
+	var failures []ExampleFailure
+
+	l.inputPaths = nil
+
+	for _, example := range examples {
+		input, err := exampleInput(example)
+		if err != nil {
+			failures = append(failures, ExampleFailure{Example: example, Reason: err.Error()})
+
+			continue
+		}
+
+		exampleLinter := l.WithDisableAll(true).
+			WithEnabledRules(example.Title).
+			WithInputModules(&input).
+			WithExportAggregates(true)
+
+		rep, err := exampleLinter.Lint(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint example %s: %w", example.Source, err)
+		}
+
+		// aggregate rules are only run for more than one file, unless run on the aggregates of the file explicitly
+		aggregateRep, err := exampleLinter.LintAggregates(ctx, rep.Aggregates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint aggregates of example %s: %w", example.Source, err)
+		}
+
+		rep.Violations = append(rep.Violations, aggregateRep.Violations...)
+
+		i := slices.IndexFunc(rep.Violations, func(v report.Violation) bool {
+			return v.Category == example.Category && v.Title == example.Title
+		})
+
+		switch {
+		case example.Fail && i == -1:
+			failures = append(failures, ExampleFailure{
+				Example: example,
+				Reason:  fmt.Sprintf("expected %s to flag example, but it passed", example.ID()),
+			})
+		case !example.Fail && i != -1:
+			failures = append(failures, ExampleFailure{
+				Example: example,
+				Reason: fmt.Sprintf("expected %s to pass example, but it was flagged on line %d: %s",
+					example.ID(), rep.Violations[i].Location.Row, rep.Violations[i].Description),
+			})
+		}
+	}
+
+	return failures, nil
+}
+
+// exampleInput returns the input of the modules of an example, named example.rego, or example_1.rego, example_2.rego
+// and so on for several modules, with modules of test packages named like test files, i.e. example_test.rego.
+func exampleInput(example Example) (rules.Input, error) {
+	fileContent := make(map[string]string, len(example.Modules))
+	modules := make(map[string]*ast.Module, len(example.Modules))
+
+	for i, code := range example.Modules {
+		name := "example"
+		if len(example.Modules) > 1 {
+			name = fmt.Sprintf("example_%d", i+1)
+		}
+
+		module, err := parse.Module(name+".rego", code)
+		if err != nil {
+			return rules.Input{}, fmt.Errorf("failed to parse: %w", err)
+		}
+
+		if strings.HasSuffix(module.Package.Path.String(), "_test") {
+			name += "_test"
+		}
+
+		fileContent[name+".rego"] = code
+		modules[name+".rego"] = module
+	}
+
+	return rules.NewInput(fileContent, modules), nil
+}
@@ -0,0 +1,112 @@
+package linter
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExamplesFromDoc(t *testing.T) {
+	t.Parallel()
+
+	doc := "# my-rule\n\n**Summary**: Don't do that\n\n**Category**: Style\n\n" +
+		"**Avoid**\n```rego\npackage p\n\nx = 1\n```\n\n" +
+		"**Prefer**\n\n```rego\npackage p\n\nx := 1\n```\n\n```rego\npackage p_test\n```\n\n" +
+		"**Avoid**\n" + SkipExampleMarker + "\n```rego\npackage p\n```\n\n" +
+		"**Prefer**\nSome words, not code\n```rego\npackage p\n```\n"
+
+	examples := ExamplesFromDoc("my-rule.md", doc)
+
+	if len(examples) != 2 {
+		t.Fatalf("expected 2 examples, got %d: %+v", len(examples), examples)
+	}
+
+	if ex := examples[0]; ex.ID() != "style/my-rule" || !ex.Fail || ex.Source != "my-rule.md:9" ||
+		!slices.Equal(ex.Modules, []string{"package p\n\nx = 1\n"}) {
+		t.Errorf("unexpected first example %+v", ex)
+	}
+
+	if ex := examples[1]; ex.Fail || ex.Source != "my-rule.md:17" ||
+		!slices.Equal(ex.Modules, []string{"package p\n\nx := 1\n", "package p_test\n"}) {
+		t.Errorf("unexpected second example %+v", ex)
+	}
+
+	if examples := ExamplesFromDoc("README.md", "# README\n\n**Avoid**\n```rego\npackage p\n```\n"); examples != nil {
+		t.Errorf("expected no examples from document without category, got %+v", examples)
+	}
+}
+
+func TestVerifyExamples(t *testing.T) {
+	t.Parallel()
+
+	examples := []Example{
+		{Category: "style", Title: "use-assignment-operator", Source: "fail", Fail: true, Modules: []string{
+			"package p\n\nimport rego.v1\n\nx = 1\n",
+		}},
+		{Category: "style", Title: "use-assignment-operator", Source: "pass", Modules: []string{
+			"package p\n\nimport rego.v1\n\nx := 1\n",
+		}},
+		{Category: "style", Title: "use-assignment-operator", Source: "wrongly pass", Fail: true, Modules: []string{
+			"package p\n\nimport rego.v1\n\nx := 1\n",
+		}},
+		{Category: "style", Title: "use-assignment-operator", Source: "wrongly fail", Modules: []string{
+			"package p\n\nimport rego.v1\n\nx = 1\n",
+		}},
+		{Category: "style", Title: "use-assignment-operator", Source: "parse error", Modules: []string{
+			"package p\n\nx :=\n",
+		}},
+	}
+
+	failures, err := NewLinter().VerifyExamples(context.Background(), examples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"wrongly pass": "expected style/use-assignment-operator to flag example, but it passed",
+		"wrongly fail": "expected style/use-assignment-operator to pass example, but it was flagged on line 5",
+		"parse error":  "failed to parse",
+	}
+
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %d: %+v", len(expected), len(failures), failures)
+	}
+
+	for _, failure := range failures {
+		if !strings.HasPrefix(failure.Reason, expected[failure.Example.Source]) {
+			t.Errorf("expected reason of %s to start with %q, got %q",
+				failure.Example.Source, expected[failure.Example.Source], failure.Reason)
+		}
+	}
+}
+
+func TestVerifyExamplesOfCustomRules(t *testing.T) {
+	t.Parallel()
+
+	linter := NewLinter().WithCustomRules([]string{filepath.Join("testdata", "custom.rego")})
+
+	examples, err := linter.RuleExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(examples) != 3 {
+		t.Fatalf("expected 3 examples, got %d: %+v", len(examples), examples)
+	}
+
+	if fails := slices.IndexFunc(examples, func(e Example) bool { return e.Fail }); fails == -1 ||
+		examples[fails].ID() != "naming/acme-corp-package" {
+		t.Errorf("expected failing example of naming/acme-corp-package, got %+v", examples)
+	}
+
+	failures, err := linter.VerifyExamples(context.Background(), examples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failures) != 0 {
+		t.Errorf("expected no failures, got %+v", failures)
+	}
+}
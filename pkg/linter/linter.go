@@ -7,27 +7,33 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"maps"
 	"os"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/gobwas/glob"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v3"
 
 	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/cover"
 	"github.com/open-policy-agent/opa/metrics"
 	"github.com/open-policy-agent/opa/profiler"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
 	"github.com/open-policy-agent/opa/topdown"
 	"github.com/open-policy-agent/opa/topdown/print"
 
-	rbundle "github.com/styrainc/regal/bundle"
+	"github.com/styrainc/regal/internal/debugbundle"
 	rio "github.com/styrainc/regal/internal/io"
 	regalmetrics "github.com/styrainc/regal/internal/metrics"
 	"github.com/styrainc/regal/internal/parse"
+	"github.com/styrainc/regal/internal/tracing"
 	"github.com/styrainc/regal/internal/util"
 	"github.com/styrainc/regal/pkg/builtins"
 	"github.com/styrainc/regal/pkg/config"
@@ -56,8 +62,25 @@ type Linter struct {
 	enableAll            bool
 	enableCategory       []string
 	ignoreFiles          []string
+	traversal            *config.Traversal
+	timeout              *time.Duration
+	debugBundle          *debugbundle.Recorder
 	metrics              metrics.Metrics
 	profiling            bool
+	profilingLimit       int
+	exportAggregates     bool
+	batchSize            int
+	compiled             *compiledRules
+	preparedASTCache     *parse.PreparedASTCache
+	coverage             *cover.Report
+	baseline             *report.Baseline
+	lintedFiles          func(path string) bool
+	showSuppressed       bool
+	sourceSnippets       bool
+	snippetContextLines  int
+	// unresolvedRulesConfig leaves the configuration of each rule to be resolved for every file linted, and is
+	// only set by benchmarks, to compare with the configuration resolved before linting
+	unresolvedRulesConfig bool
 }
 
 //nolint:gochecknoglobals
@@ -66,18 +89,20 @@ var (
 	lintQuery = ast.MustParseBody(`lint := {
 		"violations": data.regal.main.lint.violations,
 		"notices": data.regal.main.lint.notices,
+		"suppressed": data.regal.main.lint.suppressed,
 	}`)
 	// More than one file provided as input.
 	lintAndCollectQuery     = ast.MustParseBody("lint := data.regal.main.lint")
 	lintWithAggregatesQuery = ast.MustParseBody("lint_aggregate := data.regal.main.lint_aggregate")
+	// Only aggregates collected from files read for context, which aren't linted.
+	collectAggregatesQuery = ast.MustParseBody(`lint := {"aggregates": data.regal.main.lint.aggregates}`)
 )
 
 // NewLinter creates a new Regal linter.
 func NewLinter() Linter {
-	regalRules := rio.MustLoadRegalBundleFS(rbundle.Bundle)
-
 	return Linter{
-		ruleBundles: []*bundle.Bundle{&regalRules},
+		ruleBundles:    []*bundle.Bundle{loadEmbeddedBundle()},
+		profilingLimit: 10,
 	}
 }
 
@@ -183,6 +208,28 @@ func (l Linter) WithIgnore(ignore []string) Linter {
 	return l
 }
 
+// WithTraversal sets how the directories of the input paths are walked to find the files to lint, in place of the
+// traversal options of the configuration.
+func (l Linter) WithTraversal(traversal config.Traversal) Linter {
+	l.traversal = &traversal
+
+	return l
+}
+
+// WithTimeout limits the time linting may take, overriding the timeout of the configuration. Zero means no limit.
+func (l Linter) WithTimeout(timeout time.Duration) Linter {
+	l.timeout = &timeout
+
+	return l
+}
+
+// WithDebugBundle has the configuration used, and the files linted, recorded for a debug bundle.
+func (l Linter) WithDebugBundle(recorder *debugbundle.Recorder) Linter {
+	l.debugBundle = recorder
+
+	return l
+}
+
 // WithMetrics enables metrics collection.
 func (l Linter) WithMetrics(m metrics.Metrics) Linter {
 	l.metrics = m
@@ -203,6 +250,14 @@ func (l Linter) WithProfiling(enabled bool) Linter {
 	return l
 }
 
+// WithProfilingLimit sets the number of profile entries, sorted by total time, to include in the report when
+// profiling is enabled. A limit of 0 or less includes all entries. Defaults to 10.
+func (l Linter) WithProfilingLimit(limit int) Linter {
+	l.profilingLimit = limit
+
+	return l
+}
+
 // WithRootDir sets the root directory for the linter.
 // A door directory or prefix can be use to resolve relative paths
 // referenced in the linter configuration with absolute file paths or URIs.
@@ -212,8 +267,80 @@ func (l Linter) WithRootDir(rootDir string) Linter {
 	return l
 }
 
+// WithExportAggregates enables collecting aggregates from all files linted, including when only a single file
+// is linted, and returning them in the report. The aggregates may later be provided to LintAggregates, along
+// with aggregates collected from other files.
+func (l Linter) WithExportAggregates(enabled bool) Linter {
+	l.exportAggregates = enabled
+
+	return l
+}
+
+// WithShowSuppressed enables reporting the violations suppressed by ignore directives, or by files being ignored
+// for a rule in the configuration, which are otherwise left out of the report.
+func (l Linter) WithShowSuppressed(enabled bool) Linter {
+	l.showSuppressed = enabled
+
+	return l
+}
+
+// WithBaseline sets a baseline of known violations, which are left out of the report, or reported as suppressed
+// when WithShowSuppressed is enabled. Only violations not found in the baseline are reported.
+func (l Linter) WithBaseline(baseline *report.Baseline) Linter {
+	l.baseline = baseline
+
+	return l
+}
+
+// WithLintedFiles limits the files linted, of those found in the input paths, to the ones for which include returns
+// true, like files changed in a pull request. The other files are still read for the aggregates of aggregate rules,
+// which thereby consider all files, but violations are only reported for the files linted.
+func (l Linter) WithLintedFiles(include func(path string) bool) Linter {
+	l.lintedFiles = include
+
+	return l
+}
+
+// WithSourceSnippets enables including a snippet of the source in each violation reported, made up of the line of
+// the violation, and contextLines lines before and after it, so that excerpts of the code may be shown without reading
+// the files again. As the contents of files are kept until all files are linted, this adds to the memory needed
+// when linting in batches.
+func (l Linter) WithSourceSnippets(contextLines int) Linter {
+	l.sourceSnippets = true
+	l.snippetContextLines = max(contextLines, 0)
+
+	return l
+}
+
+// WithBatchSize sets the number of files from the input paths to load and lint at a time. Each batch is released
+// before the next is loaded, so that only the results, and the lightweight aggregates used by aggregate rules, are
+// kept for all files. This bounds the memory needed to lint large workspaces. A size of 0, the default, loads all
+// files at once.
+func (l Linter) WithBatchSize(size int) Linter {
+	l.batchSize = size
+
+	return l
+}
+
+// WithPreparedASTCache sets a cache of the input prepared from each file linted, which is reused when linting
+// the same contents again. This is useful when the same files are linted many times, like in the language server.
+func (l Linter) WithPreparedASTCache(cache *parse.PreparedASTCache) Linter {
+	l.preparedASTCache = cache
+
+	return l
+}
+
 // Lint runs the linter on provided policies.
-func (l Linter) Lint(ctx context.Context) (report.Report, error) {
+func (l Linter) Lint(ctx context.Context) (rep report.Report, err error) {
+	ctx, span := tracing.Start(ctx, "regal.lint")
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("regal.files_scanned", rep.Summary.FilesScanned),
+			attribute.Int("regal.violations", rep.Summary.NumViolations),
+		)
+		tracing.End(span, err)
+	}()
+
 	l.startTimer(regalmetrics.RegalLint)
 
 	finalReport := report.Report{}
@@ -222,24 +349,27 @@ func (l Linter) Lint(ctx context.Context) (report.Report, error) {
 		return report.Report{}, errors.New("nothing provided to lint")
 	}
 
-	conf, err := l.mergedConfig()
+	conf, err := l.load(ctx)
 	if err != nil {
-		return report.Report{}, fmt.Errorf("failed to merge config: %w", err)
+		return report.Report{}, err
 	}
 
-	l.combinedConfig = &conf
+	timeout := time.Duration(conf.Timeout)
+	if l.timeout != nil {
+		timeout = *l.timeout
+	}
 
-	l.dataBundle = &bundle.Bundle{
-		Manifest: bundle.Manifest{
-			Roots:    &[]string{"internal"},
-			Metadata: map[string]any{"name": "internal"},
-		},
-		Data: map[string]any{
-			"internal": map[string]any{
-				"combined_config": config.ToMap(*l.combinedConfig),
-				"capabilities":    rio.ToMap(config.CapabilitiesForThisVersion()),
-			},
-		},
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		defer func() {
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("linting timed out after %s, set a longer timeout, or 0 for none: %w", timeout, err)
+			}
+		}()
 	}
 
 	ignore := conf.Ignore.Files
@@ -250,58 +380,142 @@ func (l Linter) Lint(ctx context.Context) (report.Report, error) {
 
 	l.startTimer(regalmetrics.RegalFilterIgnoredFiles)
 
-	filtered, err := config.FilterIgnoredPaths(l.inputPaths, ignore, true, l.rootDir)
-	if err != nil {
-		return report.Report{}, fmt.Errorf("errors encountered when reading files to lint: %w", err)
+	traversal := conf.Traversal
+	if l.traversal != nil {
+		traversal = *l.traversal
 	}
 
-	l.stopTimer(regalmetrics.RegalFilterIgnoredFiles)
-	l.startTimer(regalmetrics.RegalInputParse)
-
-	inputFromPaths, err := rules.InputFromPaths(filtered)
+	filtered, err := config.FilterIgnoredPathsWithTraversal(l.inputPaths, ignore, l.rootDir, traversal)
 	if err != nil {
 		return report.Report{}, fmt.Errorf("errors encountered when reading files to lint: %w", err)
 	}
 
-	l.stopTimer(regalmetrics.RegalInputParse)
+	l.stopTimer(regalmetrics.RegalFilterIgnoredFiles)
 
-	input := inputFromPaths
+	var moduleNames []string
 
 	if l.inputModules != nil {
 		l.startTimer(regalmetrics.RegalFilterIgnoredModules)
 
-		filteredPaths, err := config.FilterIgnoredPaths(
-			l.inputModules.FileNames,
-			ignore,
-			false,
-			l.rootDir,
-		)
+		moduleNames, err = config.FilterIgnoredPaths(l.inputModules.FileNames, ignore, false, l.rootDir)
 		if err != nil {
 			return report.Report{}, fmt.Errorf("failed to filter paths: %w", err)
 		}
 
-		for _, filename := range filteredPaths {
-			input.FileNames = append(input.FileNames, filename)
-			input.Modules[filename] = l.inputModules.Modules[filename]
-			input.FileContent[filename] = l.inputModules.FileContent[filename]
+		l.stopTimer(regalmetrics.RegalFilterIgnoredModules)
+	}
+
+	// files not to be linted are only read for their aggregates, and only if there are any files to lint
+	var contextFiles []string
+
+	if l.lintedFiles != nil {
+		filtered, contextFiles = partitionFiles(filtered, l.lintedFiles)
+
+		if len(filtered)+len(moduleNames) == 0 {
+			contextFiles = nil
+		}
+	}
+
+	filesScanned := len(filtered) + len(moduleNames)
+
+	if l.debugBundle != nil {
+		l.debugBundle.AddConfig(strings.Join(l.inputPaths, ", "), conf)
+
+		// files are recorded before parsing, as those failing to parse are of most interest
+		for _, path := range filtered {
+			l.debugBundle.AddFileFromPath(path)
 		}
 
-		l.stopTimer(regalmetrics.RegalFilterIgnoredModules)
+		for _, name := range moduleNames {
+			l.debugBundle.AddFile(name, l.inputModules.FileContent[name])
+		}
 	}
 
-	goReport, err := l.lintWithGoRules(ctx, input)
-	if err != nil {
-		return report.Report{}, fmt.Errorf("failed to lint using Go rules: %w", err)
+	if internal, ok := l.dataBundle.Data["internal"].(map[string]any); ok {
+		if l.coverage != nil {
+			internal["coverage"] = coverageByFile(l.coverage, append(slices.Clone(filtered), moduleNames...))
+		}
+
+		if l.showSuppressed {
+			internal["show_suppressed"] = true
+		}
 	}
 
-	finalReport.Violations = append(finalReport.Violations, goReport.Violations...)
+	// aggregates are collected whenever there's more than one file to lint, even if batches of a single file
+	collectAggregates := filesScanned+len(contextFiles) > 1 || l.exportAggregates
 
-	regoReport, err := l.lintWithRegoRules(ctx, input)
-	if err != nil {
-		return report.Report{}, fmt.Errorf("failed to lint using Rego rules: %w", err)
+	regoReport := report.Report{Aggregates: make(map[string][]report.Aggregate)}
+
+	// contents of the files linted, kept for adding snippets once violations of aggregate rules are known too
+	var contents map[string]string
+	if l.sourceSnippets {
+		contents = make(map[string]string, filesScanned)
 	}
 
-	finalReport.Violations = append(finalReport.Violations, regoReport.Violations...)
+	for i, batch := range batches(filtered, l.batchSize) {
+		l.startTimer(regalmetrics.RegalInputParse)
+
+		_, parseSpan := tracing.Start(ctx, "regal.parse", attribute.Int("regal.files", len(batch)))
+
+		input, err := rules.InputFromPaths(batch)
+
+		tracing.End(parseSpan, err)
+
+		if err != nil {
+			return report.Report{}, fmt.Errorf("errors encountered when reading files to lint: %w", err)
+		}
+
+		l.stopTimer(regalmetrics.RegalInputParse)
+
+		// modules provided are already in memory, and so are linted along with the first batch
+		if i == 0 {
+			for _, filename := range moduleNames {
+				input.FileNames = append(input.FileNames, filename)
+				input.Modules[filename] = l.inputModules.Modules[filename]
+				input.FileContent[filename] = l.inputModules.FileContent[filename]
+			}
+		}
+
+		if l.sourceSnippets {
+			maps.Copy(contents, input.FileContent)
+		}
+
+		goReport, err := l.lintWithGoRules(ctx, input)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to lint using Go rules: %w", err)
+		}
+
+		finalReport.Violations = append(finalReport.Violations, goReport.Violations...)
+		finalReport.Suppressed = append(finalReport.Suppressed, goReport.Suppressed...)
+
+		batchReport, err := l.lintWithRegoRules(ctx, input, collectAggregates)
+		if err != nil {
+			return report.Report{}, fmt.Errorf("failed to lint using Rego rules: %w", err)
+		}
+
+		finalReport.Violations = append(finalReport.Violations, batchReport.Violations...)
+		finalReport.Suppressed = append(finalReport.Suppressed, batchReport.Suppressed...)
+		regoReport.Notices = append(regoReport.Notices, batchReport.Notices...)
+
+		for k, aggregates := range batchReport.Aggregates {
+			regoReport.Aggregates[k] = append(regoReport.Aggregates[k], aggregates...)
+		}
+
+		if l.profiling {
+			regoReport.AddProfileEntries(batchReport.AggregateProfile)
+		}
+	}
+
+	for _, batch := range batches(contextFiles, l.batchSize) {
+		aggregates, err := l.collectAggregates(ctx, batch)
+		if err != nil {
+			return report.Report{}, err
+		}
+
+		for k := range aggregates {
+			regoReport.Aggregates[k] = append(regoReport.Aggregates[k], aggregates[k]...)
+		}
+	}
 
 	rulesSkippedCounter := 0
 
@@ -315,20 +529,54 @@ func (l Linter) Lint(ctx context.Context) (report.Report, error) {
 		}
 	}
 
-	if len(input.FileNames) > 1 {
+	if filesScanned+len(contextFiles) > 1 {
 		aggregateReport, err := l.lintWithRegoAggregateRules(ctx, regoReport.Aggregates)
 		if err != nil {
 			return report.Report{}, fmt.Errorf("failed to lint using Rego aggregate rules: %w", err)
 		}
 
+		if len(contextFiles) > 0 {
+			readOnly := make(map[string]struct{}, len(contextFiles))
+			for _, path := range contextFiles {
+				readOnly[path] = struct{}{}
+			}
+
+			aggregateReport.Violations = slices.DeleteFunc(aggregateReport.Violations, func(v report.Violation) bool {
+				_, ok := readOnly[v.Location.File]
+
+				return ok
+			})
+		}
+
 		finalReport.Violations = append(finalReport.Violations, aggregateReport.Violations...)
 	}
 
+	if l.baseline != nil {
+		finalReport.ApplyBaseline(*l.baseline, l.showSuppressed)
+	}
+
+	finalReport.ApplyBudgets(func(category, title string) (int, bool) {
+		if rule, ok := conf.Rules[category][title]; ok && rule.MaxViolations != nil {
+			return *rule.MaxViolations, true
+		}
+
+		return 0, false
+	})
+
+	if l.sourceSnippets {
+		addSnippets(finalReport.Violations, contents, l.snippetContextLines)
+		addSnippets(finalReport.Suppressed, contents, l.snippetContextLines)
+	}
+
+	finalReport.Files = append(append(make([]string, 0, filesScanned), filtered...), moduleNames...)
+
 	finalReport.Summary = report.Summary{
-		FilesScanned:  len(input.FileNames),
+		FilesScanned:  filesScanned,
 		FilesFailed:   len(finalReport.ViolationsFileCount()),
 		RulesSkipped:  rulesSkippedCounter,
 		NumViolations: len(finalReport.Violations),
+		NumSuppressed: len(finalReport.Suppressed),
+		NumByLevel:    report.CountByLevel(finalReport.Violations),
 	}
 
 	if l.metrics != nil {
@@ -339,19 +587,159 @@ func (l Linter) Lint(ctx context.Context) (report.Report, error) {
 
 	if l.profiling {
 		finalReport.AggregateProfile = regoReport.AggregateProfile
-		finalReport.AggregateProfileToSortedProfile(10)
+		finalReport.AggregateProfileToSortedProfile(l.profilingLimit)
 		finalReport.AggregateProfile = nil
 	}
 
+	if l.exportAggregates {
+		finalReport.Aggregates = regoReport.Aggregates
+	}
+
 	return finalReport, nil
 }
 
+// partitionFiles splits the paths into those for which include returns true, and the rest.
+func partitionFiles(paths []string, include func(path string) bool) (included, excluded []string) {
+	for _, path := range paths {
+		if include(path) {
+			included = append(included, path)
+		} else {
+			excluded = append(excluded, path)
+		}
+	}
+
+	return included, excluded
+}
+
+// collectAggregates reads the files, and returns the aggregates collected from them by the enabled aggregate rules,
+// without linting them otherwise.
+func (l Linter) collectAggregates(ctx context.Context, paths []string) (map[string][]report.Aggregate, error) {
+	input, err := rules.InputFromPaths(paths)
+	if err != nil {
+		return nil, fmt.Errorf("errors encountered when reading files for context: %w", err)
+	}
+
+	pq, err := l.prepareQuery(ctx, collectAggregatesQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := l.evalFiles(ctx, pq, input.FileNames, func(name string) (map[string]any, error) {
+		return l.prepareAST(name, input.FileContent[name], input.Modules[name])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect aggregates: %w", err)
+	}
+
+	aggregates := make(map[string][]report.Aggregate)
+
+	for _, name := range input.FileNames {
+		for k := range results[name].Aggregates {
+			aggregates[k] = append(aggregates[k], results[name].Aggregates[k]...)
+		}
+	}
+
+	return aggregates, nil
+}
+
+func addSnippets(violations []report.Violation, contents map[string]string, contextLines int) {
+	for i := range violations {
+		if content, ok := contents[violations[i].Location.File]; ok {
+			violations[i].Snippet = report.NewSnippet(content, violations[i].Location.Row, contextLines)
+		}
+	}
+}
+
+// load loads the configuration, compiles the rules and resolves the data for linting, before any files are linted.
+func (l *Linter) load(ctx context.Context) (conf config.Config, err error) {
+	ctx, span := tracing.Start(ctx, "regal.load")
+	defer func() { tracing.End(span, err) }()
+
+	if conf, err = l.mergedConfig(); err != nil {
+		return config.Config{}, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	l.combinedConfig = &conf
+
+	// compile the rules once, to be shared by the queries for linting files and for aggregate rules
+	if l.compiled, err = l.compile(); err != nil {
+		return config.Config{}, err
+	}
+
+	if l.dataBundle, err = l.dataBundleWithRulesConfig(ctx, conf); err != nil {
+		return config.Config{}, err
+	}
+
+	return conf, nil
+}
+
+// LintAggregates runs only the aggregate rules, using aggregates previously collected by linting files with
+// WithExportAggregates enabled. This allows callers to keep the aggregates collected from each file, and when
+// a file changes, lint only that file and replace its aggregates, rather than collecting from all files again.
+func (l Linter) LintAggregates(ctx context.Context, aggregates map[string][]report.Aggregate) (report.Report, error) {
+	conf, err := l.mergedConfig()
+	if err != nil {
+		return report.Report{}, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	l.combinedConfig = &conf
+
+	if l.compiled, err = l.compile(); err != nil {
+		return report.Report{}, err
+	}
+
+	if l.dataBundle, err = l.dataBundleWithRulesConfig(ctx, conf); err != nil {
+		return report.Report{}, err
+	}
+
+	aggregateReport, err := l.lintWithRegoAggregateRules(ctx, aggregates)
+	if err != nil {
+		return report.Report{}, fmt.Errorf("failed to lint using Rego aggregate rules: %w", err)
+	}
+
+	if l.baseline != nil {
+		aggregateReport.ApplyBaseline(*l.baseline, l.showSuppressed)
+	}
+
+	aggregateReport.Summary = report.Summary{
+		FilesFailed:   len(aggregateReport.ViolationsFileCount()),
+		NumViolations: len(aggregateReport.Violations),
+		NumSuppressed: len(aggregateReport.Suppressed),
+		NumByLevel:    report.CountByLevel(aggregateReport.Violations),
+	}
+
+	return aggregateReport, nil
+}
+
+func internalDataBundle(conf config.Config) *bundle.Bundle {
+	return &bundle.Bundle{
+		Manifest: bundle.Manifest{
+			Roots:    &[]string{"internal"},
+			Metadata: map[string]any{"name": "internal"},
+		},
+		Data: map[string]any{
+			"internal": map[string]any{
+				"combined_config": config.ToMap(conf),
+				"capabilities":    rio.ToMap(config.CapabilitiesForThisVersion()),
+			},
+		},
+	}
+}
+
 // DetermineEnabledRules returns the list of rules that are enabled based on the supplied configuration.
 // This makes use of the Rego and Go rule settings to produce a single list of the rules that are to be run
 // on this linter instance.
 func (l Linter) DetermineEnabledRules(ctx context.Context) ([]string, error) {
 	enabledRules := make([]string, 0)
 
+	conf, err := l.mergedConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	l.combinedConfig = &conf
+	l.dataBundle = internalDataBundle(conf)
+
 	goRules, err := l.enabledGoRules()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get enabled Go rules: %w", err)
@@ -399,6 +787,97 @@ data.regal.config.for_rule(cat, rule).level != "ignore"
 	return enabledRules, nil
 }
 
+// EffectiveConfig returns the configuration applying to a file, with the provided and user configuration merged,
+// and the enable and disable options of the linter applied, so that the level of each rule is the one used when
+// linting the file. Rules ignored for the file by their ignore.files configuration get level "ignore", as do all
+// rules if the file is ignored altogether, by the ignore options, or a .regalignore file, in which case true is
+// returned along with the configuration.
+func (l Linter) EffectiveConfig(ctx context.Context, file string) (config.Config, bool, error) {
+	merged, err := l.mergedConfig()
+	if err != nil {
+		return config.Config{}, false, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	enabled, err := l.DetermineEnabledRules(ctx)
+	if err != nil {
+		return config.Config{}, false, err
+	}
+
+	conf := merged
+	conf.Rules = make(map[string]config.Category, len(merged.Rules))
+
+	if len(l.ignoreFiles) > 0 {
+		conf.Ignore.Files = l.ignoreFiles
+	}
+
+	if l.traversal != nil {
+		conf.Traversal = *l.traversal
+	}
+
+	ignored, err := ignoredFile(file, conf.Ignore.Files, l.rootDir, conf.Traversal)
+	if err != nil {
+		return config.Config{}, false, err
+	}
+
+	for categoryName, category := range merged.Rules {
+		rulesInCategory := make(config.Category, len(category))
+
+		for ruleName, rule := range category {
+			switch {
+			case ignored || !slices.Contains(enabled, ruleName):
+				rule.Level = "ignore"
+			case rule.Level == "ignore":
+				// enabled by the options of the linter, despite the configuration
+				rule.Level = "error"
+			}
+
+			if rule.Level != "ignore" && rule.Ignore != nil {
+				ruleIgnored, err := ignoredFile(file, rule.Ignore.Files, l.rootDir, conf.Traversal)
+				if err != nil {
+					return config.Config{}, false, err
+				}
+
+				if ruleIgnored {
+					rule.Level = "ignore"
+				}
+			}
+
+			rulesInCategory[ruleName] = rule
+		}
+
+		conf.Rules[categoryName] = rulesInCategory
+	}
+
+	return conf, ignored, nil
+}
+
+// ignoredFile returns whether the file is matched by any of the ignore patterns, or ignored by a .regalignore or
+// .gitignore file.
+func ignoredFile(file string, ignore []string, rootDir string, traversal config.Traversal) (bool, error) {
+	if len(ignore) > 0 {
+		remaining, err := config.FilterIgnoredPaths([]string{file}, ignore, false, rootDir)
+		if err != nil {
+			return false, fmt.Errorf("failed to filter paths: %w", err)
+		}
+
+		if len(remaining) == 0 {
+			return true, nil
+		}
+	}
+
+	ignoreFiles := config.NewIgnoreFiles(traversal)
+	if err := ignoreFiles.LoadParents(file); err != nil {
+		return false, fmt.Errorf("failed to load ignore files: %w", err)
+	}
+
+	ignored, err := ignoreFiles.Ignored(file, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ignore files: %w", err)
+	}
+
+	return ignored, nil
+}
+
 func (l Linter) lintWithGoRules(ctx context.Context, input rules.Input) (report.Report, error) {
 	l.startTimer(regalmetrics.RegalLintGo)
 	defer l.stopTimer(regalmetrics.RegalLintGo)
@@ -410,23 +889,78 @@ func (l Linter) lintWithGoRules(ctx context.Context, input rules.Input) (report.
 
 	aggregate := report.Report{}
 
+	input.RootDir = l.rootDir
+
 	for _, rule := range goRules {
 		inp, err := inputForRule(input, rule)
 		if err != nil {
 			return report.Report{}, fmt.Errorf("error encountered while filtering input files: %w", err)
 		}
 
+		_, span := tracing.Start(ctx, "regal.eval_rule",
+			attribute.String("regal.rule.category", rule.Category()),
+			attribute.String("regal.rule.title", rule.Name()),
+		)
+
 		result, err := rule.Run(ctx, inp)
+
+		tracing.End(span, err)
+
 		if err != nil {
 			return report.Report{}, fmt.Errorf("error encountered in Go rule evaluation: %w", err)
 		}
 
 		aggregate.Violations = append(aggregate.Violations, result.Violations...)
+
+		if l.showSuppressed && len(inp.FileNames) < len(input.FileNames) {
+			suppressed, err := suppressedByConfig(ctx, rule, input, inp)
+			if err != nil {
+				return report.Report{}, err
+			}
+
+			aggregate.Suppressed = append(aggregate.Suppressed, suppressed...)
+		}
 	}
 
 	return aggregate, err
 }
 
+// suppressedByConfig runs a Go rule on the files ignored for it in the configuration, and returns the violations
+// found as suppressed.
+func suppressedByConfig(
+	ctx context.Context,
+	rule rules.Rule,
+	input rules.Input,
+	included rules.Input,
+) ([]report.Violation, error) {
+	ignored := rules.Input{
+		FileContent: make(map[string]string),
+		Modules:     make(map[string]*ast.Module),
+	}
+
+	for _, name := range input.FileNames {
+		if _, ok := included.Modules[name]; !ok {
+			ignored.FileNames = append(ignored.FileNames, name)
+			ignored.FileContent[name] = input.FileContent[name]
+			ignored.Modules[name] = input.Modules[name]
+		}
+	}
+
+	result, err := rule.Run(ctx, ignored)
+	if err != nil {
+		return nil, fmt.Errorf("error encountered in Go rule evaluation: %w", err)
+	}
+
+	suppressed := make([]report.Violation, 0, len(result.Violations))
+
+	for _, violation := range result.Violations {
+		violation.Suppression = report.SuppressedByConfig
+		suppressed = append(suppressed, violation)
+	}
+
+	return suppressed, nil
+}
+
 func inputForRule(input rules.Input, rule rules.Rule) (rules.Input, error) {
 	ignore := rule.Config().Ignore
 
@@ -449,6 +983,7 @@ func filterInputFiles(input rules.Input, ignore []string) (rules.Input, error) {
 		FileNames:   make([]string, 0, n),
 		FileContent: make(map[string]string, n),
 		Modules:     make(map[string]*ast.Module, n),
+		RootDir:     input.RootDir,
 	}
 
 outer:
@@ -549,23 +1084,32 @@ func (l Linter) paramsToRulesConfig() map[string]any {
 }
 
 func (l Linter) prepareRegoArgs(query ast.Body) ([]func(*rego.Rego), error) {
-	var regoArgs []func(*rego.Rego)
+	compiled, err := l.compile()
+	if err != nil {
+		return nil, err
+	}
 
-	roots := []string{"eval"}
+	data := make(map[string]any, len(compiled.data)+2)
+	for k, v := range compiled.data {
+		data[k] = v
+	}
+
+	data["eval"] = l.paramsToRulesConfig()["eval"]
 
-	dataBundle := bundle.Bundle{
-		Data:     l.paramsToRulesConfig(),
-		Manifest: bundle.Manifest{Roots: &roots},
+	if l.dataBundle != nil {
+		data["internal"] = l.dataBundle.Data["internal"]
 	}
 
-	regoArgs = append(regoArgs,
+	// the rules are already compiled, so only the query is compiled when preparing for evaluation
+	regoArgs := []func(*rego.Rego){
 		rego.Metrics(l.metrics),
 		rego.ParsedQuery(query),
-		rego.ParsedBundle("regal_eval_params", &dataBundle),
+		rego.Compiler(compiled.compiler),
+		rego.Store(inmem.NewFromObjectWithOpts(data, inmem.OptRoundTripOnWrite(false))),
 		rego.Function2(builtins.RegalParseModuleMeta, builtins.RegalParseModule),
 		rego.Function1(builtins.RegalJSONPrettyMeta, builtins.RegalJSONPretty),
 		rego.Function1(builtins.RegalLastMeta, builtins.RegalLast),
-	)
+	}
 
 	if l.debugMode && l.printHook == nil {
 		l.printHook = topdown.NewPrintHook(os.Stderr)
@@ -578,37 +1122,23 @@ func (l Linter) prepareRegoArgs(query ast.Body) ([]func(*rego.Rego), error) {
 		)
 	}
 
-	if l.dataBundle != nil {
-		regoArgs = append(regoArgs, rego.ParsedBundle("internal", l.dataBundle))
-	}
+	return regoArgs, nil
+}
 
-	if l.customRulesPaths != nil {
-		regoArgs = append(regoArgs, rego.Load(l.customRulesPaths, rio.ExcludeTestFilter()))
-	}
+func (l Linter) prepareQuery(ctx context.Context, query ast.Body) (pq rego.PreparedEvalQuery, err error) {
+	ctx, span := tracing.Start(ctx, "regal.prepare", attribute.String("regal.query", query.String()))
+	defer func() { tracing.End(span, err) }()
 
-	if l.customRuleFS != nil && l.customRuleFSRootPath != "" {
-		files, err := loadModulesFromCustomRuleFS(l.customRuleFS, l.customRuleFSRootPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load custom rules from FS: %w", err)
-		}
-
-		for path, content := range files {
-			regoArgs = append(regoArgs, rego.Module(path, content))
-		}
+	regoArgs, err := l.prepareRegoArgs(query)
+	if err != nil {
+		return pq, fmt.Errorf("failed preparing query for linting: %w", err)
 	}
 
-	if l.ruleBundles != nil {
-		for _, ruleBundle := range l.ruleBundles {
-			var bundleName string
-			if metadataName, ok := ruleBundle.Manifest.Metadata["name"].(string); ok {
-				bundleName = metadataName
-			}
-
-			regoArgs = append(regoArgs, rego.ParsedBundle(bundleName, ruleBundle))
-		}
+	if pq, err = rego.New(regoArgs...).PrepareForEval(ctx); err != nil {
+		return pq, fmt.Errorf("failed preparing query for linting: %w", err)
 	}
 
-	return regoArgs, nil
+	return pq, nil
 }
 
 func loadModulesFromCustomRuleFS(customRuleFS fs.FS, rootPath string) (map[string]string, error) {
@@ -655,7 +1185,11 @@ func loadModulesFromCustomRuleFS(customRuleFS fs.FS, rootPath string) (map[strin
 	return files, nil
 }
 
-func (l Linter) lintWithRegoRules(ctx context.Context, input rules.Input) (report.Report, error) {
+func (l Linter) lintWithRegoRules(
+	ctx context.Context,
+	input rules.Input,
+	collectAggregates bool,
+) (report.Report, error) {
 	l.startTimer(regalmetrics.RegalLintRego)
 	defer l.stopTimer(regalmetrics.RegalLintRego)
 
@@ -663,39 +1197,173 @@ func (l Linter) lintWithRegoRules(ctx context.Context, input rules.Input) (repor
 	defer cancel()
 
 	var query ast.Body
-	if len(input.FileNames) > 1 {
+	if collectAggregates {
 		query = lintAndCollectQuery
 	} else {
 		query = lintQuery
 	}
 
-	regoArgs, err := l.prepareRegoArgs(query)
+	pq, err := l.prepareQuery(ctx, query)
 	if err != nil {
-		return report.Report{}, fmt.Errorf("failed preparing query for linting: %w", err)
+		return report.Report{}, err
+	}
+
+	duplicates := duplicateFiles(input)
+
+	// the prepared input of files with duplicates is kept, to be reused for linting the duplicates
+	hasDuplicates := make(map[string]bool, len(duplicates))
+	for _, original := range duplicates {
+		hasDuplicates[original] = true
+	}
+
+	var mu sync.Mutex
+
+	prepared := make(map[string]map[string]any, len(hasDuplicates))
+	originals := make([]string, 0, len(input.FileNames)-len(duplicates))
+	duplicateNames := make([]string, 0, len(duplicates))
+
+	for _, name := range input.FileNames {
+		if _, ok := duplicates[name]; ok {
+			duplicateNames = append(duplicateNames, name)
+		} else {
+			originals = append(originals, name)
+		}
 	}
 
-	pq, err := rego.New(regoArgs...).PrepareForEval(ctx)
+	results, err := l.evalFiles(ctx, pq, originals, func(name string) (map[string]any, error) {
+		enhancedAST, err := l.prepareAST(name, input.FileContent[name], input.Modules[name])
+		if err == nil && hasDuplicates[name] {
+			mu.Lock()
+			prepared[name] = enhancedAST
+			mu.Unlock()
+		}
+
+		return enhancedAST, err
+	})
 	if err != nil {
-		return report.Report{}, fmt.Errorf("failed preparing query for linting: %w", err)
+		return report.Report{}, err
+	}
+
+	if len(duplicates) > 0 {
+		if err := l.lintDuplicates(ctx, pq, duplicates, duplicateNames, prepared, results); err != nil {
+			return report.Report{}, err
+		}
 	}
 
 	aggregate := report.Report{}
 	aggregate.Aggregates = make(map[string][]report.Aggregate)
 
+	for _, name := range input.FileNames {
+		result := results[name]
+
+		aggregate.Violations = append(aggregate.Violations, result.Violations...)
+		aggregate.Suppressed = append(aggregate.Suppressed, result.Suppressed...)
+		aggregate.Notices = append(aggregate.Notices, result.Notices...)
+
+		for k := range result.Aggregates {
+			aggregate.Aggregates[k] = append(aggregate.Aggregates[k], result.Aggregates[k]...)
+		}
+
+		if l.profiling {
+			aggregate.AddProfileEntries(result.AggregateProfile)
+		}
+	}
+
+	return aggregate, nil
+}
+
+// lintDuplicates adds the results of linting each duplicate file to results, by copying those of the file with
+// identical contents, and evaluating only the rules whose results may depend on the name of the file.
+func (l Linter) lintDuplicates(
+	ctx context.Context,
+	pq rego.PreparedEvalQuery,
+	duplicates map[string]string,
+	names []string,
+	prepared map[string]map[string]any,
+	results map[string]report.Report,
+) error {
+	compiled, err := l.compile()
+	if err != nil {
+		return err
+	}
+
+	conf, err := l.mergedConfig()
+	if err != nil {
+		return fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	dependent := fileNameDependentRules(compiled.compiler, conf)
+
+	ruleNames := make([]string, 0, len(dependent))
+	for ruleName := range dependent {
+		ruleNames = append(ruleNames, ruleName)
+	}
+
+	slices.Sort(ruleNames)
+
+	dependentResults := make(map[string]report.Report)
+
+	if len(ruleNames) > 0 {
+		dependentResults, err = l.evalFiles(ctx, pq, names, func(name string) (map[string]any, error) {
+			return duplicateInput(prepared[duplicates[name]], name, ruleNames), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		original := duplicates[name]
+		result := copyReport(results[original], original, name, dependent)
+
+		if dependentResult, ok := dependentResults[name]; ok {
+			result.Violations = append(result.Violations, dependentResult.Violations...)
+			result.Suppressed = append(result.Suppressed, dependentResult.Suppressed...)
+			result.AggregateProfile = dependentResult.AggregateProfile
+
+			for k := range dependentResult.Aggregates {
+				result.Aggregates[k] = append(result.Aggregates[k], dependentResult.Aggregates[k]...)
+			}
+		}
+
+		results[name] = result
+	}
+
+	return nil
+}
+
+// evalFiles evaluates the prepared lint query concurrently for each of the named files, with the input returned
+// by prepareInput, and returns the result for each file.
+func (l Linter) evalFiles(
+	ctx context.Context,
+	pq rego.PreparedEvalQuery,
+	names []string,
+	prepareInput func(name string) (map[string]any, error),
+) (map[string]report.Report, error) {
+	results := make(map[string]report.Report, len(names))
+
 	var wg sync.WaitGroup
 
 	var mu sync.Mutex
 
-	errCh := make(chan error)
-	doneCh := make(chan bool)
+	errCh := make(chan error, len(names))
+	doneCh := make(chan bool, 1)
 
-	for _, name := range input.FileNames {
+	for _, name := range names {
 		wg.Add(1)
 
 		go func(name string) {
 			defer wg.Done()
 
-			enhancedAST, err := parse.PrepareAST(name, input.FileContent[name], input.Modules[name])
+			ctx, span := tracing.Start(ctx, "regal.eval", attribute.String("regal.file", name))
+			defer span.End()
+
+			_, prepareSpan := tracing.Start(ctx, "regal.prepare_input")
+
+			enhancedAST, err := prepareInput(name)
+
+			tracing.End(prepareSpan, err)
+
 			if err != nil {
 				errCh <- fmt.Errorf("failed preparing AST: %w", err)
 
@@ -743,16 +1411,7 @@ func (l Linter) lintWithRegoRules(ctx context.Context, input rules.Input) (repor
 			}
 
 			mu.Lock()
-			aggregate.Violations = append(aggregate.Violations, result.Violations...)
-			aggregate.Notices = append(aggregate.Notices, result.Notices...)
-
-			for k := range result.Aggregates {
-				aggregate.Aggregates[k] = append(aggregate.Aggregates[k], result.Aggregates[k]...)
-			}
-
-			if l.profiling {
-				aggregate.AddProfileEntries(result.AggregateProfile)
-			}
+			results[name] = result
 			mu.Unlock()
 		}(name)
 	}
@@ -764,14 +1423,41 @@ func (l Linter) lintWithRegoRules(ctx context.Context, input rules.Input) (repor
 
 	select {
 	case <-ctx.Done():
-		return report.Report{}, fmt.Errorf("context cancelled: %w", ctx.Err())
+		return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
 	case err := <-errCh:
-		return report.Report{}, fmt.Errorf("error encountered in rule evaluation %w", err)
+		return nil, fmt.Errorf("error encountered in rule evaluation %w", err)
 	case <-doneCh:
-		return aggregate, nil
+		return results, nil
+	}
+}
+
+// batches splits paths into batches of size, or returns a single batch of all paths if size is 0 or less. At least
+// one batch is always returned, even if empty, as modules provided to the linter are linted with the first batch.
+func batches(paths []string, size int) [][]string {
+	if size <= 0 || len(paths) <= size {
+		return [][]string{paths}
+	}
+
+	result := make([][]string, 0, (len(paths)+size-1)/size)
+
+	for start := 0; start < len(paths); start += size {
+		result = append(result, paths[start:min(start+size, len(paths))])
 	}
+
+	return result
 }
 
+func (l Linter) prepareAST(name string, content string, module *ast.Module) (map[string]any, error) {
+	if l.preparedASTCache != nil {
+		return l.preparedASTCache.PrepareAST(name, content, module) //nolint:wrapcheck
+	}
+
+	return parse.PrepareAST(name, content, module) //nolint:wrapcheck
+}
+
+// lintWithRegoAggregateRules evaluates the aggregate rules using the aggregates collected. Aggregate rules are
+// independent of each other, and so the rules of each category are evaluated concurrently, as rules evaluated for
+// the whole workspace, like those of the import graph, may otherwise dominate the time taken to lint large projects.
 func (l Linter) lintWithRegoAggregateRules(
 	ctx context.Context,
 	aggregates map[string][]report.Aggregate,
@@ -779,23 +1465,80 @@ func (l Linter) lintWithRegoAggregateRules(
 	l.startTimer(regalmetrics.RegalLintRegoAggregate)
 	defer l.stopTimer(regalmetrics.RegalLintRegoAggregate)
 
+	ctx, span := tracing.Start(ctx, "regal.eval_aggregates")
+	defer span.End()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	regoArgs, err := l.prepareRegoArgs(lintWithAggregatesQuery)
+	pq, err := l.prepareQuery(ctx, lintWithAggregatesQuery)
 	if err != nil {
-		return report.Report{}, fmt.Errorf("failed preparing query for linting: %w", err)
+		return report.Report{}, err
 	}
 
-	pq, err := rego.New(regoArgs...).PrepareForEval(ctx)
-	if err != nil {
-		return report.Report{}, fmt.Errorf("failed preparing query for linting: %w", err)
+	categories, groups := aggregateRuleGroups(l.compiled.compiler, aggregates)
+	results := make([]report.Report, len(categories))
+	errs := make([]error, len(categories))
+
+	var wg sync.WaitGroup
+
+	for i, category := range categories {
+		wg.Add(1)
+
+		go func(i int, category string) {
+			defer wg.Done()
+
+			results[i], errs[i] = l.evalAggregateRules(ctx, pq, category, groups[category], aggregates)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, category)
+	}
+
+	wg.Wait()
+
+	result := report.Report{}
+
+	for i := range categories {
+		if errs[i] != nil {
+			return report.Report{}, errs[i]
+		}
+
+		result.Violations = append(result.Violations, results[i].Violations...)
+	}
+
+	for i := range result.Violations {
+		result.Violations[i].IsAggregate = true
+	}
+
+	return result, nil
+}
+
+// evalAggregateRules evaluates the aggregate rules of a single category, with keys like "category/title", provided
+// only the aggregates of those rules.
+func (l Linter) evalAggregateRules(
+	ctx context.Context,
+	pq rego.PreparedEvalQuery,
+	category string,
+	keys []string,
+	aggregates map[string][]report.Aggregate,
+) (report.Report, error) {
+	ctx, span := tracing.Start(ctx, "regal.eval_aggregates_category", attribute.String("regal.category", category))
+	defer span.End()
+
+	// custom aggregate rules are routed their aggregates by key, and so need a key even when nothing was collected,
+	// for rules reporting on the absence of some data, like in any file linted
+	internal := make(map[string][]report.Aggregate, len(keys))
+	for _, key := range keys {
+		if internal[key] = aggregates[key]; internal[key] == nil {
+			internal[key] = []report.Aggregate{}
+		}
 	}
 
 	input := map[string]any{
 		// This will be replaced by the routing policy to provide each
 		// aggregate rule only the aggregated data from the same rule
-		"aggregates_internal": aggregates,
+		"aggregates_internal": internal,
 		// There is no file provided in input here, but we'll provide *something* for
 		// consistency, and to avoid silently failing with undefined should someone
 		// refer to input.regal in an aggregate_report rule
@@ -804,6 +1547,8 @@ func (l Linter) lintWithRegoAggregateRules(
 				"name":  "__aggregate_report__",
 				"lines": []string{},
 			},
+			// only the rules of the category are run by this evaluation
+			"rules": keys,
 		},
 	}
 
@@ -823,11 +1568,67 @@ func (l Linter) lintWithRegoAggregateRules(
 		return report.Report{}, fmt.Errorf("failed to convert result set to report: %w", err)
 	}
 
-	for i := range result.Violations {
-		result.Violations[i].IsAggregate = true
+	return result, nil
+}
+
+// aggregateRuleGroups returns the sorted categories of the aggregate rules, bundled and custom, and the keys, like
+// "category/title", of the rules of each category. Rules for which aggregates were collected are included, even if
+// not found to declare an aggregate_report rule.
+func aggregateRuleGroups(
+	compiler *ast.Compiler,
+	aggregates map[string][]report.Aggregate,
+) ([]string, map[string][]string) {
+	keys := append(
+		aggregateRules(compiler, ast.MustParseRef("data.regal.rules")),
+		aggregateRules(compiler, ast.MustParseRef("data.custom.regal.rules"))...,
+	)
+	keys = append(keys, util.Keys(aggregates)...)
+
+	slices.Sort(keys)
+
+	groups := make(map[string][]string)
+
+	for _, key := range slices.Compact(keys) {
+		category, _, _ := strings.Cut(key, "/")
+		groups[category] = append(groups[category], key)
 	}
 
-	return result, nil
+	categories := util.Keys(groups)
+	slices.Sort(categories)
+
+	return categories, groups
+}
+
+// aggregateRules returns the keys, like "category/title", of the rules under prefix declaring an aggregate_report
+// rule.
+func aggregateRules(compiler *ast.Compiler, prefix ast.Ref) []string {
+	reportRef := ast.Ref{ast.VarTerm("aggregate_report")}
+
+	var keys []string
+
+	for _, module := range compiler.Modules {
+		path := module.Package.Path
+		if len(path) != len(prefix)+2 || !path.HasPrefix(prefix) {
+			continue
+		}
+
+		category, ok1 := path[len(prefix)].Value.(ast.String)
+		title, ok2 := path[len(prefix)+1].Value.(ast.String)
+
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		for _, rule := range module.Rules {
+			if rule.Head.Ref().Equal(reportRef) {
+				keys = append(keys, string(category)+"/"+string(title))
+
+				break
+			}
+		}
+	}
+
+	return keys
 }
 
 func resultSetToReport(resultSet rego.ResultSet) (report.Report, error) {
@@ -896,6 +1697,7 @@ func (l Linter) mergedConfig() (config.Config, error) {
 		// adopt user rule levels based on config and defaults
 		// If the user configuration contains rules with the level unset, copy the level from the provided configuration
 		extractUserRuleLevels(l.userConfig, &mergedConf, providedRuleLevels)
+		applyDefaultIgnores(&mergedConf)
 	}
 
 	if mergedConf.Capabilities == nil {
@@ -940,11 +1742,9 @@ func extractUserRuleLevels(userConfig *config.Config, mergedConf *config.Config,
 			if userHasConfiguredRule && userConfig.Rules[categoryName][ruleName].Level != "" {
 				// if the user config has a level for the rule, use that
 				selectedRuleLevel = userConfig.Rules[categoryName][ruleName].Level
-			} else if categoryDefault, ok := mergedConf.Defaults.Categories[categoryName]; ok {
+			} else if categoryDefault := mergedConf.Defaults.Categories[categoryName]; categoryDefault.Level != "" {
 				// if the config has a default level for the category, use that
-				if categoryDefault.Level != "" {
-					selectedRuleLevel = categoryDefault.Level
-				}
+				selectedRuleLevel = categoryDefault.Level
 			} else if mergedConf.Defaults.Global.Level != "" {
 				// if the config has a global default level, use that
 				selectedRuleLevel = mergedConf.Defaults.Global.Level
@@ -956,6 +1756,37 @@ func extractUserRuleLevels(userConfig *config.Config, mergedConf *config.Config,
 	}
 }
 
+// applyDefaultIgnores adds the files ignored by the global and category defaults to the files ignored by each rule,
+// for generated or vendored policies to be ignored by a whole category of rules, while still being linted by others.
+func applyDefaultIgnores(mergedConf *config.Config) {
+	for categoryName, rulesByCategory := range mergedConf.Rules {
+		var files []string
+
+		if categoryDefault := mergedConf.Defaults.Categories[categoryName]; categoryDefault.Ignore != nil {
+			files = append(files, categoryDefault.Ignore.Files...)
+		}
+
+		if mergedConf.Defaults.Global.Ignore != nil {
+			files = append(files, mergedConf.Defaults.Global.Ignore.Files...)
+		}
+
+		if len(files) == 0 {
+			continue
+		}
+
+		for ruleName, rule := range rulesByCategory {
+			// the ignore of the rule may be shared with the user configuration, so a copy is modified
+			ignore := &config.Ignore{Files: files}
+			if rule.Ignore != nil {
+				ignore.Files = slices.Concat(rule.Ignore.Files, files)
+			}
+
+			rule.Ignore = ignore
+			mergedConf.Rules[categoryName][ruleName] = rule
+		}
+	}
+}
+
 // Copy the level of each rule from the provided configuration.
 func providedConfLevels(conf config.Config) map[string]string {
 	ruleLevels := make(map[string]string)
@@ -971,14 +1802,37 @@ func providedConfLevels(conf config.Config) map[string]string {
 	return ruleLevels
 }
 
+// withLevelError returns a copy of conf with the level of all rules set to error, for rules enabled regardless of
+// their configured level, which like the Rego rules keep their other options.
+func withLevelError(conf config.Config) config.Config {
+	forced := conf
+	forced.Rules = make(map[string]config.Category, len(conf.Rules))
+
+	for categoryName, rulesByCategory := range conf.Rules {
+		forced.Rules[categoryName] = make(config.Category, len(rulesByCategory))
+
+		for ruleName, rule := range rulesByCategory {
+			rule.Level = "error"
+			forced.Rules[categoryName][ruleName] = rule
+		}
+	}
+
+	return forced
+}
+
 func (l Linter) enabledGoRules() ([]rules.Rule, error) {
 	var enabledGoRules []rules.Rule
 
-	// enabling/disabling all rules takes precedence and entirely disregards configuration
-	// files, but still respects the enable/disable category or rule flags
+	conf, err := l.mergedConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merged config: %w", err)
+	}
+
+	// enabling/disabling all rules takes precedence and entirely disregards the levels of configuration
+	// files, but still respects the enable/disable category or rule flags, and the options of rules
 
 	if l.disableAll {
-		for _, rule := range rules.AllGoRules(config.Config{}) {
+		for _, rule := range rules.AllGoRules(withLevelError(conf)) {
 			if util.Contains(l.enableCategory, rule.Category()) || util.Contains(l.enable, rule.Name()) {
 				enabledGoRules = append(enabledGoRules, rule)
 			}
@@ -988,7 +1842,7 @@ func (l Linter) enabledGoRules() ([]rules.Rule, error) {
 	}
 
 	if l.enableAll {
-		for _, rule := range rules.AllGoRules(config.Config{}) {
+		for _, rule := range rules.AllGoRules(withLevelError(conf)) {
 			if !util.Contains(l.disableCategory, rule.Category()) && !util.Contains(l.disable, rule.Name()) {
 				enabledGoRules = append(enabledGoRules, rule)
 			}
@@ -997,11 +1851,6 @@ func (l Linter) enabledGoRules() ([]rules.Rule, error) {
 		return enabledGoRules, nil
 	}
 
-	conf, err := l.mergedConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create merged config: %w", err)
-	}
-
 	for _, rule := range rules.AllGoRules(conf) {
 		// disabling specific rule has the highest precedence
 		if util.Contains(l.disable, rule.Name()) {
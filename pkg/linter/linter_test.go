@@ -4,8 +4,20 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"maps"
+	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/topdown"
@@ -14,13 +26,15 @@ import (
 	"github.com/styrainc/regal/internal/test"
 	"github.com/styrainc/regal/internal/testutil"
 	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
 	"github.com/styrainc/regal/pkg/rules"
 )
 
 func TestLintWithDefaultBundle(t *testing.T) {
 	t.Parallel()
 
-	input := test.InputPolicy("p.rego", `package p
+	input := test.InputPolicy("p.rego", `# Package p is linted with all rules enabled.
+package p
 
 import rego.v1
 
@@ -43,7 +57,7 @@ camelCase if {
 		t.Errorf("expected first violation to be 'todo-comments', got %s", result.Violations[0].Title)
 	}
 
-	if result.Violations[0].Location.Row != 5 {
+	if result.Violations[0].Location.Row != 6 {
 		t.Errorf("expected first violation to be on line 3, got %d", result.Violations[0].Location.Row)
 	}
 
@@ -59,7 +73,7 @@ camelCase if {
 		t.Errorf("expected second violation to be 'prefer-snake-case', got %s", result.Violations[1].Title)
 	}
 
-	if result.Violations[1].Location.Row != 6 {
+	if result.Violations[1].Location.Row != 7 {
 		t.Errorf("expected second violation to be on line 4, got %d", result.Violations[1].Location.Row)
 	}
 
@@ -199,9 +213,24 @@ or := 1
 				},
 				Rules: map[string]config.Category{},
 			},
+			filename: "p.rego",
+			// the style category level applies to rules disabled by default too, like missing-package-comment and
+			// indentation
+			expViolations: []string{
+				"opa-fmt", "indentation", "top-level-iteration", "rule-shadows-builtin", "missing-package-comment",
+			},
+			expLevels: []string{"error", "error", "warning", "warning", "error"},
+		},
+		{
+			name: "set level to notice",
+			userConfig: &config.Config{
+				Rules: map[string]config.Category{
+					"bugs": {"rule-shadows-builtin": config.Rule{Level: "notice"}},
+				},
+			},
 			filename:      "p.rego",
 			expViolations: []string{"opa-fmt", "top-level-iteration", "rule-shadows-builtin"},
-			expLevels:     []string{"error", "warning", "warning"},
+			expLevels:     []string{"error", "error", "notice"},
 		},
 		{
 			name: "rule level ignore files",
@@ -222,6 +251,28 @@ or := 1
 			filename:      "p.rego",
 			expViolations: []string{"top-level-iteration"},
 		},
+		{
+			name: "category default ignore files",
+			userConfig: &config.Config{
+				Defaults: config.Defaults{
+					Categories: map[string]config.Default{
+						"bugs": {Ignore: &config.Ignore{Files: []string{"p.rego"}}},
+					},
+				},
+			},
+			filename:      "p.rego",
+			expViolations: []string{"opa-fmt"},
+		},
+		{
+			name: "global default ignore files",
+			userConfig: &config.Config{
+				Defaults: config.Defaults{
+					Global: config.Default{Ignore: &config.Ignore{Files: []string{"*.rego"}}},
+				},
+			},
+			filename:      "p.rego",
+			expViolations: []string{},
+		},
 		{
 			name: "user config global ignore files",
 			userConfig: &config.Config{
@@ -312,7 +363,8 @@ or := 1
 func TestLintWithGoRule(t *testing.T) {
 	t.Parallel()
 
-	input := test.InputPolicy("p.rego", `package p
+	input := test.InputPolicy("p.rego", `# Package p is linted with all rules enabled.
+package p
 		import rego.v1
 
  		x := true
@@ -324,12 +376,48 @@ func TestLintWithGoRule(t *testing.T) {
 
 	result := testutil.Must(linter.Lint(context.Background()))(t)
 
+	titles := make([]string, 0, len(result.Violations))
+	for _, violation := range result.Violations {
+		titles = append(titles, violation.Title)
+	}
+
+	expected := []string{"opa-fmt", "trailing-whitespace", "indentation", "missing-final-newline"}
+	if !slices.Equal(titles, expected) {
+		t.Errorf("expected violations %v, got %v", expected, titles)
+	}
+}
+
+func TestLintWithSourceSnippets(t *testing.T) {
+	t.Parallel()
+
+	input := test.InputPolicy("p.rego", `package p
+
+import rego.v1
+
+camelCase := true
+
+allow := true
+`)
+
+	linter := NewLinter().
+		WithDisableAll(true).
+		WithEnabledRules("prefer-snake-case").
+		WithSourceSnippets(1).
+		WithInputModules(&input)
+
+	result := testutil.Must(linter.Lint(context.Background()))(t)
+
 	if len(result.Violations) != 1 {
 		t.Fatalf("expected 1 violation, got %d", len(result.Violations))
 	}
 
-	if result.Violations[0].Title != "opa-fmt" {
-		t.Errorf("expected first violation to be 'opa-fmt', got %s", result.Violations[0].Title)
+	snippet := result.Violations[0].Snippet
+	if snippet == nil {
+		t.Fatal("expected violation to have a snippet")
+	}
+
+	if exp := []string{"", "camelCase := true", ""}; snippet.Start != 4 || !slices.Equal(snippet.Lines, exp) {
+		t.Errorf("expected snippet of lines %v from row 4, got %v from row %d", exp, snippet.Lines, snippet.Start)
 	}
 }
 
@@ -357,6 +445,116 @@ func TestLintWithUserConfigGoRuleIgnore(t *testing.T) {
 	}
 }
 
+func TestLintWithShowSuppressed(t *testing.T) {
+	t.Parallel()
+
+	userConfig := config.Config{Rules: map[string]config.Category{
+		"style": {"opa-fmt": config.Rule{Level: "error", Ignore: &config.Ignore{Files: []string{"p.rego"}}}},
+	}}
+
+	input := test.InputPolicy("p.rego", `package p
+		import rego.v1
+
+		# regal ignore:prefer-snake-case
+		camelCase := true
+	`)
+
+	linter := NewLinter().
+		WithUserConfig(userConfig).
+		WithShowSuppressed(true).
+		WithInputModules(&input)
+
+	result := testutil.Must(linter.Lint(context.Background()))(t)
+
+	if len(result.Violations) != 0 {
+		t.Fatalf("expected no violations, got %v", result.Violations)
+	}
+
+	suppressions := make(map[string]string, len(result.Suppressed))
+	for _, violation := range result.Suppressed {
+		suppressions[violation.Title] = violation.Suppression
+	}
+
+	expected := map[string]string{
+		"opa-fmt":           report.SuppressedByConfig,
+		"prefer-snake-case": report.SuppressedByDirective,
+	}
+
+	if !maps.Equal(suppressions, expected) {
+		t.Errorf("expected suppressions %v, got %v", expected, suppressions)
+	}
+
+	if result.Summary.NumSuppressed != 2 {
+		t.Errorf("expected 2 suppressed violations in summary, got %d", result.Summary.NumSuppressed)
+	}
+}
+
+func TestLintWithMaxViolations(t *testing.T) {
+	t.Parallel()
+
+	input := test.InputPolicy("p.rego", `package p
+
+import rego.v1
+
+camelCase := true
+
+otherCamelCase := true
+
+anotherCamelCase := true
+`)
+
+	for maxViolations, withinBudget := range map[int]bool{3: true, 2: false} {
+		userConfig := config.Config{Rules: map[string]config.Category{
+			"style": {"prefer-snake-case": config.Rule{Level: "error", MaxViolations: &maxViolations}},
+		}}
+
+		linter := NewLinter().
+			WithDisableAll(true).
+			WithEnabledRules("prefer-snake-case").
+			WithUserConfig(userConfig).
+			WithInputModules(&input)
+
+		result := testutil.Must(linter.Lint(context.Background()))(t)
+
+		if len(result.Violations) != 3 {
+			t.Fatalf("expected 3 violations, got %d", len(result.Violations))
+		}
+
+		for _, violation := range result.Violations {
+			if violation.WithinBudget != withinBudget {
+				t.Errorf("expected violation within budget of %d to be %t", maxViolations, withinBudget)
+			}
+		}
+
+		expected := []report.Budget{
+			{Category: "style", Title: "prefer-snake-case", MaxViolations: maxViolations, Violations: 3},
+		}
+
+		if !slices.Equal(result.Budgets, expected) {
+			t.Errorf("expected budgets %v, got %v", expected, result.Budgets)
+		}
+	}
+}
+
+func TestLintWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	input := test.InputPolicy("p.rego", "package p\n\nimport rego.v1\n\nallow := true\n")
+
+	userConfig := config.Config{Timeout: config.Duration(time.Nanosecond)}
+
+	linter := NewLinter().WithUserConfig(userConfig).WithInputModules(&input)
+
+	if _, err := linter.Lint(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out after 1ns") {
+		t.Fatalf("expected linting to time out, got %v", err)
+	}
+
+	// a timeout of 0 overrides that of the configuration, and means no limit
+	if _, err := linter.WithTimeout(0).Lint(context.Background()); err != nil {
+		t.Fatalf("expected no error with timeout overridden, got %v", err)
+	}
+}
+
 func TestLintWithCustomRule(t *testing.T) {
 	t.Parallel()
 
@@ -573,6 +771,268 @@ func TestLintWithAggregateRule(t *testing.T) {
 	}
 }
 
+func TestLintWithAggregateRulesOfSeveralCategories(t *testing.T) {
+	t.Parallel()
+
+	policies := map[string]string{
+		"foo.rego": "package foo\n\nimport data.bar\n\ndefault allow := false\n",
+		"bar.rego": "package bar\n\nimport data.foo.allow\n",
+	}
+
+	modules := make(map[string]*ast.Module)
+
+	for filename, content := range policies {
+		modules[filename] = parse.MustParseModule(content)
+	}
+
+	input := rules.NewInput(policies, modules)
+
+	linter := NewLinter().
+		WithDisableAll(true).
+		WithEnabledRules("prefer-package-imports", "no-defined-entrypoint").
+		WithInputModules(&input)
+
+	result := testutil.Must(linter.Lint(context.Background()))(t)
+
+	titles := make([]string, 0, len(result.Violations))
+	for _, violation := range result.Violations {
+		titles = append(titles, violation.Title)
+
+		if !violation.IsAggregate {
+			t.Errorf("expected violation of %s to be marked as aggregate", violation.Title)
+		}
+	}
+
+	slices.Sort(titles)
+
+	if expected := []string{"no-defined-entrypoint", "prefer-package-imports"}; !slices.Equal(titles, expected) {
+		t.Errorf("expected violations %v, got %v", expected, titles)
+	}
+}
+
+func TestAggregateRuleGroups(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := NewLinter().WithDisableAll(true).WithEnabledCategories("imports", "idiomatic").compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	categories, groups := aggregateRuleGroups(compiled.compiler, map[string][]report.Aggregate{
+		"imports/prefer-package-imports": {},
+		"custom/collected-only":          {},
+	})
+
+	if expected := []string{"custom", "idiomatic", "imports"}; !slices.Equal(categories, expected) {
+		t.Errorf("expected categories %v, got %v", expected, categories)
+	}
+
+	expectedGroups := map[string][]string{
+		"custom":    {"custom/collected-only"},
+		"idiomatic": {"idiomatic/no-defined-entrypoint"},
+		"imports":   {"imports/circular-import", "imports/prefer-package-imports", "imports/unresolved-import"},
+	}
+
+	for category, expected := range expectedGroups {
+		if !slices.Equal(groups[category], expected) {
+			t.Errorf("expected rules %v for category %s, got %v", expected, category, groups[category])
+		}
+	}
+}
+
+func TestLintWithCustomAggregateRuleWithoutEntries(t *testing.T) {
+	t.Parallel()
+
+	rule := `# METADATA
+# description: At least one rule named allow must exist
+package custom.regal.rules.organizational["at-least-one-allow"]
+
+import rego.v1
+
+import data.regal.result
+
+aggregate contains result.aggregate(rego.metadata.chain(), {}) if {
+	some rule in input.rules
+	rule.head.ref[0].value == "allow"
+}
+
+# METADATA
+# schemas:
+#   - input: schema.regal.aggregate
+aggregate_report contains violation if {
+	count(input.aggregate) == 0
+
+	violation := result.fail(rego.metadata.chain(), {})
+}
+`
+
+	rulePath := filepath.Join(t.TempDir(), "at_least_one_allow.rego")
+	if err := os.WriteFile(rulePath, []byte(rule), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		policies   map[string]string
+		violations int
+	}{
+		{
+			name:       "no entries collected",
+			policies:   map[string]string{"a.rego": "package a\n\ndeny := true\n", "b.rego": "package b\n"},
+			violations: 1,
+		},
+		{
+			name:       "entries collected",
+			policies:   map[string]string{"a.rego": "package a\n\nallow := true\n", "b.rego": "package b\n"},
+			violations: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			modules := make(map[string]*ast.Module, len(tc.policies))
+			for filename, content := range tc.policies {
+				modules[filename] = parse.MustParseModule(content)
+			}
+
+			input := rules.NewInput(tc.policies, modules)
+
+			linter := NewLinter().
+				WithDisableAll(true).
+				WithEnabledRules("at-least-one-allow").
+				WithCustomRules([]string{rulePath}).
+				WithInputModules(&input)
+
+			result := testutil.Must(linter.Lint(context.Background()))(t)
+
+			if len(result.Violations) != tc.violations {
+				t.Fatalf("expected %d violations, got %d: %v", tc.violations, len(result.Violations), result.Violations)
+			}
+		})
+	}
+}
+
+func TestLintWithLintedFiles(t *testing.T) {
+	t.Parallel()
+
+	rule := `# METADATA
+# description: All files must be seen
+package custom.regal.rules.testcase["all-files-seen"]
+
+import rego.v1
+
+import data.regal.result
+
+aggregate contains result.aggregate(rego.metadata.chain(), {})
+
+# METADATA
+# schemas:
+#   - input: schema.regal.aggregate
+aggregate_report contains violation if {
+	count(input.aggregate) == 3
+
+	some entry in input.aggregate
+
+	violation := result.fail(rego.metadata.chain(), {"location": {
+		"file": entry.aggregate_source.file,
+		"row": 1,
+		"col": 1,
+	}})
+}
+`
+
+	dir := t.TempDir()
+	policies := filepath.Join(dir, "policies")
+
+	if err := os.Mkdir(policies, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, content := range map[string]string{
+		filepath.Join(dir, "rules.rego"):  rule,
+		filepath.Join(policies, "a.rego"): "package a\n",
+		filepath.Join(policies, "b.rego"): "package b\n",
+		filepath.Join(policies, "c.rego"): "package c\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	linter := NewLinter().
+		WithDisableAll(true).
+		WithEnabledRules("all-files-seen").
+		WithCustomRules([]string{filepath.Join(dir, "rules.rego")}).
+		WithInputPaths([]string{policies}).
+		WithLintedFiles(func(path string) bool {
+			return filepath.Base(path) == "a.rego"
+		})
+
+	result := testutil.Must(linter.Lint(context.Background()))(t)
+
+	if result.Summary.FilesScanned != 1 {
+		t.Errorf("expected 1 file scanned, got %d", result.Summary.FilesScanned)
+	}
+
+	if len(result.Violations) != 1 {
+		t.Fatalf("expected 1 violation, got %d: %v", len(result.Violations), result.Violations)
+	}
+
+	if file := filepath.Base(result.Violations[0].Location.File); file != "a.rego" {
+		t.Errorf("expected violation in a.rego, got %s", file)
+	}
+}
+
+func TestLintAggregatesCollectedPerFile(t *testing.T) {
+	t.Parallel()
+
+	policies := map[string]string{
+		"foo.rego": "package foo\n\nimport data.bar\n\ndefault allow := false\n",
+		"bar.rego": "package bar\n\nimport data.foo.allow\n",
+	}
+
+	linter := NewLinter().
+		WithDisableAll(true).
+		WithEnabledRules("prefer-package-imports").
+		WithExportAggregates(true)
+
+	aggregates := make(map[string][]report.Aggregate)
+
+	// lint each file on its own, keeping the aggregates collected from each
+	for filename, content := range policies {
+		input := rules.NewInput(
+			map[string]string{filename: content},
+			map[string]*ast.Module{filename: parse.MustParseModule(content)},
+		)
+
+		result := testutil.Must(linter.WithInputModules(&input).Lint(context.Background()))(t)
+
+		for key, collected := range result.Aggregates {
+			for _, aggregate := range collected {
+				if aggregate.SourceFile() != filename {
+					t.Errorf("expected aggregate to be collected from %s, got %s", filename, aggregate.SourceFile())
+				}
+			}
+
+			aggregates[key] = append(aggregates[key], collected...)
+		}
+	}
+
+	result := testutil.Must(linter.LintAggregates(context.Background(), aggregates))(t)
+
+	if len(result.Violations) != 1 {
+		t.Fatalf("expected one violation, got %d", len(result.Violations))
+	}
+
+	if result.Violations[0].Title != "prefer-package-imports" || result.Violations[0].Location.File != "bar.rego" {
+		t.Errorf("expected prefer-package-imports violation in bar.rego, got %v", result.Violations[0])
+	}
+
+	if !result.Violations[0].IsAggregate {
+		t.Errorf("expected violation to be marked as aggregate")
+	}
+}
+
 func TestEnabledRules(t *testing.T) {
 	t.Parallel()
 
@@ -597,3 +1057,169 @@ func TestEnabledRules(t *testing.T) {
 		t.Errorf("expected first enabled rule to be 'opa-fmt', got %q", enabledRules[1])
 	}
 }
+
+func TestLintInBatches(t *testing.T) {
+	t.Parallel()
+
+	linter := NewLinter().
+		WithInputPaths([]string{"../../e2e/testdata/aggregates/three_policies"}).
+		WithCustomRules([]string{"../../e2e/testdata/aggregates/rules/custom_rules_using_aggregates.rego"})
+
+	all := testutil.Must(linter.Lint(context.Background()))(t)
+	batched := testutil.Must(linter.WithBatchSize(1).Lint(context.Background()))(t)
+
+	if !reflect.DeepEqual(all.Summary, batched.Summary) {
+		t.Errorf("expected summary %+v when linting in batches, got %+v", all.Summary, batched.Summary)
+	}
+
+	aggregates := 0
+
+	for _, violation := range batched.Violations {
+		if violation.IsAggregate {
+			aggregates++
+		}
+	}
+
+	if aggregates == 0 {
+		t.Errorf("expected violations from aggregate rules when linting in batches")
+	}
+}
+
+func TestBatches(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{"a", "b", "c", "d", "e"}
+
+	for size, expected := range map[int]int{0: 1, 2: 3, 5: 1, 10: 1} {
+		if got := len(batches(paths, size)); got != expected {
+			t.Errorf("expected %d batches of size %d, got %d", expected, size, got)
+		}
+	}
+
+	if got := batches(nil, 2); len(got) != 1 || len(got[0]) != 0 {
+		t.Errorf("expected a single empty batch for no paths, got %v", got)
+	}
+}
+
+func TestEnabledRulesWithUserConfig(t *testing.T) {
+	t.Parallel()
+
+	linter := NewLinter().WithUserConfig(config.Config{
+		Rules: map[string]config.Category{
+			"style": {"prefer-snake-case": config.Rule{Level: "ignore"}},
+		},
+	})
+
+	enabledRules, err := linter.DetermineEnabledRules(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if slices.Contains(enabledRules, "prefer-snake-case") {
+		t.Errorf("expected prefer-snake-case to be disabled by user config")
+	}
+
+	if !slices.Contains(enabledRules, "opa-fmt") {
+		t.Errorf("expected opa-fmt to be enabled")
+	}
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	linter := NewLinter().
+		WithUserConfig(config.Config{
+			Rules: map[string]config.Category{
+				"style": {
+					"prefer-snake-case": config.Rule{Level: "ignore"},
+					"line-length": config.Rule{
+						Level:  "warning",
+						Ignore: &config.Ignore{Files: []string{"generated/*"}},
+					},
+				},
+			},
+		}).
+		WithEnabledRules("prefer-snake-case").
+		WithDisabledRules("opa-fmt")
+
+	conf, ignored, err := linter.EffectiveConfig(context.Background(), "generated/p.rego")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if ignored {
+		t.Errorf("expected file not to be ignored")
+	}
+
+	for _, tc := range []struct {
+		category string
+		rule     string
+		level    string
+	}{
+		{"style", "prefer-snake-case", "error"},
+		{"style", "opa-fmt", "ignore"},
+		{"style", "line-length", "ignore"},
+		{"bugs", "constant-condition", "error"},
+	} {
+		if level := conf.Rules[tc.category][tc.rule].Level; level != tc.level {
+			t.Errorf("expected level of %s to be %s, got %s", tc.rule, tc.level, level)
+		}
+	}
+
+	conf, ignored, err = linter.WithIgnore([]string{"generated/"}).
+		EffectiveConfig(context.Background(), "generated/p.rego")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !ignored {
+		t.Errorf("expected file to be ignored")
+	}
+
+	if level := conf.Rules["bugs"]["constant-condition"].Level; level != "ignore" {
+		t.Errorf("expected all rules to be ignored for an ignored file, got %s", level)
+	}
+}
+
+//nolint:paralleltest // sets the global tracer provider
+func TestLintTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	input := test.InputPolicy("p.rego", "package p\n\nimport rego.v1\n\ncamelCase := true\n")
+
+	linter := NewLinter().
+		WithEnableAll(true).
+		WithInputPaths([]string{filepath.Join("testdata", "printer.rego")}).
+		WithInputModules(&input)
+
+	testutil.Must(linter.Lint(context.Background()))(t)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	names := make([]string, 0, len(recorder.Ended()))
+
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+		names = append(names, span.Name())
+	}
+
+	for _, name := range []string{
+		"regal.lint", "regal.load", "regal.parse", "regal.prepare", "regal.prepare_input",
+		"regal.eval", "regal.eval_rule", "regal.eval_aggregates",
+	} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("expected span %s, got %v", name, names)
+
+			continue
+		}
+
+		if name != "regal.lint" && span.Parent().SpanID() == (trace.SpanID{}) {
+			t.Errorf("expected span %s to have a parent", name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/rego"
+
+	"github.com/styrainc/regal/pkg/config"
+)
+
+//nolint:gochecknoglobals
+var (
+	// rulesConfigQuery resolves the configuration of each rule provided in input, grouped by category.
+	rulesConfigQuery = ast.MustParseBody(`rules_config := {category: {title: data.regal.config.for_rule(category, title) |
+		title := titles[_]
+	} | titles := input[category]}`)
+
+	// packages under which rules may be found, i.e. data.regal.rules.<category>.<title> for the bundled rules,
+	// and data.custom.regal.rules.<category>.<title> for custom rules.
+	rulePackagePrefixes = []ast.Ref{
+		ast.MustParseRef("data.regal.rules"),
+		ast.MustParseRef("data.custom.regal.rules"),
+	}
+)
+
+// dataBundleWithRulesConfig returns the internal data bundle for the configuration, extended with the resolved
+// configuration of each rule known to the linter. The configuration, along with any overrides from command line
+// flags, is the same for every file linted, so rather than having each rule, and the policy deciding which rules
+// to run, resolve it again with data.regal.config.for_rule for every file, it's resolved by one query before
+// linting, and looked up from data.internal.rules_config for each file.
+func (l Linter) dataBundleWithRulesConfig(ctx context.Context, conf config.Config) (*bundle.Bundle, error) {
+	dataBundle := internalDataBundle(conf)
+	l.dataBundle = dataBundle
+
+	if l.unresolvedRulesConfig {
+		return dataBundle, nil
+	}
+
+	compiled, err := l.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string][]string)
+
+	for _, module := range compiled.compiler.Modules {
+		if category, title, ok := ruleFromPackage(module.Package.Path); ok && !slices.Contains(titles[category], title) {
+			titles[category] = append(titles[category], title)
+		}
+	}
+
+	regoArgs, err := l.prepareRegoArgs(rulesConfigQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed preparing query for rules configuration: %w", err)
+	}
+
+	rs, err := rego.New(append(regoArgs, rego.Input(titles))...).Eval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rules configuration: %w", err)
+	}
+
+	if len(rs) != 1 {
+		return nil, fmt.Errorf("expected 1 item in resultset, got %d", len(rs))
+	}
+
+	rulesConfig, ok := rs[0].Bindings["rules_config"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected rules configuration to be an object, got %T", rs[0].Bindings["rules_config"])
+	}
+
+	if internal, ok := dataBundle.Data["internal"].(map[string]any); ok {
+		internal["rules_config"] = rulesConfig
+	}
+
+	return dataBundle, nil
+}
+
+// ruleOfRef returns the category and title of the rule referenced by ref, if any, like by an import of the rule for
+// its helpers.
+func ruleOfRef(ref ast.Ref) (string, string, bool) {
+	for _, prefix := range rulePackagePrefixes {
+		if len(ref) >= len(prefix)+2 && ref.HasPrefix(prefix) {
+			return ruleFromPackage(ref[:len(prefix)+2])
+		}
+	}
+
+	return "", "", false
+}
+
+// ruleFromPackage returns the category and title of the rule declared by a package, if any.
+func ruleFromPackage(path ast.Ref) (string, string, bool) {
+	for _, prefix := range rulePackagePrefixes {
+		if len(path) != len(prefix)+2 || !path.HasPrefix(prefix) {
+			continue
+		}
+
+		category, ok1 := path[len(prefix)].Value.(ast.String)
+		title, ok2 := path[len(prefix)+1].Value.(ast.String)
+
+		if ok1 && ok2 {
+			return string(category), string(title), true
+		}
+	}
+
+	return "", "", false
+}
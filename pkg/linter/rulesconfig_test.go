@@ -0,0 +1,105 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+func TestDataBundleWithRulesConfig(t *testing.T) {
+	t.Parallel()
+
+	userConfig := config.Config{
+		Rules: map[string]config.Category{
+			"style": {
+				"line-length": config.Rule{
+					Level: "warning",
+					Extra: config.ExtraAttributes{"max-line-length": 100},
+				},
+				"prefer-snake-case": config.Rule{Level: "ignore"},
+			},
+		},
+	}
+
+	linter := NewLinter().
+		WithUserConfig(userConfig).
+		WithDisabledCategories("testing").
+		WithDisabledRules("constant-condition").
+		WithEnabledRules("prefer-snake-case")
+
+	conf, err := linter.mergedConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataBundle, err := linter.dataBundleWithRulesConfig(context.Background(), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	internal, _ := dataBundle.Data["internal"].(map[string]any)
+	rulesConfig, _ := internal["rules_config"].(map[string]any)
+
+	levels := map[string]string{
+		"style/line-length":         "warning",
+		"style/prefer-snake-case":   "error",
+		"bugs/rule-shadows-builtin": "error",
+	}
+
+	for rule, expected := range levels {
+		category, title, _ := strings.Cut(rule, "/")
+
+		ruleConfig, _ := rulesConfig[category].(map[string]any)[title].(map[string]any)
+		if ruleConfig["level"] != expected {
+			t.Errorf("expected level %q for rule %s, got %v", expected, rule, ruleConfig["level"])
+		}
+	}
+
+	// disabled rules aren't compiled, and so there's nothing to resolve
+	if _, ok := rulesConfig["testing"]; ok {
+		t.Errorf("expected no configuration resolved for rules of disabled category")
+	}
+
+	if _, ok := rulesConfig["bugs"].(map[string]any)["constant-condition"]; ok {
+		t.Errorf("expected no configuration resolved for disabled rule")
+	}
+
+	lineLength, _ := rulesConfig["style"].(map[string]any)["line-length"].(map[string]any)
+	if lineLength["max-line-length"] == nil {
+		t.Errorf("expected rule configuration to include extra attributes, got %v", lineLength)
+	}
+}
+
+// BenchmarkLintRulesConfig compares linting with the configuration of each rule resolved before linting, with
+// having it resolved for every file linted.
+func BenchmarkLintRulesConfig(b *testing.B) {
+	files := make(map[string]string)
+	modules := make(map[string]*ast.Module)
+
+	for i := range 50 {
+		name := fmt.Sprintf("p%d.rego", i)
+		files[name] = fmt.Sprintf("package p%d\n\nimport rego.v1\n\nallow if {\n\tinput.x == %d\n}\n", i, i)
+		modules[name] = ast.MustParseModule(files[name])
+	}
+
+	input := rules.NewInput(files, modules)
+
+	for name, unresolved := range map[string]bool{"resolved": false, "unresolved": true} {
+		b.Run(name, func(b *testing.B) {
+			linter := NewLinter().WithInputModules(&input)
+			linter.unresolvedRulesConfig = unresolved
+
+			for range b.N {
+				if _, err := linter.Lint(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
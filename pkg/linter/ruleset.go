@@ -0,0 +1,260 @@
+package linter
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/util"
+	"github.com/styrainc/regal/pkg/version"
+)
+
+// RuleSetVersion is the version of the format of rule sets, incremented on changes breaking the comparison of rule
+// sets locked with earlier versions.
+const RuleSetVersion = 1
+
+// Sources of the rules of a rule set.
+const (
+	// RuleSourceBundle is the source of the Rego rules of the bundles of the linter, like the embedded Regal rules.
+	RuleSourceBundle = "bundle"
+	// RuleSourceCustom is the source of custom rules, like those of the .regal/rules directory.
+	RuleSourceCustom = "custom"
+	// RuleSourceGo is the source of the rules implemented in Go, which are versioned with Regal itself.
+	RuleSourceGo = "go"
+)
+
+// RuleSet describes the rules enabled for a linter, along with the bundles they're from, for the rule set to be
+// locked, and any drift from it detected, like rules changed in a bundle, or enabled by a change of configuration.
+type RuleSet struct {
+	Version      int             `json:"version"`
+	RegalVersion string          `json:"regal_version"`
+	Bundles      []RuleSetBundle `json:"bundles"`
+	Rules        []RuleSetRule   `json:"rules"`
+}
+
+// RuleSetBundle is a bundle of rules, identified by its digest, computed from its modules and data.
+type RuleSetBundle struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+}
+
+// RuleSetRule is an enabled rule, with the level it's reported at. The digest of Rego rules is computed from their
+// modules, while Go rules have none.
+type RuleSetRule struct {
+	Category string `json:"category"`
+	Title    string `json:"title"`
+	Level    string `json:"level"`
+	Source   string `json:"source"`
+	Digest   string `json:"digest,omitempty"`
+}
+
+// ID returns the identifier of the rule, made up of its category and title.
+func (r RuleSetRule) ID() string {
+	return r.Category + "/" + r.Title
+}
+
+// RuleSet returns the rule set of the linter, with the rules enabled by its configuration and options.
+func (l Linter) RuleSet() (RuleSet, error) {
+	conf, err := l.mergedConfig()
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	modules, _, err := l.ruleModules()
+	if err != nil {
+		return RuleSet{}, err
+	}
+
+	rs := RuleSet{Version: RuleSetVersion, RegalVersion: version.New().Version}
+
+	for _, ruleBundle := range l.ruleBundles {
+		name, _ := ruleBundle.Manifest.Metadata["name"].(string)
+
+		digest, err := digestOf(bundleModules(ruleBundle), ruleBundle.Data)
+		if err != nil {
+			return RuleSet{}, fmt.Errorf("failed to compute digest of bundle %s: %w", name, err)
+		}
+
+		rs.Bundles = append(rs.Bundles, RuleSetBundle{Name: name, Digest: digest})
+	}
+
+	// the modules of each rule, of which there may be more than one
+	modulesOf := make(map[RuleSetRule]map[string]*ast.Module)
+	customModules := make(map[string]*ast.Module)
+
+	for path, module := range modules {
+		category, title, ok := ruleFromPackage(module.Package.Path)
+		if !ok {
+			continue
+		}
+
+		source := RuleSourceBundle
+		if !module.Package.Path.HasPrefix(rulePackagePrefixes[0]) {
+			source = RuleSourceCustom
+			customModules[path] = module
+		}
+
+		ruleConfig, configured := conf.Rules[category][title]
+
+		// bundled rules are only run when found in the configuration, while custom rules are run by default
+		if !configured && source == RuleSourceBundle || !l.ruleEnabled(category, title, ruleConfig.Level) {
+			continue
+		}
+
+		rule := RuleSetRule{Category: category, Title: title, Level: enabledLevel(ruleConfig.Level), Source: source}
+		if modulesOf[rule] == nil {
+			modulesOf[rule] = make(map[string]*ast.Module)
+		}
+
+		modulesOf[rule][path] = module
+	}
+
+	if len(customModules) > 0 {
+		digest, err := digestOf(customModules, nil)
+		if err != nil {
+			return RuleSet{}, fmt.Errorf("failed to compute digest of custom rules: %w", err)
+		}
+
+		rs.Bundles = append(rs.Bundles, RuleSetBundle{Name: RuleSourceCustom, Digest: digest})
+	}
+
+	for rule, ruleModules := range modulesOf {
+		if rule.Digest, err = digestOf(ruleModules, nil); err != nil {
+			return RuleSet{}, fmt.Errorf("failed to compute digest of rule %s: %w", rule.ID(), err)
+		}
+
+		rs.Rules = append(rs.Rules, rule)
+	}
+
+	goRules, err := l.enabledGoRules()
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("failed to get enabled Go rules: %w", err)
+	}
+
+	for _, rule := range goRules {
+		rs.Rules = append(rs.Rules, RuleSetRule{
+			Category: rule.Category(),
+			Title:    rule.Name(),
+			Level:    enabledLevel(rule.Config().Level),
+			Source:   RuleSourceGo,
+		})
+	}
+
+	slices.SortFunc(rs.Rules, func(a, b RuleSetRule) int {
+		return cmp.Or(cmp.Compare(a.Category, b.Category), cmp.Compare(a.Title, b.Title))
+	})
+
+	return rs, nil
+}
+
+// Drift returns the differences of the rule set from the locked one, as one line per difference, or none if the
+// rule sets are the same.
+func (rs RuleSet) Drift(locked RuleSet) []string {
+	var drift []string
+
+	if rs.RegalVersion != locked.RegalVersion {
+		drift = append(drift, fmt.Sprintf("Regal version changed from %s to %s", locked.RegalVersion, rs.RegalVersion))
+	}
+
+	lockedBundles := make(map[string]RuleSetBundle, len(locked.Bundles))
+	for _, b := range locked.Bundles {
+		lockedBundles[b.Name] = b
+	}
+
+	for _, b := range rs.Bundles {
+		lb, ok := lockedBundles[b.Name]
+
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("bundle %s added", b.Name))
+		case lb.Digest != b.Digest:
+			drift = append(drift, fmt.Sprintf("bundle %s changed from digest %s to %s", b.Name, lb.Digest, b.Digest))
+		}
+
+		delete(lockedBundles, b.Name)
+	}
+
+	for _, name := range sortedKeys(lockedBundles) {
+		drift = append(drift, fmt.Sprintf("bundle %s removed", name))
+	}
+
+	lockedRules := make(map[string]RuleSetRule, len(locked.Rules))
+	for _, r := range locked.Rules {
+		lockedRules[r.ID()] = r
+	}
+
+	for _, r := range rs.Rules {
+		lr, ok := lockedRules[r.ID()]
+
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("rule %s enabled", r.ID()))
+		case lr.Level != r.Level:
+			drift = append(drift, fmt.Sprintf("rule %s level changed from %s to %s", r.ID(), lr.Level, r.Level))
+		case lr.Source != r.Source:
+			drift = append(drift, fmt.Sprintf("rule %s source changed from %s to %s", r.ID(), lr.Source, r.Source))
+		case lr.Digest != r.Digest:
+			drift = append(drift, fmt.Sprintf("rule %s changed", r.ID()))
+		}
+
+		delete(lockedRules, r.ID())
+	}
+
+	for _, id := range sortedKeys(lockedRules) {
+		drift = append(drift, fmt.Sprintf("rule %s disabled", id))
+	}
+
+	return drift
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := util.Keys(m)
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// enabledLevel returns the level of an enabled rule, where rules enabled by options rather than configuration are
+// reported as errors.
+func enabledLevel(level string) string {
+	if level == "" || level == "ignore" {
+		return "error"
+	}
+
+	return level
+}
+
+// digestOf returns the SHA-256 digest of the modules, as formatted by OPA, such that changes to comments or formatting
+// don't change the digest, along with any data. The paths of modules are left out, for the digest of custom rules not
+// to depend on the directory they're found in.
+func digestOf(modules map[string]*ast.Module, data map[string]any) (string, error) {
+	formatted := make([]string, 0, len(modules))
+	for _, module := range modules {
+		formatted = append(formatted, module.String())
+	}
+
+	slices.Sort(formatted)
+
+	h := sha256.New()
+
+	for _, module := range formatted {
+		h.Write([]byte(module + "\n"))
+	}
+
+	if len(data) > 0 {
+		bs, err := json.Marshal(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal data: %w", err)
+		}
+
+		h.Write(bs)
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,109 @@
+package linter
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/styrainc/regal/internal/testutil"
+	"github.com/styrainc/regal/pkg/config"
+)
+
+func TestRuleSet(t *testing.T) {
+	t.Parallel()
+
+	userConfig := config.Config{
+		Rules: map[string]config.Category{
+			"style": {
+				"opa-fmt":     config.Rule{Level: "ignore"},
+				"line-length": config.Rule{Level: "warning"},
+			},
+		},
+	}
+
+	linter := NewLinter().
+		WithUserConfig(userConfig).
+		WithCustomRules([]string{filepath.Join("testdata", "custom.rego")}).
+		WithEnabledRules("prefer-some-in-iteration")
+
+	rs := testutil.Must(linter.RuleSet())(t)
+
+	rules := make(map[string]RuleSetRule, len(rs.Rules))
+	for _, rule := range rs.Rules {
+		rules[rule.ID()] = rule
+	}
+
+	if _, ok := rules["style/opa-fmt"]; ok {
+		t.Error("expected ignored rule opa-fmt not to be in rule set")
+	}
+
+	if rule := rules["style/line-length"]; rule.Level != "warning" || rule.Source != RuleSourceBundle ||
+		rule.Digest == "" {
+		t.Errorf("expected bundled rule line-length with level warning and digest, got %+v", rule)
+	}
+
+	if rule := rules["naming/acme-corp-package"]; rule.Level != "error" || rule.Source != RuleSourceCustom {
+		t.Errorf("expected custom rule acme-corp-package with level error, got %+v", rule)
+	}
+
+	if rule := rules["style/prefer-some-in-iteration"]; rule.Level != "error" {
+		t.Errorf("expected rule enabled by option to have level error, got %+v", rule)
+	}
+
+	if rule := rules["bugs/field-not-in-schema"]; rule.Title != "" {
+		t.Errorf("expected Go rule ignored by default not to be in rule set, got %+v", rule)
+	}
+
+	bundles := make([]string, 0, len(rs.Bundles))
+	for _, b := range rs.Bundles {
+		bundles = append(bundles, b.Name)
+	}
+
+	if exp := []string{"regal", RuleSourceCustom}; !slices.Equal(bundles, exp) {
+		t.Errorf("expected bundles %v, got %v", exp, bundles)
+	}
+
+	if drift := rs.Drift(testutil.Must(linter.RuleSet())(t)); len(drift) != 0 {
+		t.Errorf("expected no drift of the same rule set, got %v", drift)
+	}
+}
+
+func TestRuleSetDrift(t *testing.T) {
+	t.Parallel()
+
+	locked := RuleSet{
+		Version:      RuleSetVersion,
+		RegalVersion: "v0.1.0",
+		Bundles:      []RuleSetBundle{{Name: "regal", Digest: "sha256:a"}, {Name: "custom", Digest: "sha256:c"}},
+		Rules: []RuleSetRule{
+			{Category: "bugs", Title: "changed", Level: "error", Source: RuleSourceBundle, Digest: "sha256:1"},
+			{Category: "bugs", Title: "removed", Level: "error", Source: RuleSourceBundle, Digest: "sha256:2"},
+			{Category: "style", Title: "relevelled", Level: "error", Source: RuleSourceGo},
+		},
+	}
+
+	current := RuleSet{
+		Version:      RuleSetVersion,
+		RegalVersion: "v0.2.0",
+		Bundles:      []RuleSetBundle{{Name: "regal", Digest: "sha256:b"}},
+		Rules: []RuleSetRule{
+			{Category: "bugs", Title: "added", Level: "error", Source: RuleSourceBundle, Digest: "sha256:3"},
+			{Category: "bugs", Title: "changed", Level: "error", Source: RuleSourceBundle, Digest: "sha256:4"},
+			{Category: "style", Title: "relevelled", Level: "warning", Source: RuleSourceGo},
+		},
+	}
+
+	expected := []string{
+		"Regal version changed from v0.1.0 to v0.2.0",
+		"bundle regal changed from digest sha256:a to sha256:b",
+		"bundle custom removed",
+		"rule bugs/added enabled",
+		"rule bugs/changed changed",
+		"rule style/relevelled level changed from error to warning",
+		"rule bugs/removed disabled",
+	}
+
+	if drift := current.Drift(locked); !slices.Equal(drift, expected) {
+		t.Errorf("expected drift:\n%v\ngot:\n%v", expected, drift)
+	}
+}
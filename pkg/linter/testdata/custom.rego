@@ -3,6 +3,16 @@
 # related_resources:
 # - description: documentation
 #   ref: https://www.acmecorp.example.org/docs/regal/package
+# custom:
+#   examples:
+#     fail:
+#       - |
+#         package policy
+#     pass:
+#       - |
+#         package acme.corp.policy
+#       - |
+#         package system.log
 package custom.regal.rules.naming["acme-corp-package"]
 
 import rego.v1
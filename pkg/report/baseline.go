@@ -0,0 +1,125 @@
+package report
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SuppressedByBaseline is the suppression of violations recorded in the baseline provided.
+const SuppressedByBaseline = "baseline"
+
+// Baseline is a record of the violations found in a set of policies, for violations already known, like those found
+// when first adopting Regal in a large codebase, to be suppressed when linting later, and only new ones reported.
+type Baseline struct {
+	Violations []BaselineViolation `json:"violations"`
+}
+
+// BaselineViolation is a violation recorded in a baseline. Only the fingerprint is used to match violations, while
+// the other attributes are there to tell what was recorded.
+type BaselineViolation struct {
+	Fingerprint string `json:"fingerprint"`
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	File        string `json:"file"`
+}
+
+// NewBaseline returns a baseline recording the violations of the report, ordered by file and rule, for baselines
+// regenerated to diff well.
+func NewBaseline(r Report) Baseline {
+	fingerprints := Fingerprints(r.Violations)
+	violations := make([]BaselineViolation, 0, len(r.Violations))
+
+	for i, violation := range r.Violations {
+		violations = append(violations, BaselineViolation{
+			Fingerprint: fingerprints[i],
+			Category:    violation.Category,
+			Title:       violation.Title,
+			File:        filepath.ToSlash(violation.Location.File),
+		})
+	}
+
+	slices.SortFunc(violations, func(a, b BaselineViolation) int {
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Category, b.Category),
+			cmp.Compare(a.Title, b.Title),
+			cmp.Compare(a.Fingerprint, b.Fingerprint),
+		)
+	})
+
+	return Baseline{Violations: violations}
+}
+
+// ApplyBaseline removes the violations recorded in the baseline from the report, keeping them as suppressed when
+// keepSuppressed is set, for auditing what the baseline suppresses.
+func (r *Report) ApplyBaseline(b Baseline, keepSuppressed bool) {
+	known := make(map[string]struct{}, len(b.Violations))
+	for _, violation := range b.Violations {
+		known[violation.Fingerprint] = struct{}{}
+	}
+
+	fingerprints := Fingerprints(r.Violations)
+	violations := make([]Violation, 0, len(r.Violations))
+
+	for i, violation := range r.Violations {
+		if _, ok := known[fingerprints[i]]; !ok {
+			violations = append(violations, violation)
+
+			continue
+		}
+
+		if keepSuppressed {
+			violation.Suppression = SuppressedByBaseline
+			r.Suppressed = append(r.Suppressed, violation)
+		}
+	}
+
+	r.Violations = violations
+}
+
+// Fingerprints returns a fingerprint identifying each of the violations, in the order provided. Rather than the row
+// of the violation, the fingerprint is based on the text of the line, so that it remains the same when lines are
+// added or removed above it. Violations of the same rule on lines with identical text in the same file are told
+// apart by the number of times seen before, counted from the top of the file.
+func Fingerprints(violations []Violation) []string {
+	order := make([]int, len(violations))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Or(
+			cmp.Compare(violations[a].Location.Row, violations[b].Location.Row),
+			cmp.Compare(violations[a].Location.Column, violations[b].Location.Column),
+		)
+	})
+
+	fingerprints := make([]string, len(violations))
+	occurrences := make(map[string]int)
+
+	for _, i := range order {
+		text := ""
+		if violations[i].Location.Text != nil {
+			text = strings.TrimSpace(*violations[i].Location.Text)
+		}
+
+		file := violations[i].Location.File
+		if file != "" {
+			file = filepath.ToSlash(filepath.Clean(file))
+		}
+
+		key := strings.Join([]string{violations[i].Category, violations[i].Title, file, text}, "\x00")
+		occurrences[key]++
+
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, occurrences[key])))
+
+		fingerprints[i] = hex.EncodeToString(sum[:])
+	}
+
+	return fingerprints
+}
@@ -0,0 +1,67 @@
+package report
+
+import "testing"
+
+func TestFingerprints(t *testing.T) {
+	t.Parallel()
+
+	text := "allow = true"
+	violation := Violation{
+		Title:    "use-assignment-operator",
+		Category: "style",
+		Location: Location{File: "p.rego", Row: 3, Column: 7, Text: &text},
+	}
+
+	moved := violation
+	moved.Location.Row = 10
+
+	first := Fingerprints([]Violation{violation})[0]
+
+	if second := Fingerprints([]Violation{moved})[0]; first != second {
+		t.Errorf("expected fingerprint to remain the same when violation moved to another row")
+	}
+
+	both := Fingerprints([]Violation{moved, violation})
+
+	if both[0] == both[1] {
+		t.Errorf("expected different fingerprints for violations on identical lines")
+	}
+
+	if both[1] != first {
+		t.Errorf("expected first violation in file to keep its fingerprint, regardless of order provided")
+	}
+}
+
+func TestApplyBaseline(t *testing.T) {
+	t.Parallel()
+
+	text := "allow = true"
+	known := Violation{
+		Title:    "use-assignment-operator",
+		Category: "style",
+		Location: Location{File: "p.rego", Row: 3, Column: 7, Text: &text},
+	}
+
+	baseline := NewBaseline(Report{Violations: []Violation{known}})
+
+	if exp, act := "p.rego", baseline.Violations[0].File; exp != act {
+		t.Errorf("expected file %s recorded in baseline, got %s", exp, act)
+	}
+
+	moved := known
+	moved.Location.Row = 5
+
+	added := known
+	added.Location.Row = 8
+
+	r := Report{Violations: []Violation{moved, added}}
+	r.ApplyBaseline(baseline, true)
+
+	if len(r.Violations) != 1 || r.Violations[0].Location.Row != 8 {
+		t.Fatalf("expected only violation added since baseline to be reported, got %v", r.Violations)
+	}
+
+	if len(r.Suppressed) != 1 || r.Suppressed[0].Suppression != SuppressedByBaseline {
+		t.Errorf("expected violation in baseline to be suppressed by baseline, got %v", r.Suppressed)
+	}
+}
@@ -1,8 +1,10 @@
 package report
 
 import (
+	"cmp"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -26,6 +28,18 @@ type Location struct {
 	Text   *string `json:"text,omitempty"`
 }
 
+const (
+	// SuppressedByDirective is the suppression of violations ignored by a "regal ignore" directive.
+	SuppressedByDirective = "ignore-directive"
+	// SuppressedByConfig is the suppression of violations in files ignored for the rule in the configuration.
+	SuppressedByConfig = "config"
+
+	// ChangeNew is the change of violations on lines added or changed since the ref compared to.
+	ChangeNew = "new"
+	// ChangePreExisting is the change of violations on lines unchanged since the ref compared to.
+	ChangePreExisting = "pre-existing"
+)
+
 // Violation describes any violation found by Regal.
 type Violation struct {
 	Title            string            `json:"title"`
@@ -34,7 +48,65 @@ type Violation struct {
 	Level            string            `json:"level"`
 	RelatedResources []RelatedResource `json:"related_resources,omitempty"`
 	Location         Location          `json:"location,omitempty"`
-	IsAggregate      bool              `json:"-"`
+	Suppression      string            `json:"suppression,omitempty"`
+	Change           string            `json:"change,omitempty"`
+	Blame            *Blame            `json:"blame,omitempty"`
+	Owners           []string          `json:"owners,omitempty"`
+	Target           string            `json:"target,omitempty"`
+	Snippet          *Snippet          `json:"snippet,omitempty"`
+	// WithinBudget is set for violations of a rule with a budget not yet exceeded, which don't fail linting.
+	WithinBudget bool `json:"within_budget,omitempty"`
+	IsAggregate  bool `json:"-"`
+}
+
+// Blame describes the commit last changing the line of a violation, as reported by git blame.
+type Blame struct {
+	Commit      string    `json:"commit"`
+	Author      string    `json:"author"`
+	AuthorEmail string    `json:"author_email,omitempty"`
+	AuthorTime  time.Time `json:"author_time"`
+}
+
+// String returns the abbreviated hash of the commit, followed by its author and the date it was authored.
+func (b Blame) String() string {
+	commit := b.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+
+	author := b.Author
+	if b.AuthorEmail != "" {
+		author += " <" + b.AuthorEmail + ">"
+	}
+
+	return fmt.Sprintf("%s %s %s", commit, author, b.AuthorTime.Format(time.DateOnly))
+}
+
+// Snippet is an excerpt of the source of a file, made up of the line of a violation and the lines around it.
+type Snippet struct {
+	// Row of the first line of the snippet, starting at 1.
+	Start int      `json:"start"`
+	Lines []string `json:"lines"`
+}
+
+// NewSnippet returns the excerpt of content made up of the line at row, and up to contextLines lines before and
+// after it, or nil if content has no line at row, like for violations of a file as a whole.
+func NewSnippet(content string, row, contextLines int) *Snippet {
+	lines := strings.Split(content, "\n")
+	if row < 1 || row > len(lines) {
+		return nil
+	}
+
+	start := max(row-contextLines, 1)
+	end := min(row+contextLines, len(lines))
+
+	snippet := &Snippet{Start: start, Lines: make([]string, 0, end-start+1)}
+
+	for _, line := range lines[start-1 : end] {
+		snippet.Lines = append(snippet.Lines, strings.TrimSuffix(line, "\r"))
+	}
+
+	return snippet
 }
 
 // Notice describes any notice found by Regal.
@@ -52,24 +124,54 @@ type Notice struct {
 // while working with large Rego code repositories.
 type Aggregate map[string]any
 
+// SourceFile returns the name of the file the aggregate was collected from.
+func (a Aggregate) SourceFile() string {
+	source, _ := a["aggregate_source"].(map[string]any)
+	file, _ := source["file"].(string)
+
+	return file
+}
+
 type Summary struct {
 	FilesScanned  int `json:"files_scanned"`
 	FilesFailed   int `json:"files_failed"`
 	RulesSkipped  int `json:"rules_skipped"`
 	NumViolations int `json:"num_violations"`
+	NumSuppressed int `json:"num_suppressed,omitempty"`
+	NumNew        int `json:"num_new,omitempty"`
+	// NumByLevel is the number of violations found of each level, i.e. error, warning and notice.
+	NumByLevel map[string]int `json:"num_by_level,omitempty"`
+}
+
+// Budget is the number of violations of a rule allowed before they fail linting, along with the number found.
+type Budget struct {
+	Category      string `json:"category"`
+	Title         string `json:"title"`
+	MaxViolations int    `json:"max_violations"`
+	Violations    int    `json:"violations"`
+}
+
+// Exceeded returns true if more violations were found than allowed by the budget.
+func (b Budget) Exceeded() bool {
+	return b.Violations > b.MaxViolations
 }
 
 // Report aggregate of Violation as returned by a linter run.
 type Report struct {
 	Violations []Violation `json:"violations"`
+	// Suppressed violations are only reported when requested, for the purpose of auditing suppressions.
+	Suppressed []Violation `json:"suppressed,omitempty"`
 	// We don't have aggregates when publishing the final report (see JSONReporter), so omitempty is needed here
 	// to avoid surfacing a null/empty field.
 	Aggregates       map[string][]Aggregate  `json:"aggregates,omitempty"`
 	Notices          []Notice                `json:"notices,omitempty"`
+	Budgets          []Budget                `json:"budgets,omitempty"`
 	Summary          Summary                 `json:"summary"`
 	Metrics          map[string]any          `json:"metrics,omitempty"`
 	AggregateProfile map[string]ProfileEntry `json:"-"`
 	Profile          []ProfileEntry          `json:"profile,omitempty"`
+	// Files linted, including those without violations, for reporters that report on each file.
+	Files []string `json:"-"`
 }
 
 // ProfileEntry is a single entry of profiling information, keyed by location.
@@ -169,6 +271,20 @@ func generateTableProfile(writer io.Writer) *tablewriter.Table { //nolint:unused
 	return generateTableWithKeys(writer, "Time", "Num Eval", "Num Redo", "Num Gen Expr", "Location")
 }
 
+// CountByLevel returns the number of violations of each level, or nil if there are no violations.
+func CountByLevel(violations []Violation) map[string]int {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, violation := range violations {
+		counts[violation.Level]++
+	}
+
+	return counts
+}
+
 // ViolationsFileCount returns the number of files containing violations.
 func (r Report) ViolationsFileCount() map[string]int {
 	fc := map[string]int{}
@@ -179,6 +295,146 @@ func (r Report) ViolationsFileCount() map[string]int {
 	return fc
 }
 
+// ClassifyChanges marks each violation as either new or pre-existing, as decided by isNew for the location of the
+// violation, and counts the new violations in the summary.
+func (r *Report) ClassifyChanges(isNew func(file string, row int) bool) {
+	r.Summary.NumNew = 0
+
+	for i := range r.Violations {
+		if isNew(r.Violations[i].Location.File, r.Violations[i].Location.Row) {
+			r.Violations[i].Change = ChangeNew
+			r.Summary.NumNew++
+		} else {
+			r.Violations[i].Change = ChangePreExisting
+		}
+	}
+}
+
+// AttributeBlame sets the commit last changing the line of each violation, as returned by blame, for violations on
+// lines with a commit to report.
+func (r *Report) AttributeBlame(blame func(file string, row int) (Blame, bool)) {
+	for i := range r.Violations {
+		if b, ok := blame(r.Violations[i].Location.File, r.Violations[i].Location.Row); ok {
+			r.Violations[i].Blame = &b
+		}
+	}
+}
+
+// AssignOwners sets the owners of each violation to the owners of its file, as returned by owners.
+func (r *Report) AssignOwners(owners func(file string) []string) {
+	for i := range r.Violations {
+		r.Violations[i].Owners = owners(r.Violations[i].Location.File)
+	}
+}
+
+// AssignTargets sets the target of each violation, as returned by target for its file, when several targets are
+// linted with configurations of their own.
+func (r *Report) AssignTargets(target func(file string) string) {
+	for i := range r.Violations {
+		r.Violations[i].Target = target(r.Violations[i].Location.File)
+	}
+
+	for i := range r.Suppressed {
+		r.Suppressed[i].Target = target(r.Suppressed[i].Location.File)
+	}
+}
+
+// Merge adds the violations, notices and files of other to the report, as when several targets are linted separately,
+// and updates the summary to cover both. Metrics are those of other, as collected across both, while profiles are
+// combined, with the slowest locations first.
+func (r *Report) Merge(other Report) {
+	r.Violations = append(r.Violations, other.Violations...)
+	r.Suppressed = append(r.Suppressed, other.Suppressed...)
+	r.Files = append(r.Files, other.Files...)
+
+	for _, notice := range other.Notices {
+		if !slices.Contains(r.Notices, notice) {
+			r.Notices = append(r.Notices, notice)
+
+			if notice.Severity != "none" {
+				r.Summary.RulesSkipped++
+			}
+		}
+	}
+
+	if len(other.Budgets) > 0 {
+		r.Budgets = append(r.Budgets, other.Budgets...)
+
+		sortBudgets(r.Budgets)
+	}
+
+	if other.Metrics != nil {
+		r.Metrics = other.Metrics
+	}
+
+	if len(other.Profile) > 0 {
+		r.Profile = append(r.Profile, other.Profile...)
+
+		sort.SliceStable(r.Profile, func(i, j int) bool {
+			return r.Profile[i].TotalTimeNs > r.Profile[j].TotalTimeNs
+		})
+	}
+
+	r.Summary.FilesScanned += other.Summary.FilesScanned
+	r.Summary.FilesFailed = len(r.ViolationsFileCount())
+	r.Summary.NumViolations = len(r.Violations)
+	r.Summary.NumByLevel = CountByLevel(r.Violations)
+	r.Summary.NumSuppressed = len(r.Suppressed)
+}
+
+// ApplyBudgets counts the violations of each rule with a budget, as returned by maxViolations, and marks those of
+// rules with a budget not exceeded as within budget.
+func (r *Report) ApplyBudgets(maxViolations func(category, title string) (int, bool)) {
+	budgets := make(map[string]*Budget)
+
+	for _, violation := range r.Violations {
+		key := violation.Category + "/" + violation.Title
+
+		if budget, ok := budgets[key]; ok {
+			budget.Violations++
+
+			continue
+		}
+
+		if limit, ok := maxViolations(violation.Category, violation.Title); ok {
+			budgets[key] = &Budget{
+				Category:      violation.Category,
+				Title:         violation.Title,
+				MaxViolations: limit,
+				Violations:    1,
+			}
+		}
+	}
+
+	for i := range r.Violations {
+		if budget, ok := budgets[r.Violations[i].Category+"/"+r.Violations[i].Title]; ok {
+			r.Violations[i].WithinBudget = !budget.Exceeded()
+		}
+	}
+
+	for _, budget := range budgets {
+		r.Budgets = append(r.Budgets, *budget)
+	}
+
+	sortBudgets(r.Budgets)
+}
+
+func sortBudgets(budgets []Budget) {
+	slices.SortFunc(budgets, func(a, b Budget) int {
+		return cmp.Or(cmp.Compare(a.Category, b.Category), cmp.Compare(a.Title, b.Title))
+	})
+}
+
+// SuppressedByRule returns the number of suppressed violations of each rule.
+func (r Report) SuppressedByRule() map[string]int {
+	counts := map[string]int{}
+	for _, violation := range r.Suppressed {
+		counts[violation.Title]++
+	}
+
+	return counts
+}
+
 // String shorthand form for a Location.
 func (l Location) String() string {
 	if l.Row == 0 && l.Column == 0 {
@@ -2,19 +2,34 @@
 package reporter
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"html"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/owenrumney/go-sarif/v2/sarif"
+	"gopkg.in/yaml.v3"
 
+	"github.com/styrainc/regal/internal/embeds"
 	"github.com/styrainc/regal/internal/novelty"
+	"github.com/styrainc/regal/internal/util"
+	"github.com/styrainc/regal/pkg/fixer"
+	"github.com/styrainc/regal/pkg/fixer/fixes"
 	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/version"
 )
 
 // Reporter releases linter reports in a format decided by the implementation.
@@ -23,19 +38,63 @@ type Reporter interface {
 	Publish(context.Context, report.Report) error
 }
 
+// Values for grouping the violations reported by the PrettyReporter, the CompactReporter, the JSONReporter and the
+// MarkdownReporter, which otherwise report violations in the order of the report, or by file. Grouping by owner or
+// target requires the owners or targets of violations to have been assigned, and violations with several owners are
+// reported in the group of each.
+const (
+	GroupByFile     = "file"
+	GroupByRule     = "rule"
+	GroupByCategory = "category"
+	GroupByOwner    = "owner"
+	GroupByTarget   = "target"
+)
+
+// Unowned is the key of the group of violations without owners, when grouping by owner.
+const Unowned = "(unowned)"
+
 // PrettyReporter is a Reporter for representing reports as tables.
 type PrettyReporter struct {
-	out io.Writer
+	out     io.Writer
+	groupBy string
 }
 
 // CompactReporter reports violations in a compact table.
 type CompactReporter struct {
-	out io.Writer
+	out     io.Writer
+	groupBy string
+}
+
+// SnippetReporter reports violations along with the lines of source they were found on, and a caret pointing at the
+// column of each violation, in the style of compilers like rustc. Snippets of more than the line of a violation are
+// shown when included in violations by the linter, or else the text of the location of the violation.
+type SnippetReporter struct {
+	out     io.Writer
+	groupBy string
 }
 
+// JSONReportVersion is the version of the format of the reports of the JSONReporter, which is incremented on
+// changes breaking consumers of reports, like the removal or renaming of fields. The format is described by the
+// schema returned by JSONReportSchema.
+const JSONReportVersion = 1
+
 // JSONReporter reports violations as JSON.
 type JSONReporter struct {
-	out io.Writer
+	out     io.Writer
+	groupBy string
+}
+
+// jsonReport is the report published by the JSONReporter, with the version of the format first.
+type jsonReport struct {
+	Version int `json:"version"`
+	report.Report
+	Groups []jsonGroup `json:"groups,omitempty"`
+}
+
+// jsonGroup is a group of the violations of the report published by the JSONReporter, when grouping violations.
+type jsonGroup struct {
+	Key        string             `json:"key"`
+	Violations []report.Violation `json:"violations"`
 }
 
 // GitHubReporter reports violations in a format suitable for GitHub Actions.
@@ -53,21 +112,97 @@ type SarifReporter struct {
 	out io.Writer
 }
 
+// RdJSONReporter reports violations in the Reviewdog Diagnostic Format (https://github.com/reviewdog/reviewdog),
+// either as a single rdjson document, or as rdjsonl, i.e. one diagnostic per line.
+type RdJSONReporter struct {
+	out   io.Writer
+	lines bool
+}
+
+// GitLabReporter reports violations in the Code Climate format used by the GitLab Code Quality report
+// (https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool).
+type GitLabReporter struct {
+	out io.Writer
+}
+
+// TAPReporter reports violations in the Test Anything Protocol (https://testanything.org/) format, with one test
+// point per file linted.
+type TAPReporter struct {
+	out io.Writer
+}
+
+// JUnitReporter reports violations in the JUnit XML format, with a test suite for each file linted, and a test case
+// for each rule violated in the file, for CI systems like Jenkins which only understand JUnit reports.
+type JUnitReporter struct {
+	out io.Writer
+}
+
+// CheckstyleReporter reports violations in the Checkstyle XML format, with an error element for each violation,
+// grouped by file, for editors and CI systems integrating with Checkstyle.
+type CheckstyleReporter struct {
+	out io.Writer
+}
+
+// MarkdownReporter reports violations as a Markdown summary, with a collapsible section listing the violations of
+// each file, suitable for posting as a single comment on a pull request.
+type MarkdownReporter struct {
+	out     io.Writer
+	groupBy string
+}
+
+// ExecReporter reports violations by running an external command, which is provided the report in the JSON format
+// on its standard input, and whose output is written to the output of the reporter. This allows reporting in formats
+// not supported by Regal, without changes to Regal itself.
+type ExecReporter struct {
+	out     io.Writer
+	command []string
+}
+
 // NewPrettyReporter creates a new PrettyReporter.
 func NewPrettyReporter(out io.Writer) PrettyReporter {
 	return PrettyReporter{out: out}
 }
 
+// WithGroupBy sets the PrettyReporter to report violations grouped by file, rule, category or owner, with a heading
+// for each group.
+func (tr PrettyReporter) WithGroupBy(groupBy string) PrettyReporter {
+	tr.groupBy = groupBy
+
+	return tr
+}
+
 // NewCompactReporter creates a new CompactReporter.
 func NewCompactReporter(out io.Writer) CompactReporter {
 	return CompactReporter{out: out}
 }
 
+// WithGroupBy sets the CompactReporter to report violations grouped by file, rule, category or owner, in a first
+// column spanning the rows of each group.
+func (tr CompactReporter) WithGroupBy(groupBy string) CompactReporter {
+	tr.groupBy = groupBy
+
+	return tr
+}
+
 // NewJSONReporter creates a new JSONReporter.
 func NewJSONReporter(out io.Writer) JSONReporter {
 	return JSONReporter{out: out}
 }
 
+// WithGroupBy sets the JSONReporter to report violations grouped by file, rule, category or owner, in groups added
+// to the report, along with the violations of the report.
+func (tr JSONReporter) WithGroupBy(groupBy string) JSONReporter {
+	tr.groupBy = groupBy
+
+	return tr
+}
+
+// JSONReportSchema returns the JSON Schema of the reports of the JSONReporter, for consumers of reports to validate
+// them, or to generate code from.
+func JSONReportSchema() []byte {
+	return util.Must(embeds.SchemasFS.ReadFile("schemas/report.json"))
+}
+
 // NewGitHubReporter creates a new GitHubReporter.
 func NewGitHubReporter(out io.Writer) GitHubReporter {
 	return GitHubReporter{out: out}
@@ -78,6 +213,67 @@ func NewFestiveReporter(out io.Writer) FestiveReporter {
 	return FestiveReporter{out: out}
 }
 
+// NewRdJSONReporter creates a new RdJSONReporter for the rdjson format.
+func NewRdJSONReporter(out io.Writer) RdJSONReporter {
+	return RdJSONReporter{out: out}
+}
+
+// NewRdJSONLReporter creates a new RdJSONReporter for the rdjsonl format.
+func NewRdJSONLReporter(out io.Writer) RdJSONReporter {
+	return RdJSONReporter{out: out, lines: true}
+}
+
+// NewGitLabReporter creates a new GitLabReporter.
+func NewGitLabReporter(out io.Writer) GitLabReporter {
+	return GitLabReporter{out: out}
+}
+
+// NewTAPReporter creates a new TAPReporter.
+func NewTAPReporter(out io.Writer) TAPReporter {
+	return TAPReporter{out: out}
+}
+
+// NewJUnitReporter creates a new JUnitReporter.
+func NewJUnitReporter(out io.Writer) JUnitReporter {
+	return JUnitReporter{out: out}
+}
+
+// NewCheckstyleReporter creates a new CheckstyleReporter.
+func NewCheckstyleReporter(out io.Writer) CheckstyleReporter {
+	return CheckstyleReporter{out: out}
+}
+
+// NewMarkdownReporter creates a new MarkdownReporter.
+func NewMarkdownReporter(out io.Writer) MarkdownReporter {
+	return MarkdownReporter{out: out}
+}
+
+// WithGroupBy sets the MarkdownReporter to report violations in a collapsible section for each rule, category or
+// owner, rather than for each file.
+func (tr MarkdownReporter) WithGroupBy(groupBy string) MarkdownReporter {
+	tr.groupBy = groupBy
+
+	return tr
+}
+
+// NewExecReporter creates a new ExecReporter, running the command, given by its name followed by any arguments.
+func NewExecReporter(out io.Writer, command []string) ExecReporter {
+	return ExecReporter{out: out, command: command}
+}
+
+// NewSnippetReporter creates a new SnippetReporter.
+func NewSnippetReporter(out io.Writer) SnippetReporter {
+	return SnippetReporter{out: out}
+}
+
+// WithGroupBy sets the SnippetReporter to report violations grouped by file, rule, category or owner, with a
+// heading for each group.
+func (tr SnippetReporter) WithGroupBy(groupBy string) SnippetReporter {
+	tr.groupBy = groupBy
+
+	return tr
+}
+
 // NewSarifReporter creates a new SarifReporter.
 func NewSarifReporter(out io.Writer) SarifReporter {
 	return SarifReporter{out: out}
@@ -85,8 +281,138 @@ func NewSarifReporter(out io.Writer) SarifReporter {
 
 // Publish prints a pretty report to the configured output.
 func (tr PrettyReporter) Publish(_ context.Context, r report.Report) error {
-	table := buildPrettyViolationsTable(r.Violations)
+	var table string
+
+	if tr.groupBy == "" {
+		table = buildPrettyViolationsTable(r.Violations)
+	} else {
+		for _, group := range groupViolations(r.Violations, tr.groupBy) {
+			pluralGroup := ""
+			if len(group.violations) > 1 {
+				pluralGroup = "s"
+			}
+
+			table += fmt.Sprintf("%s (%d violation%s):\n\n", group.key, len(group.violations), pluralGroup)
+			table += buildPrettyViolationsTable(group.violations)
+		}
+	}
+
+	_, err := fmt.Fprint(tr.out, table+buildPrettyFooter(r)+"\n")
+
+	return err
+}
+
+// Publish prints the violations of the report with snippets of their source to the configured output.
+func (tr SnippetReporter) Publish(_ context.Context, r report.Report) error {
+	sb := &strings.Builder{}
+
+	if tr.groupBy == "" {
+		for _, violation := range r.Violations {
+			writeViolationSnippet(sb, violation)
+		}
+	} else {
+		for _, group := range groupViolations(r.Violations, tr.groupBy) {
+			pluralGroup := ""
+			if len(group.violations) > 1 {
+				pluralGroup = "s"
+			}
+
+			fmt.Fprintf(sb, "%s (%d violation%s):\n\n", group.key, len(group.violations), pluralGroup)
+
+			for _, violation := range group.violations {
+				writeViolationSnippet(sb, violation)
+			}
+		}
+	}
+
+	_, err := fmt.Fprint(tr.out, sb.String()+buildPrettyFooter(r)+"\n")
+
+	return err
+}
+
+func writeViolationSnippet(sb *strings.Builder, violation report.Violation) {
+	levelColor := color.New(color.FgRed, color.Bold)
+
+	switch violation.Level {
+	case "warning":
+		levelColor = color.New(color.FgYellow, color.Bold)
+	case "notice":
+		levelColor = color.New(color.FgBlue, color.Bold)
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
 
+	snippet := violation.Snippet
+	if snippet == nil && violation.Location.Text != nil && violation.Location.Row > 0 {
+		text, _, _ := strings.Cut(*violation.Location.Text, "\n")
+		snippet = &report.Snippet{Start: violation.Location.Row, Lines: []string{text}}
+	}
+
+	fmt.Fprintf(sb, "%s%s\n",
+		levelColor.Sprintf("%s[%s/%s]", violation.Level, violation.Category, violation.Title),
+		bold(": "+violation.Description),
+	)
+
+	width := 1
+	if snippet != nil {
+		width = len(strconv.Itoa(snippet.Start + len(snippet.Lines) - 1))
+	}
+
+	gutter := strings.Repeat(" ", width)
+
+	fmt.Fprintf(sb, "%s%s %s\n", gutter, blue("-->"), violation.Location.String())
+
+	if snippet != nil {
+		fmt.Fprintf(sb, "%s %s\n", gutter, blue("|"))
+
+		for i, line := range snippet.Lines {
+			row := snippet.Start + i
+
+			fmt.Fprintf(sb, "%s\n", strings.TrimRight(blue(fmt.Sprintf("%*d |", width, row))+" "+line, " "))
+
+			if row == violation.Location.Row && violation.Location.Column > 0 {
+				fmt.Fprintf(sb, "%s %s %s%s\n", gutter, blue("|"), caretIndent(line, violation.Location.Column),
+					levelColor.Sprint("^"))
+			}
+		}
+
+		fmt.Fprintf(sb, "%s %s\n", gutter, blue("|"))
+	}
+
+	if violation.Change != "" {
+		fmt.Fprintf(sb, "%s %s %s\n", gutter, blue("= change:"), violation.Change)
+	}
+
+	if violation.Blame != nil {
+		fmt.Fprintf(sb, "%s %s %s\n", gutter, blue("= blame:"), violation.Blame.String())
+	}
+
+	if url := getDocumentationURL(violation); url != "" {
+		fmt.Fprintf(sb, "%s %s %s\n", gutter, blue("= see:"), cyan(url))
+	}
+
+	sb.WriteString("\n")
+}
+
+// caretIndent returns the whitespace to print before a caret for it to be shown below the column col of line, which
+// keeps the tabs of line, as their width depends on the terminal.
+func caretIndent(line string, col int) string {
+	prefix := line[:min(col-1, len(line))]
+
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+
+		return ' '
+	}, prefix)
+}
+
+// buildPrettyFooter summarizes the report, for the PrettyReporter and the SnippetReporter, with the number of files
+// linted and violations found, followed by any rules skipped, violation budgets and violations suppressed.
+func buildPrettyFooter(r report.Report) string {
 	pluralScanned := ""
 	if r.Summary.FilesScanned == 0 || r.Summary.FilesScanned > 1 {
 		pluralScanned = "s"
@@ -104,6 +430,11 @@ func (tr PrettyReporter) Publish(_ context.Context, r report.Report) error {
 
 		footer += fmt.Sprintf(" %d violation%s found", r.Summary.NumViolations, pluralViolations)
 
+		// violations are either all classified by change, or none of them are
+		if len(r.Violations) > 0 && r.Violations[0].Change != "" {
+			footer += fmt.Sprintf(" (%d new)", r.Summary.NumNew)
+		}
+
 		if r.Summary.FilesScanned > 1 && r.Summary.FilesFailed > 0 {
 			pluralFailed := ""
 			if r.Summary.FilesFailed > 1 {
@@ -135,9 +466,76 @@ func (tr PrettyReporter) Publish(_ context.Context, r report.Report) error {
 		}
 	}
 
-	_, err := fmt.Fprint(tr.out, table+footer+"\n")
+	if len(r.Budgets) > 0 {
+		footer += buildBudgetsSummary(r.Budgets)
+	}
 
-	return err
+	if len(r.Suppressed) > 0 {
+		footer += buildSuppressedSummary(r)
+	}
+
+	return footer
+}
+
+// buildBudgetsSummary lists the violations of each rule with a budget, along with the number allowed by it.
+func buildBudgetsSummary(budgets []report.Budget) string {
+	sb := &strings.Builder{}
+
+	sb.WriteString("\nViolation budgets:\n")
+
+	for _, budget := range budgets {
+		fmt.Fprintf(sb, "- %s/%s: %d of %d allowed", budget.Category, budget.Title, budget.Violations, budget.MaxViolations)
+
+		if budget.Exceeded() {
+			sb.WriteString(", budget exceeded")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// buildSuppressedSummary lists the suppressed violations of each rule, along with their number.
+func buildSuppressedSummary(r report.Report) string {
+	counts := r.SuppressedByRule()
+
+	titles := make([]string, 0, len(counts))
+	for title := range counts {
+		titles = append(titles, title)
+	}
+
+	slices.Sort(titles)
+
+	pluralSuppressed := ""
+	if len(r.Suppressed) > 1 {
+		pluralSuppressed = "s"
+	}
+
+	suppressed := slices.Clone(r.Suppressed)
+	slices.SortFunc(suppressed, func(a, b report.Violation) int {
+		return cmp.Or(
+			cmp.Compare(a.Location.File, b.Location.File),
+			cmp.Compare(a.Location.Row, b.Location.Row),
+			cmp.Compare(a.Location.Column, b.Location.Column),
+		)
+	})
+
+	sb := &strings.Builder{}
+
+	fmt.Fprintf(sb, "\n%d violation%s suppressed:\n", len(r.Suppressed), pluralSuppressed)
+
+	for _, title := range titles {
+		fmt.Fprintf(sb, "- %s (%d):\n", title, counts[title])
+
+		for _, violation := range suppressed {
+			if violation.Title == title {
+				fmt.Fprintf(sb, "  %s by %s\n", violation.Location.String(), violation.Suppression)
+			}
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
 }
 
 // Publish prints a festive report to the configured output.
@@ -154,6 +552,68 @@ func (tr FestiveReporter) Publish(ctx context.Context, r report.Report) error {
 	return pretty.Publish(ctx, r)
 }
 
+type violationGroup struct {
+	key        string
+	violations []report.Violation
+}
+
+// groupViolations groups violations by file, rule, category or owner, with groups sorted by key, and the violations
+// of each group sorted by location.
+func groupViolations(violations []report.Violation, groupBy string) []violationGroup {
+	keysOf := func(violation report.Violation) []string {
+		switch groupBy {
+		case GroupByRule:
+			return []string{violation.Title}
+		case GroupByCategory:
+			return []string{violation.Category}
+		case GroupByTarget:
+			return []string{violation.Target}
+		case GroupByOwner:
+			if len(violation.Owners) == 0 {
+				return []string{Unowned}
+			}
+
+			return violation.Owners
+		default:
+			return []string{violation.Location.File}
+		}
+	}
+
+	type keyed struct {
+		key       string
+		violation report.Violation
+	}
+
+	sorted := make([]keyed, 0, len(violations))
+
+	for _, violation := range violations {
+		for _, key := range keysOf(violation) {
+			sorted = append(sorted, keyed{key: key, violation: violation})
+		}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b keyed) int {
+		return cmp.Or(
+			cmp.Compare(a.key, b.key),
+			cmp.Compare(a.violation.Location.File, b.violation.Location.File),
+			cmp.Compare(a.violation.Location.Row, b.violation.Location.Row),
+			cmp.Compare(a.violation.Location.Column, b.violation.Location.Column),
+		)
+	})
+
+	var groups []violationGroup
+
+	for _, kv := range sorted {
+		if len(groups) == 0 || groups[len(groups)-1].key != kv.key {
+			groups = append(groups, violationGroup{key: kv.key})
+		}
+
+		groups[len(groups)-1].violations = append(groups[len(groups)-1].violations, kv.violation)
+	}
+
+	return groups
+}
+
 func buildPrettyViolationsTable(violations []report.Violation) string {
 	sb := &strings.Builder{}
 	table := tablewriter.NewWriter(sb)
@@ -176,11 +636,16 @@ func buildPrettyViolationsTable(violations []report.Violation) string {
 	yellow := color.New(color.FgYellow).SprintFunc()
 	cyan := color.New(color.FgCyan).SprintFunc()
 	red := color.New(color.FgRed).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
 
 	for i, violation := range violations {
 		description := red(violation.Description)
-		if violation.Level == "warning" {
+
+		switch violation.Level {
+		case "warning":
 			description = yellow(violation.Description)
+		case "notice":
+			description = blue(violation.Description)
 		}
 
 		table.Append([]string{yellow("Rule:"), violation.Title})
@@ -188,6 +653,14 @@ func buildPrettyViolationsTable(violations []report.Violation) string {
 		table.Append([]string{yellow("Category:"), violation.Category})
 		table.Append([]string{yellow("Location:"), cyan(violation.Location.String())})
 
+		if violation.Change != "" {
+			table.Append([]string{yellow("Change:"), violation.Change})
+		}
+
+		if violation.Blame != nil {
+			table.Append([]string{yellow("Blame:"), violation.Blame.String()})
+		}
+
 		if violation.Location.Text != nil {
 			table.Append([]string{yellow("Text:"), strings.TrimSpace(*violation.Location.Text)})
 		}
@@ -220,14 +693,26 @@ func (tr CompactReporter) Publish(_ context.Context, r report.Report) error {
 	sb := &strings.Builder{}
 	table := tablewriter.NewWriter(sb)
 
-	table.SetHeader([]string{"Location", "Description"})
 	table.SetAutoFormatHeaders(false)
 
 	table.SetColWidth(80)
 	table.SetAutoWrapText(true)
 
-	for _, violation := range r.Violations {
-		table.Append([]string{violation.Location.String(), violation.Description})
+	if tr.groupBy == "" {
+		table.SetHeader([]string{"Location", "Description"})
+
+		for _, violation := range r.Violations {
+			table.Append([]string{violation.Location.String(), violation.Description})
+		}
+	} else {
+		table.SetHeader([]string{strings.ToUpper(tr.groupBy[:1]) + tr.groupBy[1:], "Location", "Description"})
+		table.SetAutoMergeCellsByColumnIndex([]int{0})
+
+		for _, group := range groupViolations(r.Violations, tr.groupBy) {
+			for _, violation := range group.violations {
+				table.Append([]string{group.key, violation.Location.String(), violation.Description})
+			}
+		}
 	}
 
 	table.Render()
@@ -243,7 +728,17 @@ func (tr JSONReporter) Publish(_ context.Context, r report.Report) error {
 		r.Violations = []report.Violation{}
 	}
 
-	bs, err := json.MarshalIndent(r, "", "  ")
+	jr := jsonReport{Version: JSONReportVersion, Report: r}
+
+	if tr.groupBy != "" {
+		jr.Groups = make([]jsonGroup, 0)
+
+		for _, group := range groupViolations(r.Violations, tr.groupBy) {
+			jr.Groups = append(jr.Groups, jsonGroup{Key: group.key, Violations: group.violations})
+		}
+	}
+
+	bs, err := json.MarshalIndent(jr, "", "  ")
 	if err != nil {
 		return fmt.Errorf("json marshalling of report failed: %w", err)
 	}
@@ -340,20 +835,56 @@ func (tr SarifReporter) Publish(_ context.Context, r report.Report) error {
 
 	run := sarif.NewRunWithInformationURI("Regal", "https://docs.styra.com/regal")
 
+	if version.Version != "" {
+		run.Tool.Driver.WithVersion(version.Version)
+	}
+
 	for _, violation := range r.Violations {
-		pb := sarif.NewPropertyBag()
-		pb.Add("category", violation.Category)
+		addSarifRule(run, violation)
 
-		run.AddRule(violation.Title).
-			WithDescription(violation.Description).
-			WithHelpURI(getDocumentationURL(violation)).
-			WithProperties(pb.Properties)
+		run.AddDistinctArtifact(violation.Location.File)
+
+		result := run.CreateResultForRule(violation.Title).
+			WithLevel(getSarifLevel(violation.Level)).
+			WithMessage(sarif.NewTextMessage(violation.Description))
+
+		switch violation.Change {
+		case report.ChangeNew:
+			result.WithBaselineState("new")
+		case report.ChangePreExisting:
+			result.WithBaselineState("unchanged")
+		}
+
+		result.AddLocation(getLocation(violation))
+	}
+
+	for _, violation := range r.Suppressed {
+		addSarifRule(run, violation)
 
 		run.AddDistinctArtifact(violation.Location.File)
 
+		// directives in the policy are suppressions in source, while the configuration and baseline are external to it
+		var suppression *sarif.Suppression
+
+		switch violation.Suppression {
+		case report.SuppressedByDirective:
+			suppression = sarif.NewSuppression("inSource").
+				WithStatus("accepted").
+				WithJustifcation("regal ignore directive")
+		case report.SuppressedByBaseline:
+			suppression = sarif.NewSuppression("external").
+				WithStatus("accepted").
+				WithJustifcation("violation recorded in baseline")
+		default:
+			suppression = sarif.NewSuppression("external").
+				WithStatus("accepted").
+				WithJustifcation("file ignored for rule in configuration")
+		}
+
 		run.CreateResultForRule(violation.Title).
-			WithLevel(violation.Level).
+			WithLevel(getSarifLevel(violation.Level)).
 			WithMessage(sarif.NewTextMessage(violation.Description)).
+			WithSuppression([]*sarif.Suppression{suppression}).
 			AddLocation(getLocation(violation))
 	}
 
@@ -382,6 +913,604 @@ func (tr SarifReporter) Publish(_ context.Context, r report.Report) error {
 	return rep.PrettyWrite(tr.out)
 }
 
+type rdSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+type rdRange struct {
+	Start rdPosition  `json:"start"`
+	End   *rdPosition `json:"end,omitempty"`
+}
+
+type rdSuggestion struct {
+	Range rdRange `json:"range"`
+	Text  string  `json:"text"`
+}
+
+type rdLocation struct {
+	Path  string   `json:"path"`
+	Range *rdRange `json:"range,omitempty"`
+}
+
+type rdCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type rdDiagnostic struct {
+	Message     string         `json:"message"`
+	Location    rdLocation     `json:"location"`
+	Severity    string         `json:"severity"`
+	Source      rdSource       `json:"source"`
+	Code        rdCode         `json:"code"`
+	Suggestions []rdSuggestion `json:"suggestions,omitempty"`
+}
+
+type rdDiagnosticResult struct {
+	Source      rdSource       `json:"source"`
+	Diagnostics []rdDiagnostic `json:"diagnostics"`
+}
+
+//nolint:gochecknoglobals
+var rdRegalSource = rdSource{Name: "regal", URL: "https://github.com/StyraInc/regal"}
+
+// Publish prints the violations of a report as Reviewdog diagnostics to the configured output. Notices are left
+// out, as they aren't tied to any location in the linted files, which Reviewdog requires. Violations of rules with
+// automatic fixes get the fix as a suggestion, for Reviewdog to propose as a change in review comments.
+func (tr RdJSONReporter) Publish(ctx context.Context, r report.Report) error {
+	diagnostics := make([]rdDiagnostic, 0, len(r.Violations))
+	contents := make(map[string][]byte)
+
+	for _, violation := range r.Violations {
+		diagnostic := getRdDiagnostic(violation)
+
+		suggestion, err := getRdSuggestion(ctx, violation, contents)
+		if err != nil {
+			return err
+		}
+
+		if suggestion != nil {
+			diagnostic.Suggestions = []rdSuggestion{*suggestion}
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	if tr.lines {
+		encoder := json.NewEncoder(tr.out)
+
+		for _, diagnostic := range diagnostics {
+			if err := encoder.Encode(diagnostic); err != nil {
+				return fmt.Errorf("json marshalling of diagnostic failed: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	bs, err := json.MarshalIndent(rdDiagnosticResult{Source: rdRegalSource, Diagnostics: diagnostics}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshalling of report failed: %w", err)
+	}
+
+	_, err = fmt.Fprintln(tr.out, string(bs))
+
+	return err
+}
+
+func getRdDiagnostic(violation report.Violation) rdDiagnostic {
+	location := rdLocation{Path: violation.Location.File}
+
+	if violation.Location.Row > 0 {
+		location.Range = &rdRange{Start: rdPosition{Line: violation.Location.Row, Column: violation.Location.Column}}
+	}
+
+	severity := "INFO"
+
+	switch violation.Level {
+	case "error":
+		severity = "ERROR"
+	case "warning":
+		severity = "WARNING"
+	}
+
+	return rdDiagnostic{
+		Message:  violation.Description,
+		Location: location,
+		Severity: severity,
+		Source:   rdRegalSource,
+		Code:     rdCode{Value: violation.Title, URL: getDocumentationURL(violation)},
+	}
+}
+
+// getRdSuggestion returns the fix of a violation as a suggestion replacing the lines changed by the fix, or nil if
+// the rule violated has no automatic fix, or the file violating it can't be read, like when linting from stdin.
+// Files are read once, and kept in contents for the suggestions of any further violations in the same file.
+func getRdSuggestion(
+	ctx context.Context,
+	violation report.Violation,
+	contents map[string][]byte,
+) (*rdSuggestion, error) {
+	file := violation.Location.File
+	if file == "" || violation.Location.Row == 0 {
+		return nil, nil
+	}
+
+	before, ok := contents[file]
+	if !ok {
+		// a file which can't be read is stored as nil, to not try reading it again
+		before, _ = os.ReadFile(file)
+		contents[file] = before
+	}
+
+	if before == nil {
+		return nil, nil
+	}
+
+	candidate := fixes.FixCandidate{Filename: file, Contents: before}
+
+	after, fixed, err := fixer.Fix(ctx, candidate, []report.Violation{violation})
+	if err != nil {
+		var regoV1Err *fixes.RegoV1Error
+		if errors.As(err, &regoV1Err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to fix %s violation in %s: %w", violation.Title, file, err)
+	}
+
+	if len(fixed) == 0 {
+		return nil, nil
+	}
+
+	oldLines := strings.SplitAfter(string(before), "\n")
+	newLines := strings.SplitAfter(string(after), "\n")
+
+	// the lines changed are those between the lines before and after the fix have in common at start and end
+	start := 0
+	for start < len(oldLines) && start < len(newLines) && oldLines[start] == newLines[start] {
+		start++
+	}
+
+	end := 0
+	for end < len(oldLines)-start && end < len(newLines)-start &&
+		oldLines[len(oldLines)-1-end] == newLines[len(newLines)-1-end] {
+		end++
+	}
+
+	return &rdSuggestion{
+		Range: rdRange{
+			Start: rdPosition{Line: start + 1, Column: 1},
+			End:   &rdPosition{Line: len(oldLines) - end + 1, Column: 1},
+		},
+		Text: strings.Join(newLines[start:len(newLines)-end], ""),
+	}, nil
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	EngineName  string              `json:"engine_name"`
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Categories  []string            `json:"categories"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+// codeClimateCategories maps the categories of Regal to those of Code Climate, with any other category, like those
+// of custom rules, mapped to Style.
+var codeClimateCategories = map[string]string{ //nolint:gochecknoglobals
+	"bugs":        "Bug Risk",
+	"performance": "Performance",
+	"security":    "Security",
+}
+
+// Publish prints a GitLab Code Quality report to the configured output.
+func (tr GitLabReporter) Publish(_ context.Context, r report.Report) error {
+	issues := make([]codeClimateIssue, 0, len(r.Violations))
+
+	// fingerprints identify violations in GitLab, which uses them to tell which issues were introduced or resolved
+	// by a merge request
+	fingerprints := report.Fingerprints(r.Violations)
+
+	for i, violation := range r.Violations {
+		severity := "info"
+
+		switch violation.Level {
+		case "error":
+			severity = "major"
+		case "warning":
+			severity = "minor"
+		}
+
+		issues = append(issues, codeClimateIssue{
+			Type:        "issue",
+			EngineName:  "regal",
+			Description: violation.Description,
+			CheckName:   violation.Title,
+			Categories:  []string{cmp.Or(codeClimateCategories[violation.Category], "Style")},
+			Fingerprint: fingerprints[i],
+			Severity:    severity,
+			Location: codeClimateLocation{
+				Path:  codeClimatePath(violation.Location.File),
+				Lines: codeClimateLines{Begin: max(violation.Location.Row, 1)},
+			},
+		})
+	}
+
+	bs, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshalling of report failed: %w", err)
+	}
+
+	_, err = fmt.Fprintln(tr.out, string(bs))
+
+	return err
+}
+
+// codeClimatePath returns the path of the file relative to the working directory, as GitLab expects paths relative
+// to the root of the repository, and won't show violations of files linted by their absolute path otherwise.
+func codeClimatePath(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+type tapViolation struct {
+	Rule     string `yaml:"rule"`
+	Category string `yaml:"category"`
+	Level    string `yaml:"level"`
+	Message  string `yaml:"message"`
+	Line     int    `yaml:"line,omitempty"`
+	Column   int    `yaml:"column,omitempty"`
+	URL      string `yaml:"url,omitempty"`
+}
+
+// Publish prints a TAP report to the configured output. Each file linted is a test point, which fails if any
+// violations other than notices were found in the file, with the violations provided in a YAML diagnostic block.
+func (tr TAPReporter) Publish(_ context.Context, r report.Report) error {
+	files := slices.Clone(r.Files)
+	violations := make(map[string][]tapViolation)
+
+	for _, violation := range r.Violations {
+		if !slices.Contains(files, violation.Location.File) {
+			files = append(files, violation.Location.File)
+		}
+
+		violations[violation.Location.File] = append(violations[violation.Location.File], tapViolation{
+			Rule:     violation.Title,
+			Category: violation.Category,
+			Level:    violation.Level,
+			Message:  violation.Description,
+			Line:     violation.Location.Row,
+			Column:   violation.Location.Column,
+			URL:      getDocumentationURL(violation),
+		})
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("TAP version 14\n")
+	fmt.Fprintf(&sb, "1..%d\n", len(files))
+
+	for i, file := range files {
+		description := tapEscape(file)
+		if file == "" {
+			// violations of aggregate rules concerning the workspace as a whole, rather than any one file
+			description = "(workspace)"
+		}
+
+		if len(violations[file]) == 0 {
+			fmt.Fprintf(&sb, "ok %d - %s\n", i+1, description)
+
+			continue
+		}
+
+		// notices are informational, and don't fail the test point, but are still listed in its diagnostic
+		status := "ok"
+
+		for _, violation := range violations[file] {
+			if violation.Level != "notice" {
+				status = "not ok"
+			}
+		}
+
+		fmt.Fprintf(&sb, "%s %d - %s\n", status, i+1, description)
+
+		var diagnostic strings.Builder
+
+		encoder := yaml.NewEncoder(&diagnostic)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(map[string][]tapViolation{"violations": violations[file]}); err != nil {
+			return fmt.Errorf("yaml marshalling of violations failed: %w", err)
+		}
+
+		sb.WriteString("  ---\n")
+
+		for _, line := range strings.Split(strings.TrimSuffix(diagnostic.String(), "\n"), "\n") {
+			sb.WriteString("  " + line + "\n")
+		}
+
+		sb.WriteString("  ...\n")
+	}
+
+	_, err := io.WriteString(tr.out, sb.String())
+
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Publish prints a JUnit XML report to the configured output. Each file linted is a test suite, with a test case for
+// each rule violated in the file, failing with all the violations of the rule, or a single passing test case if no
+// violations were found in the file. Notices are informational, and reported as output of passing test cases.
+func (tr JUnitReporter) Publish(_ context.Context, r report.Report) error {
+	files := slices.Clone(r.Files)
+	violations := make(map[string]map[string][]report.Violation)
+
+	for _, violation := range r.Violations {
+		file := violation.Location.File
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+
+		if violations[file] == nil {
+			violations[file] = make(map[string][]report.Violation)
+		}
+
+		rule := violation.Category + "/" + violation.Title
+		violations[file][rule] = append(violations[file][rule], violation)
+	}
+
+	suites := junitTestSuites{Name: "regal", Suites: make([]junitTestSuite, 0, len(files))}
+
+	for _, file := range files {
+		name := file
+		if file == "" {
+			// violations of aggregate rules concerning the workspace as a whole, rather than any one file
+			name = "(workspace)"
+		}
+
+		suite := junitTestSuite{Name: name}
+
+		if len(violations[file]) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{Name: name, ClassName: name})
+		}
+
+		rules := util.Keys(violations[file])
+		slices.Sort(rules)
+
+		for _, rule := range rules {
+			suite.Cases = append(suite.Cases, junitTestCaseFor(name, rule, violations[file][rule]))
+		}
+
+		for _, testCase := range suite.Cases {
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+		}
+
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	bs, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("xml marshalling of report failed: %w", err)
+	}
+
+	_, err = fmt.Fprintf(tr.out, "%s%s\n", xml.Header, bs)
+
+	return err
+}
+
+// junitTestCaseFor returns the test case for the violations of a rule in a file, failing unless all of the
+// violations are notices.
+func junitTestCaseFor(file, rule string, violations []report.Violation) junitTestCase {
+	var details strings.Builder
+
+	failing := false
+
+	for _, violation := range violations {
+		if violation.Level != "notice" {
+			failing = true
+		}
+
+		fmt.Fprintf(&details, "%s:%d:%d: %s\n", file, violation.Location.Row, violation.Location.Column,
+			violation.Description)
+
+		if violation.Location.Text != nil {
+			fmt.Fprintf(&details, "\t%s\n", strings.TrimSpace(*violation.Location.Text))
+		}
+
+		if url := getDocumentationURL(violation); url != "" {
+			fmt.Fprintf(&details, "\tDocumentation: %s\n", url)
+		}
+	}
+
+	testCase := junitTestCase{Name: rule, ClassName: file}
+
+	if !failing {
+		testCase.SystemOut = details.String()
+
+		return testCase
+	}
+
+	testCase.Failure = &junitFailure{
+		Message: violations[0].Description,
+		Type:    violations[0].Level,
+		Text:    details.String(),
+	}
+
+	return testCase
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Publish prints a Checkstyle XML report to the configured output. Each file linted is listed, including those
+// without violations, with the source of each error being the rule violated, like regal.rules.style.line-length.
+// Notices are reported with the info severity.
+func (tr CheckstyleReporter) Publish(_ context.Context, r report.Report) error {
+	files := slices.Clone(r.Files)
+	errs := make(map[string][]checkstyleError)
+
+	for _, violation := range r.Violations {
+		file := violation.Location.File
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+
+		errs[file] = append(errs[file], checkstyleError{
+			Line:     violation.Location.Row,
+			Column:   violation.Location.Column,
+			Severity: checkstyleSeverity(violation.Level),
+			Message:  violation.Description,
+			Source:   "regal.rules." + violation.Category + "." + violation.Title,
+		})
+	}
+
+	checkstyle := checkstyleReport{Version: "4.3", Files: make([]checkstyleFile, 0, len(files))}
+
+	for _, file := range files {
+		name := file
+		if file == "" {
+			// violations of aggregate rules concerning the workspace as a whole, rather than any one file
+			name = "(workspace)"
+		}
+
+		checkstyle.Files = append(checkstyle.Files, checkstyleFile{Name: name, Errors: errs[file]})
+	}
+
+	bs, err := xml.MarshalIndent(checkstyle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("xml marshalling of report failed: %w", err)
+	}
+
+	_, err = fmt.Fprintf(tr.out, "%s%s\n", xml.Header, bs)
+
+	return err
+}
+
+// checkstyleSeverity returns the Checkstyle severity corresponding to the level of a violation.
+func checkstyleSeverity(level string) string {
+	if level == "notice" {
+		return "info"
+	}
+
+	return level
+}
+
+// tapEscape escapes the characters with special meaning in the description of a TAP test point.
+func tapEscape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "#", "\\#").Replace(s)
+}
+
+// addSarifRule adds the rule of the violation to the run, unless already added, with the level configured for
+// the rule as its default level, and the category of the rule as a tag, for platforms like GitHub code scanning to
+// filter results by.
+func addSarifRule(run *sarif.Run, violation report.Violation) {
+	pb := sarif.NewPropertyBag()
+	pb.Add("category", violation.Category)
+	pb.Add("tags", []string{violation.Category})
+
+	rule := run.AddRule(violation.Title).
+		WithName(violation.Title).
+		WithDescription(violation.Description).
+		WithProperties(pb.Properties)
+
+	// an empty helpUri is not a valid URI, and would fail validation of the log
+	if url := getDocumentationURL(violation); url != "" {
+		rule.WithHelpURI(url)
+	}
+
+	if rule.DefaultConfiguration == nil {
+		rule.WithDefaultConfiguration(sarif.NewReportingConfiguration().WithLevel(getSarifLevel(violation.Level)))
+	}
+}
+
+// getSarifLevel returns the SARIF level of a violation, where notices are notes.
+func getSarifLevel(level string) string {
+	if level == "notice" {
+		return "note"
+	}
+
+	return level
+}
+
 func getLocation(violation report.Violation) *sarif.Location {
 	physicalLocation := sarif.NewPhysicalLocation().
 		WithArtifactLocation(
@@ -417,3 +1546,126 @@ func getUniqueViolationURLs(violations []report.Violation) map[string]string {
 
 	return urls
 }
+
+// Publish prints a Markdown report to the configured output, starting with a summary of the number of violations
+// of each rule, followed by a collapsible section for each file, or group, listing its violations.
+func (tr MarkdownReporter) Publish(_ context.Context, r report.Report) error {
+	var sb strings.Builder
+
+	sb.WriteString("### Regal Lint Report\n\n")
+
+	pluralScanned := ""
+	if r.Summary.FilesScanned == 0 || r.Summary.FilesScanned > 1 {
+		pluralScanned = "s"
+	}
+
+	fmt.Fprintf(&sb, "%d file%s linted.", r.Summary.FilesScanned, pluralScanned)
+
+	if len(r.Violations) == 0 {
+		sb.WriteString(" No violations found.\n")
+
+		_, err := io.WriteString(tr.out, sb.String())
+
+		return err
+	}
+
+	pluralViolations := ""
+	if len(r.Violations) > 1 {
+		pluralViolations = "s"
+	}
+
+	fmt.Fprintf(&sb, " %d violation%s found", len(r.Violations), pluralViolations)
+
+	if r.Summary.FilesFailed > 0 {
+		pluralFailed := ""
+		if r.Summary.FilesFailed > 1 {
+			pluralFailed = "s"
+		}
+
+		fmt.Fprintf(&sb, " in %d file%s", r.Summary.FilesFailed, pluralFailed)
+	}
+
+	sb.WriteString(".\n\n| Rule | Category | Violations |\n| --- | --- | --- |\n")
+
+	for _, group := range groupViolations(r.Violations, GroupByRule) {
+		violation := group.violations[0]
+
+		fmt.Fprintf(&sb, "| %s | %s | %d |\n",
+			markdownRuleLink(violation), markdownEscape(violation.Category), len(group.violations))
+	}
+
+	groupBy := cmp.Or(tr.groupBy, GroupByFile)
+
+	for _, group := range groupViolations(r.Violations, groupBy) {
+		key := group.key
+		if key == "" && groupBy == GroupByFile {
+			// violations of aggregate rules concerning the workspace as a whole, rather than any one file
+			key = "(workspace)"
+		}
+
+		pluralGroup := ""
+		if len(group.violations) > 1 {
+			pluralGroup = "s"
+		}
+
+		fmt.Fprintf(&sb, "\n<details>\n<summary><code>%s</code> (%d violation%s)</summary>\n\n",
+			html.EscapeString(key), len(group.violations), pluralGroup)
+
+		sb.WriteString("| Location | Level | Rule | Description |\n| --- | --- | --- | --- |\n")
+
+		for _, violation := range group.violations {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n",
+				strings.ReplaceAll(violation.Location.String(), "|", "\\|"),
+				violation.Level,
+				markdownRuleLink(violation),
+				markdownEscape(violation.Description),
+			)
+		}
+
+		sb.WriteString("\n</details>\n")
+	}
+
+	_, err := io.WriteString(tr.out, sb.String())
+
+	return err
+}
+
+// markdownRuleLink returns the title of the rule of the violation, linked to its documentation when available.
+func markdownRuleLink(violation report.Violation) string {
+	if url := getDocumentationURL(violation); url != "" {
+		return fmt.Sprintf("[%s](%s)", markdownEscape(violation.Title), url)
+	}
+
+	return markdownEscape(violation.Title)
+}
+
+// markdownEscape escapes the characters breaking the cells of Markdown tables, or read as HTML. Other Markdown, like
+// the code spans of rule descriptions, is left to be rendered.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Publish runs the command of the reporter, with the report in the JSON format provided on its standard input.
+// Anything written by the command to standard error is passed on, and a non-zero exit code results in an error.
+func (tr ExecReporter) Publish(ctx context.Context, r report.Report) error {
+	if len(tr.command) == 0 {
+		return errors.New("no reporter command provided")
+	}
+
+	var input bytes.Buffer
+
+	if err := NewJSONReporter(&input).Publish(ctx, r); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, tr.command[0], tr.command[1:]...) //nolint:gosec
+	cmd.Stdin = &input
+	cmd.Stdout = tr.out
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run reporter command %s: %w", tr.command[0], err)
+	}
+
+	return nil
+}
@@ -3,8 +3,17 @@ package reporter
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/rego"
 
 	"github.com/styrainc/regal/pkg/report"
 )
@@ -117,6 +126,70 @@ Documentation:	https://example.com/questionable
 	}
 }
 
+func TestPrettyReporterPublishSuppressed(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	suppressed := report.Report{
+		Summary: report.Summary{FilesScanned: 1, NumSuppressed: 3},
+		Suppressed: []report.Violation{
+			{Title: "line-length", Location: report.Location{File: "a.rego", Row: 9, Column: 81}, Suppression: "config"},
+			{
+				Title:       "prefer-snake-case",
+				Location:    report.Location{File: "a.rego", Row: 3, Column: 1},
+				Suppression: "ignore-directive",
+			},
+			{Title: "line-length", Location: report.Location{File: "a.rego", Row: 2, Column: 81}, Suppression: "config"},
+		},
+	}
+
+	if err := NewPrettyReporter(&buf).Publish(context.Background(), suppressed); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `1 file linted. No violations found.
+3 violations suppressed:
+- line-length (2):
+  a.rego:2:81 by config
+  a.rego:9:81 by config
+- prefer-snake-case (1):
+  a.rego:3:1 by ignore-directive
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestPrettyReporterPublishBudgets(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	budgeted := report.Report{
+		Summary: report.Summary{FilesScanned: 1},
+		Budgets: []report.Budget{
+			{Category: "style", Title: "line-length", MaxViolations: 50, Violations: 42},
+			{Category: "style", Title: "opa-fmt", MaxViolations: 0, Violations: 1},
+		},
+	}
+
+	if err := NewPrettyReporter(&buf).Publish(context.Background(), budgeted); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `1 file linted. No violations found.
+Violation budgets:
+- style/line-length: 42 of 50 allowed
+- style/opa-fmt: 1 of 0 allowed, budget exceeded
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
 func TestPrettyReporterPublishNoViolations(t *testing.T) {
 	t.Parallel()
 
@@ -134,6 +207,122 @@ func TestPrettyReporterPublishNoViolations(t *testing.T) {
 	}
 }
 
+func TestSnippetReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := report.Report{
+		Summary: report.Summary{FilesScanned: 2, NumViolations: 3, FilesFailed: 2},
+		Violations: []report.Violation{
+			{
+				Title:       "line-length",
+				Description: "Line too long",
+				Category:    "style",
+				Level:       "warning",
+				Location:    report.Location{File: "a.rego", Row: 10, Column: 5, Text: ptr("\tallow := true")},
+				Snippet:     &report.Snippet{Start: 9, Lines: []string{"rule if {", "\tallow := true", ""}},
+				RelatedResources: []report.RelatedResource{
+					{Description: "documentation", Reference: "https://example.com/line-length"},
+				},
+			},
+			// without snippet, the text of the location is shown
+			rep.Violations[0],
+			// without text, only the location is shown
+			{
+				Title:       "file-length",
+				Description: "Max file length exceeded",
+				Category:    "style",
+				Level:       "error",
+				Location:    report.Location{File: "b.rego"},
+			},
+		},
+	}
+
+	if err := NewSnippetReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `warning[style/line-length]: Line too long
+  --> a.rego:10:5
+   |
+ 9 | rule if {
+10 | 	allow := true
+   | 	   ^
+11 |
+   |
+   = see: https://example.com/line-length
+
+error[legal/breaking-the-law]: Rego must not break the law!
+ --> a.rego:1:1
+  |
+1 | package illegal
+  | ^
+  |
+  = see: https://example.com/illegal
+
+error[style/file-length]: Max file length exceeded
+ --> b.rego
+
+2 files linted. 3 violations found in 2 files.
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+func TestSnippetReporterPublishGroupBy(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := report.Report{
+		Summary: report.Summary{FilesScanned: 2, NumViolations: 2, FilesFailed: 2, NumNew: 1},
+		Violations: []report.Violation{
+			{
+				Title:       "file-length",
+				Description: "Max file length exceeded",
+				Category:    "style",
+				Level:       "error",
+				Location:    report.Location{File: "b.rego"},
+				Change:      report.ChangeNew,
+			},
+			{
+				Title:       "file-length",
+				Description: "Max file length exceeded",
+				Category:    "style",
+				Level:       "error",
+				Location:    report.Location{File: "a.rego"},
+				Change:      report.ChangePreExisting,
+			},
+		},
+	}
+
+	if err := NewSnippetReporter(&buf).WithGroupBy(GroupByFile).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `a.rego (1 violation):
+
+error[style/file-length]: Max file length exceeded
+ --> a.rego
+  = change: pre-existing
+
+b.rego (1 violation):
+
+error[style/file-length]: Max file length exceeded
+ --> b.rego
+  = change: new
+
+2 files linted. 2 violations found (1 new) in 2 files.
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
 func TestCompactReporterPublish(t *testing.T) {
 	t.Parallel()
 
@@ -177,6 +366,122 @@ func TestCompactReporterPublishNoViolations(t *testing.T) {
 	}
 }
 
+func TestPrettyReporterPublishGroupByRule(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	grouped := report.Report{
+		Summary: report.Summary{FilesScanned: 2, NumViolations: 3, FilesFailed: 2},
+		Violations: []report.Violation{
+			{
+				Title: "line-length", Description: "Line too long", Category: "style", Level: "error",
+				Location: report.Location{File: "b.rego", Row: 3, Column: 81},
+			},
+			{
+				Title: "opa-fmt", Description: "File should be formatted", Category: "style", Level: "error",
+				Location: report.Location{File: "a.rego", Row: 1, Column: 1},
+			},
+			{
+				Title: "line-length", Description: "Line too long", Category: "style", Level: "error",
+				Location: report.Location{File: "a.rego", Row: 7, Column: 81},
+			},
+		},
+	}
+
+	if err := NewPrettyReporter(&buf).WithGroupBy(GroupByRule).Publish(context.Background(), grouped); err != nil {
+		t.Fatal(err)
+	}
+
+	var headings, locations []string
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasSuffix(line, "):") {
+			headings = append(headings, line)
+		}
+
+		if location, ok := strings.CutPrefix(line, "Location:     \t"); ok {
+			locations = append(locations, strings.TrimRight(location, " \t"))
+		}
+	}
+
+	if exp := []string{"line-length (2 violations):", "opa-fmt (1 violation):"}; !slices.Equal(headings, exp) {
+		t.Errorf("expected headings %v, got %v", exp, headings)
+	}
+
+	if exp := []string{"a.rego:7:81", "b.rego:3:81", "a.rego:1:1"}; !slices.Equal(locations, exp) {
+		t.Errorf("expected locations %v, got %v", exp, locations)
+	}
+}
+
+func TestCompactReporterPublishGroupByCategory(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := NewCompactReporter(&buf).WithGroupBy(GroupByCategory).Publish(context.Background(), rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `+----------+--------------+------------------------------+
+| Category |   Location   |         Description          |
++----------+--------------+------------------------------+
+| legal    | a.rego:1:1   | Rego must not break the law! |
+| really?  | b.rego:22:18 | Questionable decision found  |
++----------+--------------+------------------------------+
+
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestJSONReporterPublishGroupByOwner(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	owned := report.Report{
+		Violations: []report.Violation{
+			{Title: "opa-fmt", Location: report.Location{File: "a.rego", Row: 1}, Owners: []string{"@a", "@b"}},
+			{Title: "opa-fmt", Location: report.Location{File: "b.rego", Row: 1}, Owners: []string{"@b"}},
+			{Title: "opa-fmt", Location: report.Location{File: "c.rego", Row: 1}},
+		},
+	}
+
+	if err := NewJSONReporter(&buf).WithGroupBy(GroupByOwner).Publish(context.Background(), owned); err != nil {
+		t.Fatal(err)
+	}
+
+	var published struct {
+		Groups []struct {
+			Key        string             `json:"key"`
+			Violations []report.Violation `json:"violations"`
+		} `json:"groups"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &published); err != nil {
+		t.Fatal(err)
+	}
+
+	groups := make([]string, 0, len(published.Groups))
+
+	for _, group := range published.Groups {
+		files := make([]string, 0, len(group.Violations))
+		for _, violation := range group.Violations {
+			files = append(files, violation.Location.File)
+		}
+
+		groups = append(groups, group.Key+": "+strings.Join(files, ", "))
+	}
+
+	if exp := []string{"(unowned): c.rego", "@a: a.rego", "@b: a.rego, b.rego"}; !slices.Equal(groups, exp) {
+		t.Errorf("expected groups %v, got %v", exp, groups)
+	}
+}
+
 func TestJSONReporterPublish(t *testing.T) {
 	t.Parallel()
 
@@ -190,6 +495,7 @@ func TestJSONReporterPublish(t *testing.T) {
 	}
 
 	expect := `{
+  "version": 1,
   "violations": [
     {
       "title": "breaking-the-law",
@@ -270,6 +576,7 @@ func TestJSONReporterPublishNoViolations(t *testing.T) {
 	}
 
 	if buf.String() != `{
+  "version": 1,
   "violations": [],
   "summary": {
     "files_scanned": 0,
@@ -284,6 +591,65 @@ func TestJSONReporterPublishNoViolations(t *testing.T) {
 }
 
 //nolint:paralleltest
+func TestJSONReporterPublishMatchesSchema(t *testing.T) {
+	t.Parallel()
+
+	full := rep
+
+	for _, suppression := range []string{
+		report.SuppressedByDirective,
+		report.SuppressedByConfig,
+		report.SuppressedByBaseline,
+	} {
+		full.Suppressed = append(full.Suppressed, report.Violation{
+			Title:       "line-length",
+			Description: "Line too long",
+			Category:    "style",
+			Level:       "error",
+			Location:    report.Location{File: "a.rego", Row: 2, Column: 81},
+			Suppression: suppression,
+		})
+	}
+
+	full.Profile = []report.ProfileEntry{{Location: "a.rego:1", TotalTimeNs: 100, NumEval: 1}}
+	full.Violations = slices.Clone(rep.Violations)
+	full.Violations[0].Owners = []string{"@org/policy"}
+	full.Violations[0].Target = "policy"
+	full.Violations[0].Blame = &report.Blame{
+		Commit:     "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+		Author:     "Alice",
+		AuthorTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+
+	if err := NewJSONReporter(&buf).WithGroupBy(GroupByOwner).Publish(context.Background(), full); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc, schema any
+
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(JSONReportSchema(), &schema); err != nil {
+		t.Fatalf("expected schema to be valid JSON, got %v", err)
+	}
+
+	rs, err := rego.New(
+		rego.Query("[matches, errors] := json.match_schema(input.doc, input.schema)"),
+		rego.Input(map[string]any{"doc": doc, "schema": schema}),
+	).Eval(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rs) != 1 || rs[0].Bindings["matches"] != true {
+		t.Errorf("expected report to match schema, got errors: %v", rs[0].Bindings["errors"])
+	}
+}
+
 func TestGitHubReporterPublish(t *testing.T) {
 	// Can't use t.Parallel() here because t.Setenv() forbids that
 	t.Setenv("GITHUB_STEP_SUMMARY", "")
@@ -356,22 +722,36 @@ func TestSarifReporterPublish(t *testing.T) {
           "rules": [
             {
               "id": "breaking-the-law",
+              "name": "breaking-the-law",
               "shortDescription": {
                 "text": "Rego must not break the law!"
               },
+              "defaultConfiguration": {
+                "level": "error"
+              },
               "helpUri": "https://example.com/illegal",
               "properties": {
-                "category": "legal"
+                "category": "legal",
+                "tags": [
+                  "legal"
+                ]
               }
             },
             {
               "id": "questionable-decision",
+              "name": "questionable-decision",
               "shortDescription": {
                 "text": "Questionable decision found"
               },
+              "defaultConfiguration": {
+                "level": "warning"
+              },
               "helpUri": "https://example.com/questionable",
               "properties": {
-                "category": "really?"
+                "category": "really?",
+                "tags": [
+                  "really?"
+                ]
               }
             },
             {
@@ -505,12 +885,19 @@ func TestSarifReporterViolationWithoutRegion(t *testing.T) {
           "rules": [
             {
               "id": "opa-fmt",
+              "name": "opa-fmt",
               "shortDescription": {
                 "text": "File should be formatted with ` + "`opa fmt`" + `"
               },
+              "defaultConfiguration": {
+                "level": "error"
+              },
               "helpUri": "https://docs.styra.com/regal/rules/style/opa-fmt",
               "properties": {
-                "category": "style"
+                "category": "style",
+                "tags": [
+                  "style"
+                ]
               }
             }
           ]
@@ -585,3 +972,701 @@ func TestSarifReporterPublishNoViolations(t *testing.T) {
 		t.Errorf("expected %s, got %s", expect, buf.String())
 	}
 }
+
+func TestSarifReporterSuppressions(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := report.Report{}
+
+	for _, suppression := range []string{
+		report.SuppressedByDirective,
+		report.SuppressedByConfig,
+		report.SuppressedByBaseline,
+	} {
+		r.Suppressed = append(r.Suppressed, report.Violation{
+			Title:       "line-length",
+			Description: "Line too long",
+			Category:    "style",
+			Level:       "error",
+			Location:    report.Location{File: "a.rego", Row: 5, Column: 1},
+			Suppression: suppression,
+		})
+	}
+
+	if err := NewSarifReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sarif struct {
+		Runs []struct {
+			Results []struct {
+				Suppressions []struct {
+					Kind          string `json:"kind"`
+					Justification string `json:"justification"`
+				} `json:"suppressions"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][2]string{
+		{"inSource", "regal ignore directive"},
+		{"external", "file ignored for rule in configuration"},
+		{"external", "violation recorded in baseline"},
+	}
+
+	results := sarif.Runs[0].Results
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+
+	for i, result := range results {
+		if len(result.Suppressions) != 1 {
+			t.Fatalf("expected 1 suppression for result %d, got %v", i, result.Suppressions)
+		}
+
+		if got := result.Suppressions[0]; got.Kind != expected[i][0] || got.Justification != expected[i][1] {
+			t.Errorf("expected suppression %v for %s, got %+v", expected[i], r.Suppressed[i].Suppression, got)
+		}
+	}
+}
+
+func TestSarifReporterRuleMetadata(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := report.Report{
+		Violations: []report.Violation{{
+			Title:       "todo-comment",
+			Description: "Avoid TODO comments",
+			Category:    "style",
+			Level:       "notice",
+			Location:    report.Location{File: "a.rego", Row: 3, Column: 1},
+		}},
+		Suppressed: []report.Violation{{
+			Title:       "line-length",
+			Description: "Line too long",
+			Category:    "style",
+			Level:       "warning",
+			Location:    report.Location{File: "a.rego", Row: 5, Column: 1},
+			Suppression: report.SuppressedByDirective,
+		}},
+	}
+
+	if err := NewSarifReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sarif struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []map[string]any `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := sarif.Runs[0].Tool.Driver.Rules
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %v", rules)
+	}
+
+	for i, level := range []string{"note", "warning"} {
+		if _, ok := rules[i]["helpUri"]; ok {
+			t.Errorf("expected no helpUri for rule without documentation, got %v", rules[i]["helpUri"])
+		}
+
+		if config, _ := rules[i]["defaultConfiguration"].(map[string]any); config["level"] != level {
+			t.Errorf("expected default level %s for rule %v, got %v", level, rules[i]["id"], config)
+		}
+
+		if props, _ := rules[i]["properties"].(map[string]any); props["category"] != "style" {
+			t.Errorf("expected category of rule %v in properties, got %v", rules[i]["id"], props)
+		}
+	}
+}
+
+func TestRdJSONReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewRdJSONReporter(&buf).Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `{
+  "source": {
+    "name": "regal",
+    "url": "https://github.com/StyraInc/regal"
+  },
+  "diagnostics": [
+    {
+      "message": "Rego must not break the law!",
+      "location": {
+        "path": "a.rego",
+        "range": {
+          "start": {
+            "line": 1,
+            "column": 1
+          }
+        }
+      },
+      "severity": "ERROR",
+      "source": {
+        "name": "regal",
+        "url": "https://github.com/StyraInc/regal"
+      },
+      "code": {
+        "value": "breaking-the-law",
+        "url": "https://example.com/illegal"
+      }
+    },
+    {
+      "message": "Questionable decision found",
+      "location": {
+        "path": "b.rego",
+        "range": {
+          "start": {
+            "line": 22,
+            "column": 18
+          }
+        }
+      },
+      "severity": "WARNING",
+      "source": {
+        "name": "regal",
+        "url": "https://github.com/StyraInc/regal"
+      },
+      "code": {
+        "value": "questionable-decision",
+        "url": "https://example.com/questionable"
+      }
+    }
+  ]
+}
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestRdJSONLReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewRdJSONLReporter(&buf).Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	source := `"source":{"name":"regal","url":"https://github.com/StyraInc/regal"}`
+	expect := `{"message":"Rego must not break the law!","location":{"path":"a.rego","range":{"start":` +
+		`{"line":1,"column":1}}},"severity":"ERROR",` + source +
+		`,"code":{"value":"breaking-the-law","url":"https://example.com/illegal"}}
+{"message":"Questionable decision found","location":{"path":"b.rego","range":{"start":` +
+		`{"line":22,"column":18}}},"severity":"WARNING",` + source +
+		`,"code":{"value":"questionable-decision","url":"https://example.com/questionable"}}
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestRdJSONReporterPublishSuggestions(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "p.rego")
+	contents := "package p\n\nimport data.users as users\nimport data.users as users\n\nallow := users.admin\n"
+
+	if err := os.WriteFile(file, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := report.Report{Violations: []report.Violation{
+		{Title: "redundant-alias", Category: "imports", Location: report.Location{File: file, Row: 3, Column: 8}},
+		{Title: "import-shadows-import", Category: "imports", Location: report.Location{File: file, Row: 4, Column: 8}},
+		{Title: "breaking-the-law", Category: "legal", Location: report.Location{File: file, Row: 6, Column: 1}},
+	}}
+
+	var buf bytes.Buffer
+
+	if err := NewRdJSONReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var result rdDiagnosticResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]rdSuggestion{
+		{{
+			Range: rdRange{Start: rdPosition{Line: 3, Column: 1}, End: &rdPosition{Line: 4, Column: 1}},
+			Text:  "import data.users\n",
+		}},
+		{{
+			Range: rdRange{Start: rdPosition{Line: 4, Column: 1}, End: &rdPosition{Line: 5, Column: 1}},
+			Text:  "",
+		}},
+		nil,
+	}
+
+	for i, diagnostic := range result.Diagnostics {
+		if !reflect.DeepEqual(diagnostic.Suggestions, expected[i]) {
+			t.Errorf("expected suggestions %v for %s, got %v", expected[i], diagnostic.Code.Value, diagnostic.Suggestions)
+		}
+	}
+}
+
+func TestRdJSONReporterPublishNoViolations(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewRdJSONReporter(&buf).Publish(context.Background(), report.Report{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `"diagnostics": []`) {
+		t.Errorf("expected empty diagnostics, got %s", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := NewRdJSONLReporter(&buf).Publish(context.Background(), report.Report{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %s", buf.String())
+	}
+}
+
+func TestGitLabReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewGitLabReporter(&buf).Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	for i, expected := range []map[string]any{
+		{"check_name": "breaking-the-law", "severity": "major", "path": "a.rego", "begin": 1.0},
+		{"check_name": "questionable-decision", "severity": "minor", "path": "b.rego", "begin": 22.0},
+	} {
+		location, _ := issues[i]["location"].(map[string]any)
+		lines, _ := location["lines"].(map[string]any)
+
+		actual := map[string]any{
+			"check_name": issues[i]["check_name"],
+			"severity":   issues[i]["severity"],
+			"path":       location["path"],
+			"begin":      lines["begin"],
+		}
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected issue %v, got %v", expected, actual)
+		}
+
+		if fingerprint, _ := issues[i]["fingerprint"].(string); len(fingerprint) != 64 {
+			t.Errorf("expected sha256 fingerprint, got %q", fingerprint)
+		}
+
+		if issues[i]["type"] != "issue" || issues[i]["engine_name"] != "regal" ||
+			!reflect.DeepEqual(issues[i]["categories"], []any{"Style"}) {
+			t.Errorf("expected issue of regal in Style category, got %v", issues[i])
+		}
+	}
+}
+
+func TestCodeClimatePath(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string]string{
+		"p.rego":                                  "p.rego",
+		filepath.Join(wd, "policy", "p.rego"):     "policy/p.rego",
+		filepath.Join(filepath.Dir(wd), "p.rego"): filepath.Join(filepath.Dir(wd), "p.rego"),
+	} {
+		if actual := codeClimatePath(path); actual != expected {
+			t.Errorf("expected path %s to be reported as %s, got %s", path, expected, actual)
+		}
+	}
+}
+
+func TestTAPReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := rep
+	r.Files = []string{"a.rego", "b.rego", "c.rego"}
+
+	if err := NewTAPReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `TAP version 14
+1..3
+not ok 1 - a.rego
+  ---
+  violations:
+    - rule: breaking-the-law
+      category: legal
+      level: error
+      message: Rego must not break the law!
+      line: 1
+      column: 1
+      url: https://example.com/illegal
+  ...
+not ok 2 - b.rego
+  ---
+  violations:
+    - rule: questionable-decision
+      category: really?
+      level: warning
+      message: Questionable decision found
+      line: 22
+      column: 18
+      url: https://example.com/questionable
+  ...
+ok 3 - c.rego
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestTAPReporterPublishNoViolations(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewTAPReporter(&buf).Publish(context.Background(), report.Report{Files: []string{"#1.rego"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := "TAP version 14\n1..1\nok 1 - \\#1.rego\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestJUnitReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := rep
+	r.Files = []string{"a.rego", "b.rego", "c.rego"}
+	r.Violations = append(slices.Clone(rep.Violations), report.Violation{
+		Title:       "unused-rule",
+		Description: "Rule never referenced",
+		Category:    "style",
+		Location:    report.Location{File: "c.rego", Row: 3, Column: 1},
+		Level:       "notice",
+	})
+
+	if err := NewJUnitReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="regal" tests="3" failures="2">
+  <testsuite name="a.rego" tests="1" failures="1">
+    <testcase name="legal/breaking-the-law" classname="a.rego">
+      <failure message="Rego must not break the law!" type="error">a.rego:1:1: Rego must not break the law!&#xA;&#x9;package illegal&#xA;&#x9;Documentation: https://example.com/illegal&#xA;</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b.rego" tests="1" failures="1">
+    <testcase name="really?/questionable-decision" classname="b.rego">
+      <failure message="Questionable decision found" type="warning">b.rego:22:18: Questionable decision found&#xA;&#x9;default allow = true&#xA;&#x9;Documentation: https://example.com/questionable&#xA;</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="c.rego" tests="1" failures="0">
+    <testcase name="style/unused-rule" classname="c.rego">
+      <system-out>c.rego:3:1: Rule never referenced&#xA;</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestJUnitReporterPublishNoViolations(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewJUnitReporter(&buf).Publish(context.Background(), report.Report{Files: []string{"p.rego"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="regal" tests="1" failures="0">
+  <testsuite name="p.rego" tests="1" failures="0">
+    <testcase name="p.rego" classname="p.rego"></testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestCheckstyleReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := rep
+	r.Files = []string{"a.rego", "b.rego", "c.rego"}
+	r.Violations = append(slices.Clone(rep.Violations), report.Violation{
+		Title:       "unused-rule",
+		Description: "Rule never referenced",
+		Category:    "style",
+		Location:    report.Location{File: "b.rego", Row: 3, Column: 1},
+		Level:       "notice",
+	})
+
+	if err := NewCheckstyleReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="a.rego">
+    <error line="1" column="1" severity="error" message="Rego must not break the law!" source="regal.rules.legal.breaking-the-law"></error>
+  </file>
+  <file name="b.rego">
+    <error line="22" column="18" severity="warning" message="Questionable decision found" source="regal.rules.really?.questionable-decision"></error>
+    <error line="3" column="1" severity="info" message="Rule never referenced" source="regal.rules.style.unused-rule"></error>
+  </file>
+  <file name="c.rego"></file>
+</checkstyle>
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestMarkdownReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewMarkdownReporter(&buf).Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `### Regal Lint Report
+
+3 files linted. 2 violations found in 2 files.
+
+| Rule | Category | Violations |
+| --- | --- | --- |
+| [breaking-the-law](https://example.com/illegal) | legal | 1 |
+| [questionable-decision](https://example.com/questionable) | really? | 1 |
+
+<details>
+<summary><code>a.rego</code> (1 violation)</summary>
+
+| Location | Level | Rule | Description |
+| --- | --- | --- | --- |
+| ` + "`a.rego:1:1`" + ` | error | [breaking-the-law](https://example.com/illegal) | Rego must not break the law! |
+
+</details>
+
+<details>
+<summary><code>b.rego</code> (1 violation)</summary>
+
+| Location | Level | Rule | Description |
+| --- | --- | --- | --- |
+| ` + "`b.rego:22:18`" + ` | warning | [questionable-decision](https://example.com/questionable) | Questionable decision found |
+
+</details>
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestMarkdownReporterPublishGroupByOwner(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	owned := report.Report{
+		Violations: []report.Violation{
+			{Title: "opa-fmt", Description: "a | b", Location: report.Location{File: "a.rego"}, Owners: []string{"@a"}},
+			{Title: "opa-fmt", Description: "<c>", Location: report.Location{File: "b.rego"}},
+		},
+	}
+
+	if err := NewMarkdownReporter(&buf).WithGroupBy(GroupByOwner).Publish(context.Background(), owned); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expect := range []string{
+		"| opa-fmt |  | 2 |",
+		"<summary><code>(unowned)</code> (1 violation)</summary>",
+		"<summary><code>@a</code> (1 violation)</summary>",
+		"| a \\| b |",
+		"| &lt;c&gt; |",
+	} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Errorf("expected %q in report:\n%s", expect, buf.String())
+		}
+	}
+}
+
+func TestMarkdownReporterPublishNoViolations(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := NewMarkdownReporter(&buf).Publish(context.Background(), report.Report{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := "### Regal Lint Report\n\n0 files linted. No violations found.\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestExecReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat command not found")
+	}
+
+	var expected, actual bytes.Buffer
+
+	if err := NewJSONReporter(&expected).Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewExecReporter(&actual, []string{"cat"}).Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual.String() != expected.String() {
+		t.Errorf("expected JSON report to be provided to command, got %s", actual.String())
+	}
+}
+
+func TestExecReporterPublishCommandFails(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("false"); err != nil {
+		t.Skip("false command not found")
+	}
+
+	err := NewExecReporter(&bytes.Buffer{}, []string{"false"}).Publish(context.Background(), rep)
+	if err == nil || !strings.Contains(err.Error(), "failed to run reporter command false") {
+		t.Errorf("expected error from failing command, got %v", err)
+	}
+}
+
+func TestTAPReporterPublishNotices(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := report.Report{
+		Files: []string{"a.rego"},
+		Violations: []report.Violation{{
+			Title:       "todo-comment",
+			Description: "Avoid TODO comments",
+			Category:    "style",
+			Level:       "notice",
+			Location:    report.Location{File: "a.rego", Row: 3, Column: 1},
+		}},
+	}
+
+	if err := NewTAPReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `TAP version 14
+1..1
+ok 1 - a.rego
+  ---
+  violations:
+    - rule: todo-comment
+      category: style
+      level: notice
+      message: Avoid TODO comments
+      line: 3
+      column: 1
+  ...
+`
+	if buf.String() != expect {
+		t.Errorf("expected %s, got %s", expect, buf.String())
+	}
+}
+
+func TestSarifReporterPublishNotices(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	r := report.Report{
+		Violations: []report.Violation{{
+			Title:       "todo-comment",
+			Description: "Avoid TODO comments",
+			Category:    "style",
+			Level:       "notice",
+			Location:    report.Location{File: "a.rego", Row: 3, Column: 1},
+		}},
+	}
+
+	if err := NewSarifReporter(&buf).Publish(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sarif struct {
+		Runs []struct {
+			Results []struct {
+				Level string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+
+	if level := sarif.Runs[0].Results[0].Level; level != "note" {
+		t.Errorf("expected notice to be reported as note, got %s", level)
+	}
+}
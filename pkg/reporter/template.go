@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/styrainc/regal/pkg/report"
+)
+
+// TemplateReporter reports violations by rendering a Go template (https://pkg.go.dev/text/template), provided the
+// report, for shaping the output for tools not supported by any of the other formats. Besides the functions built
+// into templates, the template may use:
+//
+//   - json, returning its argument encoded as JSON
+//   - join, joining a list of strings with a separator, like {{ join .Owners ", " }}
+//   - docs, returning the URL of the documentation of the rule violated, like {{ docs . }}
+type TemplateReporter struct {
+	out  io.Writer
+	tmpl *template.Template
+}
+
+// NewTemplateReporter creates a new TemplateReporter, rendering the template text provided.
+func NewTemplateReporter(out io.Writer, text string) (TemplateReporter, error) {
+	funcs := template.FuncMap{
+		"json": func(v any) (string, error) {
+			bs, err := json.Marshal(v)
+
+			return string(bs), err
+		},
+		"join": strings.Join,
+		"docs": getDocumentationURL,
+	}
+
+	tmpl, err := template.New("report").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return TemplateReporter{}, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return TemplateReporter{out: out, tmpl: tmpl}, nil
+}
+
+// Publish renders the template provided the report to the configured output.
+func (tr TemplateReporter) Publish(_ context.Context, r report.Report) error {
+	if err := tr.tmpl.Execute(tr.out, r); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestTemplateReporterPublish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	tr, err := NewTemplateReporter(&buf, `{{ range .Violations -}}
+{{ .Location.File }}:{{ .Location.Row }} {{ .Level }} {{ .Title }} {{ json .Description }} {{ docs . }}
+{{ end -}}
+{{ .Summary.NumViolations }} violations in {{ .Summary.FilesScanned }} files
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tr.Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `a.rego:1 error breaking-the-law "Rego must not break the law!" https://example.com/illegal
+b.rego:22 warning questionable-decision "Questionable decision found" https://example.com/questionable
+2 violations in 3 files
+`
+
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+func TestTemplateReporterInvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTemplateReporter(&bytes.Buffer{}, "{{ .Violations "); err == nil {
+		t.Fatal("expected error for invalid template")
+	}
+
+	tr, err := NewTemplateReporter(&bytes.Buffer{}, "{{ .NoSuchField }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tr.Publish(context.Background(), rep); err == nil {
+		t.Fatal("expected error rendering template with unknown field")
+	}
+}
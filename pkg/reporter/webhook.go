@@ -0,0 +1,160 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/styrainc/regal/pkg/report"
+)
+
+// DefaultWebhookRetries is the number of times the WebhookReporter retries posting a report by default.
+const DefaultWebhookRetries = 3
+
+// DefaultWebhookTimeout is the time the WebhookReporter waits for the response to each request by default.
+const DefaultWebhookTimeout = 30 * time.Second
+
+// WebhookReporter reports violations by posting the report, in the JSON format, to a URL, like that of a service
+// collecting the results of linting across repositories. Failed requests, i.e. those failing to connect, or with a
+// response status of 429 or 5xx, are retried with an exponential backoff. Requests not answered in time are failed,
+// for a slow endpoint not to hang linting.
+type WebhookReporter struct {
+	url        string
+	headers    map[string]*template.Template
+	retries    int
+	retryDelay time.Duration
+	client     *http.Client
+}
+
+// NewWebhookReporter creates a new WebhookReporter, posting reports to the URL, with the headers provided. The values
+// of headers are templates, provided the report, and an env function returning the value of an environment variable,
+// like {{ env "TOKEN" }}, for secrets not to be provided on the command line.
+func NewWebhookReporter(url string, headers map[string]string) (WebhookReporter, error) {
+	tr := WebhookReporter{
+		url:        url,
+		headers:    make(map[string]*template.Template, len(headers)),
+		retries:    DefaultWebhookRetries,
+		retryDelay: time.Second,
+		client:     &http.Client{Timeout: DefaultWebhookTimeout},
+	}
+
+	funcs := template.FuncMap{"env": os.Getenv}
+
+	for name, value := range headers {
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return WebhookReporter{}, fmt.Errorf("failed to parse template of header %s: %w", name, err)
+		}
+
+		tr.headers[name] = tmpl
+	}
+
+	return tr, nil
+}
+
+// WithRetries sets the number of times the WebhookReporter retries posting a report, after the first attempt.
+func (tr WebhookReporter) WithRetries(retries int) WebhookReporter {
+	tr.retries = retries
+
+	return tr
+}
+
+// WithTimeout sets the time the WebhookReporter waits for the response to each request.
+func (tr WebhookReporter) WithTimeout(timeout time.Duration) WebhookReporter {
+	tr.client = &http.Client{Timeout: timeout}
+
+	return tr
+}
+
+// Publish posts the report in the JSON format to the URL of the reporter, retrying failed requests.
+func (tr WebhookReporter) Publish(ctx context.Context, r report.Report) error {
+	var body bytes.Buffer
+
+	if err := NewJSONReporter(&body).Publish(ctx, r); err != nil {
+		return err
+	}
+
+	headers := make(http.Header, len(tr.headers))
+
+	for name, tmpl := range tr.headers {
+		var value strings.Builder
+		if err := tmpl.Execute(&value, r); err != nil {
+			return fmt.Errorf("failed to render header %s: %w", name, err)
+		}
+
+		headers.Set(name, value.String())
+	}
+
+	delay := tr.retryDelay
+
+	for attempt := 0; ; attempt++ {
+		err := tr.post(ctx, body.Bytes(), headers)
+		if err == nil {
+			return nil
+		}
+
+		var permanent permanentError
+		if attempt >= tr.retries || errors.As(err, &permanent) {
+			return fmt.Errorf("failed to post report to %s: %w", tr.url, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to post report to %s: %w", tr.url, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// permanentError is the error of a request not worth retrying, as the response would be the same.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+func (tr WebhookReporter) post(ctx context.Context, body []byte, headers http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tr.url, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err: err}
+	}
+
+	req.Header = headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := tr.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("unexpected response status %s", resp.Status)
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+
+	return permanentError{err: err}
+}
@@ -0,0 +1,152 @@
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookReporterPublish(t *testing.T) {
+	// Can't use t.Parallel() here because t.Setenv() forbids that
+	t.Setenv("REGAL_WEBHOOK_TEST_TOKEN", "secret")
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt fails, and is retried
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("expected Authorization header Bearer secret, got %s", auth)
+		}
+
+		if count := r.Header.Get("X-Violations"); count != "2" {
+			t.Errorf("expected X-Violations header 2, got %s", count)
+		}
+
+		var published jsonReport
+		if err := json.NewDecoder(r.Body).Decode(&published); err != nil {
+			t.Errorf("failed to decode report: %v", err)
+		}
+
+		if published.Version != JSONReportVersion || len(published.Violations) != 2 {
+			t.Errorf("expected report of version %d with 2 violations, got %+v", JSONReportVersion, published)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	defer server.Close()
+
+	tr, err := NewWebhookReporter(server.URL, map[string]string{
+		"Authorization": `Bearer {{ env "REGAL_WEBHOOK_TEST_TOKEN" }}`,
+		"X-Violations":  "{{ .Summary.NumViolations }}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr.retryDelay = 0
+
+	if err := tr.Publish(context.Background(), rep); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestWebhookReporterPublishFails(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		status   int
+		attempts int32
+	}{
+		"server error retried":     {status: http.StatusInternalServerError, attempts: 3},
+		"client error not retried": {status: http.StatusUnauthorized, attempts: 1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(tc.status)
+			}))
+
+			defer server.Close()
+
+			tr, err := NewWebhookReporter(server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tr = tr.WithRetries(2)
+			tr.retryDelay = 0
+
+			err = tr.Publish(context.Background(), rep)
+			if err == nil || !strings.Contains(err.Error(), http.StatusText(tc.status)) {
+				t.Errorf("expected error with status %d, got %v", tc.status, err)
+			}
+
+			if n := attempts.Load(); n != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, n)
+			}
+		})
+	}
+}
+
+func TestWebhookReporterPublishSlowEndpoint(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	defer server.Close()
+	defer close(release)
+
+	tr, err := NewWebhookReporter(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr = tr.WithRetries(0)
+
+	if err = tr.WithTimeout(10*time.Millisecond).Publish(context.Background(), rep); err == nil {
+		t.Error("expected error when response not received within timeout")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err = tr.Publish(ctx, rep); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error of context deadline exceeded, got %v", err)
+	}
+}
+
+func TestNewWebhookReporterInvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWebhookReporter("http://localhost", map[string]string{"X-Bad": "{{ .Summary"}); err == nil {
+		t.Error("expected error for invalid header template")
+	}
+}
@@ -23,6 +23,9 @@ type Input struct {
 	FileContent map[string]string
 	// Modules is the set of modules to lint.
 	Modules map[string]*ast.Module
+	// RootDir is the root directory of the project linted, if known, against which rules resolve the paths of files
+	// they read, like schemas. If not known, paths are resolved against the working directory.
+	RootDir string
 }
 
 // Rule represents a linter rule.
@@ -111,5 +114,10 @@ func InputFromText(fileName, text string) (Input, error) {
 func AllGoRules(conf config.Config) []Rule {
 	return []Rule{
 		NewOpaFmtRule(conf),
+		NewStrictModeRule(conf),
+		NewFieldNotInSchemaRule(conf),
+		NewTrailingWhitespaceRule(conf),
+		NewIndentationRule(conf),
+		NewMissingFinalNewlineRule(conf),
 	}
 }
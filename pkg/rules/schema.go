@@ -0,0 +1,258 @@
+package rules
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/docs"
+	"github.com/styrainc/regal/internal/schemas"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+const (
+	fieldNotInSchemaTitle       = "field-not-in-schema"
+	fieldNotInSchemaDescription = "Reference to field not in schema"
+	fieldNotInSchemaCategory    = "bugs"
+
+	// keySchemas is the option of the rule mapping the paths of input and data, like data.users, to the files of
+	// their schemas.
+	keySchemas = "schemas"
+)
+
+// FieldNotInSchemaRule reports references to fields of input and data that can't exist according to the schemas
+// declared for them, either in the schemas of METADATA annotations, or in the configuration of the rule.
+type FieldNotInSchemaRule struct {
+	ruleConfig config.Rule
+}
+
+// schemaOf is a schema applying to the document at path, like input or data.users.
+type schemaOf struct {
+	path   ast.Ref
+	name   string
+	schema any
+}
+
+func NewFieldNotInSchemaRule(conf config.Config) *FieldNotInSchemaRule {
+	ruleConf, ok := conf.Rules[fieldNotInSchemaCategory][fieldNotInSchemaTitle]
+	if ok {
+		return &FieldNotInSchemaRule{ruleConfig: ruleConf}
+	}
+
+	return &FieldNotInSchemaRule{ruleConfig: config.Rule{
+		Level: "error",
+	}}
+}
+
+func (f *FieldNotInSchemaRule) Run(ctx context.Context, input Input) (*report.Report, error) {
+	result := &report.Report{}
+
+	rootDir := cmp.Or(strings.TrimPrefix(input.RootDir, "file://"), ".")
+
+	configured, err := f.configuredSchemas(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[string]schemaOf)
+
+	for _, filename := range input.FileNames {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("timeout when running %s rule: %w", fieldNotInSchemaTitle, err)
+		}
+
+		module := input.Modules[filename]
+		lines := strings.Split(input.FileContent[filename], "\n")
+
+		for _, rule := range module.Rules {
+			applying := append(declaredSchemas(module, rule, rootDir, loaded), configured...)
+			if len(applying) == 0 {
+				continue
+			}
+
+			ast.WalkRefs(rule, func(ref ast.Ref) bool {
+				if term, name, ok := unknownField(ref, applying); ok {
+					result.Violations = append(result.Violations, f.violation(term, name, filename, lines))
+				}
+
+				return false
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// unknownField returns the term of the first field referenced by ref that can't exist according to the first of the
+// schemas applying to it, along with a message naming the field as referenced, like input.user.nmae.
+func unknownField(ref ast.Ref, applying []schemaOf) (*ast.Term, string, bool) {
+	for _, s := range applying {
+		if !ref.HasPrefix(s.path) {
+			continue
+		}
+
+		p := make([]string, 0, len(ref)-len(s.path))
+
+	parts:
+		for _, term := range ref[len(s.path):] {
+			switch v := term.Value.(type) {
+			case ast.String:
+				p = append(p, string(v))
+			case ast.Number:
+				p = append(p, v.String())
+			case ast.Var:
+				p = append(p, "_")
+			default:
+				break parts
+			}
+		}
+
+		i, ok := schemas.Unknown(s.schema, p)
+		if !ok {
+			return nil, "", false
+		}
+
+		field := ref[:len(s.path)+i+1]
+
+		return field[len(field)-1], fmt.Sprintf("%s not in %s", field, s.name), true
+	}
+
+	return nil, "", false
+}
+
+// declaredSchemas returns the schemas declared in the annotations of the module applying to the rule, in order of
+// precedence. Schemas failing to load are left out, as OPA will report them when the policy is compiled.
+func declaredSchemas(module *ast.Module, rule *ast.Rule, rootDir string, loaded map[string]schemaOf) []schemaOf {
+	if rule.Location == nil {
+		return nil
+	}
+
+	declared := make([]schemaOf, 0)
+
+	for _, annotation := range schemas.Declared(module, rule.Location.Row) {
+		if annotation.Definition == nil {
+			if s, ok := loaded[annotation.Schema.String()]; ok {
+				declared = append(declared, schemaOf{path: annotation.Path, name: s.name, schema: s.schema})
+
+				continue
+			}
+		}
+
+		schema, name, err := schemas.FromAnnotation(annotation, rootDir)
+		if err != nil {
+			continue
+		}
+
+		if annotation.Definition == nil {
+			loaded[annotation.Schema.String()] = schemaOf{name: name, schema: schema}
+		}
+
+		declared = append(declared, schemaOf{path: annotation.Path, name: name, schema: schema})
+	}
+
+	return declared
+}
+
+// configuredSchemas returns the schemas provided in the configuration of the rule, with those of the longest paths
+// first, as they take precedence over those of their parents.
+func (f *FieldNotInSchemaRule) configuredSchemas(rootDir string) ([]schemaOf, error) {
+	if f.ruleConfig.Extra[keySchemas] == nil {
+		return nil, nil
+	}
+
+	files, ok := f.ruleConfig.Extra[keySchemas].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected %s option of %s rule to map paths to schema files, got %T",
+			keySchemas, fieldNotInSchemaTitle, f.ruleConfig.Extra[keySchemas])
+	}
+
+	configured := make([]schemaOf, 0, len(files))
+
+	for path, file := range files {
+		ref, err := ast.ParseRef(path)
+		if err != nil || !ref[0].Equal(ast.InputRootDocument) && !ref[0].Equal(ast.DefaultRootDocument) {
+			return nil, fmt.Errorf("invalid path %q in %s option of %s rule", path, keySchemas, fieldNotInSchemaTitle)
+		}
+
+		name, ok := file.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected schema file of %s to be a string, got %T", path, file)
+		}
+
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(rootDir, name)
+		}
+
+		bs, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema of %s: %w", path, err)
+		}
+
+		var schema any
+		if err := json.Unmarshal(bs, &schema); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schema of %s: %w", path, err)
+		}
+
+		configured = append(configured, schemaOf{path: ref, name: filepath.Base(name), schema: schema})
+	}
+
+	slices.SortFunc(configured, func(a, b schemaOf) int {
+		return cmp.Or(cmp.Compare(len(b.path), len(a.path)), a.path.Compare(b.path))
+	})
+
+	return configured, nil
+}
+
+func (f *FieldNotInSchemaRule) violation(term *ast.Term, message, filename string, lines []string) report.Violation {
+	location := report.Location{File: filename}
+
+	if term.Location != nil {
+		location.Row = term.Location.Row
+		location.Column = term.Location.Col
+
+		if len(lines) >= term.Location.Row {
+			text := lines[term.Location.Row-1]
+			location.Text = &text
+		}
+	}
+
+	return report.Violation{
+		Title:       fieldNotInSchemaTitle,
+		Description: fmt.Sprintf("%s: %s", fieldNotInSchemaDescription, message),
+		Category:    fieldNotInSchemaCategory,
+		RelatedResources: []report.RelatedResource{{
+			Description: relatedResourcesDescription,
+			Reference:   f.Documentation(),
+		}},
+		Location: location,
+		Level:    f.ruleConfig.Level,
+	}
+}
+
+func (*FieldNotInSchemaRule) Name() string {
+	return fieldNotInSchemaTitle
+}
+
+func (*FieldNotInSchemaRule) Category() string {
+	return fieldNotInSchemaCategory
+}
+
+func (*FieldNotInSchemaRule) Description() string {
+	return fieldNotInSchemaDescription
+}
+
+func (*FieldNotInSchemaRule) Documentation() string {
+	return docs.CreateDocsURL(fieldNotInSchemaCategory, fieldNotInSchemaTitle)
+}
+
+func (f *FieldNotInSchemaRule) Config() config.Rule {
+	return f.ruleConfig
+}
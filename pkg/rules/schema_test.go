@@ -0,0 +1,119 @@
+package rules_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/styrainc/regal/internal/test"
+	"github.com/styrainc/regal/internal/testutil"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+func TestFieldNotInSchemaRule(t *testing.T) {
+	t.Parallel()
+
+	policy := `# METADATA
+# scope: package
+# schemas:
+#   - input: {"type": "object", "properties": {"user": {"type": "object", "properties": {"name": {"type": "string"}}}}}
+package p
+
+import rego.v1
+
+allow if input.user.nmae == "alice"
+
+deny if input.user.name.first == "alice"
+
+# METADATA
+# schemas:
+#   - input: {"type": "object", "properties": {"groups": {"type": "array", "items": {"type": "string"}}}}
+admin if "admin" in input.groups
+
+viewer if input.groups[_] == "viewer"
+
+owner if input.user.owner
+
+ok if input.user.name == "alice"
+`
+
+	result := testutil.Must(
+		rules.NewFieldNotInSchemaRule(config.Config{}).Run(context.Background(), test.InputPolicy("p.rego", policy)),
+	)(t)
+
+	expected := []struct {
+		row  int
+		desc string
+	}{
+		{row: 9, desc: "input.user.nmae not in the schema of input"},
+		{row: 11, desc: "input.user.name.first not in the schema of input"},
+		// the schema declared for the admin rule doesn't apply to other rules
+		{row: 18, desc: "input.groups not in the schema of input"},
+		{row: 20, desc: "input.user.owner not in the schema of input"},
+	}
+
+	if len(result.Violations) != len(expected) {
+		t.Fatalf("expected %d violations, got %v", len(expected), result.Violations)
+	}
+
+	for i, exp := range expected {
+		violation := result.Violations[i]
+
+		if violation.Location.Row != exp.row {
+			t.Errorf("expected row %d, got %d", exp.row, violation.Location.Row)
+		}
+
+		if desc := "Reference to field not in schema: " + exp.desc; violation.Description != desc {
+			t.Errorf("expected description %q, got %q", desc, violation.Description)
+		}
+	}
+}
+
+func TestFieldNotInSchemaRuleConfiguredSchemas(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	schema := `{"type": "object", "additionalProperties": {"type": "object", "properties": {"roles": {}}}}`
+
+	if err := os.WriteFile(filepath.Join(root, "users.json"), []byte(schema), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.Config{Rules: map[string]config.Category{
+		"bugs": {"field-not-in-schema": config.Rule{
+			Level: "error",
+			Extra: config.ExtraAttributes{"schemas": map[string]any{"data.users": "users.json"}},
+		}},
+	}}
+
+	policy := `package p
+
+import rego.v1
+
+allow if "admin" in data.users[input.name].roles
+
+deny if data.users.alice.role == "admin"
+
+other if data.groups.admins.name
+`
+
+	input := test.InputPolicy("p.rego", policy)
+	input.RootDir = root
+
+	result := testutil.Must(rules.NewFieldNotInSchemaRule(conf).Run(context.Background(), input))(t)
+
+	if len(result.Violations) != 1 {
+		t.Fatalf("expected 1 violation, got %v", result.Violations)
+	}
+
+	if result.Violations[0].Location.Row != 7 {
+		t.Errorf("expected violation at row 7, got %d", result.Violations[0].Location.Row)
+	}
+
+	if exp := "Reference to field not in schema: data.users.alice.role not in users.json"; result.Violations[0].
+		Description != exp {
+		t.Errorf("expected description %q, got %q", exp, result.Violations[0].Description)
+	}
+}
@@ -0,0 +1,135 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/docs"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+const (
+	strictModeTitle       = "strict-mode"
+	strictModeDescription = "Strict mode check failed"
+	strictModeCategory    = "bugs"
+)
+
+// strictModeErrors are the prefixes and suffixes of the messages of errors only reported by the OPA compiler in strict
+// mode, or for modules importing rego.v1. Checks for duplicate imports and deprecated built-in functions are left out,
+// as they're covered by the import-shadows-import and deprecated-builtin rules.
+//
+//nolint:gochecknoglobals
+var strictModeErrors = []struct{ prefix, suffix string }{
+	{prefix: "assigned var ", suffix: " unused"},
+	{prefix: "declared var ", suffix: " unused"},
+	{prefix: "import ", suffix: " unused"},
+	{prefix: "unused argument "},
+	{prefix: "rules must not shadow "},
+	{prefix: "args must not shadow "},
+	{prefix: "variables must not shadow "},
+}
+
+// StrictModeRule reports the errors found by the strict mode checks of the OPA compiler, like unused variables,
+// arguments and imports, as performed by `opa check --strict`.
+type StrictModeRule struct {
+	ruleConfig config.Rule
+}
+
+func NewStrictModeRule(conf config.Config) *StrictModeRule {
+	ruleConf, ok := conf.Rules[strictModeCategory][strictModeTitle]
+	if ok {
+		return &StrictModeRule{ruleConfig: ruleConf}
+	}
+
+	return &StrictModeRule{ruleConfig: config.Rule{
+		Level: "error",
+	}}
+}
+
+func (s *StrictModeRule) Run(ctx context.Context, input Input) (*report.Report, error) {
+	result := &report.Report{}
+
+	// the compiler modifies the modules compiled, so copies are compiled, and all are compiled together, for
+	// references to other modules, like imports, to be resolved
+	modules := make(map[string]*ast.Module, len(input.FileNames))
+	for _, filename := range input.FileNames {
+		modules[filename] = input.Modules[filename].Copy()
+	}
+
+	compiler := ast.NewCompiler().
+		WithStrict(true).
+		WithEnablePrintStatements(true).
+		SetErrorLimit(0)
+
+	compiler.Compile(modules)
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("timeout when running %s rule: %w", strictModeTitle, err)
+	}
+
+	for _, err := range compiler.Errors {
+		if err.Location == nil || !isStrictModeError(err.Message) {
+			continue
+		}
+
+		txt := err.Location.Text
+		if lines := strings.Split(input.FileContent[err.Location.File], "\n"); len(lines) >= err.Location.Row {
+			txt = []byte(lines[err.Location.Row-1])
+		}
+
+		text := string(txt)
+
+		result.Violations = append(result.Violations, report.Violation{
+			Title:       strictModeTitle,
+			Description: fmt.Sprintf("%s: %s", strictModeDescription, err.Message),
+			Category:    strictModeCategory,
+			RelatedResources: []report.RelatedResource{{
+				Description: relatedResourcesDescription,
+				Reference:   s.Documentation(),
+			}},
+			Location: report.Location{
+				File:   err.Location.File,
+				Row:    err.Location.Row,
+				Column: err.Location.Col,
+				Text:   &text,
+			},
+			Level: s.ruleConfig.Level,
+		})
+	}
+
+	return result, nil
+}
+
+func isStrictModeError(message string) bool {
+	for _, e := range strictModeErrors {
+		if strings.HasPrefix(message, e.prefix) && strings.HasSuffix(message, e.suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (*StrictModeRule) Name() string {
+	return strictModeTitle
+}
+
+func (*StrictModeRule) Category() string {
+	return strictModeCategory
+}
+
+func (*StrictModeRule) Description() string {
+	return strictModeDescription
+}
+
+func (*StrictModeRule) Documentation() string {
+	return docs.CreateDocsURL(strictModeCategory, strictModeTitle)
+}
+
+func (s *StrictModeRule) Config() config.Rule {
+	return s.ruleConfig
+}
@@ -0,0 +1,92 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/styrainc/regal/internal/test"
+	"github.com/styrainc/regal/internal/testutil"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+func TestStrictModeRule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy      string
+		expRow      int
+		expDescSuff string
+	}{
+		"unused import": {
+			policy:      "package p\n\nimport rego.v1\n\nimport data.foo\n\nallow := true\n",
+			expRow:      5,
+			expDescSuff: "import data.foo unused",
+		},
+		"unused argument": {
+			policy:      "package p\n\nimport rego.v1\n\nf(x, y) := x\n",
+			expRow:      5,
+			expDescSuff: "unused argument y. (hint: use _ (wildcard variable) instead)",
+		},
+		"unused assignment": {
+			policy:      "package p\n\nimport rego.v1\n\nallow if {\n\tx := 1\n\tinput.y\n}\n",
+			expRow:      6,
+			expDescSuff: "assigned var x unused",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := testutil.Must(
+				rules.NewStrictModeRule(config.Config{}).Run(context.Background(), test.InputPolicy("p.rego", tc.policy)),
+			)(t)
+
+			if len(result.Violations) != 1 {
+				t.Fatalf("expected 1 violation, got %v", result.Violations)
+			}
+
+			violation := result.Violations[0]
+
+			if violation.Title != "strict-mode" {
+				t.Errorf("expected violation title to be 'strict-mode', got %s", violation.Title)
+			}
+
+			if exp := "Strict mode check failed: " + tc.expDescSuff; violation.Description != exp {
+				t.Errorf("expected description %q, got %q", exp, violation.Description)
+			}
+
+			if violation.Location.Row != tc.expRow {
+				t.Errorf("expected row %d, got %d", tc.expRow, violation.Location.Row)
+			}
+		})
+	}
+}
+
+func TestStrictModeRuleSuccess(t *testing.T) {
+	t.Parallel()
+
+	policy := `package p
+
+import rego.v1
+
+import data.users
+
+# errors not specific to strict mode are left to other rules
+allow if undefined_function(users)
+
+deny if {
+	x := input.x
+	x > 1
+}
+`
+
+	result := testutil.Must(
+		rules.NewStrictModeRule(config.Config{}).Run(context.Background(), test.InputPolicy("p.rego", policy)),
+	)(t)
+
+	if len(result.Violations) != 0 {
+		t.Errorf("expected no violations, got %v", result.Violations)
+	}
+}
@@ -0,0 +1,315 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+
+	"github.com/styrainc/regal/internal/docs"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+)
+
+const (
+	trailingWhitespaceTitle       = "trailing-whitespace"
+	trailingWhitespaceDescription = "Trailing whitespace"
+
+	indentationTitle       = "indentation"
+	indentationDescription = "Indentation with wrong character"
+
+	missingFinalNewlineTitle       = "missing-final-newline"
+	missingFinalNewlineDescription = "File should end with a newline"
+
+	whitespaceCategory = "style"
+
+	// keyIndentWith is the option of the indentation rule deciding whether lines are indented with tabs or spaces.
+	keyIndentWith = "indent-with"
+	// keyIndentWidth is the option of the indentation rule deciding the number of spaces to a level of indentation,
+	// when indenting with spaces.
+	keyIndentWidth = "indent-width"
+
+	defaultIndentWidth = 4
+)
+
+// TrailingWhitespaceRule reports lines ending with whitespace, except for lines inside of multi-line raw strings, where
+// the whitespace is part of the string.
+type TrailingWhitespaceRule struct {
+	ruleConfig config.Rule
+}
+
+// IndentationRule reports lines indented with spaces, or with tabs when configured to indent with spaces. Like
+// with the TrailingWhitespaceRule, lines inside of multi-line raw strings are left alone.
+type IndentationRule struct {
+	ruleConfig config.Rule
+}
+
+// MissingFinalNewlineRule reports files not ending with a newline.
+type MissingFinalNewlineRule struct {
+	ruleConfig config.Rule
+}
+
+func NewTrailingWhitespaceRule(conf config.Config) *TrailingWhitespaceRule {
+	ruleConf, ok := conf.Rules[whitespaceCategory][trailingWhitespaceTitle]
+	if ok {
+		return &TrailingWhitespaceRule{ruleConfig: ruleConf}
+	}
+
+	return &TrailingWhitespaceRule{ruleConfig: config.Rule{
+		Level: "error",
+	}}
+}
+
+func NewIndentationRule(conf config.Config) *IndentationRule {
+	ruleConf, ok := conf.Rules[whitespaceCategory][indentationTitle]
+	if ok {
+		return &IndentationRule{ruleConfig: ruleConf}
+	}
+
+	return &IndentationRule{ruleConfig: config.Rule{
+		Level: "error",
+	}}
+}
+
+func NewMissingFinalNewlineRule(conf config.Config) *MissingFinalNewlineRule {
+	ruleConf, ok := conf.Rules[whitespaceCategory][missingFinalNewlineTitle]
+	if ok {
+		return &MissingFinalNewlineRule{ruleConfig: ruleConf}
+	}
+
+	return &MissingFinalNewlineRule{ruleConfig: config.Rule{
+		Level: "error",
+	}}
+}
+
+func (r *TrailingWhitespaceRule) Run(ctx context.Context, input Input) (*report.Report, error) {
+	result := &report.Report{}
+
+	for _, filename := range input.FileNames {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("timeout when running %s rule: %w", trailingWhitespaceTitle, err)
+		}
+
+		rawStrings := multiLineRawStrings(input.Modules[filename])
+
+		for i, line := range strings.Split(input.FileContent[filename], "\n") {
+			trimmed := strings.TrimRight(line, " \t")
+			if trimmed == line || rawStrings.continuesAfter(i+1) {
+				continue
+			}
+
+			result.Violations = append(result.Violations, whitespaceViolation(
+				r, filename, i+1, len(trimmed)+1, line,
+			))
+		}
+	}
+
+	return result, nil
+}
+
+func (r *IndentationRule) Run(ctx context.Context, input Input) (*report.Report, error) {
+	result := &report.Report{}
+
+	wrong := " "
+	if IndentWithSpaces(r.ruleConfig) {
+		wrong = "\t"
+	}
+
+	for _, filename := range input.FileNames {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("timeout when running %s rule: %w", indentationTitle, err)
+		}
+
+		rawStrings := multiLineRawStrings(input.Modules[filename])
+
+		for i, line := range strings.Split(input.FileContent[filename], "\n") {
+			indentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+			// lines of only whitespace are left for the trailing-whitespace rule
+			col := strings.Index(indentation, wrong)
+			if col == -1 || len(indentation) == len(line) || rawStrings.continuesBefore(i+1) {
+				continue
+			}
+
+			result.Violations = append(result.Violations, whitespaceViolation(r, filename, i+1, col+1, line))
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MissingFinalNewlineRule) Run(ctx context.Context, input Input) (*report.Report, error) {
+	result := &report.Report{}
+
+	for _, filename := range input.FileNames {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("timeout when running %s rule: %w", missingFinalNewlineTitle, err)
+		}
+
+		content := input.FileContent[filename]
+		if content == "" || strings.HasSuffix(content, "\n") {
+			continue
+		}
+
+		lines := strings.Split(content, "\n")
+		last := lines[len(lines)-1]
+
+		result.Violations = append(result.Violations, whitespaceViolation(
+			r, filename, len(lines), len(last)+1, last,
+		))
+	}
+
+	return result, nil
+}
+
+// IndentWithSpaces returns true if the indentation rule is configured to indent with spaces rather than tabs.
+func IndentWithSpaces(conf config.Rule) bool {
+	return conf.Extra[keyIndentWith] == "spaces"
+}
+
+// IndentWidth returns the number of spaces to a level of indentation configured for the indentation rule, or
+// the default of 4 if not configured.
+func IndentWidth(conf config.Rule) int {
+	switch width := conf.Extra[keyIndentWidth].(type) {
+	case int:
+		return width
+	case float64:
+		return int(width)
+	default:
+		return defaultIndentWidth
+	}
+}
+
+func whitespaceViolation(rule Rule, filename string, row, col int, text string) report.Violation {
+	return report.Violation{
+		Title:       rule.Name(),
+		Description: rule.Description(),
+		Category:    rule.Category(),
+		RelatedResources: []report.RelatedResource{{
+			Description: relatedResourcesDescription,
+			Reference:   rule.Documentation(),
+		}},
+		Location: report.Location{
+			File:   filename,
+			Row:    row,
+			Column: col,
+			Text:   &text,
+		},
+		Level: rule.Config().Level,
+	}
+}
+
+// rowSpans are the first and last rows of multi-line raw strings.
+type rowSpans [][2]int
+
+// continuesAfter returns true if row is part of a span continuing on the next row, i.e. whitespace at the end of row
+// is part of a raw string.
+func (s rowSpans) continuesAfter(row int) bool {
+	for _, span := range s {
+		if row >= span[0] && row < span[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// continuesBefore returns true if row is part of a span continuing from the previous row, i.e. whitespace at the start
+// of row is part of a raw string.
+func (s rowSpans) continuesBefore(row int) bool {
+	for _, span := range s {
+		if row > span[0] && row <= span[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func multiLineRawStrings(module *ast.Module) rowSpans {
+	var spans rowSpans
+
+	if module == nil {
+		return spans
+	}
+
+	ast.WalkTerms(module, func(term *ast.Term) bool {
+		if _, ok := term.Value.(ast.String); !ok || term.Location == nil {
+			return false
+		}
+
+		if !bytes.HasPrefix(term.Location.Text, []byte("`")) {
+			return false
+		}
+
+		if rows := bytes.Count(term.Location.Text, []byte("\n")); rows > 0 {
+			spans = append(spans, [2]int{term.Location.Row, term.Location.Row + rows})
+		}
+
+		return false
+	})
+
+	return spans
+}
+
+func (*TrailingWhitespaceRule) Name() string {
+	return trailingWhitespaceTitle
+}
+
+func (*TrailingWhitespaceRule) Category() string {
+	return whitespaceCategory
+}
+
+func (*TrailingWhitespaceRule) Description() string {
+	return trailingWhitespaceDescription
+}
+
+func (*TrailingWhitespaceRule) Documentation() string {
+	return docs.CreateDocsURL(whitespaceCategory, trailingWhitespaceTitle)
+}
+
+func (r *TrailingWhitespaceRule) Config() config.Rule {
+	return r.ruleConfig
+}
+
+func (*IndentationRule) Name() string {
+	return indentationTitle
+}
+
+func (*IndentationRule) Category() string {
+	return whitespaceCategory
+}
+
+func (*IndentationRule) Description() string {
+	return indentationDescription
+}
+
+func (*IndentationRule) Documentation() string {
+	return docs.CreateDocsURL(whitespaceCategory, indentationTitle)
+}
+
+func (r *IndentationRule) Config() config.Rule {
+	return r.ruleConfig
+}
+
+func (*MissingFinalNewlineRule) Name() string {
+	return missingFinalNewlineTitle
+}
+
+func (*MissingFinalNewlineRule) Category() string {
+	return whitespaceCategory
+}
+
+func (*MissingFinalNewlineRule) Description() string {
+	return missingFinalNewlineDescription
+}
+
+func (*MissingFinalNewlineRule) Documentation() string {
+	return docs.CreateDocsURL(whitespaceCategory, missingFinalNewlineTitle)
+}
+
+func (r *MissingFinalNewlineRule) Config() config.Rule {
+	return r.ruleConfig
+}
@@ -0,0 +1,90 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/styrainc/regal/internal/test"
+	"github.com/styrainc/regal/internal/testutil"
+	"github.com/styrainc/regal/pkg/config"
+	"github.com/styrainc/regal/pkg/report"
+	"github.com/styrainc/regal/pkg/rules"
+)
+
+func TestWhitespaceRules(t *testing.T) {
+	t.Parallel()
+
+	spaces := config.Config{Rules: map[string]config.Category{
+		"style": {"indentation": config.Rule{Level: "error", Extra: config.ExtraAttributes{"indent-with": "spaces"}}},
+	}}
+
+	testCases := map[string]struct {
+		rule      rules.Rule
+		policy    string
+		locations []report.Location
+	}{
+		"trailing whitespace": {
+			rule:      rules.NewTrailingWhitespaceRule(config.Config{}),
+			policy:    "package p\n\nx := 1 \t\n\ny := 2\n",
+			locations: []report.Location{{Row: 3, Column: 7}},
+		},
+		"trailing whitespace on empty line": {
+			rule:      rules.NewTrailingWhitespaceRule(config.Config{}),
+			policy:    "package p\n\t\nx := 1\n",
+			locations: []report.Location{{Row: 2, Column: 1}},
+		},
+		"trailing whitespace in raw string": {
+			rule:   rules.NewTrailingWhitespaceRule(config.Config{}),
+			policy: "package p\n\nx := `foo  \nbar  \n` \n",
+			// only the whitespace following the end of the string is reported
+			locations: []report.Location{{Row: 5, Column: 2}},
+		},
+		"indented with spaces": {
+			rule:      rules.NewIndentationRule(config.Config{}),
+			policy:    "package p\n\nallow {\n    input.x\n\t input.y\n\tinput.z\n}\n",
+			locations: []report.Location{{Row: 4, Column: 1}, {Row: 5, Column: 2}},
+		},
+		"indented with spaces in raw string": {
+			rule:   rules.NewIndentationRule(config.Config{}),
+			policy: "package p\n\nx := `\n    foo\n`\n",
+		},
+		"indented with tabs when configured for spaces": {
+			rule:      rules.NewIndentationRule(spaces),
+			policy:    "package p\n\nallow {\n    input.x\n\tinput.y\n}\n",
+			locations: []report.Location{{Row: 5, Column: 1}},
+		},
+		"missing final newline": {
+			rule:      rules.NewMissingFinalNewlineRule(config.Config{}),
+			policy:    "package p\n\nx := 1",
+			locations: []report.Location{{Row: 3, Column: 7}},
+		},
+		"final newline": {
+			rule:   rules.NewMissingFinalNewlineRule(config.Config{}),
+			policy: "package p\n\nx := 1\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := testutil.Must(tc.rule.Run(context.Background(), test.InputPolicy("p.rego", tc.policy)))(t)
+
+			if len(result.Violations) != len(tc.locations) {
+				t.Fatalf("expected %d violations, got %d: %v", len(tc.locations), len(result.Violations), result.Violations)
+			}
+
+			for i, violation := range result.Violations {
+				if violation.Title != tc.rule.Name() || violation.Level != "error" {
+					t.Errorf("expected %s violation at level error, got %s at level %s",
+						tc.rule.Name(), violation.Title, violation.Level)
+				}
+
+				if violation.Location.Row != tc.locations[i].Row || violation.Location.Column != tc.locations[i].Column {
+					t.Errorf("expected violation at %d:%d, got %d:%d", tc.locations[i].Row, tc.locations[i].Column,
+						violation.Location.Row, violation.Location.Column)
+				}
+			}
+		})
+	}
+}